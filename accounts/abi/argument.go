@@ -33,19 +33,24 @@ type Argument struct {
 
 type Arguments []Argument
 
+// ArgumentMarshaling is the JSON representation of an argument. Components
+// holds the fields of tuple types.
+type ArgumentMarshaling struct {
+	Name       string
+	Type       string
+	Components []ArgumentMarshaling
+	Indexed    bool
+}
+
 // UnmarshalJSON implements json.Unmarshaler interface
 func (argument *Argument) UnmarshalJSON(data []byte) error {
-	var extarg struct {
-		Name    string
-		Type    string
-		Indexed bool
-	}
+	var extarg ArgumentMarshaling
 	err := json.Unmarshal(data, &extarg)
 	if err != nil {
 		return fmt.Errorf("argument json err: %v", err)
 	}
 
-	argument.Type, err = NewType(extarg.Type)
+	argument.Type, err = NewType(extarg.Type, extarg.Components)
 	if err != nil {
 		return err
 	}
@@ -117,7 +122,7 @@ func (arguments Arguments) unpackTuple(v interface{}, marshalledValues []interfa
 	var abi2struct map[string]string
 	if kind == reflect.Struct {
 		var err error
-		abi2struct, err = mapAbiToStructFields(arguments, value)
+		abi2struct, err = mapArgNamesToStructFields(arguments.NonIndexed().names(), value)
 		if err != nil {
 			return err
 		}
@@ -129,7 +134,7 @@ func (arguments Arguments) unpackTuple(v interface{}, marshalledValues []interfa
 		switch kind {
 		case reflect.Struct:
 			if structField, ok := abi2struct[arg.Name]; ok {
-				if err := set(value.FieldByName(structField), reflectValue, arg); err != nil {
+				if err := set(value.FieldByName(structField), reflectValue, arg.Type); err != nil {
 					return err
 				}
 			}
@@ -142,7 +147,7 @@ func (arguments Arguments) unpackTuple(v interface{}, marshalledValues []interfa
 				return err
 			}
 
-			if err := set(v.Elem(), reflectValue, arg); err != nil {
+			if err := set(v.Elem(), reflectValue, arg.Type); err != nil {
 				return err
 			}
 		default:
@@ -162,36 +167,22 @@ func (arguments Arguments) unpackAtomic(v interface{}, marshalledValues []interf
 	kind := elem.Kind()
 	reflectValue := reflect.ValueOf(marshalledValues[0])
 
-	var abi2struct map[string]string
-	if kind == reflect.Struct {
-		var err error
-		if abi2struct, err = mapAbiToStructFields(arguments, elem); err != nil {
+	arg := arguments.NonIndexed()[0]
+
+	// A struct receives a tuple directly, otherwise the field of the argument
+	if kind == reflect.Struct && arg.Type.T != TupleTy {
+		abi2struct, err := mapArgNamesToStructFields([]string{arg.Name}, elem)
+		if err != nil {
 			return err
 		}
-		arg := arguments.NonIndexed()[0]
 		if structField, ok := abi2struct[arg.Name]; ok {
-			return set(elem.FieldByName(structField), reflectValue, arg)
+			return set(elem.FieldByName(structField), reflectValue, arg.Type)
 		}
 		return nil
 	}
 
-	return set(elem, reflectValue, arguments.NonIndexed()[0])
-
-}
+	return set(elem, reflectValue, arg.Type)
 
-// Computes the full size of an array;
-// i.e. counting nested arrays, which count towards size for unpacking.
-func getArraySize(arr *Type) int {
-	size := arr.Size
-	// Arrays can be nested, with each element being the same size
-	arr = arr.Elem
-	for arr.T == ArrayTy {
-		// Keep multiplying by elem.Size while the elem is an array.
-		size *= arr.Size
-		arr = arr.Elem
-	}
-	// Now we have the full array size, including its children.
-	return size
 }
 
 // UnpackValues can be used to unpack ABI-encoded hexdata according to the ABI-specification,
@@ -202,18 +193,18 @@ func (arguments Arguments) UnpackValues(data []byte) ([]interface{}, error) {
 	virtualArgs := 0
 	for index, arg := range arguments.NonIndexed() {
 		marshalledValue, err := toGoType((index+virtualArgs)*32, arg.Type, data)
-		if arg.Type.T == ArrayTy {
-			// If we have a static array, like [3]uint256, these are coded as
-			// just like uint256,uint256,uint256.
-			// This means that we need to add two 'virtual' arguments when
-			// we count the index from now on.
+		if !isDynamicType(arg.Type) {
+			// Static arrays and tuples, like [3]uint256 or (uint256,bool), are
+			// encoded in place just like uint256,uint256,uint256. This means
+			// that we need to add 'virtual' arguments when we count the index
+			// from now on.
 			//
-			// Array values nested multiple levels deep are also encoded inline:
+			// Values nested multiple levels deep are also encoded inline:
 			// [2][3]uint256: uint256,uint256,uint256,uint256,uint256,uint256
 			//
-			// Calculate the full array size to get the correct offset for the next argument.
-			// Decrement it by 1, as the normal index increment is still applied.
-			virtualArgs += getArraySize(&arg.Type) - 1
+			// Decrement the word count by 1, as the normal index increment is
+			// still applied.
+			virtualArgs += getTypeSize(arg.Type)/32 - 1
 		}
 		if err != nil {
 			return nil, err
@@ -237,17 +228,13 @@ func (arguments Arguments) Pack(args ...interface{}) ([]byte, error) {
 		return nil, fmt.Errorf("argument count mismatch: %d for %d", len(args), len(abiArgs))
 	}
 	// variable input is the output appended at the end of packed
-	// output. This is used for strings and bytes types input.
+	// output. This is used for dynamic types like strings, bytes and slices.
 	var variableInput []byte
 
 	// input offset is the bytes offset for packed output
 	inputOffset := 0
 	for _, abiArg := range abiArgs {
-		if abiArg.Type.T == ArrayTy {
-			inputOffset += 32 * abiArg.Type.Size
-		} else {
-			inputOffset += 32
-		}
+		inputOffset += getTypeSize(abiArg.Type)
 	}
 	var ret []byte
 	for i, a := range args {
@@ -257,8 +244,8 @@ func (arguments Arguments) Pack(args ...interface{}) ([]byte, error) {
 		if err != nil {
 			return nil, err
 		}
-		// check for a slice type (string, bytes, slice)
-		if input.Type.requiresLengthPrefix() {
+		// check for a dynamic type (string, bytes, slice, dynamic array or tuple)
+		if isDynamicType(input.Type) {
 			// calculate the offset
 			offset := inputOffset + len(variableInput)
 			// set the offset
@@ -277,6 +264,15 @@ func (arguments Arguments) Pack(args ...interface{}) ([]byte, error) {
 	return ret, nil
 }
 
+// names returns the names of the arguments.
+func (arguments Arguments) names() []string {
+	names := make([]string, len(arguments))
+	for i, arg := range arguments {
+		names[i] = arg.Name
+	}
+	return names
+}
+
 // capitalise makes the first character of a string upper case, also removing any
 // prefixing underscores from the variable names.
 func capitalise(input string) string {
@@ -288,3 +284,15 @@ func capitalise(input string) string {
 	}
 	return strings.ToUpper(input[:1]) + input[1:]
 }
+
+// ToCamelCase converts an under-score string to a camel-case string, used as
+// the Go field name of tuple fields.
+func ToCamelCase(input string) string {
+	parts := strings.Split(input, "_")
+	for i, s := range parts {
+		if len(s) > 0 {
+			parts[i] = strings.ToUpper(s[:1]) + s[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -36,6 +36,10 @@ var (
 	// on a backend that doesn't implement PendingContractCaller.
 	ErrNoPendingState = errors.New("backend does not support pending state")
 
+	// This error is raised when attempting to watch events with a confirmation
+	// depth on a backend that doesn't implement ChainHeadSubscriber.
+	ErrNoChainHeads = errors.New("backend does not support chain head subscriptions")
+
 	// This error is returned by WaitDeployed if contract creation leaves an
 	// empty contract behind.
 	ErrNoCodeAfterDeploy = errors.New("no contract code after deployment")
@@ -62,6 +66,25 @@ type PendingContractCaller interface {
 	PendingCallContract(ctx context.Context, call ethereum.CallMsg) ([]byte, error)
 }
 
+// BatchCaller defines methods to execute multiple contract calls in a single round
+// trip. Batch will try to discover this interface when executing its calls, falling
+// back to calling the contracts one by one if the backend does not support it.
+type BatchCaller interface {
+	// BatchCallContract executes the given contract calls at the given block. The
+	// failures of individual calls are reported in errs, err is only set if the
+	// batch as a whole could not be executed.
+	BatchCallContract(ctx context.Context, calls []ethereum.CallMsg, blockNumber *big.Int) (outputs [][]byte, errs []error, err error)
+}
+
+// PendingBatchCaller defines methods to execute multiple contract calls against the
+// pending state in a single round trip.
+type PendingBatchCaller interface {
+	// PendingBatchCallContract executes the given contract calls against the pending
+	// state. The failures of individual calls are reported in errs, err is only set
+	// if the batch as a whole could not be executed.
+	PendingBatchCallContract(ctx context.Context, calls []ethereum.CallMsg) (outputs [][]byte, errs []error, err error)
+}
+
 // ContractTransactor defines the methods needed to allow operating with contract
 // on a write only basis. Beside the transacting method, the remainder are helpers
 // used when the user does not provide some needed values, but rather leaves it up
@@ -98,6 +121,16 @@ type ContractFilterer interface {
 	SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error)
 }
 
+// ChainHeadSubscriber defines the method needed to track the chain head while
+// watching for events. WatchLogs will try to discover this interface on the
+// filterer when events are only to be delivered after some confirmations. If the
+// backend does not support it, WatchLogs returns ErrNoChainHeads.
+type ChainHeadSubscriber interface {
+	// SubscribeNewHead subscribes to notifications about the current blockchain
+	// head on the given channel.
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+}
+
 // DeployBackend wraps the operations needed by WaitMined and WaitDeployed.
 type DeployBackend interface {
 	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
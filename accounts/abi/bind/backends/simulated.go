@@ -42,14 +42,28 @@ import (
 	"github.com/rwdxchain/go-rwdxchaina/rpc"
 )
 
-// This nil assignment ensures compile time that SimulatedBackend implements bind.ContractBackend.
-var _ bind.ContractBackend = (*SimulatedBackend)(nil)
+// These nil assignments ensure at compile time that SimulatedBackend implements
+// bind.ContractBackend and the chain access interfaces of a full node client.
+var (
+	_ bind.ContractBackend           = (*SimulatedBackend)(nil)
+	_ bind.ChainHeadSubscriber       = (*SimulatedBackend)(nil)
+	_ ethereum.ChainReader           = (*SimulatedBackend)(nil)
+	_ ethereum.TransactionReader     = (*SimulatedBackend)(nil)
+	_ ethereum.ChainStateReader      = (*SimulatedBackend)(nil)
+	_ ethereum.PendingStateReader    = (*SimulatedBackend)(nil)
+	_ ethereum.ContractCaller        = (*SimulatedBackend)(nil)
+	_ ethereum.PendingContractCaller = (*SimulatedBackend)(nil)
+)
 
-var errBlockNumberUnsupported = errors.New("SimulatedBackend cannot access blocks other than the latest block")
-var errGasEstimationFailed = errors.New("gas required exceeds allowance or always failing transaction")
+var (
+	errGasEstimationFailed = errors.New("gas required exceeds allowance or always failing transaction")
+	errPendingBlockDirty   = errors.New("pending block contains transactions")
+)
 
 // SimulatedBackend implements bind.ContractBackend, simulating a blockchain in
 // the background. Its main purpose is to allow easily testing contract bindings.
+// Besides the canonical chain, competing chains can be built with Fork to test
+// the handling of chain reorganisations.
 type SimulatedBackend struct {
 	database   ethdb.Database   // In memory database to store our testing data
 	blockchain *core.BlockChain // Ethereum blockchain to handle the consensus
@@ -57,6 +71,7 @@ type SimulatedBackend struct {
 	mu           sync.Mutex
 	pendingBlock *types.Block   // Currently pending block that will be imported on request
 	pendingState *state.StateDB // Currently pending state that will be the active on on request
+	gasLimit     uint64         // Gas limit of the blocks to mine
 
 	events *filters.EventSystem // Event system for filtering log events live
 
@@ -64,25 +79,36 @@ type SimulatedBackend struct {
 }
 
 // NewSimulatedBackend creates a new binding backend using a simulated blockchain
-// for testing purposes.
+// for testing purposes. The gas limit is used for the genesis block and all the
+// blocks mined afterwards, unless changed with SetGasLimit.
 func NewSimulatedBackend(alloc core.GenesisAlloc, gasLimit uint64) *SimulatedBackend {
 	database := ethdb.NewMemDatabase()
 	genesis := core.Genesis{Config: params.AllEthashProtocolChanges, GasLimit: gasLimit, Alloc: alloc}
 	genesis.MustCommit(database)
-	blockchain, _ := core.NewBlockChain(database, nil, genesis.Config, ethash.NewFaker(), vm.Config{})
+
+	// Keep the state of all blocks around, so that it can be queried and forked
+	// from. Headers are not verified, allowing the gas limit to be changed freely.
+	blockchain, _ := core.NewBlockChain(database, &core.CacheConfig{Disabled: true}, genesis.Config, ethash.NewFullFaker(), vm.Config{})
 
 	backend := &SimulatedBackend{
 		database:   database,
 		blockchain: blockchain,
+		gasLimit:   gasLimit,
 		config:     genesis.Config,
 		events:     filters.NewEventSystem(new(event.TypeMux), &filterBackend{database, blockchain}, false),
 	}
-	backend.rollback()
+	backend.rollback(blockchain.CurrentBlock())
 	return backend
 }
 
+// Blockchain returns the underlying blockchain of the simulated backend.
+func (b *SimulatedBackend) Blockchain() *core.BlockChain {
+	return b.blockchain
+}
+
 // Commit imports all the pending transactions as a single block and starts a
-// fresh new state.
+// fresh new state on top of it. If the block extends a fork which becomes
+// heavier than the canonical chain, the chain is reorganised.
 func (b *SimulatedBackend) Commit() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -90,7 +116,7 @@ func (b *SimulatedBackend) Commit() {
 	if _, err := b.blockchain.InsertChain([]*types.Block{b.pendingBlock}); err != nil {
 		panic(err) // This cannot happen unless the simulator is wrong, fail in that case
 	}
-	b.rollback()
+	b.rollback(b.pendingBlock)
 }
 
 // Rollback aborts all pending transactions, reverting to the last committed state.
@@ -98,15 +124,87 @@ func (b *SimulatedBackend) Rollback() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	b.rollback()
+	b.rollback(b.blockchain.GetBlockByHash(b.pendingBlock.ParentHash()))
 }
 
-func (b *SimulatedBackend) rollback() {
-	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), ethash.NewFaker(), b.database, 1, func(int, *core.BlockGen) {})
-	statedb, _ := b.blockchain.State()
+// Fork sets the pending block on top of the given parent block, which may be
+// any block in the chain. Subsequent commits will extend the fork, which becomes
+// canonical once it is heavier than the current chain. The pending block must
+// not contain any transactions.
+func (b *SimulatedBackend) Fork(ctx context.Context, parentHash common.Hash) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.pendingBlock.Transactions()) != 0 {
+		return errPendingBlockDirty
+	}
+	parent := b.blockchain.GetBlockByHash(parentHash)
+	if parent == nil {
+		return ethereum.NotFound
+	}
+	b.rollback(parent)
+	return nil
+}
+
+// SetGasLimit changes the gas limit of the pending block and all blocks mined
+// afterwards. The pending block must not contain any transactions.
+func (b *SimulatedBackend) SetGasLimit(gasLimit uint64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 
+	if len(b.pendingBlock.Transactions()) != 0 {
+		return errPendingBlockDirty
+	}
+	b.gasLimit = gasLimit
+	b.rollback(b.blockchain.GetBlockByHash(b.pendingBlock.ParentHash()))
+	return nil
+}
+
+// rollback starts a fresh pending block on top of the given parent.
+func (b *SimulatedBackend) rollback(parent *types.Block) {
+	b.generatePending(parent, nil, 0)
+}
+
+// generatePending creates the pending block on top of the given parent with
+// the given transactions, shifting its time by the given number of seconds.
+func (b *SimulatedBackend) generatePending(parent *types.Block, txs []*types.Transaction, offset int64) {
+	blocks, _ := core.GenerateChain(b.config, parent, ethash.NewFaker(), b.database, 1, func(number int, block *core.BlockGen) {
+		block.SetGasLimit(b.gasLimit)
+		for _, tx := range txs {
+			block.AddTxWithChain(b.blockchain, tx)
+		}
+		if offset != 0 {
+			block.OffsetTime(offset)
+		}
+	})
 	b.pendingBlock = blocks[0]
-	b.pendingState, _ = state.New(b.pendingBlock.Root(), statedb.Database())
+	b.pendingState, _ = b.blockchain.StateAt(b.pendingBlock.Root())
+}
+
+// stateByBlockNumber retrieves the state of the canonical block with the given
+// number, or of the latest block if the number is nil.
+func (b *SimulatedBackend) stateByBlockNumber(blockNumber *big.Int) (*state.StateDB, error) {
+	block, err := b.blockByNumber(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return b.blockchain.StateAt(block.Root())
+}
+
+// blockByNumber retrieves the canonical block with the given number, or the
+// latest block if the number is nil.
+func (b *SimulatedBackend) blockByNumber(number *big.Int) (*types.Block, error) {
+	if number == nil {
+		return b.blockchain.CurrentBlock(), nil
+	}
+	if number.Sign() < 0 || !number.IsUint64() {
+		return nil, ethereum.NotFound
+	}
+	block := b.blockchain.GetBlockByNumber(number.Uint64())
+	if block == nil {
+		return nil, ethereum.NotFound
+	}
+	return block, nil
 }
 
 // CodeAt returns the code associated with a certain account in the blockchain.
@@ -114,10 +212,10 @@ func (b *SimulatedBackend) CodeAt(ctx context.Context, contract common.Address,
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	if blockNumber != nil && blockNumber.Cmp(b.blockchain.CurrentBlock().Number()) != 0 {
-		return nil, errBlockNumberUnsupported
+	statedb, err := b.stateByBlockNumber(blockNumber)
+	if err != nil {
+		return nil, err
 	}
-	statedb, _ := b.blockchain.State()
 	return statedb.GetCode(contract), nil
 }
 
@@ -126,10 +224,10 @@ func (b *SimulatedBackend) BalanceAt(ctx context.Context, contract common.Addres
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	if blockNumber != nil && blockNumber.Cmp(b.blockchain.CurrentBlock().Number()) != 0 {
-		return nil, errBlockNumberUnsupported
+	statedb, err := b.stateByBlockNumber(blockNumber)
+	if err != nil {
+		return nil, err
 	}
-	statedb, _ := b.blockchain.State()
 	return statedb.GetBalance(contract), nil
 }
 
@@ -138,10 +236,10 @@ func (b *SimulatedBackend) NonceAt(ctx context.Context, contract common.Address,
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	if blockNumber != nil && blockNumber.Cmp(b.blockchain.CurrentBlock().Number()) != 0 {
-		return 0, errBlockNumberUnsupported
+	statedb, err := b.stateByBlockNumber(blockNumber)
+	if err != nil {
+		return 0, err
 	}
-	statedb, _ := b.blockchain.State()
 	return statedb.GetNonce(contract), nil
 }
 
@@ -150,10 +248,10 @@ func (b *SimulatedBackend) StorageAt(ctx context.Context, contract common.Addres
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	if blockNumber != nil && blockNumber.Cmp(b.blockchain.CurrentBlock().Number()) != 0 {
-		return nil, errBlockNumberUnsupported
+	statedb, err := b.stateByBlockNumber(blockNumber)
+	if err != nil {
+		return nil, err
 	}
-	statedb, _ := b.blockchain.State()
 	val := statedb.GetState(contract, key)
 	return val[:], nil
 }
@@ -161,9 +259,100 @@ func (b *SimulatedBackend) StorageAt(ctx context.Context, contract common.Addres
 // TransactionReceipt returns the receipt of a transaction.
 func (b *SimulatedBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
 	receipt, _, _, _ := rawdb.ReadReceipt(b.database, txHash)
+	if receipt == nil {
+		return nil, ethereum.NotFound
+	}
 	return receipt, nil
 }
 
+// TransactionByHash returns the transaction with the given hash, checking the
+// pending block first. Mined transactions may be part of a fork rather than the
+// canonical chain.
+func (b *SimulatedBackend) TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if tx := b.pendingBlock.Transaction(txHash); tx != nil {
+		return tx, true, nil
+	}
+	if tx, _, _, _ := rawdb.ReadTransaction(b.database, txHash); tx != nil {
+		return tx, false, nil
+	}
+	return nil, false, ethereum.NotFound
+}
+
+// BlockByHash retrieves a block based on the block hash.
+func (b *SimulatedBackend) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	if block := b.blockchain.GetBlockByHash(hash); block != nil {
+		return block, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+// BlockByNumber retrieves a block from the canonical chain. If number is nil,
+// the latest known block is returned.
+func (b *SimulatedBackend) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return b.blockByNumber(number)
+}
+
+// HeaderByHash returns a block header with the given hash.
+func (b *SimulatedBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	if header := b.blockchain.GetHeaderByHash(hash); header != nil {
+		return header, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+// HeaderByNumber returns a block header from the canonical chain. If number is
+// nil, the latest known header is returned.
+func (b *SimulatedBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	block, err := b.blockByNumber(number)
+	if err != nil {
+		return nil, err
+	}
+	return block.Header(), nil
+}
+
+// TransactionCount returns the number of transactions in the given block.
+func (b *SimulatedBackend) TransactionCount(ctx context.Context, blockHash common.Hash) (uint, error) {
+	block, err := b.BlockByHash(ctx, blockHash)
+	if err != nil {
+		return 0, err
+	}
+	return uint(len(block.Transactions())), nil
+}
+
+// TransactionInBlock returns the transaction at the given index in the given block.
+func (b *SimulatedBackend) TransactionInBlock(ctx context.Context, blockHash common.Hash, index uint) (*types.Transaction, error) {
+	block, err := b.BlockByHash(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	txs := block.Transactions()
+	if index >= uint(len(txs)) {
+		return nil, ethereum.NotFound
+	}
+	return txs[index], nil
+}
+
+// PendingBalanceAt returns the wei balance of an account in the pending state.
+func (b *SimulatedBackend) PendingBalanceAt(ctx context.Context, account common.Address) (*big.Int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.pendingState.GetBalance(account), nil
+}
+
+// PendingStorageAt returns the value of key in the storage of an account in the
+// pending state.
+func (b *SimulatedBackend) PendingStorageAt(ctx context.Context, account common.Address, key common.Hash) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	val := b.pendingState.GetState(account, key)
+	return val[:], nil
+}
+
 // PendingCodeAt returns the code associated with an account in the pending state.
 func (b *SimulatedBackend) PendingCodeAt(ctx context.Context, contract common.Address) ([]byte, error) {
 	b.mu.Lock()
@@ -172,19 +361,28 @@ func (b *SimulatedBackend) PendingCodeAt(ctx context.Context, contract common.Ad
 	return b.pendingState.GetCode(contract), nil
 }
 
+// PendingTransactionCount returns the number of transactions in the pending block.
+func (b *SimulatedBackend) PendingTransactionCount(ctx context.Context) (uint, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return uint(len(b.pendingBlock.Transactions())), nil
+}
+
 // CallContract executes a contract call.
 func (b *SimulatedBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	if blockNumber != nil && blockNumber.Cmp(b.blockchain.CurrentBlock().Number()) != 0 {
-		return nil, errBlockNumberUnsupported
+	block, err := b.blockByNumber(blockNumber)
+	if err != nil {
+		return nil, err
 	}
-	state, err := b.blockchain.State()
+	state, err := b.blockchain.StateAt(block.Root())
 	if err != nil {
 		return nil, err
 	}
-	rval, _, _, err := b.callContract(ctx, call, b.blockchain.CurrentBlock(), state)
+	rval, _, _, err := b.callContract(ctx, call, block, state)
 	return rval, err
 }
 
@@ -306,16 +504,8 @@ func (b *SimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transa
 		panic(fmt.Errorf("invalid transaction nonce: got %d, want %d", tx.Nonce(), nonce))
 	}
 
-	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), ethash.NewFaker(), b.database, 1, func(number int, block *core.BlockGen) {
-		for _, tx := range b.pendingBlock.Transactions() {
-			block.AddTxWithChain(b.blockchain, tx)
-		}
-		block.AddTxWithChain(b.blockchain, tx)
-	})
-	statedb, _ := b.blockchain.State()
-
-	b.pendingBlock = blocks[0]
-	b.pendingState, _ = state.New(b.pendingBlock.Root(), statedb.Database())
+	parent := b.blockchain.GetBlockByHash(b.pendingBlock.ParentHash())
+	b.generatePending(parent, append(b.pendingBlock.Transactions(), tx), 0)
 	return nil
 }
 
@@ -387,21 +577,40 @@ func (b *SimulatedBackend) SubscribeFilterLogs(ctx context.Context, query ethere
 	}), nil
 }
 
+// SubscribeNewHead returns an event subscription for a new header imported as
+// the head of the simulated chain.
+func (b *SimulatedBackend) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	sink := make(chan *types.Header)
+	sub := b.events.SubscribeNewHeads(sink)
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case head := <-sink:
+				select {
+				case ch <- head:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
 // AdjustTime adds a time shift to the simulated clock.
 func (b *SimulatedBackend) AdjustTime(adjustment time.Duration) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), ethash.NewFaker(), b.database, 1, func(number int, block *core.BlockGen) {
-		for _, tx := range b.pendingBlock.Transactions() {
-			block.AddTx(tx)
-		}
-		block.OffsetTime(int64(adjustment.Seconds()))
-	})
-	statedb, _ := b.blockchain.State()
-
-	b.pendingBlock = blocks[0]
-	b.pendingState, _ = state.New(b.pendingBlock.Root(), statedb.Database())
 
+	parent := b.blockchain.GetBlockByHash(b.pendingBlock.ParentHash())
+	b.generatePending(parent, b.pendingBlock.Transactions(), int64(adjustment.Seconds()))
 	return nil
 }
 
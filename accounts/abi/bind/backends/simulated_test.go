@@ -0,0 +1,356 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package backends
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina"
+	"github.com/rwdxchain/go-rwdxchaina/accounts/abi"
+	"github.com/rwdxchain/go-rwdxchaina/accounts/abi/bind"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/core"
+	"github.com/rwdxchain/go-rwdxchaina/core/types"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+)
+
+var (
+	testKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddr    = crypto.PubkeyToAddress(testKey.PublicKey)
+	pingABI     = `[{"type":"event","name":"Ping","inputs":[{"name":"value","type":"uint256"}]}]`
+	pingTopic   = crypto.Keccak256Hash([]byte("Ping(uint256)"))
+	pingRuntime = append(append([]byte{
+		0x60, 0x00, 0x35, // CALLDATALOAD(0)
+		0x60, 0x00, 0x52, // MSTORE(0)
+		0x7f, // PUSH32 topic
+	}, pingTopic.Bytes()...), []byte{
+		0x60, 0x20, 0x60, 0x00, 0xa1, // LOG1(0, 32, topic)
+		0x00, // STOP
+	}...)
+	// pingCode deploys a contract emitting Ping with the first word of the
+	// call data on every call.
+	pingCode = append([]byte{
+		0x60, byte(len(pingRuntime)), 0x80, 0x60, 0x0b, 0x60, 0x00, 0x39, // CODECOPY(0, 11, len)
+		0x60, 0x00, 0xf3, // RETURN(0, len)
+	}, pingRuntime...)
+)
+
+// deployPing deploys the Ping emitting contract on a fresh simulated chain.
+func deployPing(t *testing.T) (*SimulatedBackend, *bind.BoundContract, common.Address) {
+	sim := NewSimulatedBackend(core.GenesisAlloc{testAddr: {Balance: big.NewInt(10000000000)}}, 10000000)
+
+	parsed, err := abi.JSON(strings.NewReader(pingABI))
+	if err != nil {
+		t.Fatalf("failed to parse ABI: %v", err)
+	}
+	address, _, contract, err := bind.DeployContract(bind.NewKeyedTransactor(testKey), parsed, pingCode, sim)
+	if err != nil {
+		t.Fatalf("failed to deploy contract: %v", err)
+	}
+	sim.Commit()
+	return sim, contract, address
+}
+
+// ping sends a transaction emitting a Ping event and mines it.
+func ping(t *testing.T, sim *SimulatedBackend, contract common.Address, value int64) {
+	nonce, err := sim.PendingNonceAt(context.Background(), testAddr)
+	if err != nil {
+		t.Fatalf("failed to retrieve nonce: %v", err)
+	}
+	data := common.LeftPadBytes(big.NewInt(value).Bytes(), 32)
+	tx, err := types.SignTx(types.NewTransaction(nonce, contract, new(big.Int), 100000, big.NewInt(1), data), types.HomesteadSigner{}, testKey)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	if err := sim.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+	sim.Commit()
+}
+
+// reorg replaces the head block of the simulated chain by two empty ones.
+func reorg(t *testing.T, sim *SimulatedBackend) {
+	head := sim.blockchain.CurrentBlock()
+	if err := sim.Fork(context.Background(), head.ParentHash()); err != nil {
+		t.Fatalf("failed to fork: %v", err)
+	}
+	sim.Commit()
+	sim.Commit()
+
+	if sim.blockchain.GetBlockByNumber(head.NumberU64()).Hash() == head.Hash() {
+		t.Fatalf("fork not adopted: block %d still canonical", head.NumberU64())
+	}
+}
+
+// expectPings checks that exactly the given Ping values are delivered, in
+// order, negative values denoting retractions.
+func expectPings(t *testing.T, logs chan types.Log, want ...int64) {
+	t.Helper()
+	for i, value := range want {
+		select {
+		case log := <-logs:
+			have := new(big.Int).SetBytes(log.Data).Int64()
+			if log.Removed {
+				have = -have
+			}
+			if have != value {
+				t.Fatalf("ping %d mismatch: have %d, want %d", i, have, value)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("ping %d not delivered", i)
+		}
+	}
+	select {
+	case log := <-logs:
+		t.Fatalf("unexpected ping: %x, removed %v", log.Data, log.Removed)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// Tests that watched events removed by a reorganisation are retracted.
+func TestWatchLogsReorg(t *testing.T) {
+	sim, contract, address := deployPing(t)
+
+	logs, sub, err := contract.WatchLogs(nil, "Ping")
+	if err != nil {
+		t.Fatalf("failed to watch: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	ping(t, sim, address, 1)
+	ping(t, sim, address, 2)
+	expectPings(t, logs, 1, 2)
+
+	reorg(t, sim)
+	expectPings(t, logs, -2)
+
+	ping(t, sim, address, 3)
+	expectPings(t, logs, 3)
+}
+
+// Tests that watched events are delivered only after the requested number of
+// confirmations, and never if they are reorged out before.
+func TestWatchLogsConfirmations(t *testing.T) {
+	sim, contract, address := deployPing(t)
+
+	logs, sub, err := contract.WatchLogs(&bind.WatchOpts{Confirmations: 2}, "Ping")
+	if err != nil {
+		t.Fatalf("failed to watch: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	ping(t, sim, address, 1)
+	sim.Commit()
+	expectPings(t, logs)
+	sim.Commit()
+	expectPings(t, logs, 1)
+
+	// Removed logs are announced asynchronously, let the retraction arrive
+	// before the blocks which would confirm the reorged out event
+	ping(t, sim, address, 2)
+	reorg(t, sim)
+	expectPings(t, logs)
+	sim.Commit()
+	sim.Commit()
+	expectPings(t, logs)
+
+	ping(t, sim, address, 3)
+	sim.Commit()
+	sim.Commit()
+	expectPings(t, logs, 3)
+}
+
+// sendValue sends a value transfer to the given address to the pending block.
+func sendValue(t *testing.T, sim *SimulatedBackend, to common.Address, value int64) *types.Transaction {
+	nonce, err := sim.PendingNonceAt(context.Background(), testAddr)
+	if err != nil {
+		t.Fatalf("failed to retrieve nonce: %v", err)
+	}
+	tx, err := types.SignTx(types.NewTransaction(nonce, to, big.NewInt(value), 21000, big.NewInt(1), nil), types.HomesteadSigner{}, testKey)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	if err := sim.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+	return tx
+}
+
+// Tests that blocks, headers and transactions can be retrieved, and that
+// transactions are reported pending until mined.
+func TestChainReader(t *testing.T) {
+	ctx := context.Background()
+	sim := NewSimulatedBackend(core.GenesisAlloc{testAddr: {Balance: big.NewInt(10000000000)}}, 10000000)
+
+	tx := sendValue(t, sim, common.Address{1}, 1)
+	if count, _ := sim.PendingTransactionCount(ctx); count != 1 {
+		t.Fatalf("pending transaction count mismatch: have %d, want 1", count)
+	}
+	if _, pending, err := sim.TransactionByHash(ctx, tx.Hash()); err != nil || !pending {
+		t.Fatalf("pending transaction lookup: pending %v, err %v", pending, err)
+	}
+	if _, err := sim.TransactionReceipt(ctx, tx.Hash()); err != ethereum.NotFound {
+		t.Fatalf("pending receipt error mismatch: have %v, want %v", err, ethereum.NotFound)
+	}
+	sim.Commit()
+
+	if _, pending, err := sim.TransactionByHash(ctx, tx.Hash()); err != nil || pending {
+		t.Fatalf("mined transaction lookup: pending %v, err %v", pending, err)
+	}
+	block, err := sim.BlockByNumber(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve latest block: %v", err)
+	}
+	if block.NumberU64() != 1 {
+		t.Fatalf("latest block number mismatch: have %d, want 1", block.NumberU64())
+	}
+	if byHash, err := sim.BlockByHash(ctx, block.Hash()); err != nil || byHash.Hash() != block.Hash() {
+		t.Fatalf("block by hash mismatch: %v", err)
+	}
+	if header, err := sim.HeaderByNumber(ctx, big.NewInt(1)); err != nil || header.Hash() != block.Hash() {
+		t.Fatalf("header by number mismatch: %v", err)
+	}
+	if header, err := sim.HeaderByHash(ctx, block.Hash()); err != nil || header.Hash() != block.Hash() {
+		t.Fatalf("header by hash mismatch: %v", err)
+	}
+	if count, err := sim.TransactionCount(ctx, block.Hash()); err != nil || count != 1 {
+		t.Fatalf("transaction count mismatch: have %d, want 1, err %v", count, err)
+	}
+	if have, err := sim.TransactionInBlock(ctx, block.Hash(), 0); err != nil || have.Hash() != tx.Hash() {
+		t.Fatalf("transaction in block mismatch: %v", err)
+	}
+	if _, err := sim.TransactionInBlock(ctx, block.Hash(), 1); err != ethereum.NotFound {
+		t.Fatalf("out of range transaction error mismatch: have %v, want %v", err, ethereum.NotFound)
+	}
+	if _, err := sim.BlockByNumber(ctx, big.NewInt(2)); err != ethereum.NotFound {
+		t.Fatalf("future block error mismatch: have %v, want %v", err, ethereum.NotFound)
+	}
+	if _, err := sim.BlockByHash(ctx, common.Hash{1}); err != ethereum.NotFound {
+		t.Fatalf("unknown block error mismatch: have %v, want %v", err, ethereum.NotFound)
+	}
+	if _, _, err := sim.TransactionByHash(ctx, common.Hash{1}); err != ethereum.NotFound {
+		t.Fatalf("unknown transaction error mismatch: have %v, want %v", err, ethereum.NotFound)
+	}
+}
+
+// Tests that the state of past blocks and the pending state can be queried.
+func TestStateReader(t *testing.T) {
+	ctx := context.Background()
+	sim, _, address := deployPing(t)
+	receiver := common.Address{1}
+
+	sendValue(t, sim, receiver, 1)
+	sim.Commit()
+	sendValue(t, sim, receiver, 2)
+	sim.Commit()
+	sendValue(t, sim, receiver, 4)
+
+	for number, want := range []int64{0, 0, 1, 3} {
+		balance, err := sim.BalanceAt(ctx, receiver, big.NewInt(int64(number)))
+		if err != nil {
+			t.Fatalf("block %d: failed to retrieve balance: %v", number, err)
+		}
+		if balance.Int64() != want {
+			t.Errorf("block %d: balance mismatch: have %v, want %d", number, balance, want)
+		}
+	}
+	if balance, _ := sim.BalanceAt(ctx, receiver, nil); balance.Int64() != 3 {
+		t.Errorf("latest balance mismatch: have %v, want 3", balance)
+	}
+	if balance, _ := sim.PendingBalanceAt(ctx, receiver); balance.Int64() != 7 {
+		t.Errorf("pending balance mismatch: have %v, want 7", balance)
+	}
+	if nonce, _ := sim.NonceAt(ctx, testAddr, big.NewInt(1)); nonce != 1 {
+		t.Errorf("historic nonce mismatch: have %d, want 1", nonce)
+	}
+	if code, _ := sim.CodeAt(ctx, address, big.NewInt(0)); len(code) != 0 {
+		t.Errorf("code present before deployment: %x", code)
+	}
+	if code, _ := sim.CodeAt(ctx, address, big.NewInt(1)); !bytes.Equal(code, pingRuntime) {
+		t.Errorf("code mismatch after deployment: have %x, want %x", code, pingRuntime)
+	}
+	if _, err := sim.BalanceAt(ctx, receiver, big.NewInt(4)); err != ethereum.NotFound {
+		t.Errorf("future state error mismatch: have %v, want %v", err, ethereum.NotFound)
+	}
+}
+
+// Tests that forks become canonical once they are heavier than the current chain.
+func TestFork(t *testing.T) {
+	ctx := context.Background()
+	sim := NewSimulatedBackend(core.GenesisAlloc{testAddr: {Balance: big.NewInt(10000000000)}}, 10000000)
+	receiver := common.Address{1}
+
+	genesis := sim.blockchain.CurrentBlock()
+	tx := sendValue(t, sim, receiver, 1)
+	sim.Commit()
+
+	sendValue(t, sim, receiver, 1)
+	if err := sim.Fork(ctx, genesis.Hash()); err != errPendingBlockDirty {
+		t.Fatalf("dirty fork error mismatch: have %v, want %v", err, errPendingBlockDirty)
+	}
+	sim.Rollback()
+	if err := sim.Fork(ctx, common.Hash{1}); err != ethereum.NotFound {
+		t.Fatalf("unknown parent error mismatch: have %v, want %v", err, ethereum.NotFound)
+	}
+	if err := sim.Fork(ctx, genesis.Hash()); err != nil {
+		t.Fatalf("failed to fork: %v", err)
+	}
+	// Ties in total difficulty are broken randomly, only the second fork block
+	// makes the fork canonical for sure
+	sim.Commit()
+	sim.Commit()
+	if head := sim.blockchain.CurrentBlock().NumberU64(); head != 2 {
+		t.Fatalf("head mismatch after reorg: have %d, want 2", head)
+	}
+	if balance, _ := sim.BalanceAt(ctx, receiver, nil); balance.Sign() != 0 {
+		t.Fatalf("balance mismatch after reorg: have %v, want 0", balance)
+	}
+	if _, err := sim.TransactionReceipt(ctx, tx.Hash()); err != ethereum.NotFound {
+		t.Fatalf("reorged receipt error mismatch: have %v, want %v", err, ethereum.NotFound)
+	}
+}
+
+// Tests that the gas limit of the mined blocks can be changed.
+func TestSetGasLimit(t *testing.T) {
+	ctx := context.Background()
+	sim := NewSimulatedBackend(core.GenesisAlloc{testAddr: {Balance: big.NewInt(10000000000)}}, 10000000)
+
+	if err := sim.SetGasLimit(20000000); err != nil {
+		t.Fatalf("failed to set gas limit: %v", err)
+	}
+	sendValue(t, sim, common.Address{1}, 1)
+	if err := sim.SetGasLimit(30000000); err != errPendingBlockDirty {
+		t.Fatalf("dirty gas limit error mismatch: have %v, want %v", err, errPendingBlockDirty)
+	}
+	sim.Commit()
+	sim.Commit()
+
+	for number := int64(1); number <= 2; number++ {
+		header, err := sim.HeaderByNumber(ctx, big.NewInt(number))
+		if err != nil {
+			t.Fatalf("block %d: failed to retrieve header: %v", number, err)
+		}
+		if header.GasLimit != 20000000 {
+			t.Errorf("block %d: gas limit mismatch: have %d, want 20000000", number, header.GasLimit)
+		}
+	}
+}
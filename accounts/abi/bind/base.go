@@ -36,8 +36,9 @@ type SignerFn func(types.Signer, common.Address, *types.Transaction) (*types.Tra
 
 // CallOpts is the collection of options to fine tune a contract call request.
 type CallOpts struct {
-	Pending bool           // Whether to operate on the pending state or the last known one
-	From    common.Address // Optional the sender address, otherwise the first account is used
+	Pending     bool           // Whether to operate on the pending state or the last known one
+	From        common.Address // Optional the sender address, otherwise the first account is used
+	BlockNumber *big.Int       // Optional the block number on which the call should be performed (nil = latest)
 
 	Context context.Context // Network context to support cancellation and timeouts (nil = no timeout)
 }
@@ -68,8 +69,10 @@ type FilterOpts struct {
 // WatchOpts is the collection of options to fine tune subscribing for events
 // within a bound contract.
 type WatchOpts struct {
-	Start   *uint64         // Start of the queried range (nil = latest)
-	Context context.Context // Network context to support cancellation and timeouts (nil = no timeout)
+	Start         *uint64         // Start of the queried range (nil = latest)
+	Confirmations uint64          // Number of blocks an event must be buried under before delivery (0 = deliver immediately)
+	Retracted     func(types.Log) // Callback for events removed by a reorganisation (nil = deliver them with Removed set)
+	Context       context.Context // Network context to support cancellation and timeouts (nil = no timeout)
 }
 
 // BoundContract is the base wrapper object that reflects a contract on the
@@ -148,10 +151,10 @@ func (c *BoundContract) Call(opts *CallOpts, result interface{}, method string,
 			}
 		}
 	} else {
-		output, err = c.caller.CallContract(ctx, msg, nil)
+		output, err = c.caller.CallContract(ctx, msg, opts.BlockNumber)
 		if err == nil && len(output) == 0 {
 			// Make sure we have a contract to operate on, and bail out otherwise.
-			if code, err = c.caller.CodeAt(ctx, c.address, nil); err != nil {
+			if code, err = c.caller.CodeAt(ctx, c.address, opts.BlockNumber); err != nil {
 				return err
 			} else if len(code) == 0 {
 				return ErrNoCode
@@ -295,6 +298,16 @@ func (c *BoundContract) FilterLogs(opts *FilterOpts, name string, query ...[]int
 
 // WatchLogs filters subscribes to contract logs for future blocks, returning a
 // subscription object that can be used to tear down the watcher.
+//
+// The watcher survives failures of the underlying subscription: it resubscribes
+// and resumes from the last block it has seen, or the chain head at the time of
+// the call, without delivering any log twice. Logs removed from the chain by a
+// reorganisation are passed to the Retracted callback of the options if they
+// were delivered before, or delivered again with their Removed flag set if there
+// is no callback. The callback runs on the watcher's goroutine and must not
+// block. If a confirmation depth is
+// requested, logs are held back until enough blocks have been mined on top of
+// them, and logs removed in the meantime are never delivered at all.
 func (c *BoundContract) WatchLogs(opts *WatchOpts, name string, query ...[]interface{}) (chan types.Log, event.Subscription, error) {
 	// Don't crash on a lazy user
 	if opts == nil {
@@ -317,11 +330,26 @@ func (c *BoundContract) WatchLogs(opts *WatchOpts, name string, query ...[]inter
 	if opts.Start != nil {
 		config.FromBlock = new(big.Int).SetUint64(*opts.Start)
 	}
-	sub, err := c.filterer.SubscribeFilterLogs(ensureContext(opts.Context), config, logs)
-	if err != nil {
+	watcher := newLogWatcher(ensureContext(opts.Context), c.filterer, config, opts.Confirmations, logs)
+	watcher.retract = opts.Retracted
+	if opts.Confirmations > 0 {
+		heads, ok := c.filterer.(ChainHeadSubscriber)
+		if !ok {
+			return nil, nil, ErrNoChainHeads
+		}
+		watcher.heads = heads
+	}
+	if opts.Start == nil {
+		if err := watcher.recordHead(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := watcher.subscribe(); err != nil {
 		return nil, nil, err
 	}
-	return logs, sub, nil
+	return logs, event.NewSubscription(func(quit <-chan struct{}) error {
+		return watcher.loop(opts.Start, quit)
+	}), nil
 }
 
 // UnpackLog unpacks a retrieved log into the provided output structure.
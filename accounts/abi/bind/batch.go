@@ -0,0 +1,210 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/rwdxchain/go-rwdxchaina"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/core/types"
+)
+
+// errBatchNotExecuted is returned for the calls of a batch which was not executed yet.
+var errBatchNotExecuted = errors.New("batch not executed")
+
+// headerReader is implemented by backends able to report the chain head, which
+// is used to execute all the calls of a batch against the same block.
+type headerReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// Batch collects read-only calls to any number of bound contracts, to execute
+// them together against the same block. If the backend implements BatchCaller,
+// the calls are sent in a single round trip, otherwise they are executed one by
+// one.
+type Batch struct {
+	caller ContractCaller
+	calls  []*BatchCall
+	number *big.Int // Block the calls were last executed at
+}
+
+// BatchCall is a contract call queued in a batch.
+type BatchCall struct {
+	contract *BoundContract
+	method   string
+	input    []byte
+	result   interface{}
+	packErr  error // Error packing the input, the call is not executed if set
+	err      error
+}
+
+// NewBatch creates an empty batch of calls to execute with the given backend.
+func NewBatch(caller ContractCaller) *Batch {
+	return &Batch{caller: caller}
+}
+
+// Add queues a call of a contract method with params as input values. Once the
+// batch is executed, the output is unpacked into result, which is of the same
+// type as for BoundContract.Call.
+func (b *Batch) Add(contract *BoundContract, result interface{}, method string, params ...interface{}) *BatchCall {
+	call := &BatchCall{contract: contract, method: method, result: result, err: errBatchNotExecuted}
+	call.input, call.packErr = contract.abi.Pack(method, params...)
+	b.calls = append(b.calls, call)
+	return call
+}
+
+// Len returns the number of calls in the batch.
+func (b *Batch) Len() int {
+	return len(b.calls)
+}
+
+// BlockNumber returns the number of the block the calls were executed at, or nil
+// if they were executed against the pending state or the backend could not report
+// the chain head.
+func (b *Batch) BlockNumber() *big.Int {
+	return b.number
+}
+
+// Execute runs all the calls of the batch. Unless a block number is given in the
+// options, all calls are executed against the latest block at the time of the
+// execution. The returned error is only set if the batch as a whole failed, the
+// outcome of the individual calls is reported by their Err method.
+func (b *Batch) Execute(opts *CallOpts) error {
+	// Don't crash on a lazy user
+	if opts == nil {
+		opts = new(CallOpts)
+	}
+	ctx := ensureContext(opts.Context)
+
+	// Assemble the messages of all the calls which could be packed
+	var (
+		calls []*BatchCall
+		msgs  []ethereum.CallMsg
+	)
+	for _, call := range b.calls {
+		if call.packErr != nil {
+			call.err = call.packErr
+			continue
+		}
+		calls = append(calls, call)
+		msgs = append(msgs, ethereum.CallMsg{From: opts.From, To: &call.contract.address, Data: call.input})
+	}
+	// Pin the calls to the current head, so they see a consistent state
+	number := opts.BlockNumber
+	if !opts.Pending && number == nil {
+		if reader, ok := b.caller.(headerReader); ok {
+			head, err := reader.HeaderByNumber(ctx, nil)
+			if err != nil {
+				return b.fail(calls, err)
+			}
+			number = head.Number
+		}
+	}
+	outputs, errs, err := b.call(ctx, opts.Pending, msgs, number)
+	if err != nil {
+		return b.fail(calls, err)
+	}
+	if len(outputs) != len(msgs) || len(errs) != len(msgs) {
+		return b.fail(calls, fmt.Errorf("batch result count mismatch: have %d/%d, want %d", len(outputs), len(errs), len(msgs)))
+	}
+	if opts.Pending {
+		b.number = nil
+	} else {
+		b.number = number
+	}
+	// Unpack the results into the outputs of the individual calls
+	for i, call := range calls {
+		if call.err = errs[i]; call.err != nil {
+			continue
+		}
+		if len(outputs[i]) == 0 {
+			// Make sure we have a contract to operate on, and bail out otherwise.
+			if call.err = b.checkCode(ctx, opts.Pending, call.contract.address, number); call.err != nil {
+				continue
+			}
+		}
+		call.err = call.contract.abi.Unpack(call.result, call.method, outputs[i])
+	}
+	return nil
+}
+
+// call executes the messages against the requested state, in a single round trip
+// if the backend supports it.
+func (b *Batch) call(ctx context.Context, pending bool, msgs []ethereum.CallMsg, number *big.Int) ([][]byte, []error, error) {
+	outputs, errs := make([][]byte, len(msgs)), make([]error, len(msgs))
+	if pending {
+		if batcher, ok := b.caller.(PendingBatchCaller); ok {
+			return batcher.PendingBatchCallContract(ctx, msgs)
+		}
+		pb, ok := b.caller.(PendingContractCaller)
+		if !ok {
+			return nil, nil, ErrNoPendingState
+		}
+		for i, msg := range msgs {
+			outputs[i], errs[i] = pb.PendingCallContract(ctx, msg)
+		}
+		return outputs, errs, nil
+	}
+	if batcher, ok := b.caller.(BatchCaller); ok {
+		return batcher.BatchCallContract(ctx, msgs, number)
+	}
+	for i, msg := range msgs {
+		outputs[i], errs[i] = b.caller.CallContract(ctx, msg, number)
+	}
+	return outputs, errs, nil
+}
+
+// checkCode returns ErrNoCode if there is no contract at the given address.
+func (b *Batch) checkCode(ctx context.Context, pending bool, address common.Address, number *big.Int) error {
+	var (
+		code []byte
+		err  error
+	)
+	if pending {
+		pb, ok := b.caller.(PendingContractCaller)
+		if !ok {
+			return ErrNoPendingState
+		}
+		code, err = pb.PendingCodeAt(ctx, address)
+	} else {
+		code, err = b.caller.CodeAt(ctx, address, number)
+	}
+	if err != nil {
+		return err
+	}
+	if len(code) == 0 {
+		return ErrNoCode
+	}
+	return nil
+}
+
+// fail marks the given calls as failed with the error of the whole batch.
+func (b *Batch) fail(calls []*BatchCall, err error) error {
+	for _, call := range calls {
+		call.err = err
+	}
+	return err
+}
+
+// Err returns the error of the call, or nil if its output has been unpacked.
+func (c *BatchCall) Err() error {
+	return c.err
+}
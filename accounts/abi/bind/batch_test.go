@@ -0,0 +1,207 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/rwdxchain/go-rwdxchaina"
+	"github.com/rwdxchain/go-rwdxchaina/accounts/abi"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/core/types"
+)
+
+const batchTestABI = `[
+	{"constant":true,"inputs":[],"name":"number","outputs":[{"name":"","type":"uint256"}],"type":"function"},
+	{"constant":true,"inputs":[{"name":"x","type":"uint256"}],"name":"square","outputs":[{"name":"","type":"uint256"}],"type":"function"},
+	{"constant":true,"inputs":[],"name":"revert","outputs":[{"name":"","type":"uint256"}],"type":"function"}
+]`
+
+var errBatchTestRevert = errors.New("execution reverted")
+
+// batchTestCaller is a ContractCaller whose contracts return the number of the
+// block they are called at, or the square of their input.
+type batchTestCaller struct {
+	abi   abi.ABI
+	code  map[common.Address]bool // Addresses with contract code
+	head  int64                   // Number of the chain head
+	calls int                     // Number of calls executed one by one
+}
+
+func (c *batchTestCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	if c.code[contract] {
+		return []byte{0x00}, nil
+	}
+	return nil, nil
+}
+
+func (c *batchTestCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.calls++
+	return c.execute(call, blockNumber)
+}
+
+func (c *batchTestCaller) execute(call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if !c.code[*call.To] {
+		return nil, nil
+	}
+	if blockNumber == nil {
+		blockNumber = big.NewInt(c.head)
+	}
+	switch {
+	case bytes.HasPrefix(call.Data, c.abi.Methods["number"].Id()):
+		return c.abi.Methods["number"].Outputs.Pack(blockNumber)
+	case bytes.HasPrefix(call.Data, c.abi.Methods["square"].Id()):
+		x := new(big.Int).SetBytes(call.Data[4:])
+		return c.abi.Methods["square"].Outputs.Pack(new(big.Int).Mul(x, x))
+	default:
+		return nil, errBatchTestRevert
+	}
+}
+
+func (c *batchTestCaller) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Number: big.NewInt(c.head)}, nil
+}
+
+// batchTestBatcher extends batchTestCaller with support for batch calls.
+type batchTestBatcher struct {
+	*batchTestCaller
+	batches int // Number of batches executed
+}
+
+func (c *batchTestBatcher) BatchCallContract(ctx context.Context, calls []ethereum.CallMsg, blockNumber *big.Int) ([][]byte, []error, error) {
+	c.batches++
+
+	outputs, errs := make([][]byte, len(calls)), make([]error, len(calls))
+	for i, call := range calls {
+		outputs[i], errs[i] = c.execute(call, blockNumber)
+	}
+	return outputs, errs, nil
+}
+
+func newBatchTestCaller(t *testing.T, head int64, contracts ...common.Address) *batchTestCaller {
+	parsed, err := abi.JSON(strings.NewReader(batchTestABI))
+	if err != nil {
+		t.Fatalf("failed to parse ABI: %v", err)
+	}
+	caller := &batchTestCaller{abi: parsed, code: make(map[common.Address]bool), head: head}
+	for _, contract := range contracts {
+		caller.code[contract] = true
+	}
+	return caller
+}
+
+// Tests that the calls of a batch are executed at the same block, with their
+// failures reported individually.
+func TestBatchExecute(t *testing.T) {
+	var (
+		existing = common.Address{1}
+		missing  = common.Address{2}
+	)
+	tests := []struct {
+		batching bool
+		opts     *CallOpts
+		number   int64
+	}{
+		{batching: false, opts: nil, number: 7},
+		{batching: true, opts: nil, number: 7},
+		{batching: false, opts: &CallOpts{BlockNumber: big.NewInt(3)}, number: 3},
+		{batching: true, opts: &CallOpts{BlockNumber: big.NewInt(3)}, number: 3},
+	}
+	for i, tt := range tests {
+		caller := newBatchTestCaller(t, 7, existing)
+
+		var backend ContractCaller = caller
+		batcher := &batchTestBatcher{batchTestCaller: caller}
+		if tt.batching {
+			backend = batcher
+		}
+		var (
+			batch    = NewBatch(backend)
+			contract = NewBoundContract(existing, caller.abi, backend, nil, nil)
+			absent   = NewBoundContract(missing, caller.abi, backend, nil, nil)
+
+			number = new(*big.Int)
+			square = new(*big.Int)
+			revert = new(*big.Int)
+			nocode = new(*big.Int)
+			badarg = new(*big.Int)
+		)
+		calls := []*BatchCall{
+			batch.Add(contract, number, "number"),
+			batch.Add(contract, square, "square", big.NewInt(5)),
+			batch.Add(contract, revert, "revert"),
+			batch.Add(absent, nocode, "number"),
+			batch.Add(contract, badarg, "square", "five"),
+		}
+		if batch.Len() != len(calls) {
+			t.Fatalf("test %d: batch length mismatch: have %d, want %d", i, batch.Len(), len(calls))
+		}
+		for j, call := range calls {
+			if call.Err() != errBatchNotExecuted {
+				t.Fatalf("test %d, call %d: error mismatch before execution: have %v, want %v", i, j, call.Err(), errBatchNotExecuted)
+			}
+		}
+		if err := batch.Execute(tt.opts); err != nil {
+			t.Fatalf("test %d: failed to execute batch: %v", i, err)
+		}
+		if have := batch.BlockNumber(); have == nil || have.Int64() != tt.number {
+			t.Errorf("test %d: batch block mismatch: have %v, want %d", i, have, tt.number)
+		}
+		if err := calls[0].Err(); err != nil || (*number).Int64() != tt.number {
+			t.Errorf("test %d: call block mismatch: have %v (%v), want %d", i, *number, err, tt.number)
+		}
+		if err := calls[1].Err(); err != nil || (*square).Int64() != 25 {
+			t.Errorf("test %d: square mismatch: have %v (%v), want 25", i, *square, err)
+		}
+		if err := calls[2].Err(); err != errBatchTestRevert {
+			t.Errorf("test %d: revert error mismatch: have %v, want %v", i, err, errBatchTestRevert)
+		}
+		if err := calls[3].Err(); err != ErrNoCode {
+			t.Errorf("test %d: missing code error mismatch: have %v, want %v", i, err, ErrNoCode)
+		}
+		if err := calls[4].Err(); err == nil {
+			t.Errorf("test %d: invalid argument accepted", i)
+		}
+		// Make sure the batch was executed in a single round trip if possible
+		if tt.batching && (batcher.batches != 1 || caller.calls != 0) {
+			t.Errorf("test %d: round trips mismatch: have %d batches and %d calls, want 1 batch", i, batcher.batches, caller.calls)
+		}
+		if !tt.batching && caller.calls != 4 {
+			t.Errorf("test %d: sequential calls mismatch: have %d, want 4", i, caller.calls)
+		}
+	}
+}
+
+// Tests that pending batches are rejected if the backend has no pending state.
+func TestBatchNoPendingState(t *testing.T) {
+	caller := newBatchTestCaller(t, 1, common.Address{1})
+
+	batch := NewBatch(caller)
+	call := batch.Add(NewBoundContract(common.Address{1}, caller.abi, caller, nil, nil), new(*big.Int), "number")
+
+	if err := batch.Execute(&CallOpts{Pending: true}); err != ErrNoPendingState {
+		t.Fatalf("batch error mismatch: have %v, want %v", err, ErrNoPendingState)
+	}
+	if err := call.Err(); err != ErrNoPendingState {
+		t.Fatalf("call error mismatch: have %v, want %v", err, ErrNoPendingState)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"bytes"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 	"text/template"
 	"unicode"
@@ -47,7 +48,10 @@ const (
 // manually maintain hard coded strings that break on runtime.
 func Bind(types []string, abis []string, bytecodes []string, pkg string, lang Lang) (string, error) {
 	// Process each individual contract requested binding
-	contracts := make(map[string]*tmplContract)
+	var (
+		contracts = make(map[string]*tmplContract)
+		structs   = make(map[string]*tmplStruct)
+	)
 
 	for i := 0; i < len(types); i++ {
 		// Parse the actual ABI to generate the binding for
@@ -117,6 +121,11 @@ func Bind(types []string, abis []string, bytecodes []string, pkg string, lang La
 			// Append the event to the accumulator list
 			events[original.Name] = &tmplEvent{Original: original, Normalized: normalized}
 		}
+		// Collect the struct types of all tuples, in a deterministic order
+		used := make(map[string]*tmplStruct)
+		if err := bindStructs(evmABI, structs, used, lang); err != nil {
+			return "", err
+		}
 		contracts[types[i]] = &tmplContract{
 			Type:        capitalise(types[i]),
 			InputABI:    strings.Replace(strippedABI, "\"", "\\\"", -1),
@@ -125,12 +134,14 @@ func Bind(types []string, abis []string, bytecodes []string, pkg string, lang La
 			Calls:       calls,
 			Transacts:   transacts,
 			Events:      events,
+			Structs:     used,
 		}
 	}
 	// Generate the contract template data content and render it
 	data := &tmplData{
 		Package:   pkg,
 		Contracts: contracts,
+		Structs:   structs,
 	}
 	buffer := new(bytes.Buffer)
 
@@ -138,6 +149,8 @@ func Bind(types []string, abis []string, bytecodes []string, pkg string, lang La
 		"bindtype":      bindType[lang],
 		"bindtopictype": bindTopicType[lang],
 		"namedtype":     namedType[lang],
+		"setter":        setter[lang],
+		"getter":        getter[lang],
 		"capitalise":    capitalise,
 		"decapitalise":  decapitalise,
 	}
@@ -157,9 +170,100 @@ func Bind(types []string, abis []string, bytecodes []string, pkg string, lang La
 	return buffer.String(), nil
 }
 
+// bindStructs adds the struct types of all tuples used by the contract to
+// structs, and to used. Methods and events are visited in alphabetical order,
+// so the generated struct names don't change between runs. Java bindings only
+// support tuples and plain lists of tuples, an error is returned for lists of
+// lists of tuples.
+func bindStructs(contract abi.ABI, structs, used map[string]*tmplStruct, lang Lang) error {
+	args := []abi.Arguments{contract.Constructor.Inputs}
+
+	methods := make([]string, 0, len(contract.Methods))
+	for name := range contract.Methods {
+		methods = append(methods, name)
+	}
+	sort.Strings(methods)
+	for _, name := range methods {
+		args = append(args, contract.Methods[name].Inputs, contract.Methods[name].Outputs)
+	}
+	events := make([]string, 0, len(contract.Events))
+	for name := range contract.Events {
+		events = append(events, name)
+	}
+	sort.Strings(events)
+	for _, name := range events {
+		args = append(args, contract.Events[name].Inputs)
+	}
+	for _, list := range args {
+		for _, arg := range list {
+			if err := bindStruct(arg.Type, structs, used, lang); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bindStruct adds the struct types of a tuple, or a list of tuples, and of
+// all tuples nested in it to structs and used. Inner tuples are named first.
+func bindStruct(kind abi.Type, structs, used map[string]*tmplStruct, lang Lang) error {
+	switch kind.T {
+	case abi.ArrayTy, abi.SliceTy:
+		if lang == LangJava && kind.Elem.T != abi.TupleTy && hasTuple(*kind.Elem) {
+			return fmt.Errorf("nested lists of tuples are not supported in Java bindings: %v", kind)
+		}
+		return bindStruct(*kind.Elem, structs, used, lang)
+	case abi.TupleTy:
+		key := structKey(kind)
+		if _, exist := used[key]; exist {
+			return nil
+		}
+		fields := make([]*tmplField, len(kind.TupleElems))
+		for i, elem := range kind.TupleElems {
+			if err := bindStruct(*elem, structs, used, lang); err != nil {
+				return err
+			}
+			fields[i] = &tmplField{Name: capitalise(kind.TupleRawNames[i]), SolKind: *elem}
+		}
+		if _, exist := structs[key]; !exist {
+			structs[key] = &tmplStruct{Name: fmt.Sprintf("Struct%d", len(structs)), Fields: fields}
+		}
+		used[key] = structs[key]
+	}
+	return nil
+}
+
+// structKey returns the identifier of a tuple type. Unlike the signature, it
+// includes the field names, which are part of the generated struct.
+func structKey(kind abi.Type) string {
+	switch kind.T {
+	case abi.ArrayTy:
+		return fmt.Sprintf("%s[%d]", structKey(*kind.Elem), kind.Size)
+	case abi.SliceTy:
+		return structKey(*kind.Elem) + "[]"
+	case abi.TupleTy:
+		fields := make([]string, len(kind.TupleElems))
+		for i, elem := range kind.TupleElems {
+			fields[i] = structKey(*elem) + " " + kind.TupleRawNames[i]
+		}
+		return "(" + strings.Join(fields, ",") + ")"
+	default:
+		return kind.String()
+	}
+}
+
+// hasTuple returns whether the type is a tuple or a (nested) list of tuples.
+func hasTuple(kind abi.Type) bool {
+	for kind.T == abi.ArrayTy || kind.T == abi.SliceTy {
+		kind = *kind.Elem
+	}
+	return kind.T == abi.TupleTy
+}
+
 // bindType is a set of type binders that convert Solidity types to some supported
-// programming language types.
-var bindType = map[Lang]func(kind abi.Type) string{
+// programming language types. Tuples are converted to the struct types
+// collected in the given map.
+var bindType = map[Lang]func(kind abi.Type, structs map[string]*tmplStruct) string{
 	LangGo:   bindTypeGo,
 	LangJava: bindTypeJava,
 }
@@ -199,7 +303,17 @@ func arrayBindingGo(inner string, arraySizes []string) string {
 // bindTypeGo converts a Solidity type to a Go one. Since there is no clear mapping
 // from all Solidity types to Go ones (e.g. uint17), those that cannot be exactly
 // mapped will use an upscaled type (e.g. *big.Int).
-func bindTypeGo(kind abi.Type) string {
+func bindTypeGo(kind abi.Type, structs map[string]*tmplStruct) string {
+	if hasTuple(kind) {
+		switch kind.T {
+		case abi.ArrayTy:
+			return fmt.Sprintf("[%d]", kind.Size) + bindTypeGo(*kind.Elem, structs)
+		case abi.SliceTy:
+			return "[]" + bindTypeGo(*kind.Elem, structs)
+		default:
+			return structs[structKey(kind)].Name
+		}
+	}
 	stringKind := kind.String()
 	innerLen, innerMapping := bindUnnestedTypeGo(stringKind)
 	return arrayBindingGo(wrapArray(stringKind, innerLen, innerMapping))
@@ -247,7 +361,13 @@ func arrayBindingJava(inner string, arraySizes []string) string {
 // bindTypeJava converts a Solidity type to a Java one. Since there is no clear mapping
 // from all Solidity types to Java ones (e.g. uint17), those that cannot be exactly
 // mapped will use an upscaled type (e.g. BigDecimal).
-func bindTypeJava(kind abi.Type) string {
+func bindTypeJava(kind abi.Type, structs map[string]*tmplStruct) string {
+	if hasTuple(kind) {
+		if kind.T == abi.ArrayTy || kind.T == abi.SliceTy {
+			return bindTypeJava(*kind.Elem, structs) + "[]"
+		}
+		return structs[structKey(kind)].Name
+	}
 	stringKind := kind.String()
 	innerLen, innerMapping := bindUnnestedTypeJava(stringKind)
 	return arrayBindingJava(wrapArray(stringKind, innerLen, innerMapping))
@@ -310,26 +430,28 @@ func bindUnnestedTypeJava(stringKind string) (int, string) {
 
 // bindTopicType is a set of type binders that convert Solidity types to some
 // supported programming language topic types.
-var bindTopicType = map[Lang]func(kind abi.Type) string{
+var bindTopicType = map[Lang]func(kind abi.Type, structs map[string]*tmplStruct) string{
 	LangGo:   bindTopicTypeGo,
 	LangJava: bindTopicTypeJava,
 }
 
 // bindTypeGo converts a Solidity topic type to a Go one. It is almost the same
-// funcionality as for simple types, but dynamic types get converted to hashes.
-func bindTopicTypeGo(kind abi.Type) string {
-	bound := bindTypeGo(kind)
-	if bound == "string" || bound == "[]byte" {
+// funcionality as for simple types, but dynamic types and tuples get converted
+// to hashes.
+func bindTopicTypeGo(kind abi.Type, structs map[string]*tmplStruct) string {
+	bound := bindTypeGo(kind, structs)
+	if bound == "string" || bound == "[]byte" || hasTuple(kind) {
 		bound = "common.Hash"
 	}
 	return bound
 }
 
 // bindTypeGo converts a Solidity topic type to a Java one. It is almost the same
-// funcionality as for simple types, but dynamic types get converted to hashes.
-func bindTopicTypeJava(kind abi.Type) string {
-	bound := bindTypeJava(kind)
-	if bound == "String" || bound == "Bytes" {
+// funcionality as for simple types, but dynamic types and tuples get converted
+// to hashes.
+func bindTopicTypeJava(kind abi.Type, structs map[string]*tmplStruct) string {
+	bound := bindTypeJava(kind, structs)
+	if bound == "String" || bound == "Bytes" || hasTuple(kind) {
 		bound = "Hash"
 	}
 	return bound
@@ -345,6 +467,12 @@ var namedType = map[Lang]func(string, abi.Type) string{
 // namedTypeJava converts some primitive data types to named variants that can
 // be used as parts of method names.
 func namedTypeJava(javaKind string, solKind abi.Type) string {
+	if hasTuple(solKind) {
+		if solKind.T == abi.TupleTy {
+			return "Tuple"
+		}
+		return "Tuples"
+	}
 	switch javaKind {
 	case "byte[]":
 		return "Binary"
@@ -378,6 +506,45 @@ func namedTypeJava(javaKind string, solKind abi.Type) string {
 	}
 }
 
+// setter is a set of functions that generate the call storing a value of the
+// given type, held in expr, in a generic interface.
+var setter = map[Lang]func(kind abi.Type, expr string, structs map[string]*tmplStruct) string{
+	LangGo:   func(abi.Type, string, map[string]*tmplStruct) string { panic("this shouldn't be needed") },
+	LangJava: setterJava,
+}
+
+// setterJava generates the Interface setter call of a Java value. Tuples are
+// stored as the Interfaces of their fields, lists of tuples as the Interfaces
+// of their elements.
+func setterJava(kind abi.Type, expr string, structs map[string]*tmplStruct) string {
+	switch {
+	case kind.T == abi.TupleTy:
+		return fmt.Sprintf("setTuple(%s.toInterfaces())", expr)
+	case hasTuple(kind):
+		return fmt.Sprintf("setTuples(%s.toInterfaces(%s))", structs[structKey(*kind.Elem)].Name, expr)
+	}
+	return fmt.Sprintf("set%s(%s)", namedTypeJava(bindTypeJava(kind, structs), kind), expr)
+}
+
+// getter is a set of functions that generate the expression retrieving a value
+// of the given type from the generic interface expr.
+var getter = map[Lang]func(kind abi.Type, expr string, structs map[string]*tmplStruct) string{
+	LangGo:   func(abi.Type, string, map[string]*tmplStruct) string { panic("this shouldn't be needed") },
+	LangJava: getterJava,
+}
+
+// getterJava generates the Interface getter expression of a Java value,
+// converting tuples back to their struct classes.
+func getterJava(kind abi.Type, expr string, structs map[string]*tmplStruct) string {
+	switch {
+	case kind.T == abi.TupleTy:
+		return fmt.Sprintf("new %s(%s.getTuple())", structs[structKey(kind)].Name, expr)
+	case hasTuple(kind):
+		return fmt.Sprintf("%s.fromInterfaces(%s.getTuples())", structs[structKey(*kind.Elem)].Name, expr)
+	}
+	return fmt.Sprintf("%s.get%s()", expr, namedTypeJava(bindTypeJava(kind, structs), kind))
+}
+
 // methodNormalizer is a name transformer that modifies Solidity method names to
 // conform to target language naming concentions.
 var methodNormalizer = map[Lang]func(string) string{
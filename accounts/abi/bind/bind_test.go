@@ -252,6 +252,30 @@ var bindTests = []struct {
 			} else if str != "Transact string" {
 				t.Fatalf("Transact string mismatch: have '%s', want 'Transact string'", str)
 			}
+			// Retrieve both strings in a single batch
+			batch := bind.NewBatch(sim)
+			deployString := interactor.BatchDeployString(batch)
+			transactString := interactor.BatchTransactString(batch)
+
+			if _, err := deployString(); err == nil {
+				t.Fatalf("Batched result available before execution")
+			}
+			if err := batch.Execute(nil); err != nil {
+				t.Fatalf("Failed to execute batch: %v", err)
+			}
+			if batch.BlockNumber() == nil || batch.BlockNumber().Uint64() != 1 {
+				t.Fatalf("Batch block mismatch: have %v, want 1", batch.BlockNumber())
+			}
+			if str, err := deployString(); err != nil {
+				t.Fatalf("Failed to retrieve batched deploy string: %v", err)
+			} else if str != "Deploy string" {
+				t.Fatalf("Batched deploy string mismatch: have '%s', want 'Deploy string'", str)
+			}
+			if str, err := transactString(); err != nil {
+				t.Fatalf("Failed to retrieve batched transact string: %v", err)
+			} else if str != "Transact string" {
+				t.Fatalf("Batched transact string mismatch: have '%s', want 'Transact string'", str)
+			}
 		`,
 	},
 	// Tests that plain values can be properly returned and deserialized
@@ -284,6 +308,16 @@ var bindTests = []struct {
 			} else if str != "Hi" || num.Cmp(big.NewInt(1)) != 0 {
 				t.Fatalf("Retrieved value mismatch: have %v/%v, want %v/%v", str, num, "Hi", 1)
 			}
+			batch := bind.NewBatch(sim)
+			result := getter.BatchGetter(batch)
+			if err := batch.Execute(nil); err != nil {
+				t.Fatalf("Failed to execute batch: %v", err)
+			}
+			if str, num, _, err := result(); err != nil {
+				t.Fatalf("Failed to retrieve batched anonymous fields: %v", err)
+			} else if str != "Hi" || num.Cmp(big.NewInt(1)) != 0 {
+				t.Fatalf("Batched value mismatch: have %v/%v, want %v/%v", str, num, "Hi", 1)
+			}
 		`,
 	},
 	// Tests that tuples can be properly returned and deserialized
@@ -316,6 +350,16 @@ var bindTests = []struct {
 			} else if res.A != "Hi" || res.B.Cmp(big.NewInt(1)) != 0 {
 				t.Fatalf("Retrieved value mismatch: have %v/%v, want %v/%v", res.A, res.B, "Hi", 1)
 			}
+			batch := bind.NewBatch(sim)
+			result := tupler.BatchTuple(batch)
+			if err := batch.Execute(&bind.CallOpts{Pending: true}); err != nil {
+				t.Fatalf("Failed to execute pending batch: %v", err)
+			}
+			if res, err := result(); err != nil {
+				t.Fatalf("Failed to retrieve batched structure: %v", err)
+			} else if res.A != "Hi" || res.B.Cmp(big.NewInt(1)) != 0 {
+				t.Fatalf("Batched value mismatch: have %v/%v, want %v/%v", res.A, res.B, "Hi", 1)
+			}
 		`,
 	},
 	// Tests that arrays/slices can be properly returned and deserialized.
@@ -740,6 +784,33 @@ var bindTests = []struct {
 				t.Fatalf("unsubscribed simple event arrived: %v", event)
 			case <-time.After(250 * time.Millisecond):
 			}
+			// Subscribe with a confirmation depth and make sure events arrive only once buried
+			cch := make(chan *EventerSimpleEvent, 16)
+			csub, err := eventer.WatchSimpleEvent(&bind.WatchOpts{Confirmations: 1}, cch, []common.Address{{253}}, nil, nil)
+			if err != nil {
+				t.Fatalf("failed to subscribe to confirmed simple events: %v", err)
+			}
+			if _, err := eventer.RaiseSimpleEvent(auth, common.Address{253}, [32]byte{253}, true, big.NewInt(253)); err != nil {
+				t.Fatalf("failed to raise confirmed simple event: %v", err)
+			}
+			sim.Commit()
+
+			select {
+			case event := <-cch:
+				t.Fatalf("unconfirmed simple event arrived: %v", event)
+			case <-time.After(250 * time.Millisecond):
+			}
+			sim.Commit()
+
+			select {
+			case event := <-cch:
+				if event.Value.Uint64() != 253 || event.Raw.Removed {
+					t.Errorf("confirmed simple log content mismatch: have %v, want 253", event)
+				}
+			case <-time.After(250 * time.Millisecond):
+				t.Fatalf("confirmed simple event didn't arrive")
+			}
+			csub.Unsubscribe()
 		`,
 	},
 	{
@@ -809,6 +880,49 @@ var bindTests = []struct {
 			}
 		`,
 	},
+	// Test that tuples are bound to generated structs, using a contract which
+	// echoes its call data (without the method id) as the return data
+	{
+		`Tuples`,
+		`
+			// Hand assembled, returns the call data following the method id:
+			//   CALLDATASIZE PUSH1 4 SWAP1 SUB DUP1 PUSH1 4 PUSH1 0 CALLDATACOPY PUSH1 0 RETURN
+		`,
+		`600e80600b6000396000f336600490038060046000376000f3`,
+		`[{"constant":true,"name":"nested","type":"function","inputs":[{"name":"t","type":"tuple","components":[{"name":"amount","type":"uint256"},{"name":"entries","type":"tuple[]","components":[{"name":"label","type":"string"},{"name":"values","type":"uint8[]"}]},{"name":"flags","type":"bool[2]"}]},{"name":"b","type":"uint256"}],"outputs":[{"name":"t","type":"tuple","components":[{"name":"amount","type":"uint256"},{"name":"entries","type":"tuple[]","components":[{"name":"label","type":"string"},{"name":"values","type":"uint8[]"}]},{"name":"flags","type":"bool[2]"}]}]},{"constant":false,"name":"store","type":"function","inputs":[{"name":"points","type":"tuple[2]","components":[{"name":"x_pos","type":"uint256"},{"name":"is_set","type":"bool"}]}],"outputs":[]},{"anonymous":false,"name":"Stored","type":"event","inputs":[{"indexed":true,"name":"who","type":"address"},{"indexed":false,"name":"entry","type":"tuple","components":[{"name":"label","type":"string"},{"name":"values","type":"uint8[]"}]}]}]`,
+		`
+			// Generate a new random account and a funded simulator
+			key, _ := crypto.GenerateKey()
+			auth := bind.NewKeyedTransactor(key)
+			sim := backends.NewSimulatedBackend(core.GenesisAlloc{auth.From: {Balance: big.NewInt(10000000000)}}, 10000000)
+
+			_, _, tuples, err := DeployTuples(auth, sim)
+			if err != nil {
+				t.Fatalf("Failed to deploy tuples contract: %v", err)
+			}
+			sim.Commit()
+
+			// Nested dynamic tuples are packed and unpacked into the generated structs
+			in := Struct1{
+				Amount:  big.NewInt(7),
+				Entries: []Struct0{{Label: "a", Values: []uint8{1, 2, 3}}, {Label: "bb", Values: []uint8{}}},
+				Flags:   [2]bool{false, true},
+			}
+			out, err := tuples.Nested(nil, in, big.NewInt(9))
+			if err != nil {
+				t.Fatalf("Failed to call tuples contract: %v", err)
+			}
+			if !reflect.DeepEqual(out, in) {
+				t.Fatalf("Echoed tuple mismatch: have %+v, want %+v", out, in)
+			}
+			// Static tuples are accepted by transactions
+			if _, err := tuples.Store(auth, [2]Struct2{{XPos: big.NewInt(1), IsSet: true}, {XPos: big.NewInt(2)}}); err != nil {
+				t.Fatalf("Failed to transact with tuples contract: %v", err)
+			}
+			// Tuples of events are bound too
+			_ = TuplesStored{Entry: Struct0{Label: "c"}}
+		`,
+	},
 }
 
 // Tests that packages generated by the binder can be successfully compiled and
@@ -866,3 +980,40 @@ func TestBindings(t *testing.T) {
 		t.Fatalf("failed to run binding test: %v\n%s", err, out)
 	}
 }
+
+// Tests that Java bindings nest the classes of tuples in the contract class and
+// marshal them through the tuple accessors of Interface.
+func TestBindingsJavaTuples(t *testing.T) {
+	var abiJSON string
+	for _, tt := range bindTests {
+		if tt.name == "Tuples" {
+			abiJSON = tt.abi
+		}
+	}
+	code, err := Bind([]string{"Tuples"}, []string{abiJSON}, []string{""}, "bindtest", LangJava)
+	if err != nil {
+		t.Fatalf("failed to generate binding: %v", err)
+	}
+	for _, want := range []string{
+		"public static class Struct1 {",
+		"public Struct0[] Entries;",
+		"this.Entries = Struct0.fromInterfaces(fields.get(1).getTuples());",
+		"param0.setTuple(t.toInterfaces());",
+		"result0.setDefaultTuple();",
+		"return new Struct1(results.get(0).getTuple());",
+		"param0.setTuples(Struct2.toInterfaces(points));",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("binding doesn't contain %q", want)
+		}
+	}
+	// Struct classes must not be top level, a Java file holds one public class
+	if i := strings.Index(code, "public static class Struct0"); i < strings.Index(code, "public class Tuples") {
+		t.Errorf("struct class not nested in contract class")
+	}
+	// Lists of lists of tuples have no Java representation
+	nested := `[{"constant":true,"name":"grid","type":"function","inputs":[],"outputs":[{"name":"","type":"tuple[][]","components":[{"name":"x","type":"uint256"}]}]}]`
+	if _, err := Bind([]string{"Grid"}, []string{nested}, []string{""}, "bindtest", LangJava); err == nil {
+		t.Errorf("nested tuple lists accepted")
+	}
+}
@@ -0,0 +1,370 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/core/types"
+)
+
+// watchRetainBlocks is the number of blocks behind the chain head for which
+// delivered logs are remembered, to retract them if they get reorged out.
+const watchRetainBlocks = 128
+
+const (
+	// Delays between attempts to resubscribe after a watch subscription failed.
+	// The delay doubles after every failed attempt, up to the maximum.
+	watchResubscribeDelay    = time.Second
+	watchResubscribeMaxDelay = time.Minute
+)
+
+var (
+	errWatchStopped       = errors.New("watch stopped")
+	errSubscriptionClosed = errors.New("subscription closed")
+)
+
+// logKey uniquely identifies a log within the chain.
+type logKey struct {
+	block common.Hash
+	index uint
+}
+
+func keyOf(log types.Log) logKey {
+	return logKey{log.BlockHash, log.Index}
+}
+
+// logWatcher streams the logs matching a filter query to a channel, surviving
+// failures of the underlying subscription and chain reorganisations.
+type logWatcher struct {
+	ctx      context.Context
+	filterer ContractFilterer
+	heads    ChainHeadSubscriber // Source of chain heads, only needed for confirmations
+	query    ethereum.FilterQuery
+	confirms uint64
+	out      chan<- types.Log
+	retract  func(types.Log) // Receiver of retracted logs, instead of out if set
+	delay    time.Duration   // Initial delay before resubscribing
+
+	logSub  ethereum.Subscription
+	logCh   chan types.Log
+	headSub ethereum.Subscription
+	headCh  chan *types.Header
+
+	head      uint64               // Latest known block number
+	pending   []types.Log          // Logs waiting for confirmations
+	delivered map[logKey]types.Log // Recently delivered logs, retracted if reorged out
+}
+
+func newLogWatcher(ctx context.Context, filterer ContractFilterer, query ethereum.FilterQuery, confirms uint64, out chan<- types.Log) *logWatcher {
+	return &logWatcher{
+		ctx:       ctx,
+		filterer:  filterer,
+		query:     query,
+		confirms:  confirms,
+		out:       out,
+		delay:     watchResubscribeDelay,
+		delivered: make(map[logKey]types.Log),
+	}
+}
+
+// subscribe creates the log subscription, and the head one if confirmations
+// are required.
+func (w *logWatcher) subscribe() error {
+	w.logCh = make(chan types.Log, 128)
+	sub, err := w.filterer.SubscribeFilterLogs(w.ctx, w.query, w.logCh)
+	if err != nil {
+		return err
+	}
+	w.logSub = sub
+
+	if w.heads != nil {
+		w.headCh = make(chan *types.Header, 16)
+		sub, err := w.heads.SubscribeNewHead(w.ctx, w.headCh)
+		if err != nil {
+			w.logSub.Unsubscribe()
+			w.logSub = nil
+			return err
+		}
+		w.headSub = sub
+	}
+	return nil
+}
+
+// recordHead remembers the current chain head, if the filterer can report it,
+// so that the logs missed by a subscription failing before any log arrived are
+// fetched from there.
+func (w *logWatcher) recordHead() error {
+	reader, ok := w.filterer.(headerReader)
+	if !ok {
+		return nil
+	}
+	head, err := reader.HeaderByNumber(w.ctx, nil)
+	if err != nil {
+		return err
+	}
+	w.setHead(head.Number.Uint64())
+	return nil
+}
+
+// unsubscribe tears down the active subscriptions.
+func (w *logWatcher) unsubscribe() {
+	if w.logSub != nil {
+		w.logSub.Unsubscribe()
+		w.logSub = nil
+	}
+	if w.headSub != nil {
+		w.headSub.Unsubscribe()
+		w.headSub = nil
+	}
+}
+
+// loop delivers logs until the watch is stopped, resubscribing whenever the
+// subscriptions fail. If start is set, past logs are delivered from that block
+// on before any new ones.
+func (w *logWatcher) loop(start *uint64, quit <-chan struct{}) error {
+	defer w.unsubscribe()
+
+	from := start
+	for {
+		if err := w.run(from, quit); err == errWatchStopped {
+			return nil
+		}
+		// The subscription failed, resume from the oldest block still tracked
+		w.unsubscribe()
+		if !w.resubscribe(quit) {
+			return nil
+		}
+		from = w.resumeBlock(start)
+	}
+}
+
+// resubscribe tries to subscribe again, backing off between failed attempts.
+// It returns false if the watch was stopped in the meantime.
+func (w *logWatcher) resubscribe(quit <-chan struct{}) bool {
+	delay := w.delay
+	for {
+		select {
+		case <-time.After(delay):
+		case <-quit:
+			return false
+		}
+		if err := w.subscribe(); err == nil {
+			return true
+		}
+		if delay *= 2; delay > watchResubscribeMaxDelay {
+			delay = watchResubscribeMaxDelay
+		}
+	}
+}
+
+// resumeBlock returns the block from which to fetch the logs missed while the
+// subscription was down: the oldest block whose logs may still change what was
+// or will be delivered.
+func (w *logWatcher) resumeBlock(start *uint64) *uint64 {
+	from := start
+	if w.head > 0 {
+		head := w.head
+		from = &head
+	}
+	track := func(number uint64) {
+		if from == nil || number < *from {
+			from = &number
+		}
+	}
+	for _, log := range w.pending {
+		track(log.BlockNumber)
+	}
+	for _, log := range w.delivered {
+		track(log.BlockNumber)
+	}
+	return from
+}
+
+// run fetches the logs from the given block on, if any, then processes the
+// subscriptions until one of them fails or the watch is stopped.
+func (w *logWatcher) run(from *uint64, quit <-chan struct{}) error {
+	if from != nil {
+		if err := w.backfill(*from, quit); err != nil {
+			return err
+		}
+	}
+	var headErr <-chan error
+	if w.headSub != nil {
+		headErr = w.headSub.Err()
+	}
+	for {
+		select {
+		case log := <-w.logCh:
+			if err := w.handle(log, quit); err != nil {
+				return err
+			}
+		case head := <-w.headCh:
+			w.setHead(head.Number.Uint64())
+			if err := w.flush(quit); err != nil {
+				return err
+			}
+		case err := <-w.logSub.Err():
+			if err == nil {
+				err = errSubscriptionClosed
+			}
+			return err
+		case err := <-headErr:
+			if err == nil {
+				err = errSubscriptionClosed
+			}
+			return err
+		case <-quit:
+			return errWatchStopped
+		}
+	}
+}
+
+// backfill retrieves the logs from the given block on. Tracked logs which are
+// not part of the chain anymore are retracted or dropped, all others are
+// handled as if they came from the subscription.
+func (w *logWatcher) backfill(from uint64, quit <-chan struct{}) error {
+	query := w.query
+	query.FromBlock, query.ToBlock = new(big.Int).SetUint64(from), nil
+
+	logs, err := w.filterer.FilterLogs(w.ctx, query)
+	if err != nil {
+		return err
+	}
+	current := make(map[logKey]bool, len(logs))
+	for _, log := range logs {
+		current[keyOf(log)] = true
+	}
+	var removed []types.Log
+	for key, log := range w.delivered {
+		if log.BlockNumber >= from && !current[key] {
+			removed = append(removed, log)
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool {
+		if removed[i].BlockNumber != removed[j].BlockNumber {
+			return removed[i].BlockNumber < removed[j].BlockNumber
+		}
+		return removed[i].Index < removed[j].Index
+	})
+	for _, log := range removed {
+		log.Removed = true
+		if err := w.handle(log, quit); err != nil {
+			return err
+		}
+	}
+	pending := w.pending[:0]
+	for _, log := range w.pending {
+		if log.BlockNumber < from || current[keyOf(log)] {
+			pending = append(pending, log)
+		}
+	}
+	w.pending = pending
+
+	for _, log := range logs {
+		if err := w.handle(log, quit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handle processes a new or removed log.
+func (w *logWatcher) handle(log types.Log, quit <-chan struct{}) error {
+	key := keyOf(log)
+	if log.Removed {
+		for i, pending := range w.pending {
+			if keyOf(pending) == key {
+				w.pending = append(w.pending[:i], w.pending[i+1:]...)
+				return nil
+			}
+		}
+		if _, ok := w.delivered[key]; !ok {
+			return nil // Never delivered, nothing to retract
+		}
+		delete(w.delivered, key)
+		return w.deliver(log, quit)
+	}
+	if _, ok := w.delivered[key]; ok {
+		return nil
+	}
+	for _, pending := range w.pending {
+		if keyOf(pending) == key {
+			return nil
+		}
+	}
+	if w.heads == nil {
+		// Without a head subscription, the logs are the only chain progress indicator
+		w.setHead(log.BlockNumber)
+	}
+	if w.confirms == 0 {
+		return w.deliver(log, quit)
+	}
+	w.pending = append(w.pending, log)
+	return w.flush(quit)
+}
+
+// setHead updates the latest known block number.
+func (w *logWatcher) setHead(number uint64) {
+	if w.heads != nil || number > w.head {
+		w.head = number
+	}
+	for key, log := range w.delivered {
+		if log.BlockNumber+watchRetainBlocks < w.head {
+			delete(w.delivered, key)
+		}
+	}
+}
+
+// flush delivers the pending logs which have enough confirmations.
+func (w *logWatcher) flush(quit <-chan struct{}) error {
+	for i := 0; i < len(w.pending); {
+		log := w.pending[i]
+		if log.BlockNumber+w.confirms > w.head {
+			i++
+			continue
+		}
+		w.pending = append(w.pending[:i], w.pending[i+1:]...)
+		if err := w.deliver(log, quit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliver sends a log to the consumer, remembering it to retract it later.
+// Retracted logs are handed to the retraction callback instead, if any.
+func (w *logWatcher) deliver(log types.Log, quit <-chan struct{}) error {
+	if !log.Removed {
+		w.delivered[keyOf(log)] = log
+	} else if w.retract != nil {
+		w.retract(log)
+		return nil
+	}
+	select {
+	case w.out <- log:
+		return nil
+	case <-quit:
+		return errWatchStopped
+	}
+}
@@ -0,0 +1,368 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina"
+	"github.com/rwdxchain/go-rwdxchaina/accounts/abi"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/core/types"
+	"github.com/rwdxchain/go-rwdxchaina/event"
+)
+
+// scriptedFilterer is a ContractFilterer and ChainHeadSubscriber whose chain
+// contents and subscription events are controlled by the test.
+type scriptedFilterer struct {
+	mu        sync.Mutex
+	chain     []types.Log // Logs returned by FilterLogs
+	head      int64       // Block number returned by HeaderByNumber
+	failures  int         // Number of subscription attempts to fail
+	subs      int         // Number of successful log subscriptions
+	logSink   chan<- types.Log
+	headSink  chan<- *types.Header
+	logBreak  chan error
+	headBreak chan error
+}
+
+func (f *scriptedFilterer) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var logs []types.Log
+	for _, log := range f.chain {
+		if query.FromBlock == nil || log.BlockNumber >= query.FromBlock.Uint64() {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+func (f *scriptedFilterer) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return &types.Header{Number: big.NewInt(f.head)}, nil
+}
+
+func (f *scriptedFilterer) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.failures > 0 {
+		f.failures--
+		return nil, errors.New("connection refused")
+	}
+	f.subs++
+	f.logSink, f.logBreak = ch, make(chan error, 1)
+	return breakableSubscription(f.logBreak), nil
+}
+
+func (f *scriptedFilterer) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.headSink, f.headBreak = ch, make(chan error, 1)
+	return breakableSubscription(f.headBreak), nil
+}
+
+func breakableSubscription(fail chan error) event.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		select {
+		case err := <-fail:
+			return err
+		case <-quit:
+			return nil
+		}
+	})
+}
+
+// setChain replaces the logs returned by FilterLogs.
+func (f *scriptedFilterer) setChain(logs ...types.Log) {
+	f.mu.Lock()
+	f.chain = logs
+	f.mu.Unlock()
+}
+
+// sendLog pushes a log through the current log subscription.
+func (f *scriptedFilterer) sendLog(log types.Log) {
+	f.mu.Lock()
+	sink := f.logSink
+	f.mu.Unlock()
+	sink <- log
+}
+
+// sendHead pushes a header through the current head subscription.
+func (f *scriptedFilterer) sendHead(number int64) {
+	f.mu.Lock()
+	sink := f.headSink
+	f.mu.Unlock()
+	sink <- &types.Header{Number: big.NewInt(number)}
+}
+
+// breakLogs fails the current log subscription and waits until the watcher
+// has subscribed again, failing the given number of attempts first.
+func (f *scriptedFilterer) breakLogs(t *testing.T, failures int) {
+	f.mu.Lock()
+	subs := f.subs
+	f.failures = failures
+	f.logBreak <- errors.New("connection lost")
+	f.mu.Unlock()
+
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(time.Millisecond) {
+		f.mu.Lock()
+		resubscribed := f.subs > subs
+		f.mu.Unlock()
+		if resubscribed {
+			return
+		}
+	}
+	t.Fatalf("watcher did not resubscribe")
+}
+
+func testLog(block uint64, hash string, index uint) types.Log {
+	return types.Log{BlockNumber: block, BlockHash: common.HexToHash(hash), Index: index}
+}
+
+func removed(log types.Log) types.Log {
+	log.Removed = true
+	return log
+}
+
+// startWatcher watches the scripted filterer, with fast resubscriptions.
+// Retracted logs are passed to retract if it's set.
+func startWatcher(t *testing.T, f *scriptedFilterer, start *uint64, confirms uint64, retract ...func(types.Log)) (chan types.Log, event.Subscription) {
+	logs := make(chan types.Log, 16)
+	watcher := newLogWatcher(context.Background(), f, ethereum.FilterQuery{}, confirms, logs)
+	watcher.delay = 10 * time.Millisecond
+	if confirms > 0 {
+		watcher.heads = f
+	}
+	if len(retract) > 0 {
+		watcher.retract = retract[0]
+	}
+	if start == nil {
+		if err := watcher.recordHead(); err != nil {
+			t.Fatalf("failed to record head: %v", err)
+		}
+	}
+	if err := watcher.subscribe(); err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	return logs, event.NewSubscription(func(quit <-chan struct{}) error {
+		return watcher.loop(start, quit)
+	})
+}
+
+// expectLogs checks that exactly the given logs are delivered, in order.
+func expectLogs(t *testing.T, logs chan types.Log, want ...types.Log) {
+	t.Helper()
+	for i, log := range want {
+		select {
+		case have := <-logs:
+			if have.BlockHash != log.BlockHash || have.Index != log.Index || have.Removed != log.Removed {
+				t.Fatalf("log %d mismatch: have block %x index %d removed %v, want block %x index %d removed %v",
+					i, have.BlockHash, have.Index, have.Removed, log.BlockHash, log.Index, log.Removed)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("log %d not delivered", i)
+		}
+	}
+	select {
+	case log := <-logs:
+		t.Fatalf("unexpected log: block %x index %d removed %v", log.BlockHash, log.Index, log.Removed)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// Tests that watchers resume from the last seen block after a subscription
+// failure, without missing or duplicating logs.
+func TestWatchResubscribe(t *testing.T) {
+	var (
+		a = testLog(1, "0x01", 0)
+		b = testLog(2, "0x02", 0)
+		c = testLog(3, "0x03", 0)
+	)
+	f := new(scriptedFilterer)
+	logs, sub := startWatcher(t, f, nil, 0)
+	defer sub.Unsubscribe()
+
+	f.setChain(a)
+	f.sendLog(a)
+	expectLogs(t, logs, a)
+
+	// Log b is mined while the subscription is down, it must be fetched on resume
+	f.setChain(a, b)
+	f.breakLogs(t, 2)
+	expectLogs(t, logs, b)
+
+	// Logs already delivered by the backfill must not be delivered again
+	f.sendLog(b)
+	f.sendLog(c)
+	expectLogs(t, logs, c)
+}
+
+// Tests that watchers resume from the chain head at subscription time if the
+// subscription fails before any log was delivered.
+func TestWatchResubscribeBeforeLogs(t *testing.T) {
+	var (
+		a = testLog(3, "0x03", 0)
+		b = testLog(6, "0x06", 0)
+	)
+	f := &scriptedFilterer{head: 5}
+	f.setChain(a)
+	logs, sub := startWatcher(t, f, nil, 0)
+	defer sub.Unsubscribe()
+
+	// Log b is mined while the subscription is down, older logs stay ignored
+	f.setChain(a, b)
+	f.breakLogs(t, 0)
+	expectLogs(t, logs, b)
+}
+
+// Tests that past logs are delivered from the start block on.
+func TestWatchStart(t *testing.T) {
+	var (
+		a = testLog(1, "0x01", 0)
+		b = testLog(2, "0x02", 0)
+		c = testLog(3, "0x03", 0)
+	)
+	f := new(scriptedFilterer)
+	f.setChain(a, b)
+
+	start := uint64(2)
+	logs, sub := startWatcher(t, f, &start, 0)
+	defer sub.Unsubscribe()
+
+	expectLogs(t, logs, b)
+	f.sendLog(b)
+	f.sendLog(c)
+	expectLogs(t, logs, c)
+}
+
+// Tests that logs removed by a reorganisation are retracted, but only if they
+// were delivered before.
+func TestWatchReorg(t *testing.T) {
+	var (
+		a     = testLog(1, "0x01", 0)
+		b     = testLog(2, "0x02", 0)
+		b2    = testLog(2, "0x12", 0)
+		c     = testLog(3, "0x03", 0)
+		stray = testLog(1, "0xff", 0)
+	)
+	f := new(scriptedFilterer)
+	logs, sub := startWatcher(t, f, nil, 0)
+	defer sub.Unsubscribe()
+
+	f.setChain(a, b)
+	f.sendLog(a)
+	f.sendLog(b)
+	expectLogs(t, logs, a, b)
+
+	// Live reorg replacing block 2
+	f.setChain(a, b2)
+	f.sendLog(removed(b))
+	f.sendLog(removed(stray))
+	f.sendLog(b2)
+	expectLogs(t, logs, removed(b), b2)
+
+	// Reorg while the subscription is down, the backfill must retract
+	f.setChain(a, c)
+	f.breakLogs(t, 0)
+	expectLogs(t, logs, removed(b2), c)
+}
+
+// Tests that retracted logs are passed to the retraction callback instead of
+// being delivered again.
+func TestWatchRetractCallback(t *testing.T) {
+	var (
+		a  = testLog(1, "0x01", 0)
+		b  = testLog(2, "0x02", 0)
+		b2 = testLog(2, "0x12", 0)
+	)
+	retracted := make(chan types.Log, 16)
+	f := new(scriptedFilterer)
+	logs, sub := startWatcher(t, f, nil, 0, func(log types.Log) { retracted <- log })
+	defer sub.Unsubscribe()
+
+	f.sendLog(a)
+	f.sendLog(b)
+	expectLogs(t, logs, a, b)
+
+	f.sendLog(removed(b))
+	f.sendLog(b2)
+	expectLogs(t, logs, b2)
+	expectLogs(t, retracted, removed(b))
+}
+
+// Tests that logs are only delivered after the requested number of
+// confirmations, and that logs removed in the meantime are never delivered.
+func TestWatchConfirmations(t *testing.T) {
+	var (
+		a = testLog(1, "0x01", 0)
+		b = testLog(2, "0x02", 0)
+		c = testLog(4, "0x04", 0)
+	)
+	f := new(scriptedFilterer)
+	logs, sub := startWatcher(t, f, nil, 2)
+	defer sub.Unsubscribe()
+
+	f.sendLog(a)
+	f.sendHead(1)
+	f.sendLog(b)
+	f.sendHead(2)
+	expectLogs(t, logs)
+
+	f.sendHead(3)
+	expectLogs(t, logs, a)
+
+	f.sendLog(removed(b))
+	f.sendHead(4)
+	f.sendLog(c)
+	f.sendHead(5)
+	expectLogs(t, logs)
+
+	f.sendHead(6)
+	expectLogs(t, logs, c)
+}
+
+// Tests that confirmations are rejected if the backend can't report chain heads.
+func TestWatchConfirmationsUnsupported(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(`[{"type":"event","name":"Ping","inputs":[{"name":"value","type":"uint256"}]}]`))
+	if err != nil {
+		t.Fatalf("failed to parse ABI: %v", err)
+	}
+	// Hide the head subscription method of the filterer
+	filterer := struct{ ContractFilterer }{new(scriptedFilterer)}
+	contract := NewBoundContract(common.Address{}, parsed, nil, nil, filterer)
+
+	if _, _, err := contract.WatchLogs(&WatchOpts{Confirmations: 1}, "Ping"); err != ErrNoChainHeads {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrNoChainHeads)
+	}
+	_, sub, err := contract.WatchLogs(nil, "Ping")
+	if err != nil {
+		t.Fatalf("failed to watch without confirmations: %v", err)
+	}
+	sub.Unsubscribe()
+}
@@ -325,7 +325,7 @@ func TestPack(t *testing.T) {
 			common.Hex2Bytes("0000000000000000000000000000000000000000000000000000000000000006666f6f6261720000000000000000000000000000000000000000000000000000"),
 		},
 	} {
-		typ, err := NewType(test.typ)
+		typ, err := NewType(test.typ, nil)
 		if err != nil {
 			t.Fatalf("%v failed. Unexpected parse error: %v", i, err)
 		}
@@ -441,3 +441,137 @@ func TestPackNumber(t *testing.T) {
 		}
 	}
 }
+
+// tupleABI declares methods taking nested dynamic and static tuples.
+const tupleABI = `[
+	{"name": "nested", "type": "function", "inputs": [
+		{"name": "t", "type": "tuple", "components": [
+			{"name": "amount", "type": "uint256"},
+			{"name": "entries", "type": "tuple[]", "components": [
+				{"name": "label", "type": "string"},
+				{"name": "values", "type": "uint8[]"}
+			]},
+			{"name": "flags", "type": "bool[2]"}
+		]},
+		{"name": "b", "type": "uint256"}
+	], "outputs": [
+		{"name": "t", "type": "tuple", "components": [
+			{"name": "amount", "type": "uint256"},
+			{"name": "entries", "type": "tuple[]", "components": [
+				{"name": "label", "type": "string"},
+				{"name": "values", "type": "uint8[]"}
+			]},
+			{"name": "flags", "type": "bool[2]"}
+		]},
+		{"name": "b", "type": "uint256"}
+	]},
+	{"name": "static", "type": "function", "inputs": [
+		{"name": "points", "type": "tuple[2]", "components": [
+			{"name": "x", "type": "uint256"},
+			{"name": "is_set", "type": "bool"}
+		]},
+		{"name": "c", "type": "uint256"}
+	], "outputs": [
+		{"name": "points", "type": "tuple[2]", "components": [
+			{"name": "x", "type": "uint256"},
+			{"name": "is_set", "type": "bool"}
+		]},
+		{"name": "c", "type": "uint256"}
+	]}
+]`
+
+// Encodings of the tupleABI methods' arguments, independently generated.
+const (
+	tupleNestedEncoding = "0000000000000000000000000000000000000000000000000000000000000040" +
+		"000000000000000000000000000000000000000000000000000000000000002a" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000080" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000000" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000000000000000000000000000000000000000040" +
+		"0000000000000000000000000000000000000000000000000000000000000120" +
+		"0000000000000000000000000000000000000000000000000000000000000040" +
+		"0000000000000000000000000000000000000000000000000000000000000080" +
+		"0000000000000000000000000000000000000000000000000000000000000003" +
+		"666f6f0000000000000000000000000000000000000000000000000000000000" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000000000000000000000000000000000000000040" +
+		"0000000000000000000000000000000000000000000000000000000000000080" +
+		"0000000000000000000000000000000000000000000000000000000000000003" +
+		"6261720000000000000000000000000000000000000000000000000000000000" +
+		"0000000000000000000000000000000000000000000000000000000000000000"
+	tupleStaticEncoding = "0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000000000000000000000000000000000000000000" +
+		"0000000000000000000000000000000000000000000000000000000000000003"
+)
+
+type tupleEntry struct {
+	Name   string `abi:"label"`
+	Values []uint8
+}
+
+type tupleNested struct {
+	Amount  *big.Int
+	Entries []tupleEntry
+	Flags   [2]bool
+}
+
+type tuplePoint struct {
+	X     *big.Int
+	IsSet bool
+}
+
+func TestPackTuple(t *testing.T) {
+	abi, err := JSON(strings.NewReader(tupleABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nested := tupleNested{
+		Amount: big.NewInt(1),
+		Entries: []tupleEntry{
+			{Name: "foo", Values: []uint8{1, 2}},
+			{Name: "bar", Values: []uint8{}},
+		},
+		Flags: [2]bool{true, false},
+	}
+	if sig := abi.Methods["nested"].Sig(); sig != "nested((uint256,(string,uint8[])[],bool[2]),uint256)" {
+		t.Errorf("signature mismatch: %s", sig)
+	}
+	packed, err := abi.Pack("nested", nested, big.NewInt(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := common.Hex2Bytes(tupleNestedEncoding); !bytes.Equal(packed[4:], want) {
+		t.Errorf("nested tuple pack mismatch:\nhave %x\nwant %x", packed[4:], want)
+	}
+	// Pointers to structs are packed like the struct
+	packed, err = abi.Pack("nested", &nested, big.NewInt(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := common.Hex2Bytes(tupleNestedEncoding); !bytes.Equal(packed[4:], want) {
+		t.Errorf("nested tuple pointer pack mismatch:\nhave %x\nwant %x", packed[4:], want)
+	}
+
+	points := [2]tuplePoint{{big.NewInt(1), true}, {big.NewInt(2), false}}
+	packed, err = abi.Pack("static", points, big.NewInt(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := common.Hex2Bytes(tupleStaticEncoding); !bytes.Equal(packed[4:], want) {
+		t.Errorf("static tuple pack mismatch:\nhave %x\nwant %x", packed[4:], want)
+	}
+
+	// Structs lacking a field of the tuple can't be packed
+	type partial struct {
+		X *big.Int
+	}
+	if _, err := abi.Pack("static", [2]partial{{big.NewInt(1)}, {big.NewInt(2)}}, big.NewInt(3)); err == nil {
+		t.Errorf("packed tuple from struct missing fields")
+	}
+}
@@ -70,8 +70,9 @@ func mustArrayToByteSlice(value reflect.Value) reflect.Value {
 // set attempts to assign src to dst by either setting, copying or otherwise.
 //
 // set is a bit more lenient when it comes to assignment and doesn't force an as
-// strict ruleset as bare `reflect` does.
-func set(dst, src reflect.Value, output Argument) error {
+// strict ruleset as bare `reflect` does. Tuples are assigned field by field,
+// so they can be unpacked into any struct with matching fields.
+func set(dst, src reflect.Value, t Type) error {
 	dstType := dst.Type()
 	srcType := src.Type()
 	switch {
@@ -80,13 +81,61 @@ func set(dst, src reflect.Value, output Argument) error {
 	case dstType.Kind() == reflect.Interface:
 		dst.Set(src)
 	case dstType.Kind() == reflect.Ptr:
-		return set(dst.Elem(), src, output)
+		if dst.IsNil() && dst.CanSet() {
+			dst.Set(reflect.New(dstType.Elem()))
+		}
+		return set(dst.Elem(), src, t)
+	case t.T == TupleTy && dstType.Kind() == reflect.Struct:
+		return setTuple(dst, src, t)
+	case hasTuple(t) && (dstType.Kind() == reflect.Slice || dstType.Kind() == reflect.Array):
+		return setTupleList(dst, src, t)
 	default:
 		return fmt.Errorf("abi: cannot unmarshal %v in to %v", src.Type(), dst.Type())
 	}
 	return nil
 }
 
+// setTuple assigns the fields of the unpacked tuple src to the struct dst,
+// matching them by abi tag or field name.
+func setTuple(dst, src reflect.Value, t Type) error {
+	fieldmap, err := mapArgNamesToStructFields(t.TupleRawNames, dst)
+	if err != nil {
+		return err
+	}
+	for i, name := range t.TupleRawNames {
+		if field, ok := fieldmap[name]; ok {
+			if err := set(dst.FieldByName(field), src.Field(i), *t.TupleElems[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setTupleList assigns the elements of an unpacked slice or array of tuples
+// src to the slice or array dst.
+func setTupleList(dst, src reflect.Value, t Type) error {
+	if dst.Kind() == reflect.Slice {
+		dst.Set(reflect.MakeSlice(dst.Type(), src.Len(), src.Len()))
+	} else if dst.Len() != src.Len() {
+		return fmt.Errorf("abi: cannot unmarshal %v in to %v", src.Type(), dst.Type())
+	}
+	for i := 0; i < src.Len(); i++ {
+		if err := set(dst.Index(i), src.Index(i), *t.Elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasTuple returns whether the type is a tuple or a (nested) list of tuples.
+func hasTuple(t Type) bool {
+	for t.T == SliceTy || t.T == ArrayTy {
+		t = *t.Elem
+	}
+	return t.T == TupleTy
+}
+
 // requireAssignable assures that `dest` is a pointer and it's not an interface.
 func requireAssignable(dst, src reflect.Value) error {
 	if dst.Kind() != reflect.Ptr && dst.Kind() != reflect.Interface {
@@ -112,13 +161,14 @@ func requireUnpackKind(v reflect.Value, t reflect.Type, k reflect.Kind,
 	return nil
 }
 
-// mapAbiToStringField maps abi to struct fields.
+// mapArgNamesToStructFields maps a slice of argument names to struct fields.
 // first round: for each Exportable field that contains a `abi:""` tag
-//   and this field name exists in the arguments, pair them together.
-// second round: for each argument field that has not been already linked,
+//   and this field name exists in the given argument name list, pair them together.
+// second round: for each argument name that has not been already linked,
 //   find what variable is expected to be mapped into, if it exists and has not been
-//   used, pair them.
-func mapAbiToStructFields(args Arguments, value reflect.Value) (map[string]string, error) {
+//   used, pair them. Generated bindings name the fields in camel case, which
+//   is tried if the capitalised name doesn't exist.
+func mapArgNamesToStructFields(argNames []string, value reflect.Value) (map[string]string, error) {
 
 	typ := value.Type()
 
@@ -148,14 +198,14 @@ func mapAbiToStructFields(args Arguments, value reflect.Value) (map[string]strin
 
 		// check which argument field matches with the abi tag.
 		found := false
-		for _, abiField := range args.NonIndexed() {
-			if abiField.Name == tagName {
-				if abi2struct[abiField.Name] != "" {
+		for _, name := range argNames {
+			if name == tagName {
+				if abi2struct[name] != "" {
 					return nil, fmt.Errorf("struct: abi tag in '%s' already mapped", structFieldName)
 				}
 				// pair them
-				abi2struct[abiField.Name] = structFieldName
-				struct2abi[structFieldName] = abiField.Name
+				abi2struct[name] = structFieldName
+				struct2abi[structFieldName] = name
 				found = true
 			}
 		}
@@ -168,14 +218,16 @@ func mapAbiToStructFields(args Arguments, value reflect.Value) (map[string]strin
 	}
 
 	// second round ~~~
-	for _, arg := range args {
+	for _, abiFieldName := range argNames {
 
-		abiFieldName := arg.Name
 		structFieldName := capitalise(abiFieldName)
 
 		if structFieldName == "" {
 			return nil, fmt.Errorf("abi: purely underscored output cannot unpack to struct")
 		}
+		if camel := ToCamelCase(abiFieldName); !value.FieldByName(structFieldName).IsValid() && value.FieldByName(camel).IsValid() {
+			structFieldName = camel
+		}
 
 		// this abi has already been paired, skip it... unless there exists another, yet unassigned
 		// struct field with the same field name. If so, raise an error:
@@ -97,7 +97,7 @@ func TestTypeRegexp(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		typ, err := NewType(tt.blob)
+		typ, err := NewType(tt.blob, nil)
 		if err != nil {
 			t.Errorf("type %q: failed to parse type string: %v", tt.blob, err)
 		}
@@ -256,7 +256,7 @@ func TestTypeCheck(t *testing.T) {
 		{"invalidType", "", "unsupported arg type: invalidType"},
 		{"invalidSlice[]", "", "unsupported arg type: invalidSlice"},
 	} {
-		typ, err := NewType(test.typ)
+		typ, err := NewType(test.typ, nil)
 		if err != nil && len(test.err) == 0 {
 			t.Fatal("unexpected parse error:", err)
 		} else if err != nil && len(test.err) != 0 {
@@ -281,3 +281,63 @@ func TestTypeCheck(t *testing.T) {
 		}
 	}
 }
+
+// Tests that tuple types are parsed from their components.
+func TestTupleType(t *testing.T) {
+	components := []ArgumentMarshaling{
+		{Name: "amount", Type: "uint256"},
+		{Name: "memo_text", Type: "string"},
+		{Name: "inner", Type: "tuple[2]", Components: []ArgumentMarshaling{
+			{Name: "flag", Type: "bool"},
+			{Name: "data", Type: "bytes"},
+		}},
+	}
+	typ, err := NewType("tuple[]", components)
+	if err != nil {
+		t.Fatalf("failed to parse tuple type: %v", err)
+	}
+	if typ.T != SliceTy || typ.Elem.T != TupleTy {
+		t.Fatalf("type mismatch: have %d of %d, want slice of tuple", typ.T, typ.Elem.T)
+	}
+	if want := "(uint256,string,(bool,bytes)[2])[]"; typ.String() != want {
+		t.Errorf("signature mismatch: have %s, want %s", typ.String(), want)
+	}
+	if want := []string{"amount", "memo_text", "inner"}; !reflect.DeepEqual(typ.Elem.TupleRawNames, want) {
+		t.Errorf("field names mismatch: have %v, want %v", typ.Elem.TupleRawNames, want)
+	}
+	tuple := typ.Type.Elem()
+	for i, name := range []string{"Amount", "MemoText", "Inner"} {
+		if field := tuple.Field(i); field.Name != name {
+			t.Errorf("field %d: name mismatch: have %s, want %s", i, field.Name, name)
+		}
+	}
+	if kind := tuple.Field(2).Type.Kind(); kind != reflect.Array {
+		t.Errorf("nested tuple array kind mismatch: have %v, want %v", kind, reflect.Array)
+	}
+	if !isDynamicType(typ) || !isDynamicType(*typ.Elem) {
+		t.Errorf("tuple with dynamic fields reported static")
+	}
+
+	// Tuples with only static fields are encoded in place
+	static, err := NewType("tuple[3]", []ArgumentMarshaling{{Name: "a", Type: "uint64"}, {Name: "b", Type: "bool[2]"}})
+	if err != nil {
+		t.Fatalf("failed to parse tuple type: %v", err)
+	}
+	if isDynamicType(static) {
+		t.Errorf("static tuple reported dynamic")
+	}
+	if size := getTypeSize(static); size != 3*3*32 {
+		t.Errorf("static tuple size mismatch: have %d, want %d", size, 3*3*32)
+	}
+
+	// Fields must have unique, non-anonymous names
+	if _, err := NewType("tuple", []ArgumentMarshaling{{Name: "_", Type: "uint256"}}); err == nil {
+		t.Errorf("anonymous tuple field accepted")
+	}
+	if _, err := NewType("tuple", []ArgumentMarshaling{{Name: "a_b", Type: "uint256"}, {Name: "aB", Type: "bool"}}); err == nil {
+		t.Errorf("duplicate tuple field accepted")
+	}
+	if _, err := NewType("tuple", []ArgumentMarshaling{{Name: "a", Type: "uint"}}); err == nil {
+		t.Errorf("invalid tuple field type accepted")
+	}
+}
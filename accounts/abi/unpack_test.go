@@ -168,7 +168,7 @@ var unpackTests = []unpackTest{
 	// multi dimensional, if these pass, all types that don't require length prefix should pass
 	{
 		def:  `[{"type": "uint8[][]"}]`,
-		enc:  "00000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000008000000000000000000000000000000000000000000000000000000000000000E0000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000002",
+		enc:  "00000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a0000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000002",
 		want: [][]uint8{{1, 2}, {1, 2}},
 	},
 	{
@@ -178,7 +178,7 @@ var unpackTests = []unpackTest{
 	},
 	{
 		def:  `[{"type": "uint8[][2]"}]`,
-		enc:  "000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000800000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000001",
+		enc:  "0000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000800000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000001",
 		want: [2][]uint8{{1}, {1}},
 	},
 	{
@@ -815,3 +815,86 @@ func TestOOMMaliciousInput(t *testing.T) {
 		}
 	}
 }
+
+func TestUnpackTuple(t *testing.T) {
+	abi, err := JSON(strings.NewReader(tupleABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantNested := tupleNested{
+		Amount: big.NewInt(1),
+		Entries: []tupleEntry{
+			{Name: "foo", Values: []uint8{1, 2}},
+			{Name: "bar", Values: []uint8{}},
+		},
+		Flags: [2]bool{true, false},
+	}
+	data := common.Hex2Bytes(tupleNestedEncoding)
+
+	// Tuples unpack into structs with fields matched by name or abi tag
+	var out struct {
+		T tupleNested
+		B *big.Int
+	}
+	if err := abi.Unpack(&out, "nested", data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out.T, wantNested) || out.B.Cmp(big.NewInt(42)) != 0 {
+		t.Errorf("nested tuple unpack mismatch: have %+v, want %+v and 42", out, wantNested)
+	}
+
+	// Lists of pointers receive the tuples too
+	var (
+		nested = new(tupleNested)
+		b      = new(*big.Int)
+		list   = []interface{}{nested, b}
+	)
+	if err := abi.Unpack(&list, "nested", data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*nested, wantNested) {
+		t.Errorf("nested tuple list unpack mismatch: have %+v, want %+v", *nested, wantNested)
+	}
+
+	// A single tuple unpacks into the struct directly
+	single := append(common.LeftPadBytes([]byte{32}, 32), data[64:]...)
+	nested = new(tupleNested)
+	if err := abi.Methods["nested"].Outputs[:1].Unpack(nested, single); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*nested, wantNested) {
+		t.Errorf("single tuple unpack mismatch: have %+v, want %+v", *nested, wantNested)
+	}
+
+	// Without a target, tuples unpack into anonymous structs
+	values, err := abi.Methods["nested"].Outputs.UnpackValues(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := reflect.ValueOf(values[0]).FieldByName("Entries")
+	if entries.Len() != 2 || entries.Index(1).FieldByName("Label").String() != "bar" {
+		t.Errorf("anonymous tuple unpack mismatch: %+v", values[0])
+	}
+
+	// Static tuples are encoded in place, shifting the following arguments
+	var static struct {
+		Points [2]tuplePoint
+		C      *big.Int
+	}
+	if err := abi.Unpack(&static, "static", common.Hex2Bytes(tupleStaticEncoding)); err != nil {
+		t.Fatal(err)
+	}
+	wantPoints := [2]tuplePoint{{big.NewInt(1), true}, {big.NewInt(2), false}}
+	if !reflect.DeepEqual(static.Points, wantPoints) || static.C.Cmp(big.NewInt(3)) != 0 {
+		t.Errorf("static tuple unpack mismatch: have %+v, want %+v and 3", static, wantPoints)
+	}
+
+	// Structs with mismatching fields are rejected
+	var invalid struct {
+		Points [2]struct{ X string }
+		C      *big.Int
+	}
+	if err := abi.Unpack(&invalid, "static", common.Hex2Bytes(tupleStaticEncoding)); err == nil {
+		t.Errorf("unpacked tuple into mismatching struct")
+	}
+}
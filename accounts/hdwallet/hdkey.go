@@ -0,0 +1,140 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package hdwallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/rwdxchain/go-rwdxchaina/accounts"
+	"github.com/rwdxchain/go-rwdxchaina/common/math"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+)
+
+// masterKeySalt is the HMAC key used to derive the BIP-32 master key from a seed.
+var masterKeySalt = []byte("Bitcoin seed")
+
+// errInvalidChild is returned if a derived key is not a valid secp256k1 private
+// key, which happens with a probability lower than 1 in 2^127.
+var errInvalidChild = errors.New("invalid derived key, use another derivation index")
+
+// extendedKey is a BIP-32 extended private key: a private key together with the
+// chain code used to derive its children.
+type extendedKey struct {
+	key       *big.Int
+	chainCode []byte
+}
+
+// newMasterKey derives the BIP-32 master key of the given seed.
+func newMasterKey(seed []byte) (*extendedKey, error) {
+	mac := hmac.New(sha512.New, masterKeySalt)
+	mac.Write(seed)
+	return newExtendedKey(mac.Sum(nil), nil)
+}
+
+// newExtendedKey creates an extended key from the output of a derivation HMAC,
+// the left half of which is the key (or the tweak to add to the parent key), the
+// right half the chain code.
+func newExtendedKey(sum []byte, parent *big.Int) (*extendedKey, error) {
+	n := crypto.S256().Params().N
+
+	key := new(big.Int).SetBytes(sum[:32])
+	if key.Cmp(n) >= 0 {
+		return nil, errInvalidChild
+	}
+	if parent != nil {
+		key.Add(key, parent)
+		key.Mod(key, n)
+	}
+	if key.Sign() == 0 {
+		return nil, errInvalidChild
+	}
+	return &extendedKey{key: key, chainCode: sum[32:]}, nil
+}
+
+// privateKey returns the private key of the extended key.
+func (k *extendedKey) privateKey() (*ecdsa.PrivateKey, error) {
+	return crypto.ToECDSA(math.PaddedBigBytes(k.key, 32))
+}
+
+// child derives the child key with the given index, hardened if the index is at
+// least 2^31.
+func (k *extendedKey) child(index uint32) (*extendedKey, error) {
+	var data []byte
+	if index >= 0x80000000 {
+		data = append([]byte{0}, math.PaddedBigBytes(k.key, 32)...)
+	} else {
+		priv, err := k.privateKey()
+		if err != nil {
+			return nil, err
+		}
+		data = crypto.CompressPubkey(&priv.PublicKey)
+		zeroKey(priv)
+	}
+	data = append(data, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(data[len(data)-4:], index)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	mac.Write(data)
+	for i := range data {
+		data[i] = 0
+	}
+	return newExtendedKey(mac.Sum(nil), k.key)
+}
+
+// derive derives the private key at the given path below the extended key.
+func (k *extendedKey) derive(path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	key := k
+	for _, index := range path {
+		child, err := key.child(index)
+		if key != k {
+			key.zero()
+		}
+		if err != nil {
+			return nil, err
+		}
+		key = child
+	}
+	priv, err := key.privateKey()
+	if key != k {
+		key.zero()
+	}
+	return priv, err
+}
+
+// zero clears the extended key from memory.
+func (k *extendedKey) zero() {
+	b := k.key.Bits()
+	for i := range b {
+		b[i] = 0
+	}
+	for i := range k.chainCode {
+		k.chainCode[i] = 0
+	}
+}
+
+// zeroKey zeroes a private key in memory.
+func zeroKey(k *ecdsa.PrivateKey) {
+	b := k.D.Bits()
+	for i := range b {
+		b[i] = 0
+	}
+}
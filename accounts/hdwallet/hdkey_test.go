@@ -0,0 +1,89 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package hdwallet
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/rwdxchain/go-rwdxchaina/accounts"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+)
+
+// Tests key derivation against the first reference vector of the BIP-32
+// specification.
+func TestDerivationVectors(t *testing.T) {
+	master, err := newMasterKey(common.FromHex("000102030405060708090a0b0c0d0e0f"))
+	if err != nil {
+		t.Fatalf("failed to derive master key: %v", err)
+	}
+	tests := []struct {
+		path string
+		key  string
+	}{
+		{"m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{"m/0'/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{"m/0'/1/2'", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca"},
+		{"m/0'/1/2'/2", "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4"},
+		{"m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+	}
+	if have := fmt.Sprintf("%x", mustPrivateKey(t, master)); have != "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35" {
+		t.Errorf("master key mismatch: have %s", have)
+	}
+	for i, tt := range tests {
+		path, err := accounts.ParseDerivationPath(tt.path)
+		if err != nil {
+			t.Fatalf("test %d: invalid path: %v", i, err)
+		}
+		key, err := master.derive(path)
+		if err != nil {
+			t.Errorf("test %d: failed to derive key: %v", i, err)
+			continue
+		}
+		if have := fmt.Sprintf("%x", crypto.FromECDSA(key)); have != tt.key {
+			t.Errorf("test %d: key mismatch: have %s, want %s", i, have, tt.key)
+		}
+	}
+}
+
+// Tests that accounts derived from a mnemonic match the ones of other wallets
+// implementing BIP-39 and BIP-44.
+func TestMnemonicAccountDerivation(t *testing.T) {
+	seed := MnemonicToSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	master, err := newMasterKey(seed)
+	if err != nil {
+		t.Fatalf("failed to derive master key: %v", err)
+	}
+	path, _ := accounts.ParseDerivationPath("m/44'/60'/0'/0/0")
+	key, err := master.derive(path)
+	if err != nil {
+		t.Fatalf("failed to derive key: %v", err)
+	}
+	want := common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94")
+	if addr := crypto.PubkeyToAddress(key.PublicKey); addr != want {
+		t.Fatalf("address mismatch: have %x, want %x", addr, want)
+	}
+}
+
+func mustPrivateKey(t *testing.T, k *extendedKey) []byte {
+	key, err := k.privateKey()
+	if err != nil {
+		t.Fatalf("invalid private key: %v", err)
+	}
+	return crypto.FromECDSA(key)
+}
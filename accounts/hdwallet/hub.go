@@ -0,0 +1,265 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package hdwallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pborman/uuid"
+	"github.com/rwdxchain/go-rwdxchaina/accounts"
+	"github.com/rwdxchain/go-rwdxchaina/accounts/keystore"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/event"
+	"github.com/rwdxchain/go-rwdxchaina/log"
+)
+
+// Scheme is the protocol scheme prefixing the URLs of HD wallets and of the
+// accounts derived from them.
+const Scheme = "hd"
+
+// KeyStoreSubdir is the subdirectory of a keystore in which HD wallets are kept
+// by default, so that they are backed up together with the keys.
+const KeyStoreSubdir = "hd"
+
+// HubType is the reflect type of an HD wallet hub.
+var HubType = reflect.TypeOf(&Hub{})
+
+// walletVersion is the version of the wallet file format.
+const walletVersion = 1
+
+// walletJSON is the format of a wallet file. The BIP-39 seed is encrypted in the
+// same way keys of the keystore are, the accounts derived from it are tracked in
+// plain text, to allow listing them without decrypting the seed.
+type walletJSON struct {
+	Version  int                 `json:"version"`
+	Id       string              `json:"id"`
+	Crypto   keystore.CryptoJSON `json:"crypto"`
+	Accounts []accountJSON       `json:"accounts"`
+}
+
+// accountJSON is an account tracked in a wallet file.
+type accountJSON struct {
+	Address common.Address `json:"address"`
+	Path    string         `json:"path"`
+}
+
+// walletsByURL implements sort.Interface for []accounts.Wallet, sorting them by
+// their URL.
+type walletsByURL []accounts.Wallet
+
+func (s walletsByURL) Len() int           { return len(s) }
+func (s walletsByURL) Less(i, j int) bool { return s[i].URL().Cmp(s[j].URL()) < 0 }
+func (s walletsByURL) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// Hub is an accounts.Backend managing the HD wallets stored in a directory, one
+// file per wallet. Wallets are only added through the hub, the directory is not
+// watched for changes made by other processes.
+type Hub struct {
+	dir     string // Directory containing the wallet files
+	scryptN int    // Scrypt parameters used to encrypt the seeds of new wallets
+	scryptP int
+
+	wallets     []accounts.Wallet       // List of HD wallets, sorted by URL
+	updateFeed  event.Feed              // Event feed to notify wallet additions
+	updateScope event.SubscriptionScope // Subscription scope tracking current live listeners
+
+	stateLock sync.RWMutex // Protects the internals of the hub from racey access
+}
+
+// NewHub creates an HD wallet hub loading the wallet files in the given
+// directory. Seeds of newly created or imported wallets are encrypted with the
+// given scrypt parameters.
+func NewHub(dir string, scryptN, scryptP int) (*Hub, error) {
+	dir, _ = filepath.Abs(dir)
+	hub := &Hub{
+		dir:     dir,
+		scryptN: scryptN,
+		scryptP: scryptP,
+	}
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return hub, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, fi := range files {
+		// Skip editor backups, hidden files and anything not a regular file
+		if strings.HasSuffix(fi.Name(), "~") || strings.HasPrefix(fi.Name(), ".") || !fi.Mode().IsRegular() {
+			continue
+		}
+		path := filepath.Join(dir, fi.Name())
+		w, err := hub.load(path)
+		if err != nil {
+			log.Warn("Failed to load HD wallet", "path", path, "err", err)
+			continue
+		}
+		hub.wallets = append(hub.wallets, w)
+	}
+	sort.Sort(walletsByURL(hub.wallets))
+	return hub, nil
+}
+
+// load reads the wallet file at the given path.
+func (hub *Hub) load(path string) (*wallet, error) {
+	blob, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file walletJSON
+	if err := json.Unmarshal(blob, &file); err != nil {
+		return nil, err
+	}
+	if file.Version != walletVersion {
+		return nil, fmt.Errorf("unsupported HD wallet version %d", file.Version)
+	}
+	w := hub.newWallet(path, file.Crypto)
+	for _, acc := range file.Accounts {
+		derivationPath, err := accounts.ParseDerivationPath(acc.Path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid derivation path of account %x: %v", acc.Address, err)
+		}
+		w.accounts = append(w.accounts, w.account(acc.Address, derivationPath))
+		w.paths[acc.Address] = derivationPath
+	}
+	return w, nil
+}
+
+// newWallet creates a closed wallet stored at the given path.
+func (hub *Hub) newWallet(path string, seed keystore.CryptoJSON) *wallet {
+	url := accounts.URL{Scheme: Scheme, Path: path}
+	return &wallet{
+		hub:   hub,
+		url:   url,
+		seed:  seed,
+		paths: make(map[common.Address]accounts.DerivationPath),
+		log:   log.New("url", url),
+	}
+}
+
+// store writes the wallet file of a wallet.
+//
+// Note, store assumes the state lock of the wallet is held!
+func (hub *Hub) store(w *wallet) error {
+	file := walletJSON{
+		Version:  walletVersion,
+		Id:       strings.TrimSuffix(filepath.Base(w.url.Path), filepath.Ext(w.url.Path)),
+		Crypto:   w.seed,
+		Accounts: make([]accountJSON, len(w.accounts)),
+	}
+	for i, acc := range w.accounts {
+		file.Accounts[i] = accountJSON{Address: acc.Address, Path: w.paths[acc.Address].String()}
+	}
+	blob, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeWalletFile(w.url.Path, blob)
+}
+
+// Wallets implements accounts.Backend, returning all the HD wallets of the hub.
+func (hub *Hub) Wallets() []accounts.Wallet {
+	hub.stateLock.RLock()
+	defer hub.stateLock.RUnlock()
+
+	cpy := make([]accounts.Wallet, len(hub.wallets))
+	copy(cpy, hub.wallets)
+	return cpy
+}
+
+// Subscribe implements accounts.Backend, creating an async subscription to
+// receive notifications on the addition and the opening of HD wallets.
+func (hub *Hub) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
+	return hub.updateScope.Track(hub.updateFeed.Subscribe(sink))
+}
+
+// Import creates a new HD wallet from a BIP-39 mnemonic, protected by the given
+// optional mnemonic passphrase. The seed of the wallet is stored encrypted with
+// passphrase, the first account at the default base derivation path is tracked.
+func (hub *Hub) Import(mnemonic, mnemonicPassphrase, passphrase string) (accounts.Wallet, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	seed := MnemonicToSeed(mnemonic, mnemonicPassphrase)
+	defer func() {
+		for i := range seed {
+			seed[i] = 0
+		}
+	}()
+	master, err := newMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	defer master.zero()
+
+	crypto, err := keystore.EncryptDataV3(seed, []byte(passphrase), hub.scryptN, hub.scryptP)
+	if err != nil {
+		return nil, err
+	}
+	w := hub.newWallet(filepath.Join(hub.dir, uuid.NewRandom().String()+".json"), crypto)
+
+	path := make(accounts.DerivationPath, len(accounts.DefaultBaseDerivationPath))
+	copy(path, accounts.DefaultBaseDerivationPath)
+
+	address, err := w.derive(master, path)
+	if err != nil {
+		return nil, err
+	}
+	w.accounts = append(w.accounts, w.account(address, path))
+	w.paths[address] = path
+
+	if err := hub.store(w); err != nil {
+		return nil, err
+	}
+	hub.stateLock.Lock()
+	hub.wallets = append(hub.wallets, w)
+	sort.Sort(walletsByURL(hub.wallets))
+	hub.stateLock.Unlock()
+
+	hub.updateFeed.Send(accounts.WalletEvent{Wallet: w, Kind: accounts.WalletArrived})
+	return w, nil
+}
+
+// writeWalletFile atomically writes a wallet file readable only by its owner.
+func writeWalletFile(file string, content []byte) error {
+	// Create the wallet directory with appropriate permissions in case it is
+	// not present yet.
+	const dirPerm = 0700
+	if err := os.MkdirAll(filepath.Dir(file), dirPerm); err != nil {
+		return err
+	}
+	// Atomic write: create a temporary hidden file first then move it into place.
+	f, err := ioutil.TempFile(filepath.Dir(file), "."+filepath.Base(file)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	f.Close()
+	return os.Rename(f.Name(), file)
+}
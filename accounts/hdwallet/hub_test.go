@@ -0,0 +1,257 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package hdwallet
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/accounts"
+	"github.com/rwdxchain/go-rwdxchaina/accounts/keystore"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/core/types"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+)
+
+const (
+	veryLightScryptN = 2
+	veryLightScryptP = 1
+
+	testMnemonic = "legal winner thank year wave sausage worth useful legal winner thank yellow"
+)
+
+func tmpHub(t *testing.T) (string, *Hub) {
+	dir, err := ioutil.TempDir("", "rwdxchain-hdwallet-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hub, err := NewHub(dir, veryLightScryptN, veryLightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, hub
+}
+
+// testChainState is a chain state reader reporting a fixed set of used accounts.
+type testChainState struct {
+	nonces map[common.Address]uint64
+}
+
+func (c *testChainState) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return new(big.Int), nil
+}
+
+func (c *testChainState) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (c *testChainState) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (c *testChainState) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return c.nonces[account], nil
+}
+
+// Tests that imported wallets are persisted and can be used for signing once
+// opened, or with their passphrase.
+func TestHubImport(t *testing.T) {
+	dir, hub := tmpHub(t)
+	defer os.RemoveAll(dir)
+
+	events := make(chan accounts.WalletEvent, 4)
+	sub := hub.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	if _, err := hub.Import("legal winner thank year", "", "foo"); err != ErrInvalidMnemonic {
+		t.Fatalf("invalid mnemonic error mismatch: have %v, want %v", err, ErrInvalidMnemonic)
+	}
+	wallet, err := hub.Import(testMnemonic, "TREZOR", "foo")
+	if err != nil {
+		t.Fatalf("failed to import mnemonic: %v", err)
+	}
+	select {
+	case ev := <-events:
+		if ev.Kind != accounts.WalletArrived || ev.Wallet.URL() != wallet.URL() {
+			t.Fatalf("wallet event mismatch: have %v %v, want arrival of %v", ev.Kind, ev.Wallet.URL(), wallet.URL())
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no wallet arrival event")
+	}
+	if wallet.URL().Scheme != Scheme {
+		t.Fatalf("wallet scheme mismatch: have %s, want %s", wallet.URL().Scheme, Scheme)
+	}
+	// The first default account is tracked, even while the wallet is closed
+	accs := wallet.Accounts()
+	if len(accs) != 1 {
+		t.Fatalf("account count mismatch: have %d, want 1", len(accs))
+	}
+	master, _ := newMasterKey(MnemonicToSeed(testMnemonic, "TREZOR"))
+	key, _ := master.derive(accounts.DefaultBaseDerivationPath)
+	if accs[0].Address != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Fatalf("account mismatch: have %x, want %x", accs[0].Address, crypto.PubkeyToAddress(key.PublicKey))
+	}
+	// Signing needs the wallet opened or the passphrase
+	hash := crypto.Keccak256([]byte("hello"))
+	if _, err := wallet.SignHash(accs[0], hash); err != accounts.ErrWalletClosed {
+		t.Fatalf("closed signing error mismatch: have %v, want %v", err, accounts.ErrWalletClosed)
+	}
+	if _, err := wallet.SignHashWithPassphrase(accs[0], "bar", hash); err != keystore.ErrDecrypt {
+		t.Fatalf("invalid passphrase error mismatch: have %v, want %v", err, keystore.ErrDecrypt)
+	}
+	tx := types.NewTransaction(0, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	signed, err := wallet.SignTxWithPassphrase(accs[0], "foo", tx, big.NewInt(7))
+	if err != nil {
+		t.Fatalf("failed to sign with passphrase: %v", err)
+	}
+	if sender, err := types.Sender(types.NewEIP155Signer(big.NewInt(7)), signed); err != nil || sender != accs[0].Address {
+		t.Fatalf("transaction sender mismatch: have %x (%v), want %x", sender, err, accs[0].Address)
+	}
+	if err := wallet.Open("bar"); err != keystore.ErrDecrypt {
+		t.Fatalf("invalid open error mismatch: have %v, want %v", err, keystore.ErrDecrypt)
+	}
+	if err := wallet.Open("foo"); err != nil {
+		t.Fatalf("failed to open wallet: %v", err)
+	}
+	defer wallet.Close()
+
+	if err := wallet.Open("foo"); err != accounts.ErrWalletAlreadyOpen {
+		t.Fatalf("reopen error mismatch: have %v, want %v", err, accounts.ErrWalletAlreadyOpen)
+	}
+	sig, err := wallet.SignHash(accs[0], hash)
+	if err != nil {
+		t.Fatalf("failed to sign with open wallet: %v", err)
+	}
+	if want, _ := crypto.Sign(hash, key); !bytes.Equal(sig, want) {
+		t.Fatalf("signature mismatch")
+	}
+	if _, err := wallet.SignHash(accounts.Account{Address: common.Address{1}}, hash); err != accounts.ErrUnknownAccount {
+		t.Fatalf("unknown account error mismatch: have %v, want %v", err, accounts.ErrUnknownAccount)
+	}
+}
+
+// Tests that pinned accounts are persisted and restored when reloading the
+// wallets of a hub.
+func TestHubDerivePersistence(t *testing.T) {
+	dir, hub := tmpHub(t)
+	defer os.RemoveAll(dir)
+
+	wallet, err := hub.Import(testMnemonic, "", "foo")
+	if err != nil {
+		t.Fatalf("failed to import mnemonic: %v", err)
+	}
+	path, _ := accounts.ParseDerivationPath("m/44'/60'/0'/0/3")
+	if _, err := wallet.Derive(path, true); err != accounts.ErrWalletClosed {
+		t.Fatalf("closed derivation error mismatch: have %v, want %v", err, accounts.ErrWalletClosed)
+	}
+	if err := wallet.Open("foo"); err != nil {
+		t.Fatalf("failed to open wallet: %v", err)
+	}
+	unpinned, err := wallet.Derive(path, false)
+	if err != nil {
+		t.Fatalf("failed to derive account: %v", err)
+	}
+	if wallet.Contains(unpinned) {
+		t.Fatalf("unpinned account tracked")
+	}
+	pinned, err := wallet.Derive(path, true)
+	if err != nil {
+		t.Fatalf("failed to pin account: %v", err)
+	}
+	if pinned != unpinned || !wallet.Contains(pinned) {
+		t.Fatalf("pinned account mismatch: have %v, want %v", pinned, unpinned)
+	}
+	wallet.Close()
+
+	// Reload the hub and ensure the wallet and its accounts are restored
+	reloaded, err := NewHub(dir, veryLightScryptN, veryLightScryptP)
+	if err != nil {
+		t.Fatalf("failed to reload hub: %v", err)
+	}
+	wallets := reloaded.Wallets()
+	if len(wallets) != 1 || wallets[0].URL() != wallet.URL() {
+		t.Fatalf("reloaded wallets mismatch: have %v, want [%v]", wallets, wallet.URL())
+	}
+	if accs := wallets[0].Accounts(); len(accs) != 2 || accs[1] != pinned {
+		t.Fatalf("reloaded accounts mismatch: have %v, want [... %v]", accs, pinned)
+	}
+	if _, err := wallets[0].SignHashWithPassphrase(pinned, "foo", crypto.Keccak256(nil)); err != nil {
+		t.Fatalf("failed to sign with reloaded wallet: %v", err)
+	}
+}
+
+// Tests that opened wallets discover the accounts used on chain.
+func TestHubSelfDerive(t *testing.T) {
+	dir, hub := tmpHub(t)
+	defer os.RemoveAll(dir)
+
+	wallet, err := hub.Import(testMnemonic, "", "foo")
+	if err != nil {
+		t.Fatalf("failed to import mnemonic: %v", err)
+	}
+	// Mark the first three accounts as used on chain
+	master, _ := newMasterKey(MnemonicToSeed(testMnemonic, ""))
+	chain := &testChainState{nonces: make(map[common.Address]uint64)}
+
+	var want []common.Address
+	for i := uint32(0); i < 4; i++ {
+		path := append(accounts.DerivationPath{}, accounts.DefaultBaseDerivationPath...)
+		path[len(path)-1] = i
+		key, _ := master.derive(path)
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		if i < 3 {
+			chain.nonces[addr] = 1
+		}
+		want = append(want, addr)
+	}
+	if err := wallet.Open("foo"); err != nil {
+		t.Fatalf("failed to open wallet: %v", err)
+	}
+	defer wallet.Close()
+
+	wallet.SelfDerive(accounts.DefaultBaseDerivationPath, chain)
+
+	// The used accounts and the first unused one should be discovered. Derivation
+	// is only attempted if the self-deriver is idle, so retry for a while
+	var accs []accounts.Account
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		if accs = wallet.Accounts(); len(accs) == len(want) {
+			break
+		}
+	}
+	if len(accs) != len(want) {
+		t.Fatalf("account count mismatch: have %d, want %d", len(accs), len(want))
+	}
+	for i, acc := range accs {
+		if acc.Address != want[i] {
+			t.Errorf("account %d: address mismatch: have %x, want %x", i, acc.Address, want[i])
+		}
+	}
+	// Discovered accounts should be persisted
+	reloaded, err := NewHub(dir, veryLightScryptN, veryLightScryptP)
+	if err != nil {
+		t.Fatalf("failed to reload hub: %v", err)
+	}
+	if accs := reloaded.Wallets()[0].Accounts(); len(accs) != len(want) {
+		t.Fatalf("reloaded account count mismatch: have %d, want %d", len(accs), len(want))
+	}
+}
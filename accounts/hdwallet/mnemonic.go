@@ -0,0 +1,145 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package hdwallet
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// BIP-39 parameters of the seed derivation from a mnemonic.
+const (
+	seedIterations = 2048 // Number of PBKDF2 rounds
+	seedLength     = 64   // Length of the derived seed in bytes
+)
+
+var (
+	// ErrInvalidEntropy is returned if the entropy to encode into a mnemonic is
+	// not between 128 and 256 bits long or not a multiple of 32 bits.
+	ErrInvalidEntropy = errors.New("entropy must be 128 to 256 bits long, a multiple of 32")
+
+	// ErrInvalidMnemonic is returned if a mnemonic has the wrong number of words
+	// or contains words not in the BIP-39 word list.
+	ErrInvalidMnemonic = errors.New("invalid mnemonic")
+
+	// ErrMnemonicChecksum is returned if the checksum embedded in the last word of
+	// a mnemonic doesn't match its entropy, usually caused by a mistyped word.
+	ErrMnemonicChecksum = errors.New("invalid mnemonic checksum")
+)
+
+// wordIndex maps each word of the word list to its position.
+var wordIndex = make(map[string]int, len(wordlist))
+
+func init() {
+	for i, word := range wordlist {
+		wordIndex[word] = i
+	}
+}
+
+// NewMnemonic generates a random mnemonic encoding the given number of bits of
+// entropy, which must be between 128 and 256, a multiple of 32. A mnemonic of
+// 128 bits is 12 words long, one of 256 bits 24 words.
+func NewMnemonic(bits int) (string, error) {
+	if bits < 128 || bits > 256 || bits%32 != 0 {
+		return "", ErrInvalidEntropy
+	}
+	entropy := make([]byte, bits/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", err
+	}
+	return EntropyToMnemonic(entropy)
+}
+
+// EntropyToMnemonic encodes the given entropy into a mnemonic according to
+// BIP-39: the entropy is extended by a checksum of a bit per 32 bits of entropy,
+// the result of which is split into groups of 11 bits, each indexing a word.
+func EntropyToMnemonic(entropy []byte) (string, error) {
+	bits := len(entropy) * 8
+	if bits < 128 || bits > 256 || bits%32 != 0 {
+		return "", ErrInvalidEntropy
+	}
+	checksum := sha256.Sum256(entropy)
+	data := append(append([]byte{}, entropy...), checksum[0])
+
+	words := make([]string, (bits+bits/32)/11)
+	for i := range words {
+		index := 0
+		for j := 0; j < 11; j++ {
+			bit := i*11 + j
+			index = index<<1 | int(data[bit/8]>>(7-uint(bit%8))&1)
+		}
+		words[i] = wordlist[index]
+	}
+	return strings.Join(words, " "), nil
+}
+
+// MnemonicToEntropy decodes a mnemonic into the entropy it encodes, verifying
+// its checksum.
+func MnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(norm.NFKD.String(mnemonic))
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, ErrInvalidMnemonic
+	}
+	// Concatenate the 11 bit indices of the words
+	data := make([]byte, (len(words)*11+7)/8)
+	for i, word := range words {
+		index, ok := wordIndex[word]
+		if !ok {
+			return nil, fmt.Errorf("%v: unknown word %q", ErrInvalidMnemonic, word)
+		}
+		for j := 0; j < 11; j++ {
+			if index&(1<<uint(10-j)) != 0 {
+				bit := i*11 + j
+				data[bit/8] |= 1 << (7 - uint(bit%8))
+			}
+		}
+	}
+	// Split off the checksum and verify it against the entropy
+	var (
+		checksumBits = len(words) / 3
+		entropy      = data[:(len(words)*11-checksumBits)/8]
+		checksum     = sha256.Sum256(entropy)
+	)
+	if data[len(entropy)]>>(8-uint(checksumBits)) != checksum[0]>>(8-uint(checksumBits)) {
+		return nil, ErrMnemonicChecksum
+	}
+	return entropy, nil
+}
+
+// ValidateMnemonic checks whether a mnemonic consists of words of the BIP-39
+// word list and has a valid checksum.
+func ValidateMnemonic(mnemonic string) error {
+	_, err := MnemonicToEntropy(mnemonic)
+	return err
+}
+
+// MnemonicToSeed derives the BIP-39 seed of a mnemonic, protected by an optional
+// passphrase. The mnemonic is not validated, use ValidateMnemonic for that.
+func MnemonicToSeed(mnemonic, passphrase string) []byte {
+	var (
+		password = []byte(strings.Join(strings.Fields(norm.NFKD.String(mnemonic)), " "))
+		salt     = []byte("mnemonic" + norm.NFKD.String(passphrase))
+	)
+	return pbkdf2.Key(password, salt, seedIterations, seedLength, sha512.New)
+}
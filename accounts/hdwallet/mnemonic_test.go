@@ -0,0 +1,147 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package hdwallet
+
+import (
+	"bytes"
+	"hash/crc32"
+	"strings"
+	"testing"
+
+	"github.com/rwdxchain/go-rwdxchaina/common"
+)
+
+// Tests that the word list is the one of the BIP-39 specification.
+func TestWordlist(t *testing.T) {
+	if len(wordlist) != 2048 {
+		t.Fatalf("word count mismatch: have %d, want 2048", len(wordlist))
+	}
+	if sum := crc32.ChecksumIEEE([]byte(englishWords)); sum != 0xc1dbd296 {
+		t.Fatalf("word list checksum mismatch: have %x, want c1dbd296", sum)
+	}
+}
+
+// Tests mnemonic encoding and seed derivation against the reference vectors of
+// the BIP-39 specification, all of them using the passphrase "TREZOR".
+func TestMnemonicVectors(t *testing.T) {
+	tests := []struct {
+		entropy  string
+		mnemonic string
+		seed     string
+	}{
+		{
+			"00000000000000000000000000000000",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			"legal winner thank year wave sausage worth useful legal winner thank yellow",
+			"2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+		},
+		{
+			"ffffffffffffffffffffffffffffffff",
+			"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+			"ac27495480225222079d7be181583751e86f571027b0497b5b5d11218e0a8a13332572917f0f8e5a589620c6f15b11c61dee327651a14c34e18231052e48c069",
+		},
+		{
+			"808080808080808080808080808080808080808080808080",
+			"letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic avoid letter always",
+			"107d7c02a5aa6f38c58083ff74f04c607c2d2c0ecc55501dadd72d025b751bc27fe913ffb796f841c49b1d33b610cf0e91d3aa239027f5e99fe4ce9e5088cd65",
+		},
+		{
+			"b63a9c59a6e641f288ebc103017f1da9f8290b3da6bdef7b",
+			"renew stay biology evidence goat welcome casual join adapt armor shuffle fault little machine walk stumble urge swap",
+			"9248d83e06f4cd98debf5b6f010542760df925ce46cf38a1bdb4e4de7d21f5c39366941c69e1bdbf2966e0f6e6dbece898a0e2f0a4c2b3e640953dfe8b7bbdc5",
+		},
+		{
+			"0000000000000000000000000000000000000000000000000000000000000000",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+			"bda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8",
+		},
+		{
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+			"dd48c104698c30cfe2b6142103248622fb7bb0ff692eebb00089b32d22484e1613912f0a5b694407be899ffd31ed3992c456cdf60f5d4564b8ba3f05a69890ad",
+		},
+	}
+	for i, tt := range tests {
+		mnemonic, err := EntropyToMnemonic(common.FromHex(tt.entropy))
+		if err != nil {
+			t.Errorf("test %d: failed to encode entropy: %v", i, err)
+			continue
+		}
+		if mnemonic != tt.mnemonic {
+			t.Errorf("test %d: mnemonic mismatch: have %q, want %q", i, mnemonic, tt.mnemonic)
+		}
+		entropy, err := MnemonicToEntropy(tt.mnemonic)
+		if err != nil {
+			t.Errorf("test %d: failed to decode mnemonic: %v", i, err)
+		} else if !bytes.Equal(entropy, common.FromHex(tt.entropy)) {
+			t.Errorf("test %d: entropy mismatch: have %x, want %s", i, entropy, tt.entropy)
+		}
+		if seed := MnemonicToSeed(tt.mnemonic, "TREZOR"); !bytes.Equal(seed, common.FromHex(tt.seed)) {
+			t.Errorf("test %d: seed mismatch: have %x, want %s", i, seed, tt.seed)
+		}
+	}
+}
+
+// Tests that malformed mnemonics are rejected.
+func TestInvalidMnemonics(t *testing.T) {
+	tests := []struct {
+		mnemonic string
+		err      error
+	}{
+		{"", ErrInvalidMnemonic},
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", ErrInvalidMnemonic},
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about about", ErrInvalidMnemonic},
+		{"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon", ErrMnemonicChecksum},
+		{"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo", ErrMnemonicChecksum},
+		{"legal winner thank year wave sausage worth useful legal winner thank yellow", nil},
+		{"  legal winner thank\tyear wave sausage worth useful legal winner thank yellow\n", nil},
+	}
+	for i, tt := range tests {
+		if err := ValidateMnemonic(tt.mnemonic); err != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	err := ValidateMnemonic("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandonn")
+	if err == nil || !strings.Contains(err.Error(), "abandonn") {
+		t.Errorf("unknown word not reported: %v", err)
+	}
+}
+
+// Tests that generated mnemonics have the requested strength and are valid.
+func TestNewMnemonic(t *testing.T) {
+	for bits, words := range map[int]int{128: 12, 160: 15, 192: 18, 224: 21, 256: 24} {
+		mnemonic, err := NewMnemonic(bits)
+		if err != nil {
+			t.Fatalf("%d bits: failed to generate mnemonic: %v", bits, err)
+		}
+		if n := len(strings.Fields(mnemonic)); n != words {
+			t.Errorf("%d bits: word count mismatch: have %d, want %d", bits, n, words)
+		}
+		if err := ValidateMnemonic(mnemonic); err != nil {
+			t.Errorf("%d bits: generated mnemonic invalid: %v", bits, err)
+		}
+	}
+	for _, bits := range []int{0, 96, 136, 288} {
+		if _, err := NewMnemonic(bits); err != ErrInvalidEntropy {
+			t.Errorf("%d bits: error mismatch: have %v, want %v", bits, err, ErrInvalidEntropy)
+		}
+	}
+}
@@ -18,15 +18,16 @@
 //
 // Keys are stored as encrypted JSON files according to the Web3 Secret Storage specification.
 // See https://github.com/ethereum/wiki/wiki/Web3-Secret-Storage-Definition for more information.
+//
+// Besides a directory of key files, the keys can be kept in a single encrypted database
+// file, or by a remote signing service which never discloses them.
 package keystore
 
 import (
 	"crypto/ecdsa"
-	crand "crypto/rand"
 	"errors"
 	"fmt"
 	"math/big"
-	"os"
 	"path/filepath"
 	"reflect"
 	"runtime"
@@ -36,7 +37,6 @@ import (
 	"github.com/rwdxchain/go-rwdxchaina/accounts"
 	"github.com/rwdxchain/go-rwdxchaina/common"
 	"github.com/rwdxchain/go-rwdxchaina/core/types"
-	"github.com/rwdxchain/go-rwdxchaina/crypto"
 	"github.com/rwdxchain/go-rwdxchaina/event"
 )
 
@@ -55,12 +55,12 @@ const KeyStoreScheme = "keystore"
 // Maximum time between wallet refreshes (if filesystem notifications don't work).
 const walletRefreshCycle = 3 * time.Second
 
-// KeyStore manages a key storage directory on disk.
+// KeyStore manages a key storage directory on disk, or one of the alternative
+// key storage backends.
 type KeyStore struct {
-	storage  keyStore                     // Storage backend, might be cleartext or encrypted
-	cache    *accountCache                // In-memory account cache over the filesystem storage
-	changes  chan struct{}                // Channel receiving change notifications from the cache
-	unlocked map[common.Address]*unlocked // Currently unlocked account (decrypted private keys)
+	backend  keyBackend                   // Storage backend holding the keys, might be local or remote
+	changes  chan struct{}                // Channel receiving change notifications from the backend
+	unlocked map[common.Address]*unlocked // Currently unlocked account (decrypted private keys or credentials)
 
 	wallets     []accounts.Wallet       // Wallet wrappers around the individual key files
 	updateFeed  event.Feed              // Event feed to notify wallet additions/removals
@@ -71,15 +71,15 @@ type KeyStore struct {
 }
 
 type unlocked struct {
-	*Key
+	keySigner
 	abort chan struct{}
 }
 
 // NewKeyStore creates a keystore for the given directory.
 func NewKeyStore(keydir string, scryptN, scryptP int) *KeyStore {
 	keydir, _ = filepath.Abs(keydir)
-	ks := &KeyStore{storage: &keyStorePassphrase{keydir, scryptN, scryptP}}
-	ks.init(keydir)
+	ks := new(KeyStore)
+	ks.init(newFileBackend(&keyStorePassphrase{keydir, scryptN, scryptP}, keydir))
 	return ks
 }
 
@@ -87,28 +87,28 @@ func NewKeyStore(keydir string, scryptN, scryptP int) *KeyStore {
 // Deprecated: Use NewKeyStore.
 func NewPlaintextKeyStore(keydir string) *KeyStore {
 	keydir, _ = filepath.Abs(keydir)
-	ks := &KeyStore{storage: &keyStorePlain{keydir}}
-	ks.init(keydir)
+	ks := new(KeyStore)
+	ks.init(newFileBackend(&keyStorePlain{keydir}, keydir))
 	return ks
 }
 
-func (ks *KeyStore) init(keydir string) {
+func (ks *KeyStore) init(backend keyBackend, changes chan struct{}) {
 	// Lock the mutex since the account cache might call back with events
 	ks.mu.Lock()
 	defer ks.mu.Unlock()
 
-	// Initialize the set of unlocked keys and the account cache
+	// Initialize the set of unlocked keys and the storage backend
 	ks.unlocked = make(map[common.Address]*unlocked)
-	ks.cache, ks.changes = newAccountCache(keydir)
+	ks.backend, ks.changes = backend, changes
 
 	// TODO: In order for this finalizer to work, there must be no references
 	// to ks. addressCache doesn't keep a reference but unlocked keys do,
 	// so the finalizer will not trigger until all timed unlocks have expired.
 	runtime.SetFinalizer(ks, func(m *KeyStore) {
-		m.cache.close()
+		m.backend.close()
 	})
-	// Create the initial list of wallets from the cache
-	accs := ks.cache.accounts()
+	// Create the initial list of wallets from the backend
+	accs := ks.backend.accounts()
 	ks.wallets = make([]accounts.Wallet, len(accs))
 	for i := 0; i < len(accs); i++ {
 		ks.wallets[i] = &keystoreWallet{account: accs[i], keystore: ks}
@@ -132,9 +132,10 @@ func (ks *KeyStore) Wallets() []accounts.Wallet {
 // refreshWallets retrieves the current account list and based on that does any
 // necessary wallet refreshes.
 func (ks *KeyStore) refreshWallets() {
-	// Retrieve the current list of accounts
+	// Retrieve the current list of accounts, outside the lock as remote
+	// backends need a round trip to the signing service
+	accs := ks.backend.accounts()
 	ks.mu.Lock()
-	accs := ks.cache.accounts()
 
 	// Transform the current list of wallets into the new one
 	wallets := make([]accounts.Wallet, 0, len(accs))
@@ -220,36 +221,22 @@ func (ks *KeyStore) updater() {
 
 // HasAddress reports whether a key with the given address is present.
 func (ks *KeyStore) HasAddress(addr common.Address) bool {
-	return ks.cache.hasAddress(addr)
+	return ks.backend.hasAddress(addr)
 }
 
 // Accounts returns all key files present in the directory.
 func (ks *KeyStore) Accounts() []accounts.Account {
-	return ks.cache.accounts()
+	return ks.backend.accounts()
 }
 
 // Delete deletes the key matched by account if the passphrase is correct.
 // If the account contains no filename, the address must match a unique key.
 func (ks *KeyStore) Delete(a accounts.Account, passphrase string) error {
-	// Decrypting the key isn't really necessary, but we do
-	// it anyway to check the password and zero out the key
-	// immediately afterwards.
-	a, key, err := ks.getDecryptedKey(a, passphrase)
-	if key != nil {
-		zeroKey(key.PrivateKey)
-	}
-	if err != nil {
+	if err := ks.backend.delete(a, passphrase); err != nil {
 		return err
 	}
-	// The order is crucial here. The key is dropped from the
-	// cache after the file is gone so that a reload happening in
-	// between won't insert it into the cache again.
-	err = os.Remove(a.URL.Path)
-	if err == nil {
-		ks.cache.delete(a)
-		ks.refreshWallets()
-	}
-	return err
+	ks.refreshWallets()
+	return nil
 }
 
 // SignHash calculates a ECDSA signature for the given hash. The produced
@@ -264,7 +251,7 @@ func (ks *KeyStore) SignHash(a accounts.Account, hash []byte) ([]byte, error) {
 		return nil, ErrLocked
 	}
 	// Sign the hash using plain ECDSA operations
-	return crypto.Sign(hash, unlockedKey.PrivateKey)
+	return unlockedKey.signHash(hash)
 }
 
 // SignTx signs the given transaction with the requested account.
@@ -277,39 +264,30 @@ func (ks *KeyStore) SignTx(a accounts.Account, tx *types.Transaction, chainID *b
 	if !found {
 		return nil, ErrLocked
 	}
-	// Depending on the presence of the chain ID, sign with EIP155 or homestead
-	if chainID != nil {
-		return types.SignTx(tx, types.NewEIP155Signer(chainID), unlockedKey.PrivateKey)
-	}
-	return types.SignTx(tx, types.HomesteadSigner{}, unlockedKey.PrivateKey)
+	return signTx(unlockedKey, tx, chainID)
 }
 
 // SignHashWithPassphrase signs hash if the private key matching the given address
 // can be decrypted with the given passphrase. The produced signature is in the
 // [R || S || V] format where V is 0 or 1.
 func (ks *KeyStore) SignHashWithPassphrase(a accounts.Account, passphrase string, hash []byte) (signature []byte, err error) {
-	_, key, err := ks.getDecryptedKey(a, passphrase)
+	signer, err := ks.backend.unlock(a, passphrase)
 	if err != nil {
 		return nil, err
 	}
-	defer zeroKey(key.PrivateKey)
-	return crypto.Sign(hash, key.PrivateKey)
+	defer signer.zero()
+	return signer.signHash(hash)
 }
 
 // SignTxWithPassphrase signs the transaction if the private key matching the
 // given address can be decrypted with the given passphrase.
 func (ks *KeyStore) SignTxWithPassphrase(a accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
-	_, key, err := ks.getDecryptedKey(a, passphrase)
+	signer, err := ks.backend.unlock(a, passphrase)
 	if err != nil {
 		return nil, err
 	}
-	defer zeroKey(key.PrivateKey)
-
-	// Depending on the presence of the chain ID, sign with EIP155 or homestead
-	if chainID != nil {
-		return types.SignTx(tx, types.NewEIP155Signer(chainID), key.PrivateKey)
-	}
-	return types.SignTx(tx, types.HomesteadSigner{}, key.PrivateKey)
+	defer signer.zero()
+	return signTx(signer, tx, chainID)
 }
 
 // Unlock unlocks the given account indefinitely.
@@ -337,7 +315,11 @@ func (ks *KeyStore) Lock(addr common.Address) error {
 // shortens the active unlock timeout. If the address was previously unlocked
 // indefinitely the timeout is not altered.
 func (ks *KeyStore) TimedUnlock(a accounts.Account, passphrase string, timeout time.Duration) error {
-	a, key, err := ks.getDecryptedKey(a, passphrase)
+	a, err := ks.Find(a)
+	if err != nil {
+		return err
+	}
+	signer, err := ks.backend.unlock(a, passphrase)
 	if err != nil {
 		return err
 	}
@@ -349,17 +331,17 @@ func (ks *KeyStore) TimedUnlock(a accounts.Account, passphrase string, timeout t
 		if u.abort == nil {
 			// The address was unlocked indefinitely, so unlocking
 			// it with a timeout would be confusing.
-			zeroKey(key.PrivateKey)
+			signer.zero()
 			return nil
 		}
 		// Terminate the expire goroutine and replace it below.
 		close(u.abort)
 	}
 	if timeout > 0 {
-		u = &unlocked{Key: key, abort: make(chan struct{})}
+		u = &unlocked{keySigner: signer, abort: make(chan struct{})}
 		go ks.expire(a.Address, u, timeout)
 	} else {
-		u = &unlocked{Key: key}
+		u = &unlocked{keySigner: signer}
 	}
 	ks.unlocked[a.Address] = u
 	return nil
@@ -367,20 +349,7 @@ func (ks *KeyStore) TimedUnlock(a accounts.Account, passphrase string, timeout t
 
 // Find resolves the given account into a unique entry in the keystore.
 func (ks *KeyStore) Find(a accounts.Account) (accounts.Account, error) {
-	ks.cache.maybeReload()
-	ks.cache.mu.Lock()
-	a, err := ks.cache.find(a)
-	ks.cache.mu.Unlock()
-	return a, err
-}
-
-func (ks *KeyStore) getDecryptedKey(a accounts.Account, auth string) (accounts.Account, *Key, error) {
-	a, err := ks.Find(a)
-	if err != nil {
-		return a, nil, err
-	}
-	key, err := ks.storage.GetKey(a.Address, a.URL.Path, auth)
-	return a, key, err
+	return ks.backend.find(a)
 }
 
 func (ks *KeyStore) expire(addr common.Address, u *unlocked, timeout time.Duration) {
@@ -396,7 +365,7 @@ func (ks *KeyStore) expire(addr common.Address, u *unlocked, timeout time.Durati
 		// because the map stores a new pointer every time the key is
 		// unlocked.
 		if ks.unlocked[addr] == u {
-			zeroKey(u.PrivateKey)
+			u.zero()
 			delete(ks.unlocked, addr)
 		}
 		ks.mu.Unlock()
@@ -406,30 +375,17 @@ func (ks *KeyStore) expire(addr common.Address, u *unlocked, timeout time.Durati
 // NewAccount generates a new key and stores it into the key directory,
 // encrypting it with the passphrase.
 func (ks *KeyStore) NewAccount(passphrase string) (accounts.Account, error) {
-	_, account, err := storeNewKey(ks.storage, crand.Reader, passphrase)
+	account, err := ks.backend.newAccount(passphrase)
 	if err != nil {
 		return accounts.Account{}, err
 	}
-	// Add the account to the cache immediately rather
-	// than waiting for file system notifications to pick it up.
-	ks.cache.add(account)
 	ks.refreshWallets()
 	return account, nil
 }
 
 // Export exports as a JSON key, encrypted with newPassphrase.
 func (ks *KeyStore) Export(a accounts.Account, passphrase, newPassphrase string) (keyJSON []byte, err error) {
-	_, key, err := ks.getDecryptedKey(a, passphrase)
-	if err != nil {
-		return nil, err
-	}
-	var N, P int
-	if store, ok := ks.storage.(*keyStorePassphrase); ok {
-		N, P = store.scryptN, store.scryptP
-	} else {
-		N, P = StandardScryptN, StandardScryptP
-	}
-	return EncryptKey(key, newPassphrase, N, P)
+	return ks.backend.exportKey(a, passphrase, newPassphrase)
 }
 
 // Import stores the given encrypted JSON key into the key directory.
@@ -447,39 +403,36 @@ func (ks *KeyStore) Import(keyJSON []byte, passphrase, newPassphrase string) (ac
 // ImportECDSA stores the given key into the key directory, encrypting it with the passphrase.
 func (ks *KeyStore) ImportECDSA(priv *ecdsa.PrivateKey, passphrase string) (accounts.Account, error) {
 	key := newKeyFromECDSA(priv)
-	if ks.cache.hasAddress(key.Address) {
+	if ks.backend.hasAddress(key.Address) {
 		return accounts.Account{}, fmt.Errorf("account already exists")
 	}
 	return ks.importKey(key, passphrase)
 }
 
 func (ks *KeyStore) importKey(key *Key, passphrase string) (accounts.Account, error) {
-	a := accounts.Account{Address: key.Address, URL: accounts.URL{Scheme: KeyStoreScheme, Path: ks.storage.JoinPath(keyFileName(key.Address))}}
-	if err := ks.storage.StoreKey(a.URL.Path, key, passphrase); err != nil {
+	a, err := ks.backend.importKey(key, passphrase)
+	if err != nil {
 		return accounts.Account{}, err
 	}
-	ks.cache.add(a)
 	ks.refreshWallets()
 	return a, nil
 }
 
 // Update changes the passphrase of an existing account.
 func (ks *KeyStore) Update(a accounts.Account, passphrase, newPassphrase string) error {
-	a, key, err := ks.getDecryptedKey(a, passphrase)
-	if err != nil {
-		return err
-	}
-	return ks.storage.StoreKey(a.URL.Path, key, newPassphrase)
+	return ks.backend.update(a, passphrase, newPassphrase)
 }
 
 // ImportPreSaleKey decrypts the given Ethereum presale wallet and stores
 // a key file in the key directory. The key file is encrypted with the same passphrase.
 func (ks *KeyStore) ImportPreSaleKey(keyJSON []byte, passphrase string) (accounts.Account, error) {
-	a, _, err := importPreSaleKey(ks.storage, keyJSON, passphrase)
+	a, key, err := importPreSaleKey(ks.backend, keyJSON, passphrase)
+	if key != nil {
+		zeroKey(key.PrivateKey)
+	}
 	if err != nil {
 		return a, err
 	}
-	ks.cache.add(a)
 	ks.refreshWallets()
 	return a, nil
 }
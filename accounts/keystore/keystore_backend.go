@@ -0,0 +1,233 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	crand "crypto/rand"
+	"math/big"
+	"os"
+
+	"github.com/rwdxchain/go-rwdxchaina/accounts"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/core/types"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+)
+
+// keyBackend is the storage backend of a KeyStore, holding the keys of its
+// accounts. Depending on the backend, the keys might be loaded and decrypted
+// locally, or never leave the backend at all.
+type keyBackend interface {
+	// accounts returns all the accounts held by the backend, sorted by URL.
+	accounts() []accounts.Account
+	// hasAddress reports whether a key with the given address is present.
+	hasAddress(addr common.Address) bool
+	// find resolves the given account into a unique account of the backend.
+	find(a accounts.Account) (accounts.Account, error)
+
+	// newAccount generates a new key, protecting it with the passphrase.
+	newAccount(auth string) (accounts.Account, error)
+	// importKey stores the given key, protecting it with the passphrase.
+	importKey(key *Key, auth string) (accounts.Account, error)
+	// exportKey returns the key of an account encrypted with a new passphrase.
+	exportKey(a accounts.Account, auth, newAuth string) ([]byte, error)
+	// update changes the passphrase protecting the key of an account.
+	update(a accounts.Account, auth, newAuth string) error
+	// delete removes the key of an account if the passphrase is correct.
+	delete(a accounts.Account, auth string) error
+
+	// unlock verifies the passphrase of an account, returning a signer to sign
+	// with its key until the signer is zeroed.
+	unlock(a accounts.Account, auth string) (keySigner, error)
+
+	// close releases the resources held by the backend.
+	close()
+}
+
+// keySigner signs hashes with the key of an unlocked account.
+type keySigner interface {
+	// signHash calculates an ECDSA signature for the given hash, in the
+	// [R || S || V] format where V is 0 or 1.
+	signHash(hash []byte) ([]byte, error)
+	// zero wipes the key material or credentials held by the signer.
+	zero()
+}
+
+// signTx signs the given transaction with the signer, with EIP155 or homestead
+// rules depending on the presence of the chain ID.
+func signTx(s keySigner, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	var signer types.Signer = types.HomesteadSigner{}
+	if chainID != nil {
+		signer = types.NewEIP155Signer(chainID)
+	}
+	sig, err := s.signHash(signer.Hash(tx).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(signer, sig)
+}
+
+// localSigner is a keySigner holding a decrypted private key in memory.
+type localSigner struct {
+	key *Key
+}
+
+func (s *localSigner) signHash(hash []byte) ([]byte, error) {
+	return crypto.Sign(hash, s.key.PrivateKey)
+}
+
+func (s *localSigner) zero() {
+	zeroKey(s.key.PrivateKey)
+}
+
+// fileBackend is a keyBackend storing every key in a separate file within a key
+// directory, which is watched for changes made by other processes.
+type fileBackend struct {
+	storage keyStore      // Key file format, might be cleartext or encrypted
+	cache   *accountCache // In-memory account cache over the filesystem storage
+}
+
+// newFileBackend creates a key file backend for the given directory, returning
+// it along with the channel notifying changes of the directory contents.
+func newFileBackend(storage keyStore, keydir string) (*fileBackend, chan struct{}) {
+	cache, changes := newAccountCache(keydir)
+	return &fileBackend{storage: storage, cache: cache}, changes
+}
+
+func (b *fileBackend) accounts() []accounts.Account {
+	return b.cache.accounts()
+}
+
+func (b *fileBackend) hasAddress(addr common.Address) bool {
+	return b.cache.hasAddress(addr)
+}
+
+func (b *fileBackend) find(a accounts.Account) (accounts.Account, error) {
+	b.cache.maybeReload()
+	b.cache.mu.Lock()
+	defer b.cache.mu.Unlock()
+
+	return b.cache.find(a)
+}
+
+// getDecryptedKey resolves the given account and loads its decrypted key.
+func (b *fileBackend) getDecryptedKey(a accounts.Account, auth string) (accounts.Account, *Key, error) {
+	a, err := b.find(a)
+	if err != nil {
+		return a, nil, err
+	}
+	key, err := b.storage.GetKey(a.Address, a.URL.Path, auth)
+	return a, key, err
+}
+
+func (b *fileBackend) newAccount(auth string) (accounts.Account, error) {
+	key, account, err := storeNewKey(b.storage, crand.Reader, auth)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	zeroKey(key.PrivateKey)
+
+	// Add the account to the cache immediately rather
+	// than waiting for file system notifications to pick it up.
+	b.cache.add(account)
+	return account, nil
+}
+
+func (b *fileBackend) importKey(key *Key, auth string) (accounts.Account, error) {
+	a := accounts.Account{Address: key.Address, URL: accounts.URL{Scheme: KeyStoreScheme, Path: b.storage.JoinPath(keyFileName(key.Address))}}
+	if err := b.storage.StoreKey(a.URL.Path, key, auth); err != nil {
+		return accounts.Account{}, err
+	}
+	b.cache.add(a)
+	return a, nil
+}
+
+func (b *fileBackend) exportKey(a accounts.Account, auth, newAuth string) ([]byte, error) {
+	_, key, err := b.getDecryptedKey(a, auth)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key.PrivateKey)
+
+	var N, P int
+	if store, ok := b.storage.(*keyStorePassphrase); ok {
+		N, P = store.scryptN, store.scryptP
+	} else {
+		N, P = StandardScryptN, StandardScryptP
+	}
+	return EncryptKey(key, newAuth, N, P)
+}
+
+func (b *fileBackend) update(a accounts.Account, auth, newAuth string) error {
+	a, key, err := b.getDecryptedKey(a, auth)
+	if err != nil {
+		return err
+	}
+	defer zeroKey(key.PrivateKey)
+
+	return b.storage.StoreKey(a.URL.Path, key, newAuth)
+}
+
+func (b *fileBackend) delete(a accounts.Account, auth string) error {
+	// Decrypting the key isn't really necessary, but we do
+	// it anyway to check the password and zero out the key
+	// immediately afterwards.
+	a, key, err := b.getDecryptedKey(a, auth)
+	if key != nil {
+		zeroKey(key.PrivateKey)
+	}
+	if err != nil {
+		return err
+	}
+	// The order is crucial here. The key is dropped from the
+	// cache after the file is gone so that a reload happening in
+	// between won't insert it into the cache again.
+	if err := os.Remove(a.URL.Path); err != nil {
+		return err
+	}
+	b.cache.delete(a)
+	return nil
+}
+
+func (b *fileBackend) unlock(a accounts.Account, auth string) (keySigner, error) {
+	_, key, err := b.getDecryptedKey(a, auth)
+	if err != nil {
+		return nil, err
+	}
+	return &localSigner{key: key}, nil
+}
+
+func (b *fileBackend) close() {
+	b.cache.close()
+}
+
+// findAccount resolves the given account into a unique entry of a list of
+// accounts holding at most one account per address. The matching rules are
+// explained by the documentation of accounts.Account.
+func findAccount(all []accounts.Account, a accounts.Account) (accounts.Account, error) {
+	for _, account := range all {
+		if a.URL != (accounts.URL{}) {
+			if account.URL == a.URL && (a.Address == common.Address{} || a.Address == account.Address) {
+				return account, nil
+			}
+			continue
+		}
+		if account.Address == a.Address {
+			return account, nil
+		}
+	}
+	return accounts.Account{}, ErrNoMatch
+}
@@ -0,0 +1,152 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rwdxchain/go-rwdxchaina/accounts"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/core/types"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+)
+
+// testBackendLifecycle runs an account through its whole life in the keystore:
+// creation, signing with and without unlocking, passphrase change and deletion.
+func testBackendLifecycle(t *testing.T, ks *KeyStore) {
+	a, err := ks.NewAccount("foo")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	if !ks.HasAddress(a.Address) {
+		t.Fatalf("new account %x not found", a.Address)
+	}
+	if accs := ks.Accounts(); len(accs) != 1 || accs[0] != a {
+		t.Fatalf("account list mismatch: have %v, want [%v]", accs, a)
+	}
+	if wallets := ks.Wallets(); len(wallets) != 1 || wallets[0].URL() != a.URL {
+		t.Fatalf("wallet list mismatch: have %v, want wallet of %v", wallets, a)
+	}
+	if found, err := ks.Find(accounts.Account{Address: a.Address}); err != nil || found != a {
+		t.Fatalf("account lookup mismatch: have %v (%v), want %v", found, err, a)
+	}
+	// Sign with the passphrase and make sure the signature is valid
+	hash := crypto.Keccak256([]byte("hello"))
+	sig, err := ks.SignHashWithPassphrase(a, "foo", hash)
+	if err != nil {
+		t.Fatalf("failed to sign with passphrase: %v", err)
+	}
+	if pubkey, err := crypto.SigToPub(hash, sig); err != nil || crypto.PubkeyToAddress(*pubkey) != a.Address {
+		t.Fatalf("signature not made by the account: %v", err)
+	}
+	if _, err := ks.SignHashWithPassphrase(a, "bar", hash); err != ErrDecrypt {
+		t.Fatalf("invalid passphrase error mismatch: have %v, want %v", err, ErrDecrypt)
+	}
+	// Sign a transaction with an unlocked account
+	if _, err := ks.SignHash(a, hash); err != ErrLocked {
+		t.Fatalf("locked signing error mismatch: have %v, want %v", err, ErrLocked)
+	}
+	if err := ks.Unlock(a, "bar"); err != ErrDecrypt {
+		t.Fatalf("invalid unlock error mismatch: have %v, want %v", err, ErrDecrypt)
+	}
+	if err := ks.Unlock(a, "foo"); err != nil {
+		t.Fatalf("failed to unlock account: %v", err)
+	}
+	tx := types.NewTransaction(0, common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	signed, err := ks.SignTx(a, tx, big.NewInt(7))
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	if sender, err := types.Sender(types.NewEIP155Signer(big.NewInt(7)), signed); err != nil || sender != a.Address {
+		t.Fatalf("transaction sender mismatch: have %x (%v), want %x", sender, err, a.Address)
+	}
+	if err := ks.Lock(a.Address); err != nil {
+		t.Fatalf("failed to lock account: %v", err)
+	}
+	if _, err := ks.SignHash(a, hash); err != ErrLocked {
+		t.Fatalf("relocked signing error mismatch: have %v, want %v", err, ErrLocked)
+	}
+	// Change the passphrase and delete the account with the new one
+	if err := ks.Update(a, "foo", "bar"); err != nil {
+		t.Fatalf("failed to update passphrase: %v", err)
+	}
+	if _, err := ks.SignHashWithPassphrase(a, "bar", hash); err != nil {
+		t.Fatalf("failed to sign with new passphrase: %v", err)
+	}
+	if err := ks.Delete(a, "foo"); err != ErrDecrypt {
+		t.Fatalf("invalid delete error mismatch: have %v, want %v", err, ErrDecrypt)
+	}
+	if err := ks.Delete(a, "bar"); err != nil {
+		t.Fatalf("failed to delete account: %v", err)
+	}
+	if ks.HasAddress(a.Address) {
+		t.Fatalf("deleted account %x still present", a.Address)
+	}
+	if wallets := ks.Wallets(); len(wallets) != 0 {
+		t.Fatalf("wallets of deleted account still present: %v", wallets)
+	}
+}
+
+// Tests that keystores with different backends can be used side by side by an
+// account manager.
+func TestBackendsInManager(t *testing.T) {
+	dir, files := tmpKeyStore(t, true)
+	defer os.RemoveAll(dir)
+
+	db, err := NewDatabaseKeyStore(filepath.Join(dir, "keys.db"), veryLightScryptN, veryLightScryptP)
+	if err != nil {
+		t.Fatalf("failed to create database keystore: %v", err)
+	}
+	signer := newTestRemoteSigner(t)
+	defer signer.close()
+
+	remote, err := NewRemoteKeyStore(signer.server.URL)
+	if err != nil {
+		t.Fatalf("failed to create remote keystore: %v", err)
+	}
+	var accs []accounts.Account
+	for _, ks := range []*KeyStore{files, db, remote} {
+		a, err := ks.NewAccount("foo")
+		if err != nil {
+			t.Fatalf("failed to create account: %v", err)
+		}
+		accs = append(accs, a)
+	}
+	am := accounts.NewManager(files, db, remote)
+	defer am.Close()
+
+	if backends := am.Backends(KeyStoreType); len(backends) != 3 {
+		t.Fatalf("keystore backend count mismatch: have %d, want 3", len(backends))
+	}
+	for i, a := range accs {
+		wallet, err := am.Find(a)
+		if err != nil {
+			t.Fatalf("account %d: wallet not found: %v", i, err)
+		}
+		hash := crypto.Keccak256([]byte("hello"))
+		sig, err := wallet.SignHashWithPassphrase(a, "foo", hash)
+		if err != nil {
+			t.Fatalf("account %d: failed to sign: %v", i, err)
+		}
+		if pubkey, err := crypto.SigToPub(hash, sig); err != nil || crypto.PubkeyToAddress(*pubkey) != a.Address {
+			t.Fatalf("account %d: signature not made by the account: %v", i, err)
+		}
+	}
+}
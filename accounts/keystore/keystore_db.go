@@ -0,0 +1,267 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	crand "crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/rwdxchain/go-rwdxchaina/accounts"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+)
+
+// KeyDBScheme is the protocol scheme prefixing account and wallet URLs of keys
+// stored in a key database file.
+const KeyDBScheme = "keydb"
+
+// keyDBVersion is the version of the key database file format.
+const keyDBVersion = 1
+
+// keyDBJSON is the format of a key database file. The keys are indexed by their
+// hex address, each of them in the Web3 Secret Storage format.
+type keyDBJSON struct {
+	Version int                        `json:"version"`
+	Keys    map[string]json.RawMessage `json:"keys"`
+}
+
+// dbBackend is a keyBackend storing all keys in a single database file, each of
+// them encrypted with its own passphrase. The file is owned by the backend, it
+// is not watched for changes made by other processes.
+type dbBackend struct {
+	path    string // Path of the key database file
+	scryptN int
+	scryptP int
+
+	keys map[common.Address][]byte // Encrypted JSON of all keys in the database
+	mu   sync.Mutex
+}
+
+// NewDatabaseKeyStore creates a keystore backed by the key database file at the
+// given path, which is created once the first key is stored.
+func NewDatabaseKeyStore(path string, scryptN, scryptP int) (*KeyStore, error) {
+	path, _ = filepath.Abs(path)
+	backend, err := newDBBackend(path, scryptN, scryptP)
+	if err != nil {
+		return nil, err
+	}
+	ks := new(KeyStore)
+	ks.init(backend, make(chan struct{}))
+	return ks, nil
+}
+
+// newDBBackend loads the key database file at the given path, if it exists.
+func newDBBackend(path string, scryptN, scryptP int) (*dbBackend, error) {
+	b := &dbBackend{
+		path:    path,
+		scryptN: scryptN,
+		scryptP: scryptP,
+		keys:    make(map[common.Address][]byte),
+	}
+	blob, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var db keyDBJSON
+	if err := json.Unmarshal(blob, &db); err != nil {
+		return nil, fmt.Errorf("invalid key database %s: %v", path, err)
+	}
+	if db.Version != keyDBVersion {
+		return nil, fmt.Errorf("unsupported key database version %d", db.Version)
+	}
+	for hexaddr, keyjson := range db.Keys {
+		if !common.IsHexAddress(hexaddr) {
+			return nil, fmt.Errorf("invalid address %q in key database", hexaddr)
+		}
+		b.keys[common.HexToAddress(hexaddr)] = keyjson
+	}
+	return b, nil
+}
+
+// account returns the account of the key with the given address.
+func (b *dbBackend) account(addr common.Address) accounts.Account {
+	return accounts.Account{Address: addr, URL: accounts.URL{Scheme: KeyDBScheme, Path: fmt.Sprintf("%s/%x", b.path, addr)}}
+}
+
+func (b *dbBackend) accounts() []accounts.Account {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	accs := make([]accounts.Account, 0, len(b.keys))
+	for addr := range b.keys {
+		accs = append(accs, b.account(addr))
+	}
+	sort.Sort(accountsByURL(accs))
+	return accs
+}
+
+func (b *dbBackend) hasAddress(addr common.Address) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, ok := b.keys[addr]
+	return ok
+}
+
+func (b *dbBackend) find(a accounts.Account) (accounts.Account, error) {
+	return findAccount(b.accounts(), a)
+}
+
+// getDecryptedKey resolves the given account and decrypts its key.
+func (b *dbBackend) getDecryptedKey(a accounts.Account, auth string) (accounts.Account, *Key, error) {
+	a, err := b.find(a)
+	if err != nil {
+		return a, nil, err
+	}
+	b.mu.Lock()
+	keyjson, ok := b.keys[a.Address]
+	b.mu.Unlock()
+
+	if !ok {
+		return a, nil, ErrNoMatch
+	}
+	key, err := DecryptKey(keyjson, auth)
+	if err != nil {
+		return a, nil, err
+	}
+	// Make sure we're really operating on the requested key (no swap attacks)
+	if key.Address != a.Address {
+		zeroKey(key.PrivateKey)
+		return a, nil, fmt.Errorf("key content mismatch: have account %x, want %x", key.Address, a.Address)
+	}
+	return a, key, nil
+}
+
+// store encrypts the key with the passphrase and writes it into the database,
+// replacing any previous key with the same address if requested.
+func (b *dbBackend) store(key *Key, auth string, replace bool) error {
+	keyjson, err := EncryptKey(key, auth, b.scryptN, b.scryptP)
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.keys[key.Address]; ok && !replace {
+		return fmt.Errorf("account already exists")
+	}
+	prev, existed := b.keys[key.Address]
+	b.keys[key.Address] = keyjson
+	if err := b.write(); err != nil {
+		if existed {
+			b.keys[key.Address] = prev
+		} else {
+			delete(b.keys, key.Address)
+		}
+		return err
+	}
+	return nil
+}
+
+// write persists the keys into the database file. Callers must hold b.mu.
+func (b *dbBackend) write() error {
+	db := keyDBJSON{Version: keyDBVersion, Keys: make(map[string]json.RawMessage, len(b.keys))}
+	for addr, keyjson := range b.keys {
+		db.Keys[addr.Hex()] = keyjson
+	}
+	blob, err := json.MarshalIndent(db, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeKeyFile(b.path, blob)
+}
+
+func (b *dbBackend) newAccount(auth string) (accounts.Account, error) {
+	key, err := newKey(crand.Reader)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	defer zeroKey(key.PrivateKey)
+
+	if err := b.store(key, auth, false); err != nil {
+		return accounts.Account{}, err
+	}
+	return b.account(key.Address), nil
+}
+
+func (b *dbBackend) importKey(key *Key, auth string) (accounts.Account, error) {
+	if err := b.store(key, auth, false); err != nil {
+		return accounts.Account{}, err
+	}
+	return b.account(key.Address), nil
+}
+
+func (b *dbBackend) exportKey(a accounts.Account, auth, newAuth string) ([]byte, error) {
+	_, key, err := b.getDecryptedKey(a, auth)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroKey(key.PrivateKey)
+
+	return EncryptKey(key, newAuth, b.scryptN, b.scryptP)
+}
+
+func (b *dbBackend) update(a accounts.Account, auth, newAuth string) error {
+	_, key, err := b.getDecryptedKey(a, auth)
+	if err != nil {
+		return err
+	}
+	defer zeroKey(key.PrivateKey)
+
+	return b.store(key, newAuth, true)
+}
+
+func (b *dbBackend) delete(a accounts.Account, auth string) error {
+	// Decrypt the key to check the password, zeroing it out immediately
+	a, key, err := b.getDecryptedKey(a, auth)
+	if err != nil {
+		return err
+	}
+	zeroKey(key.PrivateKey)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	keyjson, ok := b.keys[a.Address]
+	if !ok {
+		return ErrNoMatch
+	}
+	delete(b.keys, a.Address)
+	if err := b.write(); err != nil {
+		b.keys[a.Address] = keyjson
+		return err
+	}
+	return nil
+}
+
+func (b *dbBackend) unlock(a accounts.Account, auth string) (keySigner, error) {
+	_, key, err := b.getDecryptedKey(a, auth)
+	if err != nil {
+		return nil, err
+	}
+	return &localSigner{key: key}, nil
+}
+
+func (b *dbBackend) close() {}
@@ -0,0 +1,132 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+)
+
+func tmpDatabaseKeyStore(t *testing.T) (string, *KeyStore) {
+	dir, err := ioutil.TempDir("", "rwdxchain-keystore-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks, err := NewDatabaseKeyStore(filepath.Join(dir, "keys.db"), veryLightScryptN, veryLightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, ks
+}
+
+func TestDatabaseKeyStore(t *testing.T) {
+	dir, ks := tmpDatabaseKeyStore(t)
+	defer os.RemoveAll(dir)
+
+	testBackendLifecycle(t, ks)
+}
+
+// Tests that keys stored in a database file survive reopening it, and that the
+// database file is not readable by others.
+func TestDatabaseKeyStorePersistence(t *testing.T) {
+	dir, ks := tmpDatabaseKeyStore(t)
+	defer os.RemoveAll(dir)
+
+	a, err := ks.NewAccount("foo")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	key, _ := crypto.GenerateKey()
+	b, err := ks.ImportECDSA(key, "bar")
+	if err != nil {
+		t.Fatalf("failed to import key: %v", err)
+	}
+	if _, err := ks.ImportECDSA(key, "bar"); err == nil {
+		t.Fatalf("duplicate key imported")
+	}
+	path := filepath.Join(dir, "keys.db")
+	stat, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("database file missing: %v", err)
+	}
+	if runtime.GOOS != "windows" && stat.Mode() != 0600 {
+		t.Fatalf("database file has wrong mode: got %o, want %o", stat.Mode(), 0600)
+	}
+	blob, _ := ioutil.ReadFile(path)
+	if bytes.Contains(blob, []byte(hex.EncodeToString(crypto.FromECDSA(key)))) {
+		t.Fatalf("database file contains plaintext key")
+	}
+	// Reopen the database and check the keys are still usable
+	reopened, err := NewDatabaseKeyStore(path, veryLightScryptN, veryLightScryptP)
+	if err != nil {
+		t.Fatalf("failed to reopen database: %v", err)
+	}
+	accs := reopened.Accounts()
+	if len(accs) != 2 {
+		t.Fatalf("account count mismatch: have %d, want 2", len(accs))
+	}
+	for _, acc := range accs {
+		if acc != a && acc != b {
+			t.Fatalf("unexpected account %v", acc)
+		}
+	}
+	if _, err := reopened.SignHashWithPassphrase(a, "foo", testSigData); err != nil {
+		t.Fatalf("failed to sign with reopened key: %v", err)
+	}
+	// Export the imported key and make sure it round trips
+	keyjson, err := reopened.Export(b, "bar", "baz")
+	if err != nil {
+		t.Fatalf("failed to export key: %v", err)
+	}
+	exported, err := DecryptKey(keyjson, "baz")
+	if err != nil {
+		t.Fatalf("failed to decrypt exported key: %v", err)
+	}
+	if !bytes.Equal(crypto.FromECDSA(exported.PrivateKey), crypto.FromECDSA(key)) {
+		t.Fatalf("exported key mismatch")
+	}
+}
+
+// Tests that corrupt or unknown database files are rejected.
+func TestDatabaseKeyStoreInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rwdxchain-keystore-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for i, content := range []string{
+		`not json`,
+		`{"version": 2, "keys": {}}`,
+		`{"version": 1, "keys": {"not an address": {}}}`,
+	} {
+		path := filepath.Join(dir, "keys.db")
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewDatabaseKeyStore(path, veryLightScryptN, veryLightScryptP); err == nil {
+			t.Errorf("test %d: invalid database accepted", i)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/accounts"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/common/hexutil"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+	"github.com/rwdxchain/go-rwdxchaina/log"
+)
+
+// remoteRequestTimeout is the maximum time to wait for the remote signing service
+// to answer a request.
+const remoteRequestTimeout = 30 * time.Second
+
+// remoteRequest is the body of the requests to the remote signing service.
+type remoteRequest struct {
+	Passphrase    string        `json:"passphrase"`
+	NewPassphrase string        `json:"newPassphrase,omitempty"`
+	Hash          hexutil.Bytes `json:"hash,omitempty"`
+}
+
+// remoteResponse is the body of the responses of the remote signing service.
+type remoteResponse struct {
+	Address   *common.Address `json:"address,omitempty"`
+	Signature hexutil.Bytes   `json:"signature,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// remoteBackend is a keyBackend delegating all key operations to a remote signing
+// service, which never discloses the keys it holds. The service is accessed with
+// a simple JSON over HTTP protocol:
+//
+//	GET  /accounts                      lists the addresses of all keys as a JSON array
+//	POST /accounts                      generates a new key
+//	POST /accounts/<address>/unlock     verifies the passphrase of a key
+//	POST /accounts/<address>/sign       signs a hash with a key
+//	POST /accounts/<address>/update     changes the passphrase of a key
+//	POST /accounts/<address>/delete     deletes a key
+//
+// All POST requests carry the passphrase of the key (and the new passphrase or
+// the hash to sign if needed) as a remoteRequest. Failures are reported with a
+// non-2xx status code and an error message, an invalid passphrase by status 401
+// and an unknown key by status 404. New keys are reported by their address and
+// signatures are returned in the [R || S || V] format where V is 0 or 1.
+type remoteBackend struct {
+	endpoint *url.URL
+	client   *http.Client
+
+	accs    []accounts.Account // Accounts of the service, cached between reloads
+	updated time.Time          // Time of the last reload of the account list
+	mu      sync.Mutex
+}
+
+// NewRemoteKeyStore creates a keystore delegating to the remote signing service
+// at the given HTTP endpoint. As passphrases are sent along the requests, plain
+// HTTP is only accepted for services running on the loopback interface.
+func NewRemoteKeyStore(endpoint string) (*KeyStore, error) {
+	backend, err := newRemoteBackend(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	ks := new(KeyStore)
+	ks.init(backend, make(chan struct{}))
+	return ks, nil
+}
+
+func newRemoteBackend(endpoint string) (*remoteBackend, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported remote signer scheme %q", u.Scheme)
+	}
+	if u.Scheme == "http" && !isLoopbackHost(u.Hostname()) {
+		return nil, fmt.Errorf("remote signer %s is not on the loopback interface, https required", u.Host)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return &remoteBackend{
+		endpoint: u,
+		client: &http.Client{
+			Timeout: remoteRequestTimeout,
+			// Redirects would replay passphrases to wherever the signer points
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}, nil
+}
+
+// isLoopbackHost reports whether the given host name refers to the local machine.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// account returns the account of the key with the given address.
+func (b *remoteBackend) account(addr common.Address) accounts.Account {
+	return accounts.Account{Address: addr, URL: accounts.URL{Scheme: b.endpoint.Scheme, Path: fmt.Sprintf("%s%s/accounts/%x", b.endpoint.Host, b.endpoint.Path, addr)}}
+}
+
+// do sends a request to the remote signing service, decoding the response into
+// the given result.
+func (b *remoteBackend) do(method, path string, req *remoteRequest, result interface{}) error {
+	var body []byte
+	if req != nil {
+		var err error
+		if body, err = json.Marshal(req); err != nil {
+			return err
+		}
+	}
+	httpReq, err := http.NewRequest(method, b.endpoint.String()+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	res, err := b.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	blob, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	switch {
+	case res.StatusCode == http.StatusUnauthorized:
+		return ErrDecrypt
+	case res.StatusCode == http.StatusNotFound:
+		return ErrNoMatch
+	case res.StatusCode < 200 || res.StatusCode >= 300:
+		var failure remoteResponse
+		if err := json.Unmarshal(blob, &failure); err == nil && failure.Error != "" {
+			return errors.New(failure.Error)
+		}
+		return fmt.Errorf("remote signer failure: %s", res.Status)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(blob, result)
+}
+
+// reload retrieves the account list from the remote signing service. Callers
+// must hold b.mu.
+func (b *remoteBackend) reload() error {
+	var addrs []common.Address
+	if err := b.do(http.MethodGet, "/accounts", nil, &addrs); err != nil {
+		return err
+	}
+	accs := make([]accounts.Account, len(addrs))
+	for i, addr := range addrs {
+		accs[i] = b.account(addr)
+	}
+	sort.Sort(accountsByURL(accs))
+	b.accs, b.updated = accs, time.Now()
+	return nil
+}
+
+func (b *remoteBackend) accounts() []accounts.Account {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if time.Since(b.updated) >= minReloadInterval {
+		if err := b.reload(); err != nil {
+			log.Warn("Failed to list remote signer accounts", "url", b.endpoint, "err", err)
+			b.updated = time.Now() // Don't retry on every call, keep the stale list
+		}
+	}
+	cpy := make([]accounts.Account, len(b.accs))
+	copy(cpy, b.accs)
+	return cpy
+}
+
+func (b *remoteBackend) hasAddress(addr common.Address) bool {
+	_, err := findAccount(b.accounts(), accounts.Account{Address: addr})
+	return err == nil
+}
+
+func (b *remoteBackend) find(a accounts.Account) (accounts.Account, error) {
+	return findAccount(b.accounts(), a)
+}
+
+// forceReload refreshes the account list after a change made through the backend.
+func (b *remoteBackend) forceReload() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.reload(); err != nil {
+		log.Warn("Failed to list remote signer accounts", "url", b.endpoint, "err", err)
+		b.updated = time.Time{}
+	}
+}
+
+func (b *remoteBackend) newAccount(auth string) (accounts.Account, error) {
+	var res remoteResponse
+	if err := b.do(http.MethodPost, "/accounts", &remoteRequest{Passphrase: auth}, &res); err != nil {
+		return accounts.Account{}, err
+	}
+	if res.Address == nil {
+		return accounts.Account{}, errors.New("remote signer returned no address")
+	}
+	b.forceReload()
+	return b.account(*res.Address), nil
+}
+
+// importKey implements keyBackend, but is not supported as keys are generated by
+// the remote signing service itself.
+func (b *remoteBackend) importKey(key *Key, auth string) (accounts.Account, error) {
+	return accounts.Account{}, accounts.ErrNotSupported
+}
+
+// exportKey implements keyBackend, but is not supported as keys never leave the
+// remote signing service.
+func (b *remoteBackend) exportKey(a accounts.Account, auth, newAuth string) ([]byte, error) {
+	return nil, accounts.ErrNotSupported
+}
+
+func (b *remoteBackend) update(a accounts.Account, auth, newAuth string) error {
+	a, err := b.find(a)
+	if err != nil {
+		return err
+	}
+	return b.do(http.MethodPost, fmt.Sprintf("/accounts/%s/update", a.Address.Hex()), &remoteRequest{Passphrase: auth, NewPassphrase: newAuth}, nil)
+}
+
+func (b *remoteBackend) delete(a accounts.Account, auth string) error {
+	a, err := b.find(a)
+	if err != nil {
+		return err
+	}
+	if err := b.do(http.MethodPost, fmt.Sprintf("/accounts/%s/delete", a.Address.Hex()), &remoteRequest{Passphrase: auth}, nil); err != nil {
+		return err
+	}
+	b.forceReload()
+	return nil
+}
+
+func (b *remoteBackend) unlock(a accounts.Account, auth string) (keySigner, error) {
+	a, err := b.find(a)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.do(http.MethodPost, fmt.Sprintf("/accounts/%s/unlock", a.Address.Hex()), &remoteRequest{Passphrase: auth}, nil); err != nil {
+		return nil, err
+	}
+	return &remoteSigner{backend: b, address: a.Address, auth: auth}, nil
+}
+
+func (b *remoteBackend) close() {}
+
+// remoteSigner is a keySigner holding the passphrase of a key of the remote
+// signing service, which is sent along every signing request.
+type remoteSigner struct {
+	backend *remoteBackend
+	address common.Address
+
+	auth string
+	mu   sync.Mutex
+}
+
+func (s *remoteSigner) signHash(hash []byte) ([]byte, error) {
+	s.mu.Lock()
+	auth := s.auth
+	s.mu.Unlock()
+
+	var res remoteResponse
+	if err := s.backend.do(http.MethodPost, fmt.Sprintf("/accounts/%s/sign", s.address.Hex()), &remoteRequest{Passphrase: auth, Hash: hash}, &res); err != nil {
+		return nil, err
+	}
+	if len(res.Signature) != 65 || res.Signature[64] > 1 {
+		return nil, fmt.Errorf("invalid remote signature: %x", []byte(res.Signature))
+	}
+	// Don't trust the service blindly, make sure the right key signed the hash
+	pub, err := crypto.SigToPub(hash, res.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote signature: %v", err)
+	}
+	if signer := crypto.PubkeyToAddress(*pub); signer != s.address {
+		return nil, fmt.Errorf("remote signature by %s instead of %s", signer.Hex(), s.address.Hex())
+	}
+	return res.Signature, nil
+}
+
+func (s *remoteSigner) zero() {
+	s.mu.Lock()
+	s.auth = ""
+	s.mu.Unlock()
+}
@@ -0,0 +1,273 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package keystore
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rwdxchain/go-rwdxchaina/accounts"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+)
+
+// testRemoteSigner is a local stand-in for a remote signing service, keeping its
+// keys in memory.
+type testRemoteSigner struct {
+	server *httptest.Server
+
+	keys  map[common.Address]*ecdsa.PrivateKey
+	auths map[common.Address]string
+	signs int  // Number of signatures made
+	forge bool // Whether to sign with a key other than the requested one
+	mu    sync.Mutex
+}
+
+func newTestRemoteSigner(t *testing.T) *testRemoteSigner {
+	s := &testRemoteSigner{
+		keys:  make(map[common.Address]*ecdsa.PrivateKey),
+		auths: make(map[common.Address]string),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+func (s *testRemoteSigner) close() {
+	s.server.Close()
+}
+
+func (s *testRemoteSigner) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reply := func(status int, res interface{}) {
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(res)
+	}
+	if r.URL.Path == "/accounts" && r.Method == http.MethodGet {
+		addrs := []common.Address{}
+		for addr := range s.keys {
+			addrs = append(addrs, addr)
+		}
+		reply(http.StatusOK, addrs)
+		return
+	}
+	if r.Method != http.MethodPost {
+		reply(http.StatusMethodNotAllowed, remoteResponse{Error: "method not allowed"})
+		return
+	}
+	var req remoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		reply(http.StatusBadRequest, remoteResponse{Error: err.Error()})
+		return
+	}
+	if r.URL.Path == "/accounts" {
+		key, _ := crypto.GenerateKey()
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		s.keys[addr], s.auths[addr] = key, req.Passphrase
+		reply(http.StatusOK, remoteResponse{Address: &addr})
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/accounts/"), "/")
+	if len(parts) != 2 || !common.IsHexAddress(parts[0]) {
+		reply(http.StatusBadRequest, remoteResponse{Error: "invalid path"})
+		return
+	}
+	addr := common.HexToAddress(parts[0])
+	key, ok := s.keys[addr]
+	if !ok {
+		reply(http.StatusNotFound, remoteResponse{Error: "unknown account"})
+		return
+	}
+	if req.Passphrase != s.auths[addr] {
+		reply(http.StatusUnauthorized, remoteResponse{Error: "invalid passphrase"})
+		return
+	}
+	switch parts[1] {
+	case "unlock":
+		reply(http.StatusOK, remoteResponse{})
+	case "sign":
+		if s.forge {
+			key, _ = crypto.GenerateKey()
+		}
+		sig, err := crypto.Sign(req.Hash, key)
+		if err != nil {
+			reply(http.StatusBadRequest, remoteResponse{Error: err.Error()})
+			return
+		}
+		s.signs++
+		reply(http.StatusOK, remoteResponse{Signature: sig})
+	case "update":
+		s.auths[addr] = req.NewPassphrase
+		reply(http.StatusOK, remoteResponse{})
+	case "delete":
+		delete(s.keys, addr)
+		delete(s.auths, addr)
+		reply(http.StatusOK, remoteResponse{})
+	default:
+		reply(http.StatusBadRequest, remoteResponse{Error: "unknown operation"})
+	}
+}
+
+func TestRemoteKeyStore(t *testing.T) {
+	signer := newTestRemoteSigner(t)
+	defer signer.close()
+
+	ks, err := NewRemoteKeyStore(signer.server.URL + "/")
+	if err != nil {
+		t.Fatalf("failed to create remote keystore: %v", err)
+	}
+	testBackendLifecycle(t, ks)
+
+	if signer.signs == 0 {
+		t.Fatalf("no signatures made by the remote signer")
+	}
+}
+
+// Tests that keys never leave the remote signer.
+func TestRemoteKeyStoreNoKeyTransfer(t *testing.T) {
+	signer := newTestRemoteSigner(t)
+	defer signer.close()
+
+	ks, err := NewRemoteKeyStore(signer.server.URL)
+	if err != nil {
+		t.Fatalf("failed to create remote keystore: %v", err)
+	}
+	a, err := ks.NewAccount("foo")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	if _, err := ks.Export(a, "foo", "bar"); err != accounts.ErrNotSupported {
+		t.Fatalf("export error mismatch: have %v, want %v", err, accounts.ErrNotSupported)
+	}
+	key, _ := crypto.GenerateKey()
+	if _, err := ks.ImportECDSA(key, "foo"); err != accounts.ErrNotSupported {
+		t.Fatalf("import error mismatch: have %v, want %v", err, accounts.ErrNotSupported)
+	}
+}
+
+// Tests that accounts created on the remote signer by others show up as wallets.
+func TestRemoteKeyStoreExternalChanges(t *testing.T) {
+	signer := newTestRemoteSigner(t)
+	defer signer.close()
+
+	ks, err := NewRemoteKeyStore(signer.server.URL)
+	if err != nil {
+		t.Fatalf("failed to create remote keystore: %v", err)
+	}
+	other, err := NewRemoteKeyStore(signer.server.URL)
+	if err != nil {
+		t.Fatalf("failed to create second remote keystore: %v", err)
+	}
+	a, err := other.NewAccount("foo")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	// Force a reload instead of waiting for the cached list to expire
+	ks.backend.(*remoteBackend).forceReload()
+
+	if _, err := ks.Find(a); err != nil {
+		t.Fatalf("externally created account not found: %v", err)
+	}
+	if _, err := NewRemoteKeyStore("ftp://localhost"); err == nil {
+		t.Fatalf("unsupported scheme accepted")
+	}
+}
+
+// Tests that plain HTTP is only accepted for remote signers on the local machine.
+func TestRemoteKeyStoreRequiresTLS(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		ok       bool
+	}{
+		{"http://localhost:8550", true},
+		{"http://127.0.0.1:8550", true},
+		{"http://[::1]:8550", true},
+		{"https://signer.example.com", true},
+		{"http://signer.example.com", false},
+		{"http://10.0.0.1:8550", false},
+	}
+	for _, tt := range tests {
+		_, err := NewRemoteKeyStore(tt.endpoint)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: error mismatch: have %v, want ok %v", tt.endpoint, err, tt.ok)
+		}
+	}
+}
+
+// Tests that signatures made by a key other than the requested one are rejected.
+func TestRemoteKeyStoreWrongSigner(t *testing.T) {
+	signer := newTestRemoteSigner(t)
+	defer signer.close()
+
+	ks, err := NewRemoteKeyStore(signer.server.URL)
+	if err != nil {
+		t.Fatalf("failed to create remote keystore: %v", err)
+	}
+	a, err := ks.NewAccount("foo")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	if _, err := ks.SignHashWithPassphrase(a, "foo", make([]byte, 32)); err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	signer.mu.Lock()
+	signer.forge = true
+	signer.mu.Unlock()
+
+	if _, err := ks.SignHashWithPassphrase(a, "foo", make([]byte, 32)); err == nil {
+		t.Fatalf("signature by wrong key accepted")
+	}
+}
+
+// Tests that redirects of the remote signer aren't followed, so passphrases are
+// never replayed to another server.
+func TestRemoteKeyStoreNoRedirect(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		captured int
+	)
+	capture := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		captured++
+		mu.Unlock()
+	}))
+	defer capture.Close()
+
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, capture.URL+r.URL.Path, http.StatusTemporaryRedirect)
+	}))
+	defer redirect.Close()
+
+	ks, err := NewRemoteKeyStore(redirect.URL)
+	if err != nil {
+		t.Fatalf("failed to create remote keystore: %v", err)
+	}
+	if _, err := ks.NewAccount("foo"); err == nil {
+		t.Fatalf("redirected account creation succeeded")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if captured != 0 {
+		t.Fatalf("redirects followed %d times", captured)
+	}
+}
@@ -20,12 +20,13 @@ import (
 	"errors"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rwdxchain/go-rwdxchaina/accounts"
 	"github.com/rwdxchain/go-rwdxchaina/event"
 	"github.com/rwdxchain/go-rwdxchaina/log"
-	"github.com/karalabe/hid"
+	"github.com/karalabe/usb"
 )
 
 // LedgerScheme is the protocol scheme prefixing account and wallet URLs.
@@ -47,8 +48,8 @@ type Hub struct {
 	scheme     string                  // Protocol scheme prefixing account and wallet URLs.
 	vendorID   uint16                  // USB vendor identifier used for device discovery
 	productIDs []uint16                // USB product identifiers used for device discovery
-	usageID    uint16                  // USB usage page identifier used for macOS device discovery
-	endpointID int                     // USB endpoint identifier used for non-macOS device discovery
+	usageID    uint16                  // USB usage page identifier used for Windows and macOS device discovery
+	endpointID int                     // USB endpoint identifier used for Linux device discovery
 	makeDriver func(log.Logger) driver // Factory method to construct a vendor specific driver
 
 	refreshed   time.Time               // Time instance when the list of wallets was last refreshed
@@ -56,6 +57,7 @@ type Hub struct {
 	updateFeed  event.Feed              // Event feed to notify wallet additions/removals
 	updateScope event.SubscriptionScope // Subscription scope tracking current live listeners
 	updating    bool                    // Whether the event notification loop is running
+	enumFails   uint32                  // Number of consecutive failed enumerations (atomic)
 
 	quit chan chan error
 
@@ -68,17 +70,41 @@ type Hub struct {
 
 // NewLedgerHub creates a new hardware wallet manager for Ledger devices.
 func NewLedgerHub() (*Hub, error) {
-	return newHub(LedgerScheme, 0x2c97, []uint16{0x0000 /* Ledger Blue */, 0x0001 /* Ledger Nano S */}, 0xffa0, 0, newLedgerDriver)
+	return newHub(LedgerScheme, 0x2c97, []uint16{
+		// Original product IDs
+		0x0000, /* Ledger Blue */
+		0x0001, /* Ledger Nano S */
+		0x0004, /* Ledger Nano X */
+
+		// Product IDs of firmwares also exposing a WebUSB interface. The wallet is
+		// still reached through the generic HID interface.
+		0x0011, /* HID + WebUSB Ledger Blue */
+		0x1011, /* HID + WebUSB Ledger Nano S */
+		0x4011, /* HID + WebUSB Ledger Nano X */
+		0x0015, /* HID + U2F + WebUSB Ledger Blue */
+		0x1015, /* HID + U2F + WebUSB Ledger Nano S */
+		0x4015, /* HID + U2F + WebUSB Ledger Nano X */
+	}, 0xffa0, 0, newLedgerDriver)
 }
 
-// NewTrezorHub creates a new hardware wallet manager for Trezor devices.
+// NewTrezorHub creates a new hardware wallet manager for Trezor devices talking
+// over HID, i.e. the Trezor One with firmwares before 1.7.
 func NewTrezorHub() (*Hub, error) {
-	return newHub(TrezorScheme, 0x534c, []uint16{0x0001 /* Trezor 1 */}, 0xff00, 0, newTrezorDriver)
+	return newHub(TrezorScheme, 0x534c, []uint16{0x0001 /* Trezor One HID */}, 0xff00, 0, newTrezorDriver)
+}
+
+// NewTrezorHubWithWebUSB creates a new hardware wallet manager for Trezor devices
+// talking over WebUSB, i.e. the Trezor Model T and the Trezor One with firmwares
+// from 1.7 onward.
+func NewTrezorHubWithWebUSB() (*Hub, error) {
+	// WebUSB interfaces don't have a usage page, use one that can't match instead
+	// of the unset zero value.
+	return newHub(TrezorScheme, 0x1209, []uint16{0x53c1 /* Trezor WebUSB */}, 0xffff, 0, newTrezorDriver)
 }
 
 // newHub creates a new hardware wallet manager for generic USB devices.
 func newHub(scheme string, vendorID uint16, productIDs []uint16, usageID uint16, endpointID int, makeDriver func(log.Logger) driver) (*Hub, error) {
-	if !hid.Supported() {
+	if !usb.Supported() {
 		return nil, errors.New("unsupported platform")
 	}
 	hub := &Hub{
@@ -120,7 +146,7 @@ func (hub *Hub) refreshWallets() {
 		return
 	}
 	// Retrieve the current list of USB wallet devices
-	var devices []hid.DeviceInfo
+	var devices []usb.DeviceInfo
 
 	if runtime.GOOS == "linux" {
 		// hidapi on Linux opens the device during enumeration to retrieve some infos,
@@ -135,18 +161,23 @@ func (hub *Hub) refreshWallets() {
 			return
 		}
 	}
-	for _, info := range hid.Enumerate(hub.vendorID, 0) {
-		for _, id := range hub.productIDs {
-			if info.ProductID == id && (info.UsagePage == hub.usageID || info.Interface == hub.endpointID) {
-				devices = append(devices, info)
-				break
-			}
-		}
-	}
+	infos, err := usb.Enumerate(hub.vendorID, 0)
 	if runtime.GOOS == "linux" {
 		// See rationale before the enumeration why this is needed and only on Linux.
 		hub.commsLock.Unlock()
 	}
+	if err != nil {
+		failcount := atomic.AddUint32(&hub.enumFails, 1)
+		log.Error("Failed to enumerate USB devices", "hub", hub.scheme, "vendor", hub.vendorID, "failcount", failcount, "err", err)
+		return
+	}
+	atomic.StoreUint32(&hub.enumFails, 0)
+
+	for _, info := range infos {
+		if hub.matches(info) {
+			devices = append(devices, info)
+		}
+	}
 	// Transform the current list of wallets into the new one
 	hub.stateLock.Lock()
 
@@ -197,6 +228,21 @@ func (hub *Hub) refreshWallets() {
 	}
 }
 
+// matches returns whether an enumerated USB device or interface is a wallet the
+// hub should handle. Windows and macOS report the HID usage page of interfaces,
+// Linux only reports the interface number, so either is accepted.
+func (hub *Hub) matches(info usb.DeviceInfo) bool {
+	if info.VendorID != hub.vendorID {
+		return false
+	}
+	for _, id := range hub.productIDs {
+		if info.ProductID == id && (info.UsagePage == hub.usageID || info.Interface == hub.endpointID) {
+			return true
+		}
+	}
+	return false
+}
+
 // Subscribe implements accounts.Backend, creating an async subscription to
 // receive notifications on the addition or removal of USB wallets.
 func (hub *Hub) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
@@ -0,0 +1,77 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package usbwallet
+
+import (
+	"testing"
+
+	"github.com/karalabe/usb"
+)
+
+// Tests that the hubs pick the wallet interfaces of the supported devices, as
+// reported by the different platforms.
+func TestHubDeviceMatching(t *testing.T) {
+	if !usb.Supported() {
+		t.Skip("USB not supported on this platform")
+	}
+	ledger, err := NewLedgerHub()
+	if err != nil {
+		t.Fatalf("failed to create Ledger hub: %v", err)
+	}
+	trezorHID, err := NewTrezorHub()
+	if err != nil {
+		t.Fatalf("failed to create HID Trezor hub: %v", err)
+	}
+	trezorWebUSB, err := NewTrezorHubWithWebUSB()
+	if err != nil {
+		t.Fatalf("failed to create WebUSB Trezor hub: %v", err)
+	}
+	tests := []struct {
+		hub   *Hub
+		info  usb.DeviceInfo
+		match bool
+	}{
+		// Ledger devices, matched on the generic HID interface
+		{ledger, usb.DeviceInfo{VendorID: 0x2c97, ProductID: 0x0001, Interface: 0}, true},                     // Nano S on Linux
+		{ledger, usb.DeviceInfo{VendorID: 0x2c97, ProductID: 0x0001, UsagePage: 0xffa0, Interface: -1}, true}, // Nano S on macOS
+		{ledger, usb.DeviceInfo{VendorID: 0x2c97, ProductID: 0x0004, Interface: 0}, true},                     // Nano X
+		{ledger, usb.DeviceInfo{VendorID: 0x2c97, ProductID: 0x4015, Interface: 0}, true},                     // Nano X with WebUSB
+		{ledger, usb.DeviceInfo{VendorID: 0x2c97, ProductID: 0x4015, UsagePage: 0xf1d0, Interface: 1}, false}, // Nano X U2F interface
+		{ledger, usb.DeviceInfo{VendorID: 0x2c97, ProductID: 0x4015, Interface: 2}, false},                    // Nano X WebUSB interface
+		{ledger, usb.DeviceInfo{VendorID: 0x2c97, ProductID: 0x1011, Interface: 0}, true},                     // Nano S with WebUSB
+		{ledger, usb.DeviceInfo{VendorID: 0x2c97, ProductID: 0x0002, Interface: 0}, false},                    // Unknown product
+		{ledger, usb.DeviceInfo{VendorID: 0x534c, ProductID: 0x0001, Interface: 0}, false},                    // Trezor One
+
+		// Trezor devices talking HID
+		{trezorHID, usb.DeviceInfo{VendorID: 0x534c, ProductID: 0x0001, Interface: 0}, true},
+		{trezorHID, usb.DeviceInfo{VendorID: 0x534c, ProductID: 0x0001, UsagePage: 0xff00, Interface: -1}, true},
+		{trezorHID, usb.DeviceInfo{VendorID: 0x534c, ProductID: 0x0001, UsagePage: 0xf1d0, Interface: 1}, false},
+		{trezorHID, usb.DeviceInfo{VendorID: 0x1209, ProductID: 0x53c1, Interface: 0}, false},
+
+		// Trezor devices talking WebUSB
+		{trezorWebUSB, usb.DeviceInfo{VendorID: 0x1209, ProductID: 0x53c1, Interface: 0}, true},                     // Wallet interface
+		{trezorWebUSB, usb.DeviceInfo{VendorID: 0x1209, ProductID: 0x53c1, Interface: 1}, false},                    // Debug link interface
+		{trezorWebUSB, usb.DeviceInfo{VendorID: 0x1209, ProductID: 0x53c1, UsagePage: 0xf1d0, Interface: 2}, false}, // U2F interface
+		{trezorWebUSB, usb.DeviceInfo{VendorID: 0x1209, ProductID: 0x53c0, Interface: 0}, false},                    // Bootloader
+	}
+	for i, tt := range tests {
+		if match := tt.hub.matches(tt.info); match != tt.match {
+			t.Errorf("test %d: %s hub match mismatch for %04x:%04x (usage page %04x, interface %d): have %v, want %v",
+				i, tt.hub.scheme, tt.info.VendorID, tt.info.ProductID, tt.info.UsagePage, tt.info.Interface, match, tt.match)
+		}
+	}
+}
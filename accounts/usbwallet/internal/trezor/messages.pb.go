@@ -310,6 +310,7 @@ type Features struct {
 	FirmwarePresent      *bool       `protobuf:"varint,18,opt,name=firmware_present,json=firmwarePresent" json:"firmware_present,omitempty"`
 	NeedsBackup          *bool       `protobuf:"varint,19,opt,name=needs_backup,json=needsBackup" json:"needs_backup,omitempty"`
 	Flags                *uint32     `protobuf:"varint,20,opt,name=flags" json:"flags,omitempty"`
+	Model                *string     `protobuf:"bytes,21,opt,name=model" json:"model,omitempty"`
 	XXX_unrecognized     []byte      `json:"-"`
 }
 
@@ -458,6 +459,13 @@ func (m *Features) GetFlags() uint32 {
 	return 0
 }
 
+func (m *Features) GetModel() string {
+	if m != nil && m.Model != nil {
+		return *m.Model
+	}
+	return ""
+}
+
 // *
 // Request: clear session (removes cached PIN, passphrase, etc).
 // @next Success
@@ -750,6 +758,7 @@ func (*Cancel) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{14}
 // @next PassphraseAck
 // @next Cancel
 type PassphraseRequest struct {
+	OnDevice         *bool  `protobuf:"varint,1,opt,name=on_device,json=onDevice" json:"on_device,omitempty"`
 	XXX_unrecognized []byte `json:"-"`
 }
 
@@ -758,11 +767,18 @@ func (m *PassphraseRequest) String() string            { return proto.CompactTex
 func (*PassphraseRequest) ProtoMessage()               {}
 func (*PassphraseRequest) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{15} }
 
+func (m *PassphraseRequest) GetOnDevice() bool {
+	if m != nil && m.OnDevice != nil {
+		return *m.OnDevice
+	}
+	return false
+}
+
 // *
 // Request: Send passphrase back
 // @prev PassphraseRequest
 type PassphraseAck struct {
-	Passphrase       *string `protobuf:"bytes,1,req,name=passphrase" json:"passphrase,omitempty"`
+	Passphrase       *string `protobuf:"bytes,1,opt,name=passphrase" json:"passphrase,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
 }
 
@@ -1007,8 +1023,9 @@ func (m *Address) GetAddress() string {
 // Response: Contains an Ethereum address derived from device private seed
 // @prev EthereumGetAddress
 type EthereumAddress struct {
-	Address          []byte `protobuf:"bytes,1,req,name=address" json:"address,omitempty"`
-	XXX_unrecognized []byte `json:"-"`
+	AddressBin       []byte  `protobuf:"bytes,1,opt,name=address_bin,json=addressBin" json:"address_bin,omitempty"`
+	AddressHex       *string `protobuf:"bytes,2,opt,name=address_hex,json=addressHex" json:"address_hex,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
 }
 
 func (m *EthereumAddress) Reset()                    { *m = EthereumAddress{} }
@@ -1016,13 +1033,20 @@ func (m *EthereumAddress) String() string            { return proto.CompactTextS
 func (*EthereumAddress) ProtoMessage()               {}
 func (*EthereumAddress) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{24} }
 
-func (m *EthereumAddress) GetAddress() []byte {
+func (m *EthereumAddress) GetAddressBin() []byte {
 	if m != nil {
-		return m.Address
+		return m.AddressBin
 	}
 	return nil
 }
 
+func (m *EthereumAddress) GetAddressHex() string {
+	if m != nil && m.AddressHex != nil {
+		return *m.AddressHex
+	}
+	return ""
+}
+
 // *
 // Request: Request device to wipe all sensitive data and settings
 // @next ButtonRequest
@@ -2017,7 +2041,7 @@ type EthereumSignTx struct {
 	Value            []byte   `protobuf:"bytes,6,opt,name=value" json:"value,omitempty"`
 	DataInitialChunk []byte   `protobuf:"bytes,7,opt,name=data_initial_chunk,json=dataInitialChunk" json:"data_initial_chunk,omitempty"`
 	DataLength       *uint32  `protobuf:"varint,8,opt,name=data_length,json=dataLength" json:"data_length,omitempty"`
-	ChainId          *uint32  `protobuf:"varint,9,opt,name=chain_id,json=chainId" json:"chain_id,omitempty"`
+	ChainId          *uint64  `protobuf:"varint,9,opt,name=chain_id,json=chainId" json:"chain_id,omitempty"`
 	XXX_unrecognized []byte   `json:"-"`
 }
 
@@ -2082,7 +2106,7 @@ func (m *EthereumSignTx) GetDataLength() uint32 {
 	return 0
 }
 
-func (m *EthereumSignTx) GetChainId() uint32 {
+func (m *EthereumSignTx) GetChainId() uint64 {
 	if m != nil && m.ChainId != nil {
 		return *m.ChainId
 	}
@@ -2863,219 +2887,222 @@ func init() {
 func init() { proto.RegisterFile("messages.proto", fileDescriptor1) }
 
 var fileDescriptor1 = []byte{
-	// 3424 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x5a, 0xcb, 0x6f, 0xdc, 0x46,
-	0x9a, 0x5f, 0x76, 0xb7, 0xfa, 0xf1, 0x35, 0xbb, 0x55, 0xa2, 0x2d, 0xbb, 0x2d, 0x5b, 0xb6, 0x4c,
-	0xc9, 0xb6, 0x64, 0x27, 0xed, 0x44, 0x79, 0x6c, 0xd6, 0xbb, 0x79, 0xc8, 0x7a, 0xd8, 0xde, 0xd8,
-	0x8e, 0xc0, 0x56, 0x9c, 0xdb, 0x12, 0x14, 0x59, 0xea, 0xae, 0x55, 0x37, 0xc9, 0xf0, 0xa1, 0xa8,
-	0x7d, 0xd8, 0xeb, 0xee, 0x65, 0x81, 0xec, 0x69, 0x73, 0x1a, 0xe4, 0x36, 0x19, 0x04, 0x18, 0x0c,
-	0x30, 0x18, 0x60, 0x72, 0x9a, 0x3f, 0x60, 0xfe, 0x8b, 0x39, 0xce, 0x1f, 0x30, 0xe7, 0x41, 0x3d,
-	0x48, 0x16, 0x29, 0xb6, 0x6c, 0x27, 0xc0, 0x5c, 0x04, 0xd6, 0x57, 0xbf, 0xfe, 0xea, 0x7b, 0xd5,
-	0x57, 0x5f, 0x7d, 0x25, 0xe8, 0x4e, 0x70, 0x18, 0x5a, 0x43, 0x1c, 0xf6, 0xfd, 0xc0, 0x8b, 0xbc,
-	0xa5, 0x76, 0x34, 0xf5, 0x93, 0x81, 0xae, 0x02, 0x3c, 0x71, 0x49, 0x44, 0xac, 0x31, 0x79, 0x89,
-	0xf5, 0x0e, 0xb4, 0x1f, 0xe1, 0x68, 0x0f, 0x5b, 0x51, 0x1c, 0xe0, 0x50, 0xff, 0x69, 0x0e, 0x9a,
-	0xc9, 0x40, 0xbb, 0x04, 0xf5, 0x13, 0xec, 0x3a, 0x5e, 0xd0, 0x53, 0x56, 0x94, 0xf5, 0x96, 0x21,
-	0x46, 0xda, 0x2a, 0x74, 0x26, 0xd6, 0x7f, 0x7a, 0x81, 0x79, 0x82, 0x83, 0x90, 0x78, 0x6e, 0xaf,
-	0xb2, 0xa2, 0xac, 0x77, 0x0c, 0x95, 0x11, 0x5f, 0x70, 0x1a, 0x03, 0x11, 0x57, 0x02, 0x55, 0x05,
-	0x88, 0x12, 0x25, 0x90, 0x6f, 0x45, 0xf6, 0x28, 0x05, 0xd5, 0x38, 0x88, 0x11, 0x13, 0xd0, 0x1d,
-	0x98, 0x3f, 0xf4, 0xbc, 0x68, 0xec, 0x59, 0x0e, 0x0e, 0xcc, 0x89, 0xe7, 0xe0, 0xde, 0xdc, 0x8a,
-	0xb2, 0xde, 0x34, 0xba, 0x19, 0xf9, 0x99, 0xe7, 0x60, 0xed, 0x2a, 0xb4, 0x1c, 0x7c, 0x42, 0x6c,
-	0x6c, 0x12, 0xa7, 0x57, 0x67, 0x22, 0x37, 0x39, 0xe1, 0x89, 0xa3, 0xdd, 0x82, 0xae, 0x4f, 0x5c,
-	0x93, 0xda, 0x00, 0xdb, 0x11, 0x5d, 0xab, 0xc1, 0x98, 0x74, 0x7c, 0xe2, 0xee, 0xa7, 0x44, 0xed,
-	0x3d, 0x58, 0xf4, 0xad, 0x30, 0xf4, 0x47, 0x81, 0x15, 0x62, 0x19, 0xdd, 0x64, 0xe8, 0x8b, 0xd9,
-	0xa4, 0xf4, 0xa3, 0x25, 0x68, 0x8e, 0x2d, 0x77, 0x18, 0x5b, 0x43, 0xdc, 0x6b, 0xf1, 0x75, 0x93,
-	0xb1, 0x76, 0x11, 0xe6, 0xc6, 0xd6, 0x21, 0x1e, 0xf7, 0x80, 0x4d, 0xf0, 0x81, 0x76, 0x03, 0xe6,
-	0x6c, 0x8f, 0xb8, 0x61, 0xaf, 0xbd, 0x52, 0x5d, 0x6f, 0x6f, 0xb6, 0xfa, 0xdb, 0x1e, 0x71, 0x0f,
-	0xa6, 0x3e, 0x36, 0x38, 0x5d, 0x5b, 0x81, 0x36, 0x49, 0xbd, 0xe4, 0xf4, 0x54, 0xb6, 0xba, 0x4c,
-	0xa2, 0x8b, 0x06, 0xf8, 0x84, 0x30, 0xb3, 0x75, 0x56, 0x94, 0x75, 0xd5, 0x48, 0xc7, 0x05, 0x93,
-	0x8d, 0xac, 0x70, 0xd4, 0xeb, 0x32, 0x88, 0x64, 0xb2, 0xc7, 0x56, 0x38, 0xa2, 0x4c, 0xc8, 0xc4,
-	0xf7, 0x82, 0x08, 0x3b, 0xbd, 0x79, 0xb6, 0x46, 0x3a, 0xd6, 0x96, 0x01, 0xa8, 0xc5, 0x6c, 0xcb,
-	0x1e, 0x61, 0xa7, 0x87, 0xd8, 0x6c, 0xcb, 0x27, 0xee, 0x36, 0x23, 0x68, 0xf7, 0x60, 0x41, 0xb2,
-	0x94, 0x40, 0x2d, 0x30, 0x14, 0xca, 0x26, 0x04, 0x78, 0x03, 0xd0, 0x11, 0x09, 0x26, 0xdf, 0x58,
-	0x01, 0x35, 0x2a, 0x0e, 0xb1, 0x1b, 0xf5, 0x34, 0x86, 0x9d, 0x4f, 0xe8, 0xfb, 0x9c, 0xac, 0xdd,
-	0x04, 0xd5, 0xc5, 0xd8, 0x09, 0xcd, 0x43, 0xcb, 0x3e, 0x8e, 0xfd, 0xde, 0x05, 0xae, 0x3a, 0xa3,
-	0x3d, 0x64, 0x24, 0x6a, 0xd3, 0xa3, 0xb1, 0x35, 0x0c, 0x7b, 0x17, 0x59, 0xb8, 0xf0, 0x81, 0xde,
-	0x05, 0x75, 0x7b, 0x8c, 0xad, 0x60, 0x80, 0x43, 0x6a, 0x04, 0xfd, 0x7f, 0x14, 0xe8, 0x6c, 0xf9,
-	0xfe, 0x78, 0x3a, 0xc0, 0x51, 0x44, 0xdc, 0x61, 0x98, 0xf3, 0x93, 0x32, 0xcb, 0x4f, 0x15, 0xd9,
-	0x4f, 0xb7, 0xa0, 0x1b, 0xd3, 0x38, 0x48, 0xf5, 0x61, 0x61, 0xdc, 0x34, 0x3a, 0x71, 0x88, 0xf7,
-	0x53, 0xa2, 0x76, 0x1d, 0x60, 0xe4, 0x4d, 0x70, 0x68, 0x07, 0x18, 0xf3, 0x20, 0x56, 0x0d, 0x89,
-	0xa2, 0xeb, 0x00, 0x4c, 0x92, 0x3d, 0x2a, 0x68, 0x26, 0xbe, 0x22, 0x8b, 0xbf, 0x0a, 0xad, 0xed,
-	0x91, 0xe5, 0x0e, 0xf1, 0x3e, 0x71, 0xe9, 0xd6, 0x0b, 0xf0, 0xc4, 0x3b, 0xe1, 0x72, 0x36, 0x0d,
-	0x31, 0xd2, 0x7f, 0xa3, 0x40, 0x6d, 0x9f, 0xb8, 0x43, 0xad, 0x07, 0x0d, 0xb1, 0xc9, 0x85, 0x26,
-	0xc9, 0x90, 0xfa, 0xe5, 0x30, 0x8e, 0x22, 0x2f, 0x17, 0xeb, 0x15, 0xee, 0x17, 0x3e, 0x21, 0x45,
-	0xee, 0xd9, 0x5d, 0x51, 0x7d, 0xa3, 0x5d, 0x51, 0x9b, 0xbd, 0x2b, 0xf4, 0x55, 0x68, 0x0c, 0x62,
-	0xdb, 0xc6, 0x61, 0x38, 0x5b, 0x5a, 0x7d, 0x17, 0x1a, 0x7b, 0x16, 0x19, 0xc7, 0x01, 0xd6, 0x56,
-	0xa0, 0x66, 0xd3, 0xcd, 0x4d, 0x11, 0xdd, 0x4d, 0xb5, 0x2f, 0xe8, 0x6c, 0x57, 0xb0, 0x19, 0x99,
-	0x4d, 0x25, 0xcf, 0xe6, 0x73, 0xe8, 0x3c, 0x64, 0xba, 0x19, 0xf8, 0xeb, 0x18, 0x87, 0x91, 0x76,
-	0x3b, 0xc7, 0x4c, 0xeb, 0xe7, 0x66, 0x25, 0x96, 0x1a, 0xd4, 0x1c, 0x2b, 0xb2, 0x04, 0x3f, 0xf6,
-	0xad, 0xb7, 0xa1, 0xc5, 0xe1, 0x5b, 0xf6, 0xb1, 0xfe, 0x31, 0xa0, 0x7d, 0xe2, 0x3e, 0xb3, 0xa2,
-	0x80, 0x9c, 0x26, 0xcc, 0x37, 0xa0, 0x46, 0x33, 0xaa, 0x60, 0xbe, 0xd8, 0x2f, 0x02, 0x38, 0x7f,
-	0x0a, 0xd1, 0x57, 0x40, 0x4d, 0x67, 0xb7, 0xec, 0x63, 0x0d, 0x41, 0xd5, 0x27, 0x6e, 0x4f, 0x59,
-	0xa9, 0xac, 0xb7, 0x0c, 0xfa, 0xa9, 0x37, 0xa1, 0xbe, 0x6d, 0xb9, 0x36, 0x1e, 0xeb, 0x17, 0x60,
-	0x21, 0x8b, 0x29, 0xc1, 0x4a, 0xbf, 0x0f, 0x9d, 0x8c, 0x48, 0x39, 0x5c, 0x07, 0x90, 0xc2, 0x91,
-	0x33, 0x92, 0x28, 0xfa, 0x0a, 0xc0, 0x23, 0x1c, 0xed, 0xba, 0x51, 0xe0, 0xf9, 0x53, 0xaa, 0x5f,
-	0x48, 0x5e, 0x72, 0x5c, 0xc7, 0x60, 0xdf, 0xd4, 0x31, 0xc9, 0x74, 0x0f, 0x1a, 0x98, 0x7f, 0x32,
-	0x84, 0x6a, 0x24, 0x43, 0xfd, 0x57, 0x0a, 0xa8, 0x8f, 0x70, 0xb4, 0x1f, 0x1f, 0x8e, 0x89, 0xfd,
-	0x39, 0x9e, 0xd2, 0xec, 0x6a, 0x39, 0x4e, 0x80, 0xc3, 0xd0, 0xa4, 0xf2, 0x57, 0xd7, 0x3b, 0x46,
-	0x53, 0x10, 0x9e, 0x6b, 0xeb, 0x80, 0xb0, 0xed, 0x84, 0x96, 0x69, 0xc7, 0xc1, 0x09, 0x36, 0x5d,
-	0x6b, 0x92, 0xb8, 0xa8, 0xcb, 0xe8, 0xdb, 0x94, 0xfc, 0xdc, 0x9a, 0x60, 0xba, 0xbd, 0xc3, 0x91,
-	0xf7, 0x8d, 0xe9, 0x90, 0xd0, 0x1f, 0x5b, 0x53, 0x11, 0x6f, 0x6d, 0x4a, 0xdb, 0xe1, 0x24, 0x6d,
-	0x0d, 0x5a, 0x34, 0x09, 0x72, 0x2e, 0x34, 0xc2, 0x5a, 0x0f, 0x1a, 0x0f, 0x49, 0x44, 0x69, 0x46,
-	0x93, 0xfe, 0xa5, 0x8c, 0xf4, 0xcf, 0xa0, 0x95, 0x09, 0x77, 0x03, 0x6a, 0x2e, 0x77, 0x77, 0x65,
-	0xbd, 0xbd, 0xd9, 0xee, 0x3f, 0xde, 0x79, 0xee, 0x39, 0x22, 0x74, 0x5c, 0xe1, 0xe7, 0x53, 0x3f,
-	0x3e, 0x4c, 0xfc, 0x4c, 0xbf, 0xf5, 0xbf, 0x2a, 0xcc, 0x54, 0x5b, 0x5c, 0x89, 0xf3, 0x15, 0xcc,
-	0xc9, 0x54, 0x99, 0x21, 0xd3, 0xeb, 0x28, 0xf7, 0x01, 0x34, 0x27, 0xf1, 0x38, 0x22, 0x21, 0x19,
-	0x32, 0xdd, 0xda, 0x9b, 0x57, 0xfa, 0xcf, 0x04, 0xc1, 0xc0, 0x0e, 0xc6, 0x93, 0x81, 0x1d, 0x10,
-	0x9f, 0xc7, 0x50, 0x0a, 0xd5, 0x3e, 0x85, 0x76, 0xc8, 0xe8, 0x26, 0x8b, 0xbc, 0x39, 0x16, 0x79,
-	0xa8, 0xff, 0xc4, 0xf5, 0xe3, 0x28, 0xfb, 0xc1, 0x03, 0x75, 0xb0, 0xbf, 0xfb, 0x7c, 0x67, 0x6b,
-	0x67, 0xc7, 0xd8, 0x1d, 0x0c, 0x0c, 0x08, 0xd3, 0x19, 0xfd, 0x00, 0xb4, 0xdd, 0x68, 0x84, 0x03,
-	0x1c, 0x4f, 0x5e, 0x57, 0xe7, 0xa2, 0x36, 0x95, 0x33, 0xda, 0xd0, 0x50, 0x4a, 0x58, 0xf5, 0xa0,
-	0x21, 0x7e, 0x29, 0x82, 0x32, 0x19, 0xea, 0xf7, 0x60, 0x3e, 0x59, 0x7a, 0x06, 0x58, 0xcd, 0xc0,
-	0x2a, 0xc0, 0x57, 0xc4, 0xc7, 0x3b, 0xec, 0xdc, 0xd6, 0xff, 0xaf, 0x02, 0xf0, 0xd4, 0xb3, 0x1c,
-	0x3e, 0xa4, 0x09, 0x7c, 0xe2, 0xe2, 0x89, 0xe7, 0x12, 0x3b, 0x49, 0xe0, 0xc9, 0x38, 0x0d, 0x81,
-	0x0a, 0x33, 0x6a, 0x49, 0x08, 0x88, 0xad, 0x57, 0x65, 0xbf, 0xa3, 0x9f, 0x3f, 0x2b, 0xad, 0x69,
-	0xab, 0xd2, 0x21, 0x32, 0xc7, 0x03, 0x01, 0xbb, 0xc3, 0x31, 0x09, 0x47, 0x65, 0xa7, 0x49, 0x5d,
-	0x3e, 0x4d, 0x56, 0xa1, 0x13, 0x1e, 0x13, 0xdf, 0xb4, 0x47, 0xd8, 0x3e, 0x0e, 0xe3, 0x89, 0x28,
-	0x41, 0x54, 0x4a, 0xdc, 0x16, 0x34, 0xed, 0x06, 0xb4, 0xe3, 0xcd, 0x23, 0xd3, 0xf6, 0x62, 0x37,
-	0xc2, 0x01, 0xab, 0x3b, 0x3a, 0x06, 0xc4, 0x9b, 0x47, 0xdb, 0x9c, 0xa2, 0xff, 0xb6, 0x02, 0x6d,
-	0x03, 0x87, 0x38, 0x12, 0x46, 0xb9, 0x05, 0x5d, 0xe1, 0x21, 0x33, 0xb0, 0x5c, 0xc7, 0x9b, 0x88,
-	0x33, 0xa3, 0x23, 0xa8, 0x06, 0x23, 0x6a, 0x37, 0xa0, 0x19, 0x46, 0x01, 0x76, 0x87, 0xd1, 0x88,
-	0x17, 0x6c, 0x0f, 0xaa, 0x9b, 0x1f, 0x7c, 0x68, 0xa4, 0xc4, 0xd9, 0xd6, 0xa8, 0x9e, 0x63, 0x8d,
-	0xb3, 0x07, 0x48, 0xad, 0xec, 0x00, 0xf9, 0x05, 0x46, 0x2b, 0xd8, 0xa3, 0x51, 0xb4, 0x07, 0x05,
-	0x30, 0xab, 0x8a, 0x7a, 0x81, 0x17, 0x6a, 0x40, 0x49, 0xbc, 0x5c, 0xa0, 0x85, 0x01, 0xff, 0x12,
-	0x41, 0x85, 0xa0, 0x2b, 0xf2, 0x5f, 0x92, 0x64, 0x6f, 0x03, 0x08, 0x0a, 0xcd, 0xb0, 0xb9, 0xa4,
-	0xa8, 0xc8, 0x49, 0xf1, 0x4f, 0x15, 0xe8, 0x1a, 0xd8, 0xf6, 0x4e, 0x70, 0x30, 0x15, 0xd6, 0x5f,
-	0x06, 0xf8, 0xc6, 0x0b, 0x1c, 0x2e, 0x9f, 0x38, 0xd1, 0x5b, 0x94, 0xc2, 0xc4, 0x9b, 0x6d, 0xd4,
-	0xca, 0x1b, 0x19, 0xb5, 0xfa, 0x2a, 0xa3, 0xd6, 0x5e, 0x69, 0xd4, 0x39, 0xd9, 0xa8, 0x1b, 0x80,
-	0xb0, 0x7b, 0xe4, 0x05, 0x36, 0x36, 0xa9, 0xac, 0x63, 0x12, 0x46, 0xcc, 0xea, 0x4d, 0x63, 0x5e,
-	0xd0, 0xbf, 0x12, 0x64, 0x9a, 0x39, 0x59, 0xca, 0xe1, 0x81, 0xc8, 0xbe, 0x8b, 0x3e, 0x69, 0x9d,
-	0xf1, 0xc9, 0x65, 0x68, 0x38, 0xc1, 0xd4, 0x0c, 0x62, 0x97, 0xd5, 0xbd, 0x4d, 0xa3, 0xee, 0x04,
-	0x53, 0x23, 0x76, 0xf5, 0xf7, 0xa0, 0x4d, 0x39, 0x27, 0x27, 0xe9, 0x5a, 0xee, 0x24, 0x45, 0x7d,
-	0x69, 0x4e, 0x3a, 0x44, 0x97, 0xa1, 0x41, 0x27, 0xa8, 0x6f, 0x34, 0xa8, 0x51, 0x81, 0x45, 0x8a,
-	0x61, 0xdf, 0xfa, 0x8f, 0x0a, 0xb4, 0x07, 0x64, 0xe8, 0x3e, 0x13, 0x15, 0xd0, 0xb9, 0x49, 0x2d,
-	0x57, 0x43, 0xb0, 0xcc, 0x93, 0x14, 0x4e, 0xb9, 0x14, 0x5f, 0x9d, 0x95, 0xe2, 0x0b, 0x89, 0xb8,
-	0xf6, 0xc6, 0x89, 0xf8, 0xbf, 0x15, 0xe8, 0xbc, 0xc0, 0x01, 0x39, 0x9a, 0x26, 0xf2, 0xe6, 0x92,
-	0xa1, 0x22, 0x65, 0x4e, 0xed, 0x1a, 0xb4, 0x42, 0x32, 0x74, 0xd9, 0x7d, 0x8c, 0x45, 0x8c, 0x6a,
-	0x64, 0x04, 0x59, 0x95, 0x2a, 0x8f, 0xd3, 0x52, 0x55, 0x66, 0x9e, 0xa0, 0xff, 0x0e, 0x48, 0x88,
-	0x30, 0x90, 0x79, 0xfe, 0x1c, 0x59, 0xf4, 0x1f, 0x14, 0xba, 0xa9, 0xec, 0x60, 0xea, 0x47, 0x89,
-	0x5a, 0x97, 0xa0, 0xee, 0xc7, 0x87, 0xc7, 0x38, 0xd9, 0x45, 0x62, 0x54, 0xac, 0xe2, 0x24, 0xb1,
-	0x6f, 0x82, 0x9a, 0x64, 0x32, 0xcf, 0x1d, 0xa7, 0xc7, 0xa7, 0xa0, 0x7d, 0xe1, 0x8e, 0x0b, 0x55,
-	0x48, 0xed, 0xbc, 0x43, 0x7a, 0x6e, 0x96, 0xda, 0x2f, 0x00, 0x09, 0x49, 0xb1, 0x93, 0xc8, 0x7a,
-	0x11, 0xe6, 0x5c, 0xcf, 0xb5, 0xb1, 0x10, 0x95, 0x0f, 0xce, 0x91, 0x54, 0x83, 0xda, 0x68, 0x62,
-	0xd9, 0xc2, 0xee, 0xec, 0x5b, 0xff, 0x1a, 0xba, 0x3b, 0x38, 0x67, 0x81, 0x73, 0x03, 0x31, 0x5d,
-	0xb2, 0x32, 0x63, 0xc9, 0x6a, 0xf9, 0x92, 0x35, 0x69, 0xc9, 0x3d, 0x40, 0x62, 0xc9, 0x4c, 0x95,
-	0x42, 0xad, 0x2d, 0x71, 0x90, 0x7c, 0x5b, 0xc9, 0xf9, 0x56, 0xff, 0xb3, 0x02, 0xdd, 0x6d, 0xe2,
-	0x8f, 0x70, 0xf0, 0x39, 0x9e, 0xbe, 0xb0, 0xc6, 0xf1, 0x2b, 0x64, 0x47, 0x50, 0xa5, 0x7e, 0xe5,
-	0x5c, 0xe8, 0x27, 0xd5, 0xe6, 0x84, 0xfe, 0x4e, 0x48, 0xcd, 0x07, 0x3c, 0x93, 0x32, 0xf9, 0xc4,
-	0xb1, 0x90, 0x0c, 0xb5, 0x35, 0xe8, 0x5a, 0xe1, 0xb1, 0xe9, 0xb9, 0x66, 0x02, 0xe0, 0x77, 0x7a,
-	0xd5, 0x0a, 0x8f, 0xbf, 0x70, 0x77, 0xcf, 0xa0, 0x1c, 0xae, 0xa6, 0x48, 0x52, 0x1c, 0x25, 0x54,
-	0xd7, 0xba, 0x50, 0x21, 0x27, 0xec, 0x60, 0x50, 0x8d, 0x0a, 0x39, 0xd1, 0xd7, 0x01, 0x71, 0x65,
-	0xb0, 0x93, 0xaa, 0x93, 0xca, 0xa7, 0x48, 0xf2, 0xe9, 0xff, 0x05, 0xdd, 0xdd, 0x30, 0x22, 0x13,
-	0x2b, 0xc2, 0x07, 0xa7, 0x03, 0xf2, 0x12, 0xd3, 0x23, 0xda, 0x8b, 0x23, 0x3f, 0x8e, 0xc2, 0x34,
-	0xa3, 0xd3, 0xc2, 0x59, 0x15, 0x44, 0x9e, 0xd4, 0x6f, 0x82, 0x4a, 0x5c, 0x09, 0x53, 0x61, 0x98,
-	0x36, 0xa7, 0x71, 0xc8, 0x6b, 0x25, 0x13, 0xfd, 0x26, 0xd4, 0xc5, 0xba, 0x97, 0xa1, 0x11, 0x9d,
-	0x9a, 0xa2, 0x54, 0xa7, 0xd9, 0xb4, 0x1e, 0xb1, 0x09, 0xfd, 0xf7, 0x0a, 0xd4, 0xe9, 0xf6, 0x3c,
-	0x38, 0xfd, 0xc7, 0xca, 0xa6, 0x5d, 0x85, 0x46, 0xae, 0x2b, 0xf3, 0x40, 0x79, 0xd7, 0x48, 0x28,
-	0xda, 0x75, 0x68, 0x8d, 0x3d, 0xfb, 0xd8, 0x8c, 0x88, 0xd8, 0x69, 0x9d, 0x07, 0xca, 0x3b, 0x46,
-	0x93, 0xd2, 0x0e, 0xc8, 0x04, 0xeb, 0x7f, 0x53, 0x40, 0x1d, 0x90, 0x89, 0x3f, 0xc6, 0x42, 0xf6,
-	0x35, 0xa8, 0x73, 0x11, 0x58, 0x2c, 0xb5, 0x37, 0xd5, 0xfe, 0xc1, 0x29, 0xcb, 0x99, 0x2c, 0xcd,
-	0x8b, 0x39, 0xed, 0x0e, 0x34, 0x84, 0x32, 0xbd, 0x0a, 0x83, 0x75, 0xfa, 0x07, 0xa7, 0x5f, 0x30,
-	0x0a, 0xc3, 0x25, 0xb3, 0xda, 0xfb, 0xa0, 0x46, 0x81, 0xe5, 0x86, 0x16, 0x3b, 0x09, 0xc3, 0x5e,
-	0x95, 0xa1, 0x51, 0xff, 0x20, 0x23, 0xb2, 0x1f, 0xe4, 0x50, 0xaf, 0x97, 0x16, 0x65, 0xc5, 0xe7,
-	0xce, 0x57, 0xbc, 0x7e, 0x56, 0xf1, 0x5f, 0x2b, 0xd0, 0x3a, 0x48, 0x2f, 0x8a, 0xf7, 0x41, 0x0d,
-	0xf8, 0xa7, 0x29, 0x1d, 0x73, 0x6a, 0x5f, 0x3e, 0xe2, 0xda, 0x41, 0x36, 0xd0, 0xee, 0x43, 0xc3,
-	0xc1, 0x91, 0x45, 0xc6, 0xa1, 0xa8, 0x63, 0x17, 0xfb, 0x29, 0xb7, 0x1d, 0x3e, 0xc1, 0x0d, 0x21,
-	0x50, 0xda, 0x47, 0x00, 0x21, 0x0e, 0x92, 0x36, 0x51, 0x95, 0xfd, 0xa6, 0x97, 0xfd, 0x66, 0x90,
-	0xce, 0xb1, 0x9f, 0x49, 0x58, 0x7d, 0x03, 0xe6, 0x0e, 0xd8, 0x95, 0x74, 0x05, 0x2a, 0xd1, 0x29,
-	0x13, 0xad, 0xcc, 0x82, 0x95, 0xe8, 0x54, 0xff, 0xdf, 0x0a, 0x74, 0x93, 0x0a, 0x5e, 0xf8, 0xf3,
-	0x67, 0xa4, 0xb6, 0xab, 0xd0, 0x1a, 0x5a, 0xa1, 0xe9, 0x07, 0xc4, 0x4e, 0xd2, 0x44, 0x73, 0x68,
-	0x85, 0xfb, 0x74, 0x9c, 0x4c, 0x8e, 0xc9, 0x84, 0x44, 0x22, 0xc5, 0xd1, 0xc9, 0xa7, 0x74, 0x4c,
-	0x37, 0x78, 0xe4, 0x31, 0x67, 0xa8, 0x46, 0x25, 0xf2, 0xb2, 0xcd, 0x5c, 0x97, 0x93, 0xcd, 0x5b,
-	0xa0, 0xd1, 0xeb, 0xbb, 0x29, 0x9a, 0x64, 0xa6, 0x3d, 0x8a, 0xdd, 0x63, 0x91, 0x16, 0x10, 0x9d,
-	0x11, 0x6d, 0xcf, 0x6d, 0x4a, 0xa7, 0x25, 0x0c, 0x43, 0x8f, 0x79, 0x45, 0x2c, 0xca, 0x6c, 0x4a,
-	0x7a, 0xca, 0xcb, 0xe1, 0x2b, 0xd0, 0xb4, 0x47, 0x16, 0x71, 0x4d, 0xe2, 0x88, 0x02, 0xa7, 0xc1,
-	0xc6, 0x4f, 0x1c, 0xfd, 0xff, 0x15, 0x58, 0x48, 0xec, 0x91, 0x39, 0xbb, 0xc0, 0x51, 0x39, 0xc3,
-	0x91, 0x16, 0xaa, 0xc9, 0x81, 0x69, 0x9e, 0x88, 0xae, 0x29, 0xa4, 0xa4, 0x17, 0x79, 0x40, 0x20,
-	0x6c, 0x94, 0x01, 0x8c, 0x3c, 0x20, 0x4c, 0x1a, 0x4d, 0x29, 0x69, 0xa0, 0xf7, 0xa1, 0x93, 0x09,
-	0x46, 0x9d, 0xbb, 0x0c, 0x4c, 0x02, 0x61, 0x0c, 0x9e, 0xfc, 0x5a, 0x94, 0xc2, 0xac, 0xa0, 0x3f,
-	0x85, 0x0b, 0xb2, 0x63, 0x7f, 0x59, 0x05, 0xa5, 0x13, 0x58, 0x4c, 0xb8, 0x9d, 0x5b, 0xe1, 0xa8,
-	0xbf, 0xb8, 0xc2, 0xd1, 0x0d, 0xe8, 0x25, 0x4b, 0xbd, 0xaa, 0x86, 0x79, 0xdd, 0xd5, 0xf4, 0x9f,
-	0x58, 0xd2, 0x1a, 0xba, 0x4f, 0x1c, 0xec, 0x46, 0x24, 0x9a, 0x6a, 0x1b, 0xd0, 0x24, 0xe2, 0x5b,
-	0xec, 0x8f, 0x4e, 0x3f, 0x99, 0xe4, 0xf7, 0x73, 0x92, 0x41, 0x91, 0x3d, 0xb2, 0xc6, 0xd4, 0xf7,
-	0xd8, 0x1c, 0x11, 0xc7, 0xc1, 0xae, 0x58, 0x60, 0x3e, 0xa5, 0x3f, 0x66, 0xe4, 0x3c, 0xf4, 0x84,
-	0x84, 0xb1, 0x35, 0x16, 0x97, 0xd2, 0x0c, 0xfa, 0x82, 0x91, 0x4b, 0xdb, 0x2a, 0xb5, 0xb2, 0xb6,
-	0x8a, 0x3e, 0x84, 0x2e, 0x15, 0x1d, 0x3b, 0xa9, 0xf0, 0xb3, 0x2b, 0xb9, 0x65, 0x00, 0x9f, 0x75,
-	0x4e, 0xcc, 0xe4, 0x10, 0x57, 0x8d, 0x96, 0x9f, 0xf6, 0x52, 0x72, 0x46, 0xaa, 0x16, 0x8d, 0xf4,
-	0xad, 0x02, 0x0b, 0x8f, 0x70, 0xb4, 0xbb, 0xbd, 0xf3, 0x58, 0x34, 0x5a, 0xe9, 0x6f, 0xde, 0xc0,
-	0x52, 0xb7, 0x61, 0xde, 0xc7, 0x38, 0x30, 0xcf, 0x88, 0xd0, 0xa1, 0xe4, 0xac, 0xa5, 0x53, 0xa6,
-	0x7b, 0xb5, 0x54, 0xf7, 0x77, 0xa1, 0x5b, 0x10, 0x87, 0xee, 0x13, 0x3e, 0x32, 0xb3, 0xfa, 0x13,
-	0xc2, 0x14, 0xa0, 0xbf, 0x03, 0x9d, 0x01, 0x8e, 0xbe, 0xdc, 0xdc, 0x93, 0x2e, 0x91, 0xf2, 0x8d,
-	0x46, 0x39, 0x73, 0xeb, 0xbe, 0x03, 0x9d, 0x3d, 0xd1, 0xa9, 0xde, 0x65, 0x3d, 0xdf, 0x4b, 0x50,
-	0xcf, 0xed, 0x74, 0x31, 0xd2, 0xb7, 0x60, 0x3e, 0x01, 0x26, 0x99, 0xe1, 0x12, 0xd4, 0xbd, 0xa3,
-	0xa3, 0x10, 0x27, 0xf7, 0x43, 0x31, 0x92, 0x58, 0x54, 0x72, 0x2c, 0x3e, 0x81, 0x6e, 0xc2, 0xe2,
-	0x4b, 0x7f, 0xec, 0x59, 0x0e, 0x75, 0xa6, 0x6f, 0x4d, 0xe9, 0x67, 0xd2, 0x2f, 0x11, 0x43, 0x56,
-	0x16, 0x5a, 0xe1, 0x48, 0xd8, 0x90, 0x7d, 0xeb, 0x6b, 0xd0, 0x1c, 0xe0, 0xf1, 0xd1, 0x01, 0x5d,
-	0x3b, 0xf7, 0x4b, 0x45, 0xfa, 0xa5, 0x7e, 0x17, 0x16, 0x76, 0xf0, 0x61, 0x3c, 0x7c, 0x4a, 0xdc,
-	0xe3, 0x1d, 0x6c, 0xf3, 0x97, 0x83, 0x45, 0xa8, 0x4f, 0x71, 0x68, 0xba, 0x1e, 0x5b, 0xa7, 0x69,
-	0xcc, 0x4d, 0x71, 0xf8, 0xdc, 0xd3, 0x2f, 0x48, 0xd8, 0x47, 0x38, 0x1a, 0x44, 0x56, 0x84, 0xf5,
-	0xbf, 0x54, 0x68, 0xc5, 0x2b, 0xa8, 0x8c, 0xc4, 0x34, 0xb2, 0xa6, 0x5e, 0x1c, 0x25, 0x35, 0x3f,
-	0x1f, 0x25, 0xbd, 0x97, 0x4a, 0xd6, 0x7b, 0xb9, 0x04, 0xf5, 0x09, 0xeb, 0x8a, 0x0a, 0xa7, 0x8a,
-	0x51, 0xae, 0xc5, 0x53, 0x9b, 0xd1, 0xe2, 0x99, 0x9b, 0xd5, 0xe2, 0x99, 0x79, 0xdb, 0xae, 0x9f,
-	0x73, 0xdb, 0x5e, 0x06, 0x08, 0x70, 0x88, 0x23, 0x76, 0x13, 0x66, 0xe7, 0x45, 0xcb, 0x68, 0x31,
-	0x0a, 0xbd, 0x74, 0xd2, 0xaa, 0x8b, 0x4f, 0x27, 0x3d, 0x81, 0x26, 0xd3, 0x4c, 0x65, 0xc4, 0xa4,
-	0x8f, 0xfa, 0x16, 0x68, 0x81, 0xe8, 0x0b, 0x98, 0x47, 0xd6, 0x31, 0xbf, 0x55, 0x8b, 0xb7, 0x20,
-	0x94, 0xcc, 0xec, 0x59, 0xc7, 0xec, 0x5a, 0xad, 0xdd, 0x85, 0x85, 0x14, 0xcd, 0x9a, 0x07, 0xbe,
-	0x17, 0xb2, 0x7b, 0x72, 0xc7, 0x98, 0x4f, 0x26, 0x28, 0x70, 0xdf, 0x0b, 0xf5, 0x79, 0xe8, 0x48,
-	0x36, 0xf6, 0x7c, 0x7d, 0x1f, 0xd4, 0x94, 0xf0, 0xd4, 0x1b, 0xb2, 0x0b, 0x3e, 0x3e, 0xc1, 0xe3,
-	0xe4, 0x35, 0x81, 0x0d, 0xa8, 0x79, 0x0f, 0x63, 0xfb, 0x18, 0x47, 0xc2, 0xe6, 0x62, 0xc4, 0x6e,
-	0xf3, 0xf8, 0x34, 0x12, 0x46, 0x67, 0xdf, 0xfa, 0x23, 0xb8, 0x90, 0x72, 0x7c, 0x86, 0x27, 0x5e,
-	0x30, 0x35, 0x30, 0x8f, 0x39, 0x39, 0x81, 0x74, 0xb2, 0x04, 0x32, 0x2b, 0x6e, 0x37, 0x60, 0xbe,
-	0xc0, 0x88, 0xb9, 0x99, 0x7d, 0x25, 0x01, 0xc1, 0x47, 0xfa, 0x7f, 0xc0, 0xc5, 0x02, 0xf4, 0xab,
-	0x80, 0x44, 0xf8, 0xfc, 0x45, 0x05, 0xa7, 0x8a, 0xcc, 0x49, 0xbc, 0xa6, 0x84, 0x23, 0x71, 0x5b,
-	0xe4, 0x03, 0xfd, 0x6d, 0x49, 0xa7, 0x3d, 0x4a, 0x49, 0x37, 0x6d, 0x88, 0xed, 0xc8, 0x4b, 0x76,
-	0xb8, 0x18, 0xdd, 0xfd, 0x71, 0x11, 0xda, 0xe2, 0x1c, 0x61, 0x75, 0xd8, 0x0a, 0x5c, 0x92, 0x86,
-	0x66, 0xf6, 0x60, 0x8a, 0xfe, 0x69, 0xa9, 0xf6, 0xed, 0x1f, 0x7a, 0x8a, 0xb6, 0x94, 0x5e, 0x9e,
-	0x19, 0x62, 0x9f, 0xb8, 0x43, 0xa4, 0x88, 0xb9, 0x65, 0xb8, 0x20, 0xcf, 0x89, 0x57, 0x10, 0x54,
-	0x59, 0xaa, 0x7d, 0x57, 0x32, 0x2d, 0xde, 0x39, 0x50, 0x55, 0x4c, 0xdf, 0x80, 0x45, 0x79, 0x3a,
-	0x7d, 0x14, 0x42, 0x35, 0xc1, 0xbe, 0x20, 0x5c, 0xd6, 0x2e, 0x45, 0x73, 0x02, 0x71, 0x07, 0xae,
-	0xe4, 0x56, 0x90, 0x13, 0x17, 0xaa, 0x2f, 0x35, 0x29, 0xe8, 0x8f, 0x14, 0xb8, 0x0e, 0x4b, 0x65,
-	0x40, 0x9e, 0x75, 0x50, 0x43, 0x42, 0x6e, 0xc0, 0xd5, 0x32, 0xa4, 0x48, 0x71, 0xa8, 0xb9, 0xd4,
-	0xfc, 0x2e, 0x81, 0x16, 0xe4, 0xcb, 0x5e, 0x23, 0x50, 0xab, 0xdc, 0x40, 0xc9, 0x34, 0x08, 0x0b,
-	0xe8, 0xd0, 0x2b, 0x30, 0x48, 0x8f, 0x05, 0xd4, 0x16, 0x2c, 0x0a, 0x56, 0xca, 0x00, 0xaa, 0x60,
-	0x52, 0x90, 0x22, 0xeb, 0x22, 0xa3, 0x8e, 0x60, 0x71, 0x13, 0x2e, 0xcb, 0x08, 0xa9, 0xa7, 0x8a,
-	0xba, 0x02, 0x72, 0x0d, 0xb4, 0x9c, 0x27, 0x59, 0xf1, 0x8b, 0xe6, 0xc5, 0xec, 0x5a, 0x5e, 0x4e,
-	0xf9, 0xc2, 0x83, 0xd0, 0x52, 0x9d, 0x62, 0x9a, 0x8a, 0x76, 0x1d, 0x2e, 0xe6, 0x2c, 0x27, 0x9e,
-	0xd7, 0xd1, 0x82, 0x10, 0xf4, 0x36, 0x5c, 0x2b, 0x44, 0x52, 0xee, 0x31, 0x09, 0x69, 0x29, 0xae,
-	0x57, 0x8a, 0xdb, 0xb2, 0x8f, 0xd1, 0x05, 0xee, 0xa9, 0xdf, 0x95, 0xc8, 0xcc, 0x1f, 0x97, 0xd0,
-	0xc5, 0x72, 0xbb, 0xa5, 0xe5, 0x2b, 0x5a, 0x14, 0xcb, 0x5c, 0x85, 0x85, 0x3c, 0x80, 0xf2, 0xbf,
-	0x94, 0x6a, 0x9c, 0x8b, 0x97, 0x7c, 0xcf, 0x00, 0x5d, 0x16, 0xa8, 0x82, 0xff, 0xe4, 0x57, 0x59,
-	0xd4, 0x13, 0x98, 0xd5, 0x7c, 0x88, 0xe6, 0x1e, 0x6a, 0xd1, 0x95, 0x72, 0x50, 0xee, 0x11, 0x0f,
-	0x2d, 0x09, 0x81, 0x57, 0xf3, 0x1a, 0xa5, 0x4f, 0x77, 0xe8, 0xaa, 0x64, 0x94, 0x42, 0x34, 0x64,
-	0xaf, 0xb1, 0xe8, 0x5a, 0xf9, 0xae, 0xca, 0x1e, 0x49, 0xd0, 0x72, 0x79, 0xd4, 0x26, 0xd3, 0xd7,
-	0xd3, 0xa8, 0xcd, 0xf9, 0x39, 0x39, 0x81, 0xd1, 0x8a, 0xb4, 0x8b, 0x0a, 0x96, 0x91, 0xdb, 0xd2,
-	0x48, 0x2f, 0xb7, 0x71, 0xbe, 0x55, 0x8d, 0x56, 0xcb, 0xc3, 0x3b, 0x6b, 0x5f, 0xa3, 0xb5, 0xf2,
-	0xf0, 0x96, 0xea, 0x7b, 0x74, 0xbb, 0xdc, 0xbe, 0xb9, 0xa2, 0x1d, 0xdd, 0x11, 0xa0, 0x42, 0x7c,
-	0x16, 0xcb, 0x6d, 0xb4, 0x2e, 0x24, 0xba, 0x03, 0xcb, 0xb9, 0xf8, 0x2c, 0x3e, 0x65, 0xa2, 0x8d,
-	0x14, 0x78, 0xa5, 0x1c, 0x48, 0xa5, 0xbf, 0x2b, 0x39, 0xed, 0x76, 0xc1, 0x12, 0xb9, 0x56, 0x0d,
-	0xba, 0x27, 0xed, 0x30, 0x2d, 0x1f, 0xb2, 0x6c, 0xfe, 0xad, 0xa5, 0xfa, 0x77, 0x7c, 0xbe, 0x60,
-	0xd1, 0x7c, 0x07, 0x1f, 0xbd, 0x5d, 0x6e, 0x2f, 0xa9, 0x15, 0x8d, 0xfa, 0xe5, 0x99, 0x5b, 0x34,
-	0xa5, 0xd1, 0xfd, 0x72, 0x4b, 0x15, 0x9b, 0x50, 0xe8, 0x9d, 0x74, 0x27, 0x17, 0x3c, 0x2c, 0x77,
-	0x0d, 0xd1, 0xbb, 0xa9, 0x5e, 0xeb, 0x79, 0x7e, 0xc5, 0xae, 0x25, 0xda, 0x4c, 0x35, 0x2c, 0x70,
-	0xcc, 0xf7, 0x21, 0xd1, 0x7b, 0xb3, 0x38, 0x16, 0x9b, 0x87, 0xe8, 0xfd, 0x94, 0xa3, 0x5e, 0xcc,
-	0x6d, 0xd9, 0xbd, 0x08, 0x7d, 0x50, 0x1e, 0xa9, 0xf9, 0x0b, 0x08, 0xfa, 0x50, 0x68, 0x5b, 0xb0,
-	0xab, 0xf4, 0xef, 0x46, 0xe8, 0x9f, 0x05, 0xa3, 0x75, 0xb8, 0x9e, 0x53, 0xf4, 0xcc, 0x43, 0x25,
-	0xfa, 0x48, 0x20, 0x6f, 0xe5, 0x8f, 0xa1, 0xc2, 0xbb, 0x22, 0xfa, 0x17, 0xb1, 0x66, 0x71, 0x0f,
-	0xe5, 0x9a, 0x17, 0xe8, 0x41, 0x7a, 0x4c, 0x2e, 0x97, 0xa1, 0xb2, 0x9c, 0xf8, 0xaf, 0x69, 0x8a,
-	0xb9, 0x52, 0x0e, 0xa4, 0xde, 0xff, 0xb7, 0x72, 0x6e, 0x67, 0x2e, 0x49, 0xe8, 0xe3, 0x19, 0x1b,
-	0x3c, 0x8f, 0xfa, 0xa4, 0x7c, 0xcd, 0xdc, 0x75, 0x05, 0x7d, 0x2a, 0x58, 0x6d, 0xc0, 0x8d, 0x59,
-	0x7a, 0x26, 0x2e, 0xfd, 0x4c, 0x40, 0xef, 0xc1, 0xcd, 0x32, 0x68, 0x7e, 0xcf, 0x6f, 0x09, 0x70,
-	0x1f, 0xd6, 0xca, 0xc0, 0x67, 0xf6, 0xfe, 0x43, 0x21, 0xec, 0xbd, 0xbc, 0xee, 0x67, 0xee, 0x15,
-	0xc8, 0x59, 0x6a, 0x7e, 0x9f, 0x6c, 0xeb, 0x3b, 0x33, 0xc0, 0xc9, 0xc5, 0x02, 0xe1, 0xa5, 0xda,
-	0xf7, 0x25, 0x86, 0xca, 0xdf, 0x35, 0xd0, 0xd1, 0x52, 0xed, 0x87, 0x12, 0x43, 0xe5, 0xaa, 0x65,
-	0x34, 0x14, 0xac, 0x0a, 0xe1, 0x2c, 0x57, 0xd0, 0x68, 0x24, 0x18, 0x15, 0x8c, 0x59, 0x52, 0x13,
-	0x23, 0x57, 0xb0, 0x2b, 0x84, 0x61, 0x01, 0x8a, 0x3c, 0xc1, 0xf1, 0x2e, 0xac, 0x9c, 0x03, 0x63,
-	0x15, 0x2f, 0xf2, 0x05, 0xcb, 0x59, 0xab, 0x67, 0xd5, 0x2b, 0xfa, 0x9a, 0x43, 0x1f, 0xbe, 0x0f,
-	0xab, 0xb6, 0x37, 0xe9, 0x87, 0x56, 0xe4, 0x85, 0x23, 0x32, 0xb6, 0x0e, 0xc3, 0x7e, 0x14, 0xe0,
-	0x97, 0x5e, 0xd0, 0x1f, 0x93, 0x43, 0xfe, 0x6f, 0x7e, 0x87, 0xf1, 0xd1, 0xc3, 0xce, 0x01, 0x23,
-	0x0a, 0xae, 0x7f, 0x0f, 0x00, 0x00, 0xff, 0xff, 0x2a, 0xe4, 0xc0, 0x85, 0x16, 0x28, 0x00, 0x00,
+	// 3467 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x5a, 0x4b, 0x73, 0xdc, 0x48,
+	0x72, 0x36, 0xba, 0x9b, 0xfd, 0xc8, 0x7e, 0xb0, 0x08, 0x89, 0x9a, 0x16, 0x29, 0x4a, 0x14, 0xc8,
+	0x91, 0x48, 0x69, 0xb6, 0xa5, 0xe1, 0xcc, 0xac, 0xd7, 0xb2, 0xf7, 0x41, 0xf1, 0x21, 0xc9, 0x7a,
+	0x0c, 0x03, 0xcd, 0xd5, 0xdc, 0x8c, 0x00, 0x81, 0x62, 0x77, 0x99, 0xdd, 0x00, 0x06, 0x0f, 0x0e,
+	0x5b, 0x07, 0x5f, 0xed, 0x8b, 0x23, 0xd6, 0x27, 0xcf, 0xc9, 0xb1, 0x37, 0xaf, 0x63, 0x23, 0x1c,
+	0x8e, 0x70, 0x38, 0xc2, 0x3e, 0xf9, 0x07, 0xf8, 0xe0, 0xff, 0xe0, 0xa3, 0x7f, 0x80, 0xcf, 0x8e,
+	0x7a, 0x01, 0x05, 0x10, 0x4d, 0x49, 0x33, 0x11, 0x7b, 0x61, 0x20, 0xb3, 0xbe, 0xce, 0xca, 0xcc,
+	0xca, 0xca, 0xca, 0xca, 0x22, 0xf4, 0xa6, 0x38, 0x8a, 0xec, 0x11, 0x8e, 0x06, 0x41, 0xe8, 0xc7,
+	0xfe, 0x4a, 0x3b, 0x9e, 0x05, 0x92, 0x30, 0x3a, 0x00, 0x2f, 0x3c, 0x12, 0x13, 0x7b, 0x42, 0xde,
+	0x61, 0xa3, 0x0b, 0xed, 0x67, 0x38, 0x3e, 0xc4, 0x76, 0x9c, 0x84, 0x38, 0x32, 0xfe, 0x7b, 0x01,
+	0x9a, 0x92, 0xd0, 0x6f, 0x40, 0xfd, 0x1c, 0x7b, 0xae, 0x1f, 0xf6, 0xb5, 0x75, 0x6d, 0xab, 0x65,
+	0x0a, 0x4a, 0xdf, 0x80, 0xee, 0xd4, 0xfe, 0x4b, 0x3f, 0xb4, 0xce, 0x71, 0x18, 0x11, 0xdf, 0xeb,
+	0x57, 0xd6, 0xb5, 0xad, 0xae, 0xd9, 0x61, 0xcc, 0xb7, 0x9c, 0xc7, 0x40, 0xc4, 0x53, 0x40, 0x55,
+	0x01, 0x22, 0x5e, 0x0e, 0x14, 0xd8, 0xb1, 0x33, 0x4e, 0x41, 0x35, 0x0e, 0x62, 0x4c, 0x09, 0xba,
+	0x0f, 0x8b, 0x27, 0xbe, 0x1f, 0x4f, 0x7c, 0xdb, 0xc5, 0xa1, 0x35, 0xf5, 0x5d, 0xdc, 0x5f, 0x58,
+	0xd7, 0xb6, 0x9a, 0x66, 0x2f, 0x63, 0xbf, 0xf6, 0x5d, 0xac, 0xaf, 0x42, 0xcb, 0xc5, 0xe7, 0xc4,
+	0xc1, 0x16, 0x71, 0xfb, 0x75, 0xa6, 0x72, 0x93, 0x33, 0x5e, 0xb8, 0xfa, 0xa7, 0xd0, 0x0b, 0x88,
+	0x67, 0x51, 0x1f, 0x60, 0x27, 0xa6, 0x73, 0x35, 0x98, 0x90, 0x6e, 0x40, 0xbc, 0xa3, 0x94, 0xa9,
+	0x7f, 0x01, 0xcb, 0x81, 0x1d, 0x45, 0xc1, 0x38, 0xb4, 0x23, 0xac, 0xa2, 0x9b, 0x0c, 0x7d, 0x3d,
+	0x1b, 0x54, 0x7e, 0xb4, 0x02, 0xcd, 0x89, 0xed, 0x8d, 0x12, 0x7b, 0x84, 0xfb, 0x2d, 0x3e, 0xaf,
+	0xa4, 0xf5, 0xeb, 0xb0, 0x30, 0xb1, 0x4f, 0xf0, 0xa4, 0x0f, 0x6c, 0x80, 0x13, 0xfa, 0x1d, 0x58,
+	0x70, 0x7c, 0xe2, 0x45, 0xfd, 0xf6, 0x7a, 0x75, 0xab, 0xbd, 0xd3, 0x1a, 0xec, 0xf9, 0xc4, 0x3b,
+	0x9e, 0x05, 0xd8, 0xe4, 0x7c, 0x7d, 0x1d, 0xda, 0x24, 0x5d, 0x25, 0xb7, 0xdf, 0x61, 0xb3, 0xab,
+	0x2c, 0x3a, 0x69, 0x88, 0xcf, 0x09, 0x73, 0x5b, 0x77, 0x5d, 0xdb, 0xea, 0x98, 0x29, 0x5d, 0x70,
+	0xd9, 0xd8, 0x8e, 0xc6, 0xfd, 0x1e, 0x83, 0x28, 0x2e, 0x7b, 0x6e, 0x47, 0x63, 0x2a, 0x84, 0x4c,
+	0x03, 0x3f, 0x8c, 0xb1, 0xdb, 0x5f, 0x64, 0x73, 0xa4, 0xb4, 0xbe, 0x06, 0x40, 0x3d, 0xe6, 0xd8,
+	0xce, 0x18, 0xbb, 0x7d, 0xc4, 0x46, 0x5b, 0x01, 0xf1, 0xf6, 0x18, 0x43, 0x7f, 0x08, 0x4b, 0x8a,
+	0xa7, 0x04, 0x6a, 0x89, 0xa1, 0x50, 0x36, 0x20, 0xc0, 0xdb, 0x80, 0x4e, 0x49, 0x38, 0xfd, 0xce,
+	0x0e, 0xa9, 0x53, 0x71, 0x84, 0xbd, 0xb8, 0xaf, 0x33, 0xec, 0xa2, 0xe4, 0x1f, 0x71, 0xb6, 0x7e,
+	0x17, 0x3a, 0x1e, 0xc6, 0x6e, 0x64, 0x9d, 0xd8, 0xce, 0x59, 0x12, 0xf4, 0xaf, 0x71, 0xd3, 0x19,
+	0xef, 0x29, 0x63, 0x51, 0x9f, 0x9e, 0x4e, 0xec, 0x51, 0xd4, 0xbf, 0xce, 0xc2, 0x85, 0x13, 0x94,
+	0x4b, 0x83, 0x63, 0xd2, 0x5f, 0xe6, 0x9e, 0x66, 0x84, 0xd1, 0x83, 0xce, 0xde, 0x04, 0xdb, 0xe1,
+	0x10, 0x47, 0xd4, 0x35, 0xc6, 0xdf, 0x68, 0xd0, 0xdd, 0x0d, 0x82, 0xc9, 0x6c, 0x88, 0xe3, 0x98,
+	0x78, 0xa3, 0x28, 0xb7, 0x7a, 0xda, 0xbc, 0xd5, 0xab, 0xa8, 0xab, 0xf7, 0x29, 0xf4, 0x12, 0x1a,
+	0x1d, 0xa9, 0x95, 0x2c, 0xb8, 0x9b, 0x66, 0x37, 0x89, 0xf0, 0x51, 0xca, 0xd4, 0x6f, 0x03, 0x8c,
+	0xfd, 0x29, 0x8e, 0x9c, 0x10, 0x63, 0x1e, 0xda, 0x1d, 0x53, 0xe1, 0x18, 0x06, 0x00, 0xd3, 0xe4,
+	0x50, 0xaa, 0xcf, 0x8d, 0xd2, 0x14, 0xa3, 0x8c, 0x0d, 0x68, 0xed, 0x8d, 0x6d, 0x6f, 0x84, 0x8f,
+	0x88, 0x47, 0x37, 0x64, 0x88, 0xa7, 0xfe, 0x39, 0xd7, 0xb3, 0x69, 0x0a, 0xca, 0xf8, 0x27, 0x0d,
+	0x6a, 0x47, 0xc4, 0x1b, 0xe9, 0x7d, 0x68, 0x88, 0xad, 0x2f, 0x2c, 0x91, 0x24, 0x5d, 0xad, 0x93,
+	0x24, 0x8e, 0xfd, 0xdc, 0x0e, 0xa8, 0xf0, 0xd5, 0xe2, 0x03, 0x4a, 0x3c, 0x5f, 0xde, 0x2b, 0xd5,
+	0x8f, 0xda, 0x2b, 0xb5, 0xf9, 0x7b, 0xc5, 0xd8, 0x80, 0xc6, 0x30, 0x71, 0x1c, 0x1c, 0x45, 0xf3,
+	0xb5, 0x35, 0x0e, 0xa0, 0x71, 0x68, 0x93, 0x49, 0x12, 0x62, 0x7d, 0x1d, 0x6a, 0x8e, 0xef, 0x72,
+	0x44, 0x6f, 0xa7, 0x33, 0x10, 0x7c, 0xb6, 0x57, 0xd8, 0x88, 0x2a, 0xa6, 0x92, 0x17, 0xf3, 0x12,
+	0xba, 0x4f, 0x99, 0x6d, 0x26, 0xfe, 0x36, 0xc1, 0x51, 0xac, 0xdf, 0xcb, 0x09, 0xd3, 0x07, 0xb9,
+	0x51, 0x45, 0xa4, 0x0e, 0x35, 0xd7, 0x8e, 0x6d, 0x21, 0x8f, 0x7d, 0x1b, 0x6d, 0x68, 0x71, 0xf8,
+	0xae, 0x73, 0x66, 0xfc, 0x1c, 0xd0, 0x11, 0xf1, 0x5e, 0xdb, 0x71, 0x48, 0x2e, 0xa4, 0xf0, 0x6d,
+	0xa8, 0xd1, 0x3c, 0x2b, 0x84, 0x2f, 0x0f, 0x8a, 0x00, 0x2e, 0x9f, 0x42, 0x8c, 0x75, 0xe8, 0xa4,
+	0xa3, 0xbb, 0xce, 0x99, 0x8e, 0xa0, 0x1a, 0x10, 0xaf, 0xaf, 0xad, 0x57, 0xb6, 0x5a, 0x26, 0xfd,
+	0x34, 0x9a, 0x50, 0xdf, 0xb3, 0x3d, 0x07, 0x4f, 0x8c, 0xc7, 0xb0, 0x94, 0xc5, 0x94, 0x9c, 0x6b,
+	0x15, 0x5a, 0xbe, 0x67, 0xf1, 0xe4, 0x26, 0x82, 0xa1, 0xe9, 0x7b, 0xfb, 0x8c, 0x36, 0x1e, 0x41,
+	0x37, 0xfb, 0x05, 0x15, 0x7f, 0x1b, 0x40, 0x89, 0x55, 0xee, 0x6b, 0x85, 0x63, 0xac, 0x03, 0x3c,
+	0xc3, 0xf1, 0x81, 0x17, 0x87, 0x7e, 0x30, 0xa3, 0xc6, 0x47, 0xe4, 0x1d, 0x66, 0xda, 0x74, 0x4d,
+	0xf6, 0x4d, 0x57, 0x4d, 0x0e, 0xf7, 0xa1, 0x81, 0xf9, 0x27, 0x43, 0x74, 0x4c, 0x49, 0x1a, 0xff,
+	0xa0, 0x41, 0xe7, 0x19, 0x8e, 0x8f, 0x92, 0x93, 0x09, 0x71, 0x5e, 0xe2, 0x19, 0xd5, 0xd2, 0x76,
+	0xdd, 0x10, 0x47, 0x91, 0x45, 0x8d, 0xab, 0x6e, 0x75, 0xcd, 0xa6, 0x60, 0xbc, 0xd1, 0xb7, 0x00,
+	0x61, 0xc7, 0x8d, 0x6c, 0xcb, 0x49, 0xc2, 0x73, 0x6c, 0x79, 0xf6, 0x54, 0xae, 0x5f, 0x8f, 0xf1,
+	0xf7, 0x28, 0xfb, 0x8d, 0x3d, 0xc5, 0x34, 0x23, 0x44, 0x63, 0xff, 0x3b, 0xcb, 0x25, 0x51, 0x30,
+	0xb1, 0x67, 0x22, 0x18, 0xdb, 0x94, 0xb7, 0xcf, 0x59, 0xfa, 0x26, 0xb4, 0x68, 0xde, 0xe4, 0x52,
+	0x68, 0xf8, 0xb5, 0x9e, 0x34, 0x9e, 0x92, 0x98, 0xf2, 0xcc, 0x26, 0xfd, 0x4b, 0x05, 0x19, 0xbf,
+	0x82, 0x56, 0xa6, 0xdc, 0x1d, 0xa8, 0x79, 0x3c, 0x16, 0x2a, 0x5b, 0xed, 0x9d, 0xf6, 0xe0, 0xf9,
+	0xfe, 0x1b, 0xdf, 0x15, 0x71, 0xe5, 0x89, 0x20, 0xb8, 0x08, 0x92, 0x13, 0x19, 0x04, 0xf4, 0xdb,
+	0xf8, 0x5f, 0x8d, 0xb9, 0x6a, 0x97, 0x1b, 0x71, 0xb5, 0x81, 0x39, 0x9d, 0x2a, 0x73, 0x74, 0xfa,
+	0x10, 0xe3, 0xbe, 0x82, 0xe6, 0x34, 0x99, 0xc4, 0x24, 0x22, 0x23, 0x66, 0x5b, 0x7b, 0xe7, 0xe6,
+	0xe0, 0xb5, 0x60, 0x98, 0xd8, 0xc5, 0x78, 0x3a, 0x74, 0x42, 0x12, 0xf0, 0x00, 0x4b, 0xa1, 0xfa,
+	0x2f, 0xa1, 0x1d, 0x31, 0xbe, 0xc5, 0xc2, 0x72, 0x81, 0x85, 0x25, 0x1a, 0xbc, 0xf0, 0x82, 0x24,
+	0xce, 0x7e, 0xf0, 0xa4, 0x33, 0x3c, 0x3a, 0x78, 0xb3, 0xbf, 0xbb, 0xbf, 0x6f, 0x1e, 0x0c, 0x87,
+	0x26, 0x44, 0xe9, 0x88, 0x71, 0x0c, 0xfa, 0x41, 0x3c, 0xc6, 0x21, 0x4e, 0xa6, 0x1f, 0x6a, 0x73,
+	0xd1, 0x9a, 0xca, 0x25, 0x6b, 0x68, 0x28, 0x49, 0x51, 0x7d, 0x68, 0x88, 0x5f, 0x8a, 0xd0, 0x97,
+	0xa4, 0x31, 0x84, 0x45, 0x39, 0xb5, 0x04, 0xdf, 0x81, 0xb6, 0x9c, 0xf7, 0x84, 0xed, 0x15, 0x96,
+	0x4e, 0x05, 0xeb, 0x29, 0xf1, 0x54, 0xc0, 0x18, 0x5f, 0x88, 0x65, 0x93, 0x80, 0xe7, 0xf8, 0x82,
+	0x56, 0x3e, 0xdf, 0x90, 0x00, 0x8b, 0x5d, 0xf2, 0x77, 0x15, 0x80, 0x57, 0xbe, 0xed, 0x72, 0x92,
+	0x9e, 0x02, 0x53, 0x0f, 0x4f, 0x7d, 0x8f, 0x38, 0xf2, 0x14, 0x90, 0x74, 0x1a, 0x2a, 0x95, 0x75,
+	0xad, 0x3c, 0x54, 0xc4, 0xfe, 0xad, 0xb2, 0xdf, 0xd1, 0xcf, 0x1f, 0x94, 0x1b, 0xf5, 0x0d, 0xe5,
+	0x24, 0x5a, 0xe0, 0x01, 0x83, 0xbd, 0xd1, 0x84, 0x44, 0xe3, 0xb2, 0x23, 0xa9, 0xae, 0x1e, 0x49,
+	0x1b, 0xd0, 0x8d, 0xce, 0x48, 0x60, 0x39, 0x63, 0xec, 0x9c, 0x45, 0xc9, 0x54, 0x54, 0x37, 0x1d,
+	0xca, 0xdc, 0x13, 0x3c, 0xea, 0xa1, 0x64, 0xe7, 0xd4, 0x72, 0xfc, 0xc4, 0x8b, 0x71, 0xc8, 0x4a,
+	0x9a, 0xae, 0x09, 0xc9, 0xce, 0xe9, 0x1e, 0xe7, 0x18, 0xff, 0x5c, 0x81, 0xb6, 0x89, 0x23, 0x1c,
+	0x0b, 0xa7, 0x7c, 0x0a, 0x3d, 0xb1, 0x92, 0x56, 0x68, 0x7b, 0xae, 0x3f, 0x15, 0xb9, 0xa6, 0x2b,
+	0xb8, 0x26, 0x63, 0xea, 0x77, 0xa0, 0x19, 0xc5, 0x21, 0xf6, 0x46, 0xf1, 0x98, 0xd7, 0x82, 0x4f,
+	0xaa, 0x3b, 0x5f, 0xfd, 0xd4, 0x4c, 0x99, 0xf3, 0xbd, 0x51, 0xbd, 0xc2, 0x1b, 0x97, 0x4f, 0xa1,
+	0x5a, 0xd9, 0x29, 0xf4, 0x23, 0x9c, 0x56, 0xf0, 0x47, 0xa3, 0xe8, 0x0f, 0x0a, 0x60, 0x5e, 0x15,
+	0xa5, 0x08, 0xaf, 0x01, 0x81, 0xb2, 0x78, 0x25, 0x42, 0xab, 0x0b, 0xfe, 0x25, 0x82, 0x0a, 0x41,
+	0x4f, 0xe4, 0x49, 0x91, 0xa9, 0x8d, 0x7b, 0x00, 0x82, 0x43, 0x33, 0x71, 0x2e, 0x79, 0x6a, 0x6a,
+	0xf2, 0xfc, 0xcf, 0x0a, 0xf4, 0x4c, 0xec, 0xf8, 0xe7, 0x38, 0x9c, 0x09, 0xef, 0xaf, 0x01, 0x7c,
+	0xe7, 0x87, 0x2e, 0xd7, 0x4f, 0x94, 0x05, 0x2d, 0xca, 0x61, 0xea, 0xcd, 0x77, 0x6a, 0xe5, 0xa3,
+	0x9c, 0x5a, 0x7d, 0x9f, 0x53, 0x6b, 0xef, 0x75, 0xea, 0x82, 0xea, 0xd4, 0x6d, 0x40, 0xd8, 0x3b,
+	0xf5, 0x43, 0x07, 0x5b, 0x54, 0xd7, 0x09, 0x89, 0x62, 0xe6, 0xf5, 0xa6, 0xb9, 0x28, 0xf8, 0xdf,
+	0x08, 0x36, 0xcd, 0xb0, 0x2c, 0x35, 0xf1, 0x40, 0x64, 0xdf, 0xc5, 0x35, 0x69, 0x5d, 0x5a, 0x93,
+	0x4f, 0xa0, 0xe1, 0x86, 0x33, 0x2b, 0x4c, 0x3c, 0x56, 0x52, 0x37, 0xcd, 0xba, 0x1b, 0xce, 0xcc,
+	0xc4, 0x33, 0xbe, 0x80, 0x36, 0x95, 0x2c, 0x8f, 0xc8, 0xcd, 0xdc, 0x71, 0x8c, 0x06, 0xca, 0x98,
+	0x72, 0x12, 0xaf, 0x41, 0x83, 0x0e, 0xd0, 0xb5, 0xd1, 0xa1, 0x46, 0x15, 0x16, 0xa9, 0x88, 0x7d,
+	0x1b, 0xbf, 0xd7, 0xa0, 0x3d, 0x24, 0x23, 0xef, 0xb5, 0x28, 0xa3, 0xae, 0x4c, 0x7e, 0xb9, 0x42,
+	0x84, 0x9d, 0x8c, 0x82, 0xcc, 0x1f, 0x05, 0xd5, 0x79, 0x47, 0x41, 0x21, 0x61, 0xd7, 0x3e, 0x3a,
+	0x61, 0xff, 0xb5, 0x06, 0xdd, 0xb7, 0x38, 0x24, 0xa7, 0x33, 0xa9, 0x6f, 0x2e, 0xc3, 0x6a, 0x4a,
+	0x86, 0xd5, 0x6f, 0x41, 0x2b, 0x22, 0x23, 0x8f, 0x5d, 0xf5, 0x58, 0xc4, 0x74, 0xcc, 0x8c, 0xa1,
+	0x9a, 0x52, 0xe5, 0x71, 0x5a, 0x6a, 0xca, 0xdc, 0x93, 0xf6, 0xcf, 0x01, 0x09, 0x15, 0x86, 0xaa,
+	0xcc, 0x1f, 0xa2, 0x8b, 0xf1, 0x3b, 0x8d, 0x6e, 0x2a, 0x27, 0x9c, 0x05, 0xb1, 0x34, 0xeb, 0x06,
+	0xd4, 0x83, 0xe4, 0xe4, 0x0c, 0xcb, 0x5d, 0x24, 0xa8, 0x62, 0x29, 0xa8, 0xa8, 0x7d, 0x17, 0x3a,
+	0x32, 0x93, 0xf9, 0xde, 0x24, 0x3d, 0x66, 0x05, 0xef, 0x6b, 0x6f, 0x52, 0xa8, 0x56, 0x6a, 0x57,
+	0x1d, 0xe6, 0x0b, 0xf3, 0xcc, 0x7e, 0x0b, 0x48, 0x68, 0x8a, 0x5d, 0xa9, 0xeb, 0x75, 0x58, 0xf0,
+	0x7c, 0x4f, 0x94, 0x69, 0x1d, 0x93, 0x13, 0x57, 0x68, 0xaa, 0x43, 0x6d, 0x3c, 0xb5, 0x1d, 0xe1,
+	0x77, 0xf6, 0x6d, 0x7c, 0x0b, 0xbd, 0x7d, 0x9c, 0xf3, 0xc0, 0x95, 0x81, 0x98, 0x4e, 0x59, 0x99,
+	0x33, 0x65, 0xb5, 0x7c, 0xca, 0x9a, 0x32, 0xe5, 0x21, 0xa0, 0x7d, 0x5c, 0x30, 0xa5, 0x50, 0xb0,
+	0x2b, 0x12, 0x94, 0xb5, 0xad, 0xe4, 0xd6, 0xd6, 0xf8, 0x2f, 0x0d, 0x7a, 0x7b, 0x24, 0x18, 0xe3,
+	0xf0, 0x25, 0x9e, 0xbd, 0xb5, 0x27, 0xc9, 0x7b, 0x74, 0x47, 0x50, 0xa5, 0xeb, 0xca, 0xa5, 0xd0,
+	0x4f, 0x6a, 0xcd, 0x39, 0xfd, 0x9d, 0xd0, 0x9a, 0x13, 0x3c, 0x93, 0x32, 0xfd, 0xc4, 0xb1, 0x20,
+	0x49, 0x7d, 0x13, 0x7a, 0x76, 0x74, 0x66, 0xf9, 0x9e, 0x25, 0x01, 0xbc, 0x5d, 0xd0, 0xb1, 0xa3,
+	0xb3, 0xaf, 0xbd, 0x83, 0x4b, 0x28, 0x97, 0x9b, 0xd9, 0xaf, 0x2b, 0x28, 0x61, 0xba, 0xde, 0x83,
+	0x0a, 0x39, 0x67, 0x07, 0x43, 0xc7, 0xac, 0x90, 0x73, 0x63, 0x0b, 0x10, 0x37, 0x06, 0xbb, 0xa9,
+	0x39, 0xa9, 0x7e, 0x9a, 0xa2, 0x9f, 0xf1, 0x57, 0xd0, 0x3b, 0x88, 0x62, 0x32, 0xb5, 0x63, 0x7c,
+	0x7c, 0x31, 0x24, 0xef, 0x30, 0x3d, 0xa2, 0xfd, 0x24, 0x0e, 0x92, 0x38, 0x4a, 0x33, 0x3a, 0x2d,
+	0xb0, 0x3b, 0x82, 0xc9, 0x93, 0xfa, 0x5d, 0xe8, 0x10, 0x4f, 0xc1, 0x54, 0x18, 0xa6, 0x4d, 0xbc,
+	0x0c, 0xf2, 0x41, 0xc9, 0xc4, 0xb8, 0x0b, 0x75, 0x31, 0xef, 0x27, 0xd0, 0x88, 0x2f, 0x2c, 0x51,
+	0xd2, 0xd3, 0x6c, 0x5a, 0x8f, 0xd9, 0x80, 0xf1, 0xaf, 0x1a, 0xd4, 0xe9, 0xf6, 0x3c, 0xbe, 0xf8,
+	0xc3, 0xea, 0xa6, 0xaf, 0x42, 0x23, 0xd7, 0xf0, 0x79, 0xa2, 0x7d, 0x6e, 0x4a, 0x8e, 0x7e, 0x1b,
+	0x5a, 0x13, 0xdf, 0x39, 0xb3, 0x62, 0x22, 0x76, 0x5a, 0xf7, 0x89, 0xf6, 0xd8, 0x6c, 0x52, 0xde,
+	0x31, 0x99, 0x62, 0xe3, 0xff, 0x34, 0xe8, 0x0c, 0xc9, 0x34, 0x98, 0x60, 0xa1, 0xfb, 0x26, 0xd4,
+	0xb9, 0x0a, 0x2c, 0x96, 0xda, 0x3b, 0x9d, 0xc1, 0xf1, 0x05, 0xcb, 0x99, 0x2c, 0xcd, 0x8b, 0x31,
+	0xfd, 0x3e, 0x34, 0x84, 0x31, 0xfd, 0x0a, 0x83, 0x75, 0x07, 0xc7, 0x17, 0x5f, 0x27, 0xb1, 0xc4,
+	0xc9, 0x51, 0xfd, 0x4b, 0xe8, 0xc4, 0xa1, 0xed, 0x45, 0x36, 0x3b, 0x09, 0xa3, 0x7e, 0x95, 0xa1,
+	0xd1, 0xe0, 0x38, 0x63, 0xb2, 0x1f, 0xe4, 0x50, 0x1f, 0x96, 0x16, 0x55, 0xc3, 0x17, 0xae, 0x36,
+	0xbc, 0x7e, 0xd9, 0xf0, 0x7f, 0xd4, 0xa0, 0x75, 0x9c, 0xde, 0x36, 0x1f, 0x41, 0x27, 0xe4, 0x9f,
+	0x96, 0x72, 0xcc, 0x75, 0x06, 0xea, 0x11, 0xd7, 0x0e, 0x33, 0x42, 0x7f, 0x04, 0x0d, 0x17, 0xc7,
+	0x36, 0x99, 0x44, 0xa2, 0x8e, 0x5d, 0x1e, 0xa4, 0xd2, 0xf6, 0xf9, 0x00, 0x77, 0x84, 0x40, 0xe9,
+	0x3f, 0x03, 0x88, 0x70, 0x28, 0x3b, 0x50, 0x55, 0xf6, 0x9b, 0x7e, 0xf6, 0x9b, 0x61, 0x3a, 0xc6,
+	0x7e, 0xa6, 0x60, 0x8d, 0x6d, 0x58, 0x38, 0x66, 0xf7, 0xda, 0x75, 0xa8, 0xc4, 0x17, 0x4c, 0xb5,
+	0x32, 0x0f, 0x56, 0xe2, 0x0b, 0xe3, 0x6f, 0x2b, 0xd0, 0x93, 0x95, 0xbe, 0x58, 0xcf, 0x1f, 0x90,
+	0xda, 0x56, 0xa1, 0x35, 0xb2, 0x23, 0x2b, 0x08, 0xe9, 0x75, 0x98, 0xa7, 0x89, 0xe6, 0xc8, 0x8e,
+	0x8e, 0x42, 0x92, 0x0d, 0x4e, 0xc8, 0x94, 0xc4, 0xfd, 0x5a, 0x3a, 0xf8, 0x8a, 0xd2, 0x74, 0x83,
+	0xc7, 0x3e, 0x5b, 0x8c, 0x8e, 0x59, 0x89, 0xfd, 0x6c, 0x33, 0xd7, 0xd5, 0x64, 0xf3, 0x19, 0xe8,
+	0xb4, 0x07, 0x60, 0x89, 0xfe, 0x9b, 0xe5, 0x8c, 0x13, 0xef, 0x4c, 0xa4, 0x05, 0x44, 0x47, 0x44,
+	0x47, 0x75, 0x8f, 0xf2, 0x69, 0x09, 0xc3, 0xd0, 0x13, 0x5e, 0x11, 0x8b, 0x32, 0x9b, 0xb2, 0x5e,
+	0x31, 0x8e, 0x7e, 0x13, 0x9a, 0xce, 0xd8, 0x26, 0x1e, 0xed, 0x53, 0xd2, 0x02, 0xa7, 0x66, 0x36,
+	0x18, 0xfd, 0xc2, 0x35, 0xfe, 0x5e, 0x83, 0x25, 0xe9, 0x8f, 0x6c, 0xb1, 0x0b, 0x12, 0xb5, 0x4b,
+	0x12, 0x69, 0xa1, 0x2a, 0x0f, 0x4c, 0xeb, 0x5c, 0x34, 0x64, 0x21, 0x65, 0xbd, 0xcd, 0x03, 0x42,
+	0xe1, 0xa3, 0x0c, 0x60, 0xe6, 0x01, 0x91, 0xec, 0x56, 0xa5, 0xac, 0xa1, 0x31, 0x80, 0x6e, 0xa6,
+	0x18, 0x5d, 0xdc, 0x35, 0x60, 0x1a, 0x08, 0x67, 0xf0, 0xe4, 0xd7, 0xa2, 0x1c, 0xe6, 0x05, 0xe3,
+	0x15, 0x5c, 0x53, 0x17, 0xf6, 0xc7, 0x55, 0x50, 0x06, 0x81, 0x65, 0x29, 0xed, 0xca, 0x0a, 0xa7,
+	0xf3, 0xa3, 0x2b, 0x1c, 0xc3, 0x84, 0xbe, 0x9c, 0xea, 0x7d, 0x35, 0xcc, 0x87, 0xce, 0x66, 0xfc,
+	0x07, 0x4b, 0x5a, 0x23, 0xef, 0x85, 0x8b, 0xbd, 0x98, 0xc4, 0x33, 0x7d, 0x1b, 0x9a, 0x44, 0x7c,
+	0x8b, 0xfd, 0xd1, 0x1d, 0xc8, 0x41, 0x7e, 0x8f, 0x27, 0x19, 0x14, 0x39, 0x63, 0x7b, 0x42, 0xd7,
+	0x1e, 0x5b, 0x63, 0xe2, 0xba, 0xd8, 0x13, 0x13, 0x2c, 0xa6, 0xfc, 0xe7, 0x8c, 0x9d, 0x87, 0x9e,
+	0x93, 0x28, 0xb1, 0x27, 0xe2, 0x52, 0x9a, 0x41, 0xdf, 0x32, 0x76, 0x69, 0xfb, 0xa5, 0x56, 0xd6,
+	0x7e, 0x31, 0x46, 0xd0, 0xa3, 0xaa, 0x63, 0x37, 0x55, 0x7e, 0x7e, 0x25, 0x47, 0x7b, 0xc6, 0xac,
+	0xc3, 0x62, 0xc9, 0x43, 0xbc, 0x63, 0xb6, 0x82, 0xb4, 0xe7, 0x92, 0x73, 0x52, 0xb5, 0xe8, 0xa4,
+	0xdf, 0x68, 0xb0, 0x44, 0xfb, 0x50, 0x7b, 0xfb, 0xcf, 0x45, 0xb7, 0xf6, 0x25, 0xfe, 0x28, 0x4f,
+	0xdd, 0x83, 0xc5, 0x00, 0xe3, 0xd0, 0xba, 0xa4, 0x42, 0x97, 0xb2, 0xb3, 0xd6, 0x4f, 0x99, 0xed,
+	0xd5, 0x52, 0xdb, 0x3f, 0x87, 0x5e, 0x41, 0x1d, 0xba, 0x4f, 0x38, 0x65, 0x65, 0xf5, 0x27, 0x44,
+	0x29, 0xc0, 0x78, 0x0c, 0xdd, 0x21, 0x8e, 0x7f, 0xbd, 0x73, 0xa8, 0x5c, 0x22, 0xd5, 0x1b, 0x8d,
+	0x76, 0xe9, 0xd6, 0x7d, 0x1f, 0xba, 0x87, 0xa2, 0x09, 0x7e, 0xc0, 0x1a, 0xc7, 0x37, 0xa0, 0x9e,
+	0xdb, 0xe9, 0x82, 0x32, 0x76, 0x61, 0x51, 0x02, 0x65, 0x66, 0xb8, 0x01, 0x75, 0xff, 0xf4, 0x34,
+	0xc2, 0xf2, 0x7e, 0x28, 0x28, 0x45, 0x44, 0x25, 0x27, 0xe2, 0x17, 0xd0, 0x93, 0x22, 0x7e, 0x1d,
+	0xd0, 0x87, 0x00, 0xba, 0x98, 0x81, 0x3d, 0xa3, 0x9f, 0xb2, 0x9f, 0x27, 0x48, 0x56, 0x16, 0xda,
+	0x11, 0x97, 0x40, 0xcb, 0x42, 0x3b, 0x1a, 0x1b, 0x9b, 0xd0, 0x1c, 0xe2, 0xc9, 0xe9, 0x31, 0x9d,
+	0x3b, 0xf7, 0x4b, 0x4d, 0xf9, 0xa5, 0xf1, 0x00, 0x96, 0xf6, 0xf1, 0x49, 0x32, 0x7a, 0x45, 0xbc,
+	0xb3, 0x7d, 0xec, 0xf0, 0x47, 0x89, 0x65, 0xa8, 0xcf, 0x70, 0x64, 0x79, 0x3e, 0x9b, 0xa7, 0x69,
+	0x2e, 0xcc, 0x70, 0xf4, 0xc6, 0x37, 0xae, 0x29, 0xd8, 0x67, 0x38, 0x1e, 0xc6, 0x76, 0x8c, 0x8d,
+	0xff, 0xa9, 0x40, 0x2f, 0xe5, 0x32, 0x16, 0xb3, 0xc8, 0x9e, 0xf9, 0x49, 0x2c, 0x6b, 0x7e, 0x4e,
+	0xc9, 0xde, 0x4b, 0x25, 0xeb, 0xbd, 0xdc, 0x80, 0xfa, 0x94, 0xb5, 0x56, 0xc5, 0xa2, 0x0a, 0x2a,
+	0xd7, 0xe2, 0xa9, 0xcd, 0x69, 0xf1, 0x2c, 0xcc, 0x6b, 0xf1, 0xcc, 0xbd, 0x6d, 0xd7, 0xaf, 0xb8,
+	0x6d, 0xaf, 0x01, 0x84, 0x38, 0xc2, 0x31, 0xbb, 0x09, 0xb3, 0xf3, 0xa2, 0x65, 0xb6, 0x18, 0x87,
+	0x5e, 0x3a, 0x69, 0xd5, 0xc5, 0x87, 0x65, 0x4f, 0xa0, 0xc9, 0x2c, 0xeb, 0x30, 0xa6, 0xec, 0xb7,
+	0x7e, 0x06, 0x7a, 0x28, 0xfa, 0x02, 0xd6, 0xa9, 0x7d, 0xc6, 0x6f, 0xd5, 0xe2, 0x99, 0x09, 0xc9,
+	0x91, 0x43, 0xfb, 0x8c, 0x5d, 0xab, 0xf5, 0x07, 0xb0, 0x94, 0xa2, 0x29, 0xd0, 0x0a, 0xfc, 0x88,
+	0xdd, 0x93, 0xbb, 0xe6, 0xa2, 0x1c, 0xa0, 0xc0, 0x23, 0x3f, 0x32, 0x16, 0xa1, 0xab, 0xf8, 0xd8,
+	0x0f, 0x8c, 0x23, 0xe8, 0xa4, 0x8c, 0x57, 0xfe, 0x88, 0x5d, 0xf0, 0xf1, 0x39, 0x9e, 0xc8, 0x27,
+	0x09, 0x46, 0x50, 0xf7, 0x9e, 0x24, 0xce, 0x19, 0x8e, 0x85, 0xcf, 0x05, 0xc5, 0x6e, 0xf3, 0xf8,
+	0x22, 0x16, 0x4e, 0x67, 0xdf, 0xc6, 0x33, 0xb8, 0x96, 0x4a, 0x7c, 0x8d, 0xa7, 0x7e, 0x38, 0x33,
+	0x31, 0x8f, 0x39, 0x35, 0x81, 0x74, 0xb3, 0x04, 0x32, 0x2f, 0x6e, 0xb7, 0x61, 0xb1, 0x20, 0x88,
+	0x2d, 0x33, 0xfb, 0x92, 0x01, 0xc1, 0x29, 0xe3, 0x2f, 0xe0, 0x7a, 0x01, 0xfa, 0x4d, 0x48, 0x62,
+	0x7c, 0xf5, 0xa4, 0x42, 0x52, 0x45, 0x95, 0x24, 0x9e, 0x64, 0xa2, 0xb1, 0xb8, 0x2d, 0x72, 0xc2,
+	0xf8, 0x89, 0x62, 0xd3, 0x21, 0xe5, 0xa4, 0x9b, 0x36, 0xc2, 0x4e, 0xec, 0xcb, 0x1d, 0x2e, 0xa8,
+	0x07, 0xbf, 0x5f, 0x86, 0xb6, 0x38, 0x47, 0x58, 0x1d, 0xb6, 0x0e, 0x37, 0x14, 0xd2, 0xca, 0xde,
+	0x62, 0xd1, 0x1f, 0xad, 0xd4, 0x7e, 0xf3, 0x6f, 0x7d, 0x4d, 0x5f, 0x01, 0xa4, 0x22, 0xe8, 0xcb,
+	0x0e, 0xd2, 0xc4, 0xd8, 0x1a, 0x5c, 0x53, 0xc7, 0xc4, 0x53, 0x0a, 0xaa, 0xac, 0xd4, 0xbe, 0x2f,
+	0x19, 0x16, 0x8f, 0x25, 0xa8, 0x2a, 0x86, 0xef, 0xc0, 0xb2, 0x3a, 0x9c, 0xbe, 0x2c, 0xa1, 0x9a,
+	0x10, 0x5f, 0x50, 0x2e, 0x6b, 0x97, 0xa2, 0x05, 0x81, 0xb8, 0x0f, 0x37, 0x73, 0x33, 0xa8, 0x89,
+	0x0b, 0xd5, 0x57, 0x9a, 0x14, 0xf4, 0xef, 0x14, 0xb8, 0x05, 0x2b, 0x65, 0x40, 0x9e, 0x75, 0x50,
+	0x43, 0x41, 0x6e, 0xc3, 0x6a, 0x19, 0x52, 0xa4, 0x38, 0xd4, 0x5c, 0x69, 0x7e, 0x2f, 0xa1, 0x05,
+	0xfd, 0xb2, 0x57, 0x0b, 0xd4, 0x2a, 0x77, 0x90, 0x1c, 0x06, 0xe1, 0x01, 0x03, 0xfa, 0x05, 0x01,
+	0xe9, 0xb1, 0x80, 0xda, 0x42, 0x44, 0xc1, 0x4b, 0x19, 0xa0, 0x23, 0x84, 0x14, 0xb4, 0xc8, 0xba,
+	0xc8, 0xa8, 0x2b, 0x44, 0xdc, 0x85, 0x4f, 0x54, 0x84, 0xd2, 0x53, 0x45, 0x3d, 0x01, 0xb9, 0x05,
+	0x7a, 0x6e, 0x25, 0x59, 0xf1, 0x8b, 0x16, 0xc5, 0xe8, 0x66, 0x5e, 0x4f, 0xf5, 0xc2, 0x83, 0xd0,
+	0x4a, 0x9d, 0x62, 0x9a, 0x9a, 0x7e, 0x1b, 0xae, 0xe7, 0x3c, 0x27, 0x5e, 0xee, 0xd1, 0x92, 0x50,
+	0xf4, 0x1e, 0xdc, 0x2a, 0x44, 0x52, 0xee, 0x45, 0x0a, 0xe9, 0x29, 0xae, 0x5f, 0x8a, 0xdb, 0x75,
+	0xce, 0xd0, 0x35, 0xbe, 0x52, 0xff, 0x52, 0xa2, 0x33, 0x7f, 0xa1, 0x42, 0xd7, 0xcb, 0xfd, 0x96,
+	0x96, 0xaf, 0x68, 0x59, 0x4c, 0xb3, 0x0a, 0x4b, 0x79, 0x00, 0x95, 0x7f, 0x23, 0xb5, 0x38, 0x17,
+	0x2f, 0xf9, 0x9e, 0x01, 0xfa, 0x44, 0xa0, 0x0a, 0xeb, 0xa7, 0x3e, 0xed, 0xa2, 0xbe, 0xc0, 0x6c,
+	0xe4, 0x43, 0x34, 0xf7, 0xda, 0x8b, 0x6e, 0x96, 0x83, 0x72, 0x2f, 0x81, 0x68, 0x45, 0x28, 0xbc,
+	0x01, 0xcb, 0x97, 0x41, 0x54, 0xe9, 0x55, 0xc5, 0x29, 0x85, 0x68, 0xc8, 0x9e, 0x74, 0xd1, 0xad,
+	0xf2, 0x5d, 0x95, 0x3d, 0xa6, 0xa0, 0xb5, 0xf2, 0xa8, 0x95, 0xc3, 0xb7, 0xd3, 0xa8, 0xcd, 0xad,
+	0xb3, 0x3c, 0x81, 0xd1, 0xba, 0xb2, 0x8b, 0x0a, 0x9e, 0x51, 0xdb, 0xd2, 0xc8, 0x28, 0xf7, 0x71,
+	0xbe, 0x55, 0x8d, 0x36, 0xca, 0xc3, 0x3b, 0x6b, 0x5f, 0xa3, 0xcd, 0xf2, 0xf0, 0x56, 0xea, 0x7b,
+	0x74, 0xaf, 0xdc, 0xbf, 0xb9, 0xa2, 0x1d, 0xdd, 0x17, 0xa0, 0x42, 0x7c, 0x16, 0xcb, 0x6d, 0xb4,
+	0x25, 0x34, 0xba, 0x0f, 0x6b, 0xb9, 0xf8, 0x2c, 0xbe, 0x87, 0xa2, 0xed, 0x14, 0x78, 0xb3, 0x1c,
+	0x48, 0xb5, 0x7f, 0xa0, 0x2c, 0xda, 0xbd, 0x82, 0x27, 0x72, 0xad, 0x1a, 0xf4, 0x50, 0xd9, 0x61,
+	0x7a, 0x3e, 0x64, 0xd9, 0xf8, 0x67, 0x2b, 0xf5, 0xef, 0xf9, 0x78, 0xc1, 0xa3, 0xf9, 0x0e, 0x3e,
+	0xfa, 0x49, 0xb9, 0xbf, 0x94, 0x56, 0x34, 0x1a, 0x94, 0x67, 0x6e, 0xd1, 0x94, 0x46, 0x8f, 0xca,
+	0x3d, 0x55, 0x6c, 0x42, 0xa1, 0xc7, 0xe9, 0x4e, 0x2e, 0xac, 0xb0, 0xda, 0x35, 0x44, 0x9f, 0xa7,
+	0x76, 0x6d, 0xc1, 0xad, 0x12, 0x5c, 0xda, 0xea, 0x43, 0x3b, 0xa9, 0x85, 0x05, 0x89, 0xf9, 0x3e,
+	0x24, 0xfa, 0x62, 0x9e, 0xc4, 0x62, 0xf3, 0x10, 0x7d, 0x99, 0x4a, 0x34, 0x8a, 0xb9, 0x2d, 0xbb,
+	0x17, 0xa1, 0xaf, 0xca, 0x23, 0x35, 0x7f, 0x01, 0x41, 0x3f, 0x15, 0xd6, 0x16, 0xfc, 0xaa, 0xfc,
+	0x27, 0x13, 0xfa, 0x63, 0x21, 0x68, 0x0b, 0x6e, 0xe7, 0x0c, 0xbd, 0xf4, 0xa0, 0x89, 0x7e, 0x26,
+	0x90, 0x9f, 0xc2, 0x6a, 0x19, 0x52, 0xc2, 0xfe, 0x44, 0xcc, 0x59, 0xdc, 0x43, 0xb9, 0xe6, 0x05,
+	0x7a, 0x92, 0x1e, 0x93, 0x6b, 0x65, 0xa8, 0x2c, 0x27, 0xfe, 0x69, 0x9a, 0x62, 0x6e, 0x96, 0x03,
+	0xe9, 0xea, 0xff, 0x59, 0xb9, 0xb4, 0x4b, 0x97, 0x24, 0xf4, 0xf3, 0x39, 0x1b, 0x3c, 0x8f, 0xfa,
+	0x45, 0xf9, 0x9c, 0xb9, 0xeb, 0x0a, 0xfa, 0xa5, 0x10, 0xb5, 0x0d, 0x77, 0xe6, 0xd9, 0x29, 0x97,
+	0xf4, 0x57, 0x02, 0xfa, 0x10, 0xee, 0x96, 0x41, 0xf3, 0x7b, 0x7e, 0x57, 0x80, 0x07, 0xb0, 0x59,
+	0x06, 0xbe, 0xb4, 0xf7, 0x9f, 0x0a, 0x65, 0x1f, 0xe6, 0x6d, 0xbf, 0x74, 0xaf, 0x40, 0xee, 0x4a,
+	0xf3, 0xb7, 0x72, 0x5b, 0xdf, 0x9f, 0x03, 0x96, 0x17, 0x0b, 0x84, 0x57, 0x6a, 0xbf, 0x2d, 0x71,
+	0x54, 0xfe, 0xae, 0x81, 0x4e, 0x57, 0x6a, 0xbf, 0x2b, 0x71, 0x54, 0xae, 0x5a, 0x46, 0x23, 0x21,
+	0xaa, 0x10, 0xce, 0x6a, 0x05, 0x8d, 0xc6, 0x42, 0x50, 0xc1, 0x99, 0x25, 0x35, 0x31, 0xf2, 0x84,
+	0xb8, 0x42, 0x18, 0x16, 0xa0, 0xc8, 0x17, 0x12, 0x1f, 0xc0, 0xfa, 0x15, 0x30, 0x56, 0xf1, 0xa2,
+	0x40, 0x88, 0x9c, 0x37, 0x7b, 0x56, 0xbd, 0xa2, 0x6f, 0x39, 0xf4, 0xe9, 0x97, 0xb0, 0xe1, 0xf8,
+	0xd3, 0x41, 0x64, 0xc7, 0x7e, 0x34, 0x26, 0x13, 0xfb, 0x24, 0x1a, 0xc4, 0x21, 0x7e, 0xe7, 0x87,
+	0x83, 0x09, 0x39, 0xe1, 0xff, 0x41, 0x78, 0x92, 0x9c, 0x3e, 0xed, 0x1e, 0x33, 0xa6, 0x90, 0xfa,
+	0xff, 0x03, 0x00, 0xd7, 0x6c, 0x76, 0xd2, 0x71, 0x28, 0x00, 0x00,
 }
@@ -0,0 +1,187 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package usbwallet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/rwdxchain/go-rwdxchaina/accounts"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/core/types"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+	"github.com/rwdxchain/go-rwdxchaina/log"
+	"github.com/rwdxchain/go-rwdxchaina/rlp"
+)
+
+// ledgerTestDevice is a mocked Ledger device stream, reassembling the packets
+// written into APDUs, handing them to a handler simulating the device and
+// streaming the replies back in packets.
+type ledgerTestDevice struct {
+	handler func(ins, p1 byte, data []byte) []byte
+
+	apdu    []byte       // Partially assembled APDU
+	size    int          // Total length of the APDU being assembled
+	replies bytes.Buffer // Packets of the replies not yet read
+}
+
+func (d *ledgerTestDevice) Write(packet []byte) (int, error) {
+	if len(packet) > 64 || !bytes.Equal(packet[:3], []byte{0x01, 0x01, 0x05}) {
+		return 0, fmt.Errorf("invalid packet: %x", packet)
+	}
+	if seq := binary.BigEndian.Uint16(packet[3:5]); seq == 0 {
+		d.apdu, d.size = append([]byte{}, packet[7:]...), int(binary.BigEndian.Uint16(packet[5:7]))
+	} else {
+		d.apdu = append(d.apdu, packet[5:]...)
+	}
+	if len(d.apdu) < d.size {
+		return len(packet), nil
+	}
+	apdu := d.apdu[:d.size]
+	if apdu[0] != 0xe0 || int(apdu[4]) != len(apdu)-5 {
+		return 0, fmt.Errorf("invalid APDU: %x", apdu)
+	}
+	reply := append(d.handler(apdu[1], apdu[2], apdu[5:]), 0x90, 0x00)
+
+	// Stream the reply back in 64 byte packets
+	header := make([]byte, 7)
+	copy(header, []byte{0x01, 0x01, 0x05})
+	binary.BigEndian.PutUint16(header[5:], uint16(len(reply)))
+
+	for seq := 0; len(reply) > 0; seq++ {
+		packet := make([]byte, 64)
+		binary.BigEndian.PutUint16(header[3:], uint16(seq))
+		if seq == 0 {
+			copy(packet, header)
+			reply = reply[copy(packet[7:], reply):]
+		} else {
+			copy(packet, header[:5])
+			reply = reply[copy(packet[5:], reply):]
+		}
+		d.replies.Write(packet)
+	}
+	return len(packet), nil
+}
+
+func (d *ledgerTestDevice) Read(packet []byte) (int, error) {
+	if d.replies.Len() == 0 {
+		return 0, io.EOF
+	}
+	return d.replies.Read(packet)
+}
+
+// Tests opening a Ledger and deriving addresses from it.
+func TestLedgerOpenDerive(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	driver := newLedgerDriver(log.New()).(*ledgerDriver)
+	device := &ledgerTestDevice{handler: func(ins, p1 byte, data []byte) []byte {
+		switch ledgerOpcode(ins) {
+		case ledgerOpRetrieveAddress:
+			path := make([]byte, 1+4*len(accounts.DefaultBaseDerivationPath))
+			path[0] = byte(len(accounts.DefaultBaseDerivationPath))
+			for i, component := range accounts.DefaultBaseDerivationPath {
+				binary.BigEndian.PutUint32(path[1+4*i:], component)
+			}
+			if !bytes.Equal(data, path) {
+				t.Errorf("derivation path mismatch: have %x, want %x", data, path)
+			}
+			pubkey := crypto.FromECDSAPub(&key.PublicKey)
+			reply := append([]byte{byte(len(pubkey))}, pubkey...)
+			return append(append(reply, 40), hex.EncodeToString(addr[:])...)
+
+		case ledgerOpGetConfiguration:
+			return []byte{0x01, 1, 2, 3}
+		}
+		t.Fatalf("unexpected instruction: %x", ins)
+		return nil
+	}}
+	if err := driver.Open(device, ""); err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+	if status, _ := driver.Status(); status != "Rwdxchain app v1.2.3 online" {
+		t.Errorf("status mismatch: have %q", status)
+	}
+	derived, err := driver.Derive(accounts.DefaultBaseDerivationPath)
+	if err != nil {
+		t.Fatalf("failed to derive: %v", err)
+	}
+	if derived != addr {
+		t.Errorf("address mismatch: have %x, want %x", derived, addr)
+	}
+}
+
+// Tests signing transactions, streaming the payload in chunks and recovering the
+// single byte signature V for chain IDs of any size.
+func TestLedgerSignTx(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	tx := types.NewTransaction(3, common.Address{0xbb}, big.NewInt(1), 50000, big.NewInt(1), make([]byte, 600))
+
+	for i, chainID := range []*big.Int{big.NewInt(1), big.NewInt(1337), big.NewInt(1 << 40)} {
+		var (
+			signer   = types.NewEIP155Signer(chainID)
+			txrlp, _ = rlp.EncodeToBytes([]interface{}{tx.Nonce(), tx.GasPrice(), tx.Gas(), tx.To(), tx.Value(), tx.Data(), chainID, big.NewInt(0), big.NewInt(0)})
+			streamed []byte
+		)
+
+		driver := newLedgerDriver(log.New()).(*ledgerDriver)
+		driver.device, driver.version = &ledgerTestDevice{handler: func(ins, p1 byte, data []byte) []byte {
+			if ledgerOpcode(ins) != ledgerOpSignTransaction {
+				t.Fatalf("test %d: unexpected instruction: %x", i, ins)
+			}
+			if len(data) > 255 || (len(streamed) == 0) != (ledgerParam1(p1) == ledgerP1InitTransactionData) {
+				t.Errorf("test %d: invalid chunk: p1 %x, length %d", i, p1, len(data))
+			}
+			if len(streamed) == 0 {
+				data = data[1+4*int(data[0]):]
+			}
+			if streamed = append(streamed, data...); len(streamed) < len(txrlp) {
+				return nil
+			}
+			if !bytes.Equal(streamed, txrlp) {
+				t.Errorf("test %d: streamed transaction mismatch", i)
+			}
+			h := signer.Hash(tx)
+			sig, err := crypto.Sign(h[:], key)
+			if err != nil {
+				t.Fatalf("test %d: failed to sign: %v", i, err)
+			}
+			v := sig[64] + byte(chainID.Uint64()*2+35)
+			return append([]byte{v}, sig[:64]...)
+		}}, [3]byte{1, 0, 8}
+
+		sender, signed, err := driver.SignTx(accounts.DefaultBaseDerivationPath, tx, chainID)
+		if err != nil {
+			t.Errorf("test %d: failed to sign: %v", i, err)
+			continue
+		}
+		if sender != addr {
+			t.Errorf("test %d: sender mismatch: have %x, want %x", i, sender, addr)
+		}
+		if from, err := types.Sender(signer, signed); err != nil || from != addr {
+			t.Errorf("test %d: signed transaction sender mismatch: have %x (%v), want %x", i, from, err, addr)
+		}
+	}
+}
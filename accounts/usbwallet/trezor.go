@@ -17,6 +17,11 @@
 // This file contains the implementation for interacting with the Trezor hardware
 // wallets. The wire protocol spec can be found on the SatoshiLabs website:
 // https://doc.satoshilabs.com/trezor-tech/api-protobuf.html
+//
+// Only the original message framing is implemented, which devices use over both
+// HID (Trezor One with older firmwares) and WebUSB (Trezor Model T and Trezor One
+// with newer firmwares): messages are split into 64 byte packets, each starting
+// with the '?' magic byte. Newer host protocols of the Trezor aren't supported.
 
 package usbwallet
 
@@ -41,6 +46,11 @@ import (
 // encoded passphrase.
 var ErrTrezorPINNeeded = errors.New("trezor: pin needed")
 
+// ErrTrezorPassphraseNeeded is returned if opening the trezor requires a passphrase
+// entered on the host. In this case, the calling application should request the
+// passphrase from the user and reopen the wallet with it.
+var ErrTrezorPassphraseNeeded = errors.New("trezor: passphrase needed")
+
 // errTrezorReplyInvalidHeader is the error message returned by a Trezor data exchange
 // if the device replies with a mismatching header. This usually means the device
 // is in browser mode.
@@ -48,12 +58,14 @@ var errTrezorReplyInvalidHeader = errors.New("trezor: invalid reply header")
 
 // trezorDriver implements the communication with a Trezor hardware wallet.
 type trezorDriver struct {
-	device  io.ReadWriter // USB device connection to communicate through
-	version [3]uint32     // Current version of the Trezor firmware
-	label   string        // Current textual label of the Trezor device
-	pinwait bool          // Flags whether the device is waiting for PIN entry
-	failure error         // Any failure that would make the device unusable
-	log     log.Logger    // Contextual logger to tag the trezor with its id
+	device         io.ReadWriter // USB device connection to communicate through
+	model          string        // Hardware model of the Trezor device (empty on old firmwares)
+	version        [3]uint32     // Current version of the Trezor firmware
+	label          string        // Current textual label of the Trezor device
+	pinwait        bool          // Flags whether the device is waiting for PIN entry
+	passphrasewait bool          // Flags whether the device is waiting for passphrase entry
+	failure        error         // Any failure that would make the device unusable
+	log            log.Logger    // Contextual logger to tag the trezor with its id
 }
 
 // newTrezorDriver creates a new instance of a Trezor USB protocol driver.
@@ -72,14 +84,21 @@ func (w *trezorDriver) Status() (string, error) {
 	if w.device == nil {
 		return "Closed", w.failure
 	}
+	name := "Trezor"
+	if w.model != "" {
+		name += " " + w.model
+	}
 	if w.pinwait {
-		return fmt.Sprintf("Trezor v%d.%d.%d '%s' waiting for PIN", w.version[0], w.version[1], w.version[2], w.label), w.failure
+		return fmt.Sprintf("%s v%d.%d.%d '%s' waiting for PIN", name, w.version[0], w.version[1], w.version[2], w.label), w.failure
+	}
+	if w.passphrasewait {
+		return fmt.Sprintf("%s v%d.%d.%d '%s' waiting for passphrase", name, w.version[0], w.version[1], w.version[2], w.label), w.failure
 	}
-	return fmt.Sprintf("Trezor v%d.%d.%d '%s' online", w.version[0], w.version[1], w.version[2], w.label), w.failure
+	return fmt.Sprintf("%s v%d.%d.%d '%s' online", name, w.version[0], w.version[1], w.version[2], w.label), w.failure
 }
 
 // Open implements usbwallet.driver, attempting to initialize the connection to
-// the Trezor hardware wallet. Initializing the Trezor is a two phase operation:
+// the Trezor hardware wallet. Initializing the Trezor is a multi phase operation:
 //  * The first phase is to initialize the connection and read the wallet's
 //    features. This phase is invoked is the provided passphrase is empty. The
 //    device will display the pinpad as a result and will return an appropriate
@@ -87,11 +106,15 @@ func (w *trezorDriver) Status() (string, error) {
 //  * The second phase is to unlock access to the Trezor, which is done by the
 //    user actually providing a passphrase mapping a keyboard keypad to the pin
 //    number of the user (shuffled according to the pinpad displayed).
+//  * If the device is protected by a passphrase that is entered on the host, a
+//    third phase is needed, in which the user provides the actual passphrase.
+//    Devices entering the passphrase on the device itself (e.g. Model T) don't
+//    need this phase.
 func (w *trezorDriver) Open(device io.ReadWriter, passphrase string) error {
 	w.device, w.failure = device, nil
 
 	// If phase 1 is requested, init the connection and wait for user callback
-	if passphrase == "" {
+	if passphrase == "" && !w.passphrasewait {
 		// If we're already waiting for a PIN entry, insta-return
 		if w.pinwait {
 			return ErrTrezorPINNeeded
@@ -101,26 +124,45 @@ func (w *trezorDriver) Open(device io.ReadWriter, passphrase string) error {
 		if _, err := w.trezorExchange(&trezor.Initialize{}, features); err != nil {
 			return err
 		}
+		w.model = features.GetModel()
 		w.version = [3]uint32{features.GetMajorVersion(), features.GetMinorVersion(), features.GetPatchVersion()}
 		w.label = features.GetLabel()
 
-		// Do a manual ping, forcing the device to ask for its PIN
-		askPin := true
-		res, err := w.trezorExchange(&trezor.Ping{PinProtection: &askPin}, new(trezor.PinMatrixRequest), new(trezor.Success))
+		// Do a manual ping, forcing the device to ask for its PIN and passphrase
+		askPin, askPassphrase := true, true
+		res, err := w.trezorExchange(&trezor.Ping{PinProtection: &askPin, PassphraseProtection: &askPassphrase}, new(trezor.PinMatrixRequest), new(trezor.PassphraseRequest), new(trezor.Success))
 		if err != nil {
 			return err
 		}
-		// Only return the PIN request if the device wasn't unlocked until now
-		if res == 1 {
-			return nil // Device responded with trezor.Success
+		switch res {
+		case 0:
+			w.pinwait = true
+			return ErrTrezorPINNeeded
+		case 1:
+			w.passphrasewait = true
+			return ErrTrezorPassphraseNeeded
 		}
-		w.pinwait = true
-		return ErrTrezorPINNeeded
+		return nil // Device responded with trezor.Success
 	}
 	// Phase 2 requested with actual PIN entry
-	w.pinwait = false
+	if w.pinwait {
+		w.pinwait = false
+
+		res, err := w.trezorExchange(&trezor.PinMatrixAck{Pin: &passphrase}, new(trezor.PassphraseRequest), new(trezor.Success))
+		if err != nil {
+			w.failure = err
+			return err
+		}
+		if res == 0 {
+			w.passphrasewait = true
+			return ErrTrezorPassphraseNeeded
+		}
+		return nil
+	}
+	// Phase 3 requested with actual passphrase entry
+	w.passphrasewait = false
 
-	if _, err := w.trezorExchange(&trezor.PinMatrixAck{Pin: &passphrase}, new(trezor.Success)); err != nil {
+	if _, err := w.trezorExchange(&trezor.PassphraseAck{Passphrase: &passphrase}, new(trezor.Success)); err != nil {
 		w.failure = err
 		return err
 	}
@@ -130,7 +172,7 @@ func (w *trezorDriver) Open(device io.ReadWriter, passphrase string) error {
 // Close implements usbwallet.driver, cleaning up and metadata maintained within
 // the Trezor driver.
 func (w *trezorDriver) Close() error {
-	w.version, w.label, w.pinwait = [3]uint32{}, "", false
+	w.model, w.version, w.label, w.pinwait, w.passphrasewait = "", [3]uint32{}, "", false, false
 	return nil
 }
 
@@ -166,7 +208,13 @@ func (w *trezorDriver) trezorDerive(derivationPath []uint32) (common.Address, er
 	if _, err := w.trezorExchange(&trezor.EthereumGetAddress{AddressN: derivationPath}, address); err != nil {
 		return common.Address{}, err
 	}
-	return common.BytesToAddress(address.GetAddress()), nil
+	if addr := address.GetAddressBin(); len(addr) > 0 { // Older firmwares use the binary format
+		return common.BytesToAddress(addr), nil
+	}
+	if addr := address.GetAddressHex(); len(addr) > 0 { // Newer firmwares use the hexadecimal format
+		return common.HexToAddress(addr), nil
+	}
+	return common.Address{}, errors.New("trezor: reply lacks address")
 }
 
 // trezorSign sends the transaction to the Trezor wallet, and waits for the user
@@ -192,8 +240,11 @@ func (w *trezorDriver) trezorSign(derivationPath []uint32, tx *types.Transaction
 	} else {
 		request.DataInitialChunk, data = data, nil
 	}
-	if chainID != nil { // EIP-155 transaction, set chain ID explicitly (only 32 bit is supported!?)
-		id := uint32(chainID.Int64())
+	if chainID != nil { // EIP-155 transaction, set chain ID explicitly
+		if !chainID.IsUint64() {
+			return common.Address{}, nil, fmt.Errorf("trezor: unsupported chain ID %v", chainID)
+		}
+		id := chainID.Uint64()
 		request.ChainId = &id
 	}
 	// Send the initiation message and stream content until a signature is returned
@@ -210,18 +261,24 @@ func (w *trezorDriver) trezorSign(derivationPath []uint32, tx *types.Transaction
 		}
 	}
 	// Extract the Ethereum signature and do a sanity validation
-	if len(response.GetSignatureR()) == 0 || len(response.GetSignatureS()) == 0 || response.GetSignatureV() == 0 {
+	if len(response.GetSignatureR()) == 0 || len(response.GetSignatureS()) == 0 || response.SignatureV == nil {
 		return common.Address{}, nil, errors.New("reply lacks signature")
 	}
-	signature := append(append(response.GetSignatureR(), response.GetSignatureS()...), byte(response.GetSignatureV()))
+	recid, err := trezorRecoveryID(response.GetSignatureV(), chainID)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	signature := make([]byte, 65)
+	copy(signature[32-len(response.GetSignatureR()):32], response.GetSignatureR())
+	copy(signature[64-len(response.GetSignatureS()):64], response.GetSignatureS())
+	signature[64] = recid
 
-	// Create the correct signer and signature transform based on the chain ID
+	// Create the correct signer based on the chain ID
 	var signer types.Signer
 	if chainID == nil {
 		signer = new(types.HomesteadSigner)
 	} else {
 		signer = types.NewEIP155Signer(chainID)
-		signature[64] = signature[64] - byte(chainID.Uint64()*2+35)
 	}
 	// Inject the final signature into the transaction and sanity check the sender
 	signed, err := tx.WithSignature(signer, signature)
@@ -235,6 +292,25 @@ func (w *trezorDriver) trezorSign(derivationPath []uint32, tx *types.Transaction
 	return sender, signed, nil
 }
 
+// trezorRecoveryID converts the V value of a signature returned by the Trezor to
+// the recovery identifier of the signature. Devices return 27 + recid for legacy
+// transactions and 35 + 2*chainID + recid for EIP-155 ones, unless the latter
+// overflows 32 bits, in which case the bare recovery identifier is returned.
+func trezorRecoveryID(v uint32, chainID *big.Int) (byte, error) {
+	if v <= 1 {
+		return byte(v), nil
+	}
+	offset := big.NewInt(27)
+	if chainID != nil {
+		offset = new(big.Int).Add(new(big.Int).Lsh(chainID, 1), big.NewInt(35))
+	}
+	recid := new(big.Int).Sub(new(big.Int).SetUint64(uint64(v)), offset)
+	if recid.Sign() < 0 || recid.Cmp(big.NewInt(1)) > 0 {
+		return 0, fmt.Errorf("trezor: invalid signature V %d", v)
+	}
+	return byte(recid.Uint64()), nil
+}
+
 // trezorExchange performs a data exchange with the Trezor wallet, sending it a
 // message and retrieving the response. If multiple responses are possible, the
 // method will also return the index of the destination object used.
@@ -317,6 +393,17 @@ func (w *trezorDriver) trezorExchange(req proto.Message, results ...proto.Messag
 		// Trezor is waiting for user confirmation, ack and wait for the next message
 		return w.trezorExchange(&trezor.ButtonAck{}, results...)
 	}
+	if kind == uint16(trezor.MessageType_MessageType_PassphraseRequest) {
+		// Trezor is asking for a passphrase, which can only be handled implicitly
+		// if the user enters it on the device itself
+		request := new(trezor.PassphraseRequest)
+		if err := proto.Unmarshal(reply, request); err != nil {
+			return 0, err
+		}
+		if request.GetOnDevice() {
+			return w.trezorExchange(&trezor.PassphraseAck{}, results...)
+		}
+	}
 	for i, res := range results {
 		if trezor.Type(res) == kind {
 			return i, proto.Unmarshal(reply, res)
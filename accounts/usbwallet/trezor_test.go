@@ -0,0 +1,374 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package usbwallet
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/rwdxchain/go-rwdxchaina/accounts"
+	"github.com/rwdxchain/go-rwdxchaina/accounts/usbwallet/internal/trezor"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/core/types"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+	"github.com/rwdxchain/go-rwdxchaina/log"
+)
+
+// trezorTestDevice is a mocked Trezor device stream, reassembling the packets
+// written into messages, handing them to a handler simulating the device and
+// streaming the replies back in packets.
+type trezorTestDevice struct {
+	handler func(msg proto.Message) []proto.Message
+
+	request  []byte       // Partially assembled request message
+	replies  bytes.Buffer // Packets of the replies not yet read
+	messages []string     // Names of all the requests received
+}
+
+func (d *trezorTestDevice) Write(packet []byte) (int, error) {
+	if len(packet) != 64 || packet[0] != 0x3f {
+		return 0, fmt.Errorf("invalid packet: %x", packet)
+	}
+	if len(d.request) == 0 && (packet[1] != 0x23 || packet[2] != 0x23) {
+		return 0, fmt.Errorf("invalid message header: %x", packet)
+	}
+	d.request = append(d.request, packet[1:]...)
+
+	size := 8 + int(binary.BigEndian.Uint32(d.request[4:8]))
+	if len(d.request) < size {
+		return len(packet), nil
+	}
+	kind, data := binary.BigEndian.Uint16(d.request[2:4]), d.request[8:size]
+	d.request = nil
+
+	// Decode the request and pass it to the handler
+	d.messages = append(d.messages, trezor.Name(kind))
+
+	msg := trezorMessage(kind)
+	if msg == nil {
+		return 0, fmt.Errorf("unexpected message type %s", trezor.Name(kind))
+	}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return 0, err
+	}
+	for _, reply := range d.handler(msg) {
+		d.queue(reply)
+	}
+	return len(packet), nil
+}
+
+func (d *trezorTestDevice) Read(packet []byte) (int, error) {
+	if d.replies.Len() == 0 {
+		return 0, io.EOF
+	}
+	return d.replies.Read(packet)
+}
+
+// queue encodes a reply message into packets ready to be read.
+func (d *trezorTestDevice) queue(msg proto.Message) {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	payload := make([]byte, 8+len(data))
+	copy(payload, []byte{0x23, 0x23})
+	binary.BigEndian.PutUint16(payload[2:], trezor.Type(msg))
+	binary.BigEndian.PutUint32(payload[4:], uint32(len(data)))
+	copy(payload[8:], data)
+
+	for len(payload) > 0 {
+		packet := make([]byte, 64)
+		packet[0] = 0x3f
+		payload = payload[copy(packet[1:], payload):]
+		d.replies.Write(packet)
+	}
+}
+
+// trezorMessage creates an empty message of the requests the tests handle.
+func trezorMessage(kind uint16) proto.Message {
+	for _, msg := range []proto.Message{
+		new(trezor.Initialize), new(trezor.Ping), new(trezor.PinMatrixAck), new(trezor.PassphraseAck),
+		new(trezor.ButtonAck), new(trezor.EthereumGetAddress), new(trezor.EthereumSignTx), new(trezor.EthereumTxAck),
+	} {
+		if trezor.Type(msg) == kind {
+			return msg
+		}
+	}
+	return nil
+}
+
+func newTestTrezorDriver(handler func(msg proto.Message) []proto.Message) (*trezorDriver, *trezorTestDevice) {
+	return newTrezorDriver(log.New()).(*trezorDriver), &trezorTestDevice{handler: handler}
+}
+
+// Tests opening Trezor devices protected by a PIN and a passphrase entered on
+// the host.
+func TestTrezorOpenPINAndPassphrase(t *testing.T) {
+	var (
+		model, label      = "T", "treasury"
+		major, minor, fix = uint32(2), uint32(0), uint32(10)
+		unlocked          bool
+	)
+	driver, device := newTestTrezorDriver(func(msg proto.Message) []proto.Message {
+		switch msg := msg.(type) {
+		case *trezor.Initialize:
+			return []proto.Message{&trezor.Features{Model: &model, Label: &label, MajorVersion: &major, MinorVersion: &minor, PatchVersion: &fix}}
+		case *trezor.Ping:
+			if !msg.GetPinProtection() || !msg.GetPassphraseProtection() {
+				t.Errorf("ping doesn't request protection: %v", msg)
+			}
+			return []proto.Message{new(trezor.PinMatrixRequest)}
+		case *trezor.PinMatrixAck:
+			if msg.GetPin() != "1234" {
+				text := "PIN invalid"
+				return []proto.Message{&trezor.Failure{Message: &text}}
+			}
+			return []proto.Message{new(trezor.PassphraseRequest)}
+		case *trezor.PassphraseAck:
+			if msg.GetPassphrase() != "secret" {
+				t.Errorf("passphrase mismatch: have %q, want %q", msg.GetPassphrase(), "secret")
+			}
+			unlocked = true
+			return []proto.Message{new(trezor.Success)}
+		}
+		t.Fatalf("unexpected request: %v", msg)
+		return nil
+	})
+	if err := driver.Open(device, ""); err != ErrTrezorPINNeeded {
+		t.Fatalf("first open error mismatch: have %v, want %v", err, ErrTrezorPINNeeded)
+	}
+	if status, _ := driver.Status(); status != "Trezor T v2.0.10 'treasury' waiting for PIN" {
+		t.Errorf("status mismatch: have %q", status)
+	}
+	if err := driver.Open(device, "1234"); err != ErrTrezorPassphraseNeeded {
+		t.Fatalf("PIN open error mismatch: have %v, want %v", err, ErrTrezorPassphraseNeeded)
+	}
+	if status, _ := driver.Status(); status != "Trezor T v2.0.10 'treasury' waiting for passphrase" {
+		t.Errorf("status mismatch: have %q", status)
+	}
+	if err := driver.Open(device, "secret"); err != nil {
+		t.Fatalf("failed to open with passphrase: %v", err)
+	}
+	if status, _ := driver.Status(); !unlocked || status != "Trezor T v2.0.10 'treasury' online" {
+		t.Errorf("status mismatch: have %q, unlocked %v", status, unlocked)
+	}
+}
+
+// Tests that passphrases entered on the device are acknowledged implicitly.
+func TestTrezorOnDevicePassphrase(t *testing.T) {
+	onDevice := true
+	driver, device := newTestTrezorDriver(func(msg proto.Message) []proto.Message {
+		switch msg := msg.(type) {
+		case *trezor.Initialize:
+			return []proto.Message{new(trezor.Features)}
+		case *trezor.Ping:
+			return []proto.Message{&trezor.PassphraseRequest{OnDevice: &onDevice}}
+		case *trezor.PassphraseAck:
+			if msg.Passphrase != nil {
+				t.Errorf("passphrase sent for on device entry: %q", msg.GetPassphrase())
+			}
+			return []proto.Message{new(trezor.ButtonRequest)}
+		case *trezor.ButtonAck:
+			return []proto.Message{new(trezor.Success)}
+		}
+		t.Fatalf("unexpected request: %v", msg)
+		return nil
+	})
+	if err := driver.Open(device, ""); err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+	want := []string{"Initialize", "Ping", "PassphraseAck", "ButtonAck"}
+	if fmt.Sprint(device.messages) != fmt.Sprint(want) {
+		t.Errorf("request mismatch: have %v, want %v", device.messages, want)
+	}
+	if status, _ := driver.Status(); status != "Trezor v0.0.0 '' online" {
+		t.Errorf("status mismatch: have %q", status)
+	}
+}
+
+// Tests that derived addresses are returned both in the binary format of older
+// firmwares and in the hexadecimal one of newer firmwares.
+func TestTrezorDerive(t *testing.T) {
+	want := common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94")
+
+	for i, reply := range []*trezor.EthereumAddress{
+		{AddressBin: want.Bytes()},
+		{AddressHex: proto.String(want.Hex())},
+	} {
+		reply := reply
+		driver, device := newTestTrezorDriver(func(msg proto.Message) []proto.Message {
+			if req, ok := msg.(*trezor.EthereumGetAddress); !ok || fmt.Sprint(req.AddressN) != fmt.Sprint([]uint32(accounts.DefaultBaseDerivationPath)) {
+				t.Fatalf("test %d: unexpected request: %v", i, msg)
+			}
+			return []proto.Message{reply}
+		})
+		driver.device = device
+
+		addr, err := driver.Derive(accounts.DefaultBaseDerivationPath)
+		if err != nil {
+			t.Errorf("test %d: failed to derive: %v", i, err)
+		} else if addr != want {
+			t.Errorf("test %d: address mismatch: have %x, want %x", i, addr, want)
+		}
+	}
+	driver, device := newTestTrezorDriver(func(msg proto.Message) []proto.Message {
+		return []proto.Message{new(trezor.EthereumAddress)}
+	})
+	driver.device = device
+	if _, err := driver.Derive(accounts.DefaultBaseDerivationPath); err == nil {
+		t.Errorf("empty address accepted")
+	}
+}
+
+// Tests signing transactions, streaming the payload in chunks and recovering the
+// signature for chain IDs of any size.
+func TestTrezorSignTx(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	data := make([]byte, 2500)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	tx := types.NewTransaction(7, common.Address{0xaa}, big.NewInt(1000), 100000, big.NewInt(1), data)
+
+	tests := []*big.Int{
+		nil,
+		big.NewInt(1),
+		big.NewInt(256),
+		big.NewInt(1337),
+		big.NewInt((math.MaxUint32 - 36) / 2),
+		big.NewInt(math.MaxUint32),
+		new(big.Int).SetUint64(1 << 40),
+	}
+	for i, chainID := range tests {
+		var (
+			streamed []byte
+			prompted bool
+		)
+		driver, device := newTestTrezorDriver(func(msg proto.Message) []proto.Message {
+			switch msg := msg.(type) {
+			case *trezor.EthereumSignTx:
+				if (chainID == nil && msg.ChainId != nil) || (chainID != nil && msg.GetChainId() != chainID.Uint64()) {
+					t.Errorf("test %d: chain ID mismatch: have %v, want %v", i, msg.GetChainId(), chainID)
+				}
+				if msg.GetDataLength() != uint32(len(data)) || !bytes.Equal(msg.GetTo(), common.Address{0xaa}.Bytes()) {
+					t.Errorf("test %d: transaction mismatch: %v", i, msg)
+				}
+				streamed = append(streamed, msg.DataInitialChunk...)
+			case *trezor.EthereumTxAck:
+				streamed = append(streamed, msg.DataChunk...)
+			case *trezor.ButtonAck:
+				prompted = true
+			default:
+				t.Fatalf("test %d: unexpected request: %v", i, msg)
+			}
+			if left := len(data) - len(streamed); left > 0 {
+				if left > 1024 {
+					left = 1024
+				}
+				length := uint32(left)
+				return []proto.Message{&trezor.EthereumTxRequest{DataLength: &length}}
+			}
+			if !prompted {
+				return []proto.Message{new(trezor.ButtonRequest)}
+			}
+			return []proto.Message{trezorSignature(t, key, tx, chainID)}
+		})
+		driver.device = device
+
+		sender, signed, err := driver.SignTx(accounts.DefaultBaseDerivationPath, tx, chainID)
+		if err != nil {
+			t.Errorf("test %d: failed to sign: %v", i, err)
+			continue
+		}
+		if !bytes.Equal(streamed, data) {
+			t.Errorf("test %d: streamed payload mismatch", i)
+		}
+		if sender != addr {
+			t.Errorf("test %d: sender mismatch: have %x, want %x", i, sender, addr)
+		}
+		var signer types.Signer = types.HomesteadSigner{}
+		if chainID != nil {
+			signer = types.NewEIP155Signer(chainID)
+		}
+		if from, err := types.Sender(signer, signed); err != nil || from != addr {
+			t.Errorf("test %d: signed transaction sender mismatch: have %x (%v), want %x", i, from, err, addr)
+		}
+	}
+}
+
+// trezorSignature signs a transaction the way Trezor firmwares do, returning the
+// recovery identifier in the V value offset by 27 for legacy transactions, or
+// by 35 + 2*chainID for EIP-155 ones if that fits into 32 bits.
+func trezorSignature(t *testing.T, key *ecdsa.PrivateKey, tx *types.Transaction, chainID *big.Int) *trezor.EthereumTxRequest {
+	var signer types.Signer = types.HomesteadSigner{}
+	if chainID != nil {
+		signer = types.NewEIP155Signer(chainID)
+	}
+	h := signer.Hash(tx)
+	sig, err := crypto.Sign(h[:], key)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	v := uint64(sig[64]) + 27
+	if chainID != nil {
+		v = uint64(sig[64])
+		if offset := new(big.Int).Add(new(big.Int).Lsh(chainID, 1), big.NewInt(35)); offset.Cmp(big.NewInt(math.MaxUint32)) < 0 {
+			v += offset.Uint64()
+		}
+	}
+	sigv := uint32(v)
+	return &trezor.EthereumTxRequest{SignatureR: sig[:32], SignatureS: sig[32:64], SignatureV: &sigv}
+}
+
+// Tests the conversion of signature V values into recovery identifiers.
+func TestTrezorRecoveryID(t *testing.T) {
+	tests := []struct {
+		v       uint32
+		chainID *big.Int
+		recid   byte
+		fail    bool
+	}{
+		{0, nil, 0, false},
+		{1, big.NewInt(1), 1, false},
+		{27, nil, 0, false},
+		{28, nil, 1, false},
+		{29, nil, 0, true},
+		{37, big.NewInt(1), 0, false},
+		{38, big.NewInt(1), 1, false},
+		{27, big.NewInt(1), 0, true},
+		{39, big.NewInt(1), 0, true},
+		{2*1337 + 36, big.NewInt(1337), 1, false},
+		{2, new(big.Int).SetUint64(1 << 40), 0, true},
+	}
+	for i, tt := range tests {
+		recid, err := trezorRecoveryID(tt.v, tt.chainID)
+		if (err != nil) != tt.fail {
+			t.Errorf("test %d: error mismatch: have %v, want failure %v", i, err, tt.fail)
+		} else if err == nil && recid != tt.recid {
+			t.Errorf("test %d: recovery ID mismatch: have %d, want %d", i, recid, tt.recid)
+		}
+	}
+}
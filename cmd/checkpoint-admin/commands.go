@@ -0,0 +1,216 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rwdxchain/go-rwdxchaina/accounts/abi/bind"
+	"github.com/rwdxchain/go-rwdxchaina/cmd/utils"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/common/hexutil"
+	"github.com/rwdxchain/go-rwdxchaina/contracts/checkpointoracle"
+	"github.com/rwdxchain/go-rwdxchaina/core/types"
+	"github.com/rwdxchain/go-rwdxchaina/ethclient"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	signersFlag = cli.StringFlag{
+		Name:  "signers",
+		Usage: "comma separated list of the addresses allowed to approve checkpoints",
+	}
+	thresholdFlag = cli.Uint64Flag{
+		Name:  "threshold",
+		Value: 1,
+		Usage: "the number of signers required to approve a checkpoint",
+	}
+	signaturesFlag = cli.StringFlag{
+		Name:  "signatures",
+		Usage: "comma separated list of the checkpoint signatures",
+	}
+)
+
+var commandDeploy = cli.Command{
+	Name:  "deploy",
+	Usage: "deploy a new checkpoint oracle contract",
+	Description: `
+Deploy a new checkpoint oracle contract, administered by the given signers of
+which --threshold need to approve every registered checkpoint.`,
+	Flags: []cli.Flag{
+		rpcFlag,
+		keyfileFlag,
+		passphraseFlag,
+		signersFlag,
+		thresholdFlag,
+	},
+	Action: func(ctx *cli.Context) error {
+		var signers []common.Address
+		for _, signer := range strings.Split(ctx.String(signersFlag.Name), ",") {
+			if signer = strings.TrimSpace(signer); !common.IsHexAddress(signer) {
+				utils.Fatalf("Invalid signer address: %q", signer)
+			}
+			signers = append(signers, common.HexToAddress(signer))
+		}
+		threshold := ctx.Uint64(thresholdFlag.Name)
+		if threshold == 0 || threshold > uint64(len(signers)) {
+			utils.Fatalf("Invalid threshold %d for %d signers", threshold, len(signers))
+		}
+		client := newClient(ctx)
+		key := getKey(ctx)
+
+		addr, tx, _, err := checkpointoracle.DeployCheckpointOracle(bind.NewKeyedTransactor(key.PrivateKey), client, signers, threshold)
+		if err != nil {
+			utils.Fatalf("Failed to deploy oracle: %v", err)
+		}
+		fmt.Println("Transaction:", tx.Hash().Hex())
+		waitMined(client, tx)
+		fmt.Println("Oracle address:", addr.Hex())
+		return nil
+	},
+}
+
+var commandStatus = cli.Command{
+	Name:  "status",
+	Usage: "show the status of a checkpoint oracle",
+	Description: `
+Print the admins, the signature threshold and the latest registered checkpoint
+of a checkpoint oracle contract.`,
+	Flags: []cli.Flag{
+		rpcFlag,
+		oracleFlag,
+	},
+	Action: func(ctx *cli.Context) error {
+		oracle := newOracle(ctx, newClient(ctx))
+
+		admins, err := oracle.Contract().GetAllAdmin(nil)
+		if err != nil {
+			utils.Fatalf("Failed to retrieve admins: %v", err)
+		}
+		threshold, err := oracle.Contract().GetThreshold(nil)
+		if err != nil {
+			utils.Fatalf("Failed to retrieve threshold: %v", err)
+		}
+		for i, admin := range admins {
+			fmt.Printf("Admin %d: %s\n", i, admin.Hex())
+		}
+		fmt.Println("Threshold:", threshold)
+
+		checkpoint, err := oracle.LatestCheckpoint(nil)
+		if err != nil {
+			utils.Fatalf("Failed to retrieve checkpoint: %v", err)
+		}
+		if checkpoint == nil {
+			fmt.Println("No checkpoint registered")
+			return nil
+		}
+		fmt.Println("Section index:", checkpoint.SectionIndex)
+		fmt.Println("Section head:", checkpoint.SectionHead.Hex())
+		fmt.Println("CHT root:", checkpoint.CHTRoot.Hex())
+		fmt.Println("Bloom trie root:", checkpoint.BloomRoot.Hex())
+		return nil
+	},
+}
+
+var commandSign = cli.Command{
+	Name:  "sign",
+	Usage: "sign a checkpoint",
+	Description: `
+Approve a checkpoint of the given oracle with a keyfile. The signature can be
+passed to the publish command once enough signers approved the checkpoint.
+Signing doesn't need a connection to a node.`,
+	Flags: []cli.Flag{
+		oracleFlag,
+		keyfileFlag,
+		passphraseFlag,
+		indexFlag,
+		headFlag,
+		chtFlag,
+		bloomFlag,
+	},
+	Action: func(ctx *cli.Context) error {
+		var (
+			oracle     = getOracleAddress(ctx)
+			checkpoint = getCheckpoint(ctx)
+			key        = getKey(ctx)
+		)
+		sig, err := checkpoint.Sign(oracle, key.PrivateKey)
+		if err != nil {
+			utils.Fatalf("Failed to sign checkpoint: %v", err)
+		}
+		fmt.Println("Signer:", key.Address.Hex())
+		fmt.Println("Signature:", hexutil.Encode(sig))
+		return nil
+	},
+}
+
+var commandPublish = cli.Command{
+	Name:  "publish",
+	Usage: "publish a checkpoint into the oracle",
+	Description: `
+Register a checkpoint approved by enough signers in the oracle. The transaction
+can be sent by any account, not only by the signers.`,
+	Flags: []cli.Flag{
+		rpcFlag,
+		oracleFlag,
+		keyfileFlag,
+		passphraseFlag,
+		indexFlag,
+		headFlag,
+		chtFlag,
+		bloomFlag,
+		signaturesFlag,
+	},
+	Action: func(ctx *cli.Context) error {
+		var sigs [][]byte
+		for _, sig := range strings.Split(ctx.String(signaturesFlag.Name), ",") {
+			blob, err := hexutil.Decode(strings.TrimSpace(sig))
+			if err != nil || len(blob) != 65 {
+				utils.Fatalf("Invalid signature: %q", sig)
+			}
+			sigs = append(sigs, blob)
+		}
+		var (
+			client     = newClient(ctx)
+			oracle     = newOracle(ctx, client)
+			checkpoint = getCheckpoint(ctx)
+			key        = getKey(ctx)
+		)
+		tx, err := oracle.RegisterCheckpoint(bind.NewKeyedTransactor(key.PrivateKey), checkpoint, sigs)
+		if err != nil {
+			utils.Fatalf("Failed to publish checkpoint: %v", err)
+		}
+		fmt.Println("Transaction:", tx.Hash().Hex())
+		waitMined(client, tx)
+		fmt.Println("Published checkpoint", checkpoint.SectionIndex)
+		return nil
+	},
+}
+
+// waitMined waits for a transaction to be included in the chain, exiting if it
+// failed.
+func waitMined(client *ethclient.Client, tx *types.Transaction) {
+	receipt, err := bind.WaitMined(context.Background(), client, tx)
+	if err != nil {
+		utils.Fatalf("Failed to wait for transaction: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		utils.Fatalf("Transaction %s failed", tx.Hash().Hex())
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+// checkpoint-admin is a utility that can be used to deploy and administer the
+// light client checkpoint oracle contract.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rwdxchain/go-rwdxchaina/cmd/utils"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Git SHA1 commit hash of the release (set via linker flags)
+var gitCommit = ""
+
+var app *cli.App
+
+func init() {
+	app = utils.NewApp(gitCommit, "a light client checkpoint oracle administration tool")
+	app.Commands = []cli.Command{
+		commandDeploy,
+		commandStatus,
+		commandSign,
+		commandPublish,
+	}
+}
+
+// Commonly used command line flags.
+var (
+	rpcFlag = cli.StringFlag{
+		Name:  "rpc",
+		Value: "http://localhost:8545",
+		Usage: "the RPC endpoint of the node to interact with",
+	}
+	oracleFlag = cli.StringFlag{
+		Name:  "oracle",
+		Usage: "the address of the checkpoint oracle contract",
+	}
+	keyfileFlag = cli.StringFlag{
+		Name:  "keyfile",
+		Usage: "the keyfile of the account to sign with",
+	}
+	passphraseFlag = cli.StringFlag{
+		Name:  "passwordfile",
+		Usage: "the file that contains the passphrase for the keyfile",
+	}
+	indexFlag = cli.Uint64Flag{
+		Name:  "index",
+		Usage: "the section index of the checkpoint",
+	}
+	headFlag = cli.StringFlag{
+		Name:  "head",
+		Usage: "the hash of the last block of the checkpoint section",
+	}
+	chtFlag = cli.StringFlag{
+		Name:  "cht",
+		Usage: "the CHT root of the checkpoint section",
+	}
+	bloomFlag = cli.StringFlag{
+		Name:  "bloom",
+		Usage: "the bloom trie root of the checkpoint section",
+	}
+)
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
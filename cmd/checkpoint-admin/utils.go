@@ -0,0 +1,114 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/rwdxchain/go-rwdxchaina/accounts/keystore"
+	"github.com/rwdxchain/go-rwdxchaina/cmd/utils"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/console"
+	"github.com/rwdxchain/go-rwdxchaina/contracts/checkpointoracle"
+	"github.com/rwdxchain/go-rwdxchaina/ethclient"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// getPassphrase obtains a passphrase given by the user. It first checks the
+// --passwordfile command line flag and ultimately prompts the user for a
+// passphrase.
+func getPassphrase(ctx *cli.Context) string {
+	passphraseFile := ctx.String(passphraseFlag.Name)
+	if passphraseFile != "" {
+		content, err := ioutil.ReadFile(passphraseFile)
+		if err != nil {
+			utils.Fatalf("Failed to read passphrase file '%s': %v", passphraseFile, err)
+		}
+		return strings.TrimRight(string(content), "\r\n")
+	}
+	passphrase, err := console.Stdin.PromptPassword("Passphrase: ")
+	if err != nil {
+		utils.Fatalf("Failed to read passphrase: %v", err)
+	}
+	return passphrase
+}
+
+// getKey loads and decrypts the keyfile given by the --keyfile flag.
+func getKey(ctx *cli.Context) *keystore.Key {
+	keyfile := ctx.String(keyfileFlag.Name)
+	if keyfile == "" {
+		utils.Fatalf("No keyfile specified (--%s)", keyfileFlag.Name)
+	}
+	keyjson, err := ioutil.ReadFile(keyfile)
+	if err != nil {
+		utils.Fatalf("Failed to read the keyfile at '%s': %v", keyfile, err)
+	}
+	key, err := keystore.DecryptKey(keyjson, getPassphrase(ctx))
+	if err != nil {
+		utils.Fatalf("Error decrypting key: %v", err)
+	}
+	return key
+}
+
+// newClient connects to the node given by the --rpc flag.
+func newClient(ctx *cli.Context) *ethclient.Client {
+	client, err := ethclient.Dial(ctx.String(rpcFlag.Name))
+	if err != nil {
+		utils.Fatalf("Failed to connect to node: %v", err)
+	}
+	return client
+}
+
+// getOracleAddress parses the oracle contract address given by the --oracle flag.
+func getOracleAddress(ctx *cli.Context) common.Address {
+	addr := ctx.String(oracleFlag.Name)
+	if !common.IsHexAddress(addr) {
+		utils.Fatalf("Invalid oracle address (--%s): %q", oracleFlag.Name, addr)
+	}
+	return common.HexToAddress(addr)
+}
+
+// newOracle binds the oracle contract given by the --oracle flag.
+func newOracle(ctx *cli.Context, client *ethclient.Client) *checkpointoracle.CheckpointOracle {
+	oracle, err := checkpointoracle.NewCheckpointOracle(getOracleAddress(ctx), client)
+	if err != nil {
+		utils.Fatalf("Failed to bind oracle contract: %v", err)
+	}
+	return oracle
+}
+
+// getCheckpoint assembles the checkpoint given by the --index, --head, --cht and
+// --bloom flags.
+func getCheckpoint(ctx *cli.Context) *checkpointoracle.Checkpoint {
+	checkpoint := &checkpointoracle.Checkpoint{SectionIndex: ctx.Uint64(indexFlag.Name)}
+	for _, field := range []struct {
+		flag cli.StringFlag
+		hash *common.Hash
+	}{
+		{headFlag, &checkpoint.SectionHead},
+		{chtFlag, &checkpoint.CHTRoot},
+		{bloomFlag, &checkpoint.BloomRoot},
+	} {
+		value := ctx.String(field.flag.Name)
+		if len(common.FromHex(value)) != common.HashLength {
+			utils.Fatalf("Invalid or missing hash (--%s): %q", field.flag.Name, value)
+		}
+		*field.hash = common.HexToHash(value)
+	}
+	return checkpoint
+}
@@ -34,7 +34,10 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
+	"github.com/rwdxchain/go-rwdxchaina/accounts"
+	"github.com/rwdxchain/go-rwdxchaina/accounts/keystore"
 	"github.com/rwdxchain/go-rwdxchaina/cmd/utils"
 	"github.com/rwdxchain/go-rwdxchaina/common"
 	"github.com/rwdxchain/go-rwdxchaina/crypto"
@@ -48,10 +51,10 @@ import (
 )
 
 // ExternalAPIVersion -- see extapi_changelog.md
-const ExternalAPIVersion = "2.0.0"
+const ExternalAPIVersion = "2.1.0"
 
 // InternalAPIVersion -- see intapi_changelog.md
-const InternalAPIVersion = "2.0.0"
+const InternalAPIVersion = "2.2.0"
 
 const legalWarning = `
 WARNING! 
@@ -115,6 +118,15 @@ var (
 			"This means that an STDIN/STDOUT is used for RPC-communication with a e.g. a graphical user " +
 			"interface, and can be used when Clef is started by an external process.",
 	}
+	policyFlag = cli.StringFlag{
+		Name:  "policy",
+		Usage: "File containing the transaction policy (TOML or JSON) enforced before the rules",
+	}
+	approvalTimeoutFlag = cli.DurationFlag{
+		Name:  "approval-timeout",
+		Usage: "Time given to approvers to decide on transactions requiring multi-party approval (at most the HTTP write timeout with --rpc)",
+		Value: 20 * time.Second,
+	}
 	testFlag = cli.BoolFlag{
 		Name:  "stdio-ui-test",
 		Usage: "Mechanism to test interface between Clef and UI. Requires 'stdio-ui'.",
@@ -151,6 +163,24 @@ Whenever you make an edit to the rule file, you need to use attestation to tell
 Clef that the file is 'safe' to execute.`,
 	}
 
+	attestPolicyCommand = cli.Command{
+		Action:    utils.MigrateFlags(attestPolicy),
+		Name:      "attestpolicy",
+		Usage:     "Attest that a policy file is to be used",
+		ArgsUsage: "<sha256sum>",
+		Flags: []cli.Flag{
+			logLevelFlag,
+			configdirFlag,
+			signerSecretFlag,
+		},
+		Description: `
+The attestpolicy command stores the sha256 of the policy file that you want to enforce on incoming 
+transactions. 
+
+Whenever you make an edit to the policy file, you need to use attestation to tell 
+Clef that the file is 'safe' to use.`,
+	}
+
 	addCredentialCommand = cli.Command{
 		Action:    utils.MigrateFlags(addCredential),
 		Name:      "addpw",
@@ -164,6 +194,23 @@ Clef that the file is 'safe' to execute.`,
 		Description: `
 The addpw command stores a password for a given address (keyfile). If you invoke it with only one parameter, it will 
 remove any stored credential for that address (keyfile)
+`,
+	}
+
+	addApproverCommand = cli.Command{
+		Action:    utils.MigrateFlags(addApprover),
+		Name:      "addapprover",
+		Usage:     "Register an approver for transactions requiring multi-party approval",
+		ArgsUsage: "<address> <name>",
+		Flags: []cli.Flag{
+			logLevelFlag,
+			configdirFlag,
+			signerSecretFlag,
+		},
+		Description: `
+The addapprover command registers the address of an approver, whose signed votes are accepted on the approval 
+API for transactions the ruleset requires a quorum for. If you invoke it with only one parameter, it will remove 
+the approver with that address.
 `,
 	}
 )
@@ -174,6 +221,8 @@ func init() {
 	app.Flags = []cli.Flag{
 		logLevelFlag,
 		keystoreFlag,
+		utils.KeyStoreDBFlag,
+		utils.RemoteKeyStoreFlag,
 		configdirFlag,
 		utils.NetworkIdFlag,
 		utils.LightKDFFlag,
@@ -189,11 +238,13 @@ func init() {
 		customDBFlag,
 		auditLogFlag,
 		ruleFlag,
+		policyFlag,
+		approvalTimeoutFlag,
 		stdiouiFlag,
 		testFlag,
 	}
 	app.Action = signer
-	app.Commands = []cli.Command{initCommand, attestCommand, addCredentialCommand}
+	app.Commands = []cli.Command{initCommand, attestCommand, attestPolicyCommand, addCredentialCommand, addApproverCommand}
 
 }
 func main() {
@@ -266,6 +317,30 @@ func attestFile(ctx *cli.Context) error {
 	return nil
 }
 
+func attestPolicy(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	if err := initialize(ctx); err != nil {
+		return err
+	}
+
+	stretchedKey, err := readMasterKey(ctx)
+	if err != nil {
+		utils.Fatalf(err.Error())
+	}
+	configDir := ctx.String(configdirFlag.Name)
+	vaultLocation := filepath.Join(configDir, common.Bytes2Hex(crypto.Keccak256([]byte("vault"), stretchedKey)[:10]))
+	confKey := crypto.Keccak256([]byte("config"), stretchedKey)
+
+	// Initialize the encrypted storages
+	configStorage := storage.NewAESEncryptedStorage(filepath.Join(vaultLocation, "config.json"), confKey)
+	val := ctx.Args().First()
+	configStorage.Put("policy_sha256", val)
+	log.Info("Policy attestation updated", "sha256", val)
+	return nil
+}
+
 func addCredential(ctx *cli.Context) error {
 	if len(ctx.Args()) < 1 {
 		utils.Fatalf("This command requires at leaste one argument.")
@@ -294,6 +369,61 @@ func addCredential(ctx *cli.Context) error {
 	return nil
 }
 
+func addApprover(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires at least one argument.")
+	}
+	if err := initialize(ctx); err != nil {
+		return err
+	}
+	address := ctx.Args().First()
+	if !common.IsHexAddress(address) {
+		utils.Fatalf("Invalid approver address: %s", address)
+	}
+	stretchedKey, err := readMasterKey(ctx)
+	if err != nil {
+		utils.Fatalf(err.Error())
+	}
+	configDir := ctx.String(configdirFlag.Name)
+	vaultLocation := filepath.Join(configDir, common.Bytes2Hex(crypto.Keccak256([]byte("vault"), stretchedKey)[:10]))
+	confKey := crypto.Keccak256([]byte("config"), stretchedKey)
+
+	// Initialize the encrypted storages
+	configStorage := storage.NewAESEncryptedStorage(filepath.Join(vaultLocation, "config.json"), confKey)
+	approvers, err := loadApprovers(configStorage)
+	if err != nil {
+		utils.Fatalf(err.Error())
+	}
+	// Replace or drop any previous registration of the address
+	var updated []core.Approver
+	for _, approver := range approvers {
+		if approver.Address != common.HexToAddress(address) {
+			updated = append(updated, approver)
+		}
+	}
+	if len(ctx.Args()) > 1 {
+		updated = append(updated, core.Approver{Name: ctx.Args().Get(1), Address: common.HexToAddress(address)})
+	}
+	blob, err := json.Marshal(updated)
+	if err != nil {
+		utils.Fatalf(err.Error())
+	}
+	configStorage.Put("approvers", string(blob))
+	log.Info("Approvers updated", "address", common.HexToAddress(address), "count", len(updated))
+	return nil
+}
+
+// loadApprovers reads the approvers registered in the config storage.
+func loadApprovers(configStorage storage.Storage) ([]core.Approver, error) {
+	var approvers []core.Approver
+	if blob := configStorage.Get("approvers"); blob != "" {
+		if err := json.Unmarshal([]byte(blob), &approvers); err != nil {
+			return nil, fmt.Errorf("invalid approver list: %v", err)
+		}
+	}
+	return approvers, nil
+}
+
 func initialize(c *cli.Context) error {
 	// Set up the logger to print everything
 	logOutput := os.Stdout
@@ -332,12 +462,16 @@ func signer(c *cli.Context) error {
 	log.Info("Loaded 4byte db", "signatures", db.Size(), "file", c.String("4bytedb"))
 
 	var (
-		api core.ExternalAPI
+		api       core.ExternalAPI
+		approvals *core.ApprovalQueue
 	)
 
 	configDir := c.String(configdirFlag.Name)
 	if stretchedKey, err := readMasterKey(c); err != nil {
 		log.Info("No master seed provided, rules disabled")
+		if c.String(policyFlag.Name) != "" {
+			utils.Fatalf("A master seed is required to enforce a policy")
+		}
 	} else {
 
 		if err != nil {
@@ -349,12 +483,17 @@ func signer(c *cli.Context) error {
 		pwkey := crypto.Keccak256([]byte("credentials"), stretchedKey)
 		jskey := crypto.Keccak256([]byte("jsstorage"), stretchedKey)
 		confkey := crypto.Keccak256([]byte("config"), stretchedKey)
+		policykey := crypto.Keccak256([]byte("policy"), stretchedKey)
 
 		// Initialize the encrypted storages
 		pwStorage := storage.NewAESEncryptedStorage(filepath.Join(vaultLocation, "credentials.json"), pwkey)
 		jsStorage := storage.NewAESEncryptedStorage(filepath.Join(vaultLocation, "jsstorage.json"), jskey)
 		configStorage := storage.NewAESEncryptedStorage(filepath.Join(vaultLocation, "config.json"), confkey)
 
+		ruleEngine, err := rules.NewRuleEvaluator(ui, jsStorage, pwStorage)
+		if err != nil {
+			utils.Fatalf(err.Error())
+		}
 		//Do we have a rule-file?
 		ruleJS, err := ioutil.ReadFile(c.String(ruleFlag.Name))
 		if err != nil {
@@ -368,15 +507,49 @@ func signer(c *cli.Context) error {
 				log.Info("Could not validate ruleset hash, rules not enabled", "got", hex.EncodeToString(shasum), "expected", storedShasum)
 			} else {
 				// Initialize rules
-				ruleEngine, err := rules.NewRuleEvaluator(ui, jsStorage, pwStorage)
-				if err != nil {
-					utils.Fatalf(err.Error())
-				}
 				ruleEngine.Init(string(ruleJS))
 				ui = ruleEngine
 				log.Info("Rule engine configured", "file", c.String(ruleFlag.Name))
 			}
 		}
+		// Do we have a policy-file? Unlike rules, a configured policy must not be skipped
+		if policyFile := c.String(policyFlag.Name); policyFile != "" {
+			policyData, err := ioutil.ReadFile(policyFile)
+			if err != nil {
+				utils.Fatalf("Could not load policy file: %v", err)
+			}
+			shasum := sha256.Sum256(policyData)
+			if storedShasum := configStorage.Get("policy_sha256"); storedShasum != hex.EncodeToString(shasum[:]) {
+				utils.Fatalf("Could not validate policy hash, got %x, expected %s", shasum, storedShasum)
+			}
+			config, err := rules.LoadPolicyConfig(policyFile)
+			if err != nil {
+				utils.Fatalf("Could not parse policy file: %v", err)
+			}
+			policyStorage := storage.NewAESEncryptedStorage(filepath.Join(vaultLocation, "policy.json"), policykey)
+			policy, err := rules.NewPolicy(config, policyStorage, db)
+			if err != nil {
+				utils.Fatalf("Invalid policy: %v", err)
+			}
+			ruleEngine.SetPolicy(policy)
+			ui = ruleEngine
+			log.Info("Transaction policy configured", "file", policyFile)
+		}
+		// Are there approvers for multi-party approval?
+		approvers, err := loadApprovers(configStorage)
+		if err != nil {
+			utils.Fatalf(err.Error())
+		}
+		if len(approvers) > 0 {
+			// Signing requests block until approved, so over HTTP they must be
+			// decided before the server gives up on writing the response
+			timeout := c.Duration(approvalTimeoutFlag.Name)
+			if c.Bool(utils.RPCEnabledFlag.Name) && timeout >= rpc.DefaultHTTPTimeouts.WriteTimeout {
+				utils.Fatalf("Approval timeout %v exceeds the HTTP write timeout %v", timeout, rpc.DefaultHTTPTimeouts.WriteTimeout)
+			}
+			approvals = core.NewApprovalQueue(approvers, timeout)
+			log.Info("Multi-party approval configured", "approvers", len(approvers), "timeout", timeout)
+		}
 	}
 
 	apiImpl := core.NewSignerAPI(
@@ -384,16 +557,26 @@ func signer(c *cli.Context) error {
 		c.String(keystoreFlag.Name),
 		c.Bool(utils.NoUSBFlag.Name),
 		ui, db,
-		c.Bool(utils.LightKDFFlag.Name))
+		c.Bool(utils.LightKDFFlag.Name),
+		extraKeyStores(c)...)
 
 	api = apiImpl
 
+	var approvalAPI core.ApprovalAPI
+	if approvals != nil {
+		apiImpl.EnableApprovals(approvals)
+		approvalAPI = core.NewApprovalAPI(approvals)
+	}
 	// Audit logging
 	if logfile := c.String(auditLogFlag.Name); logfile != "" {
-		api, err = core.NewAuditLogger(logfile, api)
+		auditLogger, err := core.NewAuditLogger(logfile, api)
 		if err != nil {
 			utils.Fatalf(err.Error())
 		}
+		api = auditLogger
+		if approvalAPI != nil {
+			approvalAPI = auditLogger.WrapApprovalAPI(approvalAPI)
+		}
 		log.Info("Audit logs configured", "file", logfile)
 	}
 	// register signer API with server
@@ -408,6 +591,16 @@ func signer(c *cli.Context) error {
 			Service:   api,
 			Version:   "1.0"},
 	}
+	// The approval API is only exposed over IPC, as the HTTP endpoint whitelists
+	// the account namespace. It can't live on the UI channel, which only carries
+	// requests to the UI, and votes are authenticated by the approvers' signatures.
+	if approvalAPI != nil {
+		rpcAPI = append(rpcAPI, rpc.API{
+			Namespace: "approval",
+			Public:    true,
+			Service:   approvalAPI,
+			Version:   "1.0"})
+	}
 	if c.Bool(utils.RPCEnabledFlag.Name) {
 
 		vhosts := splitAndTrim(c.GlobalString(utils.RPCVirtualHostsFlag.Name))
@@ -638,3 +831,29 @@ curl -i -H "Content-Type: application/json" -X POST --data '{"jsonrpc":"2.0","me
 
 
 **/
+
+// extraKeyStores opens the key database and remote signer configured besides
+// the keystore directory, if any.
+func extraKeyStores(c *cli.Context) []accounts.Backend {
+	var backends []accounts.Backend
+
+	n, p := keystore.StandardScryptN, keystore.StandardScryptP
+	if c.Bool(utils.LightKDFFlag.Name) {
+		n, p = keystore.LightScryptN, keystore.LightScryptP
+	}
+	if path := c.String(utils.KeyStoreDBFlag.Name); path != "" {
+		ks, err := keystore.NewDatabaseKeyStore(path, n, p)
+		if err != nil {
+			utils.Fatalf("Could not open key database: %v", err)
+		}
+		backends = append(backends, ks)
+	}
+	if endpoint := c.String(utils.RemoteKeyStoreFlag.Name); endpoint != "" {
+		ks, err := keystore.NewRemoteKeyStore(endpoint)
+		if err != nil {
+			utils.Fatalf("Could not open remote signer: %v", err)
+		}
+		backends = append(backends, ks)
+	}
+	return backends
+}
@@ -0,0 +1,307 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"net"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/core/forkid"
+	"github.com/rwdxchain/go-rwdxchaina/eth"
+	"github.com/rwdxchain/go-rwdxchaina/log"
+	"github.com/rwdxchain/go-rwdxchaina/p2p"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/enr"
+	"github.com/rwdxchain/go-rwdxchaina/rlp"
+)
+
+const (
+	crawlWorkers     = 16               // number of nodes checked concurrently
+	crawlNodeTimeout = 10 * time.Second // time limit of checking a single node
+)
+
+var errNoEthStatus = errors.New("no eth status received")
+
+// crawlTable is the part of the discovery table used by the crawler.
+type crawlTable interface {
+	Lookup(target discover.NodeID) []*discover.Node
+	RequestENR(n *discover.Node) (*enr.Record, error)
+}
+
+// crawler walks the discovery DHT and checks every node found, both in the
+// discovery protocol and with an RLPx connection.
+type crawler struct {
+	input     nodeSet
+	output    nodeSet
+	disc      crawlTable
+	key       *ecdsa.PrivateKey
+	removeAge time.Duration // age of the last response after which nodes are dropped
+
+	// dial connects to a node, it can be overridden in tests.
+	dial func(n *discover.Node) (net.Conn, error)
+}
+
+// crawlResult is the updated census entry of a checked node.
+type crawlResult struct {
+	id    discover.NodeID
+	entry nodeJSON
+}
+
+func newCrawler(input nodeSet, disc crawlTable, key *ecdsa.PrivateKey, removeAge time.Duration) *crawler {
+	c := &crawler{
+		input:     input,
+		output:    make(nodeSet, len(input)),
+		disc:      disc,
+		key:       key,
+		removeAge: removeAge,
+	}
+	c.dial = func(n *discover.Node) (net.Conn, error) {
+		addr := &net.TCPAddr{IP: n.IP, Port: int(n.TCP)}
+		return net.DialTimeout("tcp", addr.String(), crawlNodeTimeout)
+	}
+	return c
+}
+
+// run crawls the network for the given amount of time. The nodes of the input
+// set are re-checked first, followed by the nodes found in random lookups.
+func (c *crawler) run(timeout time.Duration) nodeSet {
+	var (
+		deadline = time.After(timeout)
+		found    = make(chan *discover.Node)
+		results  = make(chan crawlResult)
+		done     = make(chan struct{})
+		seen     = make(map[discover.NodeID]bool)
+		pending  []*discover.Node
+		running  int
+		stats    = new(crawlStats)
+		statusT  = time.NewTicker(8 * time.Second)
+	)
+	defer statusT.Stop()
+
+	for id, entry := range c.input {
+		n, err := entry.node()
+		if err != nil || n.ID != id {
+			log.Warn("Skipping invalid census entry", "id", id, "err", err)
+			continue
+		}
+		seen[id] = true
+		pending = append(pending, n)
+	}
+	go c.lookupLoop(found, done)
+
+loop:
+	for {
+		for running < crawlWorkers && len(pending) > 0 {
+			n := pending[0]
+			pending = pending[1:]
+			running++
+			go func() { results <- c.check(n) }()
+		}
+		select {
+		case n := <-found:
+			if !seen[n.ID] {
+				seen[n.ID] = true
+				pending = append(pending, n)
+				stats.found++
+			}
+		case r := <-results:
+			running--
+			c.output[r.id] = r.entry
+			stats.add(r.entry)
+		case <-statusT.C:
+			log.Info("Crawling in progress", "found", stats.found, "checked", stats.checked, "live", stats.live, "eth", stats.eth, "pending", len(pending))
+		case <-deadline:
+			break loop
+		}
+	}
+	close(done)
+	for ; running > 0; running-- {
+		r := <-results
+		c.output[r.id] = r.entry
+		stats.add(r.entry)
+	}
+	log.Info("Crawl finished", "found", stats.found, "checked", stats.checked, "live", stats.live, "eth", stats.eth)
+
+	// Keep the input nodes not checked this time and drop nodes that haven't
+	// responded for too long.
+	for id, entry := range c.input {
+		if _, ok := c.output[id]; !ok {
+			c.output[id] = entry
+		}
+	}
+	for id, entry := range c.output {
+		if time.Since(entry.LastResponse) > c.removeAge {
+			delete(c.output, id)
+		}
+	}
+	return c.output
+}
+
+// lookupLoop performs random lookups, reporting all nodes found.
+func (c *crawler) lookupLoop(found chan<- *discover.Node, done <-chan struct{}) {
+	for {
+		var target discover.NodeID
+		rand.Read(target[:])
+		nodes := c.disc.Lookup(target)
+		for _, n := range nodes {
+			select {
+			case found <- n:
+			case <-done:
+				return
+			}
+		}
+		// Back off if the table is empty, lookups return immediately then.
+		wait := time.Duration(0)
+		if len(nodes) == 0 {
+			wait = time.Second
+		}
+		select {
+		case <-done:
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// check queries a node's record and protocol handshakes, returning its updated
+// census entry.
+func (c *crawler) check(n *discover.Node) crawlResult {
+	entry := c.input[n.ID]
+	entry.LastCheck = time.Now().UTC().Truncate(time.Second)
+
+	responded := false
+	if r, err := c.disc.RequestENR(n); err == nil {
+		responded = true
+		entry.Seq, entry.Record = r.Seq(), discover.RecordText(r)
+		if updated, err := discover.NodeFromRecord(r); err == nil && !updated.Incomplete() && updated.TCP != 0 {
+			n = updated
+		}
+	} else {
+		log.Trace("Node record request failed", "id", n.ID, "err", err)
+	}
+	entry.URL = n.String()
+
+	hello, status, err := c.handshake(n)
+	if hello != nil {
+		responded = true
+		entry.Name, entry.Caps = hello.Name, nil
+		for _, cap := range hello.Caps {
+			entry.Caps = append(entry.Caps, cap.String())
+		}
+	}
+	if status != nil {
+		entry.Eth = status
+	}
+	if err != nil {
+		log.Trace("Node handshake failed", "id", n.ID, "err", err)
+	}
+	if responded {
+		if entry.FirstResponse.IsZero() {
+			entry.FirstResponse = entry.LastCheck
+		}
+		entry.LastResponse = entry.LastCheck
+	}
+	return crawlResult{n.ID, entry}
+}
+
+// handshake connects to a node, returning the hello it sent in the protocol
+// handshake and, if it supports the eth protocol, its chain status.
+func (c *crawler) handshake(n *discover.Node) (*p2p.Hello, *ethStatus, error) {
+	if n.TCP == 0 {
+		return nil, nil, errors.New("no TCP port")
+	}
+	fd, err := c.dial(n)
+	if err != nil {
+		return nil, nil, err
+	}
+	timeout := time.AfterFunc(crawlNodeTimeout, func() { fd.Close() })
+	defer timeout.Stop()
+
+	var protocols []p2p.Protocol
+	for i, version := range eth.ProtocolVersions {
+		protocols = append(protocols, p2p.Protocol{Name: eth.ProtocolName, Version: version, Length: eth.ProtocolLengths[i]})
+	}
+	conn, err := p2p.SetupRLPx(fd, c.key, n, "devp2p-crawler", protocols)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer conn.Close(p2p.DiscQuitting)
+
+	version, offset, ok := conn.Protocol(eth.ProtocolName)
+	if !ok {
+		return conn.Hello(), nil, nil
+	}
+	status, err := readEthStatus(conn, version, offset)
+	return conn.Hello(), status, err
+}
+
+// readEthStatus waits for the eth status message of a node.
+func readEthStatus(conn *p2p.RLPxConn, version uint, offset uint64) (*ethStatus, error) {
+	msg, err := conn.ReadMsg()
+	if err != nil {
+		return nil, err
+	}
+	defer msg.Discard()
+	if msg.Code != offset+eth.StatusMsg {
+		return nil, errNoEthStatus
+	}
+	var status struct {
+		ProtocolVersion uint32
+		NetworkID       uint64
+		TD              *big.Int
+		Head            common.Hash
+		Genesis         common.Hash
+		Rest            []rlp.RawValue `rlp:"tail"`
+	}
+	if err := msg.Decode(&status); err != nil {
+		return nil, err
+	}
+	result := &ethStatus{
+		Version:   version,
+		NetworkID: status.NetworkID,
+		TD:        status.TD,
+		Head:      status.Head,
+		Genesis:   status.Genesis,
+	}
+	if len(status.Rest) > 0 {
+		var id forkid.ID
+		if err := rlp.DecodeBytes(status.Rest[0], &id); err == nil {
+			result.ForkID = id.String()
+		}
+	}
+	return result, nil
+}
+
+// crawlStats counts the results of a crawl.
+type crawlStats struct {
+	found, checked, live, eth int
+}
+
+func (s *crawlStats) add(entry nodeJSON) {
+	s.checked++
+	if entry.LastResponse.Equal(entry.LastCheck) {
+		s.live++
+	}
+	if entry.Eth != nil && entry.LastResponse.Equal(entry.LastCheck) {
+		s.eth++
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/core/forkid"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+	"github.com/rwdxchain/go-rwdxchaina/eth"
+	"github.com/rwdxchain/go-rwdxchaina/p2p"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/enr"
+)
+
+// fakeCrawlTable is a discovery table knowing a single live node.
+type fakeCrawlTable struct {
+	node   *discover.Node
+	record *enr.Record
+}
+
+func (t *fakeCrawlTable) Lookup(discover.NodeID) []*discover.Node {
+	time.Sleep(10 * time.Millisecond)
+	return []*discover.Node{t.node}
+}
+
+func (t *fakeCrawlTable) RequestENR(n *discover.Node) (*enr.Record, error) {
+	if n.ID != t.node.ID {
+		return nil, errors.New("timeout")
+	}
+	return t.record, nil
+}
+
+// Tests that the crawler records the handshakes of live nodes and updates the
+// liveness information of the nodes of an existing census.
+func TestCrawl(t *testing.T) {
+	srv := &p2p.Server{Config: p2p.Config{
+		Name:        "test-node",
+		MaxPeers:    10,
+		ListenAddr:  "127.0.0.1:0",
+		PrivateKey:  newTestKey(t),
+		NoDiscovery: true,
+		Protocols: []p2p.Protocol{{Name: eth.ProtocolName, Version: 64, Length: 17, Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+			id := forkid.ID{Hash: [4]byte{1, 2, 3, 4}, Next: 5}
+			if err := p2p.SendItems(rw, eth.StatusMsg, uint32(64), uint64(1337), big.NewInt(100), common.Hash{1}, common.Hash{2}, id); err != nil {
+				return err
+			}
+			_, err := rw.ReadMsg()
+			return err
+		}}},
+	}}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+	defer srv.Stop()
+
+	var (
+		live    = srv.Self()
+		dead    = discover.NewNode(discover.PubkeyID(&newTestKey(t).PublicKey), live.IP, 0, 0)
+		missing = discover.NewNode(discover.PubkeyID(&newTestKey(t).PublicKey), live.IP, 0, 0)
+		now     = time.Now().UTC()
+		input   = nodeSet{
+			dead.ID:    {URL: dead.String(), LastResponse: now.Add(-48 * time.Hour)},
+			missing.ID: {URL: missing.String(), FirstResponse: now.Add(-time.Hour), LastResponse: now.Add(-time.Hour)},
+		}
+		table = &fakeCrawlTable{node: live, record: srv.LocalRecord().Record()}
+	)
+	output := newCrawler(input, table, newTestKey(t), 24*time.Hour).run(time.Second)
+
+	if len(output) != 2 {
+		t.Fatalf("census size mismatch: have %d, want %d", len(output), 2)
+	}
+	if _, ok := output[dead.ID]; ok {
+		t.Errorf("dead node not removed")
+	}
+	if entry := output[missing.ID]; !entry.LastResponse.Equal(input[missing.ID].LastResponse) || entry.LastCheck.IsZero() {
+		t.Errorf("missing node liveness mismatch: %+v", entry)
+	}
+	entry, ok := output[live.ID]
+	if !ok {
+		t.Fatalf("live node not in census")
+	}
+	if entry.LastResponse.IsZero() || !entry.FirstResponse.Equal(entry.LastResponse) {
+		t.Errorf("live node liveness mismatch: %+v", entry)
+	}
+	if entry.Record != discover.RecordText(table.record) {
+		t.Errorf("record mismatch: have %q", entry.Record)
+	}
+	if entry.Name != "test-node" || len(entry.Caps) != 1 || entry.Caps[0] != eth.ProtocolName+"/64" {
+		t.Errorf("hello mismatch: name %q, caps %v", entry.Name, entry.Caps)
+	}
+	want := &ethStatus{Version: 64, NetworkID: 1337, TD: big.NewInt(100), Head: common.Hash{1}, Genesis: common.Hash{2}, ForkID: "01020304/5"}
+	if status := entry.Eth; status == nil || status.NetworkID != want.NetworkID || status.TD.Cmp(want.TD) != 0 ||
+		status.Head != want.Head || status.Genesis != want.Genesis || status.Version != want.Version || status.ForkID != want.ForkID {
+		t.Errorf("eth status mismatch: have %+v, want %+v", status, want)
+	}
+}
+
+func newTestKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
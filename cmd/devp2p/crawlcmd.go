@@ -0,0 +1,142 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/cmd/utils"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+	"github.com/rwdxchain/go-rwdxchaina/log"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/params"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	crawlCommand = cli.Command{
+		Name:      "crawl",
+		Usage:     "create a census of the network",
+		ArgsUsage: "<nodes.json>",
+		Description: `
+Walk the discovery DHT and connect to every node found, recording its node
+record, the name and capabilities of its protocol handshake and its eth chain
+status. The census is written to the given file. If the file exists, its nodes
+are re-checked and updated.`,
+		Action: crawlNodes,
+		Flags: []cli.Flag{
+			bootnodesFlag,
+			listenAddrFlag,
+			crawlTimeoutFlag,
+			removeAfterFlag,
+			verbosityFlag,
+		},
+	}
+)
+
+var (
+	bootnodesFlag = cli.StringFlag{
+		Name:  "bootnodes",
+		Usage: "comma separated enode or enr URLs of the discovery bootstrap nodes (default mainnet)",
+	}
+	listenAddrFlag = cli.StringFlag{
+		Name:  "addr",
+		Value: "0.0.0.0:0",
+		Usage: "the UDP address of the discovery listener",
+	}
+	crawlTimeoutFlag = cli.DurationFlag{
+		Name:  "timeout",
+		Value: 30 * time.Minute,
+		Usage: "the duration of the crawl",
+	}
+	removeAfterFlag = cli.DurationFlag{
+		Name:  "remove-after",
+		Value: 24 * time.Hour,
+		Usage: "drop nodes that haven't responded for this long",
+	}
+	verbosityFlag = cli.IntFlag{
+		Name:  "verbosity",
+		Value: int(log.LvlInfo),
+		Usage: "log level (0-5)",
+	}
+)
+
+// crawlNodes performs crawlCommand.
+func crawlNodes(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("need nodes file as argument")
+	}
+	file := ctx.Args().Get(0)
+
+	handler := log.StreamHandler(os.Stderr, log.TerminalFormat(true))
+	log.Root().SetHandler(log.LvlFilterHandler(log.Lvl(ctx.Int(verbosityFlag.Name)), handler))
+
+	input := make(nodeSet)
+	if _, err := os.Stat(file); err == nil {
+		if input, err = loadNodesJSON(file); err != nil {
+			utils.Fatalf("Failed to load census: %v", err)
+		}
+	}
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		utils.Fatalf("Failed to generate node key: %v", err)
+	}
+	table := startDiscovery(ctx, key)
+	defer table.Close()
+
+	c := newCrawler(input, table, key, ctx.Duration(removeAfterFlag.Name))
+	output := c.run(ctx.Duration(crawlTimeoutFlag.Name))
+	if err := writeNodesJSON(file, output); err != nil {
+		utils.Fatalf("Failed to write census: %v", err)
+	}
+	return nil
+}
+
+// startDiscovery launches a discovery v4 table, bootstrapped with the nodes
+// given on the command line.
+func startDiscovery(ctx *cli.Context, key *ecdsa.PrivateKey) *discover.Table {
+	urls := params.MainnetBootnodes
+	if ctx.IsSet(bootnodesFlag.Name) {
+		urls = strings.Split(ctx.String(bootnodesFlag.Name), ",")
+	}
+	var bootnodes []*discover.Node
+	for _, url := range urls {
+		n, err := discover.ParseNode(strings.TrimSpace(url))
+		if err != nil {
+			utils.Fatalf("Invalid bootstrap node %q: %v", url, err)
+		}
+		bootnodes = append(bootnodes, n)
+	}
+	addr, err := net.ResolveUDPAddr("udp", ctx.String(listenAddrFlag.Name))
+	if err != nil {
+		utils.Fatalf("Invalid listen address: %v", err)
+	}
+	conn, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		utils.Fatalf("Failed to listen: %v", err)
+	}
+	table, err := discover.ListenUDP(conn, discover.Config{PrivateKey: key, Bootnodes: bootnodes})
+	if err != nil {
+		utils.Fatalf("Failed to start discovery: %v", err)
+	}
+	return table
+}
@@ -0,0 +1,227 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/cmd/utils"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/dnsdisc"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	dnsCommand = cli.Command{
+		Name:  "dns",
+		Usage: "DNS discovery commands",
+		Subcommands: []cli.Command{
+			dnsSyncCommand,
+			dnsSignCommand,
+			dnsTXTCommand,
+		},
+	}
+	dnsSyncCommand = cli.Command{
+		Name:      "sync",
+		Usage:     "download a DNS discovery tree",
+		ArgsUsage: "<url> <tree-directory>",
+		Action:    dnsSync,
+	}
+	dnsSignCommand = cli.Command{
+		Name:      "sign",
+		Usage:     "sign a DNS discovery tree",
+		ArgsUsage: "<tree-directory> <key-file>",
+		Description: `
+Sign the tree made of the node records in nodes.json and the links listed in
+enrtree-info.json with the hex encoded private key in the key file. The tree
+metadata is updated with the new sequence number and signature.`,
+		Action: dnsSign,
+		Flags: []cli.Flag{
+			dnsDomainFlag,
+			dnsSeqFlag,
+		},
+	}
+	dnsTXTCommand = cli.Command{
+		Name:      "to-txt",
+		Usage:     "create the DNS TXT records of a signed tree",
+		ArgsUsage: "<tree-directory> <output-file>",
+		Description: `
+Create the TXT records of a signed tree as a JSON object mapping DNS names to
+record contents. Use - as the output file to print the records.`,
+		Action: dnsToTXT,
+	}
+)
+
+var (
+	dnsDomainFlag = cli.StringFlag{
+		Name:  "domain",
+		Usage: "the domain name of the tree (default from enrtree-info.json)",
+	}
+	dnsSeqFlag = cli.UintFlag{
+		Name:  "seq",
+		Usage: "the sequence number of the tree (default previous + 1)",
+	}
+)
+
+const (
+	nodesFile    = "nodes.json"
+	treeInfoFile = "enrtree-info.json"
+)
+
+// dnsTreeInfo is the metadata of a tree, stored as enrtree-info.json in tree
+// directories next to the node records.
+type dnsTreeInfo struct {
+	URL          string    `json:"url,omitempty"`
+	Seq          uint      `json:"seq,omitempty"`
+	Sig          string    `json:"signature,omitempty"`
+	Links        []string  `json:"links"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+// dnsSync performs dnsSyncCommand.
+func dnsSync(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return fmt.Errorf("need tree URL and destination directory as arguments")
+	}
+	url, dir := ctx.Args().Get(0), ctx.Args().Get(1)
+
+	client := dnsdisc.NewClient(dnsdisc.Config{})
+	tree, err := client.SyncTree(url)
+	if err != nil {
+		utils.Fatalf("Failed to sync tree: %v", err)
+	}
+	info := &dnsTreeInfo{
+		URL:          url,
+		Seq:          tree.Seq(),
+		Sig:          tree.Signature(),
+		Links:        tree.Links(),
+		LastModified: time.Now(),
+	}
+	writeTreeDir(dir, info, makeNodeSet(tree.Records()))
+	fmt.Printf("Downloaded %d nodes and %d links\n", len(tree.Records()), len(info.Links))
+	return nil
+}
+
+// dnsSign performs dnsSignCommand.
+func dnsSign(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return fmt.Errorf("need tree directory and key file as arguments")
+	}
+	dir, keyfile := ctx.Args().Get(0), ctx.Args().Get(1)
+
+	info, nodes := loadTreeDir(dir)
+	domain := ctx.String(dnsDomainFlag.Name)
+	if domain == "" && info.URL != "" {
+		d, _, err := dnsdisc.ParseURL(info.URL)
+		if err != nil {
+			utils.Fatalf("Invalid tree URL in %s: %v", treeInfoFile, err)
+		}
+		domain = d
+	}
+	if domain == "" {
+		utils.Fatalf("Tree domain not set, use --%s", dnsDomainFlag.Name)
+	}
+	key, err := crypto.LoadECDSA(keyfile)
+	if err != nil {
+		utils.Fatalf("Failed to load key: %v", err)
+	}
+	seq := info.Seq + 1
+	if ctx.IsSet(dnsSeqFlag.Name) {
+		seq = ctx.Uint(dnsSeqFlag.Name)
+	}
+	tree := makeTree(info, nodes, seq)
+	url, err := tree.Sign(key, domain)
+	if err != nil {
+		utils.Fatalf("Failed to sign tree: %v", err)
+	}
+	info.URL, info.Seq, info.Sig = url, tree.Seq(), tree.Signature()
+	info.LastModified = time.Now()
+	writeTreeDir(dir, info, nil)
+
+	fmt.Println(url)
+	return nil
+}
+
+// dnsToTXT performs dnsTXTCommand.
+func dnsToTXT(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return fmt.Errorf("need tree directory and output file as arguments")
+	}
+	dir, output := ctx.Args().Get(0), ctx.Args().Get(1)
+
+	info, nodes := loadTreeDir(dir)
+	if info.URL == "" || info.Sig == "" {
+		utils.Fatalf("Tree in %s is not signed", dir)
+	}
+	domain, pubkey, err := dnsdisc.ParseURL(info.URL)
+	if err != nil {
+		utils.Fatalf("Invalid tree URL in %s: %v", treeInfoFile, err)
+	}
+	tree := makeTree(info, nodes, info.Seq)
+	if err := tree.SetSignature(pubkey, info.Sig); err != nil {
+		utils.Fatalf("Tree signature doesn't match content, sign it again: %v", err)
+	}
+	return writeJSON(output, tree.ToTXT(domain))
+}
+
+// makeTree creates the tree of the given node set and metadata.
+func makeTree(info *dnsTreeInfo, nodes nodeSet, seq uint) *dnsdisc.Tree {
+	records, err := nodes.records()
+	if err != nil {
+		utils.Fatalf("Failed to load node records: %v", err)
+	}
+	tree, err := dnsdisc.MakeTree(seq, records, info.Links)
+	if err != nil {
+		utils.Fatalf("Failed to create tree: %v", err)
+	}
+	return tree
+}
+
+// loadTreeDir reads the node set and metadata of a tree directory. The metadata
+// file is optional.
+func loadTreeDir(dir string) (*dnsTreeInfo, nodeSet) {
+	nodes, err := loadNodesJSON(filepath.Join(dir, nodesFile))
+	if err != nil {
+		utils.Fatalf("Failed to load node set: %v", err)
+	}
+	info := new(dnsTreeInfo)
+	if err := loadJSON(filepath.Join(dir, treeInfoFile), info); err != nil && !os.IsNotExist(err) {
+		utils.Fatalf("Failed to load tree info: %v", err)
+	}
+	if info.Links == nil {
+		info.Links = []string{}
+	}
+	return info, nodes
+}
+
+// writeTreeDir stores the metadata and, if given, the node set of a tree.
+func writeTreeDir(dir string, info *dnsTreeInfo, nodes nodeSet) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		utils.Fatalf("Failed to create tree directory: %v", err)
+	}
+	if err := writeJSON(filepath.Join(dir, treeInfoFile), info); err != nil {
+		utils.Fatalf("Failed to write tree info: %v", err)
+	}
+	if nodes != nil {
+		if err := writeNodesJSON(filepath.Join(dir, nodesFile), nodes); err != nil {
+			utils.Fatalf("Failed to write node set: %v", err)
+		}
+	}
+}
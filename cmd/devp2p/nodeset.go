@@ -0,0 +1,149 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/enr"
+)
+
+// nodeSet is a set of nodes, keyed by node ID. It is stored as nodes.json in
+// tree directories and is the census produced by the crawler.
+type nodeSet map[discover.NodeID]nodeJSON
+
+type nodeJSON struct {
+	Seq    uint64 `json:"seq"`
+	URL    string `json:"url,omitempty"`
+	Record string `json:"record,omitempty"`
+
+	// Liveness information maintained by the crawler.
+	FirstResponse time.Time `json:"firstResponse,omitempty"`
+	LastResponse  time.Time `json:"lastResponse,omitempty"`
+	LastCheck     time.Time `json:"lastCheck,omitempty"`
+
+	// Protocol handshake information retrieved by the crawler.
+	Name string     `json:"name,omitempty"`
+	Caps []string   `json:"caps,omitempty"`
+	Eth  *ethStatus `json:"eth,omitempty"`
+}
+
+// ethStatus is the status of a node's chain, as announced in the eth handshake.
+type ethStatus struct {
+	Version   uint        `json:"version"`
+	NetworkID uint64      `json:"networkId"`
+	TD        *big.Int    `json:"td"`
+	Head      common.Hash `json:"head"`
+	Genesis   common.Hash `json:"genesis"`
+	ForkID    string      `json:"forkId,omitempty"`
+}
+
+// loadNodesJSON reads a node set from a JSON file.
+func loadNodesJSON(file string) (nodeSet, error) {
+	var nodes nodeSet
+	if err := loadJSON(file, &nodes); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// writeNodesJSON stores a node set in a JSON file.
+func writeNodesJSON(file string, nodes nodeSet) error {
+	return writeJSON(file, nodes)
+}
+
+// makeNodeSet creates a node set from the given records.
+func makeNodeSet(records []*enr.Record) nodeSet {
+	nodes := make(nodeSet, len(records))
+	for _, r := range records {
+		n, err := discover.NodeFromRecord(r)
+		if err != nil {
+			continue
+		}
+		nodes[n.ID] = nodeJSON{Seq: r.Seq(), URL: n.String(), Record: discover.RecordText(r)}
+	}
+	return nodes
+}
+
+// records returns the decoded records of the set, sorted by node ID. Nodes
+// without a record are skipped.
+func (ns nodeSet) records() ([]*enr.Record, error) {
+	ids := make([]discover.NodeID, 0, len(ns))
+	for id, n := range ns {
+		if n.Record != "" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return string(ids[i][:]) < string(ids[j][:])
+	})
+	records := make([]*enr.Record, 0, len(ns))
+	for _, id := range ids {
+		r, err := discover.ParseRecord(ns[id].Record)
+		if err != nil {
+			return nil, fmt.Errorf("invalid record of node %x: %v", id[:8], err)
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+// node returns the node of an entry, preferring the endpoint in its record.
+func (n nodeJSON) node() (*discover.Node, error) {
+	if n.Record != "" {
+		if r, err := discover.ParseRecord(n.Record); err == nil {
+			if node, err := discover.NodeFromRecord(r); err == nil && !node.Incomplete() {
+				return node, nil
+			}
+		}
+	}
+	return discover.ParseNode(n.URL)
+}
+
+func loadJSON(file string, val interface{}) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, val); err != nil {
+		return fmt.Errorf("%s: %v", file, err)
+	}
+	return nil
+}
+
+// writeJSON stores a value as indented JSON in a file, or prints it if the file
+// name is "-".
+func writeJSON(file string, val interface{}) error {
+	data, err := json.MarshalIndent(val, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if file == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0644)
+}
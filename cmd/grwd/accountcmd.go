@@ -19,8 +19,10 @@ package main
 import (
 	"fmt"
 	"io/ioutil"
+	"strings"
 
 	"github.com/rwdxchain/go-rwdxchaina/accounts"
+	"github.com/rwdxchain/go-rwdxchaina/accounts/hdwallet"
 	"github.com/rwdxchain/go-rwdxchaina/accounts/keystore"
 	"github.com/rwdxchain/go-rwdxchaina/cmd/utils"
 	"github.com/rwdxchain/go-rwdxchaina/console"
@@ -30,6 +32,12 @@ import (
 )
 
 var (
+	hdWordsFlag = cli.IntFlag{
+		Name:  "words",
+		Value: 12,
+		Usage: "Number of words of the generated mnemonic (12, 15, 18, 21 or 24)",
+	}
+
 	walletCommand = cli.Command{
 		Name:      "wallet",
 		Usage:     "Manage Rwdxchain presale wallets",
@@ -84,7 +92,7 @@ either new or import). Without it you are not able to unlock your account.
 
 Note that exporting your key in unencrypted format is NOT supported.
 
-Keys are stored under <DATADIR>/keystore.
+Keys are stored under <DATADIR>/keystore, HD wallets under <DATADIR>/keystore/hd.
 It is safe to transfer the entire directory or the individual keys therein
 between ethereum nodes by simply copying.
 
@@ -97,6 +105,8 @@ Make sure you backup your keys regularly.`,
 				Flags: []cli.Flag{
 					utils.DataDirFlag,
 					utils.KeyStoreDirFlag,
+					utils.KeyStoreDBFlag,
+					utils.RemoteKeyStoreFlag,
 				},
 				Description: `
 Print a short summary of all accounts`,
@@ -188,6 +198,83 @@ this import mechanism is not needed when you transfer an account between
 nodes.
 `,
 			},
+			{
+				Name:  "hd",
+				Usage: "Manage HD wallets derived from a BIP-39 mnemonic",
+				Description: `
+Manage hierarchical deterministic wallets, all accounts of which are derived
+from the seed of a BIP-39 mnemonic. The seed is saved in encrypted format, you
+are prompted for a passphrase protecting it. The mnemonic itself may be extended
+by an optional BIP-39 passphrase, which is needed again to recover the wallet.
+
+When running a node, HD wallets can be opened with personal.openWallet, after
+which accounts used on chain are discovered automatically.`,
+				Subcommands: []cli.Command{
+					{
+						Name:   "new",
+						Usage:  "Create a new HD wallet from a generated mnemonic",
+						Action: utils.MigrateFlags(accountHDNew),
+						Flags: []cli.Flag{
+							utils.DataDirFlag,
+							utils.KeyStoreDirFlag,
+							utils.PasswordFileFlag,
+							utils.LightKDFFlag,
+							hdWordsFlag,
+						},
+						Description: `
+    geth account hd new
+
+Generates a new mnemonic, creates an HD wallet from it and prints the mnemonic
+and the address of the first account.
+
+Write down the mnemonic and keep it safe, together with the BIP-39 passphrase if
+one was given, it is the only way to recover the wallet.
+
+For non-interactive use the passphrase can be specified with the --password flag,
+the second line of the password file being the optional BIP-39 passphrase.
+`,
+					},
+					{
+						Name:      "import",
+						Usage:     "Import a BIP-39 mnemonic into a new HD wallet",
+						Action:    utils.MigrateFlags(accountHDImport),
+						ArgsUsage: "<mnemonicFile>",
+						Flags: []cli.Flag{
+							utils.DataDirFlag,
+							utils.KeyStoreDirFlag,
+							utils.PasswordFileFlag,
+							utils.LightKDFFlag,
+						},
+						Description: `
+    geth account hd import <mnemonicFile>
+
+Imports the mnemonic contained in <mnemonicFile> into a new HD wallet and prints
+the address of its first account.
+
+For non-interactive use the passphrase can be specified with the --password flag,
+the second line of the password file being the optional BIP-39 passphrase.
+`,
+					},
+					{
+						Name:      "derive",
+						Usage:     "Derive and track an account of an HD wallet",
+						Action:    utils.MigrateFlags(accountHDDerive),
+						ArgsUsage: "<wallet> <path>",
+						Flags: []cli.Flag{
+							utils.DataDirFlag,
+							utils.KeyStoreDirFlag,
+							utils.PasswordFileFlag,
+						},
+						Description: `
+    geth account hd derive <wallet> <path>
+
+Derives the account at the derivation <path> of the HD wallet identified by its
+URL or file path, adds it to the accounts of the wallet and prints its address.
+Relative paths are appended to the default root path m/44'/5396292'/0'/0.
+`,
+					},
+				},
+			},
 		},
 	}
 )
@@ -377,3 +464,128 @@ func accountImport(ctx *cli.Context) error {
 	fmt.Printf("Address: {%x}\n", acct.Address)
 	return nil
 }
+
+// hdWalletHub retrieves the HD wallet hub of the node.
+func hdWalletHub(ctx *cli.Context) *hdwallet.Hub {
+	stack, _ := makeConfigNode(ctx)
+	backends := stack.AccountManager().Backends(hdwallet.HubType)
+	if len(backends) == 0 {
+		utils.Fatalf("HD wallets are not available")
+	}
+	return backends[0].(*hdwallet.Hub)
+}
+
+// getMnemonicPassphrase retrieves the optional BIP-39 passphrase extending a
+// mnemonic, either as the second line of the password file, or requested
+// interactively from the user.
+func getMnemonicPassphrase(passwords []string) string {
+	if len(passwords) > 0 {
+		if len(passwords) > 1 {
+			return passwords[1]
+		}
+		return ""
+	}
+	fmt.Println("The mnemonic may be extended by an optional BIP-39 passphrase. Leave it empty for none.")
+	passphrase, err := console.Stdin.PromptPassword("BIP-39 passphrase: ")
+	if err != nil {
+		utils.Fatalf("Failed to read passphrase: %v", err)
+	}
+	if passphrase != "" {
+		confirm, err := console.Stdin.PromptPassword("Repeat BIP-39 passphrase: ")
+		if err != nil {
+			utils.Fatalf("Failed to read passphrase confirmation: %v", err)
+		}
+		if passphrase != confirm {
+			utils.Fatalf("Passphrases do not match")
+		}
+	}
+	return passphrase
+}
+
+// importMnemonic creates an HD wallet from the mnemonic and prints its first
+// account.
+func importMnemonic(ctx *cli.Context, mnemonic string) accounts.Wallet {
+	hub := hdWalletHub(ctx)
+
+	passwords := utils.MakePasswordList(ctx)
+	password := getPassPhrase("Your new HD wallet is locked with a password. Please give a password. Do not forget this password.", true, 0, passwords)
+	mnemonicPassphrase := getMnemonicPassphrase(passwords)
+
+	wallet, err := hub.Import(mnemonic, mnemonicPassphrase, password)
+	if err != nil {
+		utils.Fatalf("Failed to create HD wallet: %v", err)
+	}
+	fmt.Printf("Wallet: %s\n", wallet.URL())
+	for _, account := range wallet.Accounts() {
+		fmt.Printf("Address: {%x}\n", account.Address)
+	}
+	return wallet
+}
+
+// accountHDNew creates a new HD wallet from a generated mnemonic.
+func accountHDNew(ctx *cli.Context) error {
+	words := ctx.Int(hdWordsFlag.Name)
+	if words < 12 || words > 24 || words%3 != 0 {
+		utils.Fatalf("Invalid mnemonic length %d, must be 12, 15, 18, 21 or 24 words", words)
+	}
+	mnemonic, err := hdwallet.NewMnemonic(words * 32 / 3)
+	if err != nil {
+		utils.Fatalf("Failed to generate mnemonic: %v", err)
+	}
+	importMnemonic(ctx, mnemonic)
+
+	fmt.Printf("Mnemonic: %s\n", mnemonic)
+	fmt.Println("Write down the mnemonic and keep it safe, it is the only way to recover the wallet.")
+	return nil
+}
+
+// accountHDImport creates a new HD wallet from the mnemonic in a file.
+func accountHDImport(ctx *cli.Context) error {
+	file := ctx.Args().First()
+	if len(file) == 0 {
+		utils.Fatalf("mnemonic file must be given as argument")
+	}
+	mnemonic, err := ioutil.ReadFile(file)
+	if err != nil {
+		utils.Fatalf("Failed to read the mnemonic: %v", err)
+	}
+	if err := hdwallet.ValidateMnemonic(string(mnemonic)); err != nil {
+		utils.Fatalf("Failed to load the mnemonic: %v", err)
+	}
+	importMnemonic(ctx, strings.TrimSpace(string(mnemonic)))
+	return nil
+}
+
+// accountHDDerive derives an account of an HD wallet and adds it to the tracked
+// accounts of the wallet.
+func accountHDDerive(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		utils.Fatalf("Wallet and derivation path must be given as arguments")
+	}
+	path, err := accounts.ParseDerivationPath(ctx.Args()[1])
+	if err != nil {
+		utils.Fatalf("Invalid derivation path: %v", err)
+	}
+	var wallet accounts.Wallet
+	for _, w := range hdWalletHub(ctx).Wallets() {
+		if url := w.URL(); url.String() == ctx.Args()[0] || url.Path == ctx.Args()[0] {
+			wallet = w
+			break
+		}
+	}
+	if wallet == nil {
+		utils.Fatalf("Unknown HD wallet %s", ctx.Args()[0])
+	}
+	password := getPassPhrase("Please give the password of the HD wallet.", false, 0, utils.MakePasswordList(ctx))
+	if err := wallet.Open(password); err != nil {
+		utils.Fatalf("Failed to open HD wallet: %v", err)
+	}
+	defer wallet.Close()
+
+	account, err := wallet.Derive(path, true)
+	if err != nil {
+		utils.Fatalf("Failed to derive account: %v", err)
+	}
+	fmt.Printf("Address: {%x}\n", account.Address)
+	return nil
+}
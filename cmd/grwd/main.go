@@ -30,6 +30,7 @@ import (
 
 	"github.com/elastic/gosigar"
 	"github.com/rwdxchain/go-rwdxchaina/accounts"
+	"github.com/rwdxchain/go-rwdxchaina/accounts/hdwallet"
 	"github.com/rwdxchain/go-rwdxchaina/accounts/keystore"
 	"github.com/rwdxchain/go-rwdxchaina/cmd/utils"
 	"github.com/rwdxchain/go-rwdxchaina/console"
@@ -61,6 +62,8 @@ var (
 		utils.BootnodesV5Flag,
 		utils.DataDirFlag,
 		utils.KeyStoreDirFlag,
+		utils.KeyStoreDBFlag,
+		utils.RemoteKeyStoreFlag,
 		utils.NoUSBFlag,
 		utils.DashboardEnabledFlag,
 		utils.DashboardAddrFlag,
@@ -84,9 +87,13 @@ var (
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
 		utils.SyncModeFlag,
+		utils.SyncFromFlag,
+		utils.SyncFromTDFlag,
 		utils.GCModeFlag,
 		utils.LightServFlag,
 		utils.LightPeersFlag,
+		utils.ULCServersFlag,
+		utils.ULCFractionFlag,
 		utils.LightKDFFlag,
 		utils.CacheFlag,
 		utils.CacheDatabaseFlag,
@@ -113,6 +120,7 @@ var (
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
 		utils.DiscoveryV5Flag,
+		utils.DNSDiscoveryFlag,
 		utils.NetrestrictFlag,
 		utils.NodeKeyFileFlag,
 		utils.NodeKeyHexFlag,
@@ -298,8 +306,11 @@ func startNode(ctx *cli.Context, stack *node.Node) {
 		}
 		stateReader := ethclient.NewClient(rpcClient)
 
-		// Open any wallets already attached
+		// Open any wallets already attached, HD wallets need their password
 		for _, wallet := range stack.AccountManager().Wallets() {
+			if wallet.URL().Scheme == hdwallet.Scheme {
+				continue
+			}
 			if err := wallet.Open(""); err != nil {
 				log.Warn("Failed to open wallet", "url", wallet.URL(), "err", err)
 			}
@@ -308,6 +319,9 @@ func startNode(ctx *cli.Context, stack *node.Node) {
 		for event := range events {
 			switch event.Kind {
 			case accounts.WalletArrived:
+				if event.Wallet.URL().Scheme == hdwallet.Scheme {
+					continue
+				}
 				if err := event.Wallet.Open(""); err != nil {
 					log.Warn("New wallet appeared, failed to open", "url", event.Wallet.URL(), "err", err)
 				}
@@ -34,6 +34,11 @@
 //     $ p2psim node connect node01 node02
 //     Connected node01 to node02
 //
+// The scenario command runs a scenario file in a network of local nodes
+// instead of using the API:
+//
+//     $ p2psim scenario --report report.json ring.json
+//
 package main
 
 import (
@@ -42,6 +47,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 
@@ -103,6 +109,108 @@ func main() {
 			Usage:  "load a network snapshot from stdin",
 			Action: loadSnapshot,
 		},
+		{
+			Name:   "link",
+			Usage:  "manage emulated links between nodes",
+			Action: showLinks,
+			Subcommands: []cli.Command{
+				{
+					Name:   "show",
+					Usage:  "show link configuration",
+					Action: showLinks,
+				},
+				{
+					Name:      "set",
+					ArgsUsage: "<node> <peer>",
+					Usage:     "configure the link between two nodes",
+					Action:    setLink,
+					Flags: []cli.Flag{
+						cli.DurationFlag{
+							Name:  "latency",
+							Usage: "one-way delay",
+						},
+						cli.DurationFlag{
+							Name:  "jitter",
+							Usage: "maximum random delay added to the latency",
+						},
+						cli.IntFlag{
+							Name:  "bandwidth",
+							Usage: "bandwidth in bytes per second and direction (0 = unlimited)",
+						},
+						cli.Float64Flag{
+							Name:  "loss",
+							Usage: "probability of a write being lost and retransmitted",
+						},
+						cli.BoolFlag{
+							Name:  "down",
+							Usage: "take the link down",
+						},
+					},
+				},
+				{
+					Name:      "reset",
+					ArgsUsage: "<node> <peer>",
+					Usage:     "restore the perfect link between two nodes",
+					Action:    resetLink,
+				},
+				{
+					Name:      "seed",
+					ArgsUsage: "<seed>",
+					Usage:     "seed the randomness of links",
+					Action:    seedLinks,
+				},
+			},
+		},
+		{
+			Name:      "partition",
+			ArgsUsage: "<nodes> <nodes> [<nodes>...]",
+			Usage:     "partition the network into groups of nodes (comma separated)",
+			Action:    partitionNetwork,
+		},
+		{
+			Name:   "heal",
+			Usage:  "remove a partition of the network",
+			Action: healNetwork,
+		},
+		{
+			Name:      "scenario",
+			ArgsUsage: "<file>",
+			Usage:     "run a scenario in a local simulation network",
+			Description: `
+Runs the JSON or YAML encoded scenario in a new network of local nodes and
+prints a report of the run, failing if an expectation wasn't met. Files ending
+in .yaml or .yml are read as YAML. Two services are available to the nodes:
+
+The "ping" service pings every peer periodically and the number of answers
+received by a node can be checked with the ping_received RPC method.
+
+The "eth" service is a full node sharing a test genesis block. Blocks are
+sealed with fake proof-of-work, about one per second, by the nodes started
+with an rpc event calling miner_start. Chain progress is checked with rpc
+expectations of eth_blockNumber.
+
+Message expectations are not supported by the exec adapter.`,
+			Action: runScenario,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "adapter",
+					Value: "sim",
+					Usage: `node adapter to use (one of "sim" or "exec")`,
+				},
+				cli.StringFlag{
+					Name:  "basedir",
+					Usage: "data directory of exec adapter nodes (default: temporary directory)",
+				},
+				cli.StringFlag{
+					Name:  "report",
+					Usage: "write the report to the given file instead of stdout",
+				},
+				cli.DurationFlag{
+					Name:  "timeout",
+					Usage: "abort the scenario after the given time",
+				},
+			},
+		},
 		{
 			Name:   "node",
 			Usage:  "manage simulation nodes",
@@ -378,6 +486,98 @@ func disconnectNode(ctx *cli.Context) error {
 	return nil
 }
 
+func showLinks(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return cli.ShowCommandHelp(ctx, ctx.Command.Name)
+	}
+	state, err := client.GetLinks()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(ctx.App.Writer, 1, 2, 2, ' ', 0)
+	defer w.Flush()
+	fmt.Fprintf(w, "SEED\t%d\n", state.Seed)
+	fmt.Fprintf(w, "NODE\tPEER\tLATENCY\tJITTER\tBANDWIDTH\tLOSS\tDOWN\n")
+	for _, l := range state.Links {
+		c := l.Config
+		fmt.Fprintf(w, "%s\t%s\t%v\t%v\t%d\t%v\t%t\n", l.One.TerminalString(), l.Other.TerminalString(), c.Latency, c.Jitter, c.Bandwidth, c.Loss, c.Down)
+	}
+	return nil
+}
+
+func setLink(ctx *cli.Context) error {
+	args := ctx.Args()
+	if len(args) != 2 {
+		return cli.ShowCommandHelp(ctx, ctx.Command.Name)
+	}
+	config := adapters.LinkConfig{
+		Latency:   ctx.Duration("latency"),
+		Jitter:    ctx.Duration("jitter"),
+		Bandwidth: ctx.Int("bandwidth"),
+		Loss:      ctx.Float64("loss"),
+		Down:      ctx.Bool("down"),
+	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	if err := client.SetLink(args[0], args[1], config); err != nil {
+		return err
+	}
+	fmt.Fprintln(ctx.App.Writer, "Configured link between", args[0], "and", args[1])
+	return nil
+}
+
+func resetLink(ctx *cli.Context) error {
+	args := ctx.Args()
+	if len(args) != 2 {
+		return cli.ShowCommandHelp(ctx, ctx.Command.Name)
+	}
+	if err := client.ResetLink(args[0], args[1]); err != nil {
+		return err
+	}
+	fmt.Fprintln(ctx.App.Writer, "Reset link between", args[0], "and", args[1])
+	return nil
+}
+
+func seedLinks(ctx *cli.Context) error {
+	args := ctx.Args()
+	if len(args) != 1 {
+		return cli.ShowCommandHelp(ctx, ctx.Command.Name)
+	}
+	seed, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid seed: %v", err)
+	}
+	return client.SetLinkSeed(seed)
+}
+
+func partitionNetwork(ctx *cli.Context) error {
+	args := ctx.Args()
+	if len(args) < 2 {
+		return cli.ShowCommandHelp(ctx, ctx.Command.Name)
+	}
+	groups := make([][]string, len(args))
+	for i, arg := range args {
+		groups[i] = strings.Split(arg, ",")
+	}
+	if err := client.Partition(groups); err != nil {
+		return err
+	}
+	fmt.Fprintln(ctx.App.Writer, "Partitioned network into", len(groups), "groups")
+	return nil
+}
+
+func healNetwork(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return cli.ShowCommandHelp(ctx, ctx.Command.Name)
+	}
+	if err := client.Heal(); err != nil {
+		return err
+	}
+	fmt.Fprintln(ctx.App.Writer, "Healed network")
+	return nil
+}
+
 func rpcNode(ctx *cli.Context) error {
 	args := ctx.Args()
 	if len(args) < 2 {
@@ -0,0 +1,213 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/consensus/ethash"
+	"github.com/rwdxchain/go-rwdxchaina/core"
+	"github.com/rwdxchain/go-rwdxchaina/eth"
+	"github.com/rwdxchain/go-rwdxchaina/eth/downloader"
+	"github.com/rwdxchain/go-rwdxchaina/node"
+	"github.com/rwdxchain/go-rwdxchaina/p2p"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/simulations"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/simulations/adapters"
+	"github.com/rwdxchain/go-rwdxchaina/params"
+	"github.com/rwdxchain/go-rwdxchaina/rpc"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// scenarioServices are the services available to scenarios run by p2psim.
+var scenarioServices = adapters.Services{
+	"ping": func(ctx *adapters.ServiceContext) (node.Service, error) {
+		return new(pingService), nil
+	},
+	"eth": newScenarioEthereum,
+}
+
+// scenarioGenesis is the genesis block shared by the nodes of the eth service.
+var scenarioGenesis = &core.Genesis{
+	Config:     params.AllEthashProtocolChanges,
+	GasLimit:   params.GenesisGasLimit,
+	Difficulty: params.MinimumDifficulty,
+}
+
+// newScenarioEthereum creates a full node syncing the chain of the scenario.
+// Blocks are sealed with fake proof-of-work, so mining is instant and nodes
+// only produce blocks once told to with the miner_start RPC method.
+func newScenarioEthereum(ctx *adapters.ServiceContext) (node.Service, error) {
+	config := eth.DefaultConfig
+	config.Genesis = scenarioGenesis
+	config.NetworkId = 1337
+	config.SyncMode = downloader.FullSync
+	config.DatabaseCache = 16
+	config.TrieCache = 16
+	config.Ethash.PowMode = ethash.ModeFake
+	config.Etherbase = common.Address{0x01}
+	config.TxPool.Journal = ""
+	return eth.New(ctx.NodeContext, &config)
+}
+
+func init() {
+	// Register the services so the exec adapter can start them in
+	// child processes.
+	adapters.RegisterServices(scenarioServices)
+}
+
+func runScenario(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return cli.ShowCommandHelp(ctx, ctx.Command.Name)
+	}
+	sc, err := simulations.LoadScenario(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	var adapter adapters.NodeAdapter
+	switch ctx.String("adapter") {
+	case "sim":
+		adapter = adapters.NewSimAdapter(scenarioServices)
+	case "exec":
+		basedir := ctx.String("basedir")
+		if basedir == "" {
+			if basedir, err = ioutil.TempDir("", "p2psim-scenario"); err != nil {
+				return err
+			}
+			defer os.RemoveAll(basedir)
+		}
+		adapter = adapters.NewExecAdapter(basedir)
+	default:
+		return fmt.Errorf("unknown node adapter %q", ctx.String("adapter"))
+	}
+	network := simulations.NewNetwork(adapter, &simulations.NetworkConfig{})
+	defer network.Shutdown()
+
+	runctx := context.Background()
+	if timeout := ctx.Duration("timeout"); timeout > 0 {
+		var cancel context.CancelFunc
+		runctx, cancel = context.WithTimeout(runctx, timeout)
+		defer cancel()
+	}
+	report, err := simulations.RunScenario(runctx, network, sc)
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if file := ctx.String("report"); file != "" {
+		if err := ioutil.WriteFile(file, out, 0644); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintln(ctx.App.Writer, string(out))
+	}
+	if !report.Success {
+		return fmt.Errorf("scenario %q failed", sc.Name)
+	}
+	return nil
+}
+
+const (
+	pingMsgCode = iota
+	pongMsgCode
+)
+
+// pingInterval is the interval in which the ping service pings its peers.
+const pingInterval = 2 * time.Second
+
+// pingService runs a protocol which pings connected peers, answering their
+// pings with pongs. The number of received pongs is available through the
+// ping_received RPC method.
+type pingService struct {
+	received uint64
+}
+
+func (s *pingService) Protocols() []p2p.Protocol {
+	return []p2p.Protocol{{
+		Name:    "ping",
+		Version: 1,
+		Length:  2,
+		Run:     s.run,
+	}}
+}
+
+func (s *pingService) APIs() []rpc.API {
+	return []rpc.API{{Namespace: "ping", Version: "1.0", Service: &PingAPI{s}, Public: true}}
+}
+
+func (s *pingService) Start(server *p2p.Server) error { return nil }
+
+func (s *pingService) Stop() error { return nil }
+
+func (s *pingService) run(peer *p2p.Peer, rw p2p.MsgReadWriter) error {
+	errc := make(chan error, 2)
+	quit := make(chan struct{})
+	defer close(quit)
+	go func() {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+		for {
+			if err := p2p.Send(rw, pingMsgCode, "PING"); err != nil {
+				errc <- err
+				return
+			}
+			select {
+			case <-ticker.C:
+			case <-quit:
+				return
+			}
+		}
+	}()
+	go func() {
+		for {
+			msg, err := rw.ReadMsg()
+			if err != nil {
+				errc <- err
+				return
+			}
+			msg.Discard()
+			switch msg.Code {
+			case pingMsgCode:
+				go p2p.Send(rw, pongMsgCode, "PONG")
+			case pongMsgCode:
+				atomic.AddUint64(&s.received, 1)
+			}
+		}
+	}()
+	return <-errc
+}
+
+// PingAPI provides the RPC methods of the ping service.
+type PingAPI struct {
+	s *pingService
+}
+
+// Received returns the number of pongs received by the node.
+func (api *PingAPI) Received() uint64 {
+	return atomic.LoadUint64(&api.s.received)
+}
@@ -123,6 +123,14 @@ var (
 		Name:  "keystore",
 		Usage: "Directory for the keystore (default = inside the datadir)",
 	}
+	KeyStoreDBFlag = cli.StringFlag{
+		Name:  "keystore.db",
+		Usage: "Key database file holding additional accounts",
+	}
+	RemoteKeyStoreFlag = cli.StringFlag{
+		Name:  "keystore.remote",
+		Usage: "Remote signing service endpoint holding additional accounts (https unless on localhost)",
+	}
 	NoUSBFlag = cli.BoolFlag{
 		Name:  "nousb",
 		Usage: "Disables monitoring for and managing USB hardware wallets",
@@ -163,6 +171,14 @@ var (
 		Usage: `Blockchain sync mode ("fast", "full", or "light")`,
 		Value: &defaultSyncMode,
 	}
+	SyncFromFlag = cli.StringFlag{
+		Name:  "syncfrom",
+		Usage: "Trusted block hash to start syncing an empty chain from, skipping all earlier blocks",
+	}
+	SyncFromTDFlag = BigFlag{
+		Name:  "syncfrom.td",
+		Usage: "Total difficulty of the trusted block to start syncing from (required with --syncfrom)",
+	}
 	GCModeFlag = cli.StringFlag{
 		Name:  "gcmode",
 		Usage: `Blockchain garbage collection mode ("full", "archive")`,
@@ -178,6 +194,15 @@ var (
 		Usage: "Maximum number of LES client peers",
 		Value: eth.DefaultConfig.LightPeers,
 	}
+	ULCServersFlag = cli.StringFlag{
+		Name:  "ulc.servers",
+		Usage: "Comma separated enode URLs of the trusted servers for the ultra-light client mode",
+	}
+	ULCFractionFlag = cli.IntFlag{
+		Name:  "ulc.fraction",
+		Usage: "Percentage of trusted servers that must announce a head for the ultra-light client to accept it",
+		Value: eth.DefaultULCMinTrustedFraction,
+	}
 	LightKDFFlag = cli.BoolFlag{
 		Name:  "lightkdf",
 		Usage: "Reduce key-derivation RAM & CPU usage at some expense of KDF strength",
@@ -496,12 +521,12 @@ var (
 	}
 	BootnodesFlag = cli.StringFlag{
 		Name:  "bootnodes",
-		Usage: "Comma separated enode URLs for P2P discovery bootstrap (set v4+v5 instead for light servers)",
+		Usage: "Comma separated enode or enr URLs for P2P discovery bootstrap (set v4+v5 instead for light servers)",
 		Value: "",
 	}
 	BootnodesV4Flag = cli.StringFlag{
 		Name:  "bootnodesv4",
-		Usage: "Comma separated enode URLs for P2P v4 discovery bootstrap (light server, full nodes)",
+		Usage: "Comma separated enode or enr URLs for P2P v4 discovery bootstrap (light server, full nodes)",
 		Value: "",
 	}
 	BootnodesV5Flag = cli.StringFlag{
@@ -530,6 +555,10 @@ var (
 		Name:  "v5disc",
 		Usage: "Enables the experimental RLPx V5 (Topic Discovery) mechanism",
 	}
+	DNSDiscoveryFlag = cli.StringFlag{
+		Name:  "discovery.dns",
+		Usage: "Comma separated enrtree:// URLs of DNS node lists to dial peers from",
+	}
 	NetrestrictFlag = cli.StringFlag{
 		Name:  "netrestrict",
 		Usage: "Restricts network communication to the given IP networks (CIDR masks)",
@@ -715,6 +744,18 @@ func setBootstrapNodesV5(ctx *cli.Context, cfg *p2p.Config) {
 	}
 }
 
+// setDNSDiscovery sets the DNS node lists to dial peers from.
+func setDNSDiscovery(ctx *cli.Context, cfg *p2p.Config) {
+	if ctx.GlobalIsSet(DNSDiscoveryFlag.Name) {
+		cfg.DiscoveryDNS = nil
+		for _, url := range strings.Split(ctx.GlobalString(DNSDiscoveryFlag.Name), ",") {
+			if url = strings.TrimSpace(url); url != "" {
+				cfg.DiscoveryDNS = append(cfg.DiscoveryDNS, url)
+			}
+		}
+	}
+}
+
 // setListenAddress creates a TCP listening address string from set command
 // line flags.
 func setListenAddress(ctx *cli.Context, cfg *p2p.Config) {
@@ -889,6 +930,7 @@ func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 	setListenAddress(ctx, cfg)
 	setBootstrapNodes(ctx, cfg)
 	setBootstrapNodesV5(ctx, cfg)
+	setDNSDiscovery(ctx, cfg)
 
 	lightClient := ctx.GlobalString(SyncModeFlag.Name) == "light"
 	lightServer := ctx.GlobalInt(LightServFlag.Name) != 0
@@ -972,6 +1014,12 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.GlobalIsSet(KeyStoreDirFlag.Name) {
 		cfg.KeyStoreDir = ctx.GlobalString(KeyStoreDirFlag.Name)
 	}
+	if ctx.GlobalIsSet(KeyStoreDBFlag.Name) {
+		cfg.KeyStoreDB = ctx.GlobalString(KeyStoreDBFlag.Name)
+	}
+	if ctx.GlobalIsSet(RemoteKeyStoreFlag.Name) {
+		cfg.RemoteKeyStore = ctx.GlobalString(RemoteKeyStoreFlag.Name)
+	}
 	if ctx.GlobalIsSet(LightKDFFlag.Name) {
 		cfg.UseLightweightKDF = ctx.GlobalBool(LightKDFFlag.Name)
 	}
@@ -1101,6 +1149,22 @@ func SetShhConfig(ctx *cli.Context, stack *node.Node, cfg *whisper.Config) {
 	}
 }
 
+// setULC configures the ultra-light client mode from the command line flags.
+func setULC(ctx *cli.Context, cfg *eth.Config) {
+	if !ctx.GlobalIsSet(ULCServersFlag.Name) {
+		return
+	}
+	if cfg.SyncMode != downloader.LightSync {
+		Fatalf("Ultra-light client mode is only available with --%s=light", SyncModeFlag.Name)
+	}
+	cfg.ULC = &eth.ULCConfig{MinTrustedFraction: ctx.GlobalInt(ULCFractionFlag.Name)}
+	for _, url := range strings.Split(ctx.GlobalString(ULCServersFlag.Name), ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			cfg.ULC.TrustedServers = append(cfg.ULC.TrustedServers, url)
+		}
+	}
+}
+
 // SetEthConfig applies eth-related command line flags to the config.
 func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	// Avoid conflicting network flags
@@ -1116,6 +1180,17 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if ctx.GlobalIsSet(SyncModeFlag.Name) {
 		cfg.SyncMode = *GlobalTextMarshaler(ctx, SyncModeFlag.Name).(*downloader.SyncMode)
 	}
+	if ctx.GlobalIsSet(SyncFromFlag.Name) {
+		hex := ctx.GlobalString(SyncFromFlag.Name)
+		if len(hex) != 2*common.HashLength+2 || !strings.HasPrefix(hex, "0x") {
+			Fatalf("Invalid sync checkpoint hash %q", hex)
+		}
+		cfg.SyncFrom = common.HexToHash(hex)
+		if !ctx.GlobalIsSet(SyncFromTDFlag.Name) {
+			Fatalf("Sync checkpoint %s requires its total difficulty (--%s)", hex, SyncFromTDFlag.Name)
+		}
+		cfg.SyncFromTD = GlobalBig(ctx, SyncFromTDFlag.Name)
+	}
 	if ctx.GlobalIsSet(LightServFlag.Name) {
 		cfg.LightServ = ctx.GlobalInt(LightServFlag.Name)
 	}
@@ -1125,6 +1200,7 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if ctx.GlobalIsSet(NetworkIdFlag.Name) {
 		cfg.NetworkId = ctx.GlobalUint64(NetworkIdFlag.Name)
 	}
+	setULC(ctx, cfg)
 
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheDatabaseFlag.Name) {
 		cfg.DatabaseCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheDatabaseFlag.Name) / 100
@@ -104,19 +104,30 @@ func (b *bridge) OpenWallet(call otto.FunctionCall) (response otto.Value) {
 	if err == nil {
 		return val
 	}
-	// Wallet open failed, report error unless it's a PIN entry
-	if !strings.HasSuffix(err.Error(), usbwallet.ErrTrezorPINNeeded.Error()) {
+	// Wallet open failed, report error unless it's a PIN or passphrase entry
+	if strings.HasSuffix(err.Error(), usbwallet.ErrTrezorPINNeeded.Error()) {
+		// Trezor PIN matrix input requested, display the matrix to the user and fetch the data
+		fmt.Fprintf(b.printer, "Look at the device for number positions\n\n")
+		fmt.Fprintf(b.printer, "7 | 8 | 9\n")
+		fmt.Fprintf(b.printer, "--+---+--\n")
+		fmt.Fprintf(b.printer, "4 | 5 | 6\n")
+		fmt.Fprintf(b.printer, "--+---+--\n")
+		fmt.Fprintf(b.printer, "1 | 2 | 3\n\n")
+
+		if input, err := b.prompter.PromptPassword("Please enter current PIN: "); err != nil {
+			throwJSException(err.Error())
+		} else {
+			passwd, _ = otto.ToValue(input)
+		}
+		if val, err = call.Otto.Call("jeth.openWallet", nil, wallet, passwd); err == nil {
+			return val
+		}
+	}
+	if !strings.HasSuffix(err.Error(), usbwallet.ErrTrezorPassphraseNeeded.Error()) {
 		throwJSException(err.Error())
 	}
-	// Trezor PIN matrix input requested, display the matrix to the user and fetch the data
-	fmt.Fprintf(b.printer, "Look at the device for number positions\n\n")
-	fmt.Fprintf(b.printer, "7 | 8 | 9\n")
-	fmt.Fprintf(b.printer, "--+---+--\n")
-	fmt.Fprintf(b.printer, "4 | 5 | 6\n")
-	fmt.Fprintf(b.printer, "--+---+--\n")
-	fmt.Fprintf(b.printer, "1 | 2 | 3\n\n")
-
-	if input, err := b.prompter.PromptPassword("Please enter current PIN: "); err != nil {
+	// Trezor passphrase input requested, fetch it from the user
+	if input, err := b.prompter.PromptPassword("Please enter your passphrase: "); err != nil {
 		throwJSException(err.Error())
 	} else {
 		passwd, _ = otto.ToValue(input)
@@ -0,0 +1,113 @@
+pragma solidity ^0.4.24;
+
+/// @title Checkpoint oracle
+///
+/// @notice Reference implementation of the checkpoint oracle. The deployed code
+/// is assembled from oracle.easm and deploy.easm by gencode.go, this contract
+/// documents their behaviour and pins the same storage layout, which light
+/// clients read directly with merkle proofs (see ReadCheckpoint in oracle.go).
+contract CheckpointOracle {
+    // Signature of the latest checkpoint, 3 storage slots each.
+    struct Signature {
+        uint256 v;
+        bytes32 r;
+        bytes32 s;
+    }
+
+    uint256 threshold;        // slot 0: number of admin signatures required
+    uint256 adminCount;       // slot 1: number of admins
+    uint256 latestIndex;      // slot 2: latest section index + 1 (0 = none registered)
+    bytes32 sectionHead;      // slot 3
+    bytes32 chtRoot;          // slot 4
+    bytes32 bloomTrieRoot;    // slot 5
+    uint256 sigCount;         // slot 6: number of signatures of the latest checkpoint
+    uint256[249] reserved;    // slots 7-255
+    address[256] admins;      // slots 256-511
+    Signature[256] sigs;      // slots 512+3*i
+
+    /// @notice NewCheckpoint is emitted when a checkpoint is registered.
+    event NewCheckpoint(uint64 indexed index, bytes32 checkpointHash, bytes32 chtRoot, bytes32 bloomTrieRoot);
+
+    /// @param _adminlist addresses allowed to sign checkpoints
+    /// @param _threshold number of signatures required to register a checkpoint
+    constructor(address[] _adminlist, uint256 _threshold) public {
+        require(_threshold > 0 && _threshold <= _adminlist.length);
+        require(_adminlist.length <= 256);
+
+        adminCount = _adminlist.length;
+        threshold = _threshold;
+        for (uint256 i = 0; i < _adminlist.length; i++) {
+            admins[i] = _adminlist[i];
+        }
+    }
+
+    /// @notice Returns the latest registered checkpoint, all zero if none.
+    function GetLatestCheckpoint() public view returns (uint64, bytes32, bytes32, bytes32) {
+        uint64 index = 0;
+        if (latestIndex != 0) {
+            index = uint64(latestIndex - 1);
+        }
+        return (index, sectionHead, chtRoot, bloomTrieRoot);
+    }
+
+    /// @notice Returns the addresses allowed to sign checkpoints.
+    function GetAllAdmin() public view returns (address[]) {
+        address[] memory list = new address[](adminCount);
+        for (uint256 i = 0; i < adminCount; i++) {
+            list[i] = admins[i];
+        }
+        return list;
+    }
+
+    /// @notice Returns the number of signatures required to register a checkpoint.
+    function GetThreshold() public view returns (uint256) {
+        return threshold;
+    }
+
+    /// @notice Registers a checkpoint newer than the latest one. The signatures
+    /// are over keccak256(address(this), index, head, chtRoot, bloomTrieRoot),
+    /// each 32 bytes wide, and must be ordered by ascending signer address so
+    /// that no admin is counted twice.
+    function SetCheckpoint(
+        uint64 _sectionIndex,
+        bytes32 _sectionHead,
+        bytes32 _chtRoot,
+        bytes32 _bloomTrieRoot,
+        uint8[] v,
+        bytes32[] r,
+        bytes32[] s
+    ) public {
+        require(uint256(_sectionIndex) + 1 > latestIndex);
+        require(v.length == r.length && v.length == s.length);
+        require(v.length >= threshold);
+
+        bytes32 hash = keccak256(abi.encode(address(this), uint256(_sectionIndex), _sectionHead, _chtRoot, _bloomTrieRoot));
+
+        address last = address(0);
+        for (uint256 i = 0; i < v.length; i++) {
+            address signer = ecrecover(hash, v[i], r[i], s[i]);
+            require(signer > last);
+            require(isAdmin(signer));
+            last = signer;
+
+            sigs[i] = Signature(v[i], r[i], s[i]);
+        }
+        latestIndex = uint256(_sectionIndex) + 1;
+        sectionHead = _sectionHead;
+        chtRoot = _chtRoot;
+        bloomTrieRoot = _bloomTrieRoot;
+        sigCount = v.length;
+
+        emit NewCheckpoint(_sectionIndex, _sectionHead, _chtRoot, _bloomTrieRoot);
+    }
+
+    // isAdmin returns whether the address is allowed to sign checkpoints.
+    function isAdmin(address addr) internal view returns (bool) {
+        for (uint256 i = 0; i < adminCount; i++) {
+            if (admins[i] == addr) {
+                return true;
+            }
+        }
+        return false;
+    }
+}
@@ -0,0 +1,93 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build none
+// +build none
+
+// This program generates contract/oracle.bin, which contains the checkpoint
+// oracle deployment code, by assembling contract/deploy.easm and contract/oracle.easm.
+// The equivalent Solidity source is kept in contract/oracle.sol for reference.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"text/template"
+
+	"github.com/rwdxchain/go-rwdxchaina/core/asm"
+)
+
+// deployParams are the code lengths filled into the deployment code template.
+type deployParams struct {
+	DeployLen  int // Length of the deployment code
+	RuntimeLen int // Length of the runtime code
+	ArgsOffset int // Offset of the constructor arguments (deployment + runtime length)
+}
+
+// assemble compiles an EVM assembly source into its hex encoded binary form.
+func assemble(name string, source []byte) string {
+	compiler := asm.NewCompiler(false)
+	compiler.Feed(asm.Lex(name, source, false))
+
+	bin, errs := compiler.Compile()
+	if len(errs) != 0 {
+		for _, err := range errs {
+			fmt.Println(err)
+		}
+		panic(fmt.Sprintf("failed to assemble %s", name))
+	}
+	return bin
+}
+
+func main() {
+	source, err := ioutil.ReadFile("contract/oracle.easm")
+	if err != nil {
+		panic(err)
+	}
+	runtime := assemble("oracle.easm", source)
+
+	source, err = ioutil.ReadFile("contract/deploy.easm")
+	if err != nil {
+		panic(err)
+	}
+	tmpl := template.Must(template.New("deploy").Parse(string(source)))
+
+	// The deployment code embeds its own length, so assemble it until the
+	// length of the pushed constants settles.
+	var (
+		params = deployParams{RuntimeLen: len(runtime) / 2}
+		deploy string
+	)
+	for {
+		params.ArgsOffset = params.DeployLen + params.RuntimeLen
+
+		buf := new(bytes.Buffer)
+		if err := tmpl.Execute(buf, params); err != nil {
+			panic(err)
+		}
+		deploy = assemble("deploy.easm", buf.Bytes())
+		if len(deploy)/2 == params.DeployLen {
+			break
+		}
+		params.DeployLen = len(deploy) / 2
+	}
+	code := strings.ToLower(deploy + runtime)
+	if err := ioutil.WriteFile("contract/oracle.bin", []byte(code+"\n"), 0644); err != nil {
+		panic(err)
+	}
+}
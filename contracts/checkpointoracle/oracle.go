@@ -0,0 +1,230 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package checkpointoracle is an on-chain light client checkpoint oracle, where
+// a threshold of trusted signers register the CHT and bloom trie roots of the
+// processed sections of the chain.
+package checkpointoracle
+
+//go:generate go run ./gencode.go
+//go:generate abigen --abi contract/oracle.abi --bin contract/oracle.bin --pkg contract --type CheckpointOracle --out contract/oracle.go
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/rwdxchain/go-rwdxchaina/accounts/abi/bind"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/contracts/checkpointoracle/contract"
+	"github.com/rwdxchain/go-rwdxchaina/core/types"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+)
+
+// maxSignatures is the maximum number of signatures read from the contract storage.
+const maxSignatures = 256
+
+var (
+	errInvalidSignature       = errors.New("invalid checkpoint signature")
+	errInsufficientSignatures = errors.New("insufficient trusted checkpoint signatures")
+)
+
+// Checkpoint is a set of post-processed trie roots (CHT and bloom trie) associated
+// with the section index and head hash they belong to.
+type Checkpoint struct {
+	SectionIndex uint64      `json:"sectionIndex"`
+	SectionHead  common.Hash `json:"sectionHead"`
+	CHTRoot      common.Hash `json:"chtRoot"`
+	BloomRoot    common.Hash `json:"bloomRoot"`
+}
+
+// SigningHash returns the hash signed by the oracle admins to approve the
+// checkpoint. It's the keccak256 hash of the ABI encoding of the oracle address,
+// the section index, the section head and the trie roots, binding the signatures
+// to a single oracle contract.
+func (c *Checkpoint) SigningHash(oracle common.Address) common.Hash {
+	var index [32]byte
+	binary.BigEndian.PutUint64(index[24:], c.SectionIndex)
+
+	return crypto.Keccak256Hash(common.LeftPadBytes(oracle[:], 32), index[:], c.SectionHead[:], c.CHTRoot[:], c.BloomRoot[:])
+}
+
+// Sign approves the checkpoint of the given oracle with a private key, returning
+// the signature in the [R || S || V] format, where V is 0 or 1.
+func (c *Checkpoint) Sign(oracle common.Address, key *ecdsa.PrivateKey) ([]byte, error) {
+	return crypto.Sign(c.SigningHash(oracle).Bytes(), key)
+}
+
+// VerifySigners checks that the signatures of a checkpoint were made by at least
+// threshold distinct members of the given trusted signer set.
+func (c *Checkpoint) VerifySigners(oracle common.Address, sigs [][]byte, signers []common.Address, threshold int) error {
+	hash := c.SigningHash(oracle)
+
+	approved := make(map[common.Address]bool)
+	for _, sig := range sigs {
+		pubkey, err := crypto.SigToPub(hash.Bytes(), sig)
+		if err != nil {
+			return errInvalidSignature
+		}
+		signer := crypto.PubkeyToAddress(*pubkey)
+		for _, trusted := range signers {
+			if signer == trusted {
+				approved[signer] = true
+				break
+			}
+		}
+	}
+	if len(approved) < threshold {
+		return fmt.Errorf("%v: have %d, want %d", errInsufficientSignatures, len(approved), threshold)
+	}
+	return nil
+}
+
+// Storage slots of the oracle contract state, see contract/oracle.easm.
+var (
+	slotLatestIndex = common.BigToHash(big.NewInt(2))
+	slotSectionHead = common.BigToHash(big.NewInt(3))
+	slotCHTRoot     = common.BigToHash(big.NewInt(4))
+	slotBloomRoot   = common.BigToHash(big.NewInt(5))
+	slotSigCount    = common.BigToHash(big.NewInt(6))
+	slotSigs        = int64(512)
+)
+
+// ReadCheckpoint reconstructs the latest registered checkpoint and its signatures
+// from the raw storage of an oracle contract, accessed through the given getter.
+// This allows light clients to retrieve the checkpoint with merkle proofs instead
+// of having to execute the contract. Nil is returned if no checkpoint has been
+// registered yet.
+func ReadCheckpoint(storage func(slot common.Hash) common.Hash) (*Checkpoint, [][]byte) {
+	latest := storage(slotLatestIndex).Big()
+	if latest.Sign() == 0 || !latest.IsUint64() {
+		return nil, nil
+	}
+	checkpoint := &Checkpoint{
+		SectionIndex: latest.Uint64() - 1,
+		SectionHead:  storage(slotSectionHead),
+		CHTRoot:      storage(slotCHTRoot),
+		BloomRoot:    storage(slotBloomRoot),
+	}
+	count := storage(slotSigCount).Big()
+	if !count.IsUint64() || count.Uint64() > maxSignatures {
+		return checkpoint, nil
+	}
+	sigs := make([][]byte, 0, count.Uint64())
+	for i := int64(0); i < count.Int64(); i++ {
+		var (
+			v = storage(common.BigToHash(big.NewInt(slotSigs + 3*i))).Big()
+			r = storage(common.BigToHash(big.NewInt(slotSigs + 3*i + 1)))
+			s = storage(common.BigToHash(big.NewInt(slotSigs + 3*i + 2)))
+		)
+		if !v.IsUint64() || v.Uint64() < 27 || v.Uint64() > 28 {
+			continue
+		}
+		sig := make([]byte, 65)
+		copy(sig, r[:])
+		copy(sig[32:], s[:])
+		sig[64] = byte(v.Uint64() - 27)
+		sigs = append(sigs, sig)
+	}
+	return checkpoint, sigs
+}
+
+// CheckpointOracle is a Go wrapper around an on-chain checkpoint oracle contract.
+type CheckpointOracle struct {
+	address  common.Address
+	contract *contract.CheckpointOracle
+}
+
+// NewCheckpointOracle binds checkpoint contract and returns a registrar instance.
+func NewCheckpointOracle(contractAddr common.Address, backend bind.ContractBackend) (*CheckpointOracle, error) {
+	c, err := contract.NewCheckpointOracle(contractAddr, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &CheckpointOracle{address: contractAddr, contract: c}, nil
+}
+
+// DeployCheckpointOracle deploys a new checkpoint oracle, administered by the
+// given signers of which threshold must approve every checkpoint.
+func DeployCheckpointOracle(opts *bind.TransactOpts, backend bind.ContractBackend, admins []common.Address, threshold uint64) (common.Address, *types.Transaction, *CheckpointOracle, error) {
+	addr, tx, c, err := contract.DeployCheckpointOracle(opts, backend, admins, new(big.Int).SetUint64(threshold))
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return addr, tx, &CheckpointOracle{address: addr, contract: c}, nil
+}
+
+// ContractAddr returns the address of the contract.
+func (oracle *CheckpointOracle) ContractAddr() common.Address {
+	return oracle.address
+}
+
+// Contract returns the underlying contract instance.
+func (oracle *CheckpointOracle) Contract() *contract.CheckpointOracle {
+	return oracle.contract
+}
+
+// LatestCheckpoint retrieves the latest registered checkpoint of the oracle, or
+// nil if no checkpoint has been registered yet.
+func (oracle *CheckpointOracle) LatestCheckpoint(opts *bind.CallOpts) (*Checkpoint, error) {
+	index, head, cht, bloom, err := oracle.contract.GetLatestCheckpoint(opts)
+	if err != nil {
+		return nil, err
+	}
+	if head == (common.Hash{}) {
+		return nil, nil
+	}
+	return &Checkpoint{SectionIndex: index, SectionHead: head, CHTRoot: cht, BloomRoot: bloom}, nil
+}
+
+// RegisterCheckpoint registers a checkpoint approved by the given signatures in
+// the oracle. The signatures are ordered by their signers as the contract needs.
+func (oracle *CheckpointOracle) RegisterCheckpoint(opts *bind.TransactOpts, checkpoint *Checkpoint, sigs [][]byte) (*types.Transaction, error) {
+	hash := checkpoint.SigningHash(oracle.address)
+
+	type signature struct {
+		signer common.Address
+		sig    []byte
+	}
+	sorted := make([]signature, 0, len(sigs))
+	for _, sig := range sigs {
+		if len(sig) != 65 {
+			return nil, errInvalidSignature
+		}
+		pubkey, err := crypto.SigToPub(hash.Bytes(), sig)
+		if err != nil {
+			return nil, errInvalidSignature
+		}
+		sorted = append(sorted, signature{crypto.PubkeyToAddress(*pubkey), sig})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].signer[:], sorted[j].signer[:]) < 0
+	})
+	var (
+		v    []uint8
+		r, s [][32]byte
+	)
+	for _, sig := range sorted {
+		v = append(v, sig.sig[64]+27)
+		r = append(r, common.BytesToHash(sig.sig[:32]))
+		s = append(s, common.BytesToHash(sig.sig[32:64]))
+	}
+	return oracle.contract.SetCheckpoint(opts, checkpoint.SectionIndex, checkpoint.SectionHead, checkpoint.CHTRoot, checkpoint.BloomRoot, v, r, s)
+}
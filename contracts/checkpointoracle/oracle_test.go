@@ -0,0 +1,208 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package checkpointoracle
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/rwdxchain/go-rwdxchaina/accounts/abi/bind"
+	"github.com/rwdxchain/go-rwdxchaina/accounts/abi/bind/backends"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/core"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+)
+
+// testAdmin is an oracle admin used for testing.
+type testAdmin struct {
+	key  *ecdsa.PrivateKey
+	addr common.Address
+}
+
+// newTestAdmins creates a number of admins sorted by address.
+func newTestAdmins(n int) []testAdmin {
+	admins := make([]testAdmin, n)
+	for i := range admins {
+		key, _ := crypto.GenerateKey()
+		admins[i] = testAdmin{key: key, addr: crypto.PubkeyToAddress(key.PublicKey)}
+	}
+	sort.Slice(admins, func(i, j int) bool {
+		return bytes.Compare(admins[i].addr[:], admins[j].addr[:]) < 0
+	})
+	return admins
+}
+
+// newTestOracle deploys a checkpoint oracle administered by the given admins on a
+// simulated chain.
+func newTestOracle(t *testing.T, admins []testAdmin, threshold uint64) (*backends.SimulatedBackend, *bind.TransactOpts, *CheckpointOracle) {
+	alloc := make(core.GenesisAlloc)
+	for _, admin := range admins {
+		alloc[admin.addr] = core.GenesisAccount{Balance: big.NewInt(1000000000000000000)}
+	}
+	backend := backends.NewSimulatedBackend(alloc, 10000000)
+	auth := bind.NewKeyedTransactor(admins[0].key)
+
+	var addrs []common.Address
+	for _, admin := range admins {
+		addrs = append(addrs, admin.addr)
+	}
+	_, _, oracle, err := DeployCheckpointOracle(auth, backend, addrs, threshold)
+	if err != nil {
+		t.Fatalf("failed to deploy oracle: %v", err)
+	}
+	backend.Commit()
+	return backend, auth, oracle
+}
+
+// signCheckpoint signs a checkpoint with each of the given admins.
+func signCheckpoint(t *testing.T, oracle *CheckpointOracle, checkpoint *Checkpoint, admins ...testAdmin) [][]byte {
+	var sigs [][]byte
+	for _, admin := range admins {
+		sig, err := checkpoint.Sign(oracle.ContractAddr(), admin.key)
+		if err != nil {
+			t.Fatalf("failed to sign checkpoint: %v", err)
+		}
+		sigs = append(sigs, sig)
+	}
+	return sigs
+}
+
+// Tests that the oracle is deployed with the configured admins and threshold.
+func TestCheckpointOracleDeploy(t *testing.T) {
+	admins := newTestAdmins(3)
+	_, _, oracle := newTestOracle(t, admins, 2)
+
+	list, err := oracle.Contract().GetAllAdmin(nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve admins: %v", err)
+	}
+	if len(list) != len(admins) {
+		t.Fatalf("admin count mismatch: have %d, want %d", len(list), len(admins))
+	}
+	for i, admin := range admins {
+		if list[i] != admin.addr {
+			t.Errorf("admin %d mismatch: have %x, want %x", i, list[i], admin.addr)
+		}
+	}
+	threshold, err := oracle.Contract().GetThreshold(nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve threshold: %v", err)
+	}
+	if threshold.Uint64() != 2 {
+		t.Fatalf("threshold mismatch: have %d, want %d", threshold, 2)
+	}
+	if checkpoint, err := oracle.LatestCheckpoint(nil); err != nil || checkpoint != nil {
+		t.Fatalf("unexpected checkpoint in fresh oracle: %v, %v", checkpoint, err)
+	}
+	// Thresholds above the number of admins are rejected
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{admins[0].addr: {Balance: big.NewInt(1000000000000000000)}}, 10000000)
+	if _, _, _, err := DeployCheckpointOracle(bind.NewKeyedTransactor(admins[0].key), backend, []common.Address{admins[0].addr}, 2); err == nil {
+		t.Fatalf("oracle deployed with unreachable threshold")
+	}
+}
+
+// Tests that checkpoints are only registered if approved by a threshold of admins.
+func TestCheckpointOracleRegister(t *testing.T) {
+	var (
+		admins                = newTestAdmins(3)
+		backend, auth, oracle = newTestOracle(t, admins, 2)
+		outsider              = newTestAdmins(1)[0]
+	)
+	checkpoint := &Checkpoint{
+		SectionIndex: 3,
+		SectionHead:  common.HexToHash("0x01"),
+		CHTRoot:      common.HexToHash("0x02"),
+		BloomRoot:    common.HexToHash("0x03"),
+	}
+	// Insufficient, duplicate or foreign signatures must be rejected
+	for i, sigs := range [][][]byte{
+		signCheckpoint(t, oracle, checkpoint, admins[0]),
+		signCheckpoint(t, oracle, checkpoint, admins[1], admins[1]),
+		signCheckpoint(t, oracle, checkpoint, admins[2], outsider),
+	} {
+		if _, err := oracle.RegisterCheckpoint(auth, checkpoint, sigs); err == nil {
+			t.Errorf("test %d: invalid checkpoint approval accepted", i)
+		}
+	}
+	// A threshold of admin signatures registers the checkpoint
+	sigs := signCheckpoint(t, oracle, checkpoint, admins[2], admins[0])
+	if _, err := oracle.RegisterCheckpoint(auth, checkpoint, sigs); err != nil {
+		t.Fatalf("failed to register checkpoint: %v", err)
+	}
+	backend.Commit()
+
+	latest, err := oracle.LatestCheckpoint(nil)
+	if err != nil {
+		t.Fatalf("failed to retrieve latest checkpoint: %v", err)
+	}
+	if !reflect.DeepEqual(latest, checkpoint) {
+		t.Fatalf("checkpoint mismatch: have %+v, want %+v", latest, checkpoint)
+	}
+	// The registration event must be emitted
+	events, err := oracle.Contract().FilterNewCheckpoint(nil, []uint64{checkpoint.SectionIndex})
+	if err != nil {
+		t.Fatalf("failed to filter events: %v", err)
+	}
+	if !events.Next() {
+		t.Fatalf("checkpoint event missing: %v", events.Error())
+	}
+	if event := events.Event; event.Index != checkpoint.SectionIndex || event.CheckpointHash != checkpoint.SectionHead {
+		t.Fatalf("checkpoint event mismatch: have %+v", event)
+	}
+	// The checkpoint and its signatures must be recoverable from the raw storage
+	stored, storedSigs := ReadCheckpoint(func(slot common.Hash) common.Hash {
+		value, err := backend.StorageAt(context.Background(), oracle.ContractAddr(), slot, nil)
+		if err != nil {
+			t.Fatalf("failed to read storage: %v", err)
+		}
+		return common.BytesToHash(value)
+	})
+	if !reflect.DeepEqual(stored, checkpoint) {
+		t.Fatalf("stored checkpoint mismatch: have %+v, want %+v", stored, checkpoint)
+	}
+	signers := []common.Address{admins[0].addr, admins[1].addr, admins[2].addr}
+	if err := stored.VerifySigners(oracle.ContractAddr(), storedSigs, signers, 2); err != nil {
+		t.Fatalf("stored signatures rejected: %v", err)
+	}
+	if err := stored.VerifySigners(oracle.ContractAddr(), storedSigs, signers, 3); err == nil {
+		t.Fatalf("stored signatures accepted above the threshold")
+	}
+	// Older or equal sections cannot override the latest checkpoint
+	for _, index := range []uint64{2, 3} {
+		stale := *checkpoint
+		stale.SectionIndex = index
+		sigs := signCheckpoint(t, oracle, &stale, admins...)
+		if _, err := oracle.RegisterCheckpoint(auth, &stale, sigs); err == nil {
+			t.Errorf("stale checkpoint #%d accepted", index)
+		}
+	}
+	// Signatures are bound to the oracle contract
+	other := &Checkpoint{SectionIndex: 4, SectionHead: common.HexToHash("0x04")}
+	sigs = nil
+	for _, admin := range admins {
+		sig, _ := other.Sign(common.Address{0xff}, admin.key)
+		sigs = append(sigs, sig)
+	}
+	if _, err := oracle.RegisterCheckpoint(auth, other, sigs); err == nil {
+		t.Fatalf("checkpoint signed for another oracle accepted")
+	}
+}
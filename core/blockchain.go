@@ -331,6 +331,52 @@ func (bc *BlockChain) FastSyncCommitHead(hash common.Hash) error {
 	return nil
 }
 
+// CommitCheckpoint initialises an empty chain from a trusted checkpoint block
+// instead of the genesis. The block is written along with its receipts and total
+// difficulty as the new head, preceded by the given ancestor headers which are
+// needed to serve BLOCKHASH when executing the blocks following the checkpoint.
+// The state of the checkpoint must already be present in the database.
+func (bc *BlockChain) CommitCheckpoint(ancestors []*types.Header, block *types.Block, receipts types.Receipts, td *big.Int) error {
+	bc.wg.Add(1)
+	defer bc.wg.Done()
+
+	// Make sure the chain is still empty and the checkpoint is complete
+	if head := bc.CurrentHeader(); head.Number.Uint64() > 0 {
+		return fmt.Errorf("chain not empty, head #%d [%x???]", head.Number, head.Hash().Bytes()[:4])
+	}
+	if _, err := trie.NewSecure(block.Root(), bc.stateCache.TrieDB(), 0); err != nil {
+		return err
+	}
+	if err := SetReceiptsData(bc.chainConfig, block, receipts); err != nil {
+		return fmt.Errorf("failed to set receipts data: %v", err)
+	}
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	batch := bc.db.NewBatch()
+	for _, header := range ancestors {
+		rawdb.WriteHeader(batch, header)
+		rawdb.WriteCanonicalHash(batch, header.Hash(), header.Number.Uint64())
+	}
+	rawdb.WriteTd(batch, block.Hash(), block.NumberU64(), td)
+	rawdb.WriteBlock(batch, block)
+	rawdb.WriteReceipts(batch, block.Hash(), block.NumberU64(), receipts)
+	rawdb.WriteTxLookupEntries(batch, block)
+	rawdb.WriteCanonicalHash(batch, block.Hash(), block.NumberU64())
+	rawdb.WriteHeadBlockHash(batch, block.Hash())
+	rawdb.WriteHeadFastBlockHash(batch, block.Hash())
+	rawdb.WriteSyncCheckpoint(batch, block.Hash())
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	bc.hc.SetCurrentHeader(block.Header())
+	bc.currentBlock.Store(block)
+	bc.currentFastBlock.Store(block)
+
+	log.Info("Committed sync checkpoint as new head", "number", block.Number(), "hash", block.Hash(), "td", td, "ancestors", len(ancestors))
+	return nil
+}
+
 // GasLimit returns the gas limit of the current HEAD block.
 func (bc *BlockChain) GasLimit() uint64 {
 	return bc.CurrentBlock().GasLimit()
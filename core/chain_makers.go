@@ -62,6 +62,15 @@ func (b *BlockGen) SetCoinbase(addr common.Address) {
 	b.gasPool = new(GasPool).AddGas(b.header.GasLimit)
 }
 
+// SetGasLimit sets the gas limit of the generated block. It must be called
+// before the coinbase is set or any transactions are added.
+func (b *BlockGen) SetGasLimit(limit uint64) {
+	if b.gasPool != nil {
+		panic("gas limit must be set before the coinbase and transactions")
+	}
+	b.header.GasLimit = limit
+}
+
 // SetExtra sets the extra data field of the generated block.
 func (b *BlockGen) SetExtra(data []byte) {
 	b.header.Extra = data
@@ -0,0 +1,120 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package forkid
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/rwdxchain/go-rwdxchaina/params"
+	"github.com/rwdxchain/go-rwdxchaina/rlp"
+)
+
+// Tests that fork IDs are calculated correctly on the mainnet configuration.
+func TestCreation(t *testing.T) {
+	tests := []struct {
+		head uint64
+		want ID
+	}{
+		{0, ID{Hash: [4]byte{0xfc, 0x64, 0xec, 0x04}, Next: 1150000}},       // Unsynced
+		{1149999, ID{Hash: [4]byte{0xfc, 0x64, 0xec, 0x04}, Next: 1150000}}, // Last Frontier block
+		{1150000, ID{Hash: [4]byte{0x97, 0xc2, 0xc3, 0x4c}, Next: 1920000}}, // First Homestead block
+		{1920000, ID{Hash: [4]byte{0x91, 0xd1, 0xf9, 0x48}, Next: 2463000}}, // First DAO block
+		{2463000, ID{Hash: [4]byte{0x7a, 0x64, 0xda, 0x13}, Next: 2675000}}, // First Tangerine block
+		{2675000, ID{Hash: [4]byte{0x3e, 0xdd, 0x5b, 0x10}, Next: 4370000}}, // First Spurious block
+		{4369999, ID{Hash: [4]byte{0x3e, 0xdd, 0x5b, 0x10}, Next: 4370000}}, // Last Spurious block
+		{4370000, ID{Hash: [4]byte{0xa0, 0x0b, 0xc3, 0x24}, Next: 0}},       // First Byzantium block
+		{7987396, ID{Hash: [4]byte{0xa0, 0x0b, 0xc3, 0x24}, Next: 0}},       // Future Byzantium block
+	}
+	for i, tt := range tests {
+		if have := NewIDWithHead(params.MainnetChainConfig, params.MainnetGenesisHash, tt.head); have != tt.want {
+			t.Errorf("test %d: fork ID mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
+
+// Tests that remote fork IDs are validated according to the local chain state.
+func TestValidation(t *testing.T) {
+	// Extend the mainnet config with Constantinople to test future forks
+	config := *params.MainnetChainConfig
+	config.ConstantinopleBlock = big.NewInt(7280000)
+
+	tests := []struct {
+		head uint64
+		id   ID
+		err  error
+	}{
+		// Local is mainnet Byzantium, remote announces the same, no future fork.
+		{4370000, ID{Hash: [4]byte{0xa0, 0x0b, 0xc3, 0x24}, Next: 0}, nil},
+
+		// Local is mainnet Byzantium, remote announces the same, with the same future fork.
+		{4370000, ID{Hash: [4]byte{0xa0, 0x0b, 0xc3, 0x24}, Next: 7280000}, nil},
+
+		// Local is mainnet Byzantium, remote announces the same, with an unknown future fork.
+		{4370000, ID{Hash: [4]byte{0xa0, 0x0b, 0xc3, 0x24}, Next: 8000000}, nil},
+
+		// Local is mainnet Byzantium, remote announces Spurious with the Byzantium fork
+		// as next. The remote is just syncing.
+		{4370000, ID{Hash: [4]byte{0x3e, 0xdd, 0x5b, 0x10}, Next: 4370000}, nil},
+
+		// Local is mainnet Spurious, remote announces Byzantium. We're just syncing.
+		{4369999, ID{Hash: [4]byte{0xa0, 0x0b, 0xc3, 0x24}, Next: 7280000}, nil},
+
+		// Local is mainnet Spurious, remote announces Constantinople. We're way behind.
+		{4369999, ID{Hash: [4]byte{0x66, 0x8d, 0xb0, 0xaf}, Next: 0}, nil},
+
+		// Local is mainnet Constantinople, remote announces Byzantium without the
+		// Constantinople fork. The remote is stale and will diverge.
+		{7280000, ID{Hash: [4]byte{0xa0, 0x0b, 0xc3, 0x24}, Next: 0}, ErrRemoteStale},
+
+		// Local is mainnet Byzantium past a fork the remote announces, which we don't
+		// know about. We'll diverge from the remote.
+		{7279999, ID{Hash: [4]byte{0xa0, 0x0b, 0xc3, 0x24}, Next: 5000000}, ErrLocalIncompatibleOrStale},
+
+		// Remote is on a chain with a different genesis or fork history.
+		{7280000, ID{Hash: [4]byte{0xaf, 0xec, 0x6b, 0x27}, Next: 0}, ErrLocalIncompatibleOrStale},
+		{0, ID{Hash: [4]byte{0xaf, 0xec, 0x6b, 0x27}, Next: 1150000}, ErrLocalIncompatibleOrStale},
+	}
+	for i, tt := range tests {
+		filter := newFilter(&config, params.MainnetGenesisHash, func() uint64 { return tt.head })
+		if err := filter(tt.id); err != tt.err {
+			t.Errorf("test %d: validation error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+}
+
+// Tests that fork IDs are RLP encoded as expected.
+func TestEncoding(t *testing.T) {
+	tests := []struct {
+		id   ID
+		want []byte
+	}{
+		{ID{Hash: [4]byte{0, 0, 0, 0}, Next: 0}, []byte{0xc6, 0x84, 0x00, 0x00, 0x00, 0x00, 0x80}},
+		{ID{Hash: [4]byte{0xde, 0xad, 0xbe, 0xef}, Next: 0xbaddcafe}, []byte{0xca, 0x84, 0xde, 0xad, 0xbe, 0xef, 0x84, 0xba, 0xdd, 0xca, 0xfe}},
+	}
+	for i, tt := range tests {
+		have, err := rlp.EncodeToBytes(tt.id)
+		if err != nil {
+			t.Errorf("test %d: failed to encode fork ID: %v", i, err)
+			continue
+		}
+		if !bytes.Equal(have, tt.want) {
+			t.Errorf("test %d: RLP mismatch: have %x, want %x", i, have, tt.want)
+		}
+	}
+}
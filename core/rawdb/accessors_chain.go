@@ -126,6 +126,38 @@ func WriteFastTrieProgress(db DatabaseWriter, count uint64) {
 	}
 }
 
+// ReadSyncCheckpoint retrieves the hash of the trusted checkpoint block the local
+// chain was initialised from, or the zero hash if it was synced from genesis.
+func ReadSyncCheckpoint(db DatabaseReader) common.Hash {
+	data, _ := db.Get(syncCheckpointKey)
+	if len(data) == 0 {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// WriteSyncCheckpoint stores the hash of the trusted checkpoint block the local
+// chain was initialised from.
+func WriteSyncCheckpoint(db DatabaseWriter, hash common.Hash) {
+	if err := db.Put(syncCheckpointKey, hash.Bytes()); err != nil {
+		log.Crit("Failed to store sync checkpoint", "err", err)
+	}
+}
+
+// ReadSyncProgress retrieves the serialized progress of the last sync cycle to
+// allow resuming it across restarts.
+func ReadSyncProgress(db DatabaseReader) []byte {
+	data, _ := db.Get(syncProgressKey)
+	return data
+}
+
+// WriteSyncProgress stores the serialized progress of the current sync cycle.
+func WriteSyncProgress(db DatabaseWriter, progress []byte) {
+	if err := db.Put(syncProgressKey, progress); err != nil {
+		log.Crit("Failed to store sync progress", "err", err)
+	}
+}
+
 // ReadHeaderRLP retrieves a block header in its raw RLP database encoding.
 func ReadHeaderRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
 	data, _ := db.Get(headerKey(number, hash))
@@ -41,6 +41,12 @@ var (
 	// fastTrieProgressKey tracks the number of trie entries imported during fast sync.
 	fastTrieProgressKey = []byte("TrieSync")
 
+	// syncCheckpointKey tracks the hash of the trusted block the chain was started from.
+	syncCheckpointKey = []byte("SyncCheckpoint")
+
+	// syncProgressKey tracks the progress of an interrupted sync cycle.
+	syncProgressKey = []byte("SyncProgress")
+
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
 	headerTDSuffix     = []byte("t") // headerPrefix + num (uint64 big endian) + hash + headerTDSuffix -> td
@@ -55,6 +55,7 @@ type LesServer interface {
 	Start(srvr *p2p.Server)
 	Stop()
 	Protocols() []p2p.Protocol
+	APIs() []rpc.API
 	SetBloomBitsIndexer(bbIndexer *core.ChainIndexer)
 }
 
@@ -162,14 +163,27 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		eth.blockchain.SetHead(compat.RewindTo)
 		rawdb.WriteChainConfig(chainDb, genesisHash, chainConfig)
 	}
-	eth.bloomIndexer.Start(eth.blockchain)
+	// Resolve the sync checkpoint and defer log indexing until it's been reached
+	checkpoint := params.SyncCheckpoints[genesisHash]
+	if config.SyncFrom != (common.Hash{}) {
+		if config.SyncFromTD == nil {
+			return nil, fmt.Errorf("sync checkpoint %x without total difficulty", config.SyncFrom)
+		}
+		checkpoint = &params.SyncCheckpoint{Hash: config.SyncFrom, TD: config.SyncFromTD}
+	}
+	if checkpoint != nil && eth.blockchain.CurrentBlock().NumberU64() == 0 {
+		log.Info("Synchronising from trusted checkpoint", "hash", checkpoint.Hash, "td", checkpoint.TD)
+		go eth.startBloomIndexerAfterCheckpoint()
+	} else {
+		eth.startBloomIndexer()
+	}
 
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = ctx.ResolvePath(config.TxPool.Journal)
 	}
 	eth.txPool = core.NewTxPool(config.TxPool, eth.chainConfig, eth.blockchain)
 
-	if eth.protocolManager, err = NewProtocolManager(eth.chainConfig, config.SyncMode, config.NetworkId, eth.eventMux, eth.txPool, eth.engine, eth.blockchain, chainDb); err != nil {
+	if eth.protocolManager, err = NewProtocolManager(eth.chainConfig, checkpoint, config.SyncMode, config.NetworkId, eth.eventMux, eth.txPool, eth.engine, eth.blockchain, chainDb); err != nil {
 		return nil, err
 	}
 
@@ -254,6 +268,11 @@ func (s *Ethereum) APIs() []rpc.API {
 	// Append any APIs exposed explicitly by the consensus engine
 	apis = append(apis, s.engine.APIs(s.BlockChain())...)
 
+	// Append any APIs exposed by the light server
+	if s.lesServer != nil {
+		apis = append(apis, s.lesServer.APIs()...)
+	}
+
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
@@ -271,6 +290,11 @@ func (s *Ethereum) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   downloader.NewPublicDownloaderAPI(s.protocolManager.downloader, s.eventMux),
 			Public:    true,
+		}, {
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   downloader.NewPublicDebugAPI(s.protocolManager.downloader),
+			Public:    true,
 		}, {
 			Namespace: "miner",
 			Version:   "1.0",
@@ -439,6 +463,10 @@ func (s *Ethereum) Start(srvr *p2p.Server) error {
 		}
 		maxPeers -= s.config.LightPeers
 	}
+	// Keep the fork ID in the local node record up to date
+	if record := srvr.LocalRecord(); record != nil {
+		s.startENRUpdater(record)
+	}
 	// Start the networking layer and the light server if requested
 	s.protocolManager.Start(maxPeers)
 	if s.lesServer != nil {
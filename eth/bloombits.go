@@ -27,6 +27,7 @@ import (
 	"github.com/rwdxchain/go-rwdxchaina/core/rawdb"
 	"github.com/rwdxchain/go-rwdxchaina/core/types"
 	"github.com/rwdxchain/go-rwdxchaina/ethdb"
+	"github.com/rwdxchain/go-rwdxchaina/params"
 )
 
 const (
@@ -47,6 +48,34 @@ const (
 	bloomRetrievalWait = time.Duration(0)
 )
 
+// startBloomIndexer starts indexing the local chain into bloom bits. If the chain
+// was initialised from a sync checkpoint, the sections preceding it are skipped,
+// as the headers needed to index them are not available locally.
+func (eth *Ethereum) startBloomIndexer() {
+	if checkpoint := rawdb.ReadSyncCheckpoint(eth.chainDb); checkpoint != (common.Hash{}) {
+		if number := rawdb.ReadHeaderNumber(eth.chainDb, checkpoint); number != nil && *number+1 >= params.BloomBitsBlocks {
+			section := (*number+1)/params.BloomBitsBlocks - 1
+			eth.bloomIndexer.AddCheckpoint(section, rawdb.ReadCanonicalHash(eth.chainDb, (section+1)*params.BloomBitsBlocks-1))
+		}
+	}
+	eth.bloomIndexer.Start(eth.blockchain)
+}
+
+// startBloomIndexerAfterCheckpoint waits until the local chain is initialised
+// from its sync checkpoint and starts indexing it into bloom bits afterwards.
+func (eth *Ethereum) startBloomIndexerAfterCheckpoint() {
+	heads := make(chan core.ChainHeadEvent, 1)
+	sub := eth.blockchain.SubscribeChainHeadEvent(heads)
+	defer sub.Unsubscribe()
+
+	select {
+	case <-heads:
+		eth.startBloomIndexer()
+	case <-sub.Err():
+	case <-eth.shutdownChan:
+	}
+}
+
 // startBloomHandlers starts a batch of goroutines to accept bloom bit database
 // retrievals from possibly a range of filters and serving the data to satisfy.
 func (eth *Ethereum) startBloomHandlers(sectionSize uint64) {
@@ -82,14 +82,22 @@ type Config struct {
 	Genesis *core.Genesis `toml:",omitempty"`
 
 	// Protocol options
-	NetworkId uint64 // Network ID to use for selecting peers to connect to
-	SyncMode  downloader.SyncMode
-	NoPruning bool
+	NetworkId  uint64 // Network ID to use for selecting peers to connect to
+	SyncMode   downloader.SyncMode
+	SyncFrom   common.Hash `toml:",omitempty"` // Trusted block to start syncing an empty chain from
+	SyncFromTD *big.Int    `toml:",omitempty"` // Total difficulty of the trusted block
+	NoPruning  bool
 
 	// Light client options
 	LightServ  int `toml:",omitempty"` // Maximum percentage of time allowed for serving LES requests
 	LightPeers int `toml:",omitempty"` // Maximum number of LES client peers
 
+	// Checkpoint oracle the light client retrieves trusted checkpoints from
+	CheckpointOracle *params.CheckpointOracleConfig `toml:",omitempty"`
+
+	// Ultra-light client options
+	ULC *ULCConfig `toml:",omitempty"`
+
 	// Database options
 	SkipBcVersionCheck bool `toml:"-"`
 	DatabaseHandles    int  `toml:"-"`
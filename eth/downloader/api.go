@@ -164,3 +164,20 @@ func (api *PublicDownloaderAPI) SubscribeSyncStatus(status chan interface{}) *Sy
 	api.installSyncSubscription <- status
 	return &SyncStatusSubscription{api: api, c: status}
 }
+
+// PublicDebugAPI provides detailed diagnostics about the synchronisation, meant
+// to help operators figure out which phase of a sync cycle is slow.
+type PublicDebugAPI struct {
+	d *Downloader
+}
+
+// NewPublicDebugAPI creates a new debug API for the downloader.
+func NewPublicDebugAPI(d *Downloader) *PublicDebugAPI {
+	return &PublicDebugAPI{d: d}
+}
+
+// SyncStatus returns a detailed report on the current (or last interrupted) sync
+// cycle, including its phase, per-phase throughput, ETA and state backlog.
+func (api *PublicDebugAPI) SyncStatus() SyncStatus {
+	return api.d.Status()
+}
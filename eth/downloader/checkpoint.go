@@ -0,0 +1,255 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/core/types"
+	"github.com/rwdxchain/go-rwdxchaina/log"
+	"github.com/rwdxchain/go-rwdxchaina/params"
+)
+
+var (
+	checkpointQuorum   = 2.0 / 3.0 // Fraction of peers that must have the checkpoint in their canonical chain
+	checkpointAncestry = 256       // Number of headers preceding the checkpoint to retrieve (BLOCKHASH window)
+)
+
+// syncCheckpoint retrieves a trusted checkpoint block along with its receipts,
+// its state and the headers of its most recent ancestors from the network, and
+// commits it as the base of the (empty) local chain. Synchronisation can then
+// proceed from the checkpoint onward instead of from the genesis block.
+//
+// The total difficulty of the checkpoint cannot be retrieved from the network,
+// so the one trusted along with its hash is used, the difficulties of the
+// headers synced afterwards being accumulated on top of it as usual.
+func (d *Downloader) syncCheckpoint(p *peerConnection, td *big.Int) error {
+	if p.version < 63 {
+		return errTooOld
+	}
+	p.log.Debug("Retrieving sync checkpoint", "hash", d.checkpoint.Hash)
+
+	// A peer advertising a head lighter than the checkpoint cannot have it in its chain
+	if td.Cmp(d.checkpoint.TD) < 0 {
+		p.log.Debug("Advertised head difficulty below sync checkpoint", "td", td, "checkpoint", d.checkpoint.TD)
+		return errBadPeer
+	}
+
+	// Retrieve the checkpoint header and ensure the network agrees on it
+	packet, err := d.requestCheckpointData(p, d.headerCh, func() error {
+		return p.peer.RequestHeadersByHash(d.checkpoint.Hash, 1, 0, false)
+	})
+	if err != nil {
+		return err
+	}
+	headers := packet.(*headerPack).headers
+	if len(headers) != 1 || headers[0].Hash() != d.checkpoint.Hash {
+		p.log.Debug("Sync checkpoint not delivered", "headers", len(headers))
+		return errUnknownCheckpoint
+	}
+	header := headers[0]
+	if header.Number.Uint64() == 0 {
+		return nil
+	}
+	if err := d.verifyCheckpointQuorum(header); err != nil {
+		return err
+	}
+	// Retrieve all the chain data needed to continue operating from the checkpoint
+	ancestors, err := d.fetchCheckpointAncestors(p, header)
+	if err != nil {
+		return err
+	}
+	block, receipts, err := d.fetchCheckpointBlock(p, header)
+	if err != nil {
+		return err
+	}
+	d.syncStatsLock.Lock()
+	d.syncStatsChainOrigin = header.Number.Uint64()
+	d.syncStatsLock.Unlock()
+
+	log.Info("Downloading sync checkpoint state", "number", header.Number, "hash", header.Hash(), "root", header.Root)
+	stateSync := d.syncState(header.Root)
+	defer stateSync.Cancel()
+	if err := stateSync.Wait(); err != nil {
+		return err
+	}
+	return d.blockchain.CommitCheckpoint(ancestors, block, receipts, d.checkpoint.TD)
+}
+
+// verifyCheckpointQuorum requests the canonical header at the checkpoint's height
+// from all known peers, and ensures that a quorum of them agrees with the local
+// checkpoint. This prevents syncing from a block on a stale or minority fork.
+func (d *Downloader) verifyCheckpointQuorum(header *types.Header) error {
+	peers := d.peers.AllPeers()
+
+	pending := make(map[string]struct{}, len(peers))
+	for _, peer := range peers {
+		pending[peer.id] = struct{}{}
+		go peer.peer.RequestHeadersByNumber(header.Number.Uint64(), 1, 0, false)
+	}
+	agree := 0
+
+	ttl := d.requestTTL()
+	timeout := time.After(ttl)
+
+	for len(pending) > 0 {
+		select {
+		case <-d.cancelCh:
+			return errCancelHeaderFetch
+
+		case packet := <-d.headerCh:
+			if _, ok := pending[packet.PeerId()]; !ok {
+				log.Debug("Received unrequested checkpoint header", "peer", packet.PeerId())
+				break
+			}
+			delete(pending, packet.PeerId())
+
+			headers := packet.(*headerPack).headers
+			if len(headers) == 1 && headers[0].Hash() == header.Hash() {
+				agree++
+			}
+
+		case <-timeout:
+			log.Debug("Waiting for checkpoint headers timed out", "elapsed", ttl, "missing", len(pending))
+			pending = nil
+
+		case <-d.bodyCh:
+		case <-d.receiptCh:
+			// Out of bounds delivery, ignore
+		}
+	}
+	if float64(agree) < checkpointQuorum*float64(len(peers)) {
+		log.Warn("Sync checkpoint rejected by peers", "number", header.Number, "hash", header.Hash(), "agree", agree, "peers", len(peers))
+		return errNoCheckpointQuorum
+	}
+	log.Debug("Sync checkpoint confirmed by peers", "number", header.Number, "hash", header.Hash(), "agree", agree, "peers", len(peers))
+	return nil
+}
+
+// fetchCheckpointAncestors retrieves the headers immediately preceding the
+// checkpoint, which are needed to execute the blocks following it. The ancestry
+// is extended back to the last bloom section boundary too, so that log indexing
+// can resume from the checkpoint onward.
+func (d *Downloader) fetchCheckpointAncestors(p *peerConnection, header *types.Header) ([]*types.Header, error) {
+	number := header.Number.Uint64()
+
+	from := uint64(1)
+	if number > uint64(checkpointAncestry) {
+		from = number - uint64(checkpointAncestry)
+	}
+	if boundary := (number + 1) / params.BloomBitsBlocks * params.BloomBitsBlocks; boundary > 1 && boundary-1 < from {
+		from = boundary - 1
+	}
+	// Retrieve the ancestors in batches, ensuring each links up to the next
+	ancestors := make([]*types.Header, 0, number-from)
+	for next := from; next < number; {
+		count := MaxHeaderFetch
+		if left := int(number - next); left < count {
+			count = left
+		}
+		packet, err := d.requestCheckpointData(p, d.headerCh, func() error {
+			return p.peer.RequestHeadersByNumber(next, count, 0, false)
+		})
+		if err != nil {
+			return nil, err
+		}
+		headers := packet.(*headerPack).headers
+		if len(headers) != count {
+			p.log.Debug("Incomplete checkpoint ancestry", "requested", count, "received", len(headers))
+			return nil, errBadPeer
+		}
+		ancestors = append(ancestors, headers...)
+		next += uint64(count)
+	}
+	for i, parent := len(ancestors)-1, header; i >= 0; i-- {
+		if ancestors[i].Hash() != parent.ParentHash || ancestors[i].Number.Uint64()+1 != parent.Number.Uint64() {
+			p.log.Debug("Checkpoint ancestry broke chain ordering", "number", ancestors[i].Number, "hash", ancestors[i].Hash())
+			return nil, errInvalidChain
+		}
+		parent = ancestors[i]
+	}
+	return ancestors, nil
+}
+
+// fetchCheckpointBlock retrieves the body and receipts of the checkpoint block,
+// verifying them against the roots in the trusted header.
+func (d *Downloader) fetchCheckpointBlock(p *peerConnection, header *types.Header) (*types.Block, types.Receipts, error) {
+	hashes := []common.Hash{header.Hash()}
+
+	packet, err := d.requestCheckpointData(p, d.bodyCh, func() error { return p.peer.RequestBodies(hashes) })
+	if err != nil {
+		return nil, nil, err
+	}
+	bodies := packet.(*bodyPack)
+	if len(bodies.transactions) != 1 || len(bodies.uncles) != 1 {
+		p.log.Debug("Checkpoint body not delivered", "bodies", bodies.Stats())
+		return nil, nil, errBadPeer
+	}
+	if types.DeriveSha(types.Transactions(bodies.transactions[0])) != header.TxHash || types.CalcUncleHash(bodies.uncles[0]) != header.UncleHash {
+		p.log.Debug("Checkpoint body mismatch", "number", header.Number, "hash", header.Hash())
+		return nil, nil, errBadPeer
+	}
+	packet, err = d.requestCheckpointData(p, d.receiptCh, func() error { return p.peer.RequestReceipts(hashes) })
+	if err != nil {
+		return nil, nil, err
+	}
+	receipts := packet.(*receiptPack).receipts
+	if len(receipts) != 1 || types.DeriveSha(types.Receipts(receipts[0])) != header.ReceiptHash {
+		p.log.Debug("Checkpoint receipts mismatch", "number", header.Number, "hash", header.Hash())
+		return nil, nil, errBadPeer
+	}
+	block := types.NewBlockWithHeader(header).WithBody(bodies.transactions[0], bodies.uncles[0])
+	return block, receipts[0], nil
+}
+
+// requestCheckpointData issues a single data retrieval request to a peer and
+// waits for the response to arrive on the given delivery channel, discarding
+// anything delivered by other peers or on other channels in the mean time.
+func (d *Downloader) requestCheckpointData(p *peerConnection, deliveryCh chan dataPack, request func() error) (dataPack, error) {
+	go request()
+
+	ttl := d.requestTTL()
+	timeout := time.After(ttl)
+	for {
+		var (
+			packet dataPack
+			ch     chan dataPack
+		)
+		select {
+		case <-d.cancelCh:
+			return nil, errCancelBlockFetch
+
+		case <-timeout:
+			p.log.Debug("Waiting for checkpoint data timed out", "elapsed", ttl)
+			return nil, errTimeout
+
+		case packet = <-d.headerCh:
+			ch = d.headerCh
+		case packet = <-d.bodyCh:
+			ch = d.bodyCh
+		case packet = <-d.receiptCh:
+			ch = d.receiptCh
+		}
+		if ch != deliveryCh || packet.PeerId() != p.id {
+			log.Debug("Received checkpoint data from incorrect peer", "peer", packet.PeerId())
+			continue
+		}
+		return packet, nil
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/rwdxchain/go-rwdxchaina/event"
 	"github.com/rwdxchain/go-rwdxchaina/log"
 	"github.com/rwdxchain/go-rwdxchaina/metrics"
+	"github.com/rwdxchain/go-rwdxchaina/p2p"
 	"github.com/rwdxchain/go-rwdxchaina/params"
 )
 
@@ -90,12 +91,16 @@ var (
 	errCancelContentProcessing = errors.New("content processing canceled (requested)")
 	errNoSyncActive            = errors.New("no sync active")
 	errTooOld                  = errors.New("peer doesn't speak recent enough protocol version (need version >= 62)")
+	errUnknownCheckpoint       = errors.New("sync checkpoint unknown by peer")
+	errNoCheckpointQuorum      = errors.New("no peer quorum on sync checkpoint")
 )
 
 type Downloader struct {
 	mode SyncMode       // Synchronisation mode defining the strategy used (per sync cycle)
 	mux  *event.TypeMux // Event multiplexer to announce sync operation events
 
+	checkpoint *params.SyncCheckpoint // Trusted block to start syncing an empty chain from (nil = genesis)
+
 	queue   *queue   // Scheduler for selecting the hashes to download
 	peers   *peerSet // Set of active peers from which download can proceed
 	stateDB ethdb.Database
@@ -107,6 +112,12 @@ type Downloader struct {
 	syncStatsChainOrigin uint64 // Origin block number where syncing started at
 	syncStatsChainHeight uint64 // Highest block number known when syncing started
 	syncStatsState       stateSyncStats
+	syncStatsPhase       string       // Current phase of the sync cycle
+	syncStatsPivot       uint64       // Fast sync pivot block whose state is being retrieved
+	syncStatsHeaders     syncMeter    // Throughput meter of the header processing
+	syncStatsBlocks      syncMeter    // Throughput meter of the block (content) imports
+	syncStatsReceipts    syncMeter    // Throughput meter of the receipt imports
+	syncStatsStates      syncMeter    // Throughput meter of the state retrieval
 	syncStatsLock        sync.RWMutex // Lock protecting the sync stats fields
 
 	lightchain LightChain
@@ -190,6 +201,9 @@ type BlockChain interface {
 	// FastSyncCommitHead directly commits the head block to a certain entity.
 	FastSyncCommitHead(common.Hash) error
 
+	// CommitCheckpoint initialises an empty chain from a trusted checkpoint block.
+	CommitCheckpoint([]*types.Header, *types.Block, types.Receipts, *big.Int) error
+
 	// InsertChain inserts a batch of blocks into the local chain.
 	InsertChain(types.Blocks) (int, error)
 
@@ -197,14 +211,17 @@ type BlockChain interface {
 	InsertReceiptChain(types.Blocks, []types.Receipts) (int, error)
 }
 
-// New creates a new downloader to fetch hashes and blocks from remote peers.
-func New(mode SyncMode, stateDb ethdb.Database, mux *event.TypeMux, chain BlockChain, lightchain LightChain, dropPeer peerDropFn) *Downloader {
+// New creates a new downloader to fetch hashes and blocks from remote peers. If
+// a checkpoint is given, an empty local chain is synchronised starting from that
+// block instead of the genesis.
+func New(checkpoint *params.SyncCheckpoint, mode SyncMode, stateDb ethdb.Database, mux *event.TypeMux, chain BlockChain, lightchain LightChain, dropPeer peerDropFn) *Downloader {
 	if lightchain == nil {
 		lightchain = chain
 	}
 
 	dl := &Downloader{
 		mode:           mode,
+		checkpoint:     checkpoint,
 		stateDB:        stateDb,
 		mux:            mux,
 		queue:          newQueue(),
@@ -228,6 +245,8 @@ func New(mode SyncMode, stateDb ethdb.Database, mux *event.TypeMux, chain BlockC
 		},
 		trackStateReq: make(chan *stateReq),
 	}
+	dl.loadProgress()
+
 	go dl.qosTuner()
 	go dl.stateFetcher()
 	return dl
@@ -245,24 +264,28 @@ func (d *Downloader) Progress() ethereum.SyncProgress {
 	d.syncStatsLock.RLock()
 	defer d.syncStatsLock.RUnlock()
 
-	current := uint64(0)
-	switch d.mode {
-	case FullSync:
-		current = d.blockchain.CurrentBlock().NumberU64()
-	case FastSync:
-		current = d.blockchain.CurrentFastBlock().NumberU64()
-	case LightSync:
-		current = d.lightchain.CurrentHeader().Number.Uint64()
-	}
 	return ethereum.SyncProgress{
 		StartingBlock: d.syncStatsChainOrigin,
-		CurrentBlock:  current,
+		CurrentBlock:  d.currentBlock(),
 		HighestBlock:  d.syncStatsChainHeight,
 		PulledStates:  d.syncStatsState.processed,
 		KnownStates:   d.syncStatsState.processed + d.syncStatsState.pending,
 	}
 }
 
+// currentBlock retrieves the head block number the current sync mode is at.
+func (d *Downloader) currentBlock() uint64 {
+	switch d.mode {
+	case FullSync:
+		return d.blockchain.CurrentBlock().NumberU64()
+	case FastSync:
+		return d.blockchain.CurrentFastBlock().NumberU64()
+	case LightSync:
+		return d.lightchain.CurrentHeader().Number.Uint64()
+	}
+	return 0
+}
+
 // Synchronising returns whether the downloader is currently retrieving blocks.
 func (d *Downloader) Synchronising() bool {
 	return atomic.LoadInt32(&d.synchronising) > 0
@@ -420,7 +443,21 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 		log.Debug("Synchronisation terminated", "elapsed", time.Since(start))
 	}(time.Now())
 
+	d.resetMeters()
+	defer d.setPhase(phaseIdle)
+
+	// If the local chain is still empty and a trusted checkpoint was configured,
+	// skip the ancient chain segment and continue with full sync from there
+	if d.checkpoint != nil && d.mode != LightSync && d.lightchain.CurrentHeader().Number.Uint64() == 0 {
+		d.setPhase(phaseCheckpoint)
+		if err := d.syncCheckpoint(p, td); err != nil {
+			return err
+		}
+		d.mode = FullSync
+	}
+
 	// Look up the sync boundaries: the common ancestor and the target block
+	d.setPhase(phaseAncestor)
 	latest, err := d.fetchHeight(p)
 	if err != nil {
 		return err
@@ -436,6 +473,9 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 		d.syncStatsChainOrigin = origin
 	}
 	d.syncStatsChainHeight = height
+	d.syncStatsPhase = phaseHeaders
+	resume := d.syncStatsPivot
+	d.storeProgress()
 	d.syncStatsLock.Unlock()
 
 	// Ensure our origin point is below any fast sync pivot point
@@ -445,6 +485,13 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 			origin = 0
 		} else {
 			pivot = height - uint64(fsMinFullBlocks)
+
+			// If an interrupted sync cycle was retrieving the state of a pivot that
+			// is still recent enough, keep it to reuse the partially synced state
+			if resume != 0 && resume < pivot && pivot-resume <= uint64(fsMinFullBlocks) {
+				log.Debug("Resuming interrupted pivot state sync", "pivot", resume)
+				pivot = resume
+			}
 			if pivot <= origin {
 				origin = pivot - 1
 			}
@@ -467,7 +514,7 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, td *big.I
 		func() error { return d.processHeaders(origin+1, pivot, td) },
 	}
 	if d.mode == FastSync {
-		fetchers = append(fetchers, func() error { return d.processFastSyncContent(latest) })
+		fetchers = append(fetchers, func() error { return d.processFastSyncContent(latest, pivot) })
 	} else if d.mode == FullSync {
 		fetchers = append(fetchers, d.processFullSyncContent)
 	}
@@ -1018,6 +1065,16 @@ func (d *Downloader) fetchParts(errCancel error, deliveryCh chan dataPack, deliv
 			if peer := d.peers.Peer(packet.PeerId()); peer != nil {
 				// Deliver the received chunk of data and check chain validity
 				accepted, err := deliver(packet)
+				// Only penalise deliveries proven invalid, late ones after a timeout
+				// were already accounted for when the request expired
+				switch err {
+				case nil:
+					if accepted > 0 {
+						peer.adjustScore(p2p.ScoreUseful)
+					}
+				case errInvalidChain, errInvalidBody, errInvalidReceipt:
+					peer.adjustScore(p2p.ScoreUseless)
+				}
 				if err == errInvalidChain {
 					return err
 				}
@@ -1069,6 +1126,8 @@ func (d *Downloader) fetchParts(errCancel error, deliveryCh chan dataPack, deliv
 			// Check for fetch request timeouts and demote the responsible peers
 			for pid, fails := range expire() {
 				if peer := d.peers.Peer(pid); peer != nil {
+					peer.adjustScore(p2p.ScoreTimeout)
+
 					// If a lot of retrieval elements expired, we might have overestimated the remote peer or perhaps
 					// ourselves. Only reset to minimal throughput but don't drop just yet. If even the minimal times
 					// out that sync wise we need to get rid of the peer.
@@ -1234,6 +1293,14 @@ func (d *Downloader) processHeaders(origin uint64, pivot uint64, td *big.Int) er
 						return errStallingPeer
 					}
 				}
+				// Header retrieval is done, only block contents (if any) remain
+				if d.mode != LightSync {
+					d.syncStatsLock.Lock()
+					if d.syncStatsPhase == phaseHeaders {
+						d.syncStatsPhase = phaseBlocks
+					}
+					d.syncStatsLock.Unlock()
+				}
 				// Disable any rollback and return
 				rollback = nil
 				return nil
@@ -1300,6 +1367,8 @@ func (d *Downloader) processHeaders(origin uint64, pivot uint64, td *big.Int) er
 						return errBadPeer
 					}
 				}
+				d.markItems(&d.syncStatsHeaders, limit)
+
 				headers = headers[limit:]
 				origin += uint64(limit)
 			}
@@ -1308,6 +1377,7 @@ func (d *Downloader) processHeaders(origin uint64, pivot uint64, td *big.Int) er
 			d.syncStatsLock.Lock()
 			if d.syncStatsChainHeight < origin {
 				d.syncStatsChainHeight = origin - 1
+				d.storeProgress()
 			}
 			d.syncStatsLock.Unlock()
 
@@ -1362,12 +1432,13 @@ func (d *Downloader) importBlockResults(results []*fetchResult) error {
 		log.Debug("Downloaded item processing failed", "number", results[index].Header.Number, "hash", results[index].Header.Hash(), "err", err)
 		return errInvalidChain
 	}
+	d.markItems(&d.syncStatsBlocks, len(blocks))
 	return nil
 }
 
 // processFastSyncContent takes fetch results from the queue and writes them to the
 // database. It also controls the synchronisation of state nodes of the pivot block.
-func (d *Downloader) processFastSyncContent(latest *types.Header) error {
+func (d *Downloader) processFastSyncContent(latest *types.Header, pivot uint64) error {
 	// Start syncing state of the reported head block. This should get us most of
 	// the state of the pivot block.
 	stateSync := d.syncState(latest.Root)
@@ -1377,12 +1448,9 @@ func (d *Downloader) processFastSyncContent(latest *types.Header) error {
 			d.queue.Close() // wake up WaitResults
 		}
 	}()
-	// Figure out the ideal pivot block. Note, that this goalpost may move if the
-	// sync takes long enough for the chain head to move significantly.
-	pivot := uint64(0)
-	if height := latest.Number.Uint64(); height > uint64(fsMinFullBlocks) {
-		pivot = height - uint64(fsMinFullBlocks)
-	}
+	// The pivot block was chosen by the sync initiator. Note, that this goalpost
+	// may move if the sync takes long enough for the chain head to move significantly.
+	//
 	// To cater for moving pivot points, track the pivot block and subsequently
 	// accumulated download results separately.
 	var (
@@ -1436,8 +1504,10 @@ func (d *Downloader) processFastSyncContent(latest *types.Header) error {
 					}
 				}()
 				oldPivot = P
+				d.setPivot(P.Header.Number.Uint64())
 			}
 			// Wait for completion, occasionally checking for pivot staleness
+			d.setPhase(phaseState)
 			select {
 			case <-stateSync.done:
 				if stateSync.err != nil {
@@ -1447,6 +1517,8 @@ func (d *Downloader) processFastSyncContent(latest *types.Header) error {
 					return err
 				}
 				oldPivot = nil
+				d.setPivot(0)
+				d.setPhase(phaseBlocks)
 
 			case <-time.After(time.Second):
 				oldTail = afterP
@@ -1505,6 +1577,8 @@ func (d *Downloader) commitFastSyncData(results []*fetchResult, stateSync *state
 		log.Debug("Downloaded item processing failed", "number", results[index].Header.Number, "hash", results[index].Header.Hash(), "err", err)
 		return errInvalidChain
 	}
+	d.markItems(&d.syncStatsBlocks, len(blocks))
+	d.markItems(&d.syncStatsReceipts, len(receipts))
 	return nil
 }
 
@@ -32,6 +32,7 @@ import (
 	"github.com/rwdxchain/go-rwdxchaina/crypto"
 	"github.com/rwdxchain/go-rwdxchaina/ethdb"
 	"github.com/rwdxchain/go-rwdxchaina/event"
+	"github.com/rwdxchain/go-rwdxchaina/p2p"
 	"github.com/rwdxchain/go-rwdxchaina/params"
 	"github.com/rwdxchain/go-rwdxchaina/trie"
 )
@@ -96,7 +97,7 @@ func newTester() *downloadTester {
 	tester.stateDb = ethdb.NewMemDatabase()
 	tester.stateDb.Put(genesis.Root().Bytes(), []byte{0x00})
 
-	tester.downloader = New(FullSync, tester.stateDb, new(event.TypeMux), tester, nil, tester.dropPeer)
+	tester.downloader = New(nil, FullSync, tester.stateDb, new(event.TypeMux), tester, nil, tester.dropPeer)
 
 	return tester
 }
@@ -293,6 +294,26 @@ func (dl *downloadTester) FastSyncCommitHead(hash common.Hash) error {
 	return fmt.Errorf("non existent block: %x", hash[:4])
 }
 
+// CommitCheckpoint initialises the empty simulated chain from a checkpoint block.
+func (dl *downloadTester) CommitCheckpoint(ancestors []*types.Header, block *types.Block, receipts types.Receipts, td *big.Int) error {
+	dl.lock.Lock()
+	defer dl.lock.Unlock()
+
+	if len(dl.ownHashes) > 1 {
+		return errors.New("chain not empty")
+	}
+	for _, header := range ancestors {
+		dl.ownHashes = append(dl.ownHashes, header.Hash())
+		dl.ownHeaders[header.Hash()] = header
+	}
+	dl.ownHashes = append(dl.ownHashes, block.Hash())
+	dl.ownHeaders[block.Hash()] = block.Header()
+	dl.ownBlocks[block.Hash()] = block
+	dl.ownReceipts[block.Hash()] = receipts
+	dl.ownChainTd[block.Hash()] = td
+	return nil
+}
+
 // GetTd retrieves the block's total difficulty from the canonical chain.
 func (dl *downloadTester) GetTd(hash common.Hash, number uint64) *big.Int {
 	dl.lock.RLock()
@@ -460,9 +481,15 @@ type downloadTesterPeer struct {
 	dl    *downloadTester
 	id    string
 	delay time.Duration
+	score int32 // reputation reported by the downloader, accessed atomically
 	lock  sync.RWMutex
 }
 
+// AdjustScore tracks the reputation the downloader reports for the peer.
+func (dlp *downloadTesterPeer) AdjustScore(delta int) {
+	atomic.AddInt32(&dlp.score, int32(delta))
+}
+
 // setDelay is a thread safe setter for the network delay value.
 func (dlp *downloadTesterPeer) setDelay(delay time.Duration) {
 	dlp.lock.Lock()
@@ -685,6 +712,34 @@ func testCanonicalSynchronisation(t *testing.T, protocol int, mode SyncMode) {
 	assertOwnChain(t, tester, targetBlocks+1)
 }
 
+// Tests that the downloader reports the behaviour of peers to their reputation
+// score, also for peers wrapped as light peers.
+func TestPeerScoring(t *testing.T) {
+	t.Parallel()
+
+	tester := newTester()
+	defer tester.terminate()
+
+	hashes, headers, blocks, receipts := tester.makeChain(blockCacheItems-15, 0, tester.genesis, nil, false)
+	tester.newPeer("peer", 63, hashes, headers, blocks, receipts)
+
+	if err := tester.sync("peer", nil, FullSync); err != nil {
+		t.Fatalf("failed to synchronise blocks: %v", err)
+	}
+	peer := tester.downloader.peers.Peer("peer").peer.(*downloadTesterPeer)
+	if score := atomic.LoadInt32(&peer.score); score <= 0 {
+		t.Errorf("useful peer not rewarded: score %d", score)
+	}
+	light := &downloadTesterPeer{dl: tester, id: "light"}
+	if err := tester.downloader.RegisterLightPeer("light", 63, light); err != nil {
+		t.Fatalf("failed to register light peer: %v", err)
+	}
+	tester.downloader.peers.Peer("light").adjustScore(p2p.ScoreTimeout)
+	if score := atomic.LoadInt32(&light.score); score != p2p.ScoreTimeout {
+		t.Errorf("light peer score mismatch: have %d, want %d", score, p2p.ScoreTimeout)
+	}
+}
+
 // Tests that if a large batch of blocks are being downloaded, it is throttled
 // until the cached blocks are retrieved.
 func TestThrottling62(t *testing.T)     { testThrottling(t, 62, FullSync) }
@@ -1329,6 +1384,8 @@ func testBlockHeaderAttackerDropping(t *testing.T, protocol int) {
 		{errCancelReceiptFetch, false},      // Synchronisation was canceled, origin may be innocent, don't drop
 		{errCancelHeaderProcessing, false},  // Synchronisation was canceled, origin may be innocent, don't drop
 		{errCancelContentProcessing, false}, // Synchronisation was canceled, origin may be innocent, don't drop
+		{errUnknownCheckpoint, false},       // Checkpoint is not on the peer's chain, it may be the misconfigured one
+		{errNoCheckpointQuorum, false},      // Network disagrees on the checkpoint, origin may be innocent, don't drop
 	}
 	// Run the tests and check disconnection status
 	tester := newTester()
@@ -1753,3 +1810,166 @@ func testDeliverHeadersHang(t *testing.T, protocol int, mode SyncMode) {
 		tester.downloader.peers.peers["peer"].peer.(*floodingTestPeer).pend.Wait()
 	}
 }
+
+// Tests that an empty chain can be synchronised starting from a trusted checkpoint,
+// skipping everything but the few headers preceding it.
+func TestCheckpointSynchronisation63Full(t *testing.T) { testCheckpointSynchronisation(t, 63, FullSync) }
+func TestCheckpointSynchronisation63Fast(t *testing.T) { testCheckpointSynchronisation(t, 63, FastSync) }
+func TestCheckpointSynchronisation64Full(t *testing.T) { testCheckpointSynchronisation(t, 64, FullSync) }
+func TestCheckpointSynchronisation64Fast(t *testing.T) { testCheckpointSynchronisation(t, 64, FastSync) }
+
+func testCheckpointSynchronisation(t *testing.T, protocol int, mode SyncMode) {
+	t.Parallel()
+
+	tester := newTester()
+	defer tester.terminate()
+
+	// Create a chain long enough to have blocks before the checkpoint's ancestry
+	targetBlocks := blockCacheItems - 15
+	hashes, headers, blocks, receipts := tester.makeChain(targetBlocks, 0, tester.genesis, nil, false)
+
+	number := targetBlocks / 2
+	tester.newPeer("peer1", protocol, hashes, headers, blocks, receipts)
+	tester.newPeer("peer2", protocol, hashes, headers, blocks, receipts)
+
+	checkpoint := hashes[targetBlocks-number]
+	tester.downloader.checkpoint = &params.SyncCheckpoint{Hash: checkpoint, TD: tester.peerChainTds["peer1"][checkpoint]}
+
+	if err := tester.sync("peer1", nil, mode); err != nil {
+		t.Fatalf("failed to synchronise blocks: %v", err)
+	}
+	if head := tester.CurrentBlock().Hash(); head != hashes[0] {
+		t.Fatalf("head block mismatch: have %x, want %x", head, hashes[0])
+	}
+	if td, want := tester.GetTd(hashes[0], uint64(targetBlocks)), tester.peerChainTds["peer1"][hashes[0]]; td.Cmp(want) != 0 {
+		t.Fatalf("head td mismatch: have %v, want %v", td, want)
+	}
+	// Ensure only the checkpoint's ancestry was retrieved of the earlier chain
+	if have, want := len(tester.ownHeaders), 1+checkpointAncestry+targetBlocks-number+1; have != want {
+		t.Fatalf("synchronised headers mismatch: have %v, want %v", have, want)
+	}
+	if tester.HasHeader(hashes[targetBlocks-(number-checkpointAncestry-1)], 0) {
+		t.Fatalf("header below checkpoint ancestry retrieved")
+	}
+	if !tester.HasHeader(hashes[targetBlocks-(number-checkpointAncestry)], 0) {
+		t.Fatalf("checkpoint ancestry header missing")
+	}
+	if have, want := len(tester.ownBlocks), 1+targetBlocks-number+1; have != want {
+		t.Fatalf("synchronised blocks mismatch: have %v, want %v", have, want)
+	}
+}
+
+// Tests that a checkpoint not agreed upon by the majority of peers is rejected.
+func TestCheckpointQuorum63(t *testing.T) { testCheckpointQuorum(t, 63) }
+func TestCheckpointQuorum64(t *testing.T) { testCheckpointQuorum(t, 64) }
+
+func testCheckpointQuorum(t *testing.T, protocol int) {
+	t.Parallel()
+
+	tester := newTester()
+	defer tester.terminate()
+
+	// Create two forks, with the checkpoint being on the minority one
+	hashesA, hashesB, headersA, headersB, blocksA, blocksB, receiptsA, receiptsB := tester.makeChainFork(MaxHashFetch, 64, tester.genesis, nil, true)
+	tester.newPeer("fork A", protocol, hashesA, headersA, blocksA, receiptsA)
+	tester.newPeer("fork B1", protocol, hashesB, headersB, blocksB, receiptsB)
+	tester.newPeer("fork B2", protocol, hashesB, headersB, blocksB, receiptsB)
+
+	tester.downloader.checkpoint = &params.SyncCheckpoint{Hash: hashesA[32], TD: tester.peerChainTds["fork A"][hashesA[32]]}
+
+	if err := tester.sync("fork A", nil, FullSync); err != errNoCheckpointQuorum {
+		t.Fatalf("checkpoint quorum error mismatch: have %v, want %v", err, errNoCheckpointQuorum)
+	}
+	if head := tester.CurrentHeader().Number.Uint64(); head != 0 {
+		t.Fatalf("chain initialised despite missing quorum: head %d", head)
+	}
+	// Syncing from a peer not knowing about the checkpoint should fail too
+	if err := tester.sync("fork B1", nil, FullSync); err != errUnknownCheckpoint {
+		t.Fatalf("unknown checkpoint error mismatch: have %v, want %v", err, errUnknownCheckpoint)
+	}
+}
+
+// Tests that the progress of a sync cycle is persisted and restored by a new
+// downloader instance, and that the detailed status reports its phases.
+func TestSyncProgressPersistence63(t *testing.T) { testSyncProgressPersistence(t, 63) }
+func TestSyncProgressPersistence64(t *testing.T) { testSyncProgressPersistence(t, 64) }
+
+func testSyncProgressPersistence(t *testing.T, protocol int) {
+	t.Parallel()
+
+	tester := newTester()
+	defer tester.terminate()
+
+	targetBlocks := blockCacheItems - 15
+	hashes, headers, blocks, receipts := tester.makeChain(targetBlocks, 0, tester.genesis, nil, false)
+	tester.newPeer("peer", protocol, hashes, headers, blocks, receipts)
+
+	if err := tester.sync("peer", nil, FastSync); err != nil {
+		t.Fatalf("failed to synchronise blocks: %v", err)
+	}
+	assertOwnChain(t, tester, targetBlocks+1)
+
+	status := tester.downloader.Status()
+	if status.Syncing || status.Phase != phaseIdle {
+		t.Errorf("sync status mismatch: syncing %v, phase %q", status.Syncing, status.Phase)
+	}
+	if status.PivotBlock != 0 {
+		t.Errorf("pivot not cleared after sync: have %d", status.PivotBlock)
+	}
+	if status.Throughput["headers"] == 0 || status.Throughput["blocks"] == 0 || status.Throughput["receipts"] == 0 {
+		t.Errorf("throughput not measured: %v", status.Throughput)
+	}
+	if status.UsefulPeers != 1 {
+		t.Errorf("useful peer count mismatch: have %d, want %d", status.UsefulPeers, 1)
+	}
+	// Restart the downloader and ensure the sync origin is restored, but no sync
+	// is reported in progress until a peer announces its head
+	restarted := New(nil, FastSync, tester.stateDb, new(event.TypeMux), tester, nil, tester.dropPeer)
+	defer restarted.Terminate()
+
+	have, want := restarted.Progress(), tester.downloader.Progress()
+	if have.StartingBlock != want.StartingBlock {
+		t.Errorf("restored starting block mismatch: have %d, want %d", have.StartingBlock, want.StartingBlock)
+	}
+	if have.HighestBlock != 0 {
+		t.Errorf("highest block restored without a sync running: have %d, want 0", have.HighestBlock)
+	}
+}
+
+// Tests that a fast sync interrupted while retrieving the state of a pivot block
+// resumes with the same pivot if it is still recent enough.
+func TestSyncPivotResume63(t *testing.T) { testSyncPivotResume(t, 63) }
+func TestSyncPivotResume64(t *testing.T) { testSyncPivotResume(t, 64) }
+
+func testSyncPivotResume(t *testing.T, protocol int) {
+	t.Parallel()
+
+	tester := newTester()
+	defer tester.terminate()
+
+	targetBlocks := blockCacheItems - 15
+	hashes, headers, blocks, receipts := tester.makeChain(targetBlocks, 0, tester.genesis, nil, false)
+
+	// Simulate a previous run having been interrupted on a slightly older pivot
+	pivot := uint64(targetBlocks - fsMinFullBlocks - 16)
+	tester.downloader.setPivot(pivot)
+
+	restarted := New(nil, FastSync, tester.stateDb, new(event.TypeMux), tester, nil, tester.dropPeer)
+	tester.downloader.Terminate()
+	tester.downloader = restarted
+
+	if have := restarted.Status().PivotBlock; have != pivot {
+		t.Fatalf("restored pivot mismatch: have %d, want %d", have, pivot)
+	}
+	tester.newPeer("peer", protocol, hashes, headers, blocks, receipts)
+	if err := tester.sync("peer", nil, FastSync); err != nil {
+		t.Fatalf("failed to synchronise blocks: %v", err)
+	}
+	// Receipts are only retrieved up to (and including) the pivot block
+	if rs := len(tester.ownReceipts); rs != int(pivot)+1 {
+		t.Fatalf("synchronised receipts mismatch: have %v, want %v", rs, pivot+1)
+	}
+	if have := restarted.Status().PivotBlock; have != 0 {
+		t.Fatalf("pivot not cleared after sync: have %d", have)
+	}
+}
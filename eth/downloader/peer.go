@@ -90,6 +90,27 @@ type Peer interface {
 	RequestNodeData([]common.Hash) error
 }
 
+// scoredPeer is implemented by peers which track a reputation score, such as the
+// ones backed by a p2p.Peer.
+type scoredPeer interface {
+	AdjustScore(delta int)
+}
+
+// adjustScore reports the behaviour of the remote peer to its reputation score,
+// if the peer tracks one.
+func (p *peerConnection) adjustScore(delta int) {
+	peer := p.peer
+	if w, ok := peer.(*lightPeerWrapper); ok {
+		if scored, ok := w.peer.(scoredPeer); ok {
+			scored.AdjustScore(delta)
+		}
+		return
+	}
+	if scored, ok := peer.(scoredPeer); ok {
+		scored.AdjustScore(delta)
+	}
+}
+
 // lightPeerWrapper wraps a LightPeer struct, stubbing out the Peer-only methods.
 type lightPeerWrapper struct {
 	peer LightPeer
@@ -454,6 +475,23 @@ func (ps *peerSet) Len() int {
 	return len(ps.peers)
 }
 
+// UsefulPeers returns the number of peers within the set that have a non-zero
+// measured throughput for any kind of data, i.e. which are worth assigning tasks.
+func (ps *peerSet) UsefulPeers() int {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	useful := 0
+	for _, p := range ps.peers {
+		p.lock.RLock()
+		if p.headerThroughput > 0 || p.blockThroughput > 0 || p.receiptThroughput > 0 || p.stateThroughput > 0 {
+			useful++
+		}
+		p.lock.RUnlock()
+	}
+	return useful
+}
+
 // AllPeers retrieves a flat list of all the peers within the set.
 func (ps *peerSet) AllPeers() []*peerConnection {
 	ps.lock.RLock()
@@ -0,0 +1,230 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package downloader
+
+import (
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/core/rawdb"
+	"github.com/rwdxchain/go-rwdxchaina/log"
+	"github.com/rwdxchain/go-rwdxchaina/rlp"
+)
+
+// Phases of a sync cycle, as reported by the downloader diagnostics.
+const (
+	phaseIdle       = "idle"       // No sync cycle is running
+	phaseCheckpoint = "checkpoint" // Retrieving the trusted checkpoint block and its state
+	phaseAncestor   = "ancestor"   // Looking up the sync boundaries with the origin peer
+	phaseHeaders    = "headers"    // Retrieving the header skeleton and filling it
+	phaseBlocks     = "blocks"     // Retrieving and importing the remaining block contents
+	phaseState      = "state"      // Waiting for the state trie of the fast sync pivot
+)
+
+// syncProgress is the persisted progress of a sync cycle, allowing an interrupted
+// cycle to resume its reporting and its pivot state retrieval after a restart.
+//
+// The highest block of the cycle is deliberately not persisted: it is only known
+// once a peer reports its head, restoring it would make a restarted node report
+// a sync in progress while none is running.
+type syncProgress struct {
+	Origin     uint64 // Block number where the sync cycle started at
+	Pivot      uint64 // Fast sync pivot block whose state is being retrieved (0 = none)
+	Duplicate  uint64 // Number of state entries downloaded twice
+	Unexpected uint64 // Number of non-requested state entries received
+}
+
+// syncMeter tracks the number of items processed by a sync phase to report its
+// average throughput over the current sync cycle.
+type syncMeter struct {
+	items uint64    // Number of items processed in the current sync cycle
+	start time.Time // Time the current sync cycle started at
+}
+
+// reset clears the meter to start measuring a new sync cycle.
+func (m *syncMeter) reset(now time.Time) {
+	m.items, m.start = 0, now
+}
+
+// mark registers a number of processed items.
+func (m *syncMeter) mark(items int) {
+	m.items += uint64(items)
+}
+
+// rate returns the average number of items processed per second.
+func (m *syncMeter) rate(now time.Time) float64 {
+	if m.start.IsZero() {
+		return 0
+	}
+	elapsed := now.Sub(m.start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(m.items) / elapsed
+}
+
+// SyncStatus is a detailed report on the current (or last interrupted) sync cycle,
+// meant to help diagnosing which phase of the synchronisation is slow.
+type SyncStatus struct {
+	Syncing bool   `json:"syncing"` // Whether a sync cycle is currently running
+	Mode    string `json:"mode"`    // Synchronisation mode of the sync cycle
+	Phase   string `json:"phase"`   // Current phase of the sync cycle
+
+	StartingBlock uint64 `json:"startingBlock"` // Block number where the sync cycle started at
+	CurrentHeader uint64 `json:"currentHeader"` // Header number the header chain is currently at
+	CurrentBlock  uint64 `json:"currentBlock"`  // Block number the block import is currently at
+	HighestBlock  uint64 `json:"highestBlock"`  // Highest known block number the sync targets
+	PivotBlock    uint64 `json:"pivotBlock"`    // Fast sync pivot block whose state is being retrieved
+
+	PulledStates     uint64 `json:"pulledStates"`     // Number of state entries processed
+	HealingStates    uint64 `json:"healingStates"`    // Number of known state entries still to be retrieved
+	RetryStates      uint64 `json:"retryStates"`      // Number of state entries scheduled for (re)retrieval
+	DuplicateStates  uint64 `json:"duplicateStates"`  // Number of state entries downloaded twice
+	UnexpectedStates uint64 `json:"unexpectedStates"` // Number of non-requested state entries received
+
+	Peers       int `json:"peers"`       // Number of peers registered with the downloader
+	UsefulPeers int `json:"usefulPeers"` // Number of peers with a non-zero measured throughput
+
+	Throughput map[string]float64 `json:"throughput"` // Items processed per second in each phase
+	ETA        uint64             `json:"eta"`        // Estimated seconds until the sync completes (0 = unknown)
+}
+
+// loadProgress restores the progress of an interrupted sync cycle from the database.
+func (d *Downloader) loadProgress() {
+	blob := rawdb.ReadSyncProgress(d.stateDB)
+	if len(blob) == 0 {
+		return
+	}
+	var progress syncProgress
+	if err := rlp.DecodeBytes(blob, &progress); err != nil {
+		log.Warn("Failed to decode sync progress", "err", err)
+		return
+	}
+	d.syncStatsChainOrigin = progress.Origin
+	d.syncStatsPivot = progress.Pivot
+	d.syncStatsState.duplicate = progress.Duplicate
+	d.syncStatsState.unexpected = progress.Unexpected
+}
+
+// storeProgress persists the progress of the current sync cycle into the database.
+// The caller must hold the sync stats lock.
+func (d *Downloader) storeProgress() {
+	blob, err := rlp.EncodeToBytes(&syncProgress{
+		Origin:     d.syncStatsChainOrigin,
+		Pivot:      d.syncStatsPivot,
+		Duplicate:  d.syncStatsState.duplicate,
+		Unexpected: d.syncStatsState.unexpected,
+	})
+	if err != nil {
+		log.Crit("Failed to encode sync progress", "err", err)
+	}
+	rawdb.WriteSyncProgress(d.stateDB, blob)
+}
+
+// resetMeters clears all the phase throughput meters at the start of a sync cycle.
+func (d *Downloader) resetMeters() {
+	d.syncStatsLock.Lock()
+	defer d.syncStatsLock.Unlock()
+
+	now := time.Now()
+	for _, meter := range []*syncMeter{&d.syncStatsHeaders, &d.syncStatsBlocks, &d.syncStatsReceipts, &d.syncStatsStates} {
+		meter.reset(now)
+	}
+}
+
+// setPhase updates the currently running phase of the sync cycle.
+func (d *Downloader) setPhase(phase string) {
+	d.syncStatsLock.Lock()
+	defer d.syncStatsLock.Unlock()
+
+	d.syncStatsPhase = phase
+}
+
+// setPivot updates and persists the fast sync pivot whose state is being retrieved.
+func (d *Downloader) setPivot(pivot uint64) {
+	d.syncStatsLock.Lock()
+	defer d.syncStatsLock.Unlock()
+
+	d.syncStatsPivot = pivot
+	d.storeProgress()
+}
+
+// markItems registers a number of items processed by a sync phase.
+func (d *Downloader) markItems(meter *syncMeter, items int) {
+	d.syncStatsLock.Lock()
+	defer d.syncStatsLock.Unlock()
+
+	meter.mark(items)
+}
+
+// Status retrieves a detailed report on the current (or last interrupted) sync
+// cycle, including the phase it is in, the throughput of each phase, the number
+// of useful peers and the backlog of state entries still to be retrieved.
+func (d *Downloader) Status() SyncStatus {
+	d.syncStatsLock.RLock()
+	defer d.syncStatsLock.RUnlock()
+
+	var (
+		now     = time.Now()
+		header  = d.lightchain.CurrentHeader().Number.Uint64()
+		current = d.currentBlock()
+	)
+	status := SyncStatus{
+		Syncing:          d.Synchronising(),
+		Mode:             d.mode.String(),
+		Phase:            d.syncStatsPhase,
+		StartingBlock:    d.syncStatsChainOrigin,
+		CurrentHeader:    header,
+		CurrentBlock:     current,
+		HighestBlock:     d.syncStatsChainHeight,
+		PivotBlock:       d.syncStatsPivot,
+		PulledStates:     d.syncStatsState.processed,
+		HealingStates:    d.syncStatsState.pending,
+		RetryStates:      d.syncStatsState.retry,
+		DuplicateStates:  d.syncStatsState.duplicate,
+		UnexpectedStates: d.syncStatsState.unexpected,
+		Peers:            d.peers.Len(),
+		UsefulPeers:      d.peers.UsefulPeers(),
+		Throughput: map[string]float64{
+			"headers":  d.syncStatsHeaders.rate(now),
+			"blocks":   d.syncStatsBlocks.rate(now),
+			"receipts": d.syncStatsReceipts.rate(now),
+			"states":   d.syncStatsStates.rate(now),
+		},
+	}
+	if status.Phase == "" {
+		status.Phase = phaseIdle
+	}
+	// Estimate the remaining time as that of the slowest phase still in progress
+	var eta float64
+	if height := d.syncStatsChainHeight; status.Syncing {
+		if rate := d.syncStatsHeaders.rate(now); height > header && rate > 0 {
+			eta = float64(height-header) / rate
+		}
+		if rate := d.syncStatsBlocks.rate(now); d.mode != LightSync && height > current && rate > 0 {
+			if left := float64(height-current) / rate; left > eta {
+				eta = left
+			}
+		}
+		if rate := d.syncStatsStates.rate(now); d.syncStatsState.pending > 0 && rate > 0 {
+			if left := float64(d.syncStatsState.pending) / rate; left > eta {
+				eta = left
+			}
+		}
+	}
+	status.ETA = uint64(eta)
+	return status
+}
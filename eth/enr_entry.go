@@ -0,0 +1,79 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"github.com/rwdxchain/go-rwdxchaina/core"
+	"github.com/rwdxchain/go-rwdxchaina/core/forkid"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/enr"
+	"github.com/rwdxchain/go-rwdxchaina/rlp"
+)
+
+// enrEntry is the node record entry advertising the protocol and the fork ID of
+// the local chain on the discovery network.
+type enrEntry struct {
+	ForkID forkid.ID // Fork identifier of the local chain
+
+	// Ignore additional fields (for forward compatibility).
+	Rest []rlp.RawValue `rlp:"tail"`
+}
+
+// ENRKey implements enr.Entry.
+func (e enrEntry) ENRKey() string {
+	return ProtocolName
+}
+
+// currentENREntry constructs the node record entry based on the current state
+// of the chain.
+func currentENREntry(chain forkid.Blockchain) *enrEntry {
+	return &enrEntry{ForkID: forkid.NewID(chain)}
+}
+
+// newDialFilter creates a dial filter skipping the discovered nodes that are on
+// an incompatible chain according to their advertised fork ID. Nodes without the
+// entry in their records are accepted, as they might just not advertise it.
+func newDialFilter(filter forkid.Filter) func(r *enr.Record) bool {
+	return func(r *enr.Record) bool {
+		var entry enrEntry
+		if err := r.Load(&entry); err != nil {
+			return enr.IsNotFound(err)
+		}
+		return filter(entry.ForkID) == nil
+	}
+}
+
+// startENRUpdater keeps the entry of the local node record in sync with the fork
+// ID of the chain as it progresses through the forks.
+func (s *Ethereum) startENRUpdater(record *discover.LocalRecord) {
+	newHead := make(chan core.ChainHeadEvent, 10)
+	sub := s.blockchain.SubscribeChainHeadEvent(newHead)
+
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case <-newHead:
+				record.Set(currentENREntry(s.blockchain))
+			case <-sub.Err():
+				return
+			case <-s.shutdownChan:
+				return
+			}
+		}
+	}()
+}
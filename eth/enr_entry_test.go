@@ -0,0 +1,56 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"testing"
+
+	"github.com/rwdxchain/go-rwdxchaina/core/forkid"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+	"github.com/rwdxchain/go-rwdxchaina/eth/downloader"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/enr"
+)
+
+// Tests that discovered nodes are filtered based on the fork ID in their records.
+func TestDialFilter(t *testing.T) {
+	pm, _ := newTestProtocolManagerMust(t, downloader.FullSync, 0, nil, nil)
+	defer pm.Stop()
+
+	filter := pm.SubProtocols[0].DialFilter
+	if filter == nil {
+		t.Fatal("no dial filter set")
+	}
+	tests := []struct {
+		entry  enr.Entry
+		accept bool
+	}{
+		{nil, true}, // no entry advertised
+		{currentENREntry(pm.blockchain), true},
+		{&enrEntry{ForkID: forkid.ID{Hash: [4]byte{0x00, 0x01, 0x02, 0x03}}}, false},
+	}
+	for i, tt := range tests {
+		key, _ := crypto.GenerateKey()
+		local := discover.NewLocalRecord(key)
+		if tt.entry != nil {
+			local.Set(tt.entry)
+		}
+		if accept := filter(local.Record()); accept != tt.accept {
+			t.Errorf("test %d: filter mismatch: have %v, want %v", i, accept, tt.accept)
+		}
+	}
+}
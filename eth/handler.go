@@ -30,6 +30,7 @@ import (
 	"github.com/rwdxchain/go-rwdxchaina/consensus"
 	"github.com/rwdxchain/go-rwdxchaina/consensus/misc"
 	"github.com/rwdxchain/go-rwdxchaina/core"
+	"github.com/rwdxchain/go-rwdxchaina/core/forkid"
 	"github.com/rwdxchain/go-rwdxchaina/core/types"
 	"github.com/rwdxchain/go-rwdxchaina/eth/downloader"
 	"github.com/rwdxchain/go-rwdxchaina/eth/fetcher"
@@ -38,6 +39,7 @@ import (
 	"github.com/rwdxchain/go-rwdxchaina/log"
 	"github.com/rwdxchain/go-rwdxchaina/p2p"
 	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/enr"
 	"github.com/rwdxchain/go-rwdxchaina/params"
 	"github.com/rwdxchain/go-rwdxchaina/rlp"
 )
@@ -72,6 +74,7 @@ type ProtocolManager struct {
 	txpool      txPool
 	blockchain  *core.BlockChain
 	chainconfig *params.ChainConfig
+	forkFilter  forkid.Filter // Fork ID filter validating remote peers against the local chain
 	maxPeers    int
 
 	downloader *downloader.Downloader
@@ -98,7 +101,7 @@ type ProtocolManager struct {
 
 // NewProtocolManager returns a new Ethereum sub protocol manager. The Ethereum sub protocol manages peers capable
 // with the Ethereum network.
-func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, networkID uint64, mux *event.TypeMux, txpool txPool, engine consensus.Engine, blockchain *core.BlockChain, chaindb ethdb.Database) (*ProtocolManager, error) {
+func NewProtocolManager(config *params.ChainConfig, checkpoint *params.SyncCheckpoint, mode downloader.SyncMode, networkID uint64, mux *event.TypeMux, txpool txPool, engine consensus.Engine, blockchain *core.BlockChain, chaindb ethdb.Database) (*ProtocolManager, error) {
 	// Create the protocol manager with the base fields
 	manager := &ProtocolManager{
 		networkID:   networkID,
@@ -106,6 +109,7 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 		txpool:      txpool,
 		blockchain:  blockchain,
 		chainconfig: config,
+		forkFilter:  forkid.NewFilter(blockchain),
 		peers:       newPeerSet(),
 		newPeerCh:   make(chan *peer),
 		noMorePeers: make(chan struct{}),
@@ -120,11 +124,16 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 	if mode == downloader.FastSync {
 		manager.fastSync = uint32(1)
 	}
+	// Sync checkpoints only make sense if there's no local chain yet
+	if checkpoint != nil && blockchain.CurrentBlock().NumberU64() > 0 {
+		log.Warn("Blockchain not empty, sync checkpoint ignored", "checkpoint", checkpoint.Hash)
+		checkpoint = nil
+	}
 	// Initiate a sub-protocol for every implemented version we can handle
 	manager.SubProtocols = make([]p2p.Protocol, 0, len(ProtocolVersions))
 	for i, version := range ProtocolVersions {
 		// Skip protocol version if incompatible with the mode of operation
-		if mode == downloader.FastSync && version < eth63 {
+		if (mode == downloader.FastSync || checkpoint != nil) && version < eth63 {
 			continue
 		}
 		// Compatible; initialise the sub-protocol
@@ -153,13 +162,15 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 				}
 				return nil
 			},
+			Attributes: []enr.Entry{currentENREntry(blockchain)},
+			DialFilter: newDialFilter(manager.forkFilter),
 		})
 	}
 	if len(manager.SubProtocols) == 0 {
 		return nil, errIncompatibleConfig
 	}
 	// Construct the different synchronisation mechanisms
-	manager.downloader = downloader.New(mode, chaindb, manager.eventMux, blockchain, nil, manager.removePeer)
+	manager.downloader = downloader.New(checkpoint, mode, chaindb, manager.eventMux, blockchain, nil, manager.removePeer)
 
 	validator := func(header *types.Header) error {
 		return engine.VerifyHeader(blockchain, header, true)
@@ -263,7 +274,7 @@ func (pm *ProtocolManager) handle(p *peer) error {
 		number  = head.Number.Uint64()
 		td      = pm.blockchain.GetTd(hash, number)
 	)
-	if err := p.Handshake(pm.networkID, td, hash, genesis.Hash()); err != nil {
+	if err := p.Handshake(pm.networkID, td, hash, genesis.Hash(), forkid.NewID(pm.blockchain), pm.forkFilter); err != nil {
 		p.Log().Debug("Rwdxchain handshake failed", "err", err)
 		return err
 	}
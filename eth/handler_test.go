@@ -45,8 +45,8 @@ func TestProtocolCompatibility(t *testing.T) {
 		mode       downloader.SyncMode
 		compatible bool
 	}{
-		{61, downloader.FullSync, true}, {62, downloader.FullSync, true}, {63, downloader.FullSync, true},
-		{61, downloader.FastSync, false}, {62, downloader.FastSync, false}, {63, downloader.FastSync, true},
+		{61, downloader.FullSync, true}, {62, downloader.FullSync, true}, {63, downloader.FullSync, true}, {64, downloader.FullSync, true},
+		{61, downloader.FastSync, false}, {62, downloader.FastSync, false}, {63, downloader.FastSync, true}, {64, downloader.FastSync, true},
 	}
 	// Make sure anything we screw up is restored
 	backup := ProtocolVersions
@@ -69,6 +69,7 @@ func TestProtocolCompatibility(t *testing.T) {
 // Tests that block headers can be retrieved from a remote chain based on user queries.
 func TestGetBlockHeaders62(t *testing.T) { testGetBlockHeaders(t, 62) }
 func TestGetBlockHeaders63(t *testing.T) { testGetBlockHeaders(t, 63) }
+func TestGetBlockHeaders64(t *testing.T) { testGetBlockHeaders(t, 64) }
 
 func testGetBlockHeaders(t *testing.T, protocol int) {
 	pm, _ := newTestProtocolManagerMust(t, downloader.FullSync, downloader.MaxHashFetch+15, nil, nil)
@@ -474,7 +475,7 @@ func testDAOChallenge(t *testing.T, localForked, remoteForked bool, timeout bool
 		genesis       = gspec.MustCommit(db)
 		blockchain, _ = core.NewBlockChain(db, nil, config, pow, vm.Config{})
 	)
-	pm, err := NewProtocolManager(config, downloader.FullSync, DefaultConfig.NetworkId, evmux, new(testTxPool), pow, blockchain, db)
+	pm, err := NewProtocolManager(config, nil, downloader.FullSync, DefaultConfig.NetworkId, evmux, new(testTxPool), pow, blockchain, db)
 	if err != nil {
 		t.Fatalf("failed to start test protocol manager: %v", err)
 	}
@@ -520,3 +521,28 @@ func testDAOChallenge(t *testing.T, localForked, remoteForked bool, timeout bool
 		}
 	}
 }
+
+// Tests that responses arriving while no sync is running, e.g. late replies of
+// honest peers after a sync finished, don't count against their reputation.
+func TestLateResponsesNotPenalised(t *testing.T) {
+	pm, _ := newTestProtocolManagerMust(t, downloader.FullSync, 4, nil, nil)
+	peer, _ := newTestPeer("peer", eth63, pm, true)
+	defer peer.close()
+
+	score := peer.peer.Score()
+
+	block := pm.blockchain.GetBlockByNumber(1)
+	p2p.Send(peer.app, BlockHeadersMsg, []*types.Header{block.Header()})
+	p2p.Send(peer.app, BlockBodiesMsg, []*blockBody{{Transactions: block.Transactions(), Uncles: block.Uncles()}})
+	p2p.Send(peer.app, NodeDataMsg, [][]byte{{0x01}})
+	p2p.Send(peer.app, ReceiptsMsg, []types.Receipts{nil})
+
+	// Round trip a request to make sure all responses were processed
+	p2p.Send(peer.app, GetBlockHeadersMsg, &getBlockHeadersData{Origin: hashOrNumber{Number: 1}, Amount: 1})
+	if err := p2p.ExpectMsg(peer.app, BlockHeadersMsg, []*types.Header{block.Header()}); err != nil {
+		t.Fatalf("headers mismatch: %v", err)
+	}
+	if have := peer.peer.Score(); have != score {
+		t.Fatalf("score changed by late responses: have %d, want %d", have, score)
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+// DefaultULCMinTrustedFraction is the default percentage of trusted servers
+// which need to announce a new head for an ultra-light client to accept it.
+const DefaultULCMinTrustedFraction = 75
+
+// ULCConfig is the configuration of the ultra-light client mode, in which the
+// light client follows the heads announced by a fixed set of trusted servers
+// instead of verifying the header chain itself.
+type ULCConfig struct {
+	TrustedServers     []string `toml:",omitempty"` // Enode URLs of the trusted servers
+	MinTrustedFraction int      `toml:",omitempty"` // Percentage of trusted servers required to accept a head (1-100)
+}
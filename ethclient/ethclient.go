@@ -455,6 +455,42 @@ func (ec *Client) PendingCallContract(ctx context.Context, msg ethereum.CallMsg)
 	return hex, nil
 }
 
+// BatchCallContract executes multiple message call transactions in a single batch
+// request, all of them at the given block height (nil = latest). The failures of
+// individual calls are reported in errs, err is only set if the batch request as
+// a whole failed.
+func (ec *Client) BatchCallContract(ctx context.Context, msgs []ethereum.CallMsg, blockNumber *big.Int) ([][]byte, []error, error) {
+	return ec.batchCallContract(ctx, msgs, toBlockNumArg(blockNumber))
+}
+
+// PendingBatchCallContract executes multiple message call transactions against the
+// pending state in a single batch request.
+func (ec *Client) PendingBatchCallContract(ctx context.Context, msgs []ethereum.CallMsg) ([][]byte, []error, error) {
+	return ec.batchCallContract(ctx, msgs, "pending")
+}
+
+func (ec *Client) batchCallContract(ctx context.Context, msgs []ethereum.CallMsg, block string) ([][]byte, []error, error) {
+	var (
+		reqs = make([]rpc.BatchElem, len(msgs))
+		hexs = make([]hexutil.Bytes, len(msgs))
+	)
+	for i, msg := range msgs {
+		reqs[i] = rpc.BatchElem{
+			Method: "eth_call",
+			Args:   []interface{}{toCallArg(msg), block},
+			Result: &hexs[i],
+		}
+	}
+	if err := ec.c.BatchCallContext(ctx, reqs); err != nil {
+		return nil, nil, err
+	}
+	outputs, errs := make([][]byte, len(msgs)), make([]error, len(msgs))
+	for i := range reqs {
+		outputs[i], errs[i] = hexs[i], reqs[i].Error
+	}
+	return outputs, errs, nil
+}
+
 // SuggestGasPrice retrieves the currently suggested gas price to allow a timely
 // execution of a transaction.
 func (ec *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
@@ -16,7 +16,19 @@
 
 package ethclient
 
-import "github.com/rwdxchain/go-rwdxchaina"
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/rwdxchain/go-rwdxchaina"
+	"github.com/rwdxchain/go-rwdxchaina/accounts/abi/bind"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/common/hexutil"
+	"github.com/rwdxchain/go-rwdxchaina/rpc"
+)
 
 // Verify that Client implements the ethereum interfaces.
 var (
@@ -32,3 +44,76 @@ var (
 	// _ = ethereum.PendingStateEventer(&Client{})
 	_ = ethereum.PendingContractCaller(&Client{})
 )
+
+// Verify that Client implements the contract binding batch interfaces.
+var (
+	_ = bind.BatchCaller(&Client{})
+	_ = bind.PendingBatchCaller(&Client{})
+)
+
+// CallService is an eth API serving calls, which echo their input followed by
+// the requested block, or fail if there is no input.
+type CallService struct{}
+
+type CallArgs struct {
+	To   *common.Address `json:"to"`
+	Data hexutil.Bytes   `json:"data"`
+}
+
+func (s *CallService) Call(args CallArgs, block string) (hexutil.Bytes, error) {
+	if len(args.Data) == 0 {
+		return nil, errors.New("execution reverted")
+	}
+	return append(append([]byte{}, args.Data...), block...), nil
+}
+
+// Tests that batched contract calls are executed at the requested block, with
+// the failures of the individual calls reported separately.
+func TestBatchCallContract(t *testing.T) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", new(CallService)); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+	client := NewClient(rpc.DialInProc(server))
+	defer client.Close()
+
+	to := common.Address{1}
+	msgs := []ethereum.CallMsg{
+		{To: &to, Data: []byte{0x01}},
+		{To: &to},
+		{To: &to, Data: []byte{0x02}},
+	}
+	tests := []struct {
+		call  func() ([][]byte, []error, error)
+		block string
+	}{
+		{func() ([][]byte, []error, error) { return client.BatchCallContract(context.Background(), msgs, nil) }, "latest"},
+		{func() ([][]byte, []error, error) {
+			return client.BatchCallContract(context.Background(), msgs, big.NewInt(16))
+		}, "0x10"},
+		{func() ([][]byte, []error, error) { return client.PendingBatchCallContract(context.Background(), msgs) }, "pending"},
+	}
+	for i, tt := range tests {
+		outputs, errs, err := tt.call()
+		if err != nil {
+			t.Fatalf("test %d: batch failed: %v", i, err)
+		}
+		if len(outputs) != len(msgs) || len(errs) != len(msgs) {
+			t.Fatalf("test %d: result count mismatch: have %d/%d, want %d", i, len(outputs), len(errs), len(msgs))
+		}
+		for j, msg := range msgs {
+			if len(msg.Data) == 0 {
+				if errs[j] == nil {
+					t.Errorf("test %d, call %d: failure not reported", i, j)
+				}
+				continue
+			}
+			if errs[j] != nil {
+				t.Errorf("test %d, call %d: unexpected failure: %v", i, j, errs[j])
+			}
+			if want := append(append([]byte{}, msg.Data...), tt.block...); !bytes.Equal(outputs[j], want) {
+				t.Errorf("test %d, call %d: output mismatch: have %q, want %q", i, j, outputs[j], want)
+			}
+		}
+	}
+}
@@ -42,6 +42,7 @@ import (
 	"github.com/rwdxchain/go-rwdxchaina/params"
 	"github.com/rwdxchain/go-rwdxchaina/rlp"
 	"github.com/rwdxchain/go-rwdxchaina/rpc"
+	"github.com/rwdxchain/go-rwdxchaina/signer/eip712"
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/util"
 )
@@ -87,12 +88,17 @@ func (s *PublicEthereumAPI) Syncing() (interface{}, error) {
 		return false, nil
 	}
 	// Otherwise gather the block sync stats
+	status := s.b.Downloader().Status()
 	return map[string]interface{}{
 		"startingBlock": hexutil.Uint64(progress.StartingBlock),
 		"currentBlock":  hexutil.Uint64(progress.CurrentBlock),
 		"highestBlock":  hexutil.Uint64(progress.HighestBlock),
 		"pulledStates":  hexutil.Uint64(progress.PulledStates),
 		"knownStates":   hexutil.Uint64(progress.KnownStates),
+		"phase":         status.Phase,
+		"eta":           hexutil.Uint64(status.ETA),
+		"usefulPeers":   hexutil.Uint64(status.UsefulPeers),
+		"healingStates": hexutil.Uint64(status.HealingStates),
 	}, nil
 }
 
@@ -1266,6 +1272,36 @@ func (s *PublicTransactionPoolAPI) Sign(addr common.Address, data hexutil.Bytes)
 	return signature, err
 }
 
+// SignTypedData calculates an ECDSA signature for typed structured data
+// according to EIP-712:
+// keccak256("\x19\x01" + domainSeparator + hashStruct(message)).
+//
+// Note, the produced signature conforms to the secp256k1 curve R, S and V values,
+// where the V value will be 27 or 28 for legacy reasons.
+//
+// The account associated with addr must be unlocked.
+//
+// https://github.com/ethereum/EIPs/blob/master/EIPS/eip-712.md
+func (s *PublicTransactionPoolAPI) SignTypedData(addr common.Address, typedData eip712.TypedData) (hexutil.Bytes, error) {
+	// Look up the wallet containing the requested signer
+	account := accounts.Account{Address: addr}
+
+	wallet, err := s.b.AccountManager().Find(account)
+	if err != nil {
+		return nil, err
+	}
+	sighash, _, err := typedData.SigHash()
+	if err != nil {
+		return nil, err
+	}
+	// Sign the typed data hash with the wallet
+	signature, err := wallet.SignHash(account, sighash)
+	if err == nil {
+		signature[64] += 27 // Transform V from 0/1 to 27/28 according to the yellow paper
+	}
+	return signature, err
+}
+
 // SignTransactionResult represents a RLP encoded signed transaction.
 type SignTransactionResult struct {
 	Raw hexutil.Bytes      `json:"raw"`
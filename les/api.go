@@ -0,0 +1,100 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"errors"
+
+	"github.com/rwdxchain/go-rwdxchaina/common/hexutil"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+)
+
+var errServerNotStarted = errors.New("light server not started")
+
+// PrivateLightServerAPI provides an API to manage the clients of a light server,
+// assigning serving capacity and token balances to priority clients.
+//
+// Capacities are expressed in the units of the flow control minimum recharge rate,
+// a free client being assigned the server's default recharge rate. Balances are
+// expressed in the units of the flow control request costs.
+type PrivateLightServerAPI struct {
+	server *LesServer
+}
+
+// NewPrivateLightServerAPI creates a new LES light server API.
+func NewPrivateLightServerAPI(server *LesServer) *PrivateLightServerAPI {
+	return &PrivateLightServerAPI{server: server}
+}
+
+// pool retrieves the priority client pool of the server, if it's running.
+func (api *PrivateLightServerAPI) pool() (*priorityClientPool, error) {
+	pool := api.server.protocolManager.priorityPool()
+	if pool == nil {
+		return nil, errServerNotStarted
+	}
+	return pool, nil
+}
+
+// TotalCapacity returns the total serving capacity of the server.
+func (api *PrivateLightServerAPI) TotalCapacity() (hexutil.Uint64, error) {
+	pool, err := api.pool()
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(pool.totalCap), nil
+}
+
+// FreeClientCapacity returns the capacity assigned to each free client.
+func (api *PrivateLightServerAPI) FreeClientCapacity() (hexutil.Uint64, error) {
+	pool, err := api.pool()
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(pool.freeClientCap), nil
+}
+
+// PriorityClients returns the balance, capacity and connection status of all
+// known priority clients.
+func (api *PrivateLightServerAPI) PriorityClients() (map[discover.NodeID]priorityClientStats, error) {
+	pool, err := api.pool()
+	if err != nil {
+		return nil, err
+	}
+	return pool.stats(), nil
+}
+
+// AddBalance adds the given amount to the balance of a client (a negative amount
+// removes from it) and returns the balances before and after the operation.
+func (api *PrivateLightServerAPI) AddBalance(id discover.NodeID, amount int64) ([2]uint64, error) {
+	pool, err := api.pool()
+	if err != nil {
+		return [2]uint64{}, err
+	}
+	old, new, err := pool.addBalance(id, amount)
+	return [2]uint64{old, new}, err
+}
+
+// SetClientCapacity assigns a serving capacity to a client, making it a priority
+// client as long as it has a positive balance. A zero capacity revokes the priority
+// status. Connected clients are disconnected to reconnect with their new capacity.
+func (api *PrivateLightServerAPI) SetClientCapacity(id discover.NodeID, capacity uint64) error {
+	pool, err := api.pool()
+	if err != nil {
+		return err
+	}
+	return pool.setCapacity(id, capacity)
+}
@@ -139,6 +139,9 @@ func New(ctx *node.ServiceContext, config *eth.Config) (*LightEthereum, error) {
 	if leth.protocolManager, err = NewProtocolManager(leth.chainConfig, light.DefaultClientIndexerConfig, true, config.NetworkId, leth.eventMux, leth.engine, leth.peers, leth.blockchain, nil, chainDb, leth.odr, leth.relay, leth.serverPool, quitSync, &leth.wg); err != nil {
 		return nil, err
 	}
+	leth.protocolManager.oracle = newCheckpointOracle(config.CheckpointOracle, leth.blockchain)
+	leth.protocolManager.ulc = newULC(config.ULC)
+
 	leth.ApiBackend = &LesApiBackend{leth, nil}
 	gpoParams := config.GPO
 	if gpoParams.Default == nil {
@@ -197,6 +200,11 @@ func (s *LightEthereum) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   downloader.NewPublicDownloaderAPI(s.protocolManager.downloader, s.eventMux),
 			Public:    true,
+		}, {
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   downloader.NewPublicDebugAPI(s.protocolManager.downloader),
+			Public:    true,
 		}, {
 			Namespace: "eth",
 			Version:   "1.0",
@@ -237,6 +245,12 @@ func (s *LightEthereum) Start(srvr *p2p.Server) error {
 	// clients are searching for the first advertised protocol in the list
 	protocolVersion := AdvertiseProtocolVersions[0]
 	s.serverPool.start(srvr, lesTopic(s.blockchain.Genesis().Hash(), protocolVersion))
+	// Ultra-light clients always keep their trusted servers connected
+	if ulc := s.protocolManager.ulc; ulc != nil {
+		for _, node := range ulc.trusted {
+			srvr.AddPeer(node)
+		}
+	}
 	s.protocolManager.Start(s.config.LightPeers)
 	return nil
 }
@@ -0,0 +1,92 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/contracts/checkpointoracle"
+	"github.com/rwdxchain/go-rwdxchaina/core/types"
+	"github.com/rwdxchain/go-rwdxchaina/light"
+	"github.com/rwdxchain/go-rwdxchaina/log"
+	"github.com/rwdxchain/go-rwdxchaina/params"
+)
+
+// checkpointOracle retrieves the latest checkpoint registered in the on-chain
+// checkpoint oracle contract and, if it's approved by enough trusted signers,
+// adds it to the light chain as a trusted checkpoint.
+type checkpointOracle struct {
+	config *params.CheckpointOracleConfig
+	chain  *light.LightChain
+
+	lock   sync.Mutex
+	latest uint64 // Section index + 1 of the latest processed checkpoint
+}
+
+// newCheckpointOracle creates a checkpoint oracle handler for the given config,
+// returning nil if no (or an unusable) oracle is configured.
+func newCheckpointOracle(config *params.CheckpointOracleConfig, chain *light.LightChain) *checkpointOracle {
+	if config == nil {
+		return nil
+	}
+	if config.Threshold == 0 || config.Threshold > uint64(len(config.Signers)) {
+		log.Error("Invalid checkpoint oracle config", "signers", len(config.Signers), "threshold", config.Threshold)
+		return nil
+	}
+	log.Info("Configured checkpoint oracle", "address", config.Address, "signers", len(config.Signers), "threshold", config.Threshold)
+	return &checkpointOracle{config: config, chain: chain}
+}
+
+// update retrieves the latest checkpoint from the oracle contract storage at the
+// given head via ODR, and applies it if it covers sections not known yet. The
+// head need not be part of the local chain, as checkpoints are only accepted if
+// signed by the trusted signers.
+func (o *checkpointOracle) update(ctx context.Context, head *types.Header) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	statedb := light.NewState(ctx, head, o.chain.Odr())
+	checkpoint, sigs := checkpointoracle.ReadCheckpoint(func(slot common.Hash) common.Hash {
+		return statedb.GetState(o.config.Address, slot)
+	})
+	if err := statedb.Error(); err != nil {
+		log.Debug("Failed to retrieve oracle checkpoint", "err", err)
+		return
+	}
+	if checkpoint == nil || checkpoint.SectionIndex < o.latest {
+		return
+	}
+	if indexer := o.chain.Odr().ChtIndexer(); indexer != nil {
+		if sections, _, _ := indexer.Sections(); checkpoint.SectionIndex < sections {
+			return
+		}
+	}
+	if err := checkpoint.VerifySigners(o.config.Address, sigs, o.config.Signers, int(o.config.Threshold)); err != nil {
+		log.Warn("Rejected oracle checkpoint", "section", checkpoint.SectionIndex, "head", checkpoint.SectionHead, "err", err)
+		return
+	}
+	o.latest = checkpoint.SectionIndex + 1
+
+	o.chain.AddTrustedCheckpoint("oracle", light.TrustedCheckpoint{
+		SectionIdx:  checkpoint.SectionIndex,
+		SectionHead: checkpoint.SectionHead,
+		CHTRoot:     checkpoint.CHTRoot,
+		BloomRoot:   checkpoint.BloomRoot,
+	})
+}
@@ -0,0 +1,179 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/accounts/abi"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/consensus/ethash"
+	"github.com/rwdxchain/go-rwdxchaina/contracts/checkpointoracle"
+	"github.com/rwdxchain/go-rwdxchaina/contracts/checkpointoracle/contract"
+	"github.com/rwdxchain/go-rwdxchaina/core"
+	"github.com/rwdxchain/go-rwdxchaina/core/types"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+	"github.com/rwdxchain/go-rwdxchaina/light"
+	"github.com/rwdxchain/go-rwdxchaina/params"
+)
+
+// registerCheckpoint extends the chain of the server with a block deploying a
+// checkpoint oracle administered by the given key, and registering the checkpoint
+// in it. The address of the oracle is returned.
+func registerCheckpoint(t *testing.T, server *TestEntity, checkpoint *checkpointoracle.Checkpoint, admin *ecdsa.PrivateKey) common.Address {
+	parsed, err := abi.JSON(strings.NewReader(contract.CheckpointOracleABI))
+	if err != nil {
+		t.Fatalf("failed to parse oracle ABI: %v", err)
+	}
+	args, err := parsed.Pack("", []common.Address{crypto.PubkeyToAddress(admin.PublicKey)}, big.NewInt(1))
+	if err != nil {
+		t.Fatalf("failed to pack constructor arguments: %v", err)
+	}
+	var (
+		bc     = server.pm.blockchain.(*core.BlockChain)
+		signer = types.HomesteadSigner{}
+		oracle common.Address
+	)
+	blocks, _ := core.GenerateChain(bc.Config(), bc.CurrentBlock(), ethash.NewFaker(), server.db, 1, func(i int, block *core.BlockGen) {
+		nonce := block.TxNonce(testBankAddress)
+		oracle = crypto.CreateAddress(testBankAddress, nonce)
+
+		sig, err := checkpoint.Sign(oracle, admin)
+		if err != nil {
+			t.Fatalf("failed to sign checkpoint: %v", err)
+		}
+		var r, s [32]byte
+		copy(r[:], sig[:32])
+		copy(s[:], sig[32:64])
+
+		input, err := parsed.Pack("SetCheckpoint", checkpoint.SectionIndex, [32]byte(checkpoint.SectionHead), [32]byte(checkpoint.CHTRoot), [32]byte(checkpoint.BloomRoot), []uint8{sig[64] + 27}, [][32]byte{r}, [][32]byte{s})
+		if err != nil {
+			t.Fatalf("failed to pack checkpoint registration: %v", err)
+		}
+		deploy, _ := types.SignTx(types.NewContractCreation(nonce, new(big.Int), 1000000, nil, append(common.FromHex(contract.CheckpointOracleBin), args...)), signer, testBankKey)
+		register, _ := types.SignTx(types.NewTransaction(nonce+1, oracle, new(big.Int), 1000000, nil, input), signer, testBankKey)
+		block.AddTx(deploy)
+		block.AddTx(register)
+	})
+	if _, err := bc.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert oracle block: %v", err)
+	}
+	statedb, _ := bc.State()
+	if stored, _ := checkpointoracle.ReadCheckpoint(func(slot common.Hash) common.Hash { return statedb.GetState(oracle, slot) }); stored == nil || *stored != *checkpoint {
+		t.Fatalf("checkpoint not registered: have %v, want %v", stored, checkpoint)
+	}
+	return oracle
+}
+
+// syncOracleClient creates a server registering the first client section in a
+// checkpoint oracle, and a fresh light client configured with the oracle, which
+// trusts its admin only if requested. The client is connected to the server and
+// waited on until it synced to the head of the server.
+func syncOracleClient(t *testing.T, trusted bool) (*TestEntity, *TestEntity, *checkpointoracle.Checkpoint, *checkpointOracle, func()) {
+	config := light.TestServerIndexerConfig
+
+	waitIndexers := func(cIndexer, bIndexer, btIndexer *core.ChainIndexer) {
+		for {
+			cs, _, _ := cIndexer.Sections()
+			bts, _, _ := btIndexer.Sections()
+			if cs >= config.PairChtSize/config.ChtSize && bts >= 1 {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	server, client, tearDown := newClientServerEnv(t, int(config.PairChtSize+config.ChtConfirms), lpv2, waitIndexers, false)
+
+	// Register the first client section, as processed by the server, in an oracle
+	bc := server.pm.blockchain.(*core.BlockChain)
+	head := bc.GetHeaderByNumber(config.PairChtSize - 1).Hash()
+	checkpoint := &checkpointoracle.Checkpoint{
+		SectionIndex: 0,
+		SectionHead:  head,
+		CHTRoot:      light.GetChtRoot(server.db, config.PairChtSize/config.ChtSize-1, head),
+		BloomRoot:    light.GetBloomTrieRoot(server.db, 0, head),
+	}
+	admin, _ := crypto.GenerateKey()
+	address := registerCheckpoint(t, server, checkpoint, admin)
+
+	signer := common.Address{0x01}
+	if trusted {
+		signer = crypto.PubkeyToAddress(admin.PublicKey)
+	}
+	lc := client.pm.blockchain.(*light.LightChain)
+	client.pm.oracle = newCheckpointOracle(&params.CheckpointOracleConfig{Address: address, Signers: []common.Address{signer}, Threshold: 1}, lc)
+
+	// Connect the client, which starts syncing right away
+	peer, err1, lPeer, err2 := newTestPeerPair("peer", lpv2, server.pm, client.pm)
+	select {
+	case <-time.After(time.Millisecond * 100):
+	case err := <-err1:
+		t.Fatalf("peer 1 handshake error: %v", err)
+	case err := <-err2:
+		t.Fatalf("peer 2 handshake error: %v", err)
+	}
+	client.peers.lock.Lock()
+	lPeer.hasBlock = func(common.Hash, uint64) bool { return true }
+	client.peers.lock.Unlock()
+	server.rPeer, client.rPeer = peer, lPeer
+
+	for deadline := time.Now().Add(10 * time.Second); lc.CurrentHeader().Hash() != bc.CurrentHeader().Hash(); {
+		if time.Now().After(deadline) {
+			t.Fatalf("client not synced: have #%d, want #%d", lc.CurrentHeader().Number, bc.CurrentHeader().Number)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return server, client, checkpoint, client.pm.oracle, tearDown
+}
+
+// Tests that a fresh light client queries the oracle before syncing, and starts
+// from the oracle checkpoint instead of downloading the covered headers.
+func TestCheckpointOracleSync(t *testing.T) {
+	_, client, checkpoint, oracle, tearDown := syncOracleClient(t, true)
+	defer tearDown()
+
+	if sections, _, head := client.chtIndexer.Sections(); sections != 1 || head != checkpoint.SectionHead {
+		t.Fatalf("oracle checkpoint not added: have %d sections, head %x", sections, head)
+	}
+	if oracle.latest != 1 {
+		t.Fatalf("oracle checkpoint not marked as processed: latest %d", oracle.latest)
+	}
+	// The headers covered by the checkpoint were never downloaded
+	lc := client.pm.blockchain.(*light.LightChain)
+	if header := lc.GetHeaderByNumber(1); header != nil {
+		t.Fatalf("header #1 downloaded despite the oracle checkpoint")
+	}
+}
+
+// Tests that checkpoints not approved by the trusted signers are ignored, and
+// the light client syncs from its own headers instead.
+func TestCheckpointOracleUntrusted(t *testing.T) {
+	_, client, _, oracle, tearDown := syncOracleClient(t, false)
+	defer tearDown()
+
+	if oracle.latest != 0 {
+		t.Fatalf("rejected checkpoint marked as processed: latest %d", oracle.latest)
+	}
+	lc := client.pm.blockchain.(*light.LightChain)
+	if header := lc.GetHeaderByNumber(1); header == nil {
+		t.Fatalf("header #1 not downloaded without a trusted checkpoint")
+	}
+}
@@ -407,7 +407,14 @@ func (f *lightFetcher) nextRequest() (*distReq, uint64) {
 	bestSyncing := false
 
 	for p, fp := range f.peers {
+		// Ultra-light clients only follow heads confirmed by their trusted servers
+		if f.pm.ulc != nil && !p.trusted {
+			continue
+		}
 		for hash, n := range fp.nodeByHash {
+			if f.pm.ulc != nil && !f.trustedConfirmed(hash) {
+				continue
+			}
 			if !f.checkKnownNode(p, n) && !n.requested && (bestTd == nil || n.td.Cmp(bestTd) >= 0) {
 				amount := f.requestAmount(p, n)
 				if bestTd == nil || n.td.Cmp(bestTd) > 0 || amount < bestAmount {
@@ -438,7 +445,7 @@ func (f *lightFetcher) nextRequest() (*distReq, uint64) {
 				defer f.lock.Unlock()
 
 				fp := f.peers[p]
-				return fp != nil && fp.nodeByHash[bestHash] != nil
+				return fp != nil && fp.nodeByHash[bestHash] != nil && (f.pm.ulc == nil || p.trusted)
 			},
 			request: func(dp distPeer) func() {
 				go func() {
@@ -462,7 +469,7 @@ func (f *lightFetcher) nextRequest() (*distReq, uint64) {
 				defer f.lock.Unlock()
 
 				fp := f.peers[p]
-				if fp == nil {
+				if fp == nil || (f.pm.ulc != nil && !p.trusted) {
 					return false
 				}
 				n := fp.nodeByHash[bestHash]
@@ -496,6 +503,19 @@ func (f *lightFetcher) nextRequest() (*distReq, uint64) {
 	return rq, reqID
 }
 
+// trustedConfirmed returns whether the given head has been announced by a large
+// enough fraction of the trusted servers for an ultra-light client to accept it.
+// The caller must hold the fetcher lock.
+func (f *lightFetcher) trustedConfirmed(hash common.Hash) bool {
+	agreed := 0
+	for p, fp := range f.peers {
+		if p.trusted && fp.nodeByHash[hash] != nil {
+			agreed++
+		}
+	}
+	return f.pm.ulc.confirmed(agreed)
+}
+
 // deliverHeaders delivers header download request responses for processing
 func (f *lightFetcher) deliverHeaders(peer *peer, reqID uint64, headers []*types.Header) {
 	f.deliverChn <- fetchResponse{reqID: reqID, headers: headers, peer: peer}
@@ -511,7 +531,13 @@ func (f *lightFetcher) processResponse(req fetchRequest, resp fetchResponse) boo
 	for i, header := range resp.headers {
 		headers[int(req.amount)-1-i] = header
 	}
-	if _, err := f.chain.InsertHeaderChain(headers, 1); err != nil {
+	// Headers of ultra-light clients are confirmed by the trusted servers, skip
+	// verifying their seals
+	checkFreq := 1
+	if f.pm.ulc != nil {
+		checkFreq = 0
+	}
+	if _, err := f.chain.InsertHeaderChain(headers, checkFreq); err != nil {
 		if err == consensus.ErrFutureBlock {
 			return true
 		}
@@ -50,6 +50,31 @@ func NewClientNode(cm *ClientManager, params *ServerParams) *ClientNode {
 	return node
 }
 
+// NewClientNodeWithBuffer creates a client node whose buffer starts at the given
+// value instead of the buffer limit, restoring the state of a reconnecting client.
+func NewClientNodeWithBuffer(cm *ClientManager, params *ServerParams, bufValue uint64) *ClientNode {
+	if bufValue > params.BufLimit {
+		bufValue = params.BufLimit
+	}
+	node := &ClientNode{
+		cm:       cm,
+		params:   params,
+		bufValue: bufValue,
+		lastTime: mclock.Now(),
+	}
+	node.cmNode = cm.addNode(node)
+	return node
+}
+
+// BufferValue returns the current buffer value of the client, recharged up to now.
+func (peer *ClientNode) BufferValue() uint64 {
+	peer.lock.Lock()
+	defer peer.lock.Unlock()
+
+	peer.recalcBV(mclock.Now())
+	return peer.bufValue
+}
+
 func (peer *ClientNode) Remove(cm *ClientManager) {
 	cm.removeNode(peer.cmNode)
 }
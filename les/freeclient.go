@@ -55,12 +55,14 @@ type freeClientPool struct {
 	connPool, disconnPool *prque.Prque
 	startupTime           mclock.AbsTime
 	logOffsetAtStartup    int64
+	lastSave              mclock.AbsTime
 }
 
 const (
 	recentUsageExpTC     = time.Hour   // time constant of the exponential weighting window for "recent" server usage
 	fixedPointMultiplier = 0x1000000   // constant to convert logarithms to fixed point format
 	connectedBias        = time.Minute // this bias is applied in favor of already connected clients in order to avoid kicking them out very soon
+	freeClientSaveCycle  = time.Minute // minimum time between saving the pool status to the database during operation
 )
 
 // newFreeClientPool creates a new free client pool
@@ -110,8 +112,12 @@ func (f *freeClientPool) connect(address string, disconnectFn func()) bool {
 		recentUsage = int64(math.Exp(float64(e.logUsage-f.logOffset(now)) / fixedPointMultiplier))
 	}
 	e.linUsage = recentUsage - int64(now)
+	if f.connectedLimit <= 0 {
+		log.Debug("Client rejected, no free capacity", "address", address)
+		return false
+	}
 	// check whether (linUsage+connectedBias) is smaller than the highest entry in the connected pool
-	if f.connPool.Size() == f.connectedLimit {
+	if f.connPool.Size() >= f.connectedLimit {
 		i := f.connPool.PopItem().(*freeClientPoolEntry)
 		if e.linUsage+int64(connectedBias)-i.linUsage < 0 {
 			// kick it out and accept the new client
@@ -161,6 +167,34 @@ func (f *freeClientPool) disconnect(address string) {
 	e.connected = false
 	f.disconnPool.Push(e, -e.logUsage)
 	log.Debug("Client disconnected", "address", address)
+
+	// Save the pool status occasionally so that a crash doesn't lose all usage history
+	if time.Duration(now-f.lastSave) >= freeClientSaveCycle {
+		f.saveToDb()
+	}
+}
+
+// setConnectedLimit updates the maximum number of simultaneously connected free
+// clients, kicking out the ones with the highest recent usage if necessary. It is
+// used to shrink (or grow) the free client capacity as priority clients come and go.
+func (f *freeClientPool) setConnectedLimit(limit int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.connectedLimit = limit
+	if f.closed {
+		return
+	}
+	now := f.clock.Now()
+	for f.connPool.Size() > limit {
+		// Kick out the client with the highest recent usage
+		i := f.connPool.PopItem().(*freeClientPoolEntry)
+		f.calcLogUsage(i, now)
+		i.connected = false
+		f.disconnPool.Push(i, -i.logUsage)
+		log.Debug("Client kicked out", "address", i.address)
+		i.disconnectFn()
+	}
 }
 
 // logOffset calculates the time-dependent offset for the logarithmic
@@ -203,6 +237,7 @@ func (f *freeClientPool) loadFromDb() {
 	}
 	f.logOffsetAtStartup = int64(storage.LogOffset)
 	f.startupTime = f.clock.Now()
+	f.lastSave = f.startupTime
 	for _, e := range storage.List {
 		log.Debug("Loaded free client record", "address", e.address, "logUsage", e.logUsage)
 		f.addressMap[e.address] = e
@@ -211,9 +246,10 @@ func (f *freeClientPool) loadFromDb() {
 }
 
 // saveToDb saves pool status to the database storage
-// (automatically called during shutdown)
+// (automatically called during shutdown and periodically on disconnections)
 func (f *freeClientPool) saveToDb() {
 	now := f.clock.Now()
+	f.lastSave = now
 	storage := freeClientPoolStorage{
 		LogOffset: uint64(f.logOffset(now)),
 		List:      make([]*freeClientPoolEntry, len(f.addressMap)),
@@ -101,6 +101,10 @@ type ProtocolManager struct {
 	server      *LesServer
 	serverPool  *serverPool
 	clientPool  *freeClientPool
+	prioPool    *priorityClientPool
+	poolLock    sync.RWMutex // Protects the client pools created when the server starts
+	oracle      *checkpointOracle
+	ulc         *ulc
 	lesTopic    discv5.Topic
 	reqDist     *requestDistributor
 	retriever   *retrieveManager
@@ -155,7 +159,7 @@ func NewProtocolManager(chainConfig *params.ChainConfig, indexerConfig *light.In
 	}
 
 	if lightSync {
-		manager.downloader = downloader.New(downloader.LightSync, chainDb, manager.eventMux, nil, blockchain, removePeer)
+		manager.downloader = downloader.New(nil, downloader.LightSync, chainDb, manager.eventMux, nil, blockchain, removePeer)
 		manager.peers.notify((*downloaderPeerNotify)(manager))
 		manager.fetcher = newLightFetcher(manager)
 	}
@@ -163,6 +167,15 @@ func NewProtocolManager(chainConfig *params.ChainConfig, indexerConfig *light.In
 	return manager, nil
 }
 
+// priorityPool returns the priority client pool of the server, nil if the server
+// is not running.
+func (pm *ProtocolManager) priorityPool() *priorityClientPool {
+	pm.poolLock.RLock()
+	defer pm.poolLock.RUnlock()
+
+	return pm.prioPool
+}
+
 // removePeer initiates disconnection from a peer by removing it from the peer set
 func (pm *ProtocolManager) removePeer(id string) {
 	pm.peers.Unregister(id)
@@ -174,7 +187,14 @@ func (pm *ProtocolManager) Start(maxPeers int) {
 	if pm.lightSync {
 		go pm.syncer()
 	} else {
+		pm.poolLock.Lock()
 		pm.clientPool = newFreeClientPool(pm.chainDb, maxPeers, 10000, mclock.System{})
+		if pm.server != nil {
+			// Free clients share whatever capacity is not assigned to priority clients
+			freeCap := pm.server.defParams.MinRecharge
+			pm.prioPool = newPriorityClientPool(pm.chainDb, uint64(maxPeers)*freeCap, freeCap, pm.clientPool, mclock.System{})
+		}
+		pm.poolLock.Unlock()
 		go func() {
 			for range pm.newPeerCh {
 			}
@@ -193,6 +213,9 @@ func (pm *ProtocolManager) Stop() {
 
 	close(pm.quitSync) // quits syncer, fetcher
 	if pm.clientPool != nil {
+		if pm.prioPool != nil {
+			pm.prioPool.stop()
+		}
 		pm.clientPool.stop()
 	}
 
@@ -235,7 +258,9 @@ func (pm *ProtocolManager) runPeer(version uint, p *p2p.Peer, rw p2p.MsgReadWrit
 }
 
 func (pm *ProtocolManager) newPeer(pv int, nv uint64, p *p2p.Peer, rw p2p.MsgReadWriter) *peer {
-	return newPeer(pv, nv, p, newMeteredMsgWriter(rw))
+	peer := newPeer(pv, nv, p, newMeteredMsgWriter(rw))
+	peer.trusted = pm.ulc != nil && pm.ulc.isTrusted(p.ID())
+	return peer
 }
 
 // handle is the callback invoked to manage the life cycle of a les peer. When
@@ -249,6 +274,13 @@ func (pm *ProtocolManager) handle(p *peer) error {
 
 	p.Log().Debug("Light Rwdxchain peer connected", "name", p.Name())
 
+	// Serve clients with an assigned capacity and balance from the priority pool
+	if prioPool := pm.priorityPool(); prioPool != nil {
+		if params, bufValue, ok := prioPool.connect(p.ID(), func() { go pm.removePeer(p.id) }); ok {
+			p.fcClientParams, p.fcClientBuf, p.priority = params, bufValue, true
+			defer prioPool.disconnect(p.ID())
+		}
+	}
 	// Execute the LES handshake
 	var (
 		genesis = pm.blockchain.Genesis()
@@ -261,8 +293,11 @@ func (pm *ProtocolManager) handle(p *peer) error {
 		p.Log().Debug("Light Ethereum handshake failed", "err", err)
 		return err
 	}
+	if p.priority {
+		pm.priorityPool().track(p.ID(), p.fcClient)
+	}
 
-	if !pm.lightSync && !p.Peer.Info().Network.Trusted {
+	if !pm.lightSync && !p.priority && !p.Peer.Info().Network.Trusted {
 		addr, ok := p.RemoteAddr().(*net.TCPAddr)
 		// test peer address is not a tcp address, don't use client pool if can not typecast
 		if ok {
@@ -327,6 +362,14 @@ func (pm *ProtocolManager) handle(p *peer) error {
 
 var reqList = []uint64{GetBlockHeadersMsg, GetBlockBodiesMsg, GetCodeMsg, GetReceiptsMsg, GetProofsV1Msg, SendTxMsg, SendTxV2Msg, GetTxStatusMsg, GetHeaderProofsMsg, GetProofsV2Msg, GetHelperTrieProofsMsg}
 
+// chargeClient deducts the real cost of a served request from the balance of
+// a priority client.
+func (pm *ProtocolManager) chargeClient(p *peer, cost uint64) {
+	if p.priority {
+		pm.prioPool.charge(p.ID(), cost)
+	}
+}
+
 // handleMsg is invoked whenever an inbound message is received from a remote
 // peer. The remote connection is torn down upon returning any error.
 func (pm *ProtocolManager) handleMsg(p *peer) error {
@@ -344,11 +387,11 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		}
 		bufValue, _ := p.fcClient.AcceptRequest()
 		cost := costs.baseCost + reqCnt*costs.reqCost
-		if cost > pm.server.defParams.BufLimit {
-			cost = pm.server.defParams.BufLimit
+		if cost > p.fcClientParams.BufLimit {
+			cost = p.fcClientParams.BufLimit
 		}
 		if cost > bufValue {
-			recharge := time.Duration((cost - bufValue) * 1000000 / pm.server.defParams.MinRecharge)
+			recharge := time.Duration((cost - bufValue) * 1000000 / p.fcClientParams.MinRecharge)
 			p.Log().Error("Request came too early", "recharge", common.PrettyDuration(recharge))
 			return true
 		}
@@ -492,6 +535,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 
 		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + query.Amount*costs.reqCost)
 		pm.server.fcCostStats.update(msg.Code, query.Amount, rcost)
+		pm.chargeClient(p, rcost)
 		return p.SendBlockHeaders(req.ReqID, bv, headers)
 
 	case BlockHeadersMsg:
@@ -511,6 +555,12 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		p.fcServer.GotReply(resp.ReqID, resp.BV)
 		if pm.fetcher != nil && pm.fetcher.requestedID(resp.ReqID) {
 			pm.fetcher.deliverHeaders(p, resp.ReqID, resp.Headers)
+		} else if pm.retriever != nil && pm.retriever.requested(resp.ReqID) {
+			deliverMsg = &Msg{
+				MsgType: MsgBlockHeaders,
+				ReqID:   resp.ReqID,
+				Obj:     resp.Headers,
+			}
 		} else {
 			err := pm.downloader.DeliverHeaders(p.id, resp.Headers)
 			if err != nil {
@@ -551,6 +601,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		}
 		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + uint64(reqCnt)*costs.reqCost)
 		pm.server.fcCostStats.update(msg.Code, uint64(reqCnt), rcost)
+		pm.chargeClient(p, rcost)
 		return p.SendBlockBodiesRLP(req.ReqID, bv, bodies)
 
 	case BlockBodiesMsg:
@@ -616,6 +667,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		}
 		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + uint64(reqCnt)*costs.reqCost)
 		pm.server.fcCostStats.update(msg.Code, uint64(reqCnt), rcost)
+		pm.chargeClient(p, rcost)
 		return p.SendCode(req.ReqID, bv, data)
 
 	case CodeMsg:
@@ -682,6 +734,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		}
 		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + uint64(reqCnt)*costs.reqCost)
 		pm.server.fcCostStats.update(msg.Code, uint64(reqCnt), rcost)
+		pm.chargeClient(p, rcost)
 		return p.SendReceiptsRLP(req.ReqID, bv, receipts)
 
 	case ReceiptsMsg:
@@ -756,6 +809,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		}
 		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + uint64(reqCnt)*costs.reqCost)
 		pm.server.fcCostStats.update(msg.Code, uint64(reqCnt), rcost)
+		pm.chargeClient(p, rcost)
 		return p.SendProofs(req.ReqID, bv, proofs)
 
 	case GetProofsV2Msg:
@@ -818,6 +872,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		}
 		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + uint64(reqCnt)*costs.reqCost)
 		pm.server.fcCostStats.update(msg.Code, uint64(reqCnt), rcost)
+		pm.chargeClient(p, rcost)
 		return p.SendProofsV2(req.ReqID, bv, nodes.NodeList())
 
 	case ProofsV1Msg:
@@ -905,6 +960,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		}
 		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + uint64(reqCnt)*costs.reqCost)
 		pm.server.fcCostStats.update(msg.Code, uint64(reqCnt), rcost)
+		pm.chargeClient(p, rcost)
 		return p.SendHeaderProofs(req.ReqID, bv, proofs)
 
 	case GetHelperTrieProofsMsg:
@@ -966,6 +1022,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		}
 		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + uint64(reqCnt)*costs.reqCost)
 		pm.server.fcCostStats.update(msg.Code, uint64(reqCnt), rcost)
+		pm.chargeClient(p, rcost)
 		return p.SendHelperTrieProofs(req.ReqID, bv, HelperTrieResps{Proofs: nodes.NodeList(), AuxData: auxData})
 
 	case HeaderProofsMsg:
@@ -1026,6 +1083,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 
 		_, rcost := p.fcClient.RequestProcessed(costs.baseCost + uint64(reqCnt)*costs.reqCost)
 		pm.server.fcCostStats.update(msg.Code, uint64(reqCnt), rcost)
+		pm.chargeClient(p, rcost)
 
 	case SendTxV2Msg:
 		if pm.txpool == nil {
@@ -1061,6 +1119,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 
 		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + uint64(reqCnt)*costs.reqCost)
 		pm.server.fcCostStats.update(msg.Code, uint64(reqCnt), rcost)
+		pm.chargeClient(p, rcost)
 
 		return p.SendTxStatus(req.ReqID, bv, stats)
 
@@ -1082,6 +1141,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		}
 		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + uint64(reqCnt)*costs.reqCost)
 		pm.server.fcCostStats.update(msg.Code, uint64(reqCnt), rcost)
+		pm.chargeClient(p, rcost)
 
 		return p.SendTxStatus(req.ReqID, bv, pm.txStatus(req.Hashes))
 
@@ -1109,6 +1169,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 	if deliverMsg != nil {
 		err := pm.retriever.deliver(p, deliverMsg)
 		if err != nil {
+			p.AdjustScore(p2p.ScoreUseless)
 			p.responseErrors++
 			if p.responseErrors > maxResponseErrors {
 				return err
@@ -62,6 +62,9 @@ var (
 	miscInTrafficMeter  = metrics.NewRegisteredMeter("les/misc/in/traffic", nil)
 	miscOutPacketsMeter = metrics.NewRegisteredMeter("les/misc/out/packets", nil)
 	miscOutTrafficMeter = metrics.NewRegisteredMeter("les/misc/out/traffic", nil)
+
+	totalCapacityGauge    = metrics.NewRegisteredGauge("les/server/capacity/total", nil)
+	priorityCapacityGauge = metrics.NewRegisteredGauge("les/server/capacity/priority", nil)
 )
 
 // meteredMsgReadWriter is a wrapper around a p2p.MsgReadWriter, capable of
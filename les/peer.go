@@ -59,6 +59,7 @@ type peer struct {
 	network uint64 // Network ID being on
 
 	announceType, requestAnnounceType uint64
+	trusted                           bool // Whether the peer is a trusted server of an ultra-light client
 
 	id string
 
@@ -72,8 +73,11 @@ type peer struct {
 	hasBlock       func(common.Hash, uint64) bool
 	responseErrors int
 
-	fcClient       *flowcontrol.ClientNode // nil if the peer is server only
-	fcServer       *flowcontrol.ServerNode // nil if the peer is client only
+	fcClient       *flowcontrol.ClientNode   // nil if the peer is server only
+	fcClientParams *flowcontrol.ServerParams // flow control parameters assigned to the client (nil if the peer is server only)
+	priority       bool                      // whether the client is served from the priority pool
+	fcClientBuf    uint64                    // initial flow control buffer value of a priority client
+	fcServer       *flowcontrol.ServerNode   // nil if the peer is client only
 	fcServerParams *flowcontrol.ServerParams
 	fcCosts        requestCostTable
 }
@@ -409,13 +413,21 @@ func (p *peer) Handshake(td *big.Int, head common.Hash, headNum uint64, genesis
 		send = send.add("serveChainSince", uint64(0))
 		send = send.add("serveStateSince", uint64(0))
 		send = send.add("txRelay", nil)
-		send = send.add("flowControl/BL", server.defParams.BufLimit)
-		send = send.add("flowControl/MRR", server.defParams.MinRecharge)
+		if p.fcClientParams == nil {
+			p.fcClientParams = server.defParams
+		}
+		send = send.add("flowControl/BL", p.fcClientParams.BufLimit)
+		send = send.add("flowControl/MRR", p.fcClientParams.MinRecharge)
 		list := server.fcCostStats.getCurrentList()
 		send = send.add("flowControl/MRC", list)
 		p.fcCosts = list.decode()
 	} else {
-		p.requestAnnounceType = announceTypeSimple // set to default until "very light" client mode is implemented
+		// Ultra-light clients accept heads from trusted servers without verification,
+		// so request those to sign their announcements
+		p.requestAnnounceType = announceTypeSimple
+		if p.trusted {
+			p.requestAnnounceType = announceTypeSigned
+		}
 		send = send.add("announceType", p.requestAnnounceType)
 	}
 	recvList, err := p.sendReceiveHandshake(send)
@@ -464,7 +476,11 @@ func (p *peer) Handshake(td *big.Int, head common.Hash, headNum uint64, genesis
 		if recv.get("announceType", &p.announceType) != nil {
 			p.announceType = announceTypeSimple
 		}
-		p.fcClient = flowcontrol.NewClientNode(server.fcManager, server.defParams)
+		if p.priority {
+			p.fcClient = flowcontrol.NewClientNodeWithBuffer(server.fcManager, p.fcClientParams, p.fcClientBuf)
+		} else {
+			p.fcClient = flowcontrol.NewClientNode(server.fcManager, p.fcClientParams)
+		}
 	} else {
 		if recv.get("serveChainSince", nil) != nil {
 			return errResp(ErrUselessPeer, "peer cannot serve chain")
@@ -0,0 +1,376 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/common/mclock"
+	"github.com/rwdxchain/go-rwdxchaina/ethdb"
+	"github.com/rwdxchain/go-rwdxchaina/les/flowcontrol"
+	"github.com/rwdxchain/go-rwdxchaina/log"
+	"github.com/rwdxchain/go-rwdxchaina/metrics"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/rlp"
+)
+
+const (
+	priorityClientSaveCycle = time.Minute // minimum time between saving the balances of connected clients
+	bufLimitRatio           = 6000        // fixed ratio of the buffer limit and the minimum recharge rate of clients
+)
+
+var (
+	errNoPriorityCapacity = errors.New("not enough unassigned capacity")
+	errPoolClosed         = errors.New("client pool closed")
+)
+
+// priorityClientPool manages the clients that have been assigned a dedicated
+// serving capacity (paid tier) by the server operator. Priority clients are
+// identified by their node ID and have a token balance which is charged by the
+// real cost of every request served to them. When the balance runs out, the
+// client is disconnected and can only connect again as a free client.
+//
+// The records of priority clients are persisted in the database, so balances,
+// capacities and the drained flow control buffers survive restarts: reconnecting
+// doesn't give a client a full buffer it has not recharged yet. The capacity not taken by connected priority
+// clients is shared by the free clients, managed by a freeClientPool.
+type priorityClientPool struct {
+	db     ethdb.Database
+	lock   sync.Mutex
+	clock  mclock.Clock
+	closed bool
+
+	lastSave      mclock.AbsTime
+	totalCap      uint64 // Total serving capacity of the server
+	freeClientCap uint64 // Capacity assigned to a single free client
+	connectedCap  uint64 // Sum capacity of the connected priority clients
+
+	clients  map[discover.NodeID]*priorityClientInfo
+	freePool *freeClientPool // Free client pool sharing the unassigned capacity (nil if none)
+}
+
+// priorityClientInfo represents a client known by the priority pool.
+type priorityClientInfo struct {
+	id           discover.NodeID
+	balance      uint64 // Remaining token balance of the client
+	capacity     uint64 // Assigned capacity, in units of minimum recharge rate
+	connected    bool
+	disconnectFn func()
+
+	fcClient   *flowcontrol.ClientNode // Flow control state of the connected client, nil before the handshake
+	bufDeficit uint64                  // Flow control buffer the client used up and not recharged yet
+	bufTime    mclock.AbsTime          // Time the buffer deficit was last updated
+
+	usageMeter    metrics.Meter // Meter of the request costs served to the client
+	capacityGauge metrics.Gauge // Gauge of the capacity assigned to the client
+}
+
+// priorityClientRecord is the RLP representation of a priority client in the database.
+type priorityClientRecord struct {
+	ID         discover.NodeID
+	Balance    uint64
+	Capacity   uint64
+	BufDeficit uint64
+}
+
+// newPriorityClientPool creates a new priority client pool with the given total
+// capacity. Free clients get a fixed capacity of freeClientCap each.
+func newPriorityClientPool(db ethdb.Database, totalCap, freeClientCap uint64, freePool *freeClientPool, clock mclock.Clock) *priorityClientPool {
+	pool := &priorityClientPool{
+		db:            db,
+		clock:         clock,
+		totalCap:      totalCap,
+		freeClientCap: freeClientCap,
+		clients:       make(map[discover.NodeID]*priorityClientInfo),
+		freePool:      freePool,
+	}
+	pool.loadFromDb()
+	totalCapacityGauge.Update(int64(totalCap))
+	pool.updateFreePool()
+	return pool
+}
+
+// stop saves the balances of all connected clients and rejects further connections.
+func (p *priorityClientPool) stop() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.saveToDb()
+	p.closed = true
+}
+
+// connect is called before the handshake with a client. If the client has been
+// assigned a capacity, has a positive balance and the capacity is available, it
+// returns the flow control parameters to serve the client with, along with the
+// initial value of its flow control buffer. Otherwise the client should be handled
+// as a free client.
+//
+// Note: the disconnectFn callback should not block.
+func (p *priorityClientPool) connect(id discover.NodeID, disconnectFn func()) (*flowcontrol.ServerParams, uint64, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	c := p.clients[id]
+	if p.closed || c == nil || c.connected || c.capacity == 0 || c.balance == 0 {
+		return nil, 0, false
+	}
+	if p.connectedCap+c.capacity > p.totalCap {
+		log.Debug("Priority client rejected, capacity exhausted", "id", id, "capacity", c.capacity)
+		return nil, 0, false
+	}
+	c.connected = true
+	c.disconnectFn = disconnectFn
+	p.connectedCap += c.capacity
+
+	name := fmt.Sprintf("les/server/clients/%x", id[:8])
+	c.usageMeter = metrics.NewRegisteredMeter(name+"/usage", nil)
+	c.capacityGauge = metrics.NewRegisteredGauge(name+"/capacity", nil)
+	c.capacityGauge.Update(int64(c.capacity))
+	p.updateFreePool()
+
+	// Recharge the buffer for the time the client was away. The minimum recharge
+	// rate is the capacity per millisecond.
+	bufLimit := c.capacity * bufLimitRatio
+	if recharged := c.capacity * uint64(time.Duration(p.clock.Now()-c.bufTime)/time.Millisecond); recharged < c.bufDeficit {
+		c.bufDeficit -= recharged
+	} else {
+		c.bufDeficit = 0
+	}
+	if c.bufDeficit > bufLimit {
+		c.bufDeficit = bufLimit
+	}
+	log.Debug("Priority client connected", "id", id, "capacity", c.capacity, "balance", c.balance, "buffer", bufLimit-c.bufDeficit)
+	return &flowcontrol.ServerParams{BufLimit: bufLimit, MinRecharge: c.capacity}, bufLimit - c.bufDeficit, true
+}
+
+// track registers the flow control state of a connected client after the handshake,
+// so that its buffer is saved along with its balance.
+func (p *priorityClientPool) track(id discover.NodeID, fcClient *flowcontrol.ClientNode) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if c := p.clients[id]; c != nil && c.connected {
+		c.fcClient = fcClient
+	}
+}
+
+// disconnect should be called when the connection of a priority client is terminated.
+func (p *priorityClientPool) disconnect(id discover.NodeID) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	c := p.clients[id]
+	if c == nil || !c.connected {
+		return
+	}
+	p.release(c)
+	if !p.closed {
+		p.saveToDb()
+	}
+	log.Debug("Priority client disconnected", "id", id, "balance", c.balance)
+}
+
+// release frees up the capacity of a connected client, keeping the state of its
+// flow control buffer. The caller must hold the lock.
+func (p *priorityClientPool) release(c *priorityClientInfo) {
+	c.bufDeficit, c.bufTime = p.deficit(c), p.clock.Now()
+	c.fcClient = nil
+	c.connected = false
+	p.connectedCap -= c.capacity
+
+	name := fmt.Sprintf("les/server/clients/%x", c.id[:8])
+	metrics.DefaultRegistry.Unregister(name + "/usage")
+	metrics.DefaultRegistry.Unregister(name + "/capacity")
+	p.updateFreePool()
+}
+
+// charge deducts the real cost of a served request from the balance of a
+// connected priority client, disconnecting it if the balance runs out.
+func (p *priorityClientPool) charge(id discover.NodeID, cost uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	c := p.clients[id]
+	if c == nil || !c.connected {
+		return
+	}
+	c.usageMeter.Mark(int64(cost))
+	if cost < c.balance {
+		c.balance -= cost
+		if now := p.clock.Now(); time.Duration(now-p.lastSave) >= priorityClientSaveCycle {
+			p.saveToDb()
+		}
+		return
+	}
+	c.balance = 0
+	log.Debug("Priority client balance depleted", "id", id)
+	p.release(c)
+	p.saveToDb()
+	c.disconnectFn()
+}
+
+// addBalance adds the given amount to the balance of a client, creating its record
+// if it's unknown. Negative amounts are deducted from the balance, down to zero.
+func (p *priorityClientPool) addBalance(id discover.NodeID, amount int64) (uint64, uint64, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.closed {
+		return 0, 0, errPoolClosed
+	}
+	c := p.client(id)
+	old := c.balance
+	switch {
+	case amount >= 0:
+		c.balance += uint64(amount)
+	case uint64(-amount) < c.balance:
+		c.balance -= uint64(-amount)
+	default:
+		c.balance = 0
+	}
+	p.saveToDb()
+	if c.balance == 0 && c.connected {
+		p.release(c)
+		c.disconnectFn()
+	}
+	return old, c.balance, nil
+}
+
+// setCapacity assigns a capacity to a client, creating its record if it's unknown.
+// A zero capacity revokes the priority status of the client. As the flow control
+// parameters are fixed for the lifetime of a connection, connected clients are
+// disconnected to reconnect with their new capacity.
+func (p *priorityClientPool) setCapacity(id discover.NodeID, capacity uint64) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.closed {
+		return errPoolClosed
+	}
+	c := p.client(id)
+	if c.connected {
+		if p.connectedCap-c.capacity+capacity > p.totalCap {
+			return errNoPriorityCapacity
+		}
+		p.release(c)
+		c.disconnectFn()
+	} else if capacity > p.totalCap {
+		return errNoPriorityCapacity
+	}
+	c.capacity = capacity
+	p.saveToDb()
+	return nil
+}
+
+// deficit returns the part of the flow control buffer a client used up and did not
+// recharge yet. The caller must hold the lock.
+func (p *priorityClientPool) deficit(c *priorityClientInfo) uint64 {
+	if c.fcClient == nil {
+		return c.bufDeficit
+	}
+	if bufLimit, bufValue := c.capacity*bufLimitRatio, c.fcClient.BufferValue(); bufValue < bufLimit {
+		return bufLimit - bufValue
+	}
+	return 0
+}
+
+// client retrieves the record of a client, creating an empty one if it's unknown.
+// The caller must hold the lock.
+func (p *priorityClientPool) client(id discover.NodeID) *priorityClientInfo {
+	c := p.clients[id]
+	if c == nil {
+		c = &priorityClientInfo{id: id}
+		p.clients[id] = c
+	}
+	return c
+}
+
+// priorityClientStats is a snapshot of the status of a priority client.
+type priorityClientStats struct {
+	Balance   uint64 `json:"balance"`
+	Capacity  uint64 `json:"capacity"`
+	Connected bool   `json:"connected"`
+}
+
+// stats returns a snapshot of all known priority clients.
+func (p *priorityClientPool) stats() map[discover.NodeID]priorityClientStats {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	stats := make(map[discover.NodeID]priorityClientStats, len(p.clients))
+	for id, c := range p.clients {
+		stats[id] = priorityClientStats{Balance: c.balance, Capacity: c.capacity, Connected: c.connected}
+	}
+	return stats
+}
+
+// updateFreePool recalculates the number of free clients that fit into the capacity
+// not taken by connected priority clients. The caller must hold the lock.
+func (p *priorityClientPool) updateFreePool() {
+	priorityCapacityGauge.Update(int64(p.connectedCap))
+	if p.freePool == nil || p.freeClientCap == 0 {
+		return
+	}
+	p.freePool.setConnectedLimit(int((p.totalCap - p.connectedCap) / p.freeClientCap))
+}
+
+// priorityClientPoolStorage is the RLP representation of the pool's database storage
+type priorityClientPoolStorage struct {
+	List []priorityClientRecord
+}
+
+// loadFromDb restores the records of all priority clients from the database storage
+// (automatically called at initialization)
+func (p *priorityClientPool) loadFromDb() {
+	enc, err := p.db.Get([]byte("priorityClientPool"))
+	if err != nil {
+		return
+	}
+	var storage priorityClientPoolStorage
+	if err := rlp.DecodeBytes(enc, &storage); err != nil {
+		log.Error("Failed to decode priority client list", "err", err)
+		return
+	}
+	for _, r := range storage.List {
+		log.Debug("Loaded priority client record", "id", r.ID, "balance", r.Balance, "capacity", r.Capacity)
+		p.clients[r.ID] = &priorityClientInfo{id: r.ID, balance: r.Balance, capacity: r.Capacity, bufDeficit: r.BufDeficit, bufTime: p.clock.Now()}
+	}
+}
+
+// saveToDb saves the records of all priority clients to the database storage. Clients
+// with neither balance nor capacity are dropped. The caller must hold the lock.
+func (p *priorityClientPool) saveToDb() {
+	p.lastSave = p.clock.Now()
+
+	storage := priorityClientPoolStorage{List: make([]priorityClientRecord, 0, len(p.clients))}
+	for id, c := range p.clients {
+		if c.balance == 0 && c.capacity == 0 && !c.connected {
+			delete(p.clients, id)
+			continue
+		}
+		storage.List = append(storage.List, priorityClientRecord{ID: id, Balance: c.balance, Capacity: c.capacity, BufDeficit: p.deficit(c)})
+	}
+	enc, err := rlp.EncodeToBytes(storage)
+	if err != nil {
+		log.Error("Failed to encode priority client list", "err", err)
+		return
+	}
+	p.db.Put([]byte("priorityClientPool"), enc)
+}
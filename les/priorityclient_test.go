@@ -0,0 +1,150 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/common/mclock"
+	"github.com/rwdxchain/go-rwdxchaina/ethdb"
+	"github.com/rwdxchain/go-rwdxchaina/les/flowcontrol"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+)
+
+// Tests that priority clients take their capacity away from the free clients,
+// are charged for the served requests and get disconnected when out of balance.
+func TestPriorityClientPool(t *testing.T) {
+	var (
+		clock    mclock.Simulated
+		db       = ethdb.NewMemDatabase()
+		freePool = newFreeClientPool(db, 10, 10000, &clock)
+		pool     = newPriorityClientPool(db, 1000, 100, freePool, &clock)
+		client   = discover.NodeID{1}
+		kicked   int
+	)
+	// Unknown clients and clients without a balance are not prioritised
+	if _, _, ok := pool.connect(client, func() {}); ok {
+		t.Fatalf("unknown client accepted as priority client")
+	}
+	if err := pool.setCapacity(client, 300); err != nil {
+		t.Fatalf("failed to set client capacity: %v", err)
+	}
+	if _, _, ok := pool.connect(client, func() {}); ok {
+		t.Fatalf("client without balance accepted as priority client")
+	}
+	if old, new, err := pool.addBalance(client, 1000); err != nil || old != 0 || new != 1000 {
+		t.Fatalf("balance mismatch: have %d->%d (%v), want 0->1000", old, new, err)
+	}
+	params, bufValue, ok := pool.connect(client, func() { kicked++ })
+	if !ok {
+		t.Fatalf("priority client rejected")
+	}
+	if params.MinRecharge != 300 || params.BufLimit != 300*bufLimitRatio || bufValue != params.BufLimit {
+		t.Fatalf("flow control parameters mismatch: have %+v, buffer %d", params, bufValue)
+	}
+	// Free clients can only use the remaining capacity
+	for i := 0; i < 10; i++ {
+		freePool.connect(fmt.Sprintf("free client #%d", i), func() {})
+	}
+	if size := freePool.connPool.Size(); size != 7 {
+		t.Fatalf("connected free client count mismatch: have %d, want %d", size, 7)
+	}
+	// Charge the priority client until it runs out of balance
+	pool.charge(client, 600)
+	if stats := pool.stats()[client]; stats.Balance != 400 || !stats.Connected {
+		t.Fatalf("client stats mismatch after charge: have %+v", stats)
+	}
+	pool.charge(client, 500)
+	if stats := pool.stats()[client]; stats.Balance != 0 || stats.Connected || kicked != 1 {
+		t.Fatalf("client not disconnected when out of balance: have %+v, kicked %d", stats, kicked)
+	}
+	if limit := freePool.connectedLimit; limit != 10 {
+		t.Fatalf("free client limit not restored: have %d, want %d", limit, 10)
+	}
+	// Capacities above the total are rejected
+	if err := pool.setCapacity(discover.NodeID{2}, 1001); err != errNoPriorityCapacity {
+		t.Fatalf("capacity error mismatch: have %v, want %v", err, errNoPriorityCapacity)
+	}
+}
+
+// Tests that the records of priority clients survive a restart.
+func TestPriorityClientPoolPersistence(t *testing.T) {
+	var (
+		clock mclock.Simulated
+		db    = ethdb.NewMemDatabase()
+		pool  = newPriorityClientPool(db, 1000, 100, nil, &clock)
+	)
+	pool.setCapacity(discover.NodeID{1}, 200)
+	pool.addBalance(discover.NodeID{1}, 5000)
+	pool.addBalance(discover.NodeID{2}, 100)
+	pool.addBalance(discover.NodeID{2}, -200)
+
+	if _, _, ok := pool.connect(discover.NodeID{1}, func() {}); !ok {
+		t.Fatalf("priority client rejected")
+	}
+	pool.charge(discover.NodeID{1}, 1000)
+	pool.stop()
+
+	stats := newPriorityClientPool(db, 1000, 100, nil, &clock).stats()
+	if len(stats) != 1 {
+		t.Fatalf("restored client count mismatch: have %d, want %d", len(stats), 1)
+	}
+	if have, want := stats[discover.NodeID{1}], (priorityClientStats{Balance: 4000, Capacity: 200}); have != want {
+		t.Fatalf("restored client mismatch: have %+v, want %+v", have, want)
+	}
+}
+
+// Tests that reconnecting, even to a restarted server, does not refill the flow
+// control buffer of a priority client faster than its recharge rate.
+func TestPriorityClientPoolBufferPersistence(t *testing.T) {
+	var (
+		clock  mclock.Simulated
+		db     = ethdb.NewMemDatabase()
+		pool   = newPriorityClientPool(db, 1000, 100, nil, &clock)
+		client = discover.NodeID{1}
+	)
+	pool.setCapacity(client, 1)
+	pool.addBalance(client, 5000)
+
+	params, _, ok := pool.connect(client, func() {})
+	if !ok {
+		t.Fatalf("priority client rejected")
+	}
+	// Drain the buffer of the client, then disconnect and restart the pool
+	fcClient := flowcontrol.NewClientNodeWithBuffer(flowcontrol.NewClientManager(50, 10, 1000000000), params, 0)
+	pool.track(client, fcClient)
+	pool.disconnect(client)
+	pool.stop()
+
+	pool = newPriorityClientPool(db, 1000, 100, nil, &clock)
+	_, bufValue, ok := pool.connect(client, func() {})
+	if !ok {
+		t.Fatalf("priority client rejected after restart")
+	}
+	if bufValue >= params.BufLimit/2 {
+		t.Fatalf("buffer refilled by reconnecting: have %d, limit %d", bufValue, params.BufLimit)
+	}
+	pool.disconnect(client)
+
+	// The buffer recharges while the client is away
+	clock.Run(time.Duration(params.BufLimit) * time.Millisecond)
+	if _, bufValue, _ = pool.connect(client, func() {}); bufValue != params.BufLimit {
+		t.Fatalf("buffer not recharged: have %d, want %d", bufValue, params.BufLimit)
+	}
+}
@@ -28,6 +28,7 @@ import (
 
 	"github.com/rwdxchain/go-rwdxchaina/common/mclock"
 	"github.com/rwdxchain/go-rwdxchaina/light"
+	"github.com/rwdxchain/go-rwdxchaina/p2p"
 )
 
 var (
@@ -164,6 +165,16 @@ func (rm *retrieveManager) sendReq(reqID uint64, req *distReq, val validatorFunc
 }
 
 // deliver is called by the LES protocol manager to deliver reply messages to waiting requests
+// requested tells if a certain reqID has been sent by the retrieve manager and
+// is still waiting for a valid reply.
+func (rm *retrieveManager) requested(reqID uint64) bool {
+	rm.lock.RLock()
+	defer rm.lock.RUnlock()
+
+	_, ok := rm.sentReqs[reqID]
+	return ok
+}
+
 func (rm *retrieveManager) deliver(peer distPeer, msg *Msg) error {
 	rm.lock.RLock()
 	req, ok := rm.sentReqs[msg.ReqID]
@@ -323,6 +334,9 @@ func (r *sentReq) tryRequest() {
 		}
 		if hrto {
 			pp.Log().Debug("Request timed out hard")
+			if ok {
+				pp.AdjustScore(p2p.ScoreTimeout)
+			}
 			if r.rm.peers != nil {
 				r.rm.peers.Unregister(pp.id)
 			}
@@ -36,6 +36,7 @@ import (
 	"github.com/rwdxchain/go-rwdxchaina/p2p/discv5"
 	"github.com/rwdxchain/go-rwdxchaina/params"
 	"github.com/rwdxchain/go-rwdxchaina/rlp"
+	"github.com/rwdxchain/go-rwdxchaina/rpc"
 )
 
 type LesServer struct {
@@ -107,25 +108,33 @@ func NewLesServer(eth *eth.Ethereum, config *eth.Config) (*LesServer, error) {
 	return srv, nil
 }
 
+// APIs returns the collection of RPC services the light server offers.
+func (s *LesServer) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "les",
+			Version:   "1.0",
+			Service:   NewPrivateLightServerAPI(s),
+			Public:    false,
+		},
+	}
+}
+
 func (s *LesServer) Protocols() []p2p.Protocol {
-	return s.makeProtocols(ServerProtocolVersions)
+	protos := s.makeProtocols(ServerProtocolVersions)
+	// The server is advertised through the V5 discovery, if enabled. The light
+	// client slots are reserved so full nodes can't crowd them out, and capped so
+	// light clients don't take the slots of the full nodes.
+	for i := range protos {
+		protos[i].AdvertiseTopics = s.lesTopics
+		protos[i].MinPeers, protos[i].MaxPeers = s.config.LightPeers, s.config.LightPeers
+	}
+	return protos
 }
 
 // Start starts the LES server
 func (s *LesServer) Start(srvr *p2p.Server) {
 	s.protocolManager.Start(s.config.LightPeers)
-	if srvr.DiscV5 != nil {
-		for _, topic := range s.lesTopics {
-			topic := topic
-			go func() {
-				logger := log.New("topic", topic)
-				logger.Info("Starting topic registration")
-				defer logger.Info("Terminated topic registration")
-
-				srvr.DiscV5.RegisterTopic(topic, s.quitSync)
-			}()
-		}
-	}
 	s.privateKey = srvr.PrivateKey
 	s.protocolManager.blockLoop()
 }
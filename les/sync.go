@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/rwdxchain/go-rwdxchaina/core/rawdb"
+	"github.com/rwdxchain/go-rwdxchaina/core/types"
 	"github.com/rwdxchain/go-rwdxchaina/eth/downloader"
 	"github.com/rwdxchain/go-rwdxchaina/light"
 )
@@ -72,8 +73,64 @@ func (pm *ProtocolManager) synchronise(peer *peer) {
 		return
 	}
 
+	// Pick up any newer checkpoint registered in the checkpoint oracle first, so
+	// a fresh client syncs from it instead of from the genesis or the hardcoded
+	// checkpoint
+	if pm.oracle != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		if head, err := pm.fetchHead(ctx, peer); err != nil {
+			peer.Log().Debug("Failed to retrieve head for checkpoint oracle", "err", err)
+		} else {
+			pm.oracle.update(ctx, head)
+		}
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 	pm.blockchain.(*light.LightChain).SyncCht(ctx)
 	pm.downloader.Synchronise(peer.id, peer.Head(), peer.Td(), downloader.LightSync)
 }
+
+// fetchHead retrieves the header of the head announced by a peer. The header is
+// only checked against the announced hash, so it may only be used to look up data
+// that is authenticated by other means, e.g. the signed oracle checkpoints.
+func (pm *ProtocolManager) fetchHead(ctx context.Context, p *peer) (*types.Header, error) {
+	var (
+		hash   = p.Head()
+		reqID  = genReqID()
+		header *types.Header
+	)
+	rq := &distReq{
+		getCost: func(dp distPeer) uint64 {
+			return dp.(*peer).GetRequestCost(GetBlockHeadersMsg, 1)
+		},
+		canSend: func(dp distPeer) bool {
+			return dp.(*peer) == p
+		},
+		request: func(dp distPeer) func() {
+			peer := dp.(*peer)
+			cost := peer.GetRequestCost(GetBlockHeadersMsg, 1)
+			peer.fcServer.QueueRequest(reqID, cost)
+			return func() { peer.RequestHeadersByHash(reqID, cost, hash, 1, 0, false) }
+		},
+	}
+	validate := func(dp distPeer, msg *Msg) error {
+		if msg.MsgType != MsgBlockHeaders {
+			return errInvalidMessageType
+		}
+		headers := msg.Obj.([]*types.Header)
+		if len(headers) != 1 {
+			return errInvalidEntryCount
+		}
+		if headers[0].Hash() != hash {
+			return errHeaderUnavailable
+		}
+		header = headers[0]
+		return nil
+	}
+	if err := pm.retriever.retrieve(ctx, reqID, rq, validate, pm.quitSync); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"github.com/rwdxchain/go-rwdxchaina/eth"
+	"github.com/rwdxchain/go-rwdxchaina/log"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+)
+
+// ulc holds the configuration of the ultra-light client mode, where new heads
+// are accepted without verification if announced by a large enough fraction of
+// a fixed set of trusted servers.
+type ulc struct {
+	trusted            map[discover.NodeID]*discover.Node
+	minTrustedFraction int
+}
+
+// newULC creates the ultra-light client configuration from the user settings,
+// returning nil if the mode is not enabled.
+func newULC(config *eth.ULCConfig) *ulc {
+	if config == nil || len(config.TrustedServers) == 0 {
+		return nil
+	}
+	u := &ulc{
+		trusted:            make(map[discover.NodeID]*discover.Node),
+		minTrustedFraction: config.MinTrustedFraction,
+	}
+	for _, url := range config.TrustedServers {
+		node, err := discover.ParseNode(url)
+		if err != nil {
+			log.Error("Invalid trusted ultra-light server", "url", url, "err", err)
+			continue
+		}
+		u.trusted[node.ID] = node
+	}
+	if len(u.trusted) == 0 {
+		return nil
+	}
+	if u.minTrustedFraction <= 0 || u.minTrustedFraction > 100 {
+		log.Warn("Invalid ultra-light trusted fraction, using default", "fraction", u.minTrustedFraction, "default", eth.DefaultULCMinTrustedFraction)
+		u.minTrustedFraction = eth.DefaultULCMinTrustedFraction
+	}
+	log.Info("Ultra-light client mode enabled", "servers", len(u.trusted), "fraction", u.minTrustedFraction)
+	return u
+}
+
+// isTrusted returns whether the given server is one of the trusted ones.
+func (u *ulc) isTrusted(id discover.NodeID) bool {
+	_, ok := u.trusted[id]
+	return ok
+}
+
+// confirmed returns whether the given number of trusted servers is a large enough
+// fraction of all trusted servers to accept an announced head.
+func (u *ulc) confirmed(agreed int) bool {
+	return 100*agreed >= u.minTrustedFraction*len(u.trusted)
+}
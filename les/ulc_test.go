@@ -0,0 +1,151 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+	"github.com/rwdxchain/go-rwdxchaina/eth"
+	"github.com/rwdxchain/go-rwdxchaina/ethdb"
+	"github.com/rwdxchain/go-rwdxchaina/light"
+	"github.com/rwdxchain/go-rwdxchaina/p2p"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+)
+
+// testTrustedServers creates a number of server identities and their enode URLs.
+func testTrustedServers(n int) ([]string, []discover.NodeID) {
+	var (
+		urls []string
+		ids  []discover.NodeID
+	)
+	for i := 0; i < n; i++ {
+		key, _ := crypto.GenerateKey()
+		id := discover.PubkeyID(&key.PublicKey)
+		urls = append(urls, fmt.Sprintf("enode://%x@127.0.0.1:%d", id[:], 30303+i))
+		ids = append(ids, id)
+	}
+	return urls, ids
+}
+
+// Tests that the ultra-light client configuration is parsed correctly and heads
+// are only confirmed by the configured fraction of trusted servers.
+func TestULCConfig(t *testing.T) {
+	if newULC(nil) != nil || newULC(&eth.ULCConfig{MinTrustedFraction: 50}) != nil {
+		t.Fatalf("ultra-light mode enabled without trusted servers")
+	}
+	urls, ids := testTrustedServers(4)
+	u := newULC(&eth.ULCConfig{TrustedServers: append(urls, "invalid"), MinTrustedFraction: 50})
+	if u == nil || len(u.trusted) != len(ids) {
+		t.Fatalf("trusted server set mismatch: have %v, want %d servers", u, len(ids))
+	}
+	for _, id := range ids {
+		if !u.isTrusted(id) {
+			t.Errorf("server %x not trusted", id[:8])
+		}
+	}
+	if u.isTrusted(discover.NodeID{1}) {
+		t.Errorf("unknown server trusted")
+	}
+	for agreed, want := range []bool{false, false, true, true, true} {
+		if have := u.confirmed(agreed); have != want {
+			t.Errorf("confirmation with %d/%d servers mismatch: have %v, want %v", agreed, len(ids), have, want)
+		}
+	}
+	// Out of range fractions fall back to the default
+	if u := newULC(&eth.ULCConfig{TrustedServers: urls, MinTrustedFraction: 101}); u.minTrustedFraction != eth.DefaultULCMinTrustedFraction {
+		t.Fatalf("trusted fraction mismatch: have %d, want %d", u.minTrustedFraction, eth.DefaultULCMinTrustedFraction)
+	}
+}
+
+// Tests that an ultra-light client ignores the heads announced by untrusted
+// servers, and only fetches a head once a large enough fraction of its trusted
+// servers announced it.
+func TestULCAnnouncements(t *testing.T) {
+	var (
+		db    = ethdb.NewMemDatabase()
+		peers = newPeerSet()
+		dist  = newRequestDistributor(peers, make(chan struct{}))
+		odr   = NewLesOdr(db, light.TestClientIndexerConfig, newRetrieveManager(peers, dist, nil))
+		pm    = newTestProtocolManagerMust(t, true, 0, nil, odr, peers, db)
+	)
+	urls, ids := testTrustedServers(4)
+	pm.ulc = newULC(&eth.ULCConfig{TrustedServers: urls, MinTrustedFraction: 50})
+
+	// Use a fetcher without its sync loop, so the requests can be inspected
+	f := &lightFetcher{
+		pm:             pm,
+		chain:          pm.blockchain.(*light.LightChain),
+		odr:            odr,
+		peers:          make(map[*peer]*fetcherPeerInfo),
+		requested:      make(map[uint64]fetchRequest),
+		requestChn:     make(chan bool, 100),
+		maxConfirmedTd: big.NewInt(0),
+	}
+	connect := func(id discover.NodeID) *peer {
+		app, net := p2p.MsgPipe()
+		defer app.Close()
+
+		p := pm.newPeer(lpv2, NetworkId, p2p.NewPeer(id, "server", nil), net)
+		f.registerPeer(p)
+		return p
+	}
+	request := func() *distReq {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+
+		rq, _ := f.nextRequest()
+		return rq
+	}
+	head := &announceData{Hash: common.Hash{0x01}, Number: 1, Td: big.NewInt(1000)}
+
+	// Heads announced by untrusted servers are ignored
+	untrusted := connect(discover.NodeID{0x01})
+	if untrusted.trusted {
+		t.Fatalf("untrusted server marked as trusted")
+	}
+	f.announce(untrusted, head)
+	if request() != nil {
+		t.Fatalf("head announced by untrusted server requested")
+	}
+	// A single trusted server is not enough to reach the 50% quorum
+	first := connect(ids[0])
+	if !first.trusted {
+		t.Fatalf("trusted server not marked as trusted")
+	}
+	f.announce(first, head)
+	if request() != nil {
+		t.Fatalf("head requested before reaching the trusted quorum")
+	}
+	// Once the quorum announced the head, it's fetched from trusted servers only
+	second := connect(ids[1])
+	f.announce(second, head)
+
+	rq := request()
+	if rq == nil {
+		t.Fatalf("head not requested after reaching the trusted quorum")
+	}
+	if rq.canSend(untrusted) {
+		t.Errorf("head requested from untrusted server")
+	}
+	if !rq.canSend(first) || !rq.canSend(second) {
+		t.Errorf("head not requested from trusted servers")
+	}
+}
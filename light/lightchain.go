@@ -133,6 +133,14 @@ func (self *LightChain) addTrustedCheckpoint(cp TrustedCheckpoint) {
 	log.Info("Added trusted checkpoint", "chain", cp.name, "block", (cp.SectionIdx+1)*self.indexerConfig.ChtSize-1, "hash", cp.SectionHead)
 }
 
+// AddTrustedCheckpoint adds a trusted checkpoint retrieved from an external source
+// (e.g. an on-chain checkpoint oracle) to the blockchain, allowing the light
+// client to request the headers and logs of the covered sections.
+func (self *LightChain) AddTrustedCheckpoint(source string, cp TrustedCheckpoint) {
+	cp.name = source
+	self.addTrustedCheckpoint(cp)
+}
+
 func (self *LightChain) getProcInterrupt() bool {
 	return atomic.LoadInt32(&self.procInterrupt) == 1
 }
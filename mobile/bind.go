@@ -19,7 +19,9 @@
 package geth
 
 import (
+	"fmt"
 	"math/big"
+	"reflect"
 	"strings"
 
 	"github.com/rwdxchain/go-rwdxchaina/accounts/abi"
@@ -107,6 +109,7 @@ func (opts *TransactOpts) SetContext(context *Context) { opts.opts.Context = con
 // higher level contract bindings to operate.
 type BoundContract struct {
 	contract *bind.BoundContract
+	abi      abi.ABI
 	address  common.Address
 	deployer *types.Transaction
 }
@@ -119,12 +122,17 @@ func DeployContract(opts *TransactOpts, abiJSON string, bytecode []byte, client
 	if err != nil {
 		return nil, err
 	}
-	addr, tx, bound, err := bind.DeployContract(&opts.opts, parsed, common.CopyBytes(bytecode), client.client, args.objects...)
+	params, err := packTuples(parsed.Constructor.Inputs, args.objects)
+	if err != nil {
+		return nil, err
+	}
+	addr, tx, bound, err := bind.DeployContract(&opts.opts, parsed, common.CopyBytes(bytecode), client.client, params...)
 	if err != nil {
 		return nil, err
 	}
 	return &BoundContract{
 		contract: bound,
+		abi:      parsed,
 		address:  addr,
 		deployer: tx,
 	}, nil
@@ -139,6 +147,7 @@ func BindContract(address *Address, abiJSON string, client *EthereumClient) (con
 	}
 	return &BoundContract{
 		contract: bind.NewBoundContract(address.address, parsed, client.client, client.client, client.client),
+		abi:      parsed,
 		address:  address.address,
 	}, nil
 }
@@ -154,26 +163,50 @@ func (c *BoundContract) GetDeployer() *Transaction {
 // Call invokes the (constant) contract method with params as input values and
 // sets the output to result.
 func (c *BoundContract) Call(opts *CallOpts, out *Interfaces, method string, args *Interfaces) error {
-	if len(out.objects) == 1 {
-		result := out.objects[0]
-		if err := c.contract.Call(&opts.opts, result, method, args.objects...); err != nil {
+	m := c.abi.Methods[method]
+	params, err := packTuples(m.Inputs, args.objects)
+	if err != nil {
+		return err
+	}
+	// Tuples are unpacked into their ABI struct types first
+	results := make([]interface{}, len(out.objects))
+	copy(results, out.objects)
+	for i, result := range results {
+		switch result.(type) {
+		case *tuple, *tupleList:
+			results[i] = new(interface{})
+		}
+	}
+	if len(results) == 1 {
+		if err := c.contract.Call(&opts.opts, results[0], method, params...); err != nil {
 			return err
 		}
-		out.objects[0] = result
 	} else {
-		results := make([]interface{}, len(out.objects))
-		copy(results, out.objects)
-		if err := c.contract.Call(&opts.opts, &results, method, args.objects...); err != nil {
+		if err := c.contract.Call(&opts.opts, &results, method, params...); err != nil {
 			return err
 		}
-		copy(out.objects, results)
+	}
+	for i, result := range results {
+		switch out.objects[i].(type) {
+		case *tuple, *tupleList:
+			if i >= len(m.Outputs) {
+				return fmt.Errorf("no output %d in method %s", i, method)
+			}
+			out.objects[i] = unpackTuple(reflect.ValueOf(*result.(*interface{})), m.Outputs[i].Type)
+		default:
+			out.objects[i] = result
+		}
 	}
 	return nil
 }
 
 // Transact invokes the (paid) contract method with params as input values.
 func (c *BoundContract) Transact(opts *TransactOpts, method string, args *Interfaces) (tx *Transaction, _ error) {
-	rawTx, err := c.contract.Transact(&opts.opts, method, args.objects...)
+	params, err := packTuples(c.abi.Methods[method].Inputs, args.objects)
+	if err != nil {
+		return nil, err
+	}
+	rawTx, err := c.contract.Transact(&opts.opts, method, params...)
 	if err != nil {
 		return nil, err
 	}
@@ -189,3 +222,134 @@ func (c *BoundContract) Transfer(opts *TransactOpts) (tx *Transaction, _ error)
 	}
 	return &Transaction{rawTx}, nil
 }
+
+// packTuples converts the tuples among the arguments of a method to the struct
+// types expected by the ABI encoder. Other arguments are passed as they are.
+func packTuples(inputs abi.Arguments, objects []interface{}) ([]interface{}, error) {
+	params := make([]interface{}, len(objects))
+	for i, object := range objects {
+		switch object.(type) {
+		case *tuple, *tupleList:
+			if i >= len(inputs) {
+				return nil, fmt.Errorf("too many arguments: have %d, want %d", len(objects), len(inputs))
+			}
+			value, err := packTuple(object, inputs[i].Type)
+			if err != nil {
+				return nil, fmt.Errorf("argument %d: %v", i, err)
+			}
+			params[i] = value.Interface()
+		default:
+			params[i] = object
+		}
+	}
+	return params, nil
+}
+
+// packTuple converts the object of a tuple or list of tuples to a value of the
+// Go type of kind.
+func packTuple(object interface{}, kind abi.Type) (reflect.Value, error) {
+	switch object := object.(type) {
+	case *tuple:
+		if kind.T != abi.TupleTy {
+			return reflect.Value{}, fmt.Errorf("tuple given for %v", kind)
+		}
+		if len(object.fields) != len(kind.TupleElems) {
+			return reflect.Value{}, fmt.Errorf("tuple %v has %d fields, got %d", kind, len(kind.TupleElems), len(object.fields))
+		}
+		value := reflect.New(kind.Type).Elem()
+		for i, field := range object.fields {
+			if err := setTupleValue(value.Field(i), field, *kind.TupleElems[i]); err != nil {
+				return reflect.Value{}, fmt.Errorf("field %s: %v", kind.TupleRawNames[i], err)
+			}
+		}
+		return value, nil
+
+	case *tupleList:
+		var value reflect.Value
+		switch kind.T {
+		case abi.SliceTy:
+			value = reflect.MakeSlice(kind.Type, len(object.elems), len(object.elems))
+		case abi.ArrayTy:
+			if len(object.elems) != kind.Size {
+				return reflect.Value{}, fmt.Errorf("%v has %d elements, got %d", kind, kind.Size, len(object.elems))
+			}
+			value = reflect.New(kind.Type).Elem()
+		default:
+			return reflect.Value{}, fmt.Errorf("tuple list given for %v", kind)
+		}
+		for i, elem := range object.elems {
+			if err := setTupleValue(value.Index(i), elem, *kind.Elem); err != nil {
+				return reflect.Value{}, fmt.Errorf("element %d: %v", i, err)
+			}
+		}
+		return value, nil
+	}
+	return reflect.Value{}, fmt.Errorf("%T given for %v", object, kind)
+}
+
+// setTupleValue sets a field or element of a tuple value to the given object,
+// which holds a pointer to the value like the setters of Interface store it.
+// Slices are copied into fixed size arrays.
+func setTupleValue(dst reflect.Value, object interface{}, kind abi.Type) error {
+	switch object.(type) {
+	case *tuple, *tupleList:
+		value, err := packTuple(object, kind)
+		if err != nil {
+			return err
+		}
+		dst.Set(value)
+		return nil
+	}
+	value := reflect.ValueOf(object)
+	if !value.IsValid() {
+		return fmt.Errorf("missing value for %v", kind)
+	}
+	if value.Kind() == reflect.Ptr && !value.Type().AssignableTo(dst.Type()) {
+		value = value.Elem()
+	}
+	switch {
+	case value.Type().AssignableTo(dst.Type()):
+		dst.Set(value)
+	case value.Kind() == reflect.Slice && dst.Kind() == reflect.Array && value.Type().Elem() == dst.Type().Elem():
+		if value.Len() != dst.Len() {
+			return fmt.Errorf("%v has %d elements, got %d", kind, dst.Len(), value.Len())
+		}
+		reflect.Copy(dst, value)
+	default:
+		return fmt.Errorf("cannot use %v as %v", value.Type(), kind)
+	}
+	return nil
+}
+
+// unpackTuple converts a tuple or list of tuples decoded by the ABI package to
+// the objects retrieved by the getters of Interface. Fixed size arrays become
+// slices.
+func unpackTuple(value reflect.Value, kind abi.Type) interface{} {
+	switch {
+	case kind.T == abi.TupleTy:
+		if value.Kind() == reflect.Ptr {
+			value = value.Elem()
+		}
+		fields := make([]interface{}, len(kind.TupleElems))
+		for i := range fields {
+			fields[i] = unpackTuple(value.Field(i), *kind.TupleElems[i])
+		}
+		return &tuple{fields}
+
+	case (kind.T == abi.SliceTy || kind.T == abi.ArrayTy) && kind.Elem.T == abi.TupleTy:
+		elems := make([]interface{}, value.Len())
+		for i := range elems {
+			elems[i] = unpackTuple(value.Index(i), *kind.Elem)
+		}
+		return &tupleList{elems}
+
+	case value.Kind() == reflect.Array:
+		slice := reflect.New(reflect.SliceOf(value.Type().Elem()))
+		slice.Elem().Set(reflect.MakeSlice(slice.Elem().Type(), value.Len(), value.Len()))
+		reflect.Copy(slice.Elem(), value)
+		return slice.Interface()
+	}
+	object := reflect.New(value.Type())
+	object.Elem().Set(value)
+	return object.Interface()
+}
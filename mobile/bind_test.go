@@ -0,0 +1,103 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rwdxchain/go-rwdxchaina/accounts/abi"
+)
+
+const tupleTestABI = `[{"constant":true,"name":"echo","type":"function","inputs":[{"name":"t","type":"tuple","components":[{"name":"amount","type":"uint256"},{"name":"entries","type":"tuple[]","components":[{"name":"label","type":"string"},{"name":"hash","type":"bytes32"}]},{"name":"flags","type":"bool[2]"}]}],"outputs":[{"name":"t","type":"tuple","components":[{"name":"amount","type":"uint256"},{"name":"entries","type":"tuple[]","components":[{"name":"label","type":"string"},{"name":"hash","type":"bytes32"}]},{"name":"flags","type":"bool[2]"}]}]}]`
+
+// newTupleInterface wraps the fields of a tuple in an Interface, like the Java
+// bindings do.
+func newTupleInterface(fields ...*Interface) *Interface {
+	objects := NewInterfaces(len(fields))
+	for i, field := range fields {
+		objects.Set(i, field)
+	}
+	tuple := NewInterface()
+	tuple.SetTuple(objects)
+	return tuple
+}
+
+// Tests that tuples set through Interface are encoded by the ABI package, and
+// that decoded tuples are read back through Interface.
+func TestTupleInterfaces(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(tupleTestABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	method := parsed.Methods["echo"]
+
+	amount, label, hash, flags := NewInterface(), NewInterface(), NewInterface(), NewInterface()
+	amount.SetBigInt(NewBigInt(7))
+	label.SetString("entry")
+	hash.SetBinary(make([]byte, 32))
+	flags.SetBools([]bool{false, true})
+
+	entries := NewInterfaces(1)
+	entries.Set(0, newTupleInterface(label, hash))
+	list := NewInterface()
+	list.SetTuples(entries)
+
+	params, err := packTuples(method.Inputs, []interface{}{newTupleInterface(amount, list, flags).object})
+	if err != nil {
+		t.Fatalf("failed to convert tuple: %v", err)
+	}
+	input, err := parsed.Pack("echo", params...)
+	if err != nil {
+		t.Fatalf("failed to pack tuple: %v", err)
+	}
+	// The method echoes its input, decode it as the output
+	result := new(interface{})
+	if err := parsed.Unpack(result, "echo", input[4:]); err != nil {
+		t.Fatalf("failed to unpack tuple: %v", err)
+	}
+	out := &Interface{unpackTuple(reflect.ValueOf(*result), method.Outputs[0].Type)}
+
+	fields := out.GetTuple()
+	if fields.Size() != 3 {
+		t.Fatalf("tuple field count mismatch: have %d, want 3", fields.Size())
+	}
+	if field, _ := fields.Get(0); field.GetBigInt().GetInt64() != 7 {
+		t.Errorf("amount mismatch: have %v", field.GetBigInt())
+	}
+	field, _ := fields.Get(1)
+	elems := field.GetTuples()
+	if elems.Size() != 1 {
+		t.Fatalf("entry count mismatch: have %d, want 1", elems.Size())
+	}
+	elem, _ := elems.Get(0)
+	entry := elem.GetTuple()
+	if field, _ := entry.Get(0); field.GetString() != "entry" {
+		t.Errorf("label mismatch: have %q", field.GetString())
+	}
+	if field, _ := entry.Get(1); len(field.GetBinary()) != 32 {
+		t.Errorf("hash length mismatch: have %d", len(field.GetBinary()))
+	}
+	if field, _ := fields.Get(2); !reflect.DeepEqual(field.GetBools(), []bool{false, true}) {
+		t.Errorf("flags mismatch: have %v", field.GetBools())
+	}
+	// Tuples not matching the ABI are rejected
+	if _, err := packTuples(method.Inputs, []interface{}{newTupleInterface(amount).object}); err == nil {
+		t.Errorf("tuple with missing fields accepted")
+	}
+}
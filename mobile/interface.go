@@ -113,6 +113,27 @@ func (i *Interface) GetUint64() *BigInt {
 func (i *Interface) GetBigInt() *BigInt   { return &BigInt{*i.object.(**big.Int)} }
 func (i *Interface) GetBigInts() *BigInts { return &BigInts{*i.object.(*[]*big.Int)} }
 
+// tuple is the value of a contract tuple, holding the objects of its fields in
+// the order of the ABI.
+type tuple struct {
+	fields []interface{}
+}
+
+// tupleList is the value of a list of contract tuples, holding a tuple for each
+// element.
+type tupleList struct {
+	elems []interface{}
+}
+
+func (i *Interface) SetTuple(fields *Interfaces)  { i.object = &tuple{fields.objects} }
+func (i *Interface) SetTuples(tuples *Interfaces) { i.object = &tupleList{tuples.objects} }
+
+func (i *Interface) SetDefaultTuple()  { i.object = new(tuple) }
+func (i *Interface) SetDefaultTuples() { i.object = new(tupleList) }
+
+func (i *Interface) GetTuple() *Interfaces  { return &Interfaces{i.object.(*tuple).fields} }
+func (i *Interface) GetTuples() *Interfaces { return &Interfaces{i.object.(*tupleList).elems} }
+
 // Interfaces is a slices of wrapped generic objects.
 type Interfaces struct {
 	objects []interface{}
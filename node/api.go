@@ -18,6 +18,7 @@ package node
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -27,6 +28,7 @@ import (
 	"github.com/rwdxchain/go-rwdxchaina/metrics"
 	"github.com/rwdxchain/go-rwdxchaina/p2p"
 	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discv5"
 	"github.com/rwdxchain/go-rwdxchaina/rpc"
 )
 
@@ -75,6 +77,22 @@ func (api *PrivateAdminAPI) RemovePeer(url string) (bool, error) {
 	return true, nil
 }
 
+// BanPeer disconnects from a remote node and refuses connections to and from it
+// for some time. Repeated bans last longer.
+func (api *PrivateAdminAPI) BanPeer(url string) (bool, error) {
+	// Make sure the server is running, fail otherwise
+	server := api.node.Server()
+	if server == nil {
+		return false, ErrNodeStopped
+	}
+	node, err := discover.ParseNode(url)
+	if err != nil {
+		return false, fmt.Errorf("invalid enode: %v", err)
+	}
+	server.BanPeer(node)
+	return true, nil
+}
+
 // AddTrustedPeer allows a remote node to always connect, even if slots are full
 func (api *PrivateAdminAPI) AddTrustedPeer(url string) (bool, error) {
 	// Make sure the server is running, fail otherwise
@@ -292,6 +310,20 @@ func (api *PublicAdminAPI) NodeInfo() (*p2p.NodeInfo, error) {
 	return server.NodeInfo(), nil
 }
 
+// DiscoveryTopics retrieves the topics known to the V5 discovery, together with
+// the nodes which registered for them at the local node.
+func (api *PublicAdminAPI) DiscoveryTopics() ([]discv5.TopicInfo, error) {
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	topics := server.DiscoveryTopics()
+	if topics == nil {
+		return nil, errors.New("discovery v5 is not running")
+	}
+	return topics, nil
+}
+
 // Datadir retrieves the current data directory the node is using.
 func (api *PublicAdminAPI) Datadir() string {
 	return api.node.DataDir()
@@ -26,6 +26,7 @@ import (
 	"strings"
 
 	"github.com/rwdxchain/go-rwdxchaina/accounts"
+	"github.com/rwdxchain/go-rwdxchaina/accounts/hdwallet"
 	"github.com/rwdxchain/go-rwdxchaina/accounts/keystore"
 	"github.com/rwdxchain/go-rwdxchaina/accounts/usbwallet"
 	"github.com/rwdxchain/go-rwdxchaina/common"
@@ -42,6 +43,7 @@ const (
 	datadirStaticNodes     = "static-nodes.json"  // Path within the datadir to the static node list
 	datadirTrustedNodes    = "trusted-nodes.json" // Path within the datadir to the trusted node list
 	datadirNodeDatabase    = "nodes"              // Path within the datadir to store the node infos
+	datadirNodeDatabaseV5  = "nodes.v5"           // Path within the datadir to store the V5 discovery node infos
 )
 
 // Config represents a small collection of configuration values to fine tune the
@@ -83,6 +85,15 @@ type Config struct {
 	// scrypt KDF at the expense of security.
 	UseLightweightKDF bool `toml:",omitempty"`
 
+	// KeyStoreDB is the path of an optional key database file, keeping additional
+	// private keys in a single file. A relative path is resolved relative to the
+	// current directory.
+	KeyStoreDB string `toml:",omitempty"`
+
+	// RemoteKeyStore is the endpoint of an optional remote signing service holding
+	// additional private keys. Plain HTTP is only accepted on the loopback interface.
+	RemoteKeyStore string `toml:",omitempty"`
+
 	// NoUSB disables hardware wallet monitoring and connectivity.
 	NoUSB bool `toml:",omitempty"`
 
@@ -187,6 +198,14 @@ func (c *Config) NodeDB() string {
 	return c.ResolvePath(datadirNodeDatabase)
 }
 
+// NodeDBV5 returns the path to the V5 discovery node database.
+func (c *Config) NodeDBV5() string {
+	if c.DataDir == "" {
+		return "" // ephemeral
+	}
+	return c.ResolvePath(datadirNodeDatabaseV5)
+}
+
 // DefaultIPCEndpoint returns the IPC path used by default.
 func DefaultIPCEndpoint(clientIdentifier string) string {
 	if clientIdentifier == "" {
@@ -335,12 +354,12 @@ func (c *Config) NodeKey() *ecdsa.PrivateKey {
 	return key
 }
 
-// StaticNodes returns a list of node enode URLs configured as static nodes.
+// StaticNodes returns a list of node enode or enr URLs configured as static nodes.
 func (c *Config) StaticNodes() []*discover.Node {
 	return c.parsePersistentNodes(c.ResolvePath(datadirStaticNodes))
 }
 
-// TrustedNodes returns a list of node enode URLs configured as trusted nodes.
+// TrustedNodes returns a list of node enode or enr URLs configured as trusted nodes.
 func (c *Config) TrustedNodes() []*discover.Node {
 	return c.parsePersistentNodes(c.ResolvePath(datadirTrustedNodes))
 }
@@ -424,6 +443,27 @@ func makeAccountManager(conf *Config) (*accounts.Manager, string, error) {
 	backends := []accounts.Backend{
 		keystore.NewKeyStore(keydir, scryptN, scryptP),
 	}
+	// Load any HD wallets kept along with the keystore
+	if hdhub, err := hdwallet.NewHub(filepath.Join(keydir, hdwallet.KeyStoreSubdir), scryptN, scryptP); err != nil {
+		log.Warn(fmt.Sprintf("Failed to load HD wallets, disabling: %v", err))
+	} else {
+		backends = append(backends, hdhub)
+	}
+	// Open the key database and remote signer if requested
+	if conf.KeyStoreDB != "" {
+		dbks, err := keystore.NewDatabaseKeyStore(conf.KeyStoreDB, scryptN, scryptP)
+		if err != nil {
+			return nil, "", err
+		}
+		backends = append(backends, dbks)
+	}
+	if conf.RemoteKeyStore != "" {
+		remoteks, err := keystore.NewRemoteKeyStore(conf.RemoteKeyStore)
+		if err != nil {
+			return nil, "", err
+		}
+		backends = append(backends, remoteks)
+	}
 	if !conf.NoUSB {
 		// Start a USB hub for Ledger hardware wallets
 		if ledgerhub, err := usbwallet.NewLedgerHub(); err != nil {
@@ -431,9 +471,15 @@ func makeAccountManager(conf *Config) (*accounts.Manager, string, error) {
 		} else {
 			backends = append(backends, ledgerhub)
 		}
-		// Start a USB hub for Trezor hardware wallets
+		// Start a USB hub for Trezor hardware wallets (HID version)
 		if trezorhub, err := usbwallet.NewTrezorHub(); err != nil {
-			log.Warn(fmt.Sprintf("Failed to start Trezor hub, disabling: %v", err))
+			log.Warn(fmt.Sprintf("Failed to start HID Trezor hub, disabling: %v", err))
+		} else {
+			backends = append(backends, trezorhub)
+		}
+		// Start a USB hub for Trezor hardware wallets (WebUSB version)
+		if trezorhub, err := usbwallet.NewTrezorHubWithWebUSB(); err != nil {
+			log.Warn(fmt.Sprintf("Failed to start WebUSB Trezor hub, disabling: %v", err))
 		} else {
 			backends = append(backends, trezorhub)
 		}
@@ -22,10 +22,12 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"sync"
 	"time"
 
 	"github.com/rwdxchain/go-rwdxchaina/log"
 	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/enr"
 	"github.com/rwdxchain/go-rwdxchaina/p2p/netutil"
 )
 
@@ -42,9 +44,18 @@ const (
 	// attempted to be connected.
 	fallbackInterval = 20 * time.Second
 
+	// Dial candidates found by topic searches are polled at this interval
+	// when the dialer is idle.
+	topicPollInterval = time.Second
+
 	// Endpoint resolution is throttled with bounded backoff.
 	initialResolveDelay = 60 * time.Second
 	maxResolveDelay     = time.Hour
+
+	// Node records retrieved before dialing are reused for a while, nodes
+	// which didn't answer the request are asked again sooner.
+	recordCacheTTL       = 30 * time.Minute
+	recordCacheFailedTTL = 5 * time.Minute
 )
 
 // NodeDialer is used to connect to nodes in the network, typically by using
@@ -77,8 +88,13 @@ type dialstate struct {
 	dialing       map[discover.NodeID]connFlag
 	lookupBuf     []*discover.Node // current discovery lookup results
 	randomNodes   []*discover.Node // filled from Table
+	dnsNodes      nodeSource       // node lists published in DNS, if any
+	dnsBuf        []*discover.Node // filled from dnsNodes
+	topicNodes    nodeSource       // nodes advertising the topics searched by the protocols, if any
+	topicBuf      []*discover.Node // filled from topicNodes
 	static        map[discover.NodeID]*dialTask
 	hist          *dialHistory
+	bans          *banList // nodes banned by the server, if set
 
 	start     time.Time        // time when the dialer was first used
 	bootnodes []*discover.Node // default dials when there are no peers
@@ -90,6 +106,12 @@ type discoverTable interface {
 	Resolve(target discover.NodeID) *discover.Node
 	Lookup(target discover.NodeID) []*discover.Node
 	ReadRandomNodes([]*discover.Node) int
+	RequestENR(*discover.Node) (*enr.Record, error)
+}
+
+// nodeSource supplies dial candidates besides the discovery table.
+type nodeSource interface {
+	RandomNodes([]*discover.Node) int
 }
 
 // the dial history remembers recent dials.
@@ -166,7 +188,7 @@ func (s *dialstate) newTasks(nRunning int, peers map[discover.NodeID]*Peer, now
 
 	var newtasks []task
 	addDial := func(flag connFlag, n *discover.Node) bool {
-		if err := s.checkDial(n, peers); err != nil {
+		if err := s.checkDial(n, peers, now); err != nil {
 			log.Trace("Skipping dial candidate", "id", n.ID, "addr", &net.TCPAddr{IP: n.IP, Port: int(n.TCP)}, "err", err)
 			return false
 		}
@@ -193,7 +215,7 @@ func (s *dialstate) newTasks(nRunning int, peers map[discover.NodeID]*Peer, now
 
 	// Create dials for static nodes if they are not connected.
 	for id, t := range s.static {
-		err := s.checkDial(t.dest, peers)
+		err := s.checkDial(t.dest, peers, now)
 		switch err {
 		case errNotWhitelisted, errSelf:
 			log.Warn("Removing static dial candidate", "id", t.dest.ID, "addr", &net.TCPAddr{IP: t.dest.IP, Port: int(t.dest.TCP)}, "err", err)
@@ -215,10 +237,23 @@ func (s *dialstate) newTasks(nRunning int, peers map[discover.NodeID]*Peer, now
 			needDynDials--
 		}
 	}
+	// Nodes advertising the topics of our protocols are the most useful
+	// peers, dial them first.
+	if s.topicNodes != nil && needDynDials > 0 {
+		if len(s.topicBuf) < needDynDials {
+			s.topicBuf = make([]*discover.Node, needDynDials)
+		}
+		n := s.topicNodes.RandomNodes(s.topicBuf[:needDynDials])
+		for i := 0; i < n; i++ {
+			if addDial(dynDialedConn, s.topicBuf[i]) {
+				needDynDials--
+			}
+		}
+	}
 	// Use random nodes from the table for half of the necessary
 	// dynamic dials.
 	randomCandidates := needDynDials / 2
-	if randomCandidates > 0 {
+	if randomCandidates > 0 && s.ntab != nil {
 		n := s.ntab.ReadRandomNodes(s.randomNodes)
 		for i := 0; i < randomCandidates && i < n; i++ {
 			if addDial(dynDialedConn, s.randomNodes[i]) {
@@ -226,6 +261,23 @@ func (s *dialstate) newTasks(nRunning int, peers map[discover.NodeID]*Peer, now
 			}
 		}
 	}
+	// Use random nodes from the DNS node lists for half of the remaining
+	// dynamic dials, or for all of them if the discovery table is disabled.
+	if s.dnsNodes != nil {
+		dnsCandidates := needDynDials / 2
+		if s.ntab == nil {
+			dnsCandidates = needDynDials
+		}
+		if len(s.dnsBuf) < dnsCandidates {
+			s.dnsBuf = make([]*discover.Node, dnsCandidates)
+		}
+		n := s.dnsNodes.RandomNodes(s.dnsBuf[:dnsCandidates])
+		for i := 0; i < n; i++ {
+			if addDial(dynDialedConn, s.dnsBuf[i]) {
+				needDynDials--
+			}
+		}
+	}
 	// Create dynamic dials from random lookup results, removing tried
 	// items from the result buffer.
 	i := 0
@@ -236,7 +288,7 @@ func (s *dialstate) newTasks(nRunning int, peers map[discover.NodeID]*Peer, now
 	}
 	s.lookupBuf = s.lookupBuf[:copy(s.lookupBuf, s.lookupBuf[i:])]
 	// Launch a discovery lookup if more candidates are needed.
-	if len(s.lookupBuf) < needDynDials && !s.lookupRunning {
+	if len(s.lookupBuf) < needDynDials && !s.lookupRunning && s.ntab != nil {
 		s.lookupRunning = true
 		newtasks = append(newtasks, &discoverTask{})
 	}
@@ -245,9 +297,22 @@ func (s *dialstate) newTasks(nRunning int, peers map[discover.NodeID]*Peer, now
 	// candidates have been tried and no task is currently active.
 	// This should prevent cases where the dialer logic is not ticked
 	// because there are no pending events.
-	if nRunning == 0 && len(newtasks) == 0 && s.hist.Len() > 0 {
-		t := &waitExpireTask{s.hist.min().exp.Sub(now)}
-		newtasks = append(newtasks, t)
+	if nRunning == 0 && len(newtasks) == 0 {
+		var (
+			wait    time.Duration
+			waiting = s.hist.Len() > 0
+		)
+		if waiting {
+			wait = s.hist.min().exp.Sub(now)
+		}
+		// Nodes found by topic searches don't wake up the dialer, poll
+		// them while more peers are needed.
+		if s.topicNodes != nil && needDynDials > 0 && (!waiting || wait > topicPollInterval) {
+			wait, waiting = topicPollInterval, true
+		}
+		if waiting {
+			newtasks = append(newtasks, &waitExpireTask{wait})
+		}
 	}
 	return newtasks
 }
@@ -257,10 +322,11 @@ var (
 	errAlreadyDialing   = errors.New("already dialing")
 	errAlreadyConnected = errors.New("already connected")
 	errRecentlyDialed   = errors.New("recently dialed")
+	errBanned           = errors.New("banned")
 	errNotWhitelisted   = errors.New("not contained in netrestrict whitelist")
 )
 
-func (s *dialstate) checkDial(n *discover.Node, peers map[discover.NodeID]*Peer) error {
+func (s *dialstate) checkDial(n *discover.Node, peers map[discover.NodeID]*Peer, now time.Time) error {
 	_, dialing := s.dialing[n.ID]
 	switch {
 	case dialing:
@@ -273,6 +339,8 @@ func (s *dialstate) checkDial(n *discover.Node, peers map[discover.NodeID]*Peer)
 		return errNotWhitelisted
 	case s.hist.contains(n.ID):
 		return errRecentlyDialed
+	case s.bans != nil && s.bans.contains(n.ID, now):
+		return errBanned
 	}
 	return nil
 }
@@ -294,13 +362,17 @@ func (t *dialTask) Do(srv *Server) {
 			return
 		}
 	}
-	err := t.dial(srv, t.dest)
+	record := t.requestRecord(srv)
+	if t.flags&dynDialedConn != 0 && !t.checkRecord(srv, record) {
+		return
+	}
+	err := t.dial(srv, t.dest, record)
 	if err != nil {
 		log.Trace("Dial error", "task", t, "err", err)
 		// Try resolving the ID of static nodes if dialing failed.
 		if _, ok := err.(*dialError); ok && t.flags&staticDialedConn != 0 {
 			if t.resolve(srv) {
-				t.dial(srv, t.dest)
+				t.dial(srv, t.dest, nil)
 			}
 		}
 	}
@@ -340,15 +412,112 @@ func (t *dialTask) resolve(srv *Server) bool {
 	return true
 }
 
+// requestRecord retrieves the node record of the destination if it is needed for
+// running the dial filters of the protocols or for choosing a transport. It returns
+// nil if the record isn't needed or the node doesn't answer the request.
+func (t *dialTask) requestRecord(srv *Server) *enr.Record {
+	needed := (t.flags&dynDialedConn != 0 && srv.hasDialFilters()) || len(srv.ExtraTransports) > 0
+	if srv.ntab == nil || !needed {
+		return nil
+	}
+	if record, ok := srv.records.get(t.dest.ID, time.Now()); ok {
+		return record
+	}
+	record, err := srv.ntab.RequestENR(t.dest)
+	if err != nil {
+		log.Trace("Node record request failed", "id", t.dest.ID, "err", err)
+	}
+	srv.records.add(t.dest.ID, record, time.Now())
+	return record
+}
+
+// recordCache keeps the node records retrieved before dialing, so they aren't
+// requested again on every dial attempt. Failed requests are remembered too.
+// A nil cache stores nothing.
+type recordCache struct {
+	mu      sync.Mutex
+	records map[discover.NodeID]cachedRecord
+}
+
+type cachedRecord struct {
+	record  *enr.Record // nil if the request failed
+	expires time.Time
+}
+
+func newRecordCache() *recordCache {
+	return &recordCache{records: make(map[discover.NodeID]cachedRecord)}
+}
+
+// get returns the cached record of a node, if it's not stale yet.
+func (c *recordCache) get(id discover.NodeID, now time.Time) (*enr.Record, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.records[id]
+	if !ok || !now.Before(cached.expires) {
+		return nil, false
+	}
+	return cached.record, true
+}
+
+// add caches the record of a node, dropping the stale ones.
+func (c *recordCache) add(id discover.NodeID, record *enr.Record, now time.Time) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, cached := range c.records {
+		if !now.Before(cached.expires) {
+			delete(c.records, id)
+		}
+	}
+	ttl := recordCacheTTL
+	if record == nil {
+		ttl = recordCacheFailedTTL
+	}
+	c.records[id] = cachedRecord{record, now.Add(ttl)}
+}
+
+// checkRecord runs the node record of a discovered node through the dial filters
+// of the protocols, returning false if any of them rejects the node. Nodes without
+// a record are dialed anyway, as they might not support node records yet.
+func (t *dialTask) checkRecord(srv *Server, record *enr.Record) bool {
+	if record == nil {
+		return true
+	}
+	for _, proto := range srv.Protocols {
+		if proto.DialFilter != nil && !proto.DialFilter(record) {
+			log.Debug("Skipping incompatible node", "id", t.dest.ID, "protocol", proto.Name)
+			return false
+		}
+	}
+	return true
+}
+
 type dialError struct {
 	error
 }
 
-// dial performs the actual connection attempt.
-func (t *dialTask) dial(srv *Server, dest *discover.Node) error {
-	fd, err := srv.Dialer.Dial(dest)
-	if err != nil {
-		return &dialError{err}
+// dial performs the actual connection attempt. If the node record announces
+// an additional transport of the server, the transport is tried first.
+func (t *dialTask) dial(srv *Server, dest *discover.Node, record *enr.Record) error {
+	var fd net.Conn
+	if tr, port := srv.sharedTransport(record); tr != nil {
+		var err error
+		if fd, err = tr.Dial(endpoint(dest.IP, port)); err != nil {
+			log.Trace("Transport dial failed", "id", dest.ID, "transport", tr.Name(), "err", err)
+		}
+	}
+	if fd == nil {
+		var err error
+		if fd, err = srv.Dialer.Dial(dest); err != nil {
+			return &dialError{err}
+		}
 	}
 	mfd := newMeteredConn(fd, false)
 	return srv.SetupConn(mfd, t.flags, dest)
@@ -18,6 +18,7 @@ package p2p
 
 import (
 	"encoding/binary"
+	"errors"
 	"net"
 	"reflect"
 	"testing"
@@ -25,6 +26,7 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/enr"
 	"github.com/rwdxchain/go-rwdxchaina/p2p/netutil"
 )
 
@@ -83,6 +85,9 @@ func (t fakeTable) Close()                                   {}
 func (t fakeTable) Lookup(discover.NodeID) []*discover.Node  { return nil }
 func (t fakeTable) Resolve(discover.NodeID) *discover.Node   { return nil }
 func (t fakeTable) ReadRandomNodes(buf []*discover.Node) int { return copy(buf, t) }
+func (t fakeTable) RequestENR(*discover.Node) (*enr.Record, error) {
+	return nil, errors.New("not supported")
+}
 
 // This test checks that dynamic dials are launched from discovery results.
 func TestDialStateDynDial(t *testing.T) {
@@ -402,6 +407,123 @@ func TestDialStateDynDialFromTable(t *testing.T) {
 	})
 }
 
+type fakeNodeSource []*discover.Node
+
+func (s fakeNodeSource) RandomNodes(buf []*discover.Node) int { return copy(buf, s) }
+
+// This test checks that dynamic dials are launched from the DNS node lists when
+// the discovery table is disabled.
+func TestDialStateDynDialFromDNS(t *testing.T) {
+	state := newDialState(nil, nil, nil, 4, nil)
+	state.dnsNodes = fakeNodeSource{
+		{ID: uintID(1)},
+		{ID: uintID(2)},
+		{ID: uintID(3)},
+		{ID: uintID(4)},
+		{ID: uintID(5)},
+	}
+	runDialTest(t, dialtest{
+		init: state,
+		rounds: []round{
+			// All dynamic dials are filled from the node lists, without lookups.
+			{
+				new: []task{
+					&dialTask{flags: dynDialedConn, dest: &discover.Node{ID: uintID(1)}},
+					&dialTask{flags: dynDialedConn, dest: &discover.Node{ID: uintID(2)}},
+					&dialTask{flags: dynDialedConn, dest: &discover.Node{ID: uintID(3)}},
+					&dialTask{flags: dynDialedConn, dest: &discover.Node{ID: uintID(4)}},
+				},
+			},
+		},
+	})
+}
+
+// This test checks that the DNS node lists share the dynamic dials with the
+// discovery table.
+func TestDialStateDynDialFromTableAndDNS(t *testing.T) {
+	table := fakeTable{
+		{ID: uintID(10)},
+		{ID: uintID(11)},
+		{ID: uintID(12)},
+		{ID: uintID(13)},
+	}
+	state := newDialState(nil, nil, table, 8, nil)
+	state.dnsNodes = fakeNodeSource{
+		{ID: uintID(1)},
+		{ID: uintID(2)},
+		{ID: uintID(3)},
+	}
+	runDialTest(t, dialtest{
+		init: state,
+		rounds: []round{
+			// Half of the dials come from the table, half of the rest from the
+			// node lists and a lookup is launched for the remainder.
+			{
+				new: []task{
+					&dialTask{flags: dynDialedConn, dest: &discover.Node{ID: uintID(10)}},
+					&dialTask{flags: dynDialedConn, dest: &discover.Node{ID: uintID(11)}},
+					&dialTask{flags: dynDialedConn, dest: &discover.Node{ID: uintID(12)}},
+					&dialTask{flags: dynDialedConn, dest: &discover.Node{ID: uintID(13)}},
+					&dialTask{flags: dynDialedConn, dest: &discover.Node{ID: uintID(1)}},
+					&dialTask{flags: dynDialedConn, dest: &discover.Node{ID: uintID(2)}},
+					&discoverTask{},
+				},
+			},
+		},
+	})
+}
+
+// This test checks that nodes found by topic searches are dialed before the
+// nodes of the discovery table.
+func TestDialStateDynDialFromTopics(t *testing.T) {
+	table := fakeTable{
+		{ID: uintID(10)},
+		{ID: uintID(11)},
+		{ID: uintID(12)},
+		{ID: uintID(13)},
+	}
+	state := newDialState(nil, nil, table, 8, nil)
+	state.topicNodes = fakeNodeSource{
+		{ID: uintID(1)},
+		{ID: uintID(2)},
+	}
+	runDialTest(t, dialtest{
+		init: state,
+		rounds: []round{
+			// The topic nodes are dialed first, half of the remaining dials
+			// come from the table and a lookup is launched for the rest.
+			{
+				new: []task{
+					&dialTask{flags: dynDialedConn, dest: &discover.Node{ID: uintID(1)}},
+					&dialTask{flags: dynDialedConn, dest: &discover.Node{ID: uintID(2)}},
+					&dialTask{flags: dynDialedConn, dest: &discover.Node{ID: uintID(10)}},
+					&dialTask{flags: dynDialedConn, dest: &discover.Node{ID: uintID(11)}},
+					&dialTask{flags: dynDialedConn, dest: &discover.Node{ID: uintID(12)}},
+					&discoverTask{},
+				},
+			},
+		},
+	})
+}
+
+// This test checks that the dialer polls the topic nodes while it is idle.
+func TestDialStateTopicPoll(t *testing.T) {
+	state := newDialState(nil, nil, nil, 2, nil)
+	state.topicNodes = fakeNodeSource{}
+	runDialTest(t, dialtest{
+		init: state,
+		rounds: []round{
+			{
+				new: []task{&waitExpireTask{topicPollInterval}},
+			},
+			{
+				done: []task{&waitExpireTask{topicPollInterval}},
+				new:  []task{&waitExpireTask{topicPollInterval}},
+			},
+		},
+	})
+}
+
 // This test checks that candidates that do not match the netrestrict list are not dialed.
 func TestDialStateNetRestrict(t *testing.T) {
 	// This table always returns the same random nodes
@@ -678,10 +800,63 @@ func uintID(i uint32) discover.NodeID {
 	return id
 }
 
+// Tests that discovered nodes are only dialed if their node records pass the dial
+// filters of the protocols.
+func TestDialFilter(t *testing.T) {
+	var (
+		key    = newkey()
+		dest   = discover.NewNode(discover.PubkeyID(&key.PublicKey), net.IP{127, 0, 0, 1}, 30303, 30303)
+		local  = discover.NewLocalRecord(key)
+		dialer = new(countingDialer)
+	)
+	local.Set(enr.WithEntry("test", uint(1)))
+
+	filter := func(want uint) func(r *enr.Record) bool {
+		return func(r *enr.Record) bool {
+			var have uint
+			return r.Load(enr.WithEntry("test", &have)) == nil && have == want
+		}
+	}
+	tests := []struct {
+		record *enr.Record
+		filter func(r *enr.Record) bool
+		flags  connFlag
+		dialed bool
+	}{
+		{local.Record(), nil, dynDialedConn, true},          // no filter
+		{local.Record(), filter(1), dynDialedConn, true},    // accepted record
+		{local.Record(), filter(2), dynDialedConn, false},   // rejected record
+		{nil, filter(2), dynDialedConn, true},               // no record available
+		{local.Record(), filter(2), staticDialedConn, true}, // static nodes are not filtered
+	}
+	for i, tt := range tests {
+		srv := &Server{
+			ntab:   &resolveMock{record: tt.record},
+			Config: Config{Dialer: dialer, Protocols: []Protocol{{Name: "test", DialFilter: tt.filter}}},
+		}
+		dialer.dials = 0
+		(&dialTask{flags: tt.flags, dest: dest}).Do(srv)
+		if dialed := dialer.dials > 0; dialed != tt.dialed {
+			t.Errorf("test %d: dial mismatch: have %v, want %v", i, dialed, tt.dialed)
+		}
+	}
+}
+
+// countingDialer is a NodeDialer counting the dial attempts, which all fail.
+type countingDialer struct {
+	dials int
+}
+
+func (d *countingDialer) Dial(*discover.Node) (net.Conn, error) {
+	d.dials++
+	return nil, errors.New("dial disabled")
+}
+
 // implements discoverTable for TestDialResolve
 type resolveMock struct {
 	resolveCalls []discover.NodeID
 	answer       *discover.Node
+	record       *enr.Record
 }
 
 func (t *resolveMock) Resolve(id discover.NodeID) *discover.Node {
@@ -694,3 +869,9 @@ func (t *resolveMock) Close()                                   {}
 func (t *resolveMock) Bootstrap([]*discover.Node)               {}
 func (t *resolveMock) Lookup(discover.NodeID) []*discover.Node  { return nil }
 func (t *resolveMock) ReadRandomNodes(buf []*discover.Node) int { return 0 }
+func (t *resolveMock) RequestENR(*discover.Node) (*enr.Record, error) {
+	if t.record == nil {
+		return nil, errors.New("no record")
+	}
+	return t.record, nil
+}
@@ -57,6 +57,7 @@ type nodeDB struct {
 var (
 	nodeDBVersionKey = []byte("version") // Version of the database to flush if changes
 	nodeDBItemPrefix = []byte("n:")      // Identifier to prefix node entries with
+	nodeDBBanPrefix  = []byte("b:")      // Identifier to prefix node bans with, kept apart from the expiring node entries
 
 	nodeDBDiscoverRoot      = ":discover"
 	nodeDBDiscoverPing      = nodeDBDiscoverRoot + ":lastping"
@@ -242,15 +243,24 @@ func (db *nodeDB) expirer() {
 }
 
 // expireNodes iterates over the database and deletes all nodes that have not
-// been seen (i.e. received a pong from) for some allotted time.
+// been seen (i.e. received a pong from) for some allotted time, as well as the
+// bans that are no longer remembered.
 func (db *nodeDB) expireNodes() error {
-	threshold := time.Now().Add(-nodeDBNodeExpiration)
+	now := time.Now()
+	threshold := now.Add(-nodeDBNodeExpiration)
 
 	// Find discovered nodes that are older than the allowance
 	it := db.lvl.NewIterator(nil, nil)
 	defer it.Release()
 
 	for it.Next() {
+		if bytes.HasPrefix(it.Key(), nodeDBBanPrefix) {
+			var ban nodeBan
+			if err := rlp.DecodeBytes(it.Value(), &ban); err != nil || !now.Before(time.Unix(int64(ban.Forget), 0)) {
+				db.lvl.Delete(it.Key(), nil)
+			}
+			continue
+		}
 		// Skip the item if not a discovery node
 		id, field := splitKey(it.Key())
 		if field != nodeDBDiscoverRoot {
@@ -303,6 +313,35 @@ func (db *nodeDB) updateFindFails(id NodeID, fails int) error {
 	return db.storeInt64(makeKey(id, nodeDBDiscoverFindFails), int64(fails))
 }
 
+// nodeBan is the RLP representation of the ban of a node in the database.
+type nodeBan struct {
+	Until  uint64 // Unix time the current ban ends at
+	Forget uint64 // Unix time the bans of the node are forgotten at
+	Count  uint   // Number of remembered bans
+}
+
+// ban retrieves the ban of a node, nil if the node is not banned.
+func (db *nodeDB) ban(id NodeID) *nodeBan {
+	blob, err := db.lvl.Get(append(nodeDBBanPrefix, id[:]...), nil)
+	if err != nil {
+		return nil
+	}
+	ban := new(nodeBan)
+	if err := rlp.DecodeBytes(blob, ban); err != nil {
+		return nil
+	}
+	return ban
+}
+
+// updateBan stores the ban of a node.
+func (db *nodeDB) updateBan(id NodeID, ban *nodeBan) error {
+	blob, err := rlp.EncodeToBytes(ban)
+	if err != nil {
+		return err
+	}
+	return db.lvl.Put(append(nodeDBBanPrefix, id[:]...), blob, nil)
+}
+
 // querySeeds retrieves random nodes to be used as potential seed nodes
 // for bootstrapping.
 func (db *nodeDB) querySeeds(n int, maxAge time.Duration) []*Node {
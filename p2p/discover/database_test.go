@@ -378,3 +378,30 @@ func TestNodeDBSelfExpiration(t *testing.T) {
 		t.Errorf("self not evacuated")
 	}
 }
+
+func TestNodeDBBanExpiration(t *testing.T) {
+	db, _ := newNodeDB("", nodeDBVersion, NodeID{})
+	defer db.close()
+
+	var (
+		now    = time.Now()
+		active = NodeID{0x01}
+		stale  = NodeID{0x02}
+	)
+	if ban := db.ban(active); ban != nil {
+		t.Fatalf("unknown node banned: %+v", ban)
+	}
+	db.updateBan(active, &nodeBan{Until: uint64(now.Unix()), Forget: uint64(now.Add(time.Hour).Unix()), Count: 2})
+	db.updateBan(stale, &nodeBan{Until: uint64(now.Add(-2 * time.Hour).Unix()), Forget: uint64(now.Add(-time.Hour).Unix()), Count: 1})
+
+	// Bans are kept apart from the node entries, only forgotten ones are dropped
+	if err := db.expireNodes(); err != nil {
+		t.Fatalf("failed to expire nodes: %v", err)
+	}
+	if ban := db.ban(active); ban == nil || ban.Count != 2 || ban.Until != uint64(now.Unix()) {
+		t.Errorf("remembered ban mismatch: %+v", ban)
+	}
+	if ban := db.ban(stale); ban != nil {
+		t.Errorf("forgotten ban not expired: %+v", ban)
+	}
+}
@@ -140,7 +140,17 @@ var incompleteNodeURL = regexp.MustCompile("(?i)^(?:enode://)?([0-9a-f]+)$")
 // and UDP discovery port 33750.
 //
 //    enode://<hex node id>@10.3.58.6:33760?discport=33750
+//
+// Nodes can also be given as signed node records in their text form, which
+// starts with "enr:". See ParseRecord for details.
 func ParseNode(rawurl string) (*Node, error) {
+	if strings.HasPrefix(rawurl, recordPrefix) {
+		r, err := ParseRecord(rawurl)
+		if err != nil {
+			return nil, err
+		}
+		return NodeFromRecord(r)
+	}
 	if m := incompleteNodeURL.FindStringSubmatch(rawurl); m != nil {
 		id, err := HexID(m[1])
 		if err != nil {
@@ -0,0 +1,201 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package discover
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/log"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/enr"
+	"github.com/rwdxchain/go-rwdxchaina/rlp"
+)
+
+// recordPrefix is the prefix of the text form of node records.
+const recordPrefix = "enr:"
+
+// ParseRecord decodes and verifies a node record in its text form, which is
+// "enr:" followed by the URL-safe base64 encoding (without padding) of the RLP
+// encoded record.
+func ParseRecord(text string) (*enr.Record, error) {
+	if !strings.HasPrefix(text, recordPrefix) {
+		return nil, fmt.Errorf("invalid node record, want %q prefix", recordPrefix)
+	}
+	blob, err := base64.RawURLEncoding.DecodeString(text[len(recordPrefix):])
+	if err != nil {
+		return nil, fmt.Errorf("invalid node record encoding: %v", err)
+	}
+	var r enr.Record
+	if err := rlp.DecodeBytes(blob, &r); err != nil {
+		return nil, fmt.Errorf("invalid node record: %v", err)
+	}
+	return &r, nil
+}
+
+// RecordText returns the text form of a signed node record.
+func RecordText(r *enr.Record) string {
+	blob, err := rlp.EncodeToBytes(r)
+	if err != nil {
+		return ""
+	}
+	return recordPrefix + base64.RawURLEncoding.EncodeToString(blob)
+}
+
+// NodeFromRecord creates a node from the endpoint information of a signed node
+// record using the "v4" identity scheme. Records without an IP address result
+// in incomplete nodes.
+func NodeFromRecord(r *enr.Record) (*Node, error) {
+	var id enr.ID
+	if err := r.Load(&id); err != nil {
+		return nil, err
+	}
+	if id != enr.IDv4 {
+		return nil, fmt.Errorf("unsupported identity scheme %q", id)
+	}
+	var pubkey enr.Secp256k1
+	if err := r.Load(&pubkey); err != nil {
+		return nil, err
+	}
+	var (
+		ip  enr.IP
+		udp enr.UDP
+		tcp enr.TCP
+	)
+	for _, entry := range []enr.Entry{&ip, &udp, &tcp} {
+		if err := r.Load(entry); err != nil && !enr.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return NewNode(PubkeyID((*ecdsa.PublicKey)(&pubkey)), net.IP(ip), uint16(udp), uint16(tcp)), nil
+}
+
+// LocalRecord maintains the signed node record of the local node. Records are
+// signed lazily and the sequence number is increased whenever the content of the
+// record changes.
+type LocalRecord struct {
+	key *ecdsa.PrivateKey
+
+	lock    sync.Mutex
+	seq     uint64
+	entries map[string]enr.Entry
+	record  *enr.Record // signed record of the current entries, nil if outdated
+}
+
+// NewLocalRecord creates the local node record manager for the given key. The
+// sequence number is initialised from the current time, so that records made
+// after a restart are newer than the ones seen by the network before.
+func NewLocalRecord(key *ecdsa.PrivateKey) *LocalRecord {
+	return &LocalRecord{
+		key:     key,
+		seq:     uint64(time.Now().UnixNano() / int64(time.Millisecond)),
+		entries: make(map[string]enr.Entry),
+	}
+}
+
+// Set adds or updates an entry of the local record. The sequence number is only
+// increased if the value of the entry actually changes.
+func (lr *LocalRecord) Set(e enr.Entry) {
+	lr.lock.Lock()
+	defer lr.lock.Unlock()
+
+	if old, ok := lr.entries[e.ENRKey()]; ok && sameEntry(old, e) {
+		return
+	}
+	lr.entries[e.ENRKey()] = e
+	lr.invalidate()
+}
+
+// Delete removes an entry from the local record.
+func (lr *LocalRecord) Delete(key string) {
+	lr.lock.Lock()
+	defer lr.lock.Unlock()
+
+	if _, ok := lr.entries[key]; ok {
+		delete(lr.entries, key)
+		lr.invalidate()
+	}
+}
+
+// SetEndpoint updates the IP address and ports of the local record. Zero values
+// remove the corresponding entries.
+func (lr *LocalRecord) SetEndpoint(ip net.IP, udp, tcp uint16) {
+	if ip == nil || ip.IsUnspecified() {
+		lr.Delete(enr.IP{}.ENRKey())
+	} else {
+		lr.Set(enr.IP(ip))
+	}
+	if udp == 0 {
+		lr.Delete(enr.UDP(0).ENRKey())
+	} else {
+		lr.Set(enr.UDP(udp))
+	}
+	if tcp == 0 {
+		lr.Delete(enr.TCP(0).ENRKey())
+	} else {
+		lr.Set(enr.TCP(tcp))
+	}
+}
+
+// Seq returns the sequence number of the current local record.
+func (lr *LocalRecord) Seq() uint64 {
+	lr.lock.Lock()
+	defer lr.lock.Unlock()
+
+	return lr.seq
+}
+
+// Record returns the current signed local record.
+func (lr *LocalRecord) Record() *enr.Record {
+	lr.lock.Lock()
+	defer lr.lock.Unlock()
+
+	if lr.record == nil {
+		r := new(enr.Record)
+		for _, e := range lr.entries {
+			r.Set(e)
+		}
+		r.SetSeq(lr.seq)
+		if err := enr.SignV4(r, lr.key); err != nil {
+			log.Error("Failed to sign local node record", "err", err)
+			return nil
+		}
+		lr.record = r
+	}
+	return lr.record
+}
+
+// invalidate drops the cached signed record and bumps the sequence number if the
+// previous one has been published already.
+func (lr *LocalRecord) invalidate() {
+	if lr.record != nil {
+		lr.seq++
+	}
+	lr.record = nil
+}
+
+// sameEntry reports whether two entries have the same encoded value.
+func sameEntry(a, b enr.Entry) bool {
+	blobA, errA := rlp.EncodeToBytes(a)
+	blobB, errB := rlp.EncodeToBytes(b)
+	return errA == nil && errB == nil && bytes.Equal(blobA, blobB)
+}
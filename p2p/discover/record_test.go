@@ -0,0 +1,97 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package discover
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rwdxchain/go-rwdxchaina/p2p/enr"
+)
+
+// Tests that node records survive a roundtrip through their text form and can be
+// used as node designators.
+func TestRecordText(t *testing.T) {
+	key := newkey()
+	local := NewLocalRecord(key)
+	local.SetEndpoint(net.IP{10, 3, 58, 6}, 30301, 30303)
+
+	text := RecordText(local.Record())
+	if !strings.HasPrefix(text, "enr:") {
+		t.Fatalf("record text without prefix: %s", text)
+	}
+	record, err := ParseRecord(text)
+	if err != nil {
+		t.Fatalf("failed to parse record: %v", err)
+	}
+	if !reflect.DeepEqual(record, local.Record()) {
+		t.Fatalf("record mismatch:\n  got:  %v\n  want: %v", record, local.Record())
+	}
+	node, err := ParseNode(text)
+	if err != nil {
+		t.Fatalf("failed to parse record as node: %v", err)
+	}
+	if want := NewNode(PubkeyID(&key.PublicKey), net.IP{10, 3, 58, 6}, 30301, 30303); !reflect.DeepEqual(node, want) {
+		t.Fatalf("node mismatch:\n  got:  %v\n  want: %v", node, want)
+	}
+	// Corrupt records must be rejected
+	for _, invalid := range []string{
+		text[4:],
+		"enr:" + text[5:],
+		text[:len(text)-2] + "AA",
+	} {
+		if _, err := ParseNode(invalid); err == nil {
+			t.Errorf("invalid record accepted: %s", invalid)
+		}
+	}
+}
+
+// Tests that the local record is only re-signed with a new sequence number if
+// its content changed since it was last published.
+func TestLocalRecordSeq(t *testing.T) {
+	local := NewLocalRecord(newkey())
+	local.SetEndpoint(net.IP{10, 0, 0, 1}, 30303, 30303)
+
+	seq := local.Seq()
+	if r := local.Record(); r.Seq() != seq {
+		t.Fatalf("record seq mismatch: got %d, want %d", r.Seq(), seq)
+	}
+	// Setting the same values doesn't change the record
+	local.SetEndpoint(net.IP{10, 0, 0, 1}, 30303, 30303)
+	if local.Seq() != seq {
+		t.Fatalf("seq increased without change: got %d, want %d", local.Seq(), seq)
+	}
+	// Changing the endpoint and adding entries increase the seq once per publication
+	local.SetEndpoint(net.IP{10, 0, 0, 2}, 30303, 30303)
+	local.Set(enr.WithEntry("test", uint(1)))
+	if r := local.Record(); r.Seq() != seq+1 {
+		t.Fatalf("record seq mismatch after change: got %d, want %d", r.Seq(), seq+1)
+	}
+	var value uint
+	if err := local.Record().Load(enr.WithEntry("test", &value)); err != nil || value != 1 {
+		t.Fatalf("entry mismatch: got %d (%v), want %d", value, err, 1)
+	}
+	local.Delete("test")
+	if r := local.Record(); r.Seq() != seq+2 {
+		t.Fatalf("record seq mismatch after delete: got %d, want %d", r.Seq(), seq+2)
+	}
+	if err := local.Record().Load(enr.WithEntry("test", &value)); !enr.IsNotFound(err) {
+		t.Fatalf("deleted entry still present: %v", err)
+	}
+}
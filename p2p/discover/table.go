@@ -35,6 +35,7 @@ import (
 	"github.com/rwdxchain/go-rwdxchaina/common"
 	"github.com/rwdxchain/go-rwdxchaina/crypto"
 	"github.com/rwdxchain/go-rwdxchaina/log"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/enr"
 	"github.com/rwdxchain/go-rwdxchaina/p2p/netutil"
 )
 
@@ -87,6 +88,7 @@ type Table struct {
 type transport interface {
 	ping(NodeID, *net.UDPAddr) error
 	findnode(toid NodeID, addr *net.UDPAddr, target NodeID) ([]*Node, error)
+	requestENR(toid NodeID, addr *net.UDPAddr) (*enr.Record, error)
 	close()
 }
 
@@ -148,6 +150,23 @@ func (tab *Table) Self() *Node {
 	return tab.self
 }
 
+// Ban retrieves the ban of a node recorded in the node database: the end of its
+// current ban, the time its bans are forgotten and the number of remembered bans.
+// The count is zero if the node has no ban on record.
+func (tab *Table) Ban(id NodeID) (until, forget time.Time, count uint) {
+	ban := tab.db.ban(id)
+	if ban == nil {
+		return time.Time{}, time.Time{}, 0
+	}
+	return time.Unix(int64(ban.Until), 0), time.Unix(int64(ban.Forget), 0), ban.Count
+}
+
+// SetBan records the ban of a node in the node database, so that it's enforced
+// across restarts.
+func (tab *Table) SetBan(id NodeID, until, forget time.Time, count uint) error {
+	return tab.db.updateBan(id, &nodeBan{Until: uint64(until.Unix()), Forget: uint64(forget.Unix()), Count: count})
+}
+
 // ReadRandomNodes fills the given slice with random nodes from the
 // table. It will not write the same node more than once. The nodes in
 // the slice are copies and can be modified by the caller.
@@ -251,6 +270,15 @@ func (tab *Table) Resolve(targetID NodeID) *Node {
 	return nil
 }
 
+// RequestENR retrieves the signed node record of the given node. The node must
+// be complete, i.e. its IP address and discovery port must be known.
+func (tab *Table) RequestENR(n *Node) (*enr.Record, error) {
+	if err := n.validateComplete(); err != nil {
+		return nil, err
+	}
+	return tab.net.requestENR(n.ID, n.addr())
+}
+
 // Lookup performs a network search for nodes close
 // to the given target. It approaches the target by querying
 // nodes that are closer to it on each iteration.
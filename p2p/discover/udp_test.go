@@ -36,6 +36,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/rwdxchain/go-rwdxchaina/common"
 	"github.com/rwdxchain/go-rwdxchaina/crypto"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/enr"
 	"github.com/rwdxchain/go-rwdxchaina/rlp"
 )
 
@@ -388,6 +389,82 @@ func TestUDP_successfulPing(t *testing.T) {
 	}
 }
 
+func TestUDP_enrRequest(t *testing.T) {
+	test := newUDPTest(t)
+	defer test.table.Close()
+
+	// ENR requests are not answered without an endpoint proof.
+	test.packetIn(errExpired, enrRequestPacket, &enrRequest{})
+	test.packetIn(errUnknownNode, enrRequestPacket, &enrRequest{Expiration: futureExp})
+
+	test.table.db.updateLastPongReceived(PubkeyID(&test.remotekey.PublicKey), time.Now())
+	test.packetIn(nil, enrRequestPacket, &enrRequest{Expiration: futureExp})
+	test.waitPacketOut(func(p *enrResponse) {
+		reqhash := test.sent[len(test.sent)-1][:macSize]
+		if !bytes.Equal(p.ReplyTok, reqhash) {
+			t.Errorf("got enrResponse.ReplyTok %x, want %x", p.ReplyTok, reqhash)
+		}
+		if p.Record.Seq() != test.udp.localRecord.Seq() {
+			t.Errorf("wrong record seq: got %d, want %d", p.Record.Seq(), test.udp.localRecord.Seq())
+		}
+		n, err := NodeFromRecord(&p.Record)
+		if err != nil {
+			t.Fatalf("invalid record: %v", err)
+		}
+		if n.ID != test.table.self.ID || n.UDP != test.table.self.UDP {
+			t.Errorf("record mismatch: got %v, want %v", n, test.table.self)
+		}
+	})
+}
+
+func TestUDP_requestENR(t *testing.T) {
+	test := newUDPTest(t)
+	defer test.table.Close()
+
+	rid := PubkeyID(&test.remotekey.PublicKey)
+	test.table.db.updateLastPingReceived(rid, time.Now())
+
+	request := func(record *enr.Record) (*enr.Record, error) {
+		type result struct {
+			record *enr.Record
+			err    error
+		}
+		resultc := make(chan result, 1)
+		go func() {
+			r, err := test.udp.requestENR(rid, test.remoteaddr)
+			resultc <- result{r, err}
+		}()
+		hash, _ := test.waitPacketOut(func(p *enrRequest) {})
+		test.packetIn(nil, enrResponsePacket, &enrResponse{ReplyTok: hash, Record: *record})
+
+		select {
+		case res := <-resultc:
+			return res.record, res.err
+		case <-time.After(5 * time.Second):
+			t.Fatal("requestENR did not return within 5 seconds")
+			return nil, nil
+		}
+	}
+	// The record of the remote node is accepted.
+	local := NewLocalRecord(test.remotekey)
+	local.SetEndpoint(test.remoteaddr.IP, uint16(test.remoteaddr.Port), 30303)
+	record, err := request(local.Record())
+	if err != nil {
+		t.Fatalf("requestENR error: %v", err)
+	}
+	if record.Seq() != local.Seq() {
+		t.Errorf("wrong record seq: got %d, want %d", record.Seq(), local.Seq())
+	}
+	var tcp enr.TCP
+	if err := record.Load(&tcp); err != nil || tcp != 30303 {
+		t.Errorf("wrong TCP port in record: got %d (%v), want %d", tcp, err, 30303)
+	}
+	// Records of other nodes are rejected.
+	if _, err := request(NewLocalRecord(newkey()).Record()); err != errRecordMismatch {
+		t.Errorf("error mismatch: got %v, want %v", err, errRecordMismatch)
+	}
+}
+
 var testPackets = []struct {
 	input      string
 	wantPacket interface{}
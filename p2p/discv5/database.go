@@ -24,7 +24,9 @@ import (
 	"crypto/rand"
 	"encoding/binary"
 	"fmt"
+	"net"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -64,6 +66,7 @@ var (
 	nodeDBDiscoverFindFails     = nodeDBDiscoverRoot + ":findfail"
 	nodeDBDiscoverLocalEndpoint = nodeDBDiscoverRoot + ":localendpoint"
 	nodeDBTopicRegTickets       = ":tickets"
+	nodeDBTopicReg              = ":topicreg:" // Prefix of the ads stored for a node, followed by the topic
 )
 
 // newNodeDB creates a new node database for storing and retrieving infos about
@@ -386,6 +389,57 @@ func (db *nodeDB) updateTopicRegTickets(id NodeID, issued, used uint32) error {
 	return db.lvl.Put(key, blob, nil)
 }
 
+// topicReg is an ad stored in the database.
+type topicReg struct {
+	node   *Node
+	topic  Topic
+	expire time.Time
+}
+
+// topicRegRLP is the database encoding of an ad.
+type topicRegRLP struct {
+	IP     net.IP
+	UDP    uint16
+	TCP    uint16
+	Expire uint64 // Unix time in seconds
+}
+
+// topicRegs retrieves all ads stored in the database.
+func (db *nodeDB) topicRegs() []topicReg {
+	it := db.lvl.NewIterator(util.BytesPrefix(nodeDBItemPrefix), nil)
+	defer it.Release()
+
+	var regs []topicReg
+	for it.Next() {
+		id, field := splitKey(it.Key())
+		if !strings.HasPrefix(field, nodeDBTopicReg) {
+			continue
+		}
+		var enc topicRegRLP
+		if err := rlp.DecodeBytes(it.Value(), &enc); err != nil {
+			log.Debug("Failed to decode topic registration", "id", id, "err", err)
+			continue
+		}
+		regs = append(regs, topicReg{
+			node:   NewNode(id, enc.IP, enc.UDP, enc.TCP),
+			topic:  Topic(field[len(nodeDBTopicReg):]),
+			expire: time.Unix(int64(enc.Expire), 0),
+		})
+	}
+	return regs
+}
+
+// updateTopicReg stores an ad of the given node, expiring at the given time.
+func (db *nodeDB) updateTopicReg(node *Node, topic Topic, expire time.Time) error {
+	enc := topicRegRLP{IP: node.IP, UDP: node.UDP, TCP: node.TCP, Expire: uint64(expire.Unix())}
+	return db.storeRLP(makeKey(node.ID, nodeDBTopicReg+string(topic)), &enc)
+}
+
+// deleteTopicReg removes an ad from the database.
+func (db *nodeDB) deleteTopicReg(id NodeID, topic Topic) error {
+	return db.lvl.Delete(makeKey(id, nodeDBTopicReg+string(topic)), nil)
+}
+
 // reads the next node record from the iterator, skipping over other
 // database entries.
 func nextNode(it iterator.Iterator) *Node {
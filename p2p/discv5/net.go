@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"sort"
 	"time"
 
 	"github.com/rwdxchain/go-rwdxchaina/common"
@@ -132,7 +133,7 @@ type timeoutEvent struct {
 	node *Node
 }
 
-func newNetwork(conn transport, ourPubkey ecdsa.PublicKey, dbPath string, netrestrict *netutil.Netlist) (*Network, error) {
+func newNetwork(conn transport, ourPubkey ecdsa.PublicKey, dbPath string, netrestrict *netutil.Netlist, topics TopicConfig) (*Network, error) {
 	ourID := PubkeyID(&ourPubkey)
 
 	var db *nodeDB
@@ -149,8 +150,8 @@ func newNetwork(conn transport, ourPubkey ecdsa.PublicKey, dbPath string, netres
 		conn:             conn,
 		netrestrict:      netrestrict,
 		tab:              tab,
-		topictab:         newTopicTable(db, tab.self),
-		ticketStore:      newTicketStore(),
+		topictab:         newTopicTable(db, tab.self, topics),
+		ticketStore:      newTicketStore(topics.withDefaults().RegisterInterval),
 		refreshReq:       make(chan []*Node),
 		refreshResp:      make(chan (<-chan struct{})),
 		closed:           make(chan struct{}),
@@ -165,6 +166,7 @@ func newNetwork(conn transport, ourPubkey ecdsa.PublicKey, dbPath string, netres
 		topicSearchReq:   make(chan topicSearchReq),
 		nodes:            make(map[NodeID]*Node),
 	}
+	net.topictab.restore(net.internNodeFromDB)
 	go net.loop()
 	return net, nil
 }
@@ -315,6 +317,32 @@ func (net *Network) SearchTopic(topic Topic, setPeriod <-chan time.Duration, fou
 	}
 }
 
+// Topics returns the topics advertised and searched by the local node as well
+// as those of which it stores ads, sorted by name.
+func (net *Network) Topics() []TopicInfo {
+	var topics []TopicInfo
+	net.reqTableOp(func() {
+		infos := net.topictab.topicInfos()
+		info := func(topic Topic) *TopicInfo {
+			if infos[topic] == nil {
+				infos[topic] = &TopicInfo{Topic: topic, Registrants: []*Node{}}
+			}
+			return infos[topic]
+		}
+		for topic := range net.ticketStore.tickets {
+			info(topic).Advertised = true
+		}
+		for topic := range net.ticketStore.searchTopicMap {
+			info(topic).Searched = true
+		}
+		for _, info := range infos {
+			topics = append(topics, *info)
+		}
+	})
+	sort.Slice(topics, func(i, j int) bool { return topics[i].Topic < topics[j].Topic })
+	return topics
+}
+
 func (net *Network) reqRefresh(nursery []*Node) <-chan struct{} {
 	select {
 	case net.refreshReq <- nursery:
@@ -566,7 +594,7 @@ loop:
 			}
 			net.ticketStore.searchLookupDone(res.target, res.nodes, func(n *Node, topic Topic) []byte {
 				if n.state != nil && n.state.canQuery {
-					return net.conn.send(n, topicQueryPacket, topicQuery{Topic: topic}) // TODO: set expiration
+					return net.conn.send(n, topicQueryPacket, &topicQuery{Topic: topic}) // TODO: set expiration
 				} else {
 					if n.state == unknown {
 						net.ping(n, n.addr())
@@ -597,7 +625,7 @@ loop:
 				}
 			}
 			for topic, t := range net.topictab.topics {
-				wp := t.wcl.nextWaitPeriod(tm)
+				wp := t.wcl.nextWaitPeriod(tm, net.topictab.config)
 				if printTestImgLogs {
 					fmt.Printf("*W %d %v %016x %d\n", tm/1000000, topic, net.tab.self.sha[:8], wp/1000000)
 				}
@@ -28,7 +28,7 @@ import (
 
 func TestNetwork_Lookup(t *testing.T) {
 	key, _ := crypto.GenerateKey()
-	network, err := newNetwork(lookupTestnet, key.PublicKey, "", nil)
+	network, err := newNetwork(lookupTestnet, key.PublicKey, "", nil, TopicConfig{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -270,6 +270,11 @@ func (s *simulation) randomNode() *Network {
 }
 
 func (s *simulation) launchNode(log bool) *Network {
+	return s.launchNodeConfig(TopicConfig{})
+}
+
+// launchNodeConfig launches a node with the given topic settings.
+func (s *simulation) launchNodeConfig(config TopicConfig) *Network {
 	var (
 		num = s.nodectr
 		key = newkey()
@@ -282,7 +287,7 @@ func (s *simulation) launchNode(log bool) *Network {
 	addr := &net.UDPAddr{IP: ip, Port: 33760}
 
 	transport := &simTransport{joinTime: time.Now(), sender: id, senderAddr: addr, sim: s, priv: key}
-	net, err := newNetwork(transport, key.PublicKey, "<no database>", nil)
+	net, err := newNetwork(transport, key.PublicKey, "<no database>", nil, config)
 	if err != nil {
 		panic("cannot launch new node: " + err.Error())
 	}
@@ -454,3 +459,55 @@ func (st *simTransport) sendPacket(remote NodeID, p ingressPacket) {
 		})
 	}
 }
+
+// Tests that searching nodes find the nodes advertising a topic.
+func TestSimTopicSearch(t *testing.T) {
+	sim := newSimulation()
+	defer sim.shutdown()
+
+	config := TopicConfig{MinWaitPeriod: time.Second, RegisterInterval: time.Second}
+	bootnode := sim.launchNodeConfig(config)
+	var nets []*Network
+	for i := 0; i < 6; i++ {
+		net := sim.launchNodeConfig(config)
+		if err := net.SetFallbackNodes([]*Node{bootnode.Self()}); err != nil {
+			t.Fatal(err)
+		}
+		nets = append(nets, net)
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	advertisers := map[NodeID]bool{nets[0].Self().ID: true, nets[1].Self().ID: true}
+	go nets[0].RegisterTopic(testTopic, stop)
+	go nets[1].RegisterTopic(testTopic, stop)
+
+	var (
+		searcher  = nets[5]
+		setPeriod = make(chan time.Duration, 1)
+		found     = make(chan *Node, 100)
+		timeout   = time.After(30 * time.Second)
+	)
+	setPeriod <- time.Second
+	go searcher.SearchTopic(testTopic, setPeriod, found, nil)
+	defer close(setPeriod)
+	for seen := make(map[NodeID]bool); len(seen) < len(advertisers); {
+		select {
+		case n := <-found:
+			if !advertisers[n.ID] {
+				t.Fatalf("found node %x, which doesn't advertise the topic", n.ID[:8])
+			}
+			seen[n.ID] = true
+		case <-timeout:
+			t.Fatal("advertising nodes not found")
+		}
+	}
+
+	topics := nets[0].Topics()
+	if len(topics) == 0 || topics[0].Topic != testTopic || !topics[0].Advertised || topics[0].Searched {
+		t.Errorf("wrong topics of advertising node: %+v", topics)
+	}
+	topics = searcher.Topics()
+	if len(topics) == 0 || topics[0].Topic != testTopic || topics[0].Advertised || !topics[0].Searched {
+		t.Errorf("wrong topics of searching node: %+v", topics)
+	}
+}
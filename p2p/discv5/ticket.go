@@ -36,7 +36,6 @@ const (
 	timeWindow          = 10 // * ticketTimeBucketLen
 	wantTicketsInWindow = 10
 	collectFrequency    = time.Second * 30
-	registerFrequency   = time.Second * 60
 	maxCollectDebt      = 10
 	maxRegisterDebt     = 5
 	keepTicketConst     = time.Minute * 10
@@ -137,6 +136,8 @@ type ticketStore struct {
 	nodes       map[*Node]*ticket
 	nodeLastReq map[*Node]reqInfo
 
+	registerInterval time.Duration // average interval between registrations of a topic
+
 	lastBucketFetched timeBucket
 	nextTicketCached  *ticketRef
 	nextTicketReg     mclock.AbsTime
@@ -161,15 +162,16 @@ type topicTickets struct {
 	nextReg    mclock.AbsTime
 }
 
-func newTicketStore() *ticketStore {
+func newTicketStore(registerInterval time.Duration) *ticketStore {
 	return &ticketStore{
-		radius:         make(map[Topic]*topicRadius),
-		tickets:        make(map[Topic]*topicTickets),
-		regSet:         make(map[Topic]struct{}),
-		nodes:          make(map[*Node]*ticket),
-		nodeLastReq:    make(map[*Node]reqInfo),
-		searchTopicMap: make(map[Topic]searchTopic),
-		queriesSent:    make(map[*Node]map[common.Hash]sentQuery),
+		registerInterval: registerInterval,
+		radius:           make(map[Topic]*topicRadius),
+		tickets:          make(map[Topic]*topicTickets),
+		regSet:           make(map[Topic]struct{}),
+		nodes:            make(map[*Node]*ticket),
+		nodeLastReq:      make(map[*Node]reqInfo),
+		searchTopicMap:   make(map[Topic]searchTopic),
+		queriesSent:      make(map[*Node]map[common.Hash]sentQuery),
 	}
 }
 
@@ -362,11 +364,11 @@ func (s *ticketStore) ticketRegistered(ref ticketRef) {
 
 	topic := ref.t.topics[ref.idx]
 	tickets := s.tickets[topic]
-	min := now - mclock.AbsTime(registerFrequency)*maxRegisterDebt
+	min := now - mclock.AbsTime(s.registerInterval)*maxRegisterDebt
 	if min > tickets.nextReg {
 		tickets.nextReg = min
 	}
-	tickets.nextReg += mclock.AbsTime(registerFrequency)
+	tickets.nextReg += mclock.AbsTime(s.registerInterval)
 	s.tickets[topic] = tickets
 
 	s.removeTicketRef(ref)
@@ -27,15 +27,69 @@ import (
 	"github.com/rwdxchain/go-rwdxchaina/log"
 )
 
-const (
-	maxEntries         = 10000
-	maxEntriesPerTopic = 50
+// TopicConfig holds the settings of topic advertisement. Zero fields are set
+// to the values of DefaultTopicConfig.
+type TopicConfig struct {
+	// Limits of the ads stored for other nodes. Ads expire after
+	// RegistrationExpiry unless their registrant renews them.
+	MaxEntries         int           `toml:",omitempty"`
+	MaxEntriesPerTopic int           `toml:",omitempty"`
+	RegistrationExpiry time.Duration `toml:",omitempty"`
+
+	// MinWaitPeriod is the minimum time registrants have to wait between
+	// obtaining a ticket and using it. The waiting time of a topic grows
+	// while it is registered more often than once per TargetRegInterval,
+	// which limits the rate of incoming registrations.
+	MinWaitPeriod     time.Duration `toml:",omitempty"`
+	TargetRegInterval time.Duration `toml:",omitempty"`
+
+	// RegisterInterval is the average interval between the registrations
+	// of each topic advertised by the local node.
+	RegisterInterval time.Duration `toml:",omitempty"`
+}
 
-	fallbackRegistrationExpiry = 1 * time.Hour
-)
+// DefaultTopicConfig contains the default topic advertisement settings.
+var DefaultTopicConfig = TopicConfig{
+	MaxEntries:         10000,
+	MaxEntriesPerTopic: 50,
+	RegistrationExpiry: time.Hour,
+	MinWaitPeriod:      time.Minute,
+	TargetRegInterval:  10 * time.Minute / 50,
+	RegisterInterval:   time.Minute,
+}
+
+func (c TopicConfig) withDefaults() TopicConfig {
+	if c.MaxEntries <= 0 {
+		c.MaxEntries = DefaultTopicConfig.MaxEntries
+	}
+	if c.MaxEntriesPerTopic <= 0 {
+		c.MaxEntriesPerTopic = DefaultTopicConfig.MaxEntriesPerTopic
+	}
+	if c.RegistrationExpiry <= 0 {
+		c.RegistrationExpiry = DefaultTopicConfig.RegistrationExpiry
+	}
+	if c.MinWaitPeriod <= 0 {
+		c.MinWaitPeriod = DefaultTopicConfig.MinWaitPeriod
+	}
+	if c.TargetRegInterval <= 0 {
+		c.TargetRegInterval = DefaultTopicConfig.TargetRegInterval
+	}
+	if c.RegisterInterval <= 0 {
+		c.RegisterInterval = DefaultTopicConfig.RegisterInterval
+	}
+	return c
+}
 
 type Topic string
 
+// TopicInfo describes a topic known to the local node.
+type TopicInfo struct {
+	Topic       Topic   `json:"topic"`
+	Advertised  bool    `json:"advertised"`  // the local node registers itself for the topic
+	Searched    bool    `json:"searched"`    // the local node searches nodes of the topic
+	Registrants []*Node `json:"registrants"` // nodes which registered at the local node
+}
+
 type topicEntry struct {
 	topic   Topic
 	fifoIdx uint64
@@ -69,6 +123,7 @@ type nodeInfo struct {
 
 type topicTable struct {
 	db                    *nodeDB
+	config                TopicConfig
 	self                  *Node
 	nodes                 map[*Node]*nodeInfo
 	topics                map[Topic]*topicInfo
@@ -78,12 +133,13 @@ type topicTable struct {
 	lastGarbageCollection mclock.AbsTime
 }
 
-func newTopicTable(db *nodeDB, self *Node) *topicTable {
+func newTopicTable(db *nodeDB, self *Node, config TopicConfig) *topicTable {
 	if printTestImgLogs {
 		fmt.Printf("*N %016x\n", self.sha[:8])
 	}
 	return &topicTable{
 		db:     db,
+		config: config.withDefaults(),
 		nodes:  make(map[*Node]*nodeInfo),
 		topics: make(map[Topic]*topicInfo),
 		self:   self,
@@ -112,7 +168,7 @@ func (t *topicTable) checkDeleteTopic(topic Topic) {
 	if ti == nil {
 		return
 	}
-	if len(ti.entries) == 0 && ti.wcl.hasMinimumWaitPeriod() {
+	if len(ti.entries) == 0 && ti.wcl.hasMinimumWaitPeriod(t.config) {
 		delete(t.topics, topic)
 		heap.Remove(&t.requested, ti.rqItem.index)
 	}
@@ -169,6 +225,30 @@ func (t *topicTable) getEntries(topic Topic) []*Node {
 }
 
 func (t *topicTable) addEntry(node *Node, topic Topic) {
+	tm := mclock.Now()
+	t.insertEntry(node, topic, tm+mclock.AbsTime(t.config.RegistrationExpiry))
+	t.topics[topic].wcl.registered(tm, t.config)
+}
+
+// restore loads the ads persisted in the database. Nodes are interned using
+// the given function.
+func (t *topicTable) restore(intern func(*Node) *Node) {
+	if t.db == nil {
+		return
+	}
+	now := time.Now()
+	for _, reg := range t.db.topicRegs() {
+		remaining := reg.expire.Sub(now)
+		if remaining <= 0 {
+			t.db.deleteTopicReg(reg.node.ID, reg.topic)
+			continue
+		}
+		t.insertEntry(intern(reg.node), reg.topic, mclock.Now()+mclock.AbsTime(remaining))
+	}
+}
+
+// insertEntry stores an ad of the given node which expires at the given time.
+func (t *topicTable) insertEntry(node *Node, topic Topic, expire mclock.AbsTime) {
 	n := t.getOrNewNode(node)
 	// clear previous entries by the same node
 	for _, e := range n.entries {
@@ -177,14 +257,13 @@ func (t *topicTable) addEntry(node *Node, topic Topic) {
 	// ***
 	n = t.getOrNewNode(node)
 
-	tm := mclock.Now()
 	te := t.getOrNewTopic(topic)
 
-	if len(te.entries) == maxEntriesPerTopic {
+	if len(te.entries) >= t.config.MaxEntriesPerTopic {
 		t.deleteEntry(te.getFifoTail())
 	}
 
-	if t.globalEntries == maxEntries {
+	if t.globalEntries >= uint64(t.config.MaxEntries) {
 		t.deleteEntry(t.leastRequested()) // not empty, no need to check for nil
 	}
 
@@ -194,15 +273,23 @@ func (t *topicTable) addEntry(node *Node, topic Topic) {
 		topic:   topic,
 		fifoIdx: fifoIdx,
 		node:    node,
-		expire:  tm + mclock.AbsTime(fallbackRegistrationExpiry),
+		expire:  expire,
 	}
 	if printTestImgLogs {
-		fmt.Printf("*+ %d %v %016x %016x\n", tm/1000000, topic, t.self.sha[:8], node.sha[:8])
+		fmt.Printf("*+ %d %v %016x %016x\n", mclock.Now()/1000000, topic, t.self.sha[:8], node.sha[:8])
 	}
 	te.entries[fifoIdx] = entry
 	n.entries[topic] = entry
 	t.globalEntries++
-	te.wcl.registered(tm)
+	t.storeEntry(entry)
+}
+
+// storeEntry persists an ad, converting its expiry time to wall clock time.
+func (t *topicTable) storeEntry(e *topicEntry) {
+	if t.db != nil {
+		expire := time.Now().Add(time.Duration(e.expire - mclock.Now()))
+		t.db.updateTopicReg(e.node, e.topic, expire)
+	}
 }
 
 // removes least requested element from the fifo
@@ -232,6 +319,26 @@ func (t *topicTable) deleteEntry(e *topicEntry) {
 		t.checkDeleteTopic(e.topic)
 	}
 	t.globalEntries--
+	if t.db != nil {
+		t.db.deleteTopicReg(e.node.ID, e.topic)
+	}
+}
+
+// topicInfos returns the topics of which the local node stores ads.
+func (t *topicTable) topicInfos() map[Topic]*TopicInfo {
+	t.collectGarbage()
+
+	infos := make(map[Topic]*TopicInfo, len(t.topics))
+	for topic, te := range t.topics {
+		info := &TopicInfo{Topic: topic, Registrants: make([]*Node, 0, len(te.entries))}
+		for idx := te.fifoTail; idx < te.fifoHead; idx++ {
+			if e := te.entries[idx]; e != nil {
+				info.Registrants = append(info.Registrants, e.node)
+			}
+		}
+		infos[topic] = info
+	}
+	return infos
 }
 
 // It is assumed that topics and waitPeriods have the same length.
@@ -263,7 +370,8 @@ func (t *topicTable) useTicket(node *Node, serialNo uint32, topics []Topic, idx
 			t.addEntry(node, topics[idx])
 		} else {
 			// if there is an active entry, don't move to the front of the FIFO but prolong expire time
-			e.expire = tm + mclock.AbsTime(fallbackRegistrationExpiry)
+			e.expire = tm + mclock.AbsTime(t.config.RegistrationExpiry)
+			t.storeEntry(e)
 		}
 		return true
 	}
@@ -290,7 +398,7 @@ func (t *topicTable) getTicket(node *Node, topics []Topic) *ticket {
 		if topic := t.topics[topic]; topic != nil {
 			waitPeriod = topic.wcl.waitPeriod
 		} else {
-			waitPeriod = minWaitPeriod
+			waitPeriod = t.config.MinWaitPeriod
 		}
 
 		tic.regTime[i] = now + mclock.AbsTime(waitPeriod)
@@ -323,37 +431,37 @@ func (t *topicTable) collectGarbage() {
 }
 
 const (
-	minWaitPeriod   = time.Minute
 	regTimeWindow   = 10 // seconds
 	avgnoRegTimeout = time.Minute * 10
-	// target average interval between two incoming ad requests
-	wcTargetRegInterval = time.Minute * 10 / maxEntriesPerTopic
 	//
 	wcTimeConst = time.Minute * 10
 )
 
-// initialization is not required, will set to minWaitPeriod at first registration
+// initialization is not required, will set to the minimum wait period at first registration
 type waitControlLoop struct {
 	lastIncoming mclock.AbsTime
 	waitPeriod   time.Duration
 }
 
-func (w *waitControlLoop) registered(tm mclock.AbsTime) {
-	w.waitPeriod = w.nextWaitPeriod(tm)
+func (w *waitControlLoop) registered(tm mclock.AbsTime, config TopicConfig) {
+	w.waitPeriod = w.nextWaitPeriod(tm, config)
 	w.lastIncoming = tm
 }
 
-func (w *waitControlLoop) nextWaitPeriod(tm mclock.AbsTime) time.Duration {
+// nextWaitPeriod computes the wait period of the next ticket. The period
+// targets config.TargetRegInterval as the average interval between two
+// incoming ad requests.
+func (w *waitControlLoop) nextWaitPeriod(tm mclock.AbsTime, config TopicConfig) time.Duration {
 	period := tm - w.lastIncoming
-	wp := time.Duration(float64(w.waitPeriod) * math.Exp((float64(wcTargetRegInterval)-float64(period))/float64(wcTimeConst)))
-	if wp < minWaitPeriod {
-		wp = minWaitPeriod
+	wp := time.Duration(float64(w.waitPeriod) * math.Exp((float64(config.TargetRegInterval)-float64(period))/float64(wcTimeConst)))
+	if wp < config.MinWaitPeriod {
+		wp = config.MinWaitPeriod
 	}
 	return wp
 }
 
-func (w *waitControlLoop) hasMinimumWaitPeriod() bool {
-	return w.nextWaitPeriod(mclock.Now()) == minWaitPeriod
+func (w *waitControlLoop) hasMinimumWaitPeriod(config TopicConfig) bool {
+	return w.nextWaitPeriod(mclock.Now(), config) == config.MinWaitPeriod
 }
 
 func noRegTimeout() time.Duration {
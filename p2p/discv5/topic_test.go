@@ -18,6 +18,10 @@ package discv5
 
 import (
 	"encoding/binary"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -69,3 +73,96 @@ func TestTopicRadius(t *testing.T) {
 		t.Errorf("Average/target ratio is too far from 1 (%v)", avgRel)
 	}
 }
+
+func newTopicTestNode(i byte) *Node {
+	return NewNode(NodeID{i}, net.IP{10, 0, 0, i}, 30303, 30303)
+}
+
+func topicEntryIDs(tab *topicTable, topic Topic) map[NodeID]bool {
+	ids := make(map[NodeID]bool)
+	for _, n := range tab.getEntries(topic) {
+		ids[n.ID] = true
+	}
+	return ids
+}
+
+func TestTopicTableLimits(t *testing.T) {
+	self := newTopicTestNode(0)
+	tab := newTopicTable(nil, self, TopicConfig{MaxEntries: 3, MaxEntriesPerTopic: 2})
+
+	tab.addEntry(newTopicTestNode(1), "a")
+	tab.addEntry(newTopicTestNode(2), "a")
+	tab.addEntry(newTopicTestNode(3), "a")
+	if ids := topicEntryIDs(tab, "a"); len(ids) != 2 || ids[NodeID{1}] {
+		t.Errorf("wrong entries after exceeding topic limit: %v", ids)
+	}
+	tab.addEntry(newTopicTestNode(4), "b")
+	tab.addEntry(newTopicTestNode(5), "b")
+	if tab.globalEntries != 3 {
+		t.Errorf("wrong number of entries after exceeding global limit: %d", tab.globalEntries)
+	}
+}
+
+func TestTopicTableWaitPeriod(t *testing.T) {
+	self := newTopicTestNode(0)
+	tab := newTopicTable(nil, self, TopicConfig{MinWaitPeriod: 5 * time.Second})
+
+	ticket := tab.getTicket(newTopicTestNode(1), []Topic{"a"})
+	if wait := time.Duration(ticket.regTime[0] - ticket.issueTime); wait != 5*time.Second {
+		t.Errorf("wrong wait period of unknown topic: %v", wait)
+	}
+	// Registrations more frequent than the target interval increase the
+	// wait period.
+	for i := byte(1); i <= 20; i++ {
+		tab.addEntry(newTopicTestNode(i), "a")
+	}
+	if wait := tab.topics["a"].wcl.nextWaitPeriod(mclock.Now(), tab.config); wait <= 5*time.Second {
+		t.Errorf("wait period didn't grow: %v", wait)
+	}
+}
+
+func TestTopicTablePersistency(t *testing.T) {
+	root, err := ioutil.TempDir("", "topicdb-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	self := newTopicTestNode(0)
+
+	db, err := newNodeDB(filepath.Join(root, "database"), Version, self.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tab := newTopicTable(db, self, TopicConfig{})
+	tab.addEntry(newTopicTestNode(1), "a")
+	tab.addEntry(newTopicTestNode(2), "a")
+	tab.addEntry(newTopicTestNode(3), "b")
+	tab.deleteEntry(tab.nodes[tab.getEntries("a")[0]].entries["a"])
+	// Expired ads are dropped when loading the table.
+	db.updateTopicReg(newTopicTestNode(4), "b", time.Now().Add(-time.Second))
+	db.close()
+
+	db, err = newNodeDB(filepath.Join(root, "database"), Version, self.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.close()
+	tab = newTopicTable(db, self, TopicConfig{})
+	interned := make(map[NodeID]*Node)
+	tab.restore(func(n *Node) *Node {
+		interned[n.ID] = n
+		return n
+	})
+	if len(interned) != 2 || tab.globalEntries != 2 {
+		t.Fatalf("wrong nodes restored: %v", interned)
+	}
+	if n := interned[NodeID{3}]; n == nil || !n.IP.Equal(net.IP{10, 0, 0, 3}) || n.TCP != 30303 {
+		t.Errorf("wrong node restored: %v", n)
+	}
+	if ids := topicEntryIDs(tab, "b"); len(ids) != 1 || !ids[NodeID{3}] {
+		t.Errorf("wrong entries of topic b: %v", ids)
+	}
+	if regs := db.topicRegs(); len(regs) != 2 {
+		t.Errorf("expired ad wasn't deleted: %d ads stored", len(regs))
+	}
+}
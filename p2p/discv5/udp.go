@@ -231,11 +231,23 @@ type udp struct {
 
 // ListenUDP returns a new table that listens for UDP packets on laddr.
 func ListenUDP(priv *ecdsa.PrivateKey, conn conn, realaddr *net.UDPAddr, nodeDBPath string, netrestrict *netutil.Netlist) (*Network, error) {
+	return ListenUDPWithConfig(priv, conn, realaddr, Config{NodeDBPath: nodeDBPath, NetRestrict: netrestrict})
+}
+
+// Config holds the settings of a discovery network.
+type Config struct {
+	NodeDBPath  string           // path of the node database, in-memory if empty
+	NetRestrict *netutil.Netlist // network whitelist, nil means unrestricted
+	Topics      TopicConfig      // topic advertisement settings
+}
+
+// ListenUDPWithConfig is like ListenUDP, but applies the given configuration.
+func ListenUDPWithConfig(priv *ecdsa.PrivateKey, conn conn, realaddr *net.UDPAddr, config Config) (*Network, error) {
 	transport, err := listenUDP(priv, conn, realaddr)
 	if err != nil {
 		return nil, err
 	}
-	net, err := newNetwork(transport, priv.PublicKey, nodeDBPath, netrestrict)
+	net, err := newNetwork(transport, priv.PublicKey, config.NodeDBPath, config.NetRestrict, config.Topics)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,401 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package dnsdisc implements node discovery via signed node lists published in
+// DNS TXT records (EIP-1459).
+package dnsdisc
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru"
+	"github.com/rwdxchain/go-rwdxchaina/log"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+)
+
+// Config holds the settings of a DNS discovery client.
+type Config struct {
+	Timeout         time.Duration // timeout of a single DNS lookup (default 5s)
+	RecheckInterval time.Duration // time between tree root update checks (default 30min)
+	CacheLimit      int           // maximum number of cached tree entries (default 1000)
+	Resolver        Resolver      // DNS resolver to use (default system resolver)
+	Logger          log.Logger    // logger of the client (default root logger)
+}
+
+// Resolver is a DNS resolver that can query TXT records.
+type Resolver interface {
+	LookupTXT(ctx context.Context, domain string) ([]string, error)
+}
+
+func (cfg Config) withDefaults() Config {
+	const (
+		defaultTimeout = 5 * time.Second
+		defaultRecheck = 30 * time.Minute
+		defaultCache   = 1000
+	)
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.RecheckInterval == 0 {
+		cfg.RecheckInterval = defaultRecheck
+	}
+	if cfg.CacheLimit == 0 {
+		cfg.CacheLimit = defaultCache
+	}
+	if cfg.Resolver == nil {
+		cfg.Resolver = net.DefaultResolver
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.Root()
+	}
+	return cfg
+}
+
+// Client discovers nodes by querying DNS servers. Trees added to the client are
+// synced in the background and their nodes are made available to the dialer.
+type Client struct {
+	cfg     Config
+	entries *lru.Cache
+
+	lock  sync.Mutex
+	trees map[string]*clientTree // synced trees, keyed by URL
+	nodes []*discover.Node       // nodes of all synced trees
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+}
+
+// clientTree is the sync state of a single tree.
+type clientTree struct {
+	link   *linkEntry
+	added  bool     // whether the tree was added explicitly rather than linked
+	links  []string // URLs of the trees linked from this tree
+	seq    uint
+	synced bool
+	nodes  []*discover.Node
+}
+
+// NewClient creates a DNS discovery client.
+func NewClient(cfg Config) *Client {
+	cfg = cfg.withDefaults()
+	cache, err := lru.New(cfg.CacheLimit)
+	if err != nil {
+		panic(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Client{
+		cfg:     cfg,
+		entries: cache,
+		trees:   make(map[string]*clientTree),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// AddTree adds the tree at the given URL to the set of trees synced in the
+// background. Trees linked from it are added automatically.
+func (c *Client) AddTree(url string) error {
+	le, err := parseLink(url)
+	if err != nil {
+		return err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.addTree(le).added = true
+	return nil
+}
+
+// addTree adds a tree if it's not known yet, returning its sync state. Trees
+// are identified by their URL, so trees published at the same domain under
+// different keys are kept apart. The lock must be held.
+func (c *Client) addTree(le *linkEntry) *clientTree {
+	url := le.url()
+	ct, ok := c.trees[url]
+	if !ok {
+		ct = &clientTree{link: le}
+		c.trees[url] = ct
+	}
+	return ct
+}
+
+// pruneTrees drops the linked trees which are no longer reachable from any of
+// the explicitly added trees. The lock must be held.
+func (c *Client) pruneTrees() {
+	reachable := make(map[string]bool)
+
+	var visit func(url string)
+	visit = func(url string) {
+		ct, ok := c.trees[url]
+		if !ok || reachable[url] {
+			return
+		}
+		reachable[url] = true
+		for _, link := range ct.links {
+			visit(link)
+		}
+	}
+	for url, ct := range c.trees {
+		if ct.added {
+			visit(url)
+		}
+	}
+	for url, ct := range c.trees {
+		if !reachable[url] {
+			c.cfg.Logger.Debug("Dropped unlinked DNS discovery tree", "tree", ct.link.url())
+			delete(c.trees, url)
+		}
+	}
+}
+
+// Start launches the background sync of the added trees.
+func (c *Client) Start() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.started {
+		return
+	}
+	c.started = true
+	c.wg.Add(1)
+	go c.loop()
+}
+
+// Close stops the background sync and waits for it to terminate.
+func (c *Client) Close() {
+	c.cancel()
+	c.wg.Wait()
+}
+
+// RandomNodes fills the given slice with random nodes of the synced trees,
+// returning the number of nodes written.
+func (c *Client) RandomNodes(buf []*discover.Node) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	perm := rand.Perm(len(c.nodes))
+	n := 0
+	for ; n < len(buf) && n < len(perm); n++ {
+		buf[n] = c.nodes[perm[n]]
+	}
+	return n
+}
+
+// SyncTree downloads and verifies the complete tree at the given URL. Links to
+// other trees are not followed.
+func (c *Client) SyncTree(url string) (*Tree, error) {
+	le, err := parseLink(url)
+	if err != nil {
+		return nil, err
+	}
+	return c.syncTree(c.ctx, le)
+}
+
+// loop periodically checks the roots of all trees for updates.
+func (c *Client) loop() {
+	defer c.wg.Done()
+
+	recheck := time.NewTicker(c.cfg.RecheckInterval)
+	defer recheck.Stop()
+
+	for {
+		c.refresh(c.ctx)
+		select {
+		case <-recheck.C:
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+// refresh syncs all trees whose root changed since the last check, including
+// any newly linked trees, drops the trees no longer linked and updates the set
+// of known nodes.
+func (c *Client) refresh(ctx context.Context) {
+	checked := make(map[string]bool)
+	for {
+		// Pick the next tree not checked in this round.
+		var ct *clientTree
+		c.lock.Lock()
+		for url, t := range c.trees {
+			if !checked[url] {
+				ct = t
+				break
+			}
+		}
+		c.lock.Unlock()
+		if ct == nil || ctx.Err() != nil {
+			break
+		}
+		checked[ct.link.url()] = true
+
+		if err := c.refreshTree(ctx, ct); err != nil {
+			c.cfg.Logger.Debug("DNS discovery tree sync failed", "tree", ct.link.url(), "err", err)
+		}
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.pruneTrees()
+
+	seen := make(map[discover.NodeID]bool)
+	c.nodes = c.nodes[:0]
+	for _, t := range c.trees {
+		for _, n := range t.nodes {
+			if !seen[n.ID] {
+				seen[n.ID] = true
+				c.nodes = append(c.nodes, n)
+			}
+		}
+	}
+}
+
+// refreshTree syncs a single tree if its root changed.
+func (c *Client) refreshTree(ctx context.Context, ct *clientTree) error {
+	root, err := c.resolveRoot(ctx, ct.link)
+	if err != nil {
+		return err
+	}
+	if ct.synced && root.seq == ct.seq {
+		return nil
+	}
+	t, err := c.syncEntries(ctx, ct.link, root)
+	if err != nil {
+		return err
+	}
+	var nodes []*discover.Node
+	for _, n := range t.Nodes() {
+		if !n.Incomplete() && n.TCP != 0 {
+			nodes = append(nodes, n)
+		}
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	ct.seq, ct.synced, ct.nodes, ct.links = root.seq, true, nodes, nil
+	for _, url := range t.Links() {
+		if le, err := parseLink(url); err == nil {
+			c.addTree(le)
+			ct.links = append(ct.links, le.url())
+		}
+	}
+	c.cfg.Logger.Debug("Synced DNS discovery tree", "tree", ct.link.url(), "seq", root.seq, "nodes", len(nodes))
+	return nil
+}
+
+// syncTree downloads the root and all entries of a tree.
+func (c *Client) syncTree(ctx context.Context, le *linkEntry) (*Tree, error) {
+	root, err := c.resolveRoot(ctx, le)
+	if err != nil {
+		return nil, err
+	}
+	return c.syncEntries(ctx, le, root)
+}
+
+// syncEntries downloads all entries below the given root.
+func (c *Client) syncEntries(ctx context.Context, le *linkEntry, root *rootEntry) (*Tree, error) {
+	t := &Tree{root: root, entries: make(map[string]entry)}
+	if err := c.syncAll(ctx, le.domain, root.eroot, false, t.entries); err != nil {
+		return nil, err
+	}
+	if err := c.syncAll(ctx, le.domain, root.lroot, true, t.entries); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// syncAll downloads the entry with the given hash and all entries below it.
+func (c *Client) syncAll(ctx context.Context, domain, hash string, links bool, dest map[string]entry) error {
+	if _, ok := dest[hash]; ok {
+		return nil
+	}
+	e, err := c.resolveEntry(ctx, domain, hash, links)
+	if err != nil {
+		return err
+	}
+	dest[hash] = e
+	if branch, ok := e.(*branchEntry); ok {
+		for _, child := range branch.children {
+			if err := c.syncAll(ctx, domain, child, links, dest); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolveRoot retrieves the root of a tree and verifies its signature.
+func (c *Client) resolveRoot(ctx context.Context, le *linkEntry) (*rootEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	txts, err := c.cfg.Resolver.LookupTXT(ctx, le.domain)
+	if err != nil {
+		return nil, err
+	}
+	for _, txt := range txts {
+		if !strings.HasPrefix(txt, rootPrefix) {
+			continue
+		}
+		root, err := parseRoot(txt)
+		if err != nil {
+			return nil, nameError{le.domain, err}
+		}
+		if !root.verifySignature(le.pubkey) {
+			return nil, nameError{le.domain, entryError{"root", errInvalidSig}}
+		}
+		return root, nil
+	}
+	return nil, nameError{le.domain, errNoRoot}
+}
+
+// resolveEntry retrieves a tree entry from the cache or DNS, verifying that its
+// content matches the hash it was requested by.
+func (c *Client) resolveEntry(ctx context.Context, domain, hash string, links bool) (entry, error) {
+	if e, ok := c.entries.Get(hash); ok {
+		return e.(entry), checkEntryType(e.(entry), links)
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	name := hash + "." + domain
+	txts, err := c.cfg.Resolver.LookupTXT(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, txt := range txts {
+		e, err := parseEntry(txt, links)
+		if err == errUnknownEntry {
+			continue
+		}
+		if err != nil {
+			return nil, nameError{name, err}
+		}
+		if hashText(txt) != hash {
+			return nil, nameError{name, errHashMismatch}
+		}
+		c.entries.Add(hash, e)
+		return e, nil
+	}
+	return nil, nameError{name, errNoEntry}
+}
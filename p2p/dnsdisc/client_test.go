@@ -0,0 +1,252 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dnsdisc
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/enr"
+)
+
+// mapResolver is an in-memory resolver serving TXT records from a map.
+type mapResolver map[string]string
+
+func (mr mapResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if record, ok := mr[name]; ok {
+		return []string{record}, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (mr mapResolver) add(records map[string]string) {
+	for name, content := range records {
+		mr[name] = content
+	}
+}
+
+// Tests that a published tree can be synced and verified.
+func TestClientSyncTree(t *testing.T) {
+	var (
+		key      = testKey(t)
+		records  = testRecords(t, 30)
+		tree, _  = MakeTree(1, records, nil)
+		url, _   = tree.Sign(key, "nodes.example.org")
+		resolver = make(mapResolver)
+	)
+	resolver.add(tree.ToTXT("nodes.example.org"))
+
+	client := NewClient(Config{Resolver: resolver})
+	synced, err := client.SyncTree(url)
+	if err != nil {
+		t.Fatalf("failed to sync tree: %v", err)
+	}
+	if synced.Seq() != 1 || synced.Signature() != tree.Signature() {
+		t.Fatalf("root mismatch: have seq %d sig %s", synced.Seq(), synced.Signature())
+	}
+	if !reflect.DeepEqual(sortedIDs(synced.Nodes()), sortedIDs(tree.Nodes())) {
+		t.Fatalf("synced nodes mismatch")
+	}
+	// Trees signed by another key are rejected.
+	other := testKey(t)
+	otherURL := (&linkEntry{domain: "nodes.example.org", pubkey: &other.PublicKey}).url()
+	if _, err := client.SyncTree(otherURL); err == nil {
+		t.Fatalf("tree with invalid signature accepted")
+	}
+}
+
+// Tests that tampered entries are detected.
+func TestClientSyncTreeBadEntry(t *testing.T) {
+	var (
+		key      = testKey(t)
+		tree, _  = MakeTree(1, testRecords(t, 3), nil)
+		url, _   = tree.Sign(key, "nodes.example.org")
+		resolver = make(mapResolver)
+	)
+	resolver.add(tree.ToTXT("nodes.example.org"))
+
+	// Replace one of the node records by another one.
+	replacement := testRecords(t, 1)[0]
+	for name, content := range resolver {
+		if content[:len(enrPrefix)] == enrPrefix {
+			resolver[name] = discover.RecordText(replacement)
+			break
+		}
+	}
+	_, err := NewClient(Config{Resolver: resolver}).SyncTree(url)
+	if nerr, ok := err.(nameError); !ok || nerr.err != errHashMismatch {
+		t.Fatalf("error mismatch: have %v, want %v", err, errHashMismatch)
+	}
+}
+
+// Tests that the background sync follows links, picks up updated trees and
+// supplies the nodes of all trees.
+func TestClientRefresh(t *testing.T) {
+	var (
+		resolver   = make(mapResolver)
+		linkedKey  = testKey(t)
+		linked, _  = MakeTree(1, testRecords(t, 5), nil)
+		linkURL, _ = linked.Sign(linkedKey, "linked.example.org")
+		key        = testKey(t)
+		records    = testRecords(t, 10)
+		tree, _    = MakeTree(1, records, []string{linkURL})
+		url, _     = tree.Sign(key, "nodes.example.org")
+	)
+	resolver.add(linked.ToTXT("linked.example.org"))
+	resolver.add(tree.ToTXT("nodes.example.org"))
+
+	client := NewClient(Config{Resolver: resolver})
+	if err := client.AddTree(url); err != nil {
+		t.Fatalf("failed to add tree: %v", err)
+	}
+	client.refresh(context.Background())
+
+	want := append(tree.Nodes(), linked.Nodes()...)
+	if have := randomNodes(client); !reflect.DeepEqual(sortedIDs(have), sortedIDs(want)) {
+		t.Fatalf("node count mismatch after sync: have %d, want %d", len(have), len(want))
+	}
+	// Publish an update of the main tree, dropping some nodes.
+	update, _ := MakeTree(2, records[:4], []string{linkURL})
+	update.Sign(key, "nodes.example.org")
+	resolver.add(update.ToTXT("nodes.example.org"))
+	client.refresh(context.Background())
+
+	want = append(update.Nodes(), linked.Nodes()...)
+	if have := randomNodes(client); !reflect.DeepEqual(sortedIDs(have), sortedIDs(want)) {
+		t.Fatalf("node count mismatch after update: have %d, want %d", len(have), len(want))
+	}
+	// The limited buffer must be filled with distinct nodes.
+	buf := make([]*discover.Node, 3)
+	if n := client.RandomNodes(buf); n != 3 {
+		t.Fatalf("random node count mismatch: have %d, want %d", n, 3)
+	}
+	if buf[0].ID == buf[1].ID || buf[1].ID == buf[2].ID || buf[0].ID == buf[2].ID {
+		t.Fatalf("duplicate random nodes returned")
+	}
+}
+
+// Tests that trees published at the same domain under different keys are kept
+// apart instead of sharing the key of the first one added.
+func TestClientRefreshSameDomain(t *testing.T) {
+	var (
+		resolver = make(mapResolver)
+		key      = testKey(t)
+		tree, _  = MakeTree(1, testRecords(t, 3), nil)
+		url, _   = tree.Sign(key, "nodes.example.org")
+		other    = testKey(t)
+		otherURL = (&linkEntry{domain: "nodes.example.org", pubkey: &other.PublicKey}).url()
+	)
+	resolver.add(tree.ToTXT("nodes.example.org"))
+
+	client := NewClient(Config{Resolver: resolver})
+	if err := client.AddTree(otherURL); err != nil {
+		t.Fatalf("failed to add tree: %v", err)
+	}
+	if err := client.AddTree(url); err != nil {
+		t.Fatalf("failed to add tree: %v", err)
+	}
+	client.refresh(context.Background())
+
+	if len(client.trees) != 2 {
+		t.Fatalf("tree count mismatch: have %d, want 2", len(client.trees))
+	}
+	if have := randomNodes(client); !reflect.DeepEqual(sortedIDs(have), sortedIDs(tree.Nodes())) {
+		t.Fatalf("node count mismatch: have %d, want %d", len(have), len(tree.Nodes()))
+	}
+}
+
+// Tests that linked trees are dropped once no tree links to them anymore.
+func TestClientRefreshUnlink(t *testing.T) {
+	var (
+		resolver   = make(mapResolver)
+		linkedKey  = testKey(t)
+		linked, _  = MakeTree(1, testRecords(t, 5), nil)
+		linkURL, _ = linked.Sign(linkedKey, "linked.example.org")
+		key        = testKey(t)
+		records    = testRecords(t, 4)
+		tree, _    = MakeTree(1, records, []string{linkURL})
+		url, _     = tree.Sign(key, "nodes.example.org")
+	)
+	resolver.add(linked.ToTXT("linked.example.org"))
+	resolver.add(tree.ToTXT("nodes.example.org"))
+
+	client := NewClient(Config{Resolver: resolver})
+	client.AddTree(url)
+	client.refresh(context.Background())
+
+	if len(client.trees) != 2 {
+		t.Fatalf("tree count mismatch after sync: have %d, want 2", len(client.trees))
+	}
+	// Publish an update of the main tree without the link.
+	update, _ := MakeTree(2, records, nil)
+	update.Sign(key, "nodes.example.org")
+	resolver.add(update.ToTXT("nodes.example.org"))
+	client.refresh(context.Background())
+
+	if _, ok := client.trees[linkURL]; ok || len(client.trees) != 1 {
+		t.Fatalf("unlinked tree not dropped: have %d trees", len(client.trees))
+	}
+	if have := randomNodes(client); !reflect.DeepEqual(sortedIDs(have), sortedIDs(update.Nodes())) {
+		t.Fatalf("node count mismatch after update: have %d, want %d", len(have), len(update.Nodes()))
+	}
+}
+
+// Tests that records not describing a dialable node are not supplied.
+func TestClientRefreshIncomplete(t *testing.T) {
+	var (
+		resolver = make(mapResolver)
+		key      = testKey(t)
+		records  = testRecords(t, 2)
+		noIP     = new(enr.Record)
+	)
+	noIP.Set(enr.TCP(30303))
+	enr.SignV4(noIP, testKey(t))
+	tree, err := MakeTree(1, append(records, noIP), nil)
+	if err != nil {
+		t.Fatalf("failed to create tree: %v", err)
+	}
+	url, _ := tree.Sign(key, "nodes.example.org")
+	resolver.add(tree.ToTXT("nodes.example.org"))
+
+	client := NewClient(Config{Resolver: resolver})
+	client.AddTree(url)
+	client.refresh(context.Background())
+
+	if have := randomNodes(client); len(have) != len(records) {
+		t.Fatalf("node count mismatch: have %d, want %d", len(have), len(records))
+	}
+}
+
+func randomNodes(c *Client) []*discover.Node {
+	buf := make([]*discover.Node, 100)
+	return buf[:c.RandomNodes(buf)]
+}
+
+func sortedIDs(nodes []*discover.Node) []discover.NodeID {
+	ids := make([]discover.NodeID, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.ID
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return string(ids[i][:]) < string(ids[j][:])
+	})
+	return ids
+}
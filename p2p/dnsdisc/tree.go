@@ -0,0 +1,449 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dnsdisc
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/enr"
+)
+
+const (
+	rootPrefix   = "enrtree-root:v1"
+	linkPrefix   = "enrtree://"
+	branchPrefix = "enrtree-branch:"
+	enrPrefix    = "enr:"
+)
+
+// maxChildren is the maximum number of child hashes of a branch entry, which
+// keeps every entry within the size limits of a single DNS TXT record.
+const maxChildren = 13
+
+// sigLength is the length of root signatures in the [R || S || V] format.
+const sigLength = 65
+
+var (
+	// b32format is the encoding of entry hashes and public keys. DNS names are
+	// case-insensitive, so base64 can't be used.
+	b32format = base32.StdEncoding.WithPadding(base32.NoPadding)
+	b64format = base64.RawURLEncoding
+)
+
+// Errors returned when parsing and verifying tree entries.
+var (
+	errUnknownEntry  = errors.New("unknown entry type")
+	errNoPubkey      = errors.New("missing public key")
+	errBadPubkey     = errors.New("invalid public key")
+	errInvalidENR    = errors.New("invalid node record")
+	errInvalidChild  = errors.New("invalid child hash")
+	errInvalidSig    = errors.New("invalid base64 signature")
+	errSyntax        = errors.New("invalid syntax")
+	errNoRoot        = errors.New("no valid root found")
+	errNoEntry       = errors.New("no valid tree entry found")
+	errHashMismatch  = errors.New("hash mismatch")
+	errENRInLinkTree = errors.New("enr entry in link tree")
+	errLinkInENRTree = errors.New("link entry in ENR tree")
+)
+
+// nameError wraps an error with the DNS name it occurred at.
+type nameError struct {
+	name string
+	err  error
+}
+
+func (err nameError) Error() string {
+	if ee, ok := err.err.(entryError); ok {
+		return fmt.Sprintf("invalid %s entry at %s: %v", ee.typ, err.name, ee.err)
+	}
+	return err.name + ": " + err.err.Error()
+}
+
+// entryError wraps an error with the type of entry it occurred in.
+type entryError struct {
+	typ string
+	err error
+}
+
+func (err entryError) Error() string {
+	return fmt.Sprintf("invalid %s entry: %v", err.typ, err.err)
+}
+
+// Tree is a merkle tree of node records and links to other trees, stored in DNS
+// TXT records under a domain. The root of the tree is signed by the publisher.
+type Tree struct {
+	root    *rootEntry
+	entries map[string]entry
+}
+
+// MakeTree creates a tree containing the given node records and links to other
+// trees. The records are sorted by node ID to make the tree deterministic.
+func MakeTree(seq uint, records []*enr.Record, links []string) (*Tree, error) {
+	// Sort the records and validate that they describe usable nodes.
+	type sortedRecord struct {
+		id     discover.NodeID
+		record *enr.Record
+	}
+	sorted := make([]sortedRecord, len(records))
+	for i, r := range records {
+		n, err := discover.NodeFromRecord(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node record %d: %v", i, err)
+		}
+		sorted[i] = sortedRecord{n.ID, r}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].id[:], sorted[j].id[:]) < 0
+	})
+	enrEntries := make([]entry, len(sorted))
+	for i, r := range sorted {
+		enrEntries[i] = &enrEntry{r.record}
+	}
+	// Parse the links so they can be verified.
+	linkEntries := make([]entry, len(links))
+	for i, url := range links {
+		le, err := parseLink(url)
+		if err != nil {
+			return nil, err
+		}
+		linkEntries[i] = le
+	}
+	// Create the intermediate nodes of both subtrees.
+	t := &Tree{entries: make(map[string]entry)}
+	eroot := t.build(enrEntries)
+	t.entries[subdomain(eroot)] = eroot
+	lroot := t.build(linkEntries)
+	t.entries[subdomain(lroot)] = lroot
+	t.root = &rootEntry{seq: seq, eroot: subdomain(eroot), lroot: subdomain(lroot)}
+	return t, nil
+}
+
+// build creates the branch entries above the given entries, returning the root
+// of the subtree.
+func (t *Tree) build(entries []entry) entry {
+	if len(entries) == 1 {
+		return entries[0]
+	}
+	if len(entries) <= maxChildren {
+		hashes := make([]string, len(entries))
+		for i, e := range entries {
+			hashes[i] = subdomain(e)
+			t.entries[hashes[i]] = e
+		}
+		return &branchEntry{hashes}
+	}
+	var subtrees []entry
+	for len(entries) > 0 {
+		n := maxChildren
+		if len(entries) < n {
+			n = len(entries)
+		}
+		sub := t.build(entries[:n])
+		entries = entries[n:]
+		subtrees = append(subtrees, sub)
+		t.entries[subdomain(sub)] = sub
+	}
+	return t.build(subtrees)
+}
+
+// Sign signs the tree with the given private key and returns the URL under which
+// the tree can be found when published at the given domain.
+func (t *Tree) Sign(key *ecdsa.PrivateKey, domain string) (string, error) {
+	sig, err := crypto.Sign(t.root.sigHash(), key)
+	if err != nil {
+		return "", err
+	}
+	t.root.sig = sig
+	link := &linkEntry{domain: domain, pubkey: &key.PublicKey}
+	return link.url(), nil
+}
+
+// SetSignature assigns a previously created signature to the tree, verifying it
+// against the public key of the publisher.
+func (t *Tree) SetSignature(pubkey *ecdsa.PublicKey, signature string) error {
+	sig, err := b64format.DecodeString(signature)
+	if err != nil || len(sig) != sigLength {
+		return errInvalidSig
+	}
+	root := *t.root
+	root.sig = sig
+	if !root.verifySignature(pubkey) {
+		return errInvalidSig
+	}
+	t.root.sig = sig
+	return nil
+}
+
+// Seq returns the sequence number of the tree.
+func (t *Tree) Seq() uint {
+	return t.root.seq
+}
+
+// Signature returns the signature of the tree root in its text form.
+func (t *Tree) Signature() string {
+	return b64format.EncodeToString(t.root.sig)
+}
+
+// ToTXT returns all TXT records of the tree, keyed by their DNS name under the
+// given domain.
+func (t *Tree) ToTXT(domain string) map[string]string {
+	records := map[string]string{domain: t.root.String()}
+	for hash, e := range t.entries {
+		name := hash
+		if domain != "" {
+			name = hash + "." + domain
+		}
+		records[name] = e.String()
+	}
+	return records
+}
+
+// Links returns the URLs of all trees linked from the tree.
+func (t *Tree) Links() []string {
+	var links []string
+	for _, e := range t.entries {
+		if le, ok := e.(*linkEntry); ok {
+			links = append(links, le.url())
+		}
+	}
+	sort.Strings(links)
+	return links
+}
+
+// Records returns all node records contained in the tree.
+func (t *Tree) Records() []*enr.Record {
+	var records []*enr.Record
+	for _, e := range t.entries {
+		if ee, ok := e.(*enrEntry); ok {
+			records = append(records, ee.record)
+		}
+	}
+	return records
+}
+
+// Nodes returns the nodes described by the records of the tree.
+func (t *Tree) Nodes() []*discover.Node {
+	var nodes []*discover.Node
+	for _, r := range t.Records() {
+		if n, err := discover.NodeFromRecord(r); err == nil {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// entry is a single TXT record of a tree.
+type entry interface {
+	fmt.Stringer
+}
+
+type (
+	rootEntry struct {
+		eroot string
+		lroot string
+		seq   uint
+		sig   []byte
+	}
+	branchEntry struct {
+		children []string
+	}
+	enrEntry struct {
+		record *enr.Record
+	}
+	linkEntry struct {
+		domain string
+		pubkey *ecdsa.PublicKey
+	}
+)
+
+// subdomain returns the name of the TXT record holding the given entry, which
+// is the truncated hash of its text form.
+func subdomain(e entry) string {
+	return hashText(e.String())
+}
+
+// hashText returns the truncated hash of an entry in its text form.
+func hashText(text string) string {
+	h := crypto.Keccak256([]byte(text))
+	return b32format.EncodeToString(h[:16])
+}
+
+func (e *rootEntry) String() string {
+	return fmt.Sprintf(rootPrefix+" e=%s l=%s seq=%d sig=%s", e.eroot, e.lroot, e.seq, b64format.EncodeToString(e.sig))
+}
+
+// sigHash returns the hash of the root content covered by the signature.
+func (e *rootEntry) sigHash() []byte {
+	return crypto.Keccak256([]byte(fmt.Sprintf(rootPrefix+" e=%s l=%s seq=%d", e.eroot, e.lroot, e.seq)))
+}
+
+// verifySignature checks that the root was signed by the given key.
+func (e *rootEntry) verifySignature(pubkey *ecdsa.PublicKey) bool {
+	if len(e.sig) != sigLength {
+		return false
+	}
+	return crypto.VerifySignature(crypto.FromECDSAPub(pubkey), e.sigHash(), e.sig[:sigLength-1])
+}
+
+func (e *branchEntry) String() string {
+	return branchPrefix + strings.Join(e.children, ",")
+}
+
+func (e *enrEntry) String() string {
+	return discover.RecordText(e.record)
+}
+
+func (e *linkEntry) String() string {
+	return e.url()
+}
+
+// url returns the text form of a link, which is also the URL of the tree.
+func (e *linkEntry) url() string {
+	return linkPrefix + b32format.EncodeToString(crypto.CompressPubkey(e.pubkey)) + "@" + e.domain
+}
+
+// parseEntry parses any entry except the root. The link and node record entry
+// types are only accepted in the subtree they are allowed in.
+func parseEntry(text string, links bool) (entry, error) {
+	switch {
+	case strings.HasPrefix(text, linkPrefix):
+		if !links {
+			return nil, errLinkInENRTree
+		}
+		return parseLink(text)
+	case strings.HasPrefix(text, branchPrefix):
+		return parseBranch(text)
+	case strings.HasPrefix(text, enrPrefix):
+		if links {
+			return nil, errENRInLinkTree
+		}
+		return parseENR(text)
+	default:
+		return nil, errUnknownEntry
+	}
+}
+
+// checkEntryType verifies that an entry is allowed in the link or node record
+// subtree.
+func checkEntryType(e entry, links bool) error {
+	switch e.(type) {
+	case *linkEntry:
+		if !links {
+			return errLinkInENRTree
+		}
+	case *enrEntry:
+		if links {
+			return errENRInLinkTree
+		}
+	}
+	return nil
+}
+
+func parseRoot(text string) (*rootEntry, error) {
+	var (
+		e      rootEntry
+		sig    string
+		prefix string
+	)
+	if _, err := fmt.Sscanf(text, "%s e=%s l=%s seq=%d sig=%s", &prefix, &e.eroot, &e.lroot, &e.seq, &sig); err != nil || prefix != rootPrefix {
+		return nil, entryError{"root", errSyntax}
+	}
+	if !isValidHash(e.eroot) || !isValidHash(e.lroot) {
+		return nil, entryError{"root", errInvalidChild}
+	}
+	s, err := b64format.DecodeString(sig)
+	if err != nil || len(s) != sigLength {
+		return nil, entryError{"root", errInvalidSig}
+	}
+	e.sig = s
+	return &e, nil
+}
+
+func parseLink(text string) (*linkEntry, error) {
+	if !strings.HasPrefix(text, linkPrefix) {
+		return nil, entryError{"link", errSyntax}
+	}
+	text = text[len(linkPrefix):]
+	pos := strings.IndexByte(text, '@')
+	if pos == -1 {
+		return nil, entryError{"link", errNoPubkey}
+	}
+	keystring, domain := text[:pos], text[pos+1:]
+	if domain == "" {
+		return nil, entryError{"link", errSyntax}
+	}
+	keybytes, err := b32format.DecodeString(strings.ToUpper(keystring))
+	if err != nil {
+		return nil, entryError{"link", errBadPubkey}
+	}
+	key, err := crypto.DecompressPubkey(keybytes)
+	if err != nil {
+		return nil, entryError{"link", errBadPubkey}
+	}
+	return &linkEntry{domain: domain, pubkey: key}, nil
+}
+
+func parseBranch(text string) (*branchEntry, error) {
+	text = text[len(branchPrefix):]
+	if text == "" {
+		return &branchEntry{}, nil
+	}
+	children := strings.Split(text, ",")
+	for _, c := range children {
+		if !isValidHash(c) {
+			return nil, entryError{"branch", errInvalidChild}
+		}
+	}
+	return &branchEntry{children}, nil
+}
+
+func parseENR(text string) (*enrEntry, error) {
+	r, err := discover.ParseRecord(text)
+	if err != nil {
+		return nil, entryError{"enr", errInvalidENR}
+	}
+	return &enrEntry{r}, nil
+}
+
+// isValidHash reports whether s is a well-formed entry hash.
+func isValidHash(s string) bool {
+	dlen := b32format.DecodedLen(len(s))
+	if dlen < 12 || dlen > 32 || strings.ContainsAny(s, "\n\r") {
+		return false
+	}
+	buf := make([]byte, 32)
+	_, err := b32format.Decode(buf, []byte(s))
+	return err == nil
+}
+
+// ParseURL parses the URL of a tree, returning its domain and the public key of
+// its publisher.
+func ParseURL(url string) (domain string, pubkey *ecdsa.PublicKey, err error) {
+	le, err := parseLink(url)
+	if err != nil {
+		return "", nil, err
+	}
+	return le.domain, le.pubkey, nil
+}
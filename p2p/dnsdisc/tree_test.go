@@ -0,0 +1,156 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dnsdisc
+
+import (
+	"crypto/ecdsa"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/enr"
+)
+
+// testRecords creates a number of signed node records with random keys.
+func testRecords(t *testing.T, n int) []*enr.Record {
+	records := make([]*enr.Record, n)
+	for i := range records {
+		key, _ := crypto.GenerateKey()
+		r := new(enr.Record)
+		r.Set(enr.IP(net.IP{10, 0, byte(i >> 8), byte(i)}))
+		r.Set(enr.TCP(30303))
+		r.Set(enr.UDP(30303))
+		if err := enr.SignV4(r, key); err != nil {
+			t.Fatalf("failed to sign record: %v", err)
+		}
+		records[i] = r
+	}
+	return records
+}
+
+func testKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return key
+}
+
+// Tests that trees can be created, signed and published in TXT records, with
+// every entry fitting into a single record.
+func TestTreeToTXT(t *testing.T) {
+	var (
+		records = testRecords(t, 40)
+		other   = testKey(t)
+		link    = (&linkEntry{domain: "other.example.org", pubkey: &other.PublicKey}).url()
+	)
+	tree, err := MakeTree(3, records, []string{link})
+	if err != nil {
+		t.Fatalf("failed to create tree: %v", err)
+	}
+	key := testKey(t)
+	url, err := tree.Sign(key, "nodes.example.org")
+	if err != nil {
+		t.Fatalf("failed to sign tree: %v", err)
+	}
+	domain, pubkey, err := ParseURL(url)
+	if err != nil {
+		t.Fatalf("failed to parse tree URL %q: %v", url, err)
+	}
+	if domain != "nodes.example.org" || !reflect.DeepEqual(pubkey, &key.PublicKey) {
+		t.Fatalf("tree URL mismatch: have %s %x", domain, crypto.FromECDSAPub(pubkey))
+	}
+	if len(tree.Records()) != len(records) {
+		t.Fatalf("record count mismatch: have %d, want %d", len(tree.Records()), len(records))
+	}
+	if links := tree.Links(); !reflect.DeepEqual(links, []string{link}) {
+		t.Fatalf("link mismatch: have %v, want %v", links, []string{link})
+	}
+	txt := tree.ToTXT("nodes.example.org")
+	if !strings.HasPrefix(txt["nodes.example.org"], rootPrefix) {
+		t.Fatalf("missing root record: %q", txt["nodes.example.org"])
+	}
+	for name, content := range txt {
+		if name != "nodes.example.org" && name != hashText(content)+".nodes.example.org" {
+			t.Errorf("entry name %s doesn't match content hash", name)
+		}
+		if strings.HasPrefix(content, branchPrefix) && strings.Count(content, ",") >= maxChildren {
+			t.Errorf("branch %s has too many children", name)
+		}
+	}
+	// The signature can be re-applied to a recreated tree, but only with the
+	// right key.
+	recreated, _ := MakeTree(3, records, []string{link})
+	if err := recreated.SetSignature(&other.PublicKey, tree.Signature()); err == nil {
+		t.Fatalf("signature accepted with wrong public key")
+	}
+	if err := recreated.SetSignature(&key.PublicKey, tree.Signature()); err != nil {
+		t.Fatalf("failed to set signature: %v", err)
+	}
+	if !reflect.DeepEqual(recreated.ToTXT("nodes.example.org"), txt) {
+		t.Fatalf("recreated tree differs from original")
+	}
+}
+
+// Tests that entries round-trip through their text form and that malformed
+// entries are rejected.
+func TestParseEntry(t *testing.T) {
+	key := testKey(t)
+	records := testRecords(t, 1)
+
+	valid := []entry{
+		&branchEntry{},
+		&branchEntry{[]string{"C7HRFPF3BLGF3YR4DY5KX3SMBE", "JWXYDBPXYWG6FX3GMDIBFA6CJ4"}},
+		&enrEntry{records[0]},
+		&linkEntry{domain: "nodes.example.org", pubkey: &key.PublicKey},
+	}
+	for _, e := range valid {
+		parsed, err := parseEntry(e.String(), isLink(e))
+		if err != nil {
+			t.Errorf("failed to parse %q: %v", e, err)
+			continue
+		}
+		if parsed.String() != e.String() {
+			t.Errorf("entry mismatch: have %q, want %q", parsed, e)
+		}
+	}
+	invalid := []struct {
+		text  string
+		links bool
+		err   error
+	}{
+		{"foo", false, errUnknownEntry},
+		{"enrtree-branch:1,2", false, entryError{"branch", errInvalidChild}},
+		{"enr:invalid", false, entryError{"enr", errInvalidENR}},
+		{"enrtree://nokey", true, entryError{"link", errNoPubkey}},
+		{"enrtree://AAAA@nodes.example.org", true, entryError{"link", errBadPubkey}},
+		{valid[2].String(), true, errENRInLinkTree},
+		{valid[3].String(), false, errLinkInENRTree},
+	}
+	for _, test := range invalid {
+		if _, err := parseEntry(test.text, test.links); err != test.err {
+			t.Errorf("error mismatch for %q: have %v, want %v", test.text, err, test.err)
+		}
+	}
+}
+
+func isLink(e entry) bool {
+	_, ok := e.(*linkEntry)
+	return ok
+}
@@ -101,7 +101,7 @@ func (r *Record) Load(e Entry) error {
 
 // Set adds or updates the given entry in the record. It panics if the value can't be
 // encoded. If the record is signed, Set increments the sequence number and invalidates
-// the sequence number.
+// the signature.
 func (r *Record) Set(e Entry) {
 	blob, err := rlp.EncodeToBytes(e)
 	if err != nil {
@@ -130,7 +130,7 @@ func (r *Record) Set(e Entry) {
 }
 
 func (r *Record) invalidate() {
-	if r.signature == nil {
+	if r.signature != nil {
 		r.seq++
 	}
 	r.signature = nil
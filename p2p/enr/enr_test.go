@@ -190,6 +190,26 @@ func TestDirty(t *testing.T) {
 	}
 }
 
+// TestSeqIncrement tests that only modifications of signed records increment the
+// sequence number.
+func TestSeqIncrement(t *testing.T) {
+	var r Record
+	r.Set(UDP(30303))
+	r.Set(TCP(30303))
+	if r.Seq() != 0 {
+		t.Fatalf("unsigned record seq changed: got %d, want 0", r.Seq())
+	}
+	require.NoError(t, SignV4(&r, privkey))
+	if r.Seq() != 0 {
+		t.Fatalf("signing changed seq: got %d, want 0", r.Seq())
+	}
+	r.Set(UDP(30304))
+	r.Set(TCP(30304))
+	if r.Seq() != 1 {
+		t.Fatalf("modified record seq mismatch: got %d, want 1", r.Seq())
+	}
+}
+
 // TestGetSetOverwrite tests value overwrite when setting a new value with an existing key in record.
 func TestGetSetOverwrite(t *testing.T) {
 	var r Record
@@ -29,6 +29,12 @@ var (
 	ingressTrafficMeter = metrics.NewRegisteredMeter("p2p/InboundTraffic", nil)
 	egressConnectMeter  = metrics.NewRegisteredMeter("p2p/OutboundConnects", nil)
 	egressTrafficMeter  = metrics.NewRegisteredMeter("p2p/OutboundTraffic", nil)
+
+	// Message payload sizes before and after Snappy compression.
+	ingressCompressedMeter = metrics.NewRegisteredMeter("p2p/InboundCompressed", nil)
+	ingressPlainMeter      = metrics.NewRegisteredMeter("p2p/InboundUncompressed", nil)
+	egressCompressedMeter  = metrics.NewRegisteredMeter("p2p/OutboundCompressed", nil)
+	egressPlainMeter       = metrics.NewRegisteredMeter("p2p/OutboundUncompressed", nil)
 )
 
 // meteredConn is a wrapper around a net.Conn that meters both the
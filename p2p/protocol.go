@@ -20,6 +20,8 @@ import (
 	"fmt"
 
 	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discv5"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/enr"
 )
 
 // Protocol represents a P2P subprotocol implementation.
@@ -52,6 +54,36 @@ type Protocol struct {
 	// about a certain peer in the network. If an info retrieval function is set,
 	// but returns nil, it is assumed that the protocol handshake is still running.
 	PeerInfo func(id discover.NodeID) interface{}
+
+	// Attributes contains protocol specific information for the node record. The
+	// entries can be updated while the server is running via Server.LocalRecord.
+	Attributes []enr.Entry
+
+	// DialFilter is an optional check of the node records of discovered nodes
+	// before dialing them. It should return false for nodes which are known to
+	// be unable to run the protocol with us, e.g. because they are on another chain.
+	DialFilter func(r *enr.Record) bool
+
+	// MinPeers reserves peer slots for the protocol. Peers which don't run the
+	// protocol are only accepted if there are enough free slots left for
+	// MinPeers peers running it. Trusted and static peers ignore reservations.
+	MinPeers int
+
+	// MaxPeers limits the number of connected peers running the protocol.
+	// Peers sharing the protocol are not accepted when the limit is reached.
+	// Zero means no limit.
+	//
+	// The slot limits apply to all versions of the protocol. If versions of a
+	// protocol specify different limits, the largest one applies.
+	MaxPeers int
+
+	// AdvertiseTopics are advertised via the V5 discovery protocol while the
+	// server is running, so that nodes searching them can find us.
+	AdvertiseTopics []discv5.Topic
+
+	// SearchTopics are searched via the V5 discovery protocol. Nodes found
+	// advertising them are preferred when dialing peers.
+	SearchTopics []discv5.Topic
 }
 
 func (p Protocol) cap() Cap {
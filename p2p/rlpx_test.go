@@ -18,6 +18,7 @@ package p2p
 
 import (
 	"bytes"
+	"crypto/ecdsa"
 	"crypto/rand"
 	"errors"
 	"fmt"
@@ -31,6 +32,7 @@ import (
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/golang/snappy"
 	"github.com/rwdxchain/go-rwdxchaina/crypto"
 	"github.com/rwdxchain/go-rwdxchaina/crypto/ecies"
 	"github.com/rwdxchain/go-rwdxchaina/crypto/sha3"
@@ -379,6 +381,101 @@ func TestRLPXFrameRW(t *testing.T) {
 	}
 }
 
+// Tests that Snappy compression is enabled when both sides of the protocol
+// handshake support it.
+func TestProtocolHandshakeSnappy(t *testing.T) {
+	tests := []struct {
+		v0, v1 uint64
+		snappy bool
+	}{
+		{4, 4, false},
+		{4, snappyProtocolVersion, false},
+		{snappyProtocolVersion, 4, false},
+		{snappyProtocolVersion, snappyProtocolVersion, true},
+	}
+	for _, test := range tests {
+		var (
+			prv0, _ = crypto.GenerateKey()
+			prv1, _ = crypto.GenerateKey()
+			node1   = &discover.Node{ID: discover.PubkeyID(&prv1.PublicKey)}
+			hs0     = &protoHandshake{Version: test.v0, ID: discover.PubkeyID(&prv0.PublicKey)}
+			hs1     = &protoHandshake{Version: test.v1, ID: node1.ID}
+			snappy  = make(chan bool, 2)
+		)
+		fd0, fd1, err := pipes.TCPPipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		run := func(fd net.Conn, prv *ecdsa.PrivateKey, dest *discover.Node, hs *protoHandshake) {
+			defer fd.Close()
+			rlpx := newRLPX(fd).(*rlpx)
+			if _, err := rlpx.doEncHandshake(prv, dest); err != nil {
+				t.Errorf("enc handshake failed: %v", err)
+			} else if _, err := rlpx.doProtoHandshake(hs); err != nil {
+				t.Errorf("proto handshake failed: %v", err)
+			}
+			snappy <- rlpx.rw != nil && rlpx.rw.snappy
+		}
+		go run(fd0, prv0, node1, hs0)
+		go run(fd1, prv1, nil, hs1)
+		for i := 0; i < 2; i++ {
+			if s := <-snappy; s != test.snappy {
+				t.Errorf("versions %d, %d: snappy %t, want %t", test.v0, test.v1, s, test.snappy)
+			}
+		}
+	}
+}
+
+func newTestFrameRWPair(conn io.ReadWriter) (*rlpxFrameRW, *rlpxFrameRW) {
+	var (
+		aesSecret = make([]byte, 16)
+		macSecret = make([]byte, 16)
+		macInit   = make([]byte, 32)
+	)
+	for _, s := range [][]byte{aesSecret, macSecret, macInit} {
+		rand.Read(s)
+	}
+	s1 := secrets{AES: aesSecret, MAC: macSecret, EgressMAC: sha3.NewKeccak256(), IngressMAC: sha3.NewKeccak256()}
+	s1.EgressMAC.Write(macInit)
+	s2 := secrets{AES: aesSecret, MAC: macSecret, EgressMAC: sha3.NewKeccak256(), IngressMAC: sha3.NewKeccak256()}
+	s2.IngressMAC.Write(macInit)
+	return newRLPXFrameRW(conn, s1), newRLPXFrameRW(conn, s2)
+}
+
+func TestRLPXFrameRWSnappy(t *testing.T) {
+	conn := new(bytes.Buffer)
+	rw1, rw2 := newTestFrameRWPair(conn)
+	rw1.snappy, rw2.snappy = true, true
+
+	payload := bytes.Repeat([]byte("compressible "), 1000)
+	if err := rw1.WriteMsg(Msg{Code: 8, Size: uint32(len(payload)), Payload: bytes.NewReader(payload)}); err != nil {
+		t.Fatalf("WriteMsg error: %v", err)
+	}
+	if conn.Len() >= len(payload) {
+		t.Errorf("payload not compressed: %d bytes on the wire for %d byte payload", conn.Len(), len(payload))
+	}
+	msg, err := rw2.ReadMsg()
+	if err != nil {
+		t.Fatalf("ReadMsg error: %v", err)
+	}
+	got, _ := ioutil.ReadAll(msg.Payload)
+	if msg.Code != 8 || msg.Size != uint32(len(payload)) || !bytes.Equal(got, payload) {
+		t.Fatalf("message mismatch: code %d, size %d", msg.Code, msg.Size)
+	}
+
+	// Messages claiming a decompressed size above the limit are rejected
+	// before decompression.
+	bomb := append(snappy.Encode(nil, []byte{1}), make([]byte, 10)...)
+	bomb[0], bomb[1], bomb[2], bomb[3] = 0x80, 0x80, 0x80, 0x08 // varint length 1<<24
+	rw1.snappy = false
+	if err := rw1.WriteMsg(Msg{Code: 8, Size: uint32(len(bomb)), Payload: bytes.NewReader(bomb)}); err != nil {
+		t.Fatalf("WriteMsg error: %v", err)
+	}
+	if _, err := rw2.ReadMsg(); err != errPlainMessageTooLarge {
+		t.Fatalf("wrong error for oversized message: %v", err)
+	}
+}
+
 type handshakeAuthTest struct {
 	input       string
 	isPlain     bool
@@ -0,0 +1,125 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"crypto/ecdsa"
+	"net"
+
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/rlp"
+)
+
+// Hello is the information a remote node sends in the protocol handshake.
+type Hello struct {
+	Version    uint64
+	Name       string
+	Caps       []Cap
+	ListenPort uint64
+	ID         discover.NodeID
+}
+
+// RLPxConn is an RLPx connection to a remote node on which the encryption and
+// protocol handshakes have been performed. It allows tools to exchange messages
+// with a node without running a Server.
+//
+// Message codes are not translated, subprotocol messages need to be offset by
+// the value returned by Protocol.
+type RLPxConn struct {
+	t         transport
+	hello     *Hello
+	protocols map[string]*protoRW
+}
+
+// SetupRLPx performs the RLPx handshakes with the given node on an established
+// connection, advertising the given protocols. The connection is closed if the
+// handshakes fail.
+func SetupRLPx(fd net.Conn, key *ecdsa.PrivateKey, dest *discover.Node, name string, protocols []Protocol) (*RLPxConn, error) {
+	t := newRLPX(fd)
+	id, err := t.doEncHandshake(key, dest)
+	if err != nil {
+		t.close(err)
+		return nil, err
+	}
+	our := &protoHandshake{Version: baseProtocolVersion, Name: name, ID: discover.PubkeyID(&key.PublicKey)}
+	for _, p := range protocols {
+		our.Caps = append(our.Caps, p.cap())
+	}
+	their, err := t.doProtoHandshake(our)
+	if err != nil {
+		t.close(err)
+		return nil, err
+	}
+	if their.ID != id {
+		t.close(DiscUnexpectedIdentity)
+		return nil, DiscUnexpectedIdentity
+	}
+	hello := &Hello{Version: their.Version, Name: their.Name, Caps: their.Caps, ListenPort: their.ListenPort, ID: their.ID}
+	return &RLPxConn{t: t, hello: hello, protocols: matchProtocols(protocols, their.Caps, t)}, nil
+}
+
+// Hello returns the protocol handshake of the remote node.
+func (c *RLPxConn) Hello() *Hello {
+	return c.hello
+}
+
+// Protocol returns the negotiated version and message code offset of the given
+// protocol. The last return value is false if the remote node doesn't support
+// the protocol.
+func (c *RLPxConn) Protocol(name string) (version uint, offset uint64, ok bool) {
+	proto := c.protocols[name]
+	if proto == nil {
+		return 0, 0, false
+	}
+	return proto.Version, proto.offset, true
+}
+
+// ReadMsg reads the next subprotocol message. Pings of the base protocol are
+// answered and disconnect requests are returned as a DiscReason error.
+func (c *RLPxConn) ReadMsg() (Msg, error) {
+	for {
+		msg, err := c.t.ReadMsg()
+		if err != nil {
+			return msg, err
+		}
+		switch {
+		case msg.Code == pingMsg:
+			msg.Discard()
+			if err := SendItems(c.t, pongMsg); err != nil {
+				return Msg{}, err
+			}
+		case msg.Code == discMsg:
+			var reason [1]DiscReason
+			rlp.Decode(msg.Payload, &reason)
+			return Msg{}, reason[0]
+		case msg.Code < baseProtocolLength:
+			msg.Discard()
+		default:
+			return msg, nil
+		}
+	}
+}
+
+// WriteMsg sends a message with a raw message code.
+func (c *RLPxConn) WriteMsg(msg Msg) error {
+	return c.t.WriteMsg(msg)
+}
+
+// Close disconnects from the remote node with the given reason.
+func (c *RLPxConn) Close(reason DiscReason) {
+	c.t.close(reason)
+}
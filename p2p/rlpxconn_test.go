@@ -0,0 +1,104 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+// Tests that tools can talk to a server over a bare RLPx connection.
+func TestRLPxConn(t *testing.T) {
+	srv := &Server{
+		Config: Config{
+			Name:        "test-server",
+			MaxPeers:    10,
+			ListenAddr:  "127.0.0.1:0",
+			PrivateKey:  newkey(),
+			NoDiscovery: true,
+			Protocols: []Protocol{
+				{Name: "aaa", Version: 1, Length: 4, Run: func(p *Peer, rw MsgReadWriter) error {
+					_, err := rw.ReadMsg()
+					return err
+				}},
+				{Name: "bbb", Version: 2, Length: 3, Run: func(p *Peer, rw MsgReadWriter) error {
+					if err := SendItems(rw, 2, "hello"); err != nil {
+						return err
+					}
+					var text []string
+					msg, err := rw.ReadMsg()
+					if err != nil {
+						return err
+					}
+					if err := msg.Decode(&text); err != nil {
+						return err
+					}
+					return SendItems(rw, 1, text[0])
+				}},
+			},
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start server: %v", err)
+	}
+	defer srv.Stop()
+
+	fd, err := net.DialTimeout("tcp", srv.ListenAddr, time.Second)
+	if err != nil {
+		t.Fatalf("could not dial server: %v", err)
+	}
+	fd.SetDeadline(time.Now().Add(5 * time.Second))
+	conn, err := SetupRLPx(fd, newkey(), srv.Self(), "test-client", []Protocol{
+		{Name: "aaa", Version: 1, Length: 4},
+		{Name: "bbb", Version: 1, Length: 5},
+		{Name: "bbb", Version: 2, Length: 3},
+		{Name: "ccc", Version: 1, Length: 1},
+	})
+	if err != nil {
+		t.Fatalf("handshake failed: %v", err)
+	}
+	defer conn.Close(DiscQuitting)
+
+	if hello := conn.Hello(); hello.Name != "test-server" || hello.ID != srv.Self().ID || len(hello.Caps) != 2 {
+		t.Fatalf("hello mismatch: %+v", hello)
+	}
+	if _, _, ok := conn.Protocol("ccc"); ok {
+		t.Fatalf("unsupported protocol negotiated")
+	}
+	version, offset, ok := conn.Protocol("bbb")
+	if !ok || version != 2 || offset != baseProtocolLength+4 {
+		t.Fatalf("protocol mismatch: have %d/%d (%t), want %d/%d", version, offset, ok, 2, baseProtocolLength+4)
+	}
+	var text []string
+	msg, err := conn.ReadMsg()
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if err := msg.Decode(&text); err != nil || msg.Code != offset+2 || text[0] != "hello" {
+		t.Fatalf("message mismatch: code %d, content %v (%v)", msg.Code, text, err)
+	}
+	if err := SendItems(conn, offset, "echo"); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if msg, err = conn.ReadMsg(); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if err := msg.Decode(&text); err != nil || msg.Code != offset+1 || text[0] != "echo" {
+		t.Fatalf("message mismatch: code %d, content %v (%v)", msg.Code, text, err)
+	}
+}
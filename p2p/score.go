@@ -0,0 +1,158 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/log"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+)
+
+// Score adjustments for typical peer behaviour, to be reported by protocols
+// through Peer.AdjustScore.
+const (
+	ScoreUseful  = 1   // the peer answered a request with useful data
+	ScoreUseless = -5  // the peer sent useless or invalid data
+	ScoreTimeout = -10 // the peer didn't answer a request in time
+)
+
+const (
+	maxScore = 100  // upper bound of peer scores
+	banScore = -100 // peers reaching this score are disconnected and banned
+
+	banDuration    = 10 * time.Minute // duration of the first ban of a node
+	maxBanDuration = 24 * time.Hour   // upper bound of ban durations, also the time bans are remembered
+)
+
+// Score returns the current score of the peer. Peers start out with a score of
+// zero, which is changed by protocols reporting the peer's behaviour.
+func (p *Peer) Score() int {
+	return int(atomic.LoadInt32(&p.score))
+}
+
+// AdjustScore changes the score of the peer by delta. Protocols should report
+// good behaviour with a positive and bad behaviour with a negative delta, using
+// the Score* constants where they apply.
+//
+// Peers with a negative score are evicted when the server is full and a new peer
+// wants to connect. When the score falls to the ban threshold, the peer is
+// disconnected and the node is banned for some time.
+func (p *Peer) AdjustScore(delta int) {
+	for {
+		old := atomic.LoadInt32(&p.score)
+		score := int64(old) + int64(delta)
+		if score > maxScore {
+			score = maxScore
+		} else if score < banScore {
+			score = banScore
+		}
+		if !atomic.CompareAndSwapInt32(&p.score, old, int32(score)) {
+			continue
+		}
+		if score == banScore && atomic.CompareAndSwapInt32(&p.banned, 0, 1) {
+			p.log.Debug("Disconnecting peer with low score")
+			p.Disconnect(DiscUselessPeer)
+		}
+		return
+	}
+}
+
+// scoreBanned reports whether the peer was disconnected because of its score.
+func (p *Peer) scoreBanned() bool {
+	return atomic.LoadInt32(&p.banned) == 1
+}
+
+// banList tracks temporarily banned nodes. Nodes which are banned again while
+// their previous ban is remembered get banned for twice as long each time.
+// It is only accessed by the server's run loop.
+type banList struct {
+	bans  map[discover.NodeID]*ban
+	store banStore // persistent storage of the bans, if set
+}
+
+// banStore persists bans across restarts. It is implemented by the discovery
+// table, which keeps them in the node database.
+type banStore interface {
+	Ban(id discover.NodeID) (until, forget time.Time, count uint)
+	SetBan(id discover.NodeID, until, forget time.Time, count uint) error
+}
+
+type ban struct {
+	until  time.Time // end of the current ban
+	forget time.Time // time after which the bans are forgotten
+	count  uint      // number of remembered bans
+}
+
+func newBanList(store banStore) *banList {
+	return &banList{bans: make(map[discover.NodeID]*ban), store: store}
+}
+
+// add bans a node, returning the duration of the ban.
+func (l *banList) add(id discover.NodeID, now time.Time) time.Duration {
+	l.expire(now)
+	b := l.get(id, now)
+	if b == nil {
+		b = new(ban)
+		l.bans[id] = b
+	}
+	d := maxBanDuration
+	if b.count < 8 && banDuration<<b.count < maxBanDuration {
+		d = banDuration << b.count
+	}
+	b.count++
+	b.until = now.Add(d)
+	b.forget = b.until.Add(maxBanDuration)
+	if l.store != nil {
+		if err := l.store.SetBan(id, b.until, b.forget, b.count); err != nil {
+			log.Warn("Failed to store node ban", "id", id, "err", err)
+		}
+	}
+	return d
+}
+
+// contains reports whether the node is banned at the given time.
+func (l *banList) contains(id discover.NodeID, now time.Time) bool {
+	b := l.get(id, now)
+	return b != nil && now.Before(b.until)
+}
+
+// get retrieves the remembered ban of a node, loading it from the store if it's
+// not cached yet.
+func (l *banList) get(id discover.NodeID, now time.Time) *ban {
+	if b := l.bans[id]; b != nil || l.store == nil {
+		return b
+	}
+	until, forget, count := l.store.Ban(id)
+	if count == 0 || !now.Before(forget) {
+		return nil
+	}
+	b := &ban{until: until, forget: forget, count: count}
+	l.bans[id] = b
+	return b
+}
+
+// expire drops the bans which are no longer remembered. The store expires its
+// own copies.
+func (l *banList) expire(now time.Time) {
+	for id, b := range l.bans {
+		if !now.Before(b.forget) {
+			delete(l.bans, id)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+)
+
+func TestPeerAdjustScore(t *testing.T) {
+	p := NewPeer(randomID(), "test", nil)
+
+	p.AdjustScore(2 * maxScore)
+	if p.Score() != maxScore {
+		t.Errorf("score not capped: %d", p.Score())
+	}
+	p.AdjustScore(ScoreTimeout)
+	if p.Score() != maxScore+ScoreTimeout {
+		t.Errorf("wrong score %d, want %d", p.Score(), maxScore+ScoreTimeout)
+	}
+	if p.scoreBanned() {
+		t.Errorf("peer banned above threshold")
+	}
+	p.AdjustScore(4 * banScore)
+	if p.Score() != banScore {
+		t.Errorf("score not capped: %d", p.Score())
+	}
+	if !p.scoreBanned() {
+		t.Errorf("peer not banned at threshold")
+	}
+}
+
+func TestBanList(t *testing.T) {
+	var (
+		l   = newBanList(nil)
+		id  = discover.NodeID{1}
+		now = time.Unix(0, 0)
+	)
+	if l.contains(id, now) {
+		t.Fatal("empty list contains node")
+	}
+	// Repeated bans last longer.
+	want := []time.Duration{banDuration, 2 * banDuration, 4 * banDuration}
+	for i, w := range want {
+		if d := l.add(id, now); d != w {
+			t.Errorf("ban %d: wrong duration %v, want %v", i, d, w)
+		}
+		if !l.contains(id, now.Add(w-time.Second)) || l.contains(id, now.Add(w)) {
+			t.Errorf("ban %d: wrong end", i)
+		}
+		now = now.Add(w)
+	}
+	// Durations are capped.
+	for i := 0; i < 10; i++ {
+		l.add(id, now)
+	}
+	if d := l.add(id, now); d != maxBanDuration {
+		t.Errorf("wrong maximum duration %v", d)
+	}
+	// Bans are forgotten after a while.
+	now = now.Add(2 * maxBanDuration)
+	if d := l.add(id, now); d != banDuration {
+		t.Errorf("ban not forgotten, duration %v", d)
+	}
+}
+
+// testBanStore is an in-memory banStore.
+type testBanStore map[discover.NodeID]ban
+
+func (s testBanStore) Ban(id discover.NodeID) (until, forget time.Time, count uint) {
+	b := s[id]
+	return b.until, b.forget, b.count
+}
+
+func (s testBanStore) SetBan(id discover.NodeID, until, forget time.Time, count uint) error {
+	s[id] = ban{until: until, forget: forget, count: count}
+	return nil
+}
+
+func TestBanListPersistence(t *testing.T) {
+	var (
+		store = make(testBanStore)
+		id    = discover.NodeID{1}
+		now   = time.Unix(0, 0)
+	)
+	newBanList(store).add(id, now)
+
+	// A restarted list enforces the stored ban and extends it.
+	l := newBanList(store)
+	if !l.contains(id, now.Add(banDuration-time.Second)) {
+		t.Fatal("stored ban not enforced")
+	}
+	if d := l.add(id, now.Add(banDuration)); d != 2*banDuration {
+		t.Errorf("stored ban count ignored, duration %v", d)
+	}
+	if store[id].count != 2 {
+		t.Errorf("ban not written through, count %d", store[id].count)
+	}
+	// Forgotten bans are ignored.
+	if l := newBanList(store); l.contains(id, now.Add(3*banDuration+maxBanDuration)) {
+		t.Error("forgotten ban enforced")
+	}
+}
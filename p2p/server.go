@@ -32,6 +32,8 @@ import (
 	"github.com/rwdxchain/go-rwdxchaina/log"
 	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
 	"github.com/rwdxchain/go-rwdxchaina/p2p/discv5"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/dnsdisc"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/enr"
 	"github.com/rwdxchain/go-rwdxchaina/p2p/nat"
 	"github.com/rwdxchain/go-rwdxchaina/p2p/netutil"
 )
@@ -94,6 +96,11 @@ type Config struct {
 	// protocol.
 	BootstrapNodesV5 []*discv5.Node `toml:",omitempty"`
 
+	// DiscoveryDNS contains the URLs of signed node lists published in DNS
+	// (enrtree://<key>@<domain>). The listed nodes are dialed alongside the
+	// nodes found by the discovery table.
+	DiscoveryDNS []string `toml:",omitempty"`
+
 	// Static nodes are used as pre-configured connections which are always
 	// maintained and re-connected on disconnects.
 	StaticNodes []*discover.Node
@@ -111,6 +118,15 @@ type Config struct {
 	// live nodes in the network.
 	NodeDatabase string `toml:",omitempty"`
 
+	// NodeDatabaseV5 is the path to the database of the V5 discovery protocol,
+	// containing the previously seen nodes and the topic ads stored for them.
+	// If empty, the database is kept in memory.
+	NodeDatabaseV5 string `toml:",omitempty"`
+
+	// DiscoveryV5Topics configures topic advertisement in the V5 discovery
+	// protocol, e.g. the rate of registrations.
+	DiscoveryV5Topics discv5.TopicConfig `toml:",omitempty"`
+
 	// Protocols should contain the protocols supported
 	// by the server. Matching protocols are launched for
 	// each peer.
@@ -133,6 +149,16 @@ type Config struct {
 	// is used to dial outbound peer connections.
 	Dialer NodeDialer `toml:"-"`
 
+	// Transport is the primary network transport, used for listening on
+	// ListenAddr and dialing the TCP endpoint of nodes. It defaults to TCP.
+	// Tests may use the transports of a PipeNetwork instead.
+	Transport NetTransport `toml:"-"`
+
+	// ExtraTransports are additional transports to listen on. They are
+	// announced in the node record and preferred for dialing nodes which
+	// support them.
+	ExtraTransports []TransportListener `toml:"-"`
+
 	// If NoDial is true, the server will not dial any peers.
 	NoDial bool `toml:",omitempty"`
 
@@ -159,9 +185,15 @@ type Server struct {
 
 	ntab         discoverTable
 	listener     net.Listener
+	extraListen  []net.Listener
 	ourHandshake *protoHandshake
+	localRecord  *discover.LocalRecord
 	lastLookup   time.Time
 	DiscV5       *discv5.Network
+	dnsdisc      *dnsdisc.Client
+	bans         *banList // accessed by run loop only
+	records      *recordCache
+	inboundSlots chan struct{} // handshake slots of inbound connections, shared by all listeners
 
 	// These are for Peers, PeerCount (and nothing else).
 	peerOp     chan peerOpFunc
@@ -172,6 +204,7 @@ type Server struct {
 	removestatic  chan *discover.Node
 	addtrusted    chan *discover.Node
 	removetrusted chan *discover.Node
+	banpeer       chan *discover.Node
 	posthandshake chan *conn
 	addpeer       chan *conn
 	delpeer       chan peerDrop
@@ -336,6 +369,15 @@ func (srv *Server) RemoveTrustedPeer(node *discover.Node) {
 	}
 }
 
+// BanPeer disconnects the given node and refuses connections to and from it for
+// some time. Nodes which are banned repeatedly get banned for longer.
+func (srv *Server) BanPeer(node *discover.Node) {
+	select {
+	case srv.banpeer <- node:
+	case <-srv.quit:
+	}
+}
+
 // SubscribePeers subscribes the given channel to peer events
 func (srv *Server) SubscribeEvents(ch chan *PeerEvent) event.Subscription {
 	return srv.peerFeed.Subscribe(ch)
@@ -361,17 +403,40 @@ func (srv *Server) makeSelf(listener net.Listener, ntab discoverTable) *discover
 			return &discover.Node{IP: net.ParseIP("0.0.0.0"), ID: discover.PubkeyID(&srv.PrivateKey.PublicKey)}
 		}
 		// Otherwise inject the listener address too
-		addr := listener.Addr().(*net.TCPAddr)
+		ip, port := splitAddr(listener.Addr())
 		return &discover.Node{
 			ID:  discover.PubkeyID(&srv.PrivateKey.PublicKey),
-			IP:  addr.IP,
-			TCP: uint16(addr.Port),
+			IP:  ip,
+			TCP: port,
 		}
 	}
 	// Otherwise return the discovery node.
 	return ntab.Self()
 }
 
+// LocalRecord returns the manager of the local node record, or nil if the server
+// is not running. Protocols may use it to update their record entries.
+func (srv *Server) LocalRecord() *discover.LocalRecord {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+
+	if !srv.running {
+		return nil
+	}
+	return srv.localRecord
+}
+
+// hasDialFilters reports whether any of the protocols filters dial candidates
+// based on their node records.
+func (srv *Server) hasDialFilters() bool {
+	for _, proto := range srv.Protocols {
+		if proto.DialFilter != nil {
+			return true
+		}
+	}
+	return false
+}
+
 // Stop terminates the server and all active peer connections.
 // It blocks until all active connections have been closed.
 func (srv *Server) Stop() {
@@ -385,6 +450,9 @@ func (srv *Server) Stop() {
 		// this unblocks listener Accept
 		srv.listener.Close()
 	}
+	for _, l := range srv.extraListen {
+		l.Close()
+	}
 	close(srv.quit)
 	srv.lock.Unlock()
 	srv.loopWG.Wait()
@@ -438,8 +506,11 @@ func (srv *Server) Start() (err error) {
 	if srv.newTransport == nil {
 		srv.newTransport = newRLPX
 	}
+	if srv.Transport == nil {
+		srv.Transport = TCPTransport{DialTimeout: defaultDialTimeout}
+	}
 	if srv.Dialer == nil {
-		srv.Dialer = TCPDialer{&net.Dialer{Timeout: defaultDialTimeout}}
+		srv.Dialer = transportDialer{srv.Transport}
 	}
 	srv.quit = make(chan struct{})
 	srv.addpeer = make(chan *conn)
@@ -449,8 +520,19 @@ func (srv *Server) Start() (err error) {
 	srv.removestatic = make(chan *discover.Node)
 	srv.addtrusted = make(chan *discover.Node)
 	srv.removetrusted = make(chan *discover.Node)
+	srv.banpeer = make(chan *discover.Node)
 	srv.peerOp = make(chan peerOpFunc)
 	srv.peerOpDone = make(chan struct{})
+	srv.records = newRecordCache()
+
+	tokens := defaultMaxPendingPeers
+	if srv.MaxPendingPeers > 0 {
+		tokens = srv.MaxPendingPeers
+	}
+	srv.inboundSlots = make(chan struct{}, tokens)
+	for i := 0; i < tokens; i++ {
+		srv.inboundSlots <- struct{}{}
+	}
 
 	var (
 		conn      *net.UDPConn
@@ -480,6 +562,20 @@ func (srv *Server) Start() (err error) {
 		}
 	}
 
+	// local node record, re-signed with a higher sequence number on changes
+	srv.localRecord = discover.NewLocalRecord(srv.PrivateKey)
+	for _, p := range srv.Protocols {
+		for _, attr := range p.Attributes {
+			srv.localRecord.Set(attr)
+		}
+	}
+	if realaddr != nil {
+		if !realaddr.IP.IsUnspecified() {
+			srv.localRecord.Set(enr.IP(realaddr.IP))
+		}
+		srv.localRecord.Set(enr.UDP(realaddr.Port))
+	}
+
 	if !srv.NoDiscovery && srv.DiscoveryV5 {
 		unhandled = make(chan discover.ReadPacket, 100)
 		sconn = &sharedUDPConn{conn, unhandled}
@@ -494,6 +590,7 @@ func (srv *Server) Start() (err error) {
 			NetRestrict:  srv.NetRestrict,
 			Bootnodes:    srv.BootstrapNodes,
 			Unhandled:    unhandled,
+			LocalRecord:  srv.localRecord,
 		}
 		ntab, err := discover.ListenUDP(conn, cfg)
 		if err != nil {
@@ -506,11 +603,16 @@ func (srv *Server) Start() (err error) {
 		var (
 			ntab *discv5.Network
 			err  error
+			cfg  = discv5.Config{
+				NodeDBPath:  srv.NodeDatabaseV5,
+				NetRestrict: srv.NetRestrict,
+				Topics:      srv.DiscoveryV5Topics,
+			}
 		)
 		if sconn != nil {
-			ntab, err = discv5.ListenUDP(srv.PrivateKey, sconn, realaddr, "", srv.NetRestrict) //srv.NodeDatabase)
+			ntab, err = discv5.ListenUDPWithConfig(srv.PrivateKey, sconn, realaddr, cfg)
 		} else {
-			ntab, err = discv5.ListenUDP(srv.PrivateKey, conn, realaddr, "", srv.NetRestrict) //srv.NodeDatabase)
+			ntab, err = discv5.ListenUDPWithConfig(srv.PrivateKey, conn, realaddr, cfg)
 		}
 		if err != nil {
 			return err
@@ -523,6 +625,31 @@ func (srv *Server) Start() (err error) {
 
 	dynPeers := srv.maxDialedConns()
 	dialer := newDialState(srv.StaticNodes, srv.BootstrapNodes, srv.ntab, dynPeers, srv.NetRestrict)
+	// Remember bans across restarts in the node database of the discovery table
+	var store banStore
+	if tab, ok := srv.ntab.(banStore); ok {
+		store = tab
+	}
+	srv.bans = newBanList(store)
+	dialer.bans = srv.bans
+
+	// DNS node lists
+	if len(srv.DiscoveryDNS) > 0 && dynPeers > 0 {
+		srv.dnsdisc = dnsdisc.NewClient(dnsdisc.Config{Logger: srv.log})
+		for _, url := range srv.DiscoveryDNS {
+			if err := srv.dnsdisc.AddTree(url); err != nil {
+				return fmt.Errorf("invalid DNS discovery URL %q: %v", url, err)
+			}
+		}
+		srv.dnsdisc.Start()
+		dialer.dnsNodes = srv.dnsdisc
+	}
+	// discovery topics of the protocols
+	if srv.DiscV5 != nil {
+		if found := srv.startTopics(); found != nil && dynPeers > 0 {
+			dialer.topicNodes = found
+		}
+	}
 
 	// handshake
 	srv.ourHandshake = &protoHandshake{Version: baseProtocolVersion, Name: srv.Name, ID: discover.PubkeyID(&srv.PrivateKey.PublicKey)}
@@ -535,6 +662,9 @@ func (srv *Server) Start() (err error) {
 			return err
 		}
 	}
+	if err := srv.startExtraTransports(); err != nil {
+		return err
+	}
 	if srv.NoDial && srv.ListenAddr == "" {
 		srv.log.Warn("P2P server will be useless, neither dialing nor listening")
 	}
@@ -546,18 +676,20 @@ func (srv *Server) Start() (err error) {
 }
 
 func (srv *Server) startListening() error {
-	// Launch the TCP listener.
-	listener, err := net.Listen("tcp", srv.ListenAddr)
+	// Launch the listener of the primary transport.
+	listener, err := srv.Transport.Listen(srv.ListenAddr)
 	if err != nil {
 		return err
 	}
-	laddr := listener.Addr().(*net.TCPAddr)
-	srv.ListenAddr = laddr.String()
+	_, port := splitAddr(listener.Addr())
+	srv.ListenAddr = listener.Addr().String()
 	srv.listener = listener
+	srv.localRecord.Set(enr.TCP(port))
 	srv.loopWG.Add(1)
-	go srv.listenLoop()
+	go srv.listenLoop(listener)
 	// Map the TCP listening port if NAT is configured.
-	if !laddr.IP.IsLoopback() && srv.NAT != nil {
+	laddr, isTCP := listener.Addr().(*net.TCPAddr)
+	if isTCP && !laddr.IP.IsLoopback() && srv.NAT != nil {
 		srv.loopWG.Add(1)
 		go func() {
 			nat.Map(srv.NAT, srv.quit, "tcp", laddr.Port, laddr.Port, "ethereum p2p")
@@ -567,6 +699,24 @@ func (srv *Server) startListening() error {
 	return nil
 }
 
+// startExtraTransports launches the listeners of the additional transports and
+// announces them in the node record.
+func (srv *Server) startExtraTransports() error {
+	for _, extra := range srv.ExtraTransports {
+		listener, err := extra.Transport.Listen(extra.ListenAddr)
+		if err != nil {
+			return fmt.Errorf("can't listen on %s transport: %v", extra.Transport.Name(), err)
+		}
+		srv.extraListen = append(srv.extraListen, listener)
+		_, port := splitAddr(listener.Addr())
+		srv.localRecord.Set(enr.WithEntry(extra.Transport.Name(), port))
+		srv.log.Info("Transport listener up", "transport", extra.Transport.Name(), "addr", listener.Addr())
+		srv.loopWG.Add(1)
+		go srv.listenLoop(listener)
+	}
+	return nil
+}
+
 type dialer interface {
 	newTasks(running int, peers map[discover.NodeID]*Peer, now time.Time) []task
 	taskDone(task, time.Time)
@@ -578,7 +728,6 @@ func (srv *Server) run(dialstate dialer) {
 	defer srv.loopWG.Done()
 	var (
 		peers        = make(map[discover.NodeID]*Peer)
-		inboundCount = 0
 		trusted      = make(map[discover.NodeID]bool, len(srv.TrustedNodes))
 		taskdone     = make(chan task, maxActiveDialTasks)
 		runningTasks []task
@@ -663,6 +812,13 @@ running:
 			if p, ok := peers[n.ID]; ok {
 				p.rw.set(trustedConn, false)
 			}
+		case n := <-srv.banpeer:
+			// This channel is used by BanPeer to ban a node.
+			d := srv.bans.add(n.ID, time.Now())
+			srv.log.Debug("Banning node", "id", n.ID, "duration", d)
+			if p, ok := peers[n.ID]; ok {
+				p.Disconnect(DiscUselessPeer)
+			}
 		case op := <-srv.peerOp:
 			// This channel is used by Peers and PeerCount.
 			op(peers)
@@ -683,14 +839,22 @@ running:
 			}
 			// TODO: track in-progress inbound node IDs (pre-Peer) to avoid dialing them.
 			select {
-			case c.cont <- srv.encHandshakeChecks(peers, inboundCount, c):
+			case c.cont <- srv.encHandshakeChecks(peers, c):
 			case <-srv.quit:
 				break running
 			}
 		case c := <-srv.addpeer:
 			// At this point the connection is past the protocol handshake.
 			// Its capabilities are known and the remote identity is verified.
-			err := srv.protoHandshakeChecks(peers, inboundCount, c)
+			err := srv.protoHandshakeChecks(peers, c)
+			if err == nil && !c.is(trustedConn|staticDialedConn) && !srv.hasSlot(peers, c, nil) {
+				// The checks passed because a low-scoring peer can be evicted
+				// to make room for the new one.
+				victim := srv.evictionCandidate(peers, c)
+				victim.log.Debug("Evicting peer to free slot", "score", victim.Score())
+				victim.evicted = true
+				victim.Disconnect(DiscTooManyPeers)
+			}
 			if err == nil {
 				// The handshakes are done and it passed all checks.
 				p := newPeer(c, srv.Protocols)
@@ -703,9 +867,6 @@ running:
 				srv.log.Debug("Adding p2p peer", "name", name, "addr", c.fd.RemoteAddr(), "peers", len(peers)+1)
 				go srv.runPeer(p)
 				peers[c.id] = p
-				if p.Inbound() {
-					inboundCount++
-				}
 			}
 			// The dialer logic relies on the assumption that
 			// dial tasks complete after the peer has been added or
@@ -720,8 +881,9 @@ running:
 			d := common.PrettyDuration(mclock.Now() - pd.created)
 			pd.log.Debug("Removing p2p peer", "duration", d, "peers", len(peers)-1, "req", pd.requested, "err", pd.err)
 			delete(peers, pd.ID())
-			if pd.Inbound() {
-				inboundCount--
+			if pd.scoreBanned() {
+				d := srv.bans.add(pd.ID(), time.Now())
+				pd.log.Debug("Banning node for low score", "duration", d)
 			}
 		}
 	}
@@ -735,6 +897,9 @@ running:
 	if srv.DiscV5 != nil {
 		srv.DiscV5.Close()
 	}
+	if srv.dnsdisc != nil {
+		srv.dnsdisc.Close()
+	}
 	// Disconnect all peers.
 	for _, p := range peers {
 		p.Disconnect(DiscQuitting)
@@ -749,21 +914,23 @@ running:
 	}
 }
 
-func (srv *Server) protoHandshakeChecks(peers map[discover.NodeID]*Peer, inboundCount int, c *conn) error {
+func (srv *Server) protoHandshakeChecks(peers map[discover.NodeID]*Peer, c *conn) error {
 	// Drop connections with no matching protocols.
 	if len(srv.Protocols) > 0 && countMatchingProtocols(srv.Protocols, c.caps) == 0 {
 		return DiscUselessPeer
 	}
 	// Repeat the encryption handshake checks because the
 	// peer set might have changed between the handshakes.
-	return srv.encHandshakeChecks(peers, inboundCount, c)
+	// The protocol slots are checked as well now that the
+	// capabilities are known.
+	return srv.encHandshakeChecks(peers, c)
 }
 
-func (srv *Server) encHandshakeChecks(peers map[discover.NodeID]*Peer, inboundCount int, c *conn) error {
+func (srv *Server) encHandshakeChecks(peers map[discover.NodeID]*Peer, c *conn) error {
 	switch {
-	case !c.is(trustedConn|staticDialedConn) && len(peers) >= srv.MaxPeers:
-		return DiscTooManyPeers
-	case !c.is(trustedConn) && c.is(inboundConn) && inboundCount >= srv.maxInboundConns():
+	case !c.is(trustedConn) && srv.bans.contains(c.id, time.Now()):
+		return DiscUselessPeer
+	case !c.is(trustedConn|staticDialedConn) && !srv.hasSlot(peers, c, nil) && srv.evictionCandidate(peers, c) == nil:
 		return DiscTooManyPeers
 	case peers[c.id] != nil:
 		return DiscAlreadyConnected
@@ -774,12 +941,113 @@ func (srv *Server) encHandshakeChecks(peers map[discover.NodeID]*Peer, inboundCo
 	}
 }
 
+// hasSlot reports whether c fits into the peer slots when the given peer (if
+// non-nil) and the peers being evicted are disregarded. The protocol slot limits
+// are only checked when the capabilities of c are known.
+func (srv *Server) hasSlot(peers map[discover.NodeID]*Peer, c *conn, without *Peer) bool {
+	var (
+		total   int
+		running = make(map[string]int)
+	)
+	for _, p := range peers {
+		if p == without || p.evicted {
+			continue
+		}
+		total++
+		for name := range p.running {
+			running[name]++
+		}
+	}
+	if total >= srv.MaxPeers {
+		return false
+	}
+	if c.is(inboundConn) && srv.countInbound(peers, without) >= srv.maxInboundConns() {
+		return false
+	}
+	if c.caps == nil {
+		return true
+	}
+	var (
+		shared   = matchProtocols(srv.Protocols, c.caps, nil)
+		reserved int
+	)
+	for name, limit := range srv.protocolSlots() {
+		n := running[name]
+		if shared[name] != nil {
+			if limit.max > 0 && n >= limit.max {
+				return false
+			}
+			n++
+		}
+		if n < limit.min {
+			reserved += limit.min - n
+		}
+	}
+	return total+1+reserved <= srv.MaxPeers
+}
+
+// countInbound returns the number of inbound peers which aren't being evicted.
+func (srv *Server) countInbound(peers map[discover.NodeID]*Peer, without *Peer) int {
+	n := 0
+	for _, p := range peers {
+		if p != without && !p.evicted && p.Inbound() {
+			n++
+		}
+	}
+	return n
+}
+
+// evictionCandidate returns the lowest-scoring peer with a negative score whose
+// removal frees a slot for c. Trusted and static peers are never evicted.
+func (srv *Server) evictionCandidate(peers map[discover.NodeID]*Peer, c *conn) *Peer {
+	var victim *Peer
+	for _, p := range peers {
+		if p.evicted || p.rw.is(trustedConn|staticDialedConn) || p.Score() >= 0 {
+			continue
+		}
+		if victim != nil && p.Score() >= victim.Score() {
+			continue
+		}
+		if srv.hasSlot(peers, c, p) {
+			victim = p
+		}
+	}
+	return victim
+}
+
+// slotLimits are the peer slot limits of a protocol.
+type slotLimits struct{ min, max int }
+
+// protocolSlots collects the slot limits of the protocols which have any.
+func (srv *Server) protocolSlots() map[string]slotLimits {
+	limits := make(map[string]slotLimits)
+	for _, proto := range srv.Protocols {
+		if proto.MinPeers == 0 && proto.MaxPeers == 0 {
+			continue
+		}
+		l := limits[proto.Name]
+		if proto.MinPeers > l.min {
+			l.min = proto.MinPeers
+		}
+		if proto.MaxPeers > l.max {
+			l.max = proto.MaxPeers
+		}
+		limits[proto.Name] = l
+	}
+	return limits
+}
+
 func (srv *Server) maxInboundConns() int {
 	return srv.MaxPeers - srv.maxDialedConns()
 }
 
 func (srv *Server) maxDialedConns() int {
-	if srv.NoDiscovery || srv.NoDial {
+	if srv.NoDial {
+		return 0
+	}
+	// Without the discovery table, peers are only dialed if other sources
+	// provide candidates.
+	if srv.NoDiscovery && len(srv.DiscoveryDNS) == 0 && !srv.searchesTopics() {
 		return 0
 	}
 	r := srv.DialRatio
@@ -795,18 +1063,14 @@ type tempError interface {
 
 // listenLoop runs in its own goroutine and accepts
 // inbound connections.
-func (srv *Server) listenLoop() {
+func (srv *Server) listenLoop(listener net.Listener) {
 	defer srv.loopWG.Done()
-	srv.log.Info("RLPx listener up", "self", srv.makeSelf(srv.listener, srv.ntab))
-
-	tokens := defaultMaxPendingPeers
-	if srv.MaxPendingPeers > 0 {
-		tokens = srv.MaxPendingPeers
-	}
-	slots := make(chan struct{}, tokens)
-	for i := 0; i < tokens; i++ {
-		slots <- struct{}{}
+	if listener == srv.listener {
+		srv.log.Info("RLPx listener up", "self", srv.makeSelf(srv.listener, srv.ntab))
 	}
+	// The handshake slots are shared with the listeners of the other transports,
+	// MaxPendingPeers limits the pending inbound connections of all of them.
+	slots := srv.inboundSlots
 
 	for {
 		// Wait for a handshake slot before accepting.
@@ -817,7 +1081,7 @@ func (srv *Server) listenLoop() {
 			err error
 		)
 		for {
-			fd, err = srv.listener.Accept()
+			fd, err = listener.Accept()
 			if tempErr, ok := err.(tempError); ok && tempErr.Temporary() {
 				srv.log.Debug("Temporary read error", "err", err)
 				continue
@@ -830,7 +1094,7 @@ func (srv *Server) listenLoop() {
 
 		// Reject connections that do not match NetRestrict.
 		if srv.NetRestrict != nil {
-			if tcp, ok := fd.RemoteAddr().(*net.TCPAddr); ok && !srv.NetRestrict.Contains(tcp.IP) {
+			if ip, _ := splitAddr(fd.RemoteAddr()); ip != nil && !srv.NetRestrict.Contains(ip) {
 				srv.log.Debug("Rejected conn (not whitelisted in NetRestrict)", "addr", fd.RemoteAddr())
 				fd.Close()
 				slots <- struct{}{}
@@ -968,6 +1232,7 @@ type NodeInfo struct {
 	ID    string `json:"id"`    // Unique node identifier (also the encryption key)
 	Name  string `json:"name"`  // Name of the node, including client type, version, OS, custom data
 	Enode string `json:"enode"` // Enode URL for adding this peer from remote peers
+	ENR   string `json:"enr"`   // Signed node record in its text form
 	IP    string `json:"ip"`    // IP address of the node
 	Ports struct {
 		Discovery int `json:"discovery"` // UDP listening port for discovery protocol
@@ -992,6 +1257,9 @@ func (srv *Server) NodeInfo() *NodeInfo {
 	}
 	info.Ports.Discovery = int(node.UDP)
 	info.Ports.Listener = int(node.TCP)
+	if record := srv.LocalRecord(); record != nil {
+		info.ENR = discover.RecordText(record.Record())
+	}
 
 	// Gather all the running protocol infos (only once per protocol type)
 	for _, proto := range srv.Protocols {
@@ -29,6 +29,7 @@ import (
 	"github.com/rwdxchain/go-rwdxchaina/crypto/sha3"
 	"github.com/rwdxchain/go-rwdxchaina/log"
 	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/enr"
 )
 
 func init() {
@@ -122,6 +123,52 @@ func TestServerListen(t *testing.T) {
 	}
 }
 
+// Tests that the server maintains a signed local node record, containing the
+// endpoint and the protocol attributes, and publishes it in the node info.
+func TestServerNodeRecord(t *testing.T) {
+	srv := &Server{
+		Config: Config{
+			Name:       "test",
+			MaxPeers:   10,
+			ListenAddr: "127.0.0.1:0",
+			PrivateKey: newkey(),
+			Protocols:  []Protocol{{Name: "test", Attributes: []enr.Entry{enr.WithEntry("test", uint(1))}}},
+		},
+	}
+	if srv.LocalRecord() != nil {
+		t.Fatal("local record available before start")
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start server: %v", err)
+	}
+	defer srv.Stop()
+
+	info := srv.NodeInfo()
+	record, err := discover.ParseRecord(info.ENR)
+	if err != nil {
+		t.Fatalf("invalid node info record %q: %v", info.ENR, err)
+	}
+	node, err := discover.NodeFromRecord(record)
+	if err != nil {
+		t.Fatalf("invalid node record: %v", err)
+	}
+	if self := srv.Self(); node.ID != self.ID || !node.IP.Equal(self.IP) || node.UDP != self.UDP {
+		t.Errorf("record mismatch: got %v, want %v", node, self)
+	}
+	if listener := srv.listener.Addr().(*net.TCPAddr); int(node.TCP) != listener.Port {
+		t.Errorf("record TCP port mismatch: got %d, want %d", node.TCP, listener.Port)
+	}
+	var attr uint
+	if err := record.Load(enr.WithEntry("test", &attr)); err != nil || attr != 1 {
+		t.Errorf("protocol attribute mismatch: got %d (%v), want %d", attr, err, 1)
+	}
+	// Updating the attributes publishes a newer record
+	srv.LocalRecord().Set(enr.WithEntry("test", uint(2)))
+	if updated, _ := discover.ParseRecord(srv.NodeInfo().ENR); updated == nil || updated.Seq() != record.Seq()+1 {
+		t.Errorf("record not updated: got %v, want seq %d", updated, record.Seq()+1)
+	}
+}
+
 func TestServerDial(t *testing.T) {
 	// run a one-shot TCP server to handle the connection.
 	listener, err := net.Listen("tcp", "127.0.0.1:0")
@@ -472,6 +519,151 @@ func TestServerPeerLimits(t *testing.T) {
 	conn.Close()
 }
 
+// startSlotTestServer starts a server which doesn't dial and accepts peers
+// injected with addSlotTestPeer.
+func startSlotTestServer(t *testing.T, maxPeers int, protocols ...Protocol) *Server {
+	srv := &Server{
+		Config: Config{
+			PrivateKey: newkey(),
+			MaxPeers:   maxPeers,
+			NoDial:     true,
+			Protocols:  protocols,
+		},
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start: %v", err)
+	}
+	return srv
+}
+
+// addSlotTestPeer injects an inbound connection with the given capabilities.
+func addSlotTestPeer(srv *Server, id discover.NodeID, caps ...Cap) error {
+	fd, _ := net.Pipe()
+	c := &conn{fd: fd, transport: newTestTransport(id, fd), flags: inboundConn, id: id, caps: caps, cont: make(chan error)}
+	if err := srv.checkpoint(c, srv.posthandshake); err != nil {
+		return err
+	}
+	return srv.checkpoint(c, srv.addpeer)
+}
+
+func slotTestProtocol(name string, min, max int) Protocol {
+	proto := discard
+	proto.Name, proto.MinPeers, proto.MaxPeers = name, min, max
+	return proto
+}
+
+func TestServerProtocolSlots(t *testing.T) {
+	var (
+		a   = slotTestProtocol("a", 2, 0)
+		b   = slotTestProtocol("b", 0, 1)
+		c   = slotTestProtocol("c", 0, 0)
+		srv = startSlotTestServer(t, 4, a, b, c)
+	)
+	defer srv.Stop()
+
+	tests := []struct {
+		caps []Cap
+		err  error
+	}{
+		{[]Cap{b.cap()}, nil},
+		{[]Cap{b.cap(), c.cap()}, DiscTooManyPeers}, // b is at its maximum
+		{[]Cap{c.cap()}, nil},
+		{[]Cap{c.cap()}, DiscTooManyPeers}, // remaining slots are reserved for a
+		{[]Cap{a.cap()}, nil},
+		{[]Cap{a.cap(), c.cap()}, nil},
+		{[]Cap{a.cap()}, DiscTooManyPeers}, // server is full
+	}
+	for i, test := range tests {
+		if err := addSlotTestPeer(srv, randomID(), test.caps...); err != test.err {
+			t.Errorf("peer %d: wrong error %v, want %v", i, err, test.err)
+		}
+	}
+}
+
+func TestServerEviction(t *testing.T) {
+	srv := startSlotTestServer(t, 2, discard)
+	defer srv.Stop()
+
+	events := make(chan *PeerEvent, 10)
+	sub := srv.SubscribeEvents(events)
+	defer sub.Unsubscribe()
+
+	for i := 0; i < 2; i++ {
+		if err := addSlotTestPeer(srv, randomID(), discard.cap()); err != nil {
+			t.Fatalf("could not add peer %d: %v", i, err)
+		}
+	}
+	// Without misbehaving peers, the server is full.
+	if err := addSlotTestPeer(srv, randomID(), discard.cap()); err != DiscTooManyPeers {
+		t.Fatalf("wrong error for peer above limit: %v", err)
+	}
+	// Peers with a negative score get evicted, starting with the lowest score.
+	peers := srv.Peers()
+	peers[0].AdjustScore(ScoreUseless)
+	peers[1].AdjustScore(ScoreTimeout)
+	if err := addSlotTestPeer(srv, randomID(), discard.cap()); err != nil {
+		t.Fatalf("peer not accepted after eviction: %v", err)
+	}
+	for ev := range events {
+		if ev.Type != PeerEventTypeDrop {
+			continue
+		}
+		if ev.Peer != peers[1].ID() {
+			t.Fatalf("wrong peer evicted: %v", ev.Peer)
+		}
+		break
+	}
+}
+
+func TestServerBan(t *testing.T) {
+	srv := startSlotTestServer(t, 10, discard)
+	defer srv.Stop()
+
+	waitDrop := func(id discover.NodeID) {
+		for {
+			connected := false
+			for _, p := range srv.Peers() {
+				connected = connected || p.ID() == id
+			}
+			if !connected {
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	// Peers reaching the ban threshold get disconnected and banned.
+	offender := randomID()
+	if err := addSlotTestPeer(srv, offender, discard.cap()); err != nil {
+		t.Fatalf("could not add peer: %v", err)
+	}
+	p := srv.Peers()[0]
+	for p.Score() > banScore {
+		p.AdjustScore(ScoreTimeout)
+	}
+	waitDrop(offender)
+	if err := addSlotTestPeer(srv, offender, discard.cap()); err != DiscUselessPeer {
+		t.Errorf("wrong error for banned peer: %v", err)
+	}
+
+	// BanPeer disconnects and bans connected peers.
+	banned := randomID()
+	if err := addSlotTestPeer(srv, banned, discard.cap()); err != nil {
+		t.Fatalf("could not add peer: %v", err)
+	}
+	srv.BanPeer(&discover.Node{ID: banned})
+	waitDrop(banned)
+	if err := addSlotTestPeer(srv, banned, discard.cap()); err != DiscUselessPeer {
+		t.Errorf("wrong error for banned peer: %v", err)
+	}
+
+	// Trusted nodes ignore bans.
+	srv.AddTrustedPeer(&discover.Node{ID: banned})
+	if err := addSlotTestPeer(srv, banned, discard.cap()); err != nil {
+		t.Errorf("trusted peer not accepted: %v", err)
+	}
+}
+
 func TestServerSetupConn(t *testing.T) {
 	id := randomID()
 	srvkey := newkey()
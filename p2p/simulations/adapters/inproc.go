@@ -39,6 +39,7 @@ type SimAdapter struct {
 	mtx      sync.RWMutex
 	nodes    map[discover.NodeID]*SimNode
 	services map[string]ServiceFunc
+	links    *linkTable
 }
 
 // NewSimAdapter creates a SimAdapter which is capable of running in-memory
@@ -50,6 +51,7 @@ func NewSimAdapter(services map[string]ServiceFunc) *SimAdapter {
 		pipe:     pipes.NetPipe,
 		nodes:    make(map[discover.NodeID]*SimNode),
 		services: services,
+		links:    newLinkTable(),
 	}
 }
 
@@ -58,6 +60,7 @@ func NewTCPAdapter(services map[string]ServiceFunc) *SimAdapter {
 		pipe:     pipes.TCPPipe,
 		nodes:    make(map[discover.NodeID]*SimNode),
 		services: services,
+		links:    newLinkTable(),
 	}
 }
 
@@ -87,14 +90,20 @@ func (s *SimAdapter) NewNode(config *NodeConfig) (Node, error) {
 		}
 	}
 
+	p2pConfig := p2p.Config{
+		PrivateKey:      config.PrivateKey,
+		MaxPeers:        math.MaxInt32,
+		NoDiscovery:     true,
+		Dialer:          &simDialer{s, id},
+		EnableMsgEvents: config.EnableMsgEvents,
+	}
+	if config.DiscoveryV5 {
+		// The discovery runs on UDP, peer connections still use pipes.
+		p2pConfig.DiscoveryV5 = true
+		p2pConfig.ListenAddr = "127.0.0.1:0"
+	}
 	n, err := node.New(&node.Config{
-		P2P: p2p.Config{
-			PrivateKey:      config.PrivateKey,
-			MaxPeers:        math.MaxInt32,
-			NoDiscovery:     true,
-			Dialer:          s,
-			EnableMsgEvents: config.EnableMsgEvents,
-		},
+		P2P:    p2pConfig,
 		NoUSB:  true,
 		Logger: log.New("node.id", id.String()),
 	})
@@ -114,8 +123,24 @@ func (s *SimAdapter) NewNode(config *NodeConfig) (Node, error) {
 }
 
 // Dial implements the p2p.NodeDialer interface by connecting to the node using
-// an in-memory net.Pipe
+// an in-memory net.Pipe. The connection doesn't emulate a link, nodes of the
+// adapter dial through their own dialer which does.
 func (s *SimAdapter) Dial(dest *discover.Node) (conn net.Conn, err error) {
+	return s.dial(nil, dest)
+}
+
+// simDialer dials the nodes of the adapter on behalf of a node, applying the
+// link between the two nodes to the connection.
+type simDialer struct {
+	adapter *SimAdapter
+	id      discover.NodeID
+}
+
+func (d *simDialer) Dial(dest *discover.Node) (net.Conn, error) {
+	return d.adapter.dial(&d.id, dest)
+}
+
+func (s *SimAdapter) dial(from *discover.NodeID, dest *discover.Node) (net.Conn, error) {
 	node, ok := s.GetNode(dest.ID)
 	if !ok {
 		return nil, fmt.Errorf("unknown node: %s", dest.ID)
@@ -129,6 +154,15 @@ func (s *SimAdapter) Dial(dest *discover.Node) (conn net.Conn, err error) {
 	if err != nil {
 		return nil, err
 	}
+	if from != nil {
+		dialer, listener, err := s.links.wrap(*from, dest.ID, pipe2, pipe1)
+		if err != nil {
+			pipe1.Close()
+			pipe2.Close()
+			return nil, err
+		}
+		pipe1, pipe2 = listener, dialer
+	}
 	// this is simulated 'listening'
 	// asynchronously call the dialed destintion node's p2p server
 	// to set up connection on the 'listening' side
@@ -150,6 +184,21 @@ func (s *SimAdapter) DialRPC(id discover.NodeID) (*rpc.Client, error) {
 	return rpc.DialInProc(handler), nil
 }
 
+// SetLink implements LinkEmulator.
+func (s *SimAdapter) SetLink(one, other discover.NodeID, config LinkConfig) {
+	s.links.set(one, other, config)
+}
+
+// Links implements LinkEmulator.
+func (s *SimAdapter) Links() []Link {
+	return s.links.list()
+}
+
+// SetLinkSeed implements LinkEmulator.
+func (s *SimAdapter) SetLinkSeed(seed int64) {
+	s.links.setSeed(seed)
+}
+
 // GetNode returns the node with the given ID if it exists
 func (s *SimAdapter) GetNode(id discover.NodeID) (*SimNode, bool) {
 	s.mtx.RLock()
@@ -0,0 +1,387 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package adapters
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+)
+
+const (
+	linkQueueSize = 64                     // number of writes in flight per connection and direction
+	minRetransmit = 200 * time.Millisecond // lower bound of the retransmission delay of lost writes
+)
+
+// ErrLinkDown is returned when dialing a node over a link which is down.
+var ErrLinkDown = errors.New("link is down")
+
+// LinkEmulator is implemented by node adapters which can emulate the quality of
+// the links between their nodes. Links are symmetric, the configuration of a
+// link applies to both directions.
+type LinkEmulator interface {
+	// SetLink configures the link between two nodes. The configuration also
+	// applies to existing connections. Connections are closed when the link
+	// goes down.
+	SetLink(one, other discover.NodeID, config LinkConfig)
+
+	// Links returns the configured links.
+	Links() []Link
+
+	// SetLinkSeed seeds the random number generators of links created after
+	// the call. Equal seeds lead to equal delays for equal traffic.
+	SetLinkSeed(seed int64)
+}
+
+// LinkConfig describes the quality of a link. The zero value is a perfect link.
+//
+// Links emulate reliable stream connections. Lost writes are retransmitted,
+// i.e. delivered after an additional delay of at least two latencies or
+// 200ms, delaying the writes following them as well.
+type LinkConfig struct {
+	Latency   time.Duration // one-way delay of writes
+	Jitter    time.Duration // maximum random delay added to the latency
+	Bandwidth int           // bytes per second and direction, zero means unlimited
+	Loss      float64       // probability of a write being lost and retransmitted
+	Down      bool          // the link refuses and closes connections
+}
+
+// Link is the configuration of the link between two nodes.
+type Link struct {
+	One    discover.NodeID `json:"one"`
+	Other  discover.NodeID `json:"other"`
+	Config LinkConfig      `json:"config"`
+}
+
+type linkConfigJSON struct {
+	Latency   string  `json:"latency,omitempty"`
+	Jitter    string  `json:"jitter,omitempty"`
+	Bandwidth int     `json:"bandwidth,omitempty"`
+	Loss      float64 `json:"loss,omitempty"`
+	Down      bool    `json:"down,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler, encoding durations as strings like "150ms".
+func (c LinkConfig) MarshalJSON() ([]byte, error) {
+	enc := linkConfigJSON{Bandwidth: c.Bandwidth, Loss: c.Loss, Down: c.Down}
+	if c.Latency != 0 {
+		enc.Latency = c.Latency.String()
+	}
+	if c.Jitter != 0 {
+		enc.Jitter = c.Jitter.String()
+	}
+	return json.Marshal(enc)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *LinkConfig) UnmarshalJSON(data []byte) error {
+	var dec linkConfigJSON
+	if err := json.Unmarshal(data, &dec); err != nil {
+		return err
+	}
+	config := LinkConfig{Bandwidth: dec.Bandwidth, Loss: dec.Loss, Down: dec.Down}
+	var err error
+	if dec.Latency != "" {
+		if config.Latency, err = time.ParseDuration(dec.Latency); err != nil {
+			return err
+		}
+	}
+	if dec.Jitter != "" {
+		if config.Jitter, err = time.ParseDuration(dec.Jitter); err != nil {
+			return err
+		}
+	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	*c = config
+	return nil
+}
+
+// Validate checks the configuration for invalid values.
+func (c LinkConfig) Validate() error {
+	switch {
+	case c.Latency < 0 || c.Jitter < 0:
+		return errors.New("negative link delay")
+	case c.Bandwidth < 0:
+		return errors.New("negative link bandwidth")
+	case c.Loss < 0 || c.Loss >= 1:
+		return fmt.Errorf("link loss %v out of range [0, 1)", c.Loss)
+	}
+	return nil
+}
+
+// linkKey identifies the link between two nodes regardless of direction.
+type linkKey [2]discover.NodeID
+
+func newLinkKey(one, other discover.NodeID) linkKey {
+	if bytes.Compare(one[:], other[:]) > 0 {
+		one, other = other, one
+	}
+	return linkKey{one, other}
+}
+
+// linkTable tracks the link configurations and connections of an adapter.
+type linkTable struct {
+	mu      sync.Mutex
+	seed    int64
+	configs map[linkKey]LinkConfig
+	conns   map[linkKey]map[*linkConn]struct{}
+	counts  map[linkKey]uint64 // number of connections created per link
+}
+
+func newLinkTable() *linkTable {
+	return &linkTable{
+		configs: make(map[linkKey]LinkConfig),
+		conns:   make(map[linkKey]map[*linkConn]struct{}),
+		counts:  make(map[linkKey]uint64),
+	}
+}
+
+func (t *linkTable) setSeed(seed int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.seed = seed
+	t.counts = make(map[linkKey]uint64)
+}
+
+func (t *linkTable) set(one, other discover.NodeID, config LinkConfig) {
+	key := newLinkKey(one, other)
+	t.mu.Lock()
+	if config == (LinkConfig{}) {
+		delete(t.configs, key)
+	} else {
+		t.configs[key] = config
+	}
+	var closing []*linkConn
+	if config.Down {
+		for c := range t.conns[key] {
+			closing = append(closing, c)
+		}
+	}
+	t.mu.Unlock()
+
+	for _, c := range closing {
+		c.Close()
+	}
+}
+
+func (t *linkTable) config(key linkKey) LinkConfig {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.configs[key]
+}
+
+func (t *linkTable) list() []Link {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	links := make([]Link, 0, len(t.configs))
+	for key, config := range t.configs {
+		links = append(links, Link{One: key[0], Other: key[1], Config: config})
+	}
+	return links
+}
+
+// wrap applies the link between the two nodes to both ends of a connection,
+// or fails if the link is down. dialer is the end of the dialing node.
+func (t *linkTable) wrap(from, to discover.NodeID, dialer, listener net.Conn) (net.Conn, net.Conn, error) {
+	key := newLinkKey(from, to)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.configs[key].Down {
+		return nil, nil, ErrLinkDown
+	}
+	n := t.counts[key]
+	t.counts[key]++
+	if t.conns[key] == nil {
+		t.conns[key] = make(map[*linkConn]struct{})
+	}
+	c1 := newLinkConn(t, key, dialer, t.connSeed(key, n, from))
+	c2 := newLinkConn(t, key, listener, t.connSeed(key, n, to))
+	t.conns[key][c1] = struct{}{}
+	t.conns[key][c2] = struct{}{}
+	return c1, c2, nil
+}
+
+// connSeed derives the seed of one end of a connection from the table seed, so
+// that the delays of a connection don't depend on the traffic of others.
+func (t *linkTable) connSeed(key linkKey, n uint64, writer discover.NodeID) int64 {
+	h := fnv.New64a()
+	binary.Write(h, binary.BigEndian, t.seed)
+	h.Write(key[0][:])
+	h.Write(key[1][:])
+	binary.Write(h, binary.BigEndian, n)
+	h.Write(writer[:])
+	return int64(h.Sum64())
+}
+
+func (t *linkTable) remove(c *linkConn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.conns[c.key], c)
+	if len(t.conns[c.key]) == 0 {
+		delete(t.conns, c.key)
+	}
+}
+
+// linkConn delays the writes to a connection according to the configuration of
+// its link. Writes are queued and delivered in order by a separate goroutine.
+type linkConn struct {
+	net.Conn
+	table *linkTable
+	key   linkKey
+
+	queue     chan linkWrite
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	mu   sync.Mutex
+	rand *rand.Rand
+	busy time.Time // time at which the link is done transmitting queued writes
+	last time.Time // delivery time of the last queued write
+	err  error     // error of a delivery
+}
+
+type linkWrite struct {
+	data    []byte
+	deliver time.Time
+}
+
+func newLinkConn(table *linkTable, key linkKey, conn net.Conn, seed int64) *linkConn {
+	c := &linkConn{
+		Conn:   conn,
+		table:  table,
+		key:    key,
+		queue:  make(chan linkWrite, linkQueueSize),
+		closed: make(chan struct{}),
+		rand:   rand.New(rand.NewSource(seed)),
+	}
+	go c.deliver()
+	return c
+}
+
+var errLinkConnClosed = errors.New("use of closed network connection")
+
+// Write queues data for delivery after the delay of the link.
+func (c *linkConn) Write(b []byte) (int, error) {
+	select {
+	case <-c.closed:
+		return 0, errLinkConnClosed
+	default:
+	}
+	config := c.table.config(c.key)
+
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return 0, c.err
+	}
+	w := linkWrite{data: make([]byte, len(b)), deliver: c.deliveryTime(config, len(b))}
+	c.mu.Unlock()
+	copy(w.data, b)
+
+	select {
+	case c.queue <- w:
+		return len(b), nil
+	case <-c.closed:
+		return 0, errLinkConnClosed
+	}
+}
+
+// deliveryTime computes when a write of the given size arrives.
+func (c *linkConn) deliveryTime(config LinkConfig, size int) time.Time {
+	now := time.Now()
+	sent := now
+	if config.Bandwidth > 0 {
+		if c.busy.After(sent) {
+			sent = c.busy
+		}
+		sent = sent.Add(time.Duration(size) * time.Second / time.Duration(config.Bandwidth))
+		c.busy = sent
+	}
+	delay := config.Latency
+	if config.Jitter > 0 {
+		delay += time.Duration(c.rand.Int63n(int64(config.Jitter) + 1))
+	}
+	if config.Loss > 0 && c.rand.Float64() < config.Loss {
+		retransmit := 2 * delay
+		if retransmit < minRetransmit {
+			retransmit = minRetransmit
+		}
+		delay += retransmit
+	}
+	deliver := sent.Add(delay)
+	if deliver.Before(c.last) {
+		deliver = c.last // streams are delivered in order
+	}
+	c.last = deliver
+	return deliver
+}
+
+func (c *linkConn) deliver() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		var w linkWrite
+		select {
+		case w = <-c.queue:
+		case <-c.closed:
+			return
+		}
+		if wait := time.Until(w.deliver); wait > 0 {
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(wait)
+			select {
+			case <-timer.C:
+			case <-c.closed:
+				return
+			}
+		}
+		if _, err := c.Conn.Write(w.data); err != nil {
+			c.mu.Lock()
+			c.err = err
+			c.mu.Unlock()
+			c.Close()
+			return
+		}
+	}
+}
+
+// Close closes the connection, dropping writes which weren't delivered yet.
+func (c *linkConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		err = c.Conn.Close()
+		c.table.remove(c)
+	})
+	return err
+}
@@ -0,0 +1,164 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package adapters
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+)
+
+var (
+	linkTestA = discover.NodeID{1}
+	linkTestB = discover.NodeID{2}
+)
+
+func newLinkTestConns(t *testing.T, table *linkTable) (net.Conn, net.Conn) {
+	p1, p2 := net.Pipe()
+	c1, c2, err := table.wrap(linkTestA, linkTestB, p1, p2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c1, c2
+}
+
+// transfer writes data to one end of a connection and returns the time it took
+// to read it from the other end.
+func transfer(t *testing.T, from, to net.Conn, data []byte) time.Duration {
+	start := time.Now()
+	go from.Write(data)
+	buf := make([]byte, len(data))
+	if _, err := io.ReadFull(to, buf); err != nil {
+		t.Fatal(err)
+	}
+	return time.Since(start)
+}
+
+func TestLinkLatency(t *testing.T) {
+	table := newLinkTable()
+	c1, c2 := newLinkTestConns(t, table)
+	defer c1.Close()
+
+	if d := transfer(t, c1, c2, []byte("ping")); d > 50*time.Millisecond {
+		t.Errorf("perfect link took %v", d)
+	}
+	// Configuration changes apply to existing connections, in both directions.
+	table.set(linkTestB, linkTestA, LinkConfig{Latency: 100 * time.Millisecond})
+	if d := transfer(t, c1, c2, []byte("ping")); d < 100*time.Millisecond {
+		t.Errorf("link with 100ms latency took %v", d)
+	}
+	if d := transfer(t, c2, c1, []byte("pong")); d < 100*time.Millisecond {
+		t.Errorf("link with 100ms latency took %v in reverse direction", d)
+	}
+}
+
+func TestLinkBandwidth(t *testing.T) {
+	table := newLinkTable()
+	table.set(linkTestA, linkTestB, LinkConfig{Bandwidth: 10000})
+	c1, c2 := newLinkTestConns(t, table)
+	defer c1.Close()
+
+	// 2000 bytes at 10000 bytes/s take 200ms.
+	if d := transfer(t, c1, c2, make([]byte, 2000)); d < 200*time.Millisecond {
+		t.Errorf("transfer took %v", d)
+	}
+}
+
+func TestLinkDown(t *testing.T) {
+	table := newLinkTable()
+	c1, c2 := newLinkTestConns(t, table)
+
+	table.set(linkTestA, linkTestB, LinkConfig{Down: true})
+	if _, err := c2.Read(make([]byte, 1)); err == nil {
+		t.Error("read from connection of link which is down succeeded")
+	}
+	if _, err := c1.Write([]byte("x")); err == nil {
+		t.Error("write to connection of link which is down succeeded")
+	}
+	if _, _, err := table.wrap(linkTestA, linkTestB, nil, nil); err != ErrLinkDown {
+		t.Errorf("wrong error for link which is down: %v", err)
+	}
+	if len(table.conns) != 0 {
+		t.Errorf("closed connections still tracked: %v", table.conns)
+	}
+
+	table.set(linkTestA, linkTestB, LinkConfig{})
+	c1, c2 = newLinkTestConns(t, table)
+	defer c1.Close()
+	transfer(t, c1, c2, []byte("ping"))
+}
+
+// Tests that links with equal seeds delay writes by equal amounts.
+func TestLinkSeed(t *testing.T) {
+	config := LinkConfig{Latency: time.Second, Jitter: time.Second, Loss: 0.2}
+	delays := func(seed int64) []time.Duration {
+		table := newLinkTable()
+		table.setSeed(seed)
+		table.set(linkTestA, linkTestB, config)
+		c1, _ := newLinkTestConns(t, table)
+		defer c1.Close()
+
+		lc := c1.(*linkConn)
+		var delays []time.Duration
+		for i := 0; i < 20; i++ {
+			lc.last = time.Time{} // disable ordering
+			start := time.Now()
+			delays = append(delays, lc.deliveryTime(config, 100).Sub(start).Round(time.Millisecond))
+		}
+		return delays
+	}
+
+	first, second := delays(1), delays(1)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("delays differ for equal seeds:\n%v\n%v", first, second)
+	}
+	if reflect.DeepEqual(first, delays(2)) {
+		t.Error("delays equal for different seeds")
+	}
+	for _, d := range first {
+		if d < config.Latency || d > 3*(config.Latency+config.Jitter) {
+			t.Errorf("delay %v out of range", d)
+		}
+	}
+}
+
+func TestLinkConfigJSON(t *testing.T) {
+	config := LinkConfig{Latency: 150 * time.Millisecond, Jitter: 20 * time.Millisecond, Bandwidth: 1 << 20, Loss: 0.01}
+	enc, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"latency":"150ms","jitter":"20ms","bandwidth":1048576,"loss":0.01}`
+	if string(enc) != want {
+		t.Errorf("wrong encoding: got %s, want %s", enc, want)
+	}
+	var dec LinkConfig
+	if err := json.Unmarshal(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if dec != config {
+		t.Errorf("wrong decoding: got %+v, want %+v", dec, config)
+	}
+	if err := json.Unmarshal([]byte(`{"loss":1.5}`), &dec); err == nil {
+		t.Error("decoding invalid loss succeeded")
+	}
+}
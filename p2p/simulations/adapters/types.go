@@ -99,6 +99,11 @@ type NodeConfig struct {
 	// function to sanction or prevent suggesting a peer
 	Reachable func(id discover.NodeID) bool
 
+	// DiscoveryV5 enables the V5 discovery protocol, which lets the node
+	// find peers by the topics of its protocols. Sim nodes run it on a
+	// UDP port of the loopback interface.
+	DiscoveryV5 bool
+
 	Port uint16
 }
 
@@ -111,6 +116,7 @@ type nodeConfigJSON struct {
 	Services        []string `json:"services"`
 	EnableMsgEvents bool     `json:"enable_msg_events"`
 	Port            uint16   `json:"port"`
+	DiscoveryV5     bool     `json:"discovery_v5,omitempty"`
 }
 
 // MarshalJSON implements the json.Marshaler interface by encoding the config
@@ -122,6 +128,7 @@ func (n *NodeConfig) MarshalJSON() ([]byte, error) {
 		Services:        n.Services,
 		Port:            n.Port,
 		EnableMsgEvents: n.EnableMsgEvents,
+		DiscoveryV5:     n.DiscoveryV5,
 	}
 	if n.PrivateKey != nil {
 		confJSON.PrivateKey = hex.EncodeToString(crypto.FromECDSA(n.PrivateKey))
@@ -161,6 +168,7 @@ func (n *NodeConfig) UnmarshalJSON(data []byte) error {
 	n.Services = confJSON.Services
 	n.Port = confJSON.Port
 	n.EnableMsgEvents = confJSON.EnableMsgEvents
+	n.DiscoveryV5 = confJSON.DiscoveryV5
 
 	return nil
 }
@@ -0,0 +1,146 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/node"
+	"github.com/rwdxchain/go-rwdxchaina/p2p"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discv5"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/simulations/adapters"
+	"github.com/rwdxchain/go-rwdxchaina/rpc"
+)
+
+const testDiscoveryTopic = discv5.Topic("simtest")
+
+// topicService runs a protocol which advertises and searches discovery topics.
+type topicService struct {
+	advertise, search []discv5.Topic
+}
+
+func (s *topicService) Protocols() []p2p.Protocol {
+	return []p2p.Protocol{{
+		Name:            "topic",
+		Version:         1,
+		Length:          1,
+		AdvertiseTopics: s.advertise,
+		SearchTopics:    s.search,
+		Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+			for {
+				msg, err := rw.ReadMsg()
+				if err != nil {
+					return err
+				}
+				msg.Discard()
+			}
+		},
+	}}
+}
+
+func (s *topicService) APIs() []rpc.API                { return nil }
+func (s *topicService) Start(server *p2p.Server) error { return nil }
+func (s *topicService) Stop() error                    { return nil }
+
+var topicServices = adapters.Services{
+	"plain": func(ctx *adapters.ServiceContext) (node.Service, error) {
+		return &topicService{}, nil
+	},
+	"advertiser": func(ctx *adapters.ServiceContext) (node.Service, error) {
+		return &topicService{advertise: []discv5.Topic{testDiscoveryTopic}}, nil
+	},
+	"searcher": func(ctx *adapters.ServiceContext) (node.Service, error) {
+		return &topicService{search: []discv5.Topic{testDiscoveryTopic}}, nil
+	},
+}
+
+// Tests that nodes dial the peers they find by searching the discovery topics
+// of their protocols.
+func TestDiscoveryTopics(t *testing.T) {
+	net := NewNetwork(adapters.NewSimAdapter(topicServices), &NetworkConfig{})
+	defer net.Shutdown()
+
+	var ids []discover.NodeID
+	for _, service := range []string{"plain", "plain", "advertiser", "plain", "searcher"} {
+		config := adapters.RandomNodeConfig()
+		config.Services = []string{service}
+		config.DiscoveryV5 = true
+		node, err := net.NewNodeWithConfig(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := net.Start(node.ID()); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, node.ID())
+	}
+	boot, advertiser, searcher := ids[0], ids[2], ids[4]
+
+	// Bootstrap the discovery of all nodes from the first one.
+	server := func(id discover.NodeID) *p2p.Server {
+		return net.GetNode(id).Node.(*adapters.SimNode).Server()
+	}
+	bootnode := server(boot).DiscV5.Self()
+	for _, id := range ids[1:] {
+		if err := server(id).DiscV5.SetFallbackNodes([]*discv5.Node{bootnode}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deadline := time.Now().Add(60 * time.Second)
+	for {
+		if c := net.GetConn(searcher, advertiser); c != nil && c.Up {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("searching node didn't connect to advertising node")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	for _, c := range upConns(net) {
+		if c.One == searcher && c.Other != advertiser {
+			t.Errorf("searching node dialed %v, which doesn't advertise the topic", c.Other)
+		}
+	}
+
+	// Check the topics reported by the admin API.
+	checkTopic := func(id discover.NodeID, advertised, searched bool) {
+		client, err := net.GetNode(id).Client()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var topics []discv5.TopicInfo
+		if err := client.Call(&topics, "admin_discoveryTopics"); err != nil {
+			t.Fatal(err)
+		}
+		for _, info := range topics {
+			if info.Topic == testDiscoveryTopic {
+				if info.Advertised != advertised || info.Searched != searched {
+					t.Errorf("wrong topic info of node %v: %+v", id, info)
+				}
+				return
+			}
+		}
+		if advertised || searched {
+			t.Errorf("topic missing in topics of node %v: %+v", id, topics)
+		}
+	}
+	checkTopic(advertiser, true, false)
+	checkTopic(searcher, false, true)
+}
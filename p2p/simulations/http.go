@@ -205,6 +205,37 @@ func (c *Client) DisconnectNode(nodeID, peerID string) error {
 	return c.Delete(fmt.Sprintf("/nodes/%s/conn/%s", nodeID, peerID))
 }
 
+// GetLinks returns the link configuration of the network
+func (c *Client) GetLinks() (*LinkState, error) {
+	state := &LinkState{}
+	return state, c.Get("/links", state)
+}
+
+// SetLinkSeed seeds the randomness of the network's links
+func (c *Client) SetLinkSeed(seed int64) error {
+	return c.Post("/links/seed", seed, nil)
+}
+
+// SetLink configures the link between a node and a peer node
+func (c *Client) SetLink(nodeID, peerID string, config adapters.LinkConfig) error {
+	return c.Post(fmt.Sprintf("/nodes/%s/link/%s", nodeID, peerID), config, nil)
+}
+
+// ResetLink restores the perfect link between a node and a peer node
+func (c *Client) ResetLink(nodeID, peerID string) error {
+	return c.Delete(fmt.Sprintf("/nodes/%s/link/%s", nodeID, peerID))
+}
+
+// Partition splits the network into groups of nodes, given by ID or name
+func (c *Client) Partition(groups [][]string) error {
+	return c.Post("/partition", groups, nil)
+}
+
+// Heal removes a partition of the network
+func (c *Client) Heal() error {
+	return c.Post("/heal", nil, nil)
+}
+
 // RPCClient returns an RPC client connected to a node
 func (c *Client) RPCClient(ctx context.Context, nodeID string) (*rpc.Client, error) {
 	baseURL := strings.Replace(c.URL, "http", "ws", 1)
@@ -296,6 +327,12 @@ func NewServer(network *Network) *Server {
 	s.POST("/nodes/:nodeid/conn/:peerid", s.ConnectNode)
 	s.DELETE("/nodes/:nodeid/conn/:peerid", s.DisconnectNode)
 	s.GET("/nodes/:nodeid/rpc", s.NodeRPC)
+	s.POST("/nodes/:nodeid/link/:peerid", s.SetLink)
+	s.DELETE("/nodes/:nodeid/link/:peerid", s.ResetLink)
+	s.GET("/links", s.GetLinks)
+	s.POST("/links/seed", s.SetLinkSeed)
+	s.POST("/partition", s.Partition)
+	s.POST("/heal", s.Heal)
 
 	return s
 }
@@ -647,6 +684,100 @@ func (s *Server) DisconnectNode(w http.ResponseWriter, req *http.Request) {
 	s.JSON(w, http.StatusOK, node.NodeInfo())
 }
 
+// SetLink configures the link between a node and a peer node
+func (s *Server) SetLink(w http.ResponseWriter, req *http.Request) {
+	node := req.Context().Value("node").(*Node)
+	peer := req.Context().Value("peer").(*Node)
+
+	var config adapters.LinkConfig
+	if err := json.NewDecoder(req.Body).Decode(&config); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := s.network.SetLink(node.ID(), peer.ID(), config); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.JSON(w, http.StatusOK, config)
+}
+
+// ResetLink restores the perfect link between a node and a peer node
+func (s *Server) ResetLink(w http.ResponseWriter, req *http.Request) {
+	node := req.Context().Value("node").(*Node)
+	peer := req.Context().Value("peer").(*Node)
+
+	if err := s.network.SetLink(node.ID(), peer.ID(), adapters.LinkConfig{}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.JSON(w, http.StatusOK, adapters.LinkConfig{})
+}
+
+// GetLinks returns the link configuration of the network
+func (s *Server) GetLinks(w http.ResponseWriter, req *http.Request) {
+	state, err := s.network.LinkState()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.JSON(w, http.StatusOK, state)
+}
+
+// SetLinkSeed seeds the randomness of the network's links
+func (s *Server) SetLinkSeed(w http.ResponseWriter, req *http.Request) {
+	var seed int64
+	if err := json.NewDecoder(req.Body).Decode(&seed); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := s.network.SetLinkSeed(seed); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.JSON(w, http.StatusOK, seed)
+}
+
+// Partition splits the network into groups of nodes, which are given by ID or
+// name
+func (s *Server) Partition(w http.ResponseWriter, req *http.Request) {
+	var names [][]string
+	if err := json.NewDecoder(req.Body).Decode(&names); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	groups := make([][]discover.NodeID, len(names))
+	for i, group := range names {
+		for _, name := range group {
+			node := s.lookupNode(name)
+			if node == nil {
+				http.Error(w, fmt.Sprintf("unknown node %q", name), http.StatusBadRequest)
+				return
+			}
+			groups[i] = append(groups[i], node.ID())
+		}
+	}
+	if err := s.network.Partition(groups...); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.JSON(w, http.StatusOK, groups)
+}
+
+// Heal removes a partition of the network
+func (s *Server) Heal(w http.ResponseWriter, req *http.Request) {
+	if err := s.network.Heal(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
 // Options responds to the OPTIONS HTTP method by returning a 200 OK response
 // with the "Access-Control-Allow-Headers" header set to "Content-Type"
 func (s *Server) Options(w http.ResponseWriter, req *http.Request) {
@@ -709,12 +840,7 @@ func (s *Server) wrapHandler(handler http.HandlerFunc) httprouter.Handle {
 		ctx := context.Background()
 
 		if id := params.ByName("nodeid"); id != "" {
-			var node *Node
-			if nodeID, err := discover.HexID(id); err == nil {
-				node = s.network.GetNode(nodeID)
-			} else {
-				node = s.network.GetNodeByName(id)
-			}
+			node := s.lookupNode(id)
 			if node == nil {
 				http.NotFound(w, req)
 				return
@@ -723,12 +849,7 @@ func (s *Server) wrapHandler(handler http.HandlerFunc) httprouter.Handle {
 		}
 
 		if id := params.ByName("peerid"); id != "" {
-			var peer *Node
-			if peerID, err := discover.HexID(id); err == nil {
-				peer = s.network.GetNode(peerID)
-			} else {
-				peer = s.network.GetNodeByName(id)
-			}
+			peer := s.lookupNode(id)
 			if peer == nil {
 				http.NotFound(w, req)
 				return
@@ -739,3 +860,11 @@ func (s *Server) wrapHandler(handler http.HandlerFunc) httprouter.Handle {
 		handler(w, req.WithContext(ctx))
 	}
 }
+
+// lookupNode returns the node with the given ID or name
+func (s *Server) lookupNode(id string) *Node {
+	if nodeID, err := discover.HexID(id); err == nil {
+		return s.network.GetNode(nodeID)
+	}
+	return s.network.GetNodeByName(id)
+}
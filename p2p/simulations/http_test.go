@@ -18,6 +18,7 @@ package simulations
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"net/http/httptest"
@@ -43,7 +44,9 @@ type testService struct {
 	// peerCount is incremented once a peer handshake has been performed
 	peerCount int64
 
-	peers    map[discover.NodeID]*testPeer
+	// peers tracks the handshake state of connections, which is kept per
+	// connection because nodes may reconnect
+	peers    map[*p2p.Peer]*testPeer
 	peersMtx sync.Mutex
 
 	// state stores []byte which is used to test creating and loading
@@ -54,7 +57,7 @@ type testService struct {
 func newTestService(ctx *adapters.ServiceContext) (node.Service, error) {
 	svc := &testService{
 		id:    ctx.Config.ID,
-		peers: make(map[discover.NodeID]*testPeer),
+		peers: make(map[*p2p.Peer]*testPeer),
 	}
 	svc.state.Store(ctx.Snapshot)
 	return svc, nil
@@ -63,22 +66,35 @@ func newTestService(ctx *adapters.ServiceContext) (node.Service, error) {
 type testPeer struct {
 	testReady chan struct{}
 	dumReady  chan struct{}
+	dropped   chan struct{} // closed when the test protocol returns
 }
 
-func (t *testService) peer(id discover.NodeID) *testPeer {
+func (t *testService) peer(p *p2p.Peer) *testPeer {
 	t.peersMtx.Lock()
 	defer t.peersMtx.Unlock()
-	if peer, ok := t.peers[id]; ok {
+	if peer, ok := t.peers[p]; ok {
 		return peer
 	}
 	peer := &testPeer{
 		testReady: make(chan struct{}),
 		dumReady:  make(chan struct{}),
+		dropped:   make(chan struct{}),
 	}
-	t.peers[id] = peer
+	t.peers[p] = peer
 	return peer
 }
 
+// waitReady waits for a handshake of another protocol, failing if the test
+// protocol of the connection returned before completing it.
+func (peer *testPeer) waitReady(ready chan struct{}) error {
+	select {
+	case <-ready:
+		return nil
+	case <-peer.dropped:
+		return errors.New("peer dropped")
+	}
+}
+
 func (t *testService) Protocols() []p2p.Protocol {
 	return []p2p.Protocol{
 		{
@@ -136,7 +152,8 @@ func (t *testService) handshake(rw p2p.MsgReadWriter, code uint64) error {
 }
 
 func (t *testService) RunTest(p *p2p.Peer, rw p2p.MsgReadWriter) error {
-	peer := t.peer(p.ID())
+	peer := t.peer(p)
+	defer close(peer.dropped)
 
 	// perform three handshakes with three different message codes,
 	// used to test message sending and filtering
@@ -167,10 +184,12 @@ func (t *testService) RunTest(p *p2p.Peer, rw p2p.MsgReadWriter) error {
 }
 
 func (t *testService) RunDum(p *p2p.Peer, rw p2p.MsgReadWriter) error {
-	peer := t.peer(p.ID())
+	peer := t.peer(p)
 
 	// wait for the test protocol to perform its handshake
-	<-peer.testReady
+	if err := peer.waitReady(peer.testReady); err != nil {
+		return err
+	}
 
 	// perform a handshake
 	if err := t.handshake(rw, 0); err != nil {
@@ -189,10 +208,12 @@ func (t *testService) RunDum(p *p2p.Peer, rw p2p.MsgReadWriter) error {
 	}
 }
 func (t *testService) RunPrb(p *p2p.Peer, rw p2p.MsgReadWriter) error {
-	peer := t.peer(p.ID())
+	peer := t.peer(p)
 
 	// wait for the dum protocol to perform its handshake
-	<-peer.dumReady
+	if err := peer.waitReady(peer.dumReady); err != nil {
+		return err
+	}
 
 	// perform a handshake
 	if err := t.handshake(rw, 0); err != nil {
@@ -0,0 +1,188 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulations
+
+import (
+	"fmt"
+
+	"github.com/rwdxchain/go-rwdxchaina/log"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/simulations/adapters"
+)
+
+// LinkState is the link configuration of a network.
+type LinkState struct {
+	Seed  int64           `json:"seed"`
+	Links []adapters.Link `json:"links"`
+}
+
+// linkEmulator returns the node adapter if it supports link emulation.
+func (net *Network) linkEmulator() (adapters.LinkEmulator, error) {
+	le, ok := net.nodeAdapter.(adapters.LinkEmulator)
+	if !ok {
+		return nil, fmt.Errorf("%s doesn't support link emulation", net.nodeAdapter.Name())
+	}
+	return le, nil
+}
+
+// SetLink configures the link between two nodes. Configuring a link as down
+// disconnects the nodes and prevents them from connecting again.
+func (net *Network) SetLink(one, other discover.NodeID, config adapters.LinkConfig) error {
+	le, err := net.linkEmulator()
+	if err != nil {
+		return err
+	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	if net.GetNode(one) == nil {
+		return fmt.Errorf("node %v does not exist", one)
+	}
+	if net.GetNode(other) == nil {
+		return fmt.Errorf("node %v does not exist", other)
+	}
+	if one == other {
+		return fmt.Errorf("link of node %v to itself", one)
+	}
+	le.SetLink(one, other, config)
+	return nil
+}
+
+// Link returns the configuration of the link between two nodes.
+func (net *Network) Link(one, other discover.NodeID) adapters.LinkConfig {
+	le, err := net.linkEmulator()
+	if err != nil {
+		return adapters.LinkConfig{}
+	}
+	for _, l := range le.Links() {
+		if (l.One == one && l.Other == other) || (l.One == other && l.Other == one) {
+			return l.Config
+		}
+	}
+	return adapters.LinkConfig{}
+}
+
+// SetLinkSeed seeds the randomness of the links, i.e. jitter and loss, so that
+// runs can be reproduced.
+func (net *Network) SetLinkSeed(seed int64) error {
+	le, err := net.linkEmulator()
+	if err != nil {
+		return err
+	}
+	net.lock.Lock()
+	net.linkSeed = seed
+	net.lock.Unlock()
+	le.SetLinkSeed(seed)
+	return nil
+}
+
+// LinkState returns the link configuration of the network.
+func (net *Network) LinkState() (*LinkState, error) {
+	le, err := net.linkEmulator()
+	if err != nil {
+		return nil, err
+	}
+	net.lock.RLock()
+	defer net.lock.RUnlock()
+	return &LinkState{Seed: net.linkSeed, Links: le.Links()}, nil
+}
+
+// Partition splits the network into the given groups of nodes by taking down
+// all links between nodes of different groups. Nodes which are not part of a
+// group form a group of their own. A previous partition is healed first.
+func (net *Network) Partition(groups ...[]discover.NodeID) error {
+	if _, err := net.linkEmulator(); err != nil {
+		return err
+	}
+	group := make(map[discover.NodeID]int)
+	for i, g := range groups {
+		for _, id := range g {
+			if net.GetNode(id) == nil {
+				return fmt.Errorf("node %v does not exist", id)
+			}
+			group[id] = i + 1
+		}
+	}
+	if err := net.Heal(); err != nil {
+		return err
+	}
+
+	net.lock.Lock()
+	for _, c := range net.Conns {
+		if c.Up && group[c.One] != group[c.Other] {
+			net.cutConns = append(net.cutConns, c)
+		}
+	}
+	net.lock.Unlock()
+
+	nodes := net.GetNodes()
+	for i, one := range nodes {
+		for _, other := range nodes[i+1:] {
+			if group[one.ID()] != group[other.ID()] {
+				config := net.Link(one.ID(), other.ID())
+				config.Down = true
+				if err := net.SetLink(one.ID(), other.ID(), config); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Heal brings up all links which are down and reconnects the nodes whose
+// connections were cut by Partition.
+func (net *Network) Heal() error {
+	le, err := net.linkEmulator()
+	if err != nil {
+		return err
+	}
+	for _, l := range le.Links() {
+		if l.Config.Down {
+			l.Config.Down = false
+			le.SetLink(l.One, l.Other, l.Config)
+		}
+	}
+
+	net.lock.Lock()
+	cut := net.cutConns
+	net.cutConns = nil
+	net.lock.Unlock()
+	for _, c := range cut {
+		if err := net.reconnect(c.One, c.Other); err != nil {
+			log.Debug("Failed to restore connection after partition", "conn", c, "err", err)
+		}
+	}
+	return nil
+}
+
+// reconnect connects two nodes which were connected before. The dialing node
+// forgets the other one first, as it would otherwise delay the dial.
+func (net *Network) reconnect(one, other discover.NodeID) error {
+	node, peer := net.GetNode(one), net.GetNode(other)
+	if node == nil || peer == nil {
+		return fmt.Errorf("connection between %v and %v does not exist", one, other)
+	}
+	client, err := node.Client()
+	if err != nil {
+		return err
+	}
+	if err := client.Call(nil, "admin_removePeer", string(peer.Addr())); err != nil {
+		return err
+	}
+	return net.Connect(one, other)
+}
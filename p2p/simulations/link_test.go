@@ -0,0 +1,168 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulations
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/simulations/adapters"
+)
+
+// startLinkTestNetwork starts a fully connected network of n nodes.
+func startLinkTestNetwork(t *testing.T, n int) (*Network, []discover.NodeID) {
+	net := NewNetwork(adapters.NewSimAdapter(testServices), &NetworkConfig{DefaultService: "test"})
+	var ids []discover.NodeID
+	for i := 0; i < n; i++ {
+		node, err := net.NewNodeWithConfig(adapters.RandomNodeConfig())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := net.Start(node.ID()); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, node.ID())
+	}
+	for i := range ids {
+		for j := i + 1; j < len(ids); j++ {
+			if err := net.Connect(ids[i], ids[j]); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	waitUpConns(t, net, n*(n-1)/2)
+	return net, ids
+}
+
+func upConns(net *Network) (up []*Conn) {
+	net.lock.RLock()
+	defer net.lock.RUnlock()
+	for _, c := range net.Conns {
+		if c.Up {
+			up = append(up, c)
+		}
+	}
+	return up
+}
+
+func waitUpConns(t *testing.T, net *Network, n int) []*Conn {
+	var up []*Conn
+	for start := time.Now(); time.Since(start) < 10*time.Second; time.Sleep(50 * time.Millisecond) {
+		if up = upConns(net); len(up) == n {
+			return up
+		}
+	}
+	t.Fatalf("%d connections up, want %d", len(up), n)
+	return nil
+}
+
+func TestNetworkPartition(t *testing.T) {
+	net, ids := startLinkTestNetwork(t, 4)
+	defer net.Shutdown()
+
+	groups := [][]discover.NodeID{{ids[0], ids[1]}, {ids[2], ids[3]}}
+	if err := net.Partition(groups...); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range waitUpConns(t, net, 2) {
+		if !(c.One == ids[0] && c.Other == ids[1]) && !(c.One == ids[2] && c.Other == ids[3]) {
+			t.Errorf("connection %v across partition is up", c)
+		}
+	}
+	if !net.Link(ids[1], ids[2]).Down {
+		t.Error("link across partition is up")
+	}
+
+	if err := net.Heal(); err != nil {
+		t.Fatal(err)
+	}
+	waitUpConns(t, net, 6)
+	if state, _ := net.LinkState(); len(state.Links) != 0 {
+		t.Errorf("links left after healing: %v", state.Links)
+	}
+}
+
+func TestNetworkLinkLatency(t *testing.T) {
+	net, ids := startLinkTestNetwork(t, 2)
+	defer net.Shutdown()
+
+	if err := net.SetLink(ids[0], ids[1], adapters.LinkConfig{Latency: 200 * time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+	client, err := net.GetNode(ids[0]).Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Call(nil, "admin_removePeer", string(net.GetNode(ids[1]).Addr())); err != nil {
+		t.Fatal(err)
+	}
+	waitUpConns(t, net, 0)
+
+	// The handshake of a new connection takes at least a round trip.
+	start := time.Now()
+	if err := net.reconnect(ids[0], ids[1]); err != nil {
+		t.Fatal(err)
+	}
+	waitUpConns(t, net, 1)
+	if d := time.Since(start); d < 400*time.Millisecond {
+		t.Errorf("connection over link with 200ms latency established within %v", d)
+	}
+}
+
+func TestHTTPLinks(t *testing.T) {
+	net, ids := startLinkTestNetwork(t, 3)
+	defer net.Shutdown()
+	s := httptest.NewServer(NewServer(net))
+	defer s.Close()
+	client := NewClient(s.URL)
+
+	config := adapters.LinkConfig{Latency: 50 * time.Millisecond, Loss: 0.1}
+	if err := client.SetLink(ids[0].String(), ids[1].String(), config); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SetLinkSeed(42); err != nil {
+		t.Fatal(err)
+	}
+	state, err := client.GetLinks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Seed != 42 || len(state.Links) != 1 || state.Links[0].Config != config {
+		t.Fatalf("wrong link state: %+v", state)
+	}
+	if err := client.ResetLink(ids[1].String(), ids[0].String()); err != nil {
+		t.Fatal(err)
+	}
+	if cfg := net.Link(ids[0], ids[1]); cfg != (adapters.LinkConfig{}) {
+		t.Fatalf("link not reset: %+v", cfg)
+	}
+
+	if err := client.Partition([][]string{{ids[0].String()}}); err != nil {
+		t.Fatal(err)
+	}
+	waitUpConns(t, net, 1)
+	if err := client.Heal(); err != nil {
+		t.Fatal(err)
+	}
+	waitUpConns(t, net, 3)
+
+	if err := client.Partition([][]string{{"unknown"}}); err == nil {
+		t.Error("partition with unknown node succeeded")
+	}
+}
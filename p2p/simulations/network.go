@@ -60,6 +60,9 @@ type Network struct {
 	events      event.Feed
 	lock        sync.RWMutex
 	quitc       chan struct{}
+
+	linkSeed int64   // seed of the emulated links
+	cutConns []*Conn // connections cut by a partition
 }
 
 // NewNetwork returns a Network which uses the given NodeAdapter and NetworkConfig
@@ -512,6 +515,7 @@ func (net *Network) Reset() {
 
 	net.Nodes = nil
 	net.Conns = nil
+	net.cutConns = nil
 }
 
 // Node is a wrapper around adapters.Node which is used to track the status
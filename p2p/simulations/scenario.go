@@ -0,0 +1,801 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/common/hexutil"
+	"github.com/rwdxchain/go-rwdxchaina/log"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/simulations/adapters"
+	"gopkg.in/yaml.v2"
+)
+
+// scenarioPollInterval is the interval in which expectations are checked.
+const scenarioPollInterval = 100 * time.Millisecond
+
+// Scenario is a declarative description of a network experiment. It defines the
+// nodes of the network and their topology, the events happening while the
+// scenario runs and the expectations which must be met for the run to succeed.
+// Nodes are referred to by their index, starting at zero.
+//
+// Scenarios are encoded as JSON or YAML, with the same keys. For example:
+//
+//	{
+//	  "name": "ring-partition",
+//	  "nodes": 6,
+//	  "services": ["ping"],
+//	  "topology": {"type": "ring"},
+//	  "events": [
+//	    {"at": "2s", "type": "partition", "groups": [[0, 1, 2], [3, 4, 5]]},
+//	    {"at": "5s", "type": "heal"}
+//	  ],
+//	  "expect": [
+//	    {"type": "peers", "peers": 2, "within": "10s"},
+//	    {"type": "message", "protocol": "ping", "code": 1, "from": 0, "to": 1, "within": "10s"}
+//	  ]
+//	}
+type Scenario struct {
+	Name     string                `json:"name"`
+	Nodes    int                   `json:"nodes"`
+	Services []string              `json:"services"`
+	Topology ScenarioTopology      `json:"topology"`
+	Seed     int64                 `json:"seed,omitempty"` // seed of random choices, e.g. the random topology
+	Events   []ScenarioEvent       `json:"events,omitempty"`
+	Expect   []ScenarioExpectation `json:"expect"`
+}
+
+// Topology types of scenarios.
+const (
+	TopologyChain  = "chain"  // node i is connected to node i+1
+	TopologyRing   = "ring"   // a chain with the last node connected to the first
+	TopologyStar   = "star"   // all nodes are connected to node 0
+	TopologyFull   = "full"   // all nodes are connected to each other
+	TopologyRandom = "random" // every node is connected to at least Degree random nodes
+)
+
+// ScenarioTopology defines the initial connections of a scenario.
+type ScenarioTopology struct {
+	Type   string `json:"type"`
+	Degree int    `json:"degree,omitempty"`
+}
+
+// Event types of scenarios.
+const (
+	ScenarioConnect    = "connect"    // connects the two nodes of the event
+	ScenarioDisconnect = "disconnect" // disconnects the two nodes of the event
+	ScenarioCrash      = "crash"      // stops the nodes of the event
+	ScenarioRestart    = "restart"    // restarts crashed nodes and restores their topology connections
+	ScenarioPartition  = "partition"  // cuts all connections between the groups of the event
+	ScenarioHeal       = "heal"       // restores the connections cut by partitions
+	ScenarioLink       = "link"       // configures the link between the two nodes of the event
+	ScenarioRPC        = "rpc"        // calls Method with Params on the nodes of the event
+)
+
+// ScenarioEvent is a change of the network happening at a given time after the
+// start of the scenario. Partitions put nodes not listed in any group into a
+// group of their own.
+//
+// Link events require a node adapter emulating links, like the SimAdapter.
+// Their randomness is seeded with the seed of the scenario.
+//
+// RPC events drive the services of the nodes, e.g. calling "miner_start" on a
+// node makes it produce the blocks the other nodes sync.
+type ScenarioEvent struct {
+	At     Duration             `json:"at"`
+	Type   string               `json:"type"`
+	Nodes  []int                `json:"nodes,omitempty"`
+	Groups [][]int              `json:"groups,omitempty"`
+	Link   *adapters.LinkConfig `json:"link,omitempty"`
+	Method string               `json:"method,omitempty"`
+	Params []interface{}        `json:"params,omitempty"`
+}
+
+// Expectation types of scenarios.
+const (
+	ExpectPeers   = "peers"   // the nodes have at least Peers connections
+	ExpectRPC     = "rpc"     // the numeric result of calling Method on the nodes is at least AtLeast
+	ExpectMessage = "message" // a Protocol message with Code sent by node From was received by node To
+)
+
+// ScenarioExpectation is a condition which must be met within the given time
+// after the start of the scenario. Expectations with a start time are checked
+// from that time on, e.g. to verify the recovery of the network after events.
+// Peers and RPC expectations apply to the listed nodes, or all nodes if none
+// are listed.
+//
+// RPC expectations can check chain progress, e.g. all nodes reaching block 10
+// is expressed with method "eth_blockNumber" and at_least 10. Message
+// expectations require message events, which the exec adapter doesn't support.
+type ScenarioExpectation struct {
+	Type   string   `json:"type"`
+	After  Duration `json:"after,omitempty"`
+	Within Duration `json:"within"`
+	Nodes  []int    `json:"nodes,omitempty"`
+
+	Peers int `json:"peers,omitempty"`
+
+	Method  string `json:"method,omitempty"`
+	AtLeast uint64 `json:"at_least,omitempty"`
+
+	Protocol string `json:"protocol,omitempty"`
+	Code     uint64 `json:"code,omitempty"`
+	From     int    `json:"from,omitempty"`
+	To       int    `json:"to,omitempty"`
+}
+
+// Duration is a time.Duration encoded as a string like "1m30s" in JSON.
+type Duration time.Duration
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(input []byte) error {
+	var s string
+	if err := json.Unmarshal(input, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// LoadScenario reads a scenario from a YAML file if its name ends with .yaml or
+// .yml, from a JSON file otherwise.
+func LoadScenario(file string) (*Scenario, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, fmt.Errorf("invalid scenario %s: %v", file, err)
+		}
+	}
+	sc := new(Scenario)
+	if err := json.Unmarshal(data, sc); err != nil {
+		return nil, fmt.Errorf("invalid scenario %s: %v", file, err)
+	}
+	return sc, sc.Validate()
+}
+
+// yamlToJSON converts a YAML document to JSON, so that it's decoded with the
+// same keys and types as JSON scenarios.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	doc, err := jsonValue(doc)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+// jsonValue converts the mappings decoded from YAML, which may have keys of any
+// type, to JSON objects.
+func jsonValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		obj := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			name, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("non-string key %v", key)
+			}
+			conv, err := jsonValue(val)
+			if err != nil {
+				return nil, err
+			}
+			obj[name] = conv
+		}
+		return obj, nil
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, val := range v {
+			conv, err := jsonValue(val)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = conv
+		}
+		return list, nil
+	}
+	return v, nil
+}
+
+// Validate checks the scenario for errors.
+func (sc *Scenario) Validate() error {
+	if sc.Nodes < 1 {
+		return errors.New("scenario has no nodes")
+	}
+	if len(sc.Services) == 0 {
+		return errors.New("scenario has no services")
+	}
+	switch sc.Topology.Type {
+	case TopologyChain, TopologyRing, TopologyStar, TopologyFull, "":
+	case TopologyRandom:
+		if sc.Topology.Degree < 1 || sc.Topology.Degree >= sc.Nodes {
+			return fmt.Errorf("invalid degree %d of random topology", sc.Topology.Degree)
+		}
+	default:
+		return fmt.Errorf("unknown topology %q", sc.Topology.Type)
+	}
+	for i, ev := range sc.Events {
+		if err := sc.validateEvent(ev); err != nil {
+			return fmt.Errorf("event %d: %v", i, err)
+		}
+	}
+	for i, exp := range sc.Expect {
+		if err := sc.validateExpectation(exp); err != nil {
+			return fmt.Errorf("expectation %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+func (sc *Scenario) validateEvent(ev ScenarioEvent) error {
+	if ev.At < 0 {
+		return errors.New("negative time")
+	}
+	if err := sc.checkIndexes(ev.Nodes); err != nil {
+		return err
+	}
+	for _, group := range ev.Groups {
+		if err := sc.checkIndexes(group); err != nil {
+			return err
+		}
+	}
+	switch ev.Type {
+	case ScenarioConnect, ScenarioDisconnect, ScenarioLink:
+		if len(ev.Nodes) != 2 || ev.Nodes[0] == ev.Nodes[1] {
+			return fmt.Errorf("%s needs two distinct nodes", ev.Type)
+		}
+		if ev.Type == ScenarioLink && ev.Link == nil {
+			return errors.New("link event needs link configuration")
+		}
+	case ScenarioCrash, ScenarioRestart:
+		if len(ev.Nodes) == 0 {
+			return fmt.Errorf("%s needs nodes", ev.Type)
+		}
+	case ScenarioRPC:
+		if len(ev.Nodes) == 0 {
+			return errors.New("rpc event needs nodes")
+		}
+		if ev.Method == "" {
+			return errors.New("rpc event needs method")
+		}
+	case ScenarioPartition:
+		if len(ev.Groups) == 0 {
+			return errors.New("partition needs groups")
+		}
+	case ScenarioHeal:
+	default:
+		return fmt.Errorf("unknown event type %q", ev.Type)
+	}
+	return nil
+}
+
+func (sc *Scenario) validateExpectation(exp ScenarioExpectation) error {
+	if exp.Within <= 0 || exp.Within < exp.After {
+		return errors.New("missing or invalid time limit")
+	}
+	if err := sc.checkIndexes(exp.Nodes); err != nil {
+		return err
+	}
+	switch exp.Type {
+	case ExpectPeers:
+	case ExpectRPC:
+		if exp.Method == "" {
+			return errors.New("rpc expectation needs method")
+		}
+	case ExpectMessage:
+		if err := sc.checkIndexes([]int{exp.From, exp.To}); err != nil {
+			return err
+		}
+		if exp.Protocol == "" {
+			return errors.New("message expectation needs protocol")
+		}
+	default:
+		return fmt.Errorf("unknown expectation type %q", exp.Type)
+	}
+	return nil
+}
+
+func (sc *Scenario) checkIndexes(nodes []int) error {
+	for _, i := range nodes {
+		if i < 0 || i >= sc.Nodes {
+			return fmt.Errorf("node index %d out of range", i)
+		}
+	}
+	return nil
+}
+
+// edges returns the connections of the scenario topology.
+func (sc *Scenario) edges() [][2]int {
+	var (
+		n     = sc.Nodes
+		edges [][2]int
+	)
+	switch sc.Topology.Type {
+	case TopologyChain, TopologyRing:
+		for i := 0; i < n-1; i++ {
+			edges = append(edges, [2]int{i, i + 1})
+		}
+		if sc.Topology.Type == TopologyRing && n > 2 {
+			edges = append(edges, [2]int{n - 1, 0})
+		}
+	case TopologyStar:
+		for i := 1; i < n; i++ {
+			edges = append(edges, [2]int{0, i})
+		}
+	case TopologyFull:
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				edges = append(edges, [2]int{i, j})
+			}
+		}
+	case TopologyRandom:
+		var (
+			rnd    = rand.New(rand.NewSource(sc.Seed))
+			linked = make(map[[2]int]bool)
+			degree = make([]int, n)
+		)
+		for i := 0; i < n; i++ {
+			for _, j := range rnd.Perm(n) {
+				if degree[i] >= sc.Topology.Degree {
+					break
+				}
+				e := [2]int{i, j}
+				if j < i {
+					e = [2]int{j, i}
+				}
+				if i == j || linked[e] {
+					continue
+				}
+				linked[e] = true
+				degree[i]++
+				degree[j]++
+				edges = append(edges, e)
+			}
+		}
+	}
+	return edges
+}
+
+// ScenarioReport is the result of running a scenario.
+type ScenarioReport struct {
+	Name         string                      `json:"name"`
+	Success      bool                        `json:"success"`
+	StartedAt    time.Time                   `json:"started_at"`
+	FinishedAt   time.Time                   `json:"finished_at"`
+	Nodes        []discover.NodeID           `json:"nodes"` // node IDs by index
+	Events       []ScenarioEventResult       `json:"events"`
+	Expectations []ScenarioExpectationResult `json:"expectations"`
+}
+
+// ScenarioEventResult reports the execution of an event.
+type ScenarioEventResult struct {
+	ScenarioEvent
+	Error string `json:"error,omitempty"`
+}
+
+// ScenarioExpectationResult reports the outcome of an expectation.
+type ScenarioExpectationResult struct {
+	ScenarioExpectation
+	Passed   bool     `json:"passed"`
+	PassedAt Duration `json:"passed_at,omitempty"` // time since the start of the scenario
+	Error    string   `json:"error,omitempty"`
+}
+
+// scenarioRun is the state of a running scenario.
+type scenarioRun struct {
+	sc      *Scenario
+	net     *Network
+	ids     []discover.NodeID
+	edges   [][2]int
+	cut     map[[2]int]bool // connections cut by partitions
+	group   map[int]int     // partition group of nodes, nil if not partitioned
+	pending map[[2]int]bool // connections to restore, true if waiting for the old one to drop
+
+	msgMu sync.Mutex
+	msgs  map[Msg]bool // received messages
+}
+
+// RunScenario creates and starts the nodes of a scenario in the network,
+// connects them and executes the events of the scenario until all of its
+// expectations have passed or failed. The nodes are not stopped when the
+// scenario ends.
+func RunScenario(ctx context.Context, net *Network, sc *Scenario) (*ScenarioReport, error) {
+	if err := sc.Validate(); err != nil {
+		return nil, err
+	}
+	run := newScenarioRun(net, sc)
+	if _, ok := net.nodeAdapter.(adapters.LinkEmulator); ok {
+		if err := net.SetLinkSeed(sc.Seed); err != nil {
+			return nil, err
+		}
+	}
+	for i := 0; i < sc.Nodes; i++ {
+		conf := adapters.RandomNodeConfig()
+		conf.Services = sc.Services
+		node, err := net.NewNodeWithConfig(conf)
+		if err != nil {
+			return nil, fmt.Errorf("can't create node %d: %v", i, err)
+		}
+		run.ids = append(run.ids, node.ID())
+	}
+	events := make(chan *Event, 64)
+	sub := net.Events().Subscribe(events)
+	defer sub.Unsubscribe()
+	go run.collectMessages(events, sub.Err())
+
+	for _, id := range run.ids {
+		if err := net.Start(id); err != nil {
+			return nil, fmt.Errorf("can't start node %v: %v", id, err)
+		}
+	}
+	for _, e := range run.edges {
+		if err := net.Connect(run.ids[e[0]], run.ids[e[1]]); err != nil {
+			return nil, fmt.Errorf("can't connect nodes %d and %d: %v", e[0], e[1], err)
+		}
+	}
+	return run.run(ctx), nil
+}
+
+func newScenarioRun(net *Network, sc *Scenario) *scenarioRun {
+	return &scenarioRun{
+		sc:      sc,
+		net:     net,
+		edges:   sc.edges(),
+		cut:     make(map[[2]int]bool),
+		pending: make(map[[2]int]bool),
+		msgs:    make(map[Msg]bool),
+	}
+}
+
+// collectMessages records the messages received in the network.
+func (run *scenarioRun) collectMessages(events chan *Event, errc <-chan error) {
+	for {
+		select {
+		case ev := <-events:
+			if ev.Type == EventTypeMsg && ev.Msg.Received {
+				run.msgMu.Lock()
+				run.msgs[*ev.Msg] = true
+				run.msgMu.Unlock()
+			}
+		case <-errc:
+			return
+		}
+	}
+}
+
+func (run *scenarioRun) run(ctx context.Context) *ScenarioReport {
+	var (
+		start  = time.Now()
+		report = &ScenarioReport{Name: run.sc.Name, StartedAt: start, Nodes: run.ids, Success: true}
+		events = make([]ScenarioEvent, len(run.sc.Events))
+		poll   = time.NewTicker(scenarioPollInterval)
+	)
+	defer poll.Stop()
+	copy(events, run.sc.Events)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At < events[j].At })
+	for _, exp := range run.sc.Expect {
+		report.Expectations = append(report.Expectations, ScenarioExpectationResult{ScenarioExpectation: exp})
+	}
+
+	for {
+		elapsed := time.Since(start)
+		for len(events) > 0 && time.Duration(events[0].At) <= elapsed {
+			result := ScenarioEventResult{ScenarioEvent: events[0]}
+			if err := run.execute(events[0]); err != nil {
+				log.Warn("Scenario event failed", "type", events[0].Type, "err", err)
+				result.Error = err.Error()
+				report.Success = false
+			}
+			report.Events = append(report.Events, result)
+			events = events[1:]
+		}
+		run.maintain()
+
+		pending := 0
+		for i := range report.Expectations {
+			res := &report.Expectations[i]
+			if res.Passed || res.Error != "" {
+				continue
+			}
+			if elapsed < time.Duration(res.After) {
+				pending++
+				continue
+			}
+			switch pass, err := run.check(ctx, res.ScenarioExpectation); {
+			case pass:
+				res.Passed, res.PassedAt = true, Duration(elapsed)
+			case elapsed >= time.Duration(res.Within):
+				res.Error = "timeout"
+				if err != nil {
+					res.Error = err.Error()
+				}
+			default:
+				pending++
+			}
+		}
+		if pending == 0 && len(events) == 0 {
+			break
+		}
+		select {
+		case <-poll.C:
+			continue
+		case <-ctx.Done():
+		}
+		for i := range report.Expectations {
+			if res := &report.Expectations[i]; !res.Passed && res.Error == "" {
+				res.Error = ctx.Err().Error()
+			}
+		}
+		for _, ev := range events {
+			report.Events = append(report.Events, ScenarioEventResult{ScenarioEvent: ev, Error: ctx.Err().Error()})
+		}
+		break
+	}
+	for _, res := range report.Expectations {
+		report.Success = report.Success && res.Passed
+	}
+	report.FinishedAt = time.Now()
+	return report
+}
+
+// execute applies an event to the network.
+func (run *scenarioRun) execute(ev ScenarioEvent) error {
+	switch ev.Type {
+	case ScenarioConnect:
+		c := [2]int{ev.Nodes[0], ev.Nodes[1]}
+		if run.partitioned(c[0], c[1]) {
+			return errors.New("nodes are partitioned")
+		}
+		delete(run.pending, c)
+		delete(run.pending, [2]int{c[1], c[0]})
+		return run.net.Connect(run.ids[c[0]], run.ids[c[1]])
+	case ScenarioDisconnect:
+		delete(run.pending, [2]int{ev.Nodes[0], ev.Nodes[1]})
+		delete(run.pending, [2]int{ev.Nodes[1], ev.Nodes[0]})
+		return run.disconnect(ev.Nodes[0], ev.Nodes[1])
+	case ScenarioCrash:
+		for _, i := range ev.Nodes {
+			if err := run.net.Stop(run.ids[i]); err != nil {
+				return err
+			}
+		}
+	case ScenarioRestart:
+		for _, i := range ev.Nodes {
+			if err := run.net.Start(run.ids[i]); err != nil {
+				return err
+			}
+		}
+		// The connections of restarted nodes can only be restored once the
+		// network noticed that the old ones are gone.
+		for _, i := range ev.Nodes {
+			for _, e := range run.edges {
+				if e[0] == i || e[1] == i {
+					run.pending[e] = true
+				}
+			}
+		}
+	case ScenarioPartition:
+		run.heal()
+		run.group = make(map[int]int)
+		for gi, group := range ev.Groups {
+			for _, i := range group {
+				run.group[i] = gi + 1
+			}
+		}
+		run.maintain()
+	case ScenarioHeal:
+		run.heal()
+		run.maintain()
+	case ScenarioLink:
+		return run.net.SetLink(run.ids[ev.Nodes[0]], run.ids[ev.Nodes[1]], *ev.Link)
+	case ScenarioRPC:
+		for _, i := range ev.Nodes {
+			client, err := run.net.GetNode(run.ids[i]).Client()
+			if err != nil {
+				return err
+			}
+			if err := client.Call(nil, ev.Method, ev.Params...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// maintain cuts connections across partitions, which may have been established
+// after the partition started, and restores pending connections.
+func (run *scenarioRun) maintain() {
+	for _, c := range run.connections() {
+		if run.partitioned(c[0], c[1]) {
+			if err := run.disconnect(c[0], c[1]); err != nil {
+				log.Debug("Scenario partition failed", "one", c[0], "other", c[1], "err", err)
+			}
+			run.cut[c] = true
+		}
+	}
+	for c, waitDrop := range run.pending {
+		if run.partitioned(c[0], c[1]) {
+			continue
+		}
+		one, other := run.net.GetNode(run.ids[c[0]]), run.net.GetNode(run.ids[c[1]])
+		conn := run.net.GetConn(one.ID(), other.ID())
+		if conn != nil && conn.Up {
+			if !waitDrop {
+				delete(run.pending, c)
+			}
+			continue
+		}
+		run.pending[c] = false
+		if !one.Up || !other.Up {
+			continue
+		}
+		if err := run.reconnect(c[0], c[1]); err != nil {
+			log.Debug("Scenario reconnect failed", "one", c[0], "other", c[1], "err", err)
+		}
+	}
+}
+
+// reconnect connects two nodes which were connected before. Their previous
+// dial attempts are forgotten first, the server would delay redials otherwise.
+func (run *scenarioRun) reconnect(a, b int) error {
+	for _, c := range [][2]int{{a, b}, {b, a}} {
+		if err := run.removePeer(c[0], c[1]); err != nil {
+			return err
+		}
+	}
+	return run.net.Connect(run.ids[a], run.ids[b])
+}
+
+// disconnect disconnects two nodes. Both nodes are asked to drop the other one,
+// so that neither of them redials the other as a static peer.
+func (run *scenarioRun) disconnect(a, b int) error {
+	if err := run.net.Disconnect(run.ids[a], run.ids[b]); err != nil {
+		return err
+	}
+	return run.removePeer(b, a)
+}
+
+// removePeer removes a peer of a node, disconnecting them and clearing it from
+// the node's static peers.
+func (run *scenarioRun) removePeer(node, peer int) error {
+	client, err := run.net.GetNode(run.ids[node]).Client()
+	if err != nil {
+		return err
+	}
+	return client.Call(nil, "admin_removePeer", string(run.net.GetNode(run.ids[peer]).Addr()))
+}
+
+// partitioned reports whether two nodes are in different partition groups.
+func (run *scenarioRun) partitioned(a, b int) bool {
+	return run.group != nil && run.group[a] != run.group[b]
+}
+
+// heal removes the partition and schedules the restoration of the connections
+// it cut.
+func (run *scenarioRun) heal() {
+	run.group = nil
+	for c := range run.cut {
+		run.pending[c] = true
+	}
+	run.cut = make(map[[2]int]bool)
+}
+
+// connections returns the established connections by the indexes of the nodes.
+func (run *scenarioRun) connections() [][2]int {
+	index := make(map[discover.NodeID]int, len(run.ids))
+	for i, id := range run.ids {
+		index[id] = i
+	}
+	run.net.lock.RLock()
+	defer run.net.lock.RUnlock()
+	var conns [][2]int
+	for _, c := range run.net.Conns {
+		one, ok1 := index[c.One]
+		other, ok2 := index[c.Other]
+		if c.Up && ok1 && ok2 {
+			conns = append(conns, [2]int{one, other})
+		}
+	}
+	return conns
+}
+
+// check evaluates an expectation.
+func (run *scenarioRun) check(ctx context.Context, exp ScenarioExpectation) (bool, error) {
+	nodes := exp.Nodes
+	if len(nodes) == 0 {
+		for i := range run.ids {
+			nodes = append(nodes, i)
+		}
+	}
+	switch exp.Type {
+	case ExpectPeers:
+		peers := make(map[int]int)
+		for _, c := range run.connections() {
+			peers[c[0]]++
+			peers[c[1]]++
+		}
+		for _, i := range nodes {
+			if peers[i] < exp.Peers {
+				return false, nil
+			}
+		}
+		return true, nil
+	case ExpectRPC:
+		for _, i := range nodes {
+			v, err := run.callNumber(ctx, run.ids[i], exp.Method)
+			if err != nil || v < exp.AtLeast {
+				return false, err
+			}
+		}
+		return true, nil
+	case ExpectMessage:
+		msg := Msg{One: run.ids[exp.From], Other: run.ids[exp.To], Protocol: exp.Protocol, Code: exp.Code, Received: true}
+		run.msgMu.Lock()
+		defer run.msgMu.Unlock()
+		return run.msgs[msg], nil
+	}
+	return false, fmt.Errorf("unknown expectation type %q", exp.Type)
+}
+
+// callNumber calls an RPC method returning a number, which may be encoded as
+// a JSON number or a hex string.
+func (run *scenarioRun) callNumber(ctx context.Context, id discover.NodeID, method string) (uint64, error) {
+	node := run.net.GetNode(id)
+	if node == nil || !node.Up {
+		return 0, fmt.Errorf("node %v is down", id.TerminalString())
+	}
+	client, err := node.Client()
+	if err != nil {
+		return 0, err
+	}
+	var result json.RawMessage
+	if err := client.CallContext(ctx, &result, method); err != nil {
+		return 0, err
+	}
+	var hex hexutil.Uint64
+	if err := json.Unmarshal(result, &hex); err == nil {
+		return uint64(hex), nil
+	}
+	return strconv.ParseUint(string(result), 10, 64)
+}
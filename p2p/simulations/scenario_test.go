@@ -0,0 +1,270 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulations
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/p2p/simulations/adapters"
+)
+
+const testScenario = `{
+  "name": "ring",
+  "nodes": 4,
+  "services": ["test"],
+  "topology": {"type": "ring"},
+  "events": [
+    {"at": "300ms", "type": "partition", "groups": [[0, 1], [2, 3]]},
+    {"at": "600ms", "type": "heal"},
+    {"at": "600ms", "type": "crash", "nodes": [3]},
+    {"at": "700ms", "type": "restart", "nodes": [3]},
+    {"at": "700ms", "type": "link", "nodes": [0, 1], "link": {"latency": "10ms", "jitter": "5ms"}},
+    {"at": "700ms", "type": "rpc", "nodes": [0, 1], "method": "test_peerCount"}
+  ],
+  "expect": [
+    {"type": "peers", "peers": 2, "within": "5s"},
+    {"type": "rpc", "method": "test_peerCount", "at_least": 2, "within": "5s"},
+    {"type": "message", "protocol": "test", "code": 1, "from": 0, "to": 1, "within": "5s"},
+    {"type": "peers", "peers": 2, "after": "800ms", "within": "10s"}
+  ]
+}`
+
+const testScenarioYAML = `
+name: ring
+nodes: 4
+services: [test]
+topology:
+  type: ring
+events:
+  - {at: 300ms, type: partition, groups: [[0, 1], [2, 3]]}
+  - {at: 600ms, type: heal}
+  - {at: 600ms, type: crash, nodes: [3]}
+  - {at: 700ms, type: restart, nodes: [3]}
+  - at: 700ms
+    type: link
+    nodes: [0, 1]
+    link:
+      latency: 10ms
+      jitter: 5ms
+  - {at: 700ms, type: rpc, nodes: [0, 1], method: test_peerCount}
+expect:
+  - {type: peers, peers: 2, within: 5s}
+  - {type: rpc, method: test_peerCount, at_least: 2, within: 5s}
+  - {type: message, protocol: test, code: 1, from: 0, to: 1, within: 5s}
+  - {type: peers, peers: 2, after: 800ms, within: 10s}
+`
+
+func newScenarioTestNetwork() *Network {
+	return NewNetwork(adapters.NewSimAdapter(testServices), &NetworkConfig{DefaultService: "test"})
+}
+
+func TestRunScenario(t *testing.T) {
+	var sc Scenario
+	if err := json.Unmarshal([]byte(testScenario), &sc); err != nil {
+		t.Fatal(err)
+	}
+	net := newScenarioTestNetwork()
+	defer net.Shutdown()
+
+	report, err := RunScenario(context.Background(), net, &sc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Success {
+		out, _ := json.MarshalIndent(report, "", "  ")
+		t.Fatalf("scenario failed:\n%s", out)
+	}
+	if len(report.Nodes) != 4 || len(report.Events) != 6 || len(report.Expectations) != 4 {
+		t.Fatalf("incomplete report: %d nodes, %d events, %d expectations", len(report.Nodes), len(report.Events), len(report.Expectations))
+	}
+	if ev := report.Events[5]; ev.Error != "" {
+		t.Errorf("rpc event failed: %s", ev.Error)
+	}
+	if cfg := net.Link(report.Nodes[0], report.Nodes[1]); cfg.Latency != 10*time.Millisecond {
+		t.Errorf("link not configured: %+v", cfg)
+	}
+	// The ring must be complete again after healing and restarting.
+	for i, id := range report.Nodes {
+		next := report.Nodes[(i+1)%len(report.Nodes)]
+		if conn := net.GetConn(id, next); conn == nil || !conn.Up {
+			t.Errorf("nodes %d and %d not connected after scenario", i, (i+1)%len(report.Nodes))
+		}
+	}
+}
+
+func TestRunScenarioFailure(t *testing.T) {
+	sc := &Scenario{
+		Nodes:    2,
+		Services: []string{"test"},
+		Topology: ScenarioTopology{Type: TopologyChain},
+		Expect: []ScenarioExpectation{
+			{Type: ExpectPeers, Peers: 1, Within: Duration(5 * time.Second)},
+			{Type: ExpectPeers, Peers: 2, Within: Duration(300 * time.Millisecond)},
+		},
+	}
+	net := newScenarioTestNetwork()
+	defer net.Shutdown()
+
+	report, err := RunScenario(context.Background(), net, sc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Success {
+		t.Fatal("scenario with unreachable expectation succeeded")
+	}
+	if !report.Expectations[0].Passed {
+		t.Errorf("first expectation failed: %s", report.Expectations[0].Error)
+	}
+	if report.Expectations[1].Passed || report.Expectations[1].Error != "timeout" {
+		t.Errorf("wrong result of second expectation: %+v", report.Expectations[1])
+	}
+}
+
+func TestScenarioPartition(t *testing.T) {
+	sc := &Scenario{Nodes: 4, Services: []string{"test"}, Topology: ScenarioTopology{Type: TopologyFull}}
+	net := newScenarioTestNetwork()
+	defer net.Shutdown()
+	if _, err := RunScenario(context.Background(), net, sc); err != nil {
+		t.Fatal(err)
+	}
+	run := newScenarioRun(net, sc)
+	for _, n := range net.GetNodes() {
+		run.ids = append(run.ids, n.ID())
+	}
+
+	if err := run.execute(ScenarioEvent{Type: ScenarioPartition, Groups: [][]int{{0, 1}}}); err != nil {
+		t.Fatal(err)
+	}
+	waitScenarioConns(t, run, 2)
+	if err := run.execute(ScenarioEvent{Type: ScenarioConnect, Nodes: []int{0, 2}}); err == nil {
+		t.Error("connect across partition succeeded")
+	}
+
+	if err := run.execute(ScenarioEvent{Type: ScenarioHeal}); err != nil {
+		t.Fatal(err)
+	}
+	waitScenarioConns(t, run, 6)
+}
+
+// waitScenarioConns maintains the network of a scenario until it has the given
+// number of connections, none of which crosses a partition.
+func waitScenarioConns(t *testing.T, run *scenarioRun, n int) {
+	var conns [][2]int
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(scenarioPollInterval) {
+		run.maintain()
+		conns = run.connections()
+		crossing := false
+		for _, c := range conns {
+			crossing = crossing || run.partitioned(c[0], c[1])
+		}
+		if len(conns) == n && !crossing {
+			return
+		}
+	}
+	t.Fatalf("wrong connections: got %v, want %d connections", conns, n)
+}
+
+// Tests that JSON and YAML scenario files describe the same scenarios.
+func TestLoadScenario(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rwdxchain-scenario-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var scenarios []*Scenario
+	for name, content := range map[string]string{"ring.json": testScenario, "ring.yaml": testScenarioYAML} {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		sc, err := LoadScenario(path)
+		if err != nil {
+			t.Fatalf("%s: failed to load scenario: %v", name, err)
+		}
+		scenarios = append(scenarios, sc)
+	}
+	if !reflect.DeepEqual(scenarios[0], scenarios[1]) {
+		t.Errorf("scenario mismatch:\nJSON: %+v\nYAML: %+v", scenarios[0], scenarios[1])
+	}
+	if scenarios[0].Events[4].Link == nil || time.Duration(scenarios[0].Events[4].Link.Latency) != 10*time.Millisecond {
+		t.Errorf("link configuration mismatch: %+v", scenarios[0].Events[4].Link)
+	}
+	// Mappings with non-string keys can't be expressed in JSON
+	path := filepath.Join(dir, "invalid.yml")
+	ioutil.WriteFile(path, []byte("nodes: 2\nservices: [test]\n1: 2\n"), 0600)
+	if _, err := LoadScenario(path); err == nil {
+		t.Errorf("non-string key accepted")
+	}
+}
+
+func TestScenarioValidate(t *testing.T) {
+	tests := []struct {
+		sc  Scenario
+		err string
+	}{
+		{Scenario{Services: []string{"test"}}, "no nodes"},
+		{Scenario{Nodes: 2}, "no services"},
+		{Scenario{Nodes: 2, Services: []string{"test"}, Topology: ScenarioTopology{Type: "tree"}}, "unknown topology"},
+		{Scenario{Nodes: 2, Services: []string{"test"}, Topology: ScenarioTopology{Type: TopologyRandom, Degree: 2}}, "invalid degree"},
+		{Scenario{Nodes: 2, Services: []string{"test"}, Events: []ScenarioEvent{{Type: ScenarioConnect, Nodes: []int{0, 2}}}}, "out of range"},
+		{Scenario{Nodes: 2, Services: []string{"test"}, Events: []ScenarioEvent{{Type: ScenarioPartition}}}, "needs groups"},
+		{Scenario{Nodes: 2, Services: []string{"test"}, Events: []ScenarioEvent{{Type: ScenarioLink, Nodes: []int{0, 1}}}}, "needs link"},
+		{Scenario{Nodes: 2, Services: []string{"test"}, Events: []ScenarioEvent{{Type: ScenarioRPC, Nodes: []int{0}}}}, "needs method"},
+		{Scenario{Nodes: 2, Services: []string{"test"}, Expect: []ScenarioExpectation{{Type: ExpectRPC, Within: 1}}}, "needs method"},
+		{Scenario{Nodes: 2, Services: []string{"test"}, Expect: []ScenarioExpectation{{Type: ExpectPeers}}}, "time limit"},
+		{Scenario{Nodes: 2, Services: []string{"test"}, Expect: []ScenarioExpectation{{Type: ExpectPeers, After: 2, Within: 1}}}, "time limit"},
+	}
+	for i, test := range tests {
+		err := test.sc.Validate()
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("test %d: got error %v, want %q", i, err, test.err)
+		}
+	}
+}
+
+func TestScenarioRandomTopology(t *testing.T) {
+	sc := &Scenario{Nodes: 10, Seed: 42, Topology: ScenarioTopology{Type: TopologyRandom, Degree: 3}}
+	edges := sc.edges()
+	degree := make([]int, sc.Nodes)
+	for _, e := range edges {
+		degree[e[0]]++
+		degree[e[1]]++
+	}
+	for i, d := range degree {
+		if d < 3 {
+			t.Errorf("node %d has degree %d", i, d)
+		}
+	}
+	again := sc.edges()
+	if len(again) != len(edges) {
+		t.Fatal("random topology not deterministic")
+	}
+	for i := range edges {
+		if edges[i] != again[i] {
+			t.Fatal("random topology not deterministic")
+		}
+	}
+}
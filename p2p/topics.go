@@ -0,0 +1,173 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discv5"
+)
+
+const (
+	// maxTopicNodes is the number of nodes found by topic searches which are
+	// kept as dial candidates.
+	maxTopicNodes = 200
+
+	// Topics are searched quickly until nodes advertising them are found,
+	// then the search slows down.
+	topicSearchFast = 500 * time.Millisecond
+	topicSearchSlow = time.Minute
+)
+
+// topicNodes collects the nodes found by searching the topics of the
+// protocols. It is a nodeSource of preferred dial candidates.
+type topicNodes struct {
+	mu    sync.Mutex
+	nodes []*discover.Node // oldest first
+	index map[discover.NodeID]int
+}
+
+func newTopicNodes() *topicNodes {
+	return &topicNodes{index: make(map[discover.NodeID]int)}
+}
+
+// add adds a node, replacing an older entry of the same node. The oldest
+// node is dropped when the set is full.
+func (t *topicNodes) add(n *discv5.Node) {
+	node := discover.NewNode(discover.NodeID(n.ID), n.IP, n.UDP, n.TCP)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if i, ok := t.index[node.ID]; ok {
+		t.remove(i)
+	} else if len(t.nodes) >= maxTopicNodes {
+		t.remove(0)
+	}
+	t.index[node.ID] = len(t.nodes)
+	t.nodes = append(t.nodes, node)
+}
+
+func (t *topicNodes) remove(i int) {
+	delete(t.index, t.nodes[i].ID)
+	t.nodes = append(t.nodes[:i], t.nodes[i+1:]...)
+	for j := i; j < len(t.nodes); j++ {
+		t.index[t.nodes[j].ID] = j
+	}
+}
+
+// RandomNodes fills buf with random nodes of the set.
+func (t *topicNodes) RandomNodes(buf []*discover.Node) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n := 0
+	for _, i := range rand.Perm(len(t.nodes)) {
+		if n == len(buf) {
+			break
+		}
+		buf[n] = t.nodes[i]
+		n++
+	}
+	return n
+}
+
+// protocolTopics returns the topics advertised and searched by the protocols,
+// without duplicates.
+func protocolTopics(protocols []Protocol) (advertise, search []discv5.Topic) {
+	seen := make(map[discv5.Topic]bool)
+	searched := make(map[discv5.Topic]bool)
+	for _, p := range protocols {
+		for _, topic := range p.AdvertiseTopics {
+			if !seen[topic] {
+				seen[topic] = true
+				advertise = append(advertise, topic)
+			}
+		}
+		for _, topic := range p.SearchTopics {
+			if !searched[topic] {
+				searched[topic] = true
+				search = append(search, topic)
+			}
+		}
+	}
+	return advertise, search
+}
+
+// searchesTopics reports whether the server searches the topics of its
+// protocols, providing dial candidates.
+func (srv *Server) searchesTopics() bool {
+	_, search := protocolTopics(srv.Protocols)
+	return srv.DiscoveryV5 && len(search) > 0
+}
+
+// startTopics advertises and searches the topics of the protocols via the V5
+// discovery. The nodes found are returned as a source of dial candidates.
+func (srv *Server) startTopics() *topicNodes {
+	advertise, search := protocolTopics(srv.Protocols)
+	for _, topic := range advertise {
+		srv.log.Debug("Advertising discovery topic", "topic", topic)
+		go srv.DiscV5.RegisterTopic(topic, srv.quit)
+	}
+	if len(search) == 0 {
+		return nil
+	}
+	found := newTopicNodes()
+	for _, topic := range search {
+		srv.log.Debug("Searching discovery topic", "topic", topic)
+		go srv.searchTopic(topic, found)
+	}
+	return found
+}
+
+// searchTopic searches a topic until the server is stopped.
+func (srv *Server) searchTopic(topic discv5.Topic, found *topicNodes) {
+	var (
+		setPeriod = make(chan time.Duration, 1)
+		nodes     = make(chan *discv5.Node, 100)
+		fast      = true
+	)
+	setPeriod <- topicSearchFast
+	go srv.DiscV5.SearchTopic(topic, setPeriod, nodes, nil)
+	for {
+		select {
+		case n := <-nodes:
+			found.add(n)
+			if fast {
+				fast = false
+				setPeriod <- topicSearchSlow
+			}
+		case <-srv.quit:
+			close(setPeriod)
+			return
+		}
+	}
+}
+
+// DiscoveryTopics returns the topics known to the V5 discovery, i.e. those
+// advertised and searched by the local node and those advertised by other
+// nodes through it. It returns nil if the V5 discovery isn't running.
+func (srv *Server) DiscoveryTopics() []discv5.TopicInfo {
+	srv.lock.Lock()
+	ntab := srv.DiscV5
+	srv.lock.Unlock()
+	if ntab == nil {
+		return nil
+	}
+	return ntab.Topics()
+}
@@ -0,0 +1,74 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discv5"
+)
+
+func TestTopicNodes(t *testing.T) {
+	found := newTopicNodes()
+	for i := 0; i < maxTopicNodes+2; i++ {
+		found.add(discv5.NewNode(discv5.NodeID(uintID(uint32(i))), net.IP{127, 0, 0, 1}, 30303, 30303))
+	}
+	// Adding a known node again moves it to the end.
+	found.add(discv5.NewNode(discv5.NodeID(uintID(5)), net.IP{127, 0, 0, 2}, 30303, 30304))
+
+	buf := make([]*discover.Node, maxTopicNodes+10)
+	n := found.RandomNodes(buf)
+	if n != maxTopicNodes {
+		t.Fatalf("wrong number of nodes: %d", n)
+	}
+	ids := make(map[discover.NodeID]*discover.Node)
+	for _, node := range buf[:n] {
+		ids[node.ID] = node
+	}
+	if ids[uintID(0)] != nil || ids[uintID(1)] != nil {
+		t.Error("oldest nodes weren't dropped")
+	}
+	if node := ids[uintID(5)]; node == nil || node.TCP != 30304 {
+		t.Errorf("node wasn't updated: %v", node)
+	}
+	if last := found.nodes[len(found.nodes)-1]; last.ID != uintID(5) {
+		t.Errorf("updated node isn't the newest: %v", last)
+	}
+	for id, i := range found.index {
+		if found.nodes[i].ID != id {
+			t.Fatalf("index of %v is wrong", id)
+		}
+	}
+}
+
+func TestProtocolTopics(t *testing.T) {
+	protocols := []Protocol{
+		{Name: "a", Version: 1, AdvertiseTopics: []discv5.Topic{"a"}, SearchTopics: []discv5.Topic{"a", "b"}},
+		{Name: "a", Version: 2, AdvertiseTopics: []discv5.Topic{"a", "c"}},
+		{Name: "b", Version: 1},
+	}
+	advertise, search := protocolTopics(protocols)
+	if want := []discv5.Topic{"a", "c"}; !reflect.DeepEqual(advertise, want) {
+		t.Errorf("wrong advertised topics: got %v, want %v", advertise, want)
+	}
+	if want := []discv5.Topic{"a", "b"}; !reflect.DeepEqual(search, want) {
+		t.Errorf("wrong searched topics: got %v, want %v", search, want)
+	}
+}
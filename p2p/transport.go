@@ -0,0 +1,246 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/enr"
+)
+
+// NetTransport is a network transport carrying RLPx connections.
+//
+// The server listens on its primary transport, which is TCP unless Config.Transport
+// is set, and dials the TCP endpoint of nodes with it. Additional transports can be
+// configured in Config.ExtraTransports. They are announced in the node record, with
+// the transport name as key and the listening port as value, and are used for dialing
+// nodes announcing the same transport. Dials fall back to the primary transport if
+// no transport is shared or the connection attempt fails.
+//
+// Besides TCPTransport, the package provides the in-memory transports of
+// PipeNetwork for tests.
+type NetTransport interface {
+	// Name identifies the transport in node records.
+	Name() string
+
+	// Listen opens a listener on the given host:port address.
+	Listen(addr string) (net.Listener, error)
+
+	// Dial connects to the given host:port address.
+	Dial(addr string) (net.Conn, error)
+}
+
+// TransportListener configures an additional transport of the server.
+type TransportListener struct {
+	Transport  NetTransport
+	ListenAddr string
+}
+
+// TCPTransport is the default transport, plain TCP connections.
+type TCPTransport struct {
+	DialTimeout time.Duration
+}
+
+// Name implements NetTransport.
+func (TCPTransport) Name() string { return "tcp" }
+
+// Listen implements NetTransport.
+func (TCPTransport) Listen(addr string) (net.Listener, error) {
+	return net.Listen("tcp", addr)
+}
+
+// Dial implements NetTransport.
+func (t TCPTransport) Dial(addr string) (net.Conn, error) {
+	return net.DialTimeout("tcp", addr, t.DialTimeout)
+}
+
+// transportDialer implements NodeDialer by dialing the TCP endpoint of nodes
+// through a transport.
+type transportDialer struct {
+	NetTransport
+}
+
+func (t transportDialer) Dial(dest *discover.Node) (net.Conn, error) {
+	return t.NetTransport.Dial(endpoint(dest.IP, dest.TCP))
+}
+
+// sharedTransport returns the first additional transport announced in the given
+// node record, along with the node's port for it.
+func (srv *Server) sharedTransport(record *enr.Record) (NetTransport, uint16) {
+	if record == nil {
+		return nil, 0
+	}
+	for _, extra := range srv.ExtraTransports {
+		var port uint16
+		if err := record.Load(enr.WithEntry(extra.Transport.Name(), &port)); err == nil && port != 0 {
+			return extra.Transport, port
+		}
+	}
+	return nil, 0
+}
+
+// endpoint creates a host:port address.
+func endpoint(ip net.IP, port uint16) string {
+	return net.JoinHostPort(ip.String(), strconv.Itoa(int(port)))
+}
+
+// splitAddr returns the IP and port of a listener or connection address.
+func splitAddr(addr net.Addr) (net.IP, uint16) {
+	switch addr := addr.(type) {
+	case *net.TCPAddr:
+		return addr.IP, uint16(addr.Port)
+	case *net.UDPAddr:
+		return addr.IP, uint16(addr.Port)
+	}
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return nil, 0
+	}
+	p, _ := strconv.ParseUint(port, 10, 16)
+	return net.ParseIP(host), uint16(p)
+}
+
+var errPipeRefused = errors.New("pipe connection refused")
+
+// PipeNetwork is an in-memory network of net.Pipe connections. Its transports
+// let tests connect servers without opening ports. Listeners are identified by
+// their port number only, the host part of addresses is ignored.
+type PipeNetwork struct {
+	mu        sync.Mutex
+	listeners map[uint16]*pipeListener
+	lastPort  uint16
+}
+
+// NewPipeNetwork creates an empty in-memory network.
+func NewPipeNetwork() *PipeNetwork {
+	return &PipeNetwork{listeners: make(map[uint16]*pipeListener)}
+}
+
+// Transport returns a transport connecting through the network.
+func (pn *PipeNetwork) Transport(name string) NetTransport {
+	return &pipeTransport{name, pn}
+}
+
+type pipeTransport struct {
+	name string
+	net  *PipeNetwork
+}
+
+func (t *pipeTransport) Name() string { return t.name }
+
+// Listen creates a listener on the port of addr. Port zero picks an unused port.
+func (t *pipeTransport) Listen(addr string) (net.Listener, error) {
+	host, portstr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		ip = net.IPv4(127, 0, 0, 1)
+	}
+	port, err := strconv.ParseUint(portstr, 10, 16)
+	if err != nil {
+		return nil, err
+	}
+
+	pn := t.net
+	pn.mu.Lock()
+	defer pn.mu.Unlock()
+	for i := 0; port == 0 && i < 65535; i++ {
+		pn.lastPort++
+		if pn.lastPort != 0 && pn.listeners[pn.lastPort] == nil {
+			port = uint64(pn.lastPort)
+		}
+	}
+	if port == 0 {
+		return nil, errors.New("no free pipe port")
+	}
+	if pn.listeners[uint16(port)] != nil {
+		return nil, errors.New("pipe address already in use")
+	}
+	l := &pipeListener{
+		net:    pn,
+		addr:   &net.TCPAddr{IP: ip, Port: int(port)},
+		conns:  make(chan net.Conn),
+		closed: make(chan struct{}),
+	}
+	pn.listeners[uint16(port)] = l
+	return l, nil
+}
+
+// Dial connects to the listener on the port of addr.
+func (t *pipeTransport) Dial(addr string) (net.Conn, error) {
+	_, portstr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.ParseUint(portstr, 10, 16)
+	if err != nil {
+		return nil, err
+	}
+	t.net.mu.Lock()
+	l := t.net.listeners[uint16(port)]
+	t.net.mu.Unlock()
+	if l == nil {
+		return nil, errPipeRefused
+	}
+	c1, c2 := net.Pipe()
+	select {
+	case l.conns <- c1:
+		return c2, nil
+	case <-l.closed:
+		c1.Close()
+		c2.Close()
+		return nil, errPipeRefused
+	}
+}
+
+type pipeListener struct {
+	net       *PipeNetwork
+	addr      *net.TCPAddr
+	conns     chan net.Conn
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.closed:
+		return nil, errors.New("pipe listener closed")
+	}
+}
+
+func (l *pipeListener) Close() error {
+	l.closeOnce.Do(func() {
+		l.net.mu.Lock()
+		delete(l.net.listeners, uint16(l.addr.Port))
+		l.net.mu.Unlock()
+		close(l.closed)
+	})
+	return nil
+}
+
+func (l *pipeListener) Addr() net.Addr {
+	return l.addr
+}
@@ -0,0 +1,180 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/p2p/discover"
+	"github.com/rwdxchain/go-rwdxchaina/p2p/enr"
+)
+
+func TestPipeNetwork(t *testing.T) {
+	tr := NewPipeNetwork().Transport("pipe")
+	l1, err := tr.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l2, err := tr.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l1.Addr().String() == l2.Addr().String() {
+		t.Fatalf("listeners share address %v", l1.Addr())
+	}
+	if _, err := tr.Listen(l1.Addr().String()); err == nil {
+		t.Fatal("listening on used address succeeded")
+	}
+
+	go func() {
+		c, err := l2.Accept()
+		if err != nil {
+			return
+		}
+		c.Write([]byte("hello"))
+		c.Close()
+	}()
+	c, err := tr.Dial(l2.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(c, buf); err != nil || string(buf) != "hello" {
+		t.Fatalf("wrong data %q, err %v", buf, err)
+	}
+
+	l2.Close()
+	if _, err := tr.Dial(l2.Addr().String()); err != errPipeRefused {
+		t.Fatalf("wrong error for closed listener: %v", err)
+	}
+}
+
+func startTransportTestServer(t *testing.T, config Config) *Server {
+	config.PrivateKey = newkey()
+	config.MaxPeers = 10
+	config.NoDiscovery = true
+	config.Protocols = []Protocol{discard}
+	srv := &Server{Config: config}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start server: %v", err)
+	}
+	return srv
+}
+
+func waitPeer(t *testing.T, srv *Server, id discover.NodeID) *Peer {
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		for _, p := range srv.Peers() {
+			if p.ID() == id {
+				return p
+			}
+		}
+	}
+	t.Fatalf("peer %x not connected", id[:8])
+	return nil
+}
+
+// Tests that servers can run on an in-memory transport.
+func TestServerPipeTransport(t *testing.T) {
+	var (
+		pn   = NewPipeNetwork()
+		srv1 = startTransportTestServer(t, Config{Transport: pn.Transport("pipe"), ListenAddr: "127.0.0.1:0"})
+		srv2 = startTransportTestServer(t, Config{Transport: pn.Transport("pipe"), ListenAddr: "127.0.0.1:0"})
+	)
+	defer srv1.Stop()
+	defer srv2.Stop()
+
+	srv2.AddPeer(srv1.Self())
+	waitPeer(t, srv1, srv2.Self().ID)
+}
+
+// Tests that additional transports are announced in the node record and used
+// for dialing nodes announcing them, falling back to TCP.
+func TestServerExtraTransport(t *testing.T) {
+	var (
+		pn   = NewPipeNetwork()
+		srv1 = startTransportTestServer(t, Config{
+			ListenAddr:      "127.0.0.1:0",
+			ExtraTransports: []TransportListener{{pn.Transport("pipe"), "127.0.0.1:0"}},
+		})
+		srv2 = startTransportTestServer(t, Config{
+			ExtraTransports: []TransportListener{{pn.Transport("pipe"), "127.0.0.1:0"}},
+		})
+	)
+	defer srv1.Stop()
+	defer srv2.Stop()
+
+	record := srv1.LocalRecord().Record()
+	var port uint16
+	if err := record.Load(enr.WithEntry("pipe", &port)); err != nil || port == 0 {
+		t.Fatalf("transport not announced: port %d, err %v", port, err)
+	}
+
+	// Dial with the record. The TCP endpoint is unreachable, so the connection
+	// can only be established through the pipe transport.
+	n := srv1.Self()
+	unreachable := discover.NewNode(n.ID, n.IP, 0, 1)
+	task := &dialTask{flags: staticDialedConn, dest: unreachable}
+	if err := task.dial(srv2, unreachable, record); err != nil {
+		t.Fatalf("dial through pipe transport failed: %v", err)
+	}
+	if p := waitPeer(t, srv1, srv2.Self().ID); p.RemoteAddr().Network() != "pipe" {
+		t.Errorf("peer not connected through pipe transport, remote addr %v", p.RemoteAddr())
+	}
+	srv2.RemovePeer(n)
+
+	// Dial without the transport entry, falling back to TCP.
+	var plain enr.Record
+	plain.Set(enr.TCP(n.TCP))
+	srv3 := startTransportTestServer(t, Config{ExtraTransports: []TransportListener{{pn.Transport("pipe"), "127.0.0.1:0"}}})
+	defer srv3.Stop()
+	task = &dialTask{flags: staticDialedConn, dest: n}
+	if err := task.dial(srv3, n, &plain); err != nil {
+		t.Fatalf("TCP dial failed: %v", err)
+	}
+	if p := waitPeer(t, srv1, srv3.Self().ID); p.RemoteAddr().Network() != "tcp" {
+		t.Errorf("peer not connected through TCP, remote addr %v", p.RemoteAddr())
+	}
+}
+
+// Tests that node records are requested once per dialed node until they go stale.
+func TestDialRecordCache(t *testing.T) {
+	var (
+		c   = newRecordCache()
+		id  = discover.NodeID{1}
+		now = time.Unix(0, 0)
+		r   enr.Record
+	)
+	if _, ok := c.get(id, now); ok {
+		t.Fatal("empty cache returned record")
+	}
+	c.add(id, &r, now)
+	if record, ok := c.get(id, now.Add(recordCacheTTL-time.Second)); !ok || record != &r {
+		t.Fatal("cached record not returned")
+	}
+	if _, ok := c.get(id, now.Add(recordCacheTTL)); ok {
+		t.Fatal("stale record returned")
+	}
+	c.add(id, nil, now)
+	if record, ok := c.get(id, now); !ok || record != nil {
+		t.Fatal("failed request not cached")
+	}
+	if _, ok := c.get(id, now.Add(recordCacheFailedTTL)); ok {
+		t.Fatal("failed request cached too long")
+	}
+}
@@ -30,6 +30,28 @@ var (
 	RinkebyGenesisHash = common.HexToHash("0x6341fd3daf94b748c72ced5a5b26028f2474f5f00d824504e4fa37a75767e177")
 )
 
+// SyncCheckpoint is a trusted block an empty chain can start synchronising from.
+// Its total difficulty is trusted along with its hash, as it cannot be derived
+// without the chain segment preceding it.
+type SyncCheckpoint struct {
+	Hash common.Hash // Hash of the checkpoint block
+	TD   *big.Int    // Total difficulty of the checkpoint block
+}
+
+// SyncCheckpoints contains the trusted blocks of the preconfigured networks, keyed
+// by genesis hash, that nodes with an empty database start synchronising from
+// instead of the genesis block. A sync checkpoint explicitly requested by the user
+// takes precedence.
+var SyncCheckpoints = map[common.Hash]*SyncCheckpoint{}
+
+// CheckpointOracleConfig represents a set of checkpoint contract (which acts as an
+// oracle) config which used for light client checkpoint syncing.
+type CheckpointOracleConfig struct {
+	Address   common.Address   `json:"address"`   // Address of the checkpoint oracle contract
+	Signers   []common.Address `json:"signers"`   // Trusted signers whose approval is required
+	Threshold uint64           `json:"threshold"` // Number of distinct signers that must approve a checkpoint
+}
+
 var (
 	// MainnetChainConfig is the chain parameters to run a node on the main network.
 	MainnetChainConfig = &ChainConfig{
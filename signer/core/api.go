@@ -23,17 +23,21 @@ import (
 	"fmt"
 	"io/ioutil"
 	"math/big"
+	"path/filepath"
 	"reflect"
 
 	"github.com/rwdxchain/go-rwdxchaina/accounts"
+	"github.com/rwdxchain/go-rwdxchaina/accounts/hdwallet"
 	"github.com/rwdxchain/go-rwdxchaina/accounts/keystore"
 	"github.com/rwdxchain/go-rwdxchaina/accounts/usbwallet"
 	"github.com/rwdxchain/go-rwdxchaina/common"
 	"github.com/rwdxchain/go-rwdxchaina/common/hexutil"
+	"github.com/rwdxchain/go-rwdxchaina/core/types"
 	"github.com/rwdxchain/go-rwdxchaina/crypto"
 	"github.com/rwdxchain/go-rwdxchaina/internal/ethapi"
 	"github.com/rwdxchain/go-rwdxchaina/log"
 	"github.com/rwdxchain/go-rwdxchaina/rlp"
+	"github.com/rwdxchain/go-rwdxchaina/signer/eip712"
 )
 
 // ExternalAPI defines the external API through which signing requests are made.
@@ -46,6 +50,8 @@ type ExternalAPI interface {
 	SignTransaction(ctx context.Context, args SendTxArgs, methodSelector *string) (*ethapi.SignTransactionResult, error)
 	// Sign - request to sign the given data (plus prefix)
 	Sign(ctx context.Context, addr common.MixedcaseAddress, data hexutil.Bytes) (hexutil.Bytes, error)
+	// SignTypedData - request to sign the given typed structured data (EIP-712)
+	SignTypedData(ctx context.Context, addr common.MixedcaseAddress, typedData eip712.TypedData) (hexutil.Bytes, error)
 	// EcRecover - request to perform ecrecover
 	EcRecover(ctx context.Context, data, sig hexutil.Bytes) (common.Address, error)
 	// Export - request to export an account
@@ -87,6 +93,7 @@ type SignerAPI struct {
 	am        *accounts.Manager
 	UI        SignerUI
 	validator *Validator
+	approvals *ApprovalQueue // Queue of transactions awaiting multi-party approval, nil if disabled
 }
 
 // Metadata about a request
@@ -154,12 +161,17 @@ type (
 		OldPassword string `json:"old_password"`
 		NewPassword string `json:"new_password"`
 	}
+	// SignDataRequest info about a request to sign data. For typed data the
+	// message is a readable rendering of the typed data, which is included
+	// as well.
 	SignDataRequest struct {
-		Address common.MixedcaseAddress `json:"address"`
-		Rawdata hexutil.Bytes           `json:"raw_data"`
-		Message string                  `json:"message"`
-		Hash    hexutil.Bytes           `json:"hash"`
-		Meta    Metadata                `json:"meta"`
+		ContentType string                  `json:"content_type"`
+		Address     common.MixedcaseAddress `json:"address"`
+		Rawdata     hexutil.Bytes           `json:"raw_data"`
+		Message     string                  `json:"message"`
+		Hash        hexutil.Bytes           `json:"hash"`
+		TypedData   *eip712.TypedData       `json:"typed_data,omitempty"`
+		Meta        Metadata                `json:"meta"`
 	}
 	SignDataResponse struct {
 		Approved bool `json:"approved"`
@@ -187,14 +199,21 @@ type (
 	}
 )
 
+// Content types of data to sign
+const (
+	TextPlain = "text/plain" // Arbitrary data, signed with the personal_sign prefix
+	DataTyped = "data/typed" // Typed structured data according to EIP-712
+)
+
 var ErrRequestDenied = errors.New("Request denied")
 
 // NewSignerAPI creates a new API that can be used for Account management.
 // ksLocation specifies the directory where to store the password protected private
 // key that is generated when a new Account is created.
 // noUSB disables USB support that is required to support hardware devices such as
-// ledger and trezor.
-func NewSignerAPI(chainID int64, ksLocation string, noUSB bool, ui SignerUI, abidb *AbiDb, lightKDF bool) *SignerAPI {
+// ledger and trezor. Any extra backends, such as key databases or remote signers,
+// are added to the account manager as well.
+func NewSignerAPI(chainID int64, ksLocation string, noUSB bool, ui SignerUI, abidb *AbiDb, lightKDF bool, extra ...accounts.Backend) *SignerAPI {
 	var (
 		backends []accounts.Backend
 		n, p     = keystore.StandardScryptN, keystore.StandardScryptP
@@ -205,7 +224,16 @@ func NewSignerAPI(chainID int64, ksLocation string, noUSB bool, ui SignerUI, abi
 	// support password based accounts
 	if len(ksLocation) > 0 {
 		backends = append(backends, keystore.NewKeyStore(ksLocation, n, p))
+
+		// support HD wallets kept along with the keystore
+		if hdhub, err := hdwallet.NewHub(filepath.Join(ksLocation, hdwallet.KeyStoreSubdir), n, p); err != nil {
+			log.Warn(fmt.Sprintf("Failed to load HD wallets, disabling: %v", err))
+		} else {
+			backends = append(backends, hdhub)
+			log.Debug("HD wallet support enabled")
+		}
 	}
+	backends = append(backends, extra...)
 	if !noUSB {
 		// Start a USB hub for Ledger hardware wallets
 		if ledgerhub, err := usbwallet.NewLedgerHub(); err != nil {
@@ -214,15 +242,22 @@ func NewSignerAPI(chainID int64, ksLocation string, noUSB bool, ui SignerUI, abi
 			backends = append(backends, ledgerhub)
 			log.Debug("Ledger support enabled")
 		}
-		// Start a USB hub for Trezor hardware wallets
+		// Start a USB hub for Trezor hardware wallets (HID version)
 		if trezorhub, err := usbwallet.NewTrezorHub(); err != nil {
-			log.Warn(fmt.Sprintf("Failed to start Trezor hub, disabling: %v", err))
+			log.Warn(fmt.Sprintf("Failed to start HID Trezor hub, disabling: %v", err))
 		} else {
 			backends = append(backends, trezorhub)
-			log.Debug("Trezor support enabled")
+			log.Debug("Trezor support enabled via HID")
+		}
+		// Start a USB hub for Trezor hardware wallets (WebUSB version)
+		if trezorhub, err := usbwallet.NewTrezorHubWithWebUSB(); err != nil {
+			log.Warn(fmt.Sprintf("Failed to start WebUSB Trezor hub, disabling: %v", err))
+		} else {
+			backends = append(backends, trezorhub)
+			log.Debug("Trezor support enabled via WebUSB")
 		}
 	}
-	return &SignerAPI{big.NewInt(chainID), accounts.NewManager(backends...), ui, NewValidator(abidb)}
+	return &SignerAPI{big.NewInt(chainID), accounts.NewManager(backends...), ui, NewValidator(abidb), nil}
 }
 
 // List returns the set of wallet this signer manages. Each wallet can contain
@@ -337,6 +372,13 @@ func (api *SignerAPI) SignTransaction(ctx context.Context, args SendTxArgs, meth
 	}
 	// Log changes made by the UI to the signing-request
 	logDiff(&req, &result)
+
+	// Hold the transaction until a quorum of approvers signed off on it, if required
+	if err := api.awaitApprovals(ctx, &req, &result); err != nil {
+		api.UI.ShowError(err.Error())
+		api.failTx(&result, err)
+		return nil, err
+	}
 	var (
 		acc    accounts.Account
 		wallet accounts.Wallet
@@ -344,6 +386,7 @@ func (api *SignerAPI) SignTransaction(ctx context.Context, args SendTxArgs, meth
 	acc = accounts.Account{Address: result.Transaction.From.Address()}
 	wallet, err = api.am.Find(acc)
 	if err != nil {
+		api.failTx(&result, err)
 		return nil, err
 	}
 	// Convert fields into a real transaction
@@ -353,6 +396,7 @@ func (api *SignerAPI) SignTransaction(ctx context.Context, args SendTxArgs, meth
 	signedTx, err := wallet.SignTxWithPassphrase(acc, result.Password, unsignedTx, api.chainID)
 	if err != nil {
 		api.UI.ShowError(err.Error())
+		api.failTx(&result, err)
 		return nil, err
 	}
 
@@ -366,6 +410,47 @@ func (api *SignerAPI) SignTransaction(ctx context.Context, args SendTxArgs, meth
 
 }
 
+// failTx notifies the UI, if it cares, that an approved transaction could not be
+// signed.
+func (api *SignerAPI) failTx(result *SignTxResponse, err error) {
+	if handler, ok := api.UI.(FailedTxHandler); ok {
+		handler.OnFailedTx(result.Transaction, err)
+	}
+}
+
+// EnableApprovals makes transactions requiring the approval of several parties,
+// as decided by the UI, wait in the given queue before being signed.
+func (api *SignerAPI) EnableApprovals(queue *ApprovalQueue) {
+	api.approvals = queue
+}
+
+// awaitApprovals checks whether the UI approved transaction requires the
+// approval of several approvers and if so, blocks until it is decided.
+func (api *SignerAPI) awaitApprovals(ctx context.Context, req *SignTxRequest, result *SignTxResponse) error {
+	policy, ok := api.UI.(ApprovalPolicy)
+	if !ok {
+		return nil
+	}
+	// The quorum is decided on the transaction as it will be signed
+	final := *req
+	final.Transaction = result.Transaction
+
+	quorum, err := policy.RequiredApprovals(&final)
+	if err != nil {
+		return err
+	}
+	if quorum <= 0 {
+		return nil
+	}
+	if api.approvals == nil {
+		return ErrNoApprovers
+	}
+	hash := types.NewEIP155Signer(api.chainID).Hash(result.Transaction.toTransaction())
+	api.UI.ShowInfo(fmt.Sprintf("Transaction %s requires the approval of %d approvers", hash.Hex(), quorum))
+
+	return api.approvals.Await(ctx, result.Transaction, hash, quorum, req.Meta)
+}
+
 // Sign calculates an Ethereum ECDSA signature for:
 // keccack256("\x19Ethereum Signed Message:\n" + len(message) + message))
 //
@@ -377,11 +462,35 @@ func (api *SignerAPI) SignTransaction(ctx context.Context, args SendTxArgs, meth
 // https://github.com/rwdxchain/go-rwdxchaina/wiki/Management-APIs#personal_sign
 func (api *SignerAPI) Sign(ctx context.Context, addr common.MixedcaseAddress, data hexutil.Bytes) (hexutil.Bytes, error) {
 	sighash, msg := SignHash(data)
+	req := &SignDataRequest{ContentType: TextPlain, Address: addr, Rawdata: data, Message: msg, Hash: sighash, Meta: MetadataFromContext(ctx)}
+	return api.signData(req)
+}
+
+// SignTypedData signs typed structured data according to EIP-712, which is
+// calculated as
+// keccak256("\x19\x01" + domainSeparator + hashStruct(message))
+//
+// The typed data is presented to the user in a readable form for approval.
+// The V value of the signature will be 27 or 28, as with Sign.
+func (api *SignerAPI) SignTypedData(ctx context.Context, addr common.MixedcaseAddress, typedData eip712.TypedData) (hexutil.Bytes, error) {
+	sighash, rawData, err := typedData.SigHash()
+	if err != nil {
+		return nil, err
+	}
+	msg, err := typedData.Pprint()
+	if err != nil {
+		return nil, err
+	}
+	req := &SignDataRequest{ContentType: DataTyped, Address: addr, Rawdata: rawData, Message: msg, Hash: sighash, TypedData: &typedData, Meta: MetadataFromContext(ctx)}
+	return api.signData(req)
+}
+
+// signData asks the UI to approve a request to sign data and signs the hash
+// of the request if approved.
+func (api *SignerAPI) signData(req *SignDataRequest) (hexutil.Bytes, error) {
 	// We make the request prior to looking up if we actually have the account, to prevent
 	// account-enumeration via the API
-	req := &SignDataRequest{Address: addr, Rawdata: data, Message: msg, Hash: sighash, Meta: MetadataFromContext(ctx)}
 	res, err := api.UI.ApproveSignData(req)
-
 	if err != nil {
 		return nil, err
 	}
@@ -389,13 +498,13 @@ func (api *SignerAPI) Sign(ctx context.Context, addr common.MixedcaseAddress, da
 		return nil, ErrRequestDenied
 	}
 	// Look up the wallet containing the requested signer
-	account := accounts.Account{Address: addr.Address()}
+	account := accounts.Account{Address: req.Address.Address()}
 	wallet, err := api.am.Find(account)
 	if err != nil {
 		return nil, err
 	}
 	// Assemble sign the data with the wallet
-	signature, err := wallet.SignHashWithPassphrase(account, res.Password, sighash)
+	signature, err := wallet.SignHashWithPassphrase(account, res.Password, req.Hash)
 	if err != nil {
 		api.UI.ShowError(err.Error())
 		return nil, err
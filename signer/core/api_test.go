@@ -31,9 +31,12 @@ import (
 	"github.com/rwdxchain/go-rwdxchaina/cmd/utils"
 	"github.com/rwdxchain/go-rwdxchaina/common"
 	"github.com/rwdxchain/go-rwdxchaina/common/hexutil"
+	"github.com/rwdxchain/go-rwdxchaina/common/math"
 	"github.com/rwdxchain/go-rwdxchaina/core/types"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
 	"github.com/rwdxchain/go-rwdxchaina/internal/ethapi"
 	"github.com/rwdxchain/go-rwdxchaina/rlp"
+	"github.com/rwdxchain/go-rwdxchaina/signer/eip712"
 )
 
 //Used for testing
@@ -254,6 +257,65 @@ func TestSignData(t *testing.T) {
 		t.Errorf("Expected 65 byte signature (got %d bytes)", len(h))
 	}
 }
+
+func TestSignTypedData(t *testing.T) {
+
+	api, control := setup(t)
+	createAccount(control, api, t)
+	createAccount(control, api, t)
+	control <- "1"
+	list, err := api.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := common.NewMixedcaseAddress(list[0].Address)
+
+	typedData := eip712.TypedData{
+		Types: eip712.Types{
+			"EIP712Domain": {{Name: "name", Type: "string"}, {Name: "chainId", Type: "uint256"}},
+			"Greeting":     {{Name: "text", Type: "string"}, {Name: "count", Type: "uint8"}},
+		},
+		PrimaryType: "Greeting",
+		Domain:      eip712.TypedDataDomain{Name: "EHLO", ChainId: (*math.HexOrDecimal256)(big.NewInt(1))},
+		Message:     eip712.TypedDataMessage{"text": "EHLO world", "count": float64(1)},
+	}
+	// Invalid typed data is rejected without asking the user
+	invalid := typedData
+	invalid.PrimaryType = "Farewell"
+	if _, err := api.SignTypedData(context.Background(), a, invalid); err == nil {
+		t.Errorf("Expected error for unknown primary type")
+	}
+
+	control <- "No way"
+	h, err := api.SignTypedData(context.Background(), a, typedData)
+	if h != nil {
+		t.Errorf("Expected nil-data, got %x", h)
+	}
+	if err != ErrRequestDenied {
+		t.Errorf("Expected ErrRequestDenied! %v", err)
+	}
+
+	control <- "Y"
+	control <- "apassword"
+	h, err = api.SignTypedData(context.Background(), a, typedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h == nil || len(h) != 65 {
+		t.Fatalf("Expected 65 byte signature (got %d bytes)", len(h))
+	}
+	sighash, _, _ := typedData.SigHash()
+	sig := common.CopyBytes(h)
+	sig[64] -= 27
+	pubkey, err := crypto.SigToPub(sighash, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer := crypto.PubkeyToAddress(*pubkey); signer != a.Address() {
+		t.Errorf("Signer mismatch: have %x, want %x", signer, a.Address())
+	}
+}
+
 func mkTestTx(from common.MixedcaseAddress) SendTxArgs {
 	to := common.NewMixedcaseAddress(common.HexToAddress("0x1337"))
 	gas := hexutil.Uint64(21000)
@@ -0,0 +1,331 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pborman/uuid"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/common/hexutil"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+	"github.com/rwdxchain/go-rwdxchaina/log"
+)
+
+var (
+	// ErrApprovalTimeout is returned if a transaction did not gather the required
+	// approvals in time.
+	ErrApprovalTimeout = errors.New("approval timed out")
+
+	// ErrApprovalRejected is returned if enough approvers rejected a transaction
+	// for the required quorum to become unreachable.
+	ErrApprovalRejected = errors.New("approval rejected")
+
+	// ErrNoApprovers is returned if a transaction requires approvals, but no
+	// approvers are registered.
+	ErrNoApprovers = errors.New("no approvers registered")
+
+	// ErrUnknownApprover is returned if a vote is not signed by a registered approver.
+	ErrUnknownApprover = errors.New("unknown approver")
+
+	// ErrUnknownApprovalRequest is returned when voting on a request that is not
+	// (or no longer) pending.
+	ErrUnknownApprovalRequest = errors.New("unknown approval request")
+
+	// ErrAlreadyVoted is returned if an approver votes twice on the same request.
+	ErrAlreadyVoted = errors.New("approver already voted")
+)
+
+// ApprovalPolicy is implemented by UIs able to decide whether a transaction needs
+// the approval of several approvers before being signed, e.g. the rule engine.
+type ApprovalPolicy interface {
+	// RequiredApprovals returns the number of approvers that need to approve
+	// the transaction, 0 if it can be signed right away.
+	RequiredApprovals(request *SignTxRequest) (int, error)
+}
+
+// FailedTxHandler is implemented by UIs holding on to resources for approved
+// transactions until they are signed, e.g. the spend limits reserved by the rule
+// engine, to be notified of approved transactions that failed to get signed.
+type FailedTxHandler interface {
+	// OnFailedTx notifies the UI that an approved transaction was not signed.
+	OnFailedTx(tx SendTxArgs, err error)
+}
+
+// Approver is an identity allowed to vote on transactions pending approval,
+// authenticating its votes by signing them with its key.
+type Approver struct {
+	Name    string         `json:"name"`
+	Address common.Address `json:"address"`
+}
+
+// PendingApproval is a transaction waiting for a quorum of approvers.
+type PendingApproval struct {
+	ID          string           `json:"id"`
+	Transaction SendTxArgs       `json:"transaction"`
+	Hash        common.Hash      `json:"hash"` // Hash to be signed by the account, binding the votes to the transaction
+	Quorum      int              `json:"quorum"`
+	Approvals   []common.Address `json:"approvals"`
+	Rejections  []common.Address `json:"rejections"`
+	Deadline    time.Time        `json:"deadline"`
+	Meta        Metadata         `json:"meta"`
+}
+
+// pendingApproval is a pending request along with the channel its outcome is
+// delivered on.
+type pendingApproval struct {
+	PendingApproval
+	done chan error
+}
+
+// copy returns a snapshot of the pending request, safe to use without the lock
+// of the queue held.
+func (p *pendingApproval) copy() PendingApproval {
+	cpy := p.PendingApproval
+	cpy.Approvals = append([]common.Address{}, p.Approvals...)
+	cpy.Rejections = append([]common.Address{}, p.Rejections...)
+	return cpy
+}
+
+// voted returns whether the given approver already voted on the request.
+func (p *pendingApproval) voted(addr common.Address) bool {
+	for _, voter := range p.Approvals {
+		if voter == addr {
+			return true
+		}
+	}
+	for _, voter := range p.Rejections {
+		if voter == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// ApprovalMessage returns the text an approver signs, with the personal_sign
+// prefix, to approve or reject a pending request. The message names the request
+// and the hash of the transaction, so a vote cannot be replayed on another one.
+func ApprovalMessage(id string, hash common.Hash, approve bool) string {
+	decision := "Reject"
+	if approve {
+		decision = "Approve"
+	}
+	return fmt.Sprintf("%s clef request %s for transaction %s", decision, id, hash.Hex())
+}
+
+// ApprovalQueue holds the transactions waiting for the approval of a quorum of
+// registered approvers.
+type ApprovalQueue struct {
+	approvers map[common.Address]Approver
+	timeout   time.Duration
+
+	pending map[string]*pendingApproval
+	lock    sync.Mutex
+}
+
+// NewApprovalQueue creates an approval queue for the given approvers. Requests
+// not decided within the timeout fail.
+func NewApprovalQueue(approvers []Approver, timeout time.Duration) *ApprovalQueue {
+	q := &ApprovalQueue{
+		approvers: make(map[common.Address]Approver),
+		timeout:   timeout,
+		pending:   make(map[string]*pendingApproval),
+	}
+	for _, approver := range approvers {
+		q.approvers[approver.Address] = approver
+	}
+	return q
+}
+
+// Approvers returns the registered approvers, sorted by address.
+func (q *ApprovalQueue) Approvers() []Approver {
+	approvers := make([]Approver, 0, len(q.approvers))
+	for _, approver := range q.approvers {
+		approvers = append(approvers, approver)
+	}
+	sort.Slice(approvers, func(i, j int) bool {
+		return approvers[i].Address.Hex() < approvers[j].Address.Hex()
+	})
+	return approvers
+}
+
+// Await queues a transaction and blocks until quorum approvers approved it, it
+// was rejected, the timeout expired or the context was cancelled.
+func (q *ApprovalQueue) Await(ctx context.Context, args SendTxArgs, hash common.Hash, quorum int, meta Metadata) error {
+	if len(q.approvers) == 0 {
+		return ErrNoApprovers
+	}
+	if quorum > len(q.approvers) {
+		return fmt.Errorf("approval quorum %d exceeds the %d registered approvers", quorum, len(q.approvers))
+	}
+	p := &pendingApproval{
+		PendingApproval: PendingApproval{
+			ID:          uuid.NewRandom().String(),
+			Transaction: args,
+			Hash:        hash,
+			Quorum:      quorum,
+			Deadline:    time.Now().Add(q.timeout),
+			Meta:        meta,
+		},
+		done: make(chan error, 1),
+	}
+	q.lock.Lock()
+	q.pending[p.ID] = p
+	q.lock.Unlock()
+
+	log.Info("Transaction pending approval", "id", p.ID, "hash", hash, "quorum", quorum, "deadline", p.Deadline)
+
+	timer := time.NewTimer(q.timeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case err = <-p.done:
+		return err
+	case <-timer.C:
+		err = ErrApprovalTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	// The request expired, drop it unless a final vote raced with the expiry
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	delete(q.pending, p.ID)
+	select {
+	case decision := <-p.done:
+		return decision
+	default:
+		log.Info("Transaction approval expired", "id", p.ID, "err", err)
+		return err
+	}
+}
+
+// Pending returns the requests currently waiting for approval.
+func (q *ApprovalQueue) Pending() []PendingApproval {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	pending := make([]PendingApproval, 0, len(q.pending))
+	for _, p := range q.pending {
+		pending = append(pending, p.copy())
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Deadline.Before(pending[j].Deadline)
+	})
+	return pending
+}
+
+// Vote records the approval or the rejection of a pending request, signed by one
+// of the approvers over the ApprovalMessage of the request. Once the quorum is
+// reached or becomes unreachable, the request is decided.
+func (q *ApprovalQueue) Vote(id string, approve bool, signature []byte) (PendingApproval, error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	p, ok := q.pending[id]
+	if !ok {
+		return PendingApproval{}, ErrUnknownApprovalRequest
+	}
+	voter, err := recoverApprover(ApprovalMessage(id, p.Hash, approve), signature)
+	if err != nil {
+		return PendingApproval{}, err
+	}
+	if _, ok := q.approvers[voter]; !ok {
+		return PendingApproval{}, ErrUnknownApprover
+	}
+	if p.voted(voter) {
+		return PendingApproval{}, ErrAlreadyVoted
+	}
+	if approve {
+		p.Approvals = append(p.Approvals, voter)
+	} else {
+		p.Rejections = append(p.Rejections, voter)
+	}
+	log.Info("Transaction approval vote", "id", id, "approver", q.approvers[voter].Name, "approve", approve)
+
+	switch {
+	case len(p.Approvals) >= p.Quorum:
+		p.done <- nil
+		delete(q.pending, id)
+	case len(p.Rejections) > len(q.approvers)-p.Quorum:
+		p.done <- ErrApprovalRejected
+		delete(q.pending, id)
+	}
+	return p.copy(), nil
+}
+
+// recoverApprover returns the address that signed the message with the
+// personal_sign prefix.
+func recoverApprover(message string, signature []byte) (common.Address, error) {
+	if len(signature) != 65 {
+		return common.Address{}, fmt.Errorf("signature must be 65 bytes long")
+	}
+	if signature[64] != 27 && signature[64] != 28 {
+		return common.Address{}, fmt.Errorf("invalid Ethereum signature (V is not 27 or 28)")
+	}
+	sig := make([]byte, 65)
+	copy(sig, signature)
+	sig[64] -= 27
+
+	hash, _ := SignHash([]byte(message))
+	pubkey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
+
+// ApprovalAPI is the API through which approvers vote on the transactions
+// waiting for their approval.
+type ApprovalAPI interface {
+	// Pending returns the transactions waiting for approval
+	Pending(ctx context.Context) ([]PendingApproval, error)
+	// Approve approves a pending transaction
+	Approve(ctx context.Context, id string, signature hexutil.Bytes) (PendingApproval, error)
+	// Reject rejects a pending transaction
+	Reject(ctx context.Context, id string, signature hexutil.Bytes) (PendingApproval, error)
+}
+
+// approvalAPI exposes an approval queue to the approvers, without its signer
+// facing methods.
+type approvalAPI struct {
+	queue *ApprovalQueue
+}
+
+// NewApprovalAPI creates the API through which approvers vote on the requests
+// of the given queue.
+func NewApprovalAPI(queue *ApprovalQueue) ApprovalAPI {
+	return &approvalAPI{queue}
+}
+
+func (api *approvalAPI) Pending(ctx context.Context) ([]PendingApproval, error) {
+	return api.queue.Pending(), nil
+}
+
+func (api *approvalAPI) Approve(ctx context.Context, id string, signature hexutil.Bytes) (PendingApproval, error) {
+	return api.queue.Vote(id, true, signature)
+}
+
+func (api *approvalAPI) Reject(ctx context.Context, id string, signature hexutil.Bytes) (PendingApproval, error) {
+	return api.queue.Vote(id, false, signature)
+}
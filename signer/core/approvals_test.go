@@ -0,0 +1,229 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"testing"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+)
+
+// testApprovers creates a set of approver keys and their identities.
+func testApprovers(n int) ([]*ecdsa.PrivateKey, []Approver) {
+	var (
+		keys      []*ecdsa.PrivateKey
+		approvers []Approver
+	)
+	for i := 0; i < n; i++ {
+		key, _ := crypto.GenerateKey()
+		keys = append(keys, key)
+		approvers = append(approvers, Approver{Name: string('a' + rune(i)), Address: crypto.PubkeyToAddress(key.PublicKey)})
+	}
+	return keys, approvers
+}
+
+// signVote signs an approval or rejection of a pending request.
+func signVote(t *testing.T, key *ecdsa.PrivateKey, p PendingApproval, approve bool) []byte {
+	hash, _ := SignHash([]byte(ApprovalMessage(p.ID, p.Hash, approve)))
+	sig, err := crypto.Sign(hash, key)
+	if err != nil {
+		t.Fatalf("failed to sign vote: %v", err)
+	}
+	sig[64] += 27
+	return sig
+}
+
+// awaitPending waits until the queue holds a pending request and returns it.
+func awaitPending(t *testing.T, q *ApprovalQueue) PendingApproval {
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		if pending := q.Pending(); len(pending) > 0 {
+			return pending[0]
+		}
+	}
+	t.Fatalf("no pending approval request")
+	return PendingApproval{}
+}
+
+// Tests that a transaction is approved once the quorum is reached, and that only
+// valid votes of registered approvers are counted.
+func TestApprovalQuorum(t *testing.T) {
+	keys, approvers := testApprovers(3)
+	q := NewApprovalQueue(approvers, time.Minute)
+
+	result := make(chan error, 1)
+	go func() {
+		result <- q.Await(context.Background(), SendTxArgs{}, common.Hash{0x01}, 2, Metadata{})
+	}()
+	p := awaitPending(t, q)
+	if p.Quorum != 2 || p.Hash != (common.Hash{0x01}) {
+		t.Fatalf("pending request mismatch: have quorum %d hash %x", p.Quorum, p.Hash)
+	}
+	// Votes from outsiders, for other decisions or in a broken format are refused
+	outsider, _ := crypto.GenerateKey()
+	if _, err := q.Vote(p.ID, true, signVote(t, outsider, p, true)); err != ErrUnknownApprover {
+		t.Errorf("outsider vote error mismatch: have %v, want %v", err, ErrUnknownApprover)
+	}
+	if _, err := q.Vote(p.ID, true, signVote(t, keys[0], p, false)); err != ErrUnknownApprover {
+		t.Errorf("replayed rejection error mismatch: have %v, want %v", err, ErrUnknownApprover)
+	}
+	if _, err := q.Vote(p.ID, true, []byte{0x01}); err == nil {
+		t.Errorf("malformed signature accepted")
+	}
+	if _, err := q.Vote("unknown", true, signVote(t, keys[0], p, true)); err != ErrUnknownApprovalRequest {
+		t.Errorf("unknown request error mismatch: have %v, want %v", err, ErrUnknownApprovalRequest)
+	}
+	// Valid votes are counted once
+	p, err := q.Vote(p.ID, true, signVote(t, keys[0], p, true))
+	if err != nil {
+		t.Fatalf("failed to approve: %v", err)
+	}
+	if len(p.Approvals) != 1 || p.Approvals[0] != approvers[0].Address {
+		t.Fatalf("approvals mismatch: have %v", p.Approvals)
+	}
+	if _, err := q.Vote(p.ID, false, signVote(t, keys[0], p, false)); err != ErrAlreadyVoted {
+		t.Errorf("duplicate vote error mismatch: have %v, want %v", err, ErrAlreadyVoted)
+	}
+	select {
+	case err := <-result:
+		t.Fatalf("request decided before quorum: %v", err)
+	default:
+	}
+	if _, err := q.Vote(p.ID, true, signVote(t, keys[2], p, true)); err != nil {
+		t.Fatalf("failed to approve: %v", err)
+	}
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Fatalf("approval error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("request not approved after reaching quorum")
+	}
+	if pending := q.Pending(); len(pending) != 0 {
+		t.Errorf("decided request still pending: %v", pending)
+	}
+}
+
+// Tests that a transaction is rejected as soon as the quorum becomes unreachable.
+func TestApprovalRejection(t *testing.T) {
+	keys, approvers := testApprovers(3)
+	q := NewApprovalQueue(approvers, time.Minute)
+
+	result := make(chan error, 1)
+	go func() {
+		result <- q.Await(context.Background(), SendTxArgs{}, common.Hash{0x02}, 2, Metadata{})
+	}()
+	p := awaitPending(t, q)
+
+	if _, err := q.Vote(p.ID, false, signVote(t, keys[0], p, false)); err != nil {
+		t.Fatalf("failed to reject: %v", err)
+	}
+	if _, err := q.Vote(p.ID, false, signVote(t, keys[1], p, false)); err != nil {
+		t.Fatalf("failed to reject: %v", err)
+	}
+	select {
+	case err := <-result:
+		if err != ErrApprovalRejected {
+			t.Fatalf("rejection error mismatch: have %v, want %v", err, ErrApprovalRejected)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("request not rejected with unreachable quorum")
+	}
+}
+
+// Tests that undecided transactions expire, and that unsatisfiable quorums are
+// refused outright.
+func TestApprovalTimeout(t *testing.T) {
+	_, approvers := testApprovers(2)
+	q := NewApprovalQueue(approvers, 50*time.Millisecond)
+
+	if err := q.Await(context.Background(), SendTxArgs{}, common.Hash{}, 1, Metadata{}); err != ErrApprovalTimeout {
+		t.Fatalf("timeout error mismatch: have %v, want %v", err, ErrApprovalTimeout)
+	}
+	if pending := q.Pending(); len(pending) != 0 {
+		t.Errorf("expired request still pending: %v", pending)
+	}
+	if err := q.Await(context.Background(), SendTxArgs{}, common.Hash{}, 3, Metadata{}); err == nil {
+		t.Fatalf("unsatisfiable quorum accepted")
+	}
+	if err := NewApprovalQueue(nil, time.Minute).Await(context.Background(), SendTxArgs{}, common.Hash{}, 1, Metadata{}); err != ErrNoApprovers {
+		t.Fatalf("missing approvers error mismatch: have %v, want %v", err, ErrNoApprovers)
+	}
+}
+
+// quorumUI is a headless UI requiring a fixed number of approvals for every
+// transaction.
+type quorumUI struct {
+	*HeadlessUI
+	quorum int
+}
+
+func (ui *quorumUI) RequiredApprovals(request *SignTxRequest) (int, error) {
+	return ui.quorum, nil
+}
+
+// Tests that the signer holds transactions requiring approvals until approved.
+func TestSignTxWithApprovals(t *testing.T) {
+	api, control := setup(t)
+	createAccount(control, api, t)
+	control <- "A"
+	list, err := api.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	methodSig := "test(uint)"
+	tx := mkTestTx(common.NewMixedcaseAddress(list[0].Address))
+
+	api.UI = &quorumUI{api.UI.(*HeadlessUI), 1}
+
+	// Without approvers, the transaction cannot be signed
+	control <- "Y"
+	control <- "apassword"
+	if _, err := api.SignTransaction(context.Background(), tx, &methodSig); err != ErrNoApprovers {
+		t.Fatalf("missing approvers error mismatch: have %v, want %v", err, ErrNoApprovers)
+	}
+	keys, approvers := testApprovers(1)
+	queue := NewApprovalQueue(approvers, time.Minute)
+	api.EnableApprovals(queue)
+
+	control <- "Y"
+	control <- "apassword"
+	done := make(chan error, 1)
+	go func() {
+		_, err := api.SignTransaction(context.Background(), tx, &methodSig)
+		done <- err
+	}()
+	p := awaitPending(t, queue)
+	if want := tx.toTransaction(); p.Transaction.toTransaction().Hash() != want.Hash() {
+		t.Errorf("pending transaction mismatch")
+	}
+	if _, err := NewApprovalAPI(queue).Approve(context.Background(), p.ID, signVote(t, keys[0], p, true)); err != nil {
+		t.Fatalf("failed to approve: %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("failed to sign approved transaction: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("approved transaction not signed")
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/rwdxchain/go-rwdxchaina/common/hexutil"
 	"github.com/rwdxchain/go-rwdxchaina/internal/ethapi"
 	"github.com/rwdxchain/go-rwdxchaina/log"
+	"github.com/rwdxchain/go-rwdxchaina/signer/eip712"
 )
 
 type AuditLogger struct {
@@ -72,6 +73,14 @@ func (l *AuditLogger) Sign(ctx context.Context, addr common.MixedcaseAddress, da
 	return b, e
 }
 
+func (l *AuditLogger) SignTypedData(ctx context.Context, addr common.MixedcaseAddress, typedData eip712.TypedData) (hexutil.Bytes, error) {
+	l.log.Info("SignTypedData", "type", "request", "metadata", MetadataFromContext(ctx).String(),
+		"addr", addr.String(), "data", typedData)
+	b, e := l.api.SignTypedData(ctx, addr, typedData)
+	l.log.Info("SignTypedData", "type", "response", "data", common.Bytes2Hex(b), "error", e)
+	return b, e
+}
+
 func (l *AuditLogger) EcRecover(ctx context.Context, data, sig hexutil.Bytes) (common.Address, error) {
 	l.log.Info("EcRecover", "type", "request", "metadata", MetadataFromContext(ctx).String(),
 		"data", common.Bytes2Hex(data))
@@ -98,6 +107,41 @@ func (l *AuditLogger) Import(ctx context.Context, keyJSON json.RawMessage) (Acco
 	return a, e
 }
 
+// ApprovalAuditLogger logs the votes cast through an ApprovalAPI into the audit
+// log of the signer.
+type ApprovalAuditLogger struct {
+	log log.Logger
+	api ApprovalAPI
+}
+
+func (l *ApprovalAuditLogger) Pending(ctx context.Context) ([]PendingApproval, error) {
+	return l.api.Pending(ctx)
+}
+
+func (l *ApprovalAuditLogger) Approve(ctx context.Context, id string, signature hexutil.Bytes) (PendingApproval, error) {
+	l.log.Info("Approve", "type", "request", "metadata", MetadataFromContext(ctx).String(),
+		"id", id, "signature", common.Bytes2Hex(signature))
+	p, e := l.api.Approve(ctx, id, signature)
+	l.log.Info("Approve", "type", "response", "id", id, "hash", p.Hash.Hex(),
+		"approvals", len(p.Approvals), "rejections", len(p.Rejections), "quorum", p.Quorum, "error", e)
+	return p, e
+}
+
+func (l *ApprovalAuditLogger) Reject(ctx context.Context, id string, signature hexutil.Bytes) (PendingApproval, error) {
+	l.log.Info("Reject", "type", "request", "metadata", MetadataFromContext(ctx).String(),
+		"id", id, "signature", common.Bytes2Hex(signature))
+	p, e := l.api.Reject(ctx, id, signature)
+	l.log.Info("Reject", "type", "response", "id", id, "hash", p.Hash.Hex(),
+		"approvals", len(p.Approvals), "rejections", len(p.Rejections), "quorum", p.Quorum, "error", e)
+	return p, e
+}
+
+// WrapApprovalAPI wraps an ApprovalAPI, logging the votes into the same
+// audit log as the external API.
+func (l *AuditLogger) WrapApprovalAPI(api ApprovalAPI) *ApprovalAuditLogger {
+	return &ApprovalAuditLogger{l.log, api}
+}
+
 func NewAuditLogger(path string, api ExternalAPI) (*AuditLogger, error) {
 	l := log.New("api", "signer")
 	handler, err := log.FileHandler(path, log.LogfmtFormat())
@@ -0,0 +1,167 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eip712
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/rwdxchain/go-rwdxchaina/common/hexutil"
+)
+
+// NameValueType is a named, typed value of typed data, presented to the user
+// when signing. The value of structs and arrays is a list of NameValueTypes,
+// all other values are strings.
+type NameValueType struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+	Typ   string      `json:"type"`
+}
+
+// Pprint returns the value as indented text, one field per line.
+func (nvt *NameValueType) Pprint(depth int) string {
+	var output bytes.Buffer
+	output.WriteString(strings.Repeat("  ", depth))
+	output.WriteString(fmt.Sprintf("%s [%s]:", nvt.Name, nvt.Typ))
+	if nvts, ok := nvt.Value.([]*NameValueType); ok {
+		output.WriteString("\n")
+		for _, next := range nvts {
+			output.WriteString(next.Pprint(depth + 1))
+		}
+	} else {
+		output.WriteString(fmt.Sprintf(" %q\n", nvt.Value))
+	}
+	return output.String()
+}
+
+// Format returns the domain and the message in a readable form.
+func (typedData *TypedData) Format() ([]*NameValueType, error) {
+	domain, err := typedData.formatData(DomainType, typedData.Domain.Map())
+	if err != nil {
+		return nil, err
+	}
+	message, err := typedData.formatData(typedData.PrimaryType, typedData.Message)
+	if err != nil {
+		return nil, err
+	}
+	return []*NameValueType{
+		{Name: DomainType, Value: domain, Typ: "domain"},
+		{Name: typedData.PrimaryType, Value: message, Typ: "primary type"},
+	}, nil
+}
+
+// Pprint returns the domain and the message as indented text.
+func (typedData *TypedData) Pprint() (string, error) {
+	nvts, err := typedData.Format()
+	if err != nil {
+		return "", err
+	}
+	var output bytes.Buffer
+	for _, nvt := range nvts {
+		output.WriteString(nvt.Pprint(0))
+	}
+	return output.String(), nil
+}
+
+// formatData formats the fields of a struct value.
+func (typedData *TypedData) formatData(primaryType string, data TypedDataMessage) ([]*NameValueType, error) {
+	var output []*NameValueType
+	for _, field := range typedData.Types[primaryType] {
+		item, err := typedData.formatValue(field.Name, field.Type, data[field.Name])
+		if err != nil {
+			return nil, fmt.Errorf("field %s.%s: %v", primaryType, field.Name, err)
+		}
+		output = append(output, item)
+	}
+	return output, nil
+}
+
+// formatValue formats a value of the given type. Values are converted to
+// their canonical form, e.g. numbers to decimal and addresses to checksummed
+// hex, so the user sees exactly what's signed.
+func (typedData *TypedData) formatValue(name, typ string, value interface{}) (*NameValueType, error) {
+	item := &NameValueType{Name: name, Typ: typ}
+
+	if elemType, _, ok := parseArray(typ); ok {
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid value for %s: %v", typ, value)
+		}
+		var list []*NameValueType
+		for i, elem := range items {
+			formatted, err := typedData.formatValue(fmt.Sprintf("[%d]", i), elemType, elem)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, formatted)
+		}
+		item.Value = list
+		return item, nil
+	}
+	if _, ok := typedData.Types[typ]; ok {
+		data, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid value for %s: %v", typ, value)
+		}
+		fields, err := typedData.formatData(typ, data)
+		if err != nil {
+			return nil, err
+		}
+		item.Value = fields
+		return item, nil
+	}
+	switch typ {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid value for string: %v", value)
+		}
+		item.Value = s
+	case "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid value for bool: %v", value)
+		}
+		item.Value = fmt.Sprint(b)
+	case "address":
+		addr, err := parseAddress(value)
+		if err != nil {
+			return nil, err
+		}
+		item.Value = addr.Hex()
+	default:
+		kind, size, err := parseSized(typ)
+		if typ == "bytes" || kind == "bytes" {
+			b, err := parseBytes(value)
+			if err != nil {
+				return nil, err
+			}
+			item.Value = hexutil.Encode(b)
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		n, err := parseInteger(kind == "int", size, value)
+		if err != nil {
+			return nil, err
+		}
+		item.Value = n.String()
+	}
+	return item, nil
+}
@@ -0,0 +1,511 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package eip712 implements the hashing of typed structured data as specified
+// by EIP-712, which allows signers to present the data in a readable way.
+//
+// The hash signed for typed data is
+//
+//	keccak256("\x19\x01" ‖ hashStruct(domain) ‖ hashStruct(message))
+//
+// where hashStruct(s) = keccak256(typeHash ‖ encodeData(s)) and the domain is
+// a struct of the type EIP712Domain.
+package eip712
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/common/hexutil"
+	cmath "github.com/rwdxchain/go-rwdxchaina/common/math"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+)
+
+// DomainType is the name of the type of the domain separator struct.
+const DomainType = "EIP712Domain"
+
+// domainFields are the fields the domain type may have, with their types.
+var domainFields = map[string]string{
+	"name":              "string",
+	"version":           "string",
+	"chainId":           "uint256",
+	"verifyingContract": "address",
+	"salt":              "bytes32",
+}
+
+var (
+	typeNameRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+	sizedRegexp    = regexp.MustCompile(`^(u?int|bytes)([0-9]+)$`)
+)
+
+// Type is a field of a struct type: its name and the type of its value.
+type Type struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Types maps the names of struct types to their fields.
+type Types map[string][]Type
+
+// TypedDataMessage is the value of a struct, mapping field names to values as
+// decoded from JSON. Integers may be given as numbers or as decimal or hex
+// strings, bytes as hex strings.
+type TypedDataMessage = map[string]interface{}
+
+// TypedDataDomain is the domain separator, which makes signatures specific to
+// a dapp. Only the fields which are set are part of the EIP712Domain struct.
+type TypedDataDomain struct {
+	Name              string                 `json:"name,omitempty"`
+	Version           string                 `json:"version,omitempty"`
+	ChainId           *cmath.HexOrDecimal256 `json:"chainId,omitempty"`
+	VerifyingContract string                 `json:"verifyingContract,omitempty"`
+	Salt              string                 `json:"salt,omitempty"`
+}
+
+// TypedData is a message of typed structured data to sign, as passed to
+// eth_signTypedData.
+type TypedData struct {
+	Types       Types            `json:"types"`
+	PrimaryType string           `json:"primaryType"`
+	Domain      TypedDataDomain  `json:"domain"`
+	Message     TypedDataMessage `json:"message"`
+}
+
+// UnmarshalJSON decodes the domain, accepting the chain id as a JSON number as
+// well as a decimal or hex string.
+func (domain *TypedDataDomain) UnmarshalJSON(input []byte) error {
+	type typedDataDomain TypedDataDomain
+	var dec struct {
+		typedDataDomain
+		ChainId json.RawMessage `json:"chainId,omitempty"`
+	}
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	*domain = TypedDataDomain(dec.typedDataDomain)
+	if len(dec.ChainId) > 0 && string(dec.ChainId) != "null" {
+		domain.ChainId = new(cmath.HexOrDecimal256)
+		if err := domain.ChainId.UnmarshalText(bytes.Trim(dec.ChainId, `"`)); err != nil {
+			return fmt.Errorf("invalid chainId %s: %v", dec.ChainId, err)
+		}
+	}
+	return nil
+}
+
+// Map returns the domain as a struct value of the EIP712Domain type.
+func (domain *TypedDataDomain) Map() TypedDataMessage {
+	data := make(TypedDataMessage)
+	if domain.Name != "" {
+		data["name"] = domain.Name
+	}
+	if domain.Version != "" {
+		data["version"] = domain.Version
+	}
+	if domain.ChainId != nil {
+		data["chainId"] = (*big.Int)(domain.ChainId)
+	}
+	if domain.VerifyingContract != "" {
+		data["verifyingContract"] = domain.VerifyingContract
+	}
+	if domain.Salt != "" {
+		data["salt"] = domain.Salt
+	}
+	return data
+}
+
+// SigHash validates the typed data and returns the hash to sign, along with
+// the data it is calculated from:
+//
+//	keccak256("\x19\x01" ‖ domainSeparator ‖ hashStruct(message))
+func (typedData *TypedData) SigHash() (hash, rawData []byte, err error) {
+	if err := typedData.Validate(); err != nil {
+		return nil, nil, err
+	}
+	domainSeparator, err := typedData.HashStruct(DomainType, typedData.Domain.Map())
+	if err != nil {
+		return nil, nil, err
+	}
+	messageHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+	if err != nil {
+		return nil, nil, err
+	}
+	rawData = append([]byte{0x19, 0x01}, domainSeparator...)
+	rawData = append(rawData, messageHash...)
+	return crypto.Keccak256(rawData), rawData, nil
+}
+
+// HashStruct returns the hash of a struct value of the given type:
+//
+//	keccak256(typeHash ‖ encodeData(data))
+func (typedData *TypedData) HashStruct(primaryType string, data TypedDataMessage) (hexutil.Bytes, error) {
+	encoded, err := typedData.EncodeData(primaryType, data)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(encoded), nil
+}
+
+// Dependencies returns the struct types referenced by the given type,
+// directly or indirectly, including the type itself as the first entry.
+func (typedData *TypedData) Dependencies(primaryType string, found []string) []string {
+	primaryType = baseType(primaryType)
+	if includes(found, primaryType) {
+		return found
+	}
+	if typedData.Types[primaryType] == nil {
+		return found
+	}
+	found = append(found, primaryType)
+	for _, field := range typedData.Types[primaryType] {
+		found = typedData.Dependencies(field.Type, found)
+	}
+	return found
+}
+
+// EncodeType returns the encoding of a struct type and all types it
+// references, the latter sorted by name:
+//
+//	Mail(Person from,Person to,string contents)Person(string name,address wallet)
+func (typedData *TypedData) EncodeType(primaryType string) hexutil.Bytes {
+	deps := typedData.Dependencies(primaryType, nil)
+	if len(deps) > 1 {
+		sort.Strings(deps[1:])
+	}
+	var buffer bytes.Buffer
+	for _, dep := range deps {
+		fields := make([]string, len(typedData.Types[dep]))
+		for i, field := range typedData.Types[dep] {
+			fields[i] = field.Type + " " + field.Name
+		}
+		buffer.WriteString(dep + "(" + strings.Join(fields, ",") + ")")
+	}
+	return buffer.Bytes()
+}
+
+// TypeHash returns the hash of the encoding of a struct type.
+func (typedData *TypedData) TypeHash(primaryType string) hexutil.Bytes {
+	return crypto.Keccak256(typedData.EncodeType(primaryType))
+}
+
+// EncodeData returns the type hash of a struct type followed by the encoding
+// of each field of the given value, in the order of the type definition.
+// Atomic values are encoded as 32 byte words, dynamic values (string, bytes)
+// by their hash, arrays by the hash of the concatenated encodings of their
+// items and structs by their struct hash.
+func (typedData *TypedData) EncodeData(primaryType string, data TypedDataMessage) (hexutil.Bytes, error) {
+	fields, ok := typedData.Types[primaryType]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %q", primaryType)
+	}
+	if len(data) > len(fields) {
+		return nil, fmt.Errorf("%s has %d fields, but %d values are given", primaryType, len(fields), len(data))
+	}
+	buffer := bytes.NewBuffer(typedData.TypeHash(primaryType))
+	for _, field := range fields {
+		value, ok := data[field.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing value of field %s.%s", primaryType, field.Name)
+		}
+		encoded, err := typedData.encodeValue(field.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s.%s: %v", primaryType, field.Name, err)
+		}
+		buffer.Write(encoded)
+	}
+	return buffer.Bytes(), nil
+}
+
+// encodeValue encodes a value of a field of a struct.
+func (typedData *TypedData) encodeValue(typ string, value interface{}) ([]byte, error) {
+	if elemType, size, ok := parseArray(typ); ok {
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid value for %s: %v", typ, value)
+		}
+		if size >= 0 && len(items) != size {
+			return nil, fmt.Errorf("invalid length of %s: %d", typ, len(items))
+		}
+		var buffer bytes.Buffer
+		for i, item := range items {
+			encoded, err := typedData.encodeValue(elemType, item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %v", i, err)
+			}
+			buffer.Write(encoded)
+		}
+		return crypto.Keccak256(buffer.Bytes()), nil
+	}
+	if _, ok := typedData.Types[typ]; ok {
+		data, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid value for %s: %v", typ, value)
+		}
+		return typedData.HashStruct(typ, data)
+	}
+	return encodePrimitive(typ, value)
+}
+
+// encodePrimitive encodes an atomic or dynamic value.
+func encodePrimitive(typ string, value interface{}) ([]byte, error) {
+	switch typ {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid value for string: %v", value)
+		}
+		return crypto.Keccak256([]byte(s)), nil
+
+	case "bytes":
+		b, err := parseBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.Keccak256(b), nil
+
+	case "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid value for bool: %v", value)
+		}
+		if b {
+			return cmath.PaddedBigBytes(common.Big1, 32), nil
+		}
+		return make([]byte, 32), nil
+
+	case "address":
+		addr, err := parseAddress(value)
+		if err != nil {
+			return nil, err
+		}
+		return common.LeftPadBytes(addr.Bytes(), 32), nil
+	}
+	kind, size, err := parseSized(typ)
+	if err != nil {
+		return nil, err
+	}
+	if kind == "bytes" {
+		b, err := parseBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) > size {
+			return nil, fmt.Errorf("invalid length of %s: %d", typ, len(b))
+		}
+		return common.RightPadBytes(b, 32), nil
+	}
+	n, err := parseInteger(kind == "int", size, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %v", typ, err)
+	}
+	return cmath.PaddedBigBytes(cmath.U256(new(big.Int).Set(n)), 32), nil
+}
+
+// parseInteger converts a JSON decoded value to an integer of the given
+// signedness and size in bits.
+func parseInteger(signed bool, size int, value interface{}) (*big.Int, error) {
+	var n *big.Int
+	switch v := value.(type) {
+	case *big.Int:
+		n = v
+	case string:
+		var ok bool
+		if n, ok = cmath.ParseBig256(v); !ok {
+			return nil, fmt.Errorf("invalid integer %q", v)
+		}
+	case json.Number:
+		var ok bool
+		if n, ok = cmath.ParseBig256(v.String()); !ok {
+			return nil, fmt.Errorf("invalid integer %q", v)
+		}
+	case float64:
+		// Numbers beyond 2^53 lose precision in JSON, they must be strings
+		if v != math.Trunc(v) || math.Abs(v) > 1<<53 {
+			return nil, fmt.Errorf("imprecise integer %v, use a string", v)
+		}
+		n = big.NewInt(int64(v))
+	default:
+		return nil, fmt.Errorf("invalid integer %v", value)
+	}
+	if signed {
+		limit := new(big.Int).Lsh(common.Big1, uint(size-1))
+		if n.Cmp(new(big.Int).Neg(limit)) < 0 || n.Cmp(limit) >= 0 {
+			return nil, fmt.Errorf("%v out of range", n)
+		}
+	} else if n.Sign() < 0 || n.BitLen() > size {
+		return nil, fmt.Errorf("%v out of range", n)
+	}
+	return n, nil
+}
+
+// parseBytes converts a hex string to bytes.
+func parseBytes(value interface{}) ([]byte, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid value for bytes: %v", value)
+	}
+	b, err := hexutil.Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bytes %q: %v", s, err)
+	}
+	return b, nil
+}
+
+// parseAddress converts a hex string to an address.
+func parseAddress(value interface{}) (common.Address, error) {
+	s, ok := value.(string)
+	if !ok || !common.IsHexAddress(s) {
+		return common.Address{}, fmt.Errorf("invalid address %v", value)
+	}
+	return common.HexToAddress(s), nil
+}
+
+// Validate checks that all types are well formed and that the domain and the
+// primary type are defined.
+func (typedData *TypedData) Validate() error {
+	if err := typedData.Types.validate(); err != nil {
+		return err
+	}
+	domainType, ok := typedData.Types[DomainType]
+	if !ok {
+		return errors.New("missing type " + DomainType)
+	}
+	domain := typedData.Domain.Map()
+	if len(domain) != len(domainType) {
+		return fmt.Errorf("domain has %d fields, but %s defines %d", len(domain), DomainType, len(domainType))
+	}
+	for _, field := range domainType {
+		if typ, ok := domainFields[field.Name]; !ok || typ != field.Type {
+			return fmt.Errorf("invalid domain field %s %s", field.Type, field.Name)
+		}
+		if _, ok := domain[field.Name]; !ok {
+			return fmt.Errorf("missing value of domain field %s", field.Name)
+		}
+	}
+	if _, ok := typedData.Types[typedData.PrimaryType]; !ok {
+		return fmt.Errorf("unknown primary type %q", typedData.PrimaryType)
+	}
+	return nil
+}
+
+// validate checks that the names of types and fields are valid and unique and
+// that all field types are defined.
+func (t Types) validate() error {
+	for name, fields := range t {
+		if !typeNameRegexp.MatchString(name) || isPrimitive(name) {
+			return fmt.Errorf("invalid type name %q", name)
+		}
+		seen := make(map[string]bool)
+		for _, field := range fields {
+			if field.Name == "" {
+				return fmt.Errorf("unnamed field in type %s", name)
+			}
+			if seen[field.Name] {
+				return fmt.Errorf("duplicate field %s.%s", name, field.Name)
+			}
+			seen[field.Name] = true
+
+			typ := field.Type
+			for {
+				elemType, _, ok := parseArray(typ)
+				if !ok {
+					break
+				}
+				typ = elemType
+			}
+			if _, ok := t[typ]; !ok && !isPrimitive(typ) {
+				return fmt.Errorf("unknown type %q of field %s.%s", field.Type, name, field.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// isPrimitive returns whether the type is an atomic or dynamic type.
+func isPrimitive(typ string) bool {
+	switch typ {
+	case "string", "bytes", "bool", "address":
+		return true
+	}
+	_, _, err := parseSized(typ)
+	return err == nil
+}
+
+// parseSized splits the types uintN, intN and bytesN into kind and size,
+// checking the size is valid.
+func parseSized(typ string) (string, int, error) {
+	match := sizedRegexp.FindStringSubmatch(typ)
+	if match == nil {
+		return "", 0, fmt.Errorf("unknown type %q", typ)
+	}
+	size, _ := strconv.Atoi(match[2])
+	if match[1] == "bytes" {
+		if size < 1 || size > 32 {
+			return "", 0, fmt.Errorf("invalid size of %s", typ)
+		}
+	} else if size < 8 || size > 256 || size%8 != 0 {
+		return "", 0, fmt.Errorf("invalid size of %s", typ)
+	}
+	return match[1], size, nil
+}
+
+// parseArray splits an array type T[] or T[n] into its element type and size,
+// which is -1 for dynamic arrays.
+func parseArray(typ string) (string, int, bool) {
+	if !strings.HasSuffix(typ, "]") {
+		return "", 0, false
+	}
+	i := strings.LastIndex(typ, "[")
+	if i <= 0 {
+		return "", 0, false
+	}
+	if typ[i+1:len(typ)-1] == "" {
+		return typ[:i], -1, true
+	}
+	size, err := strconv.Atoi(typ[i+1 : len(typ)-1])
+	if err != nil || size < 0 {
+		return "", 0, false
+	}
+	return typ[:i], size, true
+}
+
+// baseType strips all array dimensions from a type.
+func baseType(typ string) string {
+	for {
+		elemType, _, ok := parseArray(typ)
+		if !ok {
+			return typ
+		}
+		typ = elemType
+	}
+}
+
+func includes(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,289 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eip712
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/common/hexutil"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+)
+
+// mailJSON is the example of the EIP-712 specification.
+const mailJSON = `{
+  "types": {
+    "EIP712Domain": [
+      {"name": "name", "type": "string"},
+      {"name": "version", "type": "string"},
+      {"name": "chainId", "type": "uint256"},
+      {"name": "verifyingContract", "type": "address"}
+    ],
+    "Person": [
+      {"name": "name", "type": "string"},
+      {"name": "wallet", "type": "address"}
+    ],
+    "Mail": [
+      {"name": "from", "type": "Person"},
+      {"name": "to", "type": "Person"},
+      {"name": "contents", "type": "string"}
+    ]
+  },
+  "primaryType": "Mail",
+  "domain": {
+    "name": "Ether Mail",
+    "version": "1",
+    "chainId": 1,
+    "verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+  },
+  "message": {
+    "from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+    "to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+    "contents": "Hello, Bob!"
+  }
+}`
+
+func mailData(t *testing.T) *TypedData {
+	var typedData TypedData
+	if err := json.Unmarshal([]byte(mailJSON), &typedData); err != nil {
+		t.Fatalf("failed to unmarshal typed data: %v", err)
+	}
+	return &typedData
+}
+
+func TestMailHashes(t *testing.T) {
+	typedData := mailData(t)
+
+	if have, want := string(typedData.EncodeType("Mail")), "Mail(Person from,Person to,string contents)Person(string name,address wallet)"; have != want {
+		t.Errorf("encodeType mismatch: have %q, want %q", have, want)
+	}
+	if have, want := typedData.TypeHash("Mail").String(), "0xa0cedeb2dc280ba39b857546d74f5549c3a1d7bdc2dd96bf881f76108e23dac2"; have != want {
+		t.Errorf("typeHash mismatch: have %s, want %s", have, want)
+	}
+	domainSeparator, err := typedData.HashStruct(DomainType, typedData.Domain.Map())
+	if err != nil {
+		t.Fatalf("failed to hash domain: %v", err)
+	}
+	if have, want := domainSeparator.String(), "0xf2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f"; have != want {
+		t.Errorf("domain separator mismatch: have %s, want %s", have, want)
+	}
+	messageHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+	if err != nil {
+		t.Fatalf("failed to hash message: %v", err)
+	}
+	if have, want := messageHash.String(), "0xc52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e"; have != want {
+		t.Errorf("message hash mismatch: have %s, want %s", have, want)
+	}
+	hash, rawData, err := typedData.SigHash()
+	if err != nil {
+		t.Fatalf("failed to hash typed data: %v", err)
+	}
+	if have, want := hexutil.Encode(hash), "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"; have != want {
+		t.Errorf("sighash mismatch: have %s, want %s", have, want)
+	}
+	if !bytes.Equal(rawData[:2], []byte{0x19, 0x01}) || len(rawData) != 66 {
+		t.Errorf("raw data malformed: %x", rawData)
+	}
+}
+
+func TestMailSignature(t *testing.T) {
+	typedData := mailData(t)
+	hash, _, err := typedData.SigHash()
+	if err != nil {
+		t.Fatalf("failed to hash typed data: %v", err)
+	}
+	key, _ := crypto.ToECDSA(crypto.Keccak256([]byte("cow")))
+	if have, want := crypto.PubkeyToAddress(key.PublicKey), common.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"); have != want {
+		t.Fatalf("signer mismatch: have %x, want %x", have, want)
+	}
+	sig, err := crypto.Sign(hash, key)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	if have, want := hexutil.Encode(sig[:32]), "0x4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d"; have != want {
+		t.Errorf("r mismatch: have %s, want %s", have, want)
+	}
+	if have, want := hexutil.Encode(sig[32:64]), "0x07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b91562"; have != want {
+		t.Errorf("s mismatch: have %s, want %s", have, want)
+	}
+	if have, want := sig[64]+27, byte(28); have != want {
+		t.Errorf("v mismatch: have %d, want %d", have, want)
+	}
+}
+
+func TestEncodeValues(t *testing.T) {
+	typedData := &TypedData{
+		Types: Types{
+			"Point": {{Name: "x", Type: "int8"}, {Name: "y", Type: "int8"}},
+			"Shape": {
+				{Name: "points", Type: "Point[]"},
+				{Name: "tags", Type: "bytes4[2]"},
+				{Name: "filled", Type: "bool"},
+				{Name: "data", Type: "bytes"},
+				{Name: "area", Type: "uint64"},
+			},
+		},
+	}
+	shape := TypedDataMessage{
+		"points": []interface{}{
+			map[string]interface{}{"x": float64(-1), "y": "2"},
+			map[string]interface{}{"x": "0x03", "y": json.Number("4")},
+		},
+		"tags":   []interface{}{"0x01020304", "0x05"},
+		"filled": true,
+		"data":   "0xdeadbeef",
+		"area":   "18446744073709551615",
+	}
+	if have, want := string(typedData.EncodeType("Shape")), "Shape(Point[] points,bytes4[2] tags,bool filled,bytes data,uint64 area)Point(int8 x,int8 y)"; have != want {
+		t.Errorf("encodeType mismatch: have %q, want %q", have, want)
+	}
+	encoded, err := typedData.EncodeData("Shape", shape)
+	if err != nil {
+		t.Fatalf("failed to encode data: %v", err)
+	}
+	// Assemble the expected encoding field by field
+	point := func(x, y string) []byte {
+		enc := append([]byte{}, typedData.TypeHash("Point")...)
+		enc = append(enc, common.FromHex(x)...)
+		return crypto.Keccak256(append(enc, common.FromHex(y)...))
+	}
+	var points []byte
+	points = append(points, point("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", "0x0000000000000000000000000000000000000000000000000000000000000002")...)
+	points = append(points, point("0x0000000000000000000000000000000000000000000000000000000000000003", "0x0000000000000000000000000000000000000000000000000000000000000004")...)
+
+	want := append([]byte{}, typedData.TypeHash("Shape")...)
+	want = append(want, crypto.Keccak256(points)...)
+	want = append(want, crypto.Keccak256(common.FromHex("0x01020304000000000000000000000000000000000000000000000000000000000500000000000000000000000000000000000000000000000000000000000000"))...)
+	want = append(want, common.FromHex("0x0000000000000000000000000000000000000000000000000000000000000001")...)
+	want = append(want, crypto.Keccak256(common.FromHex("0xdeadbeef"))...)
+	want = append(want, common.FromHex("0x000000000000000000000000000000000000000000000000ffffffffffffffff")...)
+	if !bytes.Equal(encoded, want) {
+		t.Errorf("encoding mismatch:\nhave %x\nwant %x", []byte(encoded), want)
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	typedData := &TypedData{
+		Types: Types{
+			"Values": {
+				{Name: "small", Type: "uint8"},
+				{Name: "signed", Type: "int8"},
+				{Name: "short", Type: "bytes2"},
+				{Name: "fixed", Type: "address[2]"},
+			},
+		},
+	}
+	valid := func() TypedDataMessage {
+		return TypedDataMessage{
+			"small":  float64(255),
+			"signed": float64(-128),
+			"short":  "0x0102",
+			"fixed":  []interface{}{"0x0000000000000000000000000000000000000001", "0x0000000000000000000000000000000000000002"},
+		}
+	}
+	if _, err := typedData.EncodeData("Values", valid()); err != nil {
+		t.Fatalf("failed to encode valid data: %v", err)
+	}
+	tests := []struct {
+		field string
+		value interface{}
+		err   string
+	}{
+		{"small", float64(256), "out of range"},
+		{"small", float64(-1), "out of range"},
+		{"small", 1.5, "imprecise integer"},
+		{"signed", "128", "out of range"},
+		{"signed", true, "invalid integer"},
+		{"short", "0x010203", "invalid length"},
+		{"short", "0102", "invalid bytes"},
+		{"fixed", []interface{}{"0x0000000000000000000000000000000000000001"}, "invalid length"},
+		{"fixed", []interface{}{"0x01", "0x02"}, "invalid address"},
+		{"missing", nil, "missing value"},
+		{"extra", "", "4 fields, but 5 values"},
+	}
+	for i, tt := range tests {
+		data := valid()
+		switch tt.field {
+		case "missing":
+			delete(data, "small")
+		case "extra":
+			data["extra"] = tt.value
+		default:
+			data[tt.field] = tt.value
+		}
+		_, err := typedData.EncodeData("Values", data)
+		if err == nil {
+			t.Errorf("test %d: expected error containing %q, got none", i, tt.err)
+		} else if !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("test %d: error mismatch: have %q, want %q", i, err, tt.err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		mutate func(*TypedData)
+		err    string
+	}{
+		{func(td *TypedData) { delete(td.Types, DomainType) }, "missing type EIP712Domain"},
+		{func(td *TypedData) { td.PrimaryType = "Letter" }, "unknown primary type"},
+		{func(td *TypedData) { td.Types["Mail"][2].Type = "text" }, "unknown type \"text\""},
+		{func(td *TypedData) { td.Types["Mail"][2].Type = "uint7" }, "unknown type \"uint7\""},
+		{func(td *TypedData) { td.Types["Mail"][1].Name = "from" }, "duplicate field Mail.from"},
+		{func(td *TypedData) { td.Types["uint8"] = []Type{} }, "invalid type name"},
+		{func(td *TypedData) { td.Domain.Version = "" }, "domain has 3 fields"},
+		{func(td *TypedData) { td.Types[DomainType][0].Type = "bytes32" }, "invalid domain field"},
+	}
+	for i, tt := range tests {
+		typedData := mailData(t)
+		tt.mutate(typedData)
+		_, _, err := typedData.SigHash()
+		if err == nil {
+			t.Errorf("test %d: expected error containing %q, got none", i, tt.err)
+		} else if !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("test %d: error mismatch: have %q, want %q", i, err, tt.err)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	typedData := mailData(t)
+	output, err := typedData.Pprint()
+	if err != nil {
+		t.Fatalf("failed to format typed data: %v", err)
+	}
+	want := `EIP712Domain [domain]:
+  name [string]: "Ether Mail"
+  version [string]: "1"
+  chainId [uint256]: "1"
+  verifyingContract [address]: "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+Mail [primary type]:
+  from [Person]:
+    name [string]: "Cow"
+    wallet [address]: "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"
+  to [Person]:
+    name [string]: "Bob"
+    wallet [address]: "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"
+  contents [string]: "Hello, Bob!"
+`
+	if output != want {
+		t.Errorf("output mismatch:\nhave\n%s\nwant\n%s", output, want)
+	}
+}
@@ -0,0 +1,398 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/naoina/toml"
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/common/hexutil"
+	"github.com/rwdxchain/go-rwdxchaina/common/math"
+	"github.com/rwdxchain/go-rwdxchaina/core/types"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+	"github.com/rwdxchain/go-rwdxchaina/signer/core"
+	"github.com/rwdxchain/go-rwdxchaina/signer/storage"
+)
+
+// These settings ensure that TOML keys use the same names as Go struct fields.
+var tomlSettings = toml.Config{
+	NormFieldName: func(rt reflect.Type, key string) string {
+		return key
+	},
+	FieldToKey: func(rt reflect.Type, field string) string {
+		return field
+	},
+	MissingField: func(rt reflect.Type, field string) error {
+		return fmt.Errorf("field '%s' is not defined in %s", field, rt.String())
+	},
+}
+
+// Duration is a time.Duration configured as a string, e.g. "24h".
+type Duration time.Duration
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(input []byte) error {
+	duration, err := time.ParseDuration(string(input))
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// SpendLimit caps the value an account may transfer within a rolling window.
+type SpendLimit struct {
+	Amount *math.HexOrDecimal256 // Maximum value in wei
+	Window Duration              // Length of the rolling window, "24h" for a daily limit
+}
+
+// AccountPolicy restricts the transactions of an account. Empty fields place no
+// restriction.
+type AccountPolicy struct {
+	Address      common.Address        // Account the policy applies to, ignored for the default policy
+	Limits       []SpendLimit          `toml:",omitempty"` // Limits on the value transferred
+	Destinations []common.Address      `toml:",omitempty"` // Allowed recipients of transactions
+	MaxGasPrice  *math.HexOrDecimal256 `toml:",omitempty"` // Highest gas price accepted, in wei
+	Methods      []string              `toml:",omitempty"` // Allowed methods, as selectors, signatures or names
+}
+
+// PolicyConfig is the configuration of the built-in transaction policies.
+type PolicyConfig struct {
+	Default  *AccountPolicy  `toml:",omitempty"` // Policy of the accounts without one of their own
+	Accounts []AccountPolicy `toml:",omitempty"` // Policies of specific accounts
+}
+
+// LoadPolicyConfig reads a policy configuration from a TOML file if its name
+// ends with .toml, from a JSON file otherwise.
+func LoadPolicyConfig(file string) (*PolicyConfig, error) {
+	blob, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	config := new(PolicyConfig)
+	if strings.EqualFold(filepath.Ext(file), ".toml") {
+		err = tomlSettings.NewDecoder(bytes.NewReader(blob)).Decode(config)
+		// Add file name to errors that have a line number.
+		if _, ok := err.(*toml.LineError); ok {
+			err = errors.New(file + ", " + err.Error())
+		}
+	} else {
+		err = json.Unmarshal(blob, config)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// spendRecord is a transfer made by an account, tracked to enforce its limits.
+type spendRecord struct {
+	Time  int64        `json:"time"` // Unix time of the signing, in seconds
+	Value *hexutil.Big `json:"value"`
+}
+
+// Policy enforces the built-in transaction policies, tracking the value spent by
+// the accounts in an (encrypted) storage so limits survive restarts.
+type Policy struct {
+	fallback  *AccountPolicy
+	accounts  map[common.Address]*AccountPolicy
+	retention time.Duration // Longest window of any limit, older transfers are dropped
+
+	storage  storage.Storage
+	reserved map[common.Address][]*big.Int // Value of the checked transactions not yet signed
+	db       *core.AbiDb                   // Signature database to resolve method names, may be nil
+	now      func() time.Time              // Source of the current time, overridable for testing
+	lock     sync.Mutex                    // Serializes updates of the spend records and reservations
+}
+
+// NewPolicy creates a policy enforcer from the given configuration, tracking the
+// spent value in the given storage. Methods allowed by name are resolved using
+// the 4byte database.
+func NewPolicy(config *PolicyConfig, storage storage.Storage, db *core.AbiDb) (*Policy, error) {
+	p := &Policy{
+		fallback: config.Default,
+		accounts: make(map[common.Address]*AccountPolicy),
+		storage:  storage,
+		reserved: make(map[common.Address][]*big.Int),
+		db:       db,
+		now:      time.Now,
+	}
+	if p.fallback != nil {
+		if err := p.validate(p.fallback); err != nil {
+			return nil, fmt.Errorf("invalid default policy: %v", err)
+		}
+	}
+	for i := range config.Accounts {
+		account := &config.Accounts[i]
+		if _, exists := p.accounts[account.Address]; exists {
+			return nil, fmt.Errorf("duplicate policy for account %s", account.Address.Hex())
+		}
+		if err := p.validate(account); err != nil {
+			return nil, fmt.Errorf("invalid policy for account %s: %v", account.Address.Hex(), err)
+		}
+		p.accounts[account.Address] = account
+	}
+	return p, nil
+}
+
+// validate checks an account policy, extending the retention of the spend
+// records to cover its limits.
+func (p *Policy) validate(policy *AccountPolicy) error {
+	for _, limit := range policy.Limits {
+		if limit.Amount == nil {
+			return errors.New("spend limit without amount")
+		}
+		if limit.Window <= 0 {
+			return errors.New("spend limit without window")
+		}
+		if window := time.Duration(limit.Window); window > p.retention {
+			p.retention = window
+		}
+	}
+	for _, method := range policy.Methods {
+		if strings.HasPrefix(method, "0x") {
+			if selector, err := hexutil.Decode(method); err != nil || len(selector) != 4 {
+				return fmt.Errorf("invalid method selector %q", method)
+			}
+		} else if method == "" {
+			return errors.New("empty method")
+		}
+	}
+	return nil
+}
+
+// policy returns the policy of an account, nil if it is unrestricted.
+func (p *Policy) policy(account common.Address) *AccountPolicy {
+	if policy, ok := p.accounts[account]; ok {
+		return policy
+	}
+	return p.fallback
+}
+
+// Check verifies that a transaction complies with the policy of its sender. The
+// value of a compliant transaction is reserved against the spend limits until it
+// is either recorded once signed, or released if it doesn't get signed, so that
+// concurrent transactions cannot overdraw the limits together.
+func (p *Policy) Check(args *core.SendTxArgs) error {
+	policy := p.policy(args.From.Address())
+	if policy == nil {
+		return nil
+	}
+	if len(policy.Destinations) > 0 {
+		if args.To == nil {
+			return errors.New("contract creation not allowed")
+		}
+		allowed := false
+		for _, destination := range policy.Destinations {
+			if destination == args.To.Address() {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("destination %s not allowed", args.To.Address().Hex())
+		}
+	}
+	if policy.MaxGasPrice != nil && args.GasPrice.ToInt().Cmp((*big.Int)(policy.MaxGasPrice)) > 0 {
+		return fmt.Errorf("gas price %v above ceiling of %v", args.GasPrice.ToInt(), (*big.Int)(policy.MaxGasPrice))
+	}
+	var data []byte
+	if args.Data != nil {
+		data = *args.Data
+	} else if args.Input != nil {
+		data = *args.Input
+	}
+	if err := p.checkMethod(policy.Methods, data); err != nil {
+		return err
+	}
+	return p.checkLimits(args.From.Address(), policy.Limits, args.Value.ToInt())
+}
+
+// checkMethod verifies that the call data invokes one of the allowed methods.
+// Plain transfers without data are always allowed.
+func (p *Policy) checkMethod(methods []string, data []byte) error {
+	if len(methods) == 0 || len(data) == 0 {
+		return nil
+	}
+	if len(data) < 4 {
+		return fmt.Errorf("invalid call data %x", data)
+	}
+	selector := data[:4]
+
+	var signature string
+	if p.db != nil {
+		signature, _ = p.db.LookupMethodSelector(selector)
+	}
+	for _, method := range methods {
+		switch {
+		case strings.HasPrefix(method, "0x"):
+			if method == hexutil.Encode(selector) {
+				return nil
+			}
+		case strings.Contains(method, "("):
+			if bytes.Equal(crypto.Keccak256([]byte(method))[:4], selector) {
+				return nil
+			}
+		default:
+			if signature != "" && strings.SplitN(signature, "(", 2)[0] == method {
+				return nil
+			}
+		}
+	}
+	if signature != "" {
+		return fmt.Errorf("method %s (%s) not allowed", hexutil.Encode(selector), signature)
+	}
+	return fmt.Errorf("method %s not allowed", hexutil.Encode(selector))
+}
+
+// checkLimits verifies that transferring value keeps the account within its
+// spend limits, counting the transactions still pending signing as spent, and
+// reserves the value if so.
+func (p *Policy) checkLimits(account common.Address, limits []SpendLimit, value *big.Int) error {
+	if len(limits) == 0 || value.Sign() == 0 {
+		return nil
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	records := p.records(account)
+	pending := new(big.Int)
+	for _, reserved := range p.reserved[account] {
+		pending.Add(pending, reserved)
+	}
+	now := p.now()
+	for _, limit := range limits {
+		since := now.Add(-time.Duration(limit.Window)).Unix()
+
+		spent := new(big.Int).Add(value, pending)
+		for _, record := range records {
+			if record.Time > since {
+				spent.Add(spent, record.Value.ToInt())
+			}
+		}
+		if spent.Cmp((*big.Int)(limit.Amount)) > 0 {
+			return fmt.Errorf("spend limit of %v wei per %v exceeded", (*big.Int)(limit.Amount), time.Duration(limit.Window))
+		}
+	}
+	p.reserved[account] = append(p.reserved[account], new(big.Int).Set(value))
+	return nil
+}
+
+// Release returns the value reserved by a successful Check of a transaction that
+// will not be signed, e.g. because it got rejected afterwards.
+func (p *Policy) Release(args *core.SendTxArgs) {
+	account := args.From.Address()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.release(account, args.Value.ToInt())
+}
+
+// release drops a reservation of the given value, if there is one. Reservations
+// of the same value are interchangeable, any of them may be dropped.
+//
+// Note, release assumes the lock of the policy is held!
+func (p *Policy) release(account common.Address, value *big.Int) {
+	reserved := p.reserved[account]
+	for i, v := range reserved {
+		if v.Cmp(value) == 0 {
+			reserved = append(reserved[:i], reserved[i+1:]...)
+			break
+		}
+	}
+	if len(reserved) == 0 {
+		delete(p.reserved, account)
+	} else {
+		p.reserved[account] = reserved
+	}
+}
+
+// Record tracks the value transferred by a signed transaction against the limits
+// of its sender, replacing the reservation made when it was checked.
+func (p *Policy) Record(tx *types.Transaction) error {
+	var signer types.Signer = types.HomesteadSigner{}
+	if tx.Protected() {
+		signer = types.NewEIP155Signer(tx.ChainId())
+	}
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return err
+	}
+	policy := p.policy(from)
+	if policy == nil || len(policy.Limits) == 0 || tx.Value().Sign() == 0 {
+		return nil
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.release(from, tx.Value())
+
+	// Drop the transfers no limit looks at anymore and add the new one
+	now := p.now()
+	since := now.Add(-p.retention).Unix()
+
+	var records []spendRecord
+	for _, record := range p.records(from) {
+		if record.Time > since {
+			records = append(records, record)
+		}
+	}
+	records = append(records, spendRecord{Time: now.Unix(), Value: (*hexutil.Big)(tx.Value())})
+
+	blob, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	p.storage.Put(spendKey(from), string(blob))
+	return nil
+}
+
+// records returns the tracked transfers of an account.
+//
+// Note, records assumes the lock of the policy is held!
+func (p *Policy) records(account common.Address) []spendRecord {
+	var records []spendRecord
+	if blob := p.storage.Get(spendKey(account)); blob != "" {
+		if err := json.Unmarshal([]byte(blob), &records); err != nil {
+			// Refuse to forget transfers, count the account as having exhausted its limits
+			return []spendRecord{{Time: p.now().Unix(), Value: (*hexutil.Big)(math.MaxBig256)}}
+		}
+	}
+	return records
+}
+
+// spendKey is the storage key of the transfers of an account.
+func spendKey(account common.Address) string {
+	return "spent:" + strings.ToLower(account.Hex())
+}
@@ -0,0 +1,342 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rules
+
+import (
+	"crypto/ecdsa"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rwdxchain/go-rwdxchaina/common"
+	"github.com/rwdxchain/go-rwdxchaina/common/hexutil"
+	"github.com/rwdxchain/go-rwdxchaina/core/types"
+	"github.com/rwdxchain/go-rwdxchaina/crypto"
+	"github.com/rwdxchain/go-rwdxchaina/internal/ethapi"
+	"github.com/rwdxchain/go-rwdxchaina/signer/core"
+	"github.com/rwdxchain/go-rwdxchaina/signer/storage"
+)
+
+const testPolicyTOML = `
+[Default]
+MaxGasPrice = "20000000000"
+
+[[Accounts]]
+Address = "0x0000000000000000000000000000000000001337"
+Destinations = ["0x000000000000000000000000000000000000dead"]
+Methods = ["transfer", "approve(address,uint256)", "0x095ea7b4"]
+
+[[Accounts.Limits]]
+Amount = "1000000000000000000"
+Window = "24h"
+
+[[Accounts.Limits]]
+Amount = "0x6f05b59d3b20000"
+Window = "1h"
+`
+
+const testPolicyJSON = `{
+	"Default": {"MaxGasPrice": "20000000000"},
+	"Accounts": [{
+		"Address": "0x0000000000000000000000000000000000001337",
+		"Destinations": ["0x000000000000000000000000000000000000dead"],
+		"Methods": ["transfer", "approve(address,uint256)", "0x095ea7b4"],
+		"Limits": [
+			{"Amount": "1000000000000000000", "Window": "24h"},
+			{"Amount": "0x6f05b59d3b20000", "Window": "1h"}
+		]
+	}]
+}`
+
+// Tests that policies are loaded from both TOML and JSON files.
+func TestLoadPolicyConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rwdxchain-policy-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for name, content := range map[string]string{"policy.toml": testPolicyTOML, "policy.json": testPolicyJSON} {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		config, err := LoadPolicyConfig(path)
+		if err != nil {
+			t.Fatalf("%s: failed to load policy: %v", name, err)
+		}
+		if config.Default == nil || (*big.Int)(config.Default.MaxGasPrice).Cmp(big.NewInt(20000000000)) != 0 {
+			t.Errorf("%s: default policy mismatch: %+v", name, config.Default)
+		}
+		if len(config.Accounts) != 1 {
+			t.Fatalf("%s: account policy count mismatch: have %d, want 1", name, len(config.Accounts))
+		}
+		account := config.Accounts[0]
+		if account.Address != common.HexToAddress("0x1337") || len(account.Destinations) != 1 || len(account.Methods) != 3 {
+			t.Errorf("%s: account policy mismatch: %+v", name, account)
+		}
+		if len(account.Limits) != 2 || time.Duration(account.Limits[0].Window) != 24*time.Hour ||
+			(*big.Int)(account.Limits[1].Amount).Cmp(big.NewInt(500000000000000000)) != 0 {
+			t.Errorf("%s: spend limits mismatch: %+v", name, account.Limits)
+		}
+	}
+	// Unknown fields are rejected rather than silently ignored
+	path := filepath.Join(dir, "invalid.toml")
+	ioutil.WriteFile(path, []byte("[Default]\nMaxGasprice = 1\n"), 0600)
+	if _, err := LoadPolicyConfig(path); err == nil {
+		t.Errorf("unknown field accepted")
+	}
+}
+
+// testPolicy creates a policy enforcer from the test configuration.
+func testPolicy(t *testing.T, backend storage.Storage) *Policy {
+	dir, err := ioutil.TempDir("", "rwdxchain-policy-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "policy.toml")
+	if err := ioutil.WriteFile(path, []byte(testPolicyTOML), 0600); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadPolicyConfig(path)
+	if err != nil {
+		t.Fatalf("failed to load policy: %v", err)
+	}
+	db, _ := core.NewEmptyAbiDB()
+	db.AddSignature("transfer(address,uint256)", crypto.Keccak256([]byte("transfer(address,uint256)"))[:4])
+
+	policy, err := NewPolicy(config, backend, db)
+	if err != nil {
+		t.Fatalf("failed to create policy: %v", err)
+	}
+	return policy
+}
+
+// policyTx creates a transaction request from the given account.
+func policyTx(from string, to string, value int64, gasPrice int64, data []byte) *core.SendTxArgs {
+	args := &core.SendTxArgs{
+		From:     common.NewMixedcaseAddress(common.HexToAddress(from)),
+		Value:    hexutil.Big(*big.NewInt(value)),
+		GasPrice: hexutil.Big(*big.NewInt(gasPrice)),
+	}
+	if to != "" {
+		dest := common.NewMixedcaseAddress(common.HexToAddress(to))
+		args.To = &dest
+	}
+	if data != nil {
+		input := hexutil.Bytes(data)
+		args.Data = &input
+	}
+	return args
+}
+
+// Tests the destination, gas price and method restrictions of policies.
+func TestPolicyRestrictions(t *testing.T) {
+	policy := testPolicy(t, storage.NewEphemeralStorage())
+
+	selector := func(signature string) []byte {
+		return crypto.Keccak256([]byte(signature))[:4]
+	}
+	tests := []struct {
+		args *core.SendTxArgs
+		ok   bool
+	}{
+		// The default policy only caps the gas price
+		{policyTx("0x01", "0x02", 1, 20000000000, []byte{0x01}), true},
+		{policyTx("0x01", "", 1, 20000000001, nil), false},
+
+		// Account policies replace the default one
+		{policyTx("0x1337", "0xdead", 1, 50000000000, nil), true},
+		{policyTx("0x1337", "0xbeef", 1, 1, nil), false},
+		{policyTx("0x1337", "", 1, 1, []byte{0x60, 0x60}), false},
+
+		// Methods are allowed by name, signature or selector
+		{policyTx("0x1337", "0xdead", 0, 1, selector("transfer(address,uint256)")), true},
+		{policyTx("0x1337", "0xdead", 0, 1, selector("approve(address,uint256)")), true},
+		{policyTx("0x1337", "0xdead", 0, 1, []byte{0x09, 0x5e, 0xa7, 0xb4, 0x00}), true},
+		{policyTx("0x1337", "0xdead", 0, 1, selector("transferFrom(address,address,uint256)")), false},
+		{policyTx("0x1337", "0xdead", 0, 1, []byte{0xa9, 0x05}), false},
+	}
+	for i, tt := range tests {
+		if err := policy.Check(tt.args); (err == nil) != tt.ok {
+			t.Errorf("test %d: policy check mismatch: have %v, want ok %v", i, err, tt.ok)
+		}
+	}
+}
+
+// signedPolicyTx signs a value transfer to the allowed destination.
+func signedPolicyTx(t *testing.T, key *ecdsa.PrivateKey, value int64) *types.Transaction {
+	tx := types.NewTransaction(0, common.HexToAddress("0xdead"), big.NewInt(value), 21000, big.NewInt(1), nil)
+	signed, err := types.SignTx(tx, types.NewEIP155Signer(big.NewInt(1)), key)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	return signed
+}
+
+// Tests that spend limits are enforced over rolling windows and survive restarts.
+func TestPolicySpendLimits(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	backend := storage.NewEphemeralStorage()
+	policy := testPolicy(t, backend)
+	policy.accounts[from] = policy.accounts[common.HexToAddress("0x1337")]
+
+	now := time.Unix(1500000000, 0)
+	policy.now = func() time.Time { return now }
+
+	ether := int64(1000000000000000000)
+	check := func(value int64) error {
+		return policy.Check(policyTx(from.Hex(), "0xdead", value, 1, nil))
+	}
+	// Half an ether per hour may be spent
+	if err := check(ether / 2); err != nil {
+		t.Fatalf("transfer within limits refused: %v", err)
+	}
+	if err := policy.Record(signedPolicyTx(t, key, ether/2)); err != nil {
+		t.Fatalf("failed to record transfer: %v", err)
+	}
+	if err := check(1); err == nil {
+		t.Fatalf("transfer above hourly limit allowed")
+	}
+	// After an hour, the daily limit of one ether still applies
+	now = now.Add(time.Hour)
+	if err := check(ether/2 + 1); err == nil {
+		t.Fatalf("transfer above daily limit allowed")
+	}
+	if err := check(ether / 2); err != nil {
+		t.Fatalf("transfer within limits refused: %v", err)
+	}
+	policy.Record(signedPolicyTx(t, key, ether/2))
+
+	// The records are persisted, a restarted policy enforces the same limits
+	restarted := testPolicy(t, backend)
+	restarted.accounts[from] = restarted.accounts[common.HexToAddress("0x1337")]
+	restarted.now = policy.now
+
+	if err := restarted.Check(policyTx(from.Hex(), "0xdead", 1, 1, nil)); err == nil {
+		t.Fatalf("transfer above daily limit allowed after restart")
+	}
+	// Once the first transfer leaves the window, its value may be spent again
+	now = now.Add(23 * time.Hour)
+	if err := restarted.Check(policyTx(from.Hex(), "0xdead", ether/2, 1, nil)); err != nil {
+		t.Fatalf("transfer within rolling window refused: %v", err)
+	}
+}
+
+// Tests that concurrent transactions cannot overdraw the spend limits together,
+// and that the value reserved by rejected transactions becomes available again.
+func TestPolicyConcurrentSpends(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	policy := testPolicy(t, storage.NewEphemeralStorage())
+	policy.accounts[from] = policy.accounts[common.HexToAddress("0x1337")]
+
+	// Two transfers of 0.3 ether each are within the hourly limit of half an
+	// ether on their own, but not together
+	value := int64(300000000000000000)
+
+	var (
+		start = make(chan struct{})
+		errs  = make(chan error, 2)
+		pend  sync.WaitGroup
+	)
+	for i := 0; i < 2; i++ {
+		pend.Add(1)
+		go func() {
+			defer pend.Done()
+			<-start
+			errs <- policy.Check(policyTx(from.Hex(), "0xdead", value, 1, nil))
+		}()
+	}
+	close(start)
+	pend.Wait()
+	close(errs)
+
+	allowed := 0
+	for err := range errs {
+		if err == nil {
+			allowed++
+		}
+	}
+	if allowed != 1 {
+		t.Fatalf("concurrent transfers allowed: have %d, want 1", allowed)
+	}
+	// Releasing the pending transfer frees its value for another one
+	policy.Release(policyTx(from.Hex(), "0xdead", value, 1, nil))
+	if err := policy.Check(policyTx(from.Hex(), "0xdead", value, 1, nil)); err != nil {
+		t.Fatalf("transfer refused after release: %v", err)
+	}
+	// Recording the signed transfer replaces its reservation instead of adding to it
+	if err := policy.Record(signedPolicyTx(t, key, value)); err != nil {
+		t.Fatalf("failed to record transfer: %v", err)
+	}
+	if err := policy.Check(policyTx(from.Hex(), "0xdead", 200000000000000000, 1, nil)); err != nil {
+		t.Fatalf("transfer within limits refused after recording: %v", err)
+	}
+	if err := policy.Check(policyTx(from.Hex(), "0xdead", 1, 1, nil)); err == nil {
+		t.Fatalf("transfer above hourly limit allowed")
+	}
+}
+
+// Tests that the rule engine enforces the policies before running the rules, and
+// tracks the value of the signed transactions.
+func TestPolicyRuleEngine(t *testing.T) {
+	js := `function ApproveTx(r) { return "Approve"; }`
+
+	next := &dummyUI{}
+	r, err := NewRuleEvaluator(next, storage.NewEphemeralStorage(), storage.NewEphemeralStorage())
+	if err != nil {
+		t.Fatalf("failed to create rule engine: %v", err)
+	}
+	r.Init(js)
+
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	policy := testPolicy(t, storage.NewEphemeralStorage())
+	policy.accounts[from] = policy.accounts[common.HexToAddress("0x1337")]
+	r.SetPolicy(policy)
+
+	// Transactions violating the policy are rejected without asking the rules or the user
+	resp, err := r.ApproveTx(&core.SignTxRequest{Transaction: *policyTx(from.Hex(), "0xbeef", 1, 1, nil)})
+	if err != nil || resp.Approved {
+		t.Fatalf("policy violation approved: %v %v", resp.Approved, err)
+	}
+	if len(next.calls) != 1 || next.calls[0] != "ShowError" {
+		t.Fatalf("unexpected calls to next UI: %v", next.calls)
+	}
+	// Compliant transactions are handed to the rules, and counted once signed
+	resp, err = r.ApproveTx(&core.SignTxRequest{Transaction: *policyTx(from.Hex(), "0xdead", 500000000000000000, 1, nil)})
+	if err != nil || !resp.Approved {
+		t.Fatalf("compliant transaction not approved: %v %v", resp.Approved, err)
+	}
+	r.OnApprovedTx(ethapi.SignTransactionResult{Tx: signedPolicyTx(t, key, 500000000000000000)})
+
+	resp, err = r.ApproveTx(&core.SignTxRequest{Transaction: *policyTx(from.Hex(), "0xdead", 1, 1, nil)})
+	if err != nil || resp.Approved {
+		t.Fatalf("transaction above spend limit approved: %v %v", resp.Approved, err)
+	}
+}
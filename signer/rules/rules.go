@@ -52,7 +52,8 @@ type rulesetUI struct {
 	next        core.SignerUI // The next handler, for manual processing
 	storage     storage.Storage
 	credentials storage.Storage
-	jsRules     string // The rules to use
+	jsRules     string  // The rules to use
+	policy      *Policy // Built-in policies enforced before the rules, nil if none
 }
 
 func NewRuleEvaluator(next core.SignerUI, jsbackend, credentialsBackend storage.Storage) (*rulesetUI, error) {
@@ -70,6 +71,12 @@ func (r *rulesetUI) Init(javascriptRules string) error {
 	r.jsRules = javascriptRules
 	return nil
 }
+// SetPolicy makes the rule engine enforce the given built-in policies on every
+// transaction, before evaluating the rules.
+func (r *rulesetUI) SetPolicy(policy *Policy) {
+	r.policy = policy
+}
+
 func (r *rulesetUI) execute(jsfunc string, jsarg interface{}) (otto.Value, error) {
 
 	// Instantiate a fresh vm engine every time
@@ -140,11 +147,30 @@ func (r *rulesetUI) checkApproval(jsfunc string, jsarg []byte, err error) (bool,
 }
 
 func (r *rulesetUI) ApproveTx(request *core.SignTxRequest) (core.SignTxResponse, error) {
+	// Enforce the built-in policies before the rules get to see the request
+	if r.policy != nil {
+		if err := r.checkPolicy(&request.Transaction); err != nil {
+			return core.SignTxResponse{Approved: false}, nil
+		}
+	}
 	jsonreq, err := json.Marshal(request)
 	approved, err := r.checkApproval("ApproveTx", jsonreq, err)
 	if err != nil {
 		log.Info("Rule-based approval error, going to manual", "error", err)
-		return r.next.ApproveTx(request)
+		response, err := r.next.ApproveTx(request)
+
+		// The transaction may have been modified manually, swap the reservation
+		// of the requested one for a check of the one to be signed
+		if r.policy != nil {
+			r.policy.Release(&request.Transaction)
+		}
+		if err != nil || !response.Approved {
+			return response, err
+		}
+		if err := r.checkPolicy(&response.Transaction); err != nil {
+			return core.SignTxResponse{Approved: false}, nil
+		}
+		return response, nil
 	}
 
 	if approved {
@@ -155,9 +181,65 @@ func (r *rulesetUI) ApproveTx(request *core.SignTxRequest) (core.SignTxResponse,
 			},
 			nil
 	}
+	if r.policy != nil {
+		r.policy.Release(&request.Transaction)
+	}
 	return core.SignTxResponse{Approved: false}, err
 }
 
+// checkPolicy verifies a transaction against the built-in policies, notifying
+// the user of any violation.
+func (r *rulesetUI) checkPolicy(args *core.SendTxArgs) error {
+	if r.policy == nil {
+		return nil
+	}
+	if err := r.policy.Check(args); err != nil {
+		log.Info("Transaction violates policy", "error", err)
+		r.next.ShowError(fmt.Sprintf("Transaction rejected by policy: %v", err))
+		return err
+	}
+	return nil
+}
+
+// OnFailedTx implements core.FailedTxHandler, releasing the value reserved for
+// an approved transaction that could not be signed.
+func (r *rulesetUI) OnFailedTx(args core.SendTxArgs, err error) {
+	if r.policy != nil {
+		r.policy.Release(&args)
+	}
+}
+
+// RequiredApprovals implements core.ApprovalPolicy, asking the optional
+// ApprovalQuorum function of the ruleset how many approvers need to approve the
+// transaction. Rulesets not defining it never require approvals. Unlike the
+// approval callbacks, failures are not deferred to the next UI, but reject the
+// transaction, so a broken ruleset cannot bypass the quorum.
+func (r *rulesetUI) RequiredApprovals(request *core.SignTxRequest) (int, error) {
+	jsonreq, err := json.Marshal(request)
+	if err != nil {
+		return 0, err
+	}
+	v, err := r.execute(`(typeof ApprovalQuorum === "function" ? ApprovalQuorum : function() { return 0; })`, string(jsonreq))
+	if err != nil {
+		log.Info("error occurred during execution", "error", err)
+		return 0, err
+	}
+	if v.IsUndefined() || v.IsNull() {
+		return 0, nil
+	}
+	if !v.IsNumber() {
+		return 0, fmt.Errorf("invalid approval quorum: %v", v)
+	}
+	quorum, err := v.ToInteger()
+	if err != nil {
+		return 0, err
+	}
+	if quorum < 0 {
+		return 0, fmt.Errorf("invalid approval quorum: %d", quorum)
+	}
+	return int(quorum), nil
+}
+
 func (r *rulesetUI) lookupPassword(address common.Address) string {
 	return r.credentials.Get(strings.ToLower(address.String()))
 }
@@ -236,6 +318,12 @@ func (r *rulesetUI) OnSignerStartup(info core.StartupInfo) {
 }
 
 func (r *rulesetUI) OnApprovedTx(tx ethapi.SignTransactionResult) {
+	// Track the value spent against the limits of the built-in policies
+	if r.policy != nil && tx.Tx != nil {
+		if err := r.policy.Record(tx.Tx); err != nil {
+			log.Warn("Failed to track spent value", "error", err)
+		}
+	}
 	jsonTx, err := json.Marshal(tx)
 	if err != nil {
 		log.Warn("failed marshalling transaction", "tx", tx)
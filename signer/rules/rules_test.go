@@ -28,6 +28,7 @@ import (
 	"github.com/rwdxchain/go-rwdxchaina/core/types"
 	"github.com/rwdxchain/go-rwdxchaina/internal/ethapi"
 	"github.com/rwdxchain/go-rwdxchaina/signer/core"
+	"github.com/rwdxchain/go-rwdxchaina/signer/eip712"
 	"github.com/rwdxchain/go-rwdxchaina/signer/storage"
 )
 
@@ -629,3 +630,100 @@ function ApproveSignData(r){
 		t.Fatalf("Expected approved")
 	}
 }
+
+func TestSignTypedData(t *testing.T) {
+
+	js := `function ApproveSignData(r){
+    if( r.content_type != "data/typed"){
+        return "Reject"
+    }
+    var td = r.typed_data
+    if( td.domain.name == "Ether Mail" && td.primaryType == "Mail" && td.message.to.name == "Bob"){
+        return "Approve"
+    }
+    return "Reject"
+}`
+	r, err := initRuleEngine(js)
+	if err != nil {
+		t.Errorf("Couldn't create evaluator %v", err)
+		return
+	}
+	typedData := eip712.TypedData{
+		Types: eip712.Types{
+			"EIP712Domain": {{Name: "name", Type: "string"}},
+			"Person":       {{Name: "name", Type: "string"}},
+			"Mail":         {{Name: "to", Type: "Person"}, {Name: "contents", Type: "string"}},
+		},
+		PrimaryType: "Mail",
+		Domain:      eip712.TypedDataDomain{Name: "Ether Mail"},
+		Message: eip712.TypedDataMessage{
+			"to":       map[string]interface{}{"name": "Bob"},
+			"contents": "Hello, Bob!",
+		},
+	}
+	addr, _ := mixAddr("0x694267f14675d7e1b9494fd8d72fefe1755710fa")
+	for _, to := range []string{"Bob", "Mallory"} {
+		typedData.Message["to"] = map[string]interface{}{"name": to}
+		hash, raw, err := typedData.SigHash()
+		if err != nil {
+			t.Fatalf("Failed to hash typed data: %v", err)
+		}
+		resp, err := r.ApproveSignData(&core.SignDataRequest{
+			ContentType: core.DataTyped,
+			Address:     *addr,
+			Rawdata:     raw,
+			Hash:        hash,
+			TypedData:   &typedData,
+			Meta:        core.Metadata{Remote: "remoteip", Local: "localip", Scheme: "inproc"},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		if want := to == "Bob"; resp.Approved != want {
+			t.Errorf("Recipient %s: approved %v, want %v", to, resp.Approved, want)
+		}
+	}
+}
+
+// TestApprovalQuorum tests that rulesets can require transactions to be approved
+// by several approvers, and that broken quorum functions fail closed.
+func TestApprovalQuorum(t *testing.T) {
+	js := `
+	function ApprovalQuorum(r) {
+		var value = new BigNumber(r.transaction.value.slice(2), 16);
+		if (value.greaterThan(new BigNumber("1e18"))) {
+			return 2;
+		}
+		return 0;
+	}`
+	r, err := initRuleEngine(js)
+	if err != nil {
+		t.Fatalf("Couldn't create evaluator %v", err)
+	}
+	if quorum, err := r.RequiredApprovals(dummyTxWithV(1)); err != nil || quorum != 0 {
+		t.Errorf("low value quorum mismatch: have %d (%v), want 0", quorum, err)
+	}
+	if quorum, err := r.RequiredApprovals(dummyTxWithV(2000000000000000000)); err != nil || quorum != 2 {
+		t.Errorf("high value quorum mismatch: have %d (%v), want 2", quorum, err)
+	}
+	// Rulesets without a quorum function never require approvals
+	if r, err = initRuleEngine(JS); err != nil {
+		t.Fatalf("Couldn't create evaluator %v", err)
+	}
+	if quorum, err := r.RequiredApprovals(dummyTxWithV(1)); err != nil || quorum != 0 {
+		t.Errorf("missing function quorum mismatch: have %d (%v), want 0", quorum, err)
+	}
+	// Failing or invalid quorum functions must reject the transaction
+	for _, js := range []string{
+		`function ApprovalQuorum(r) { throw "broken"; }`,
+		`function ApprovalQuorum(r) { return "two"; }`,
+		`function ApprovalQuorum(r) { return -1; }`,
+	} {
+		if r, err = initRuleEngine(js); err != nil {
+			t.Fatalf("Couldn't create evaluator %v", err)
+		}
+		if _, err := r.RequiredApprovals(dummyTxWithV(1)); err == nil {
+			t.Errorf("expected error for ruleset %q", js)
+		}
+	}
+}